@@ -254,6 +254,26 @@ def go_deps():
             "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/andygrunwald/go-jira/com_github_andygrunwald_go_jira-v1.14.0.zip",
         ],
     )
+    go_repository(
+        name = "com_github_ansel1_merry",
+        build_file_proto_mode = "disable_global",
+        importpath = "github.com/ansel1/merry",
+        sha256 = "a1261f463ce90b16465d9fa8d0653b6957b0a67e81c072cc36d1cfbbce07fa2c",
+        strip_prefix = "github.com/ansel1/merry@v1.6.2",
+        urls = [
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/ansel1/merry/com_github_ansel1_merry-v1.6.2.zip",
+        ],
+    )
+    go_repository(
+        name = "com_github_ansel1_merry_v2",
+        build_file_proto_mode = "disable_global",
+        importpath = "github.com/ansel1/merry/v2",
+        sha256 = "428c5c5f639145c77bbe609a4e93bc8dc28a3ca1fd2624d52ed0d91def04989e",
+        strip_prefix = "github.com/ansel1/merry/v2@v2.0.1",
+        urls = [
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/ansel1/merry/v2/com_github_ansel1_merry_v2-v2.0.1.zip",
+        ],
+    )
     go_repository(
         name = "com_github_antihax_optional",
         build_file_proto_mode = "disable_global",
@@ -2856,6 +2876,26 @@ def go_deps():
             "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/GeertJohan/go.rice/com_github_geertjohan_go_rice-v1.0.0.zip",
         ],
     )
+    go_repository(
+        name = "com_github_gemalto_flume",
+        build_file_proto_mode = "disable_global",
+        importpath = "github.com/gemalto/flume",
+        sha256 = "11c39e337032ad1daaa4831a1f986cf756e11f137b28e880f00750f13fde5a83",
+        strip_prefix = "github.com/gemalto/flume@v0.13.0",
+        urls = [
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/gemalto/flume/com_github_gemalto_flume-v0.13.0.zip",
+        ],
+    )
+    go_repository(
+        name = "com_github_gemalto_kmip_go",
+        build_file_proto_mode = "disable_global",
+        importpath = "github.com/gemalto/kmip-go",
+        sha256 = "36d16c8e1e0f8c1a8891f22192c49cf0c31c85ab6c4125f158dd68f9c287f6cc",
+        strip_prefix = "github.com/gemalto/kmip-go@v0.0.10",
+        urls = [
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/gemalto/kmip-go/com_github_gemalto_kmip_go-v0.0.10.zip",
+        ],
+    )
     go_repository(
         name = "com_github_getkin_kin_openapi",
         build_file_proto_mode = "disable_global",
@@ -11578,10 +11618,10 @@ def go_deps():
         name = "org_uber_go_multierr",
         build_file_proto_mode = "disable_global",
         importpath = "go.uber.org/multierr",
-        sha256 = "abee21bbd1cb62b0721680430ef8e098717299d10b4382876b9aa40664e6556c",
-        strip_prefix = "go.uber.org/multierr@v1.7.0",
+        sha256 = "0077abedf9a4798c50e015045b5cc092b8001be6d994fc59781c4b0d6ae94507",
+        strip_prefix = "go.uber.org/multierr@v1.8.0",
         urls = [
-            "https://storage.googleapis.com/cockroach-godeps/gomod/go.uber.org/multierr/org_uber_go_multierr-v1.7.0.zip",
+            "https://storage.googleapis.com/cockroach-godeps/gomod/go.uber.org/multierr/org_uber_go_multierr-v1.8.0.zip",
         ],
     )
     go_repository(
@@ -11598,10 +11638,10 @@ def go_deps():
         name = "org_uber_go_zap",
         build_file_proto_mode = "disable_global",
         importpath = "go.uber.org/zap",
-        sha256 = "6437824258873fed421b7975b8e4cafd1be80cdc15e553beaa887b499dd01420",
-        strip_prefix = "go.uber.org/zap@v1.19.0",
+        sha256 = "98a8a0ad4beba9471c6016c4b0c0a077254e5f2669290ab87c28c900d62e3885",
+        strip_prefix = "go.uber.org/zap@v1.21.0",
         urls = [
-            "https://storage.googleapis.com/cockroach-godeps/gomod/go.uber.org/zap/org_uber_go_zap-v1.19.0.zip",
+            "https://storage.googleapis.com/cockroach-godeps/gomod/go.uber.org/zap/org_uber_go_zap-v1.21.0.zip",
         ],
     )
     go_repository(
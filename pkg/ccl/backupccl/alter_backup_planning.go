@@ -29,7 +29,7 @@ func alterBackupTypeCheck(
 	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
 ) (ok bool, _ colinfo.ResultColumns, _ error) {
 	alterBackupStmt, ok := stmt.(*tree.AlterBackup)
-	if !ok {
+	if !ok || getAlterBackupCompact(alterBackupStmt) != nil {
 		return false, nil, nil
 	}
 	if err := exprutil.TypeCheck(
@@ -48,7 +48,7 @@ func alterBackupPlanHook(
 	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
 ) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
 	alterBackupStmt, ok := stmt.(*tree.AlterBackup)
-	if !ok {
+	if !ok || getAlterBackupCompact(alterBackupStmt) != nil {
 		return nil, nil, nil, false, nil
 	}
 
@@ -317,6 +317,15 @@ func processScheduleOptions(
 				continue
 			}
 			s.incArgs.UpdatesLastBackupMetric = updatesLastBackupMetric
+		case optCompactionThreshold:
+			if s.incArgs == nil {
+				return errors.Newf("%s requires an incremental backup schedule", optCompactionThreshold)
+			}
+			threshold, err := parseCompactionThreshold(map[string]string{k: v})
+			if err != nil {
+				return err
+			}
+			s.incArgs.CompactionThreshold = threshold
 		default:
 			return errors.Newf("unexpected schedule option: %s = %s", k, v)
 		}
@@ -471,6 +480,7 @@ func processFullBackupRecurrence(
 			s.fullArgs.UpdatesLastBackupMetric,
 			s.incStmt,
 			s.fullArgs.ChainProtectedTimestampRecords,
+			0, /* compactionThreshold */
 		)
 
 		if err != nil {
@@ -0,0 +1,697 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+type backupCompactionResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &backupCompactionResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+//
+// The job merges the data of the compacted layers by key and writes it to the
+// files of a new layer, whose manifest covers the interval from the start time
+// of the first compacted layer to the end time of the last. The layers that a
+// compacted layer covers are ignored when the backup is read, so the compacted
+// layers are left in place unless their deletion was requested. Even then, they
+// are only deleted once no RESTORE or VERIFY BACKUP job, which may have been
+// planned before the compacted layer was written and so still read them, is
+// running; until then the job retries. The backup remains valid if the job
+// fails while deleting them.
+func (r *backupCompactionResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.BackupCompactionDetails)
+
+	var prog jobspb.BackupCompactionProgress
+	if pr := r.job.Progress().GetBackupCompaction(); pr != nil {
+		prog = *pr
+	}
+	if !prog.CompactedManifestWritten {
+		kmsEnv := backupencryption.MakeBackupKMSEnv(
+			execCfg.Settings,
+			&execCfg.ExternalIODirConfig,
+			execCfg.InternalDB,
+			p.User(),
+		)
+		c := &backupCompactor{
+			execCfg: execCfg,
+			user:    p.User(),
+			details: details,
+			kmsEnv:  &kmsEnv,
+		}
+		if err := c.compact(ctx); err != nil {
+			return err
+		}
+		prog = c.prog
+		prog.CompactedManifestWritten = true
+		if err := r.job.NoTxn().FractionProgressed(ctx, func(
+			ctx context.Context, details jobspb.ProgressDetails,
+		) float32 {
+			*details.(*jobspb.Progress_BackupCompaction).BackupCompaction = prog
+			return 1
+		}); err != nil {
+			return err
+		}
+	}
+
+	if details.DeleteCompactedLayers {
+		if err := r.deleteCompactedLayers(ctx, execCfg, p.User(), details); err != nil {
+			return err
+		}
+	}
+	return r.unlock(ctx, execCfg, p.User(), details)
+}
+
+// deleteCompactedLayers deletes the layers which were compacted, unless a job
+// which may read them is running, in which case a retryable error is returned
+// so that the deletion is attempted again later.
+func (r *backupCompactionResumer) deleteCompactedLayers(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	details jobspb.BackupCompactionDetails,
+) error {
+	var readerExists bool
+	if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) (err error) {
+		readerExists, err = jobs.RunningJobExists(ctx, jobspb.InvalidJobID, txn,
+			execCfg.Settings.Version, jobspb.TypeRestore, jobspb.TypeVerifyBackup)
+		return err
+	}); err != nil {
+		return err
+	}
+	if readerExists {
+		return jobs.MarkAsRetryJobError(errors.New(
+			"waiting for running RESTORE and VERIFY BACKUP jobs to finish before deleting compacted backup layers"))
+	}
+	for _, uri := range details.URIs {
+		if err := deleteBackupLayer(ctx, execCfg, user, uri); err != nil {
+			return errors.Wrapf(err, "deleting compacted backup layer")
+		}
+	}
+	return nil
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. If the manifest of the
+// compacted layer was not written, the files written to it are deleted.
+func (r *backupCompactionResumer) OnFailOrCancel(
+	ctx context.Context, execCtx interface{}, jobErr error,
+) error {
+	p := execCtx.(sql.JobExecContext)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.BackupCompactionDetails)
+	if prog := r.job.Progress().GetBackupCompaction(); prog == nil || !prog.CompactedManifestWritten {
+		if err := deleteBackupLayer(ctx, execCfg, p.User(), details.CompactedURI); err != nil {
+			log.Warningf(ctx, "failed to delete partially written compacted backup layer: %v", err)
+		}
+	}
+	return r.unlock(ctx, execCfg, p.User(), details)
+}
+
+// CollectProfile is a part of the Resumer interface.
+func (r *backupCompactionResumer) CollectProfile(_ context.Context, _ interface{}) error {
+	return nil
+}
+
+// ReportResults implements JobResultsReporter interface.
+func (r *backupCompactionResumer) ReportResults(
+	ctx context.Context, resultsCh chan<- tree.Datums,
+) error {
+	prog := r.job.Progress().GetBackupCompaction()
+	select {
+	case resultsCh <- tree.Datums{
+		tree.NewDInt(tree.DInt(r.job.ID())),
+		tree.NewDString(string(jobs.StatusSucceeded)),
+		tree.NewDInt(tree.DInt(prog.Files)),
+		tree.NewDInt(tree.DInt(prog.Keys)),
+		tree.NewDInt(tree.DInt(prog.Bytes)),
+	}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// unlock removes the lock file which the job wrote when it was planned.
+func (r *backupCompactionResumer) unlock(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	details jobspb.BackupCompactionDetails,
+) error {
+	store, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, details.Dest, user)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	lockFileName := fmt.Sprintf("%s%d", backupCompactionLockFilePrefix, r.job.ID())
+	if err := store.Delete(ctx, lockFileName); err != nil && !errors.Is(err, cloud.ErrFileDoesNotExist) {
+		return errors.Wrap(err, "removing compaction lock file")
+	}
+	return nil
+}
+
+// deleteBackupLayer deletes the files of the backup layer at uri. Its manifest
+// is deleted first, so that the layer is no longer listed as part of the
+// backup even if the deletion of its other files fails.
+func deleteBackupLayer(
+	ctx context.Context, execCfg *sql.ExecutorConfig, user username.SQLUsername, uri string,
+) error {
+	store, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, uri, user)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	for _, name := range []string{
+		backupbase.BackupManifestName,
+		backupbase.BackupOldManifestName,
+		backupbase.BackupMetadataName,
+	} {
+		if err := store.Delete(ctx, name); err != nil && !errors.Is(err, cloud.ErrFileDoesNotExist) {
+			return err
+		}
+	}
+	var files []string
+	if err := store.List(ctx, "", "", func(f string) error {
+		files = append(files, f)
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := store.Delete(ctx, f); err != nil && !errors.Is(err, cloud.ErrFileDoesNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// layerFile is a file of one of the layers which are compacted.
+type layerFile struct {
+	layer int
+	file  backuppb.BackupManifest_File
+}
+
+// backupCompactor writes the compacted layer of a backup.
+type backupCompactor struct {
+	execCfg *sql.ExecutorConfig
+	user    username.SQLUsername
+	details jobspb.BackupCompactionDetails
+	kmsEnv  cloud.KMSEnv
+
+	manifests  []backuppb.BackupManifest
+	stores     []cloud.ExternalStorage
+	dest       cloud.ExternalStorage
+	encryption *kvpb.FileEncryptionOptions
+	endTime    hlc.Timestamp
+	pkIDs      map[uint64]bool
+
+	// introduced contains the introduced spans of all of the compacted layers,
+	// and laterIntroduced[i] those of the layers after the i'th. Data of the
+	// i'th layer in the latter is superseded by that of the later layer.
+	introduced      roachpb.SpanGroup
+	laterIntroduced []roachpb.SpanGroup
+
+	// The data file which is being written, and the entries of the manifest
+	// of the compacted layer for the data in it.
+	out          io.WriteCloser
+	outName      string
+	outSize      int64
+	sst          storage.SSTWriter
+	pendingFiles []backuppb.BackupManifest_File
+
+	files []backuppb.BackupManifest_File
+	prog  jobspb.BackupCompactionProgress
+}
+
+// compact writes the data files and the manifest of the compacted layer.
+func (c *backupCompactor) compact(ctx context.Context) error {
+	mkStore := c.execCfg.DistSQLSrv.ExternalStorageFromURI
+	mem := c.execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+
+	var memSize int64
+	var err error
+	c.manifests, memSize, err = backupinfo.GetBackupManifests(ctx, &mem, c.user, mkStore,
+		c.details.URIs, c.details.Encryption, c.kmsEnv)
+	if err != nil {
+		return err
+	}
+	defer mem.Shrink(ctx, memSize)
+	last := c.manifests[len(c.manifests)-1]
+	c.endTime = last.EndTime
+
+	if c.details.Encryption != nil {
+		key := c.details.Encryption.Key
+		if c.details.Encryption.Mode == jobspb.EncryptionMode_KMS {
+			key, err = backupencryption.GetEncryptionKey(ctx, c.details.Encryption, c.kmsEnv)
+			if err != nil {
+				return errors.Wrap(err, "failed to decrypt data key")
+			}
+		}
+		c.encryption = &kvpb.FileEncryptionOptions{Key: key}
+	}
+
+	c.stores = make([]cloud.ExternalStorage, len(c.manifests))
+	for i := range c.manifests {
+		c.stores[i], err = c.execCfg.DistSQLSrv.ExternalStorage(ctx, c.manifests[i].Dir)
+		if err != nil {
+			return err
+		}
+		defer c.stores[i].Close()
+	}
+	c.dest, err = mkStore(ctx, c.details.CompactedURI, c.user)
+	if err != nil {
+		return err
+	}
+	defer c.dest.Close()
+
+	iterFactories, err := backupinfo.GetBackupManifestIterFactories(ctx,
+		c.execCfg.DistSQLSrv.ExternalStorage, c.manifests, c.details.Encryption, c.kmsEnv)
+	if err != nil {
+		return err
+	}
+	var files []layerFile
+	for i := range c.manifests {
+		it, err := iterFactories[i].NewFileIter(ctx)
+		if err != nil {
+			return err
+		}
+		for ; ; it.Next() {
+			if ok, err := it.Valid(); err != nil {
+				it.Close()
+				return err
+			} else if !ok {
+				break
+			}
+			files = append(files, layerFile{layer: i, file: *it.Value()})
+		}
+		it.Close()
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].file.Span.Key.Compare(files[j].file.Span.Key) < 0
+	})
+
+	c.laterIntroduced = make([]roachpb.SpanGroup, len(c.manifests))
+	var boundaries []roachpb.Key
+	for i := len(c.manifests) - 1; i >= 0; i-- {
+		if i+1 < len(c.manifests) {
+			c.laterIntroduced[i].Add(c.laterIntroduced[i+1].Slice()...)
+			c.laterIntroduced[i].Add(c.manifests[i+1].IntroducedSpans...)
+		}
+		c.introduced.Add(c.manifests[i].IntroducedSpans...)
+		for _, sp := range c.manifests[i].IntroducedSpans {
+			boundaries = append(boundaries, sp.Key, sp.EndKey)
+		}
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i].Compare(boundaries[j]) < 0 })
+
+	descs, err := c.collectDescriptors(ctx, iterFactories[len(c.manifests)-1])
+	if err != nil {
+		return err
+	}
+	c.pkIDs = make(map[uint64]bool)
+	for i := range descs {
+		if t, _, _, _, _ := descpb.GetDescriptors(&descs[i]); t != nil {
+			c.pkIDs[kvpb.BulkOpSummaryID(uint64(t.ID), uint64(t.PrimaryIndex.ID))] = true
+		}
+	}
+
+	// Group the files whose spans overlap, and compact each group, split at
+	// the boundaries of the introduced spans so that the data which each part
+	// of the group restores comes from the same layers.
+	for i := 0; i < len(files); {
+		group := files[i].file.Span
+		j := i + 1
+		for ; j < len(files) && files[j].file.Span.Key.Compare(group.EndKey) < 0; j++ {
+			if files[j].file.Span.EndKey.Compare(group.EndKey) > 0 {
+				group.EndKey = files[j].file.Span.EndKey
+			}
+		}
+		start := group.Key
+		for b := sort.Search(len(boundaries), func(k int) bool {
+			return boundaries[k].Compare(group.Key) > 0
+		}); b < len(boundaries) && boundaries[b].Compare(group.EndKey) < 0; b++ {
+			if boundaries[b].Equal(start) {
+				continue
+			}
+			if err := c.compactSpan(ctx, roachpb.Span{Key: start, EndKey: boundaries[b]}, files[i:j]); err != nil {
+				return err
+			}
+			start = boundaries[b]
+		}
+		if err := c.compactSpan(ctx, roachpb.Span{Key: start, EndKey: group.EndKey}, files[i:j]); err != nil {
+			return err
+		}
+		i = j
+	}
+	if err := c.flush(); err != nil {
+		return err
+	}
+
+	return c.writeManifest(ctx, iterFactories, descs)
+}
+
+// collectDescriptors returns the descriptors of the last compacted layer.
+func (c *backupCompactor) collectDescriptors(
+	ctx context.Context, f *backupinfo.IterFactory,
+) ([]descpb.Descriptor, error) {
+	it := f.NewDescIter(ctx)
+	defer it.Close()
+	var descs []descpb.Descriptor
+	for ; ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			return nil, err
+		} else if !ok {
+			break
+		}
+		descs = append(descs, *protoutil.Clone(it.Value()).(*descpb.Descriptor))
+	}
+	return descs, nil
+}
+
+// compactSpan writes the data in the span of the given files to the compacted
+// layer. Unless the revision history of the layers is kept, only the latest
+// revision of each key is written.
+func (c *backupCompactor) compactSpan(
+	ctx context.Context, span roachpb.Span, candidates []layerFile,
+) error {
+	type fileKey struct {
+		layer int
+		path  string
+	}
+	seen := make(map[fileKey]struct{})
+	var storeFiles []storageccl.StoreFile
+	for _, f := range candidates {
+		if !f.file.Span.Overlaps(span) || c.laterIntroduced[f.layer].Encloses(span) {
+			continue
+		}
+		k := fileKey{layer: f.layer, path: f.file.Path}
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		storeFiles = append(storeFiles, storageccl.StoreFile{Store: c.stores[f.layer], FilePath: f.file.Path})
+	}
+	if len(storeFiles) == 0 {
+		return nil
+	}
+
+	var rows storage.RowCounter
+	var written bool
+	if err := func() error {
+		iter, err := storageccl.ExternalSSTReader(ctx, storeFiles, c.encryption, storage.IterOptions{
+			KeyTypes:   storage.IterKeyTypePointsOnly,
+			LowerBound: span.Key,
+			UpperBound: span.EndKey,
+		})
+		if err != nil {
+			return err
+		}
+		defer iter.Close()
+		for iter.SeekGE(storage.MVCCKey{Key: span.Key}); ; {
+			if ok, err := iter.Valid(); err != nil {
+				return err
+			} else if !ok {
+				return nil
+			}
+			key := iter.UnsafeKey()
+			value, err := iter.UnsafeValue()
+			if err != nil {
+				return err
+			}
+			if err := c.open(ctx); err != nil {
+				return err
+			}
+			if key.Timestamp.IsEmpty() {
+				err = c.sst.PutUnversioned(key.Key, value)
+			} else {
+				err = c.sst.PutRawMVCC(key, value)
+			}
+			if err != nil {
+				return err
+			}
+			if err := rows.Count(key.Key); err != nil {
+				return err
+			}
+			size := int64(len(key.Key) + len(value))
+			rows.DataSize += size
+			c.outSize += size
+			c.prog.Keys++
+			c.prog.Bytes += size
+			written = true
+			if c.details.RevisionHistory {
+				iter.Next()
+			} else {
+				iter.NextKey()
+			}
+		}
+	}(); err != nil {
+		return errors.Wrapf(err, "compacting %s", span)
+	}
+
+	if err := func() error {
+		iter, err := storageccl.ExternalSSTReader(ctx, storeFiles, c.encryption, storage.IterOptions{
+			KeyTypes:   storage.IterKeyTypeRangesOnly,
+			LowerBound: span.Key,
+			UpperBound: span.EndKey,
+		})
+		if err != nil {
+			return err
+		}
+		defer iter.Close()
+		for iter.SeekGE(storage.MVCCKey{Key: span.Key}); ; iter.Next() {
+			if ok, err := iter.Valid(); err != nil {
+				return err
+			} else if !ok {
+				return nil
+			}
+			if err := c.open(ctx); err != nil {
+				return err
+			}
+			rangeKeys := iter.RangeKeys()
+			for _, v := range rangeKeys.Versions {
+				if err := c.sst.PutRawMVCCRangeKey(rangeKeys.AsRangeKey(v), v.Value); err != nil {
+					return err
+				}
+			}
+			written = true
+		}
+	}(); err != nil {
+		return errors.Wrapf(err, "compacting range keys of %s", span)
+	}
+	if !written {
+		return nil
+	}
+
+	f := backuppb.BackupManifest_File{
+		Span:        span,
+		Path:        c.outName,
+		EntryCounts: countRows(rows.BulkOpSummary, c.pkIDs),
+	}
+	// Data in introduced spans is restored from the start of time, which is
+	// marked in the same way as in the files of an incremental backup.
+	if c.introduced.Encloses(span) {
+		f.EndTime = c.endTime
+	}
+	if l := len(c.pendingFiles) - 1; l >= 0 && c.pendingFiles[l].Span.EndKey.Equal(span.Key) &&
+		c.pendingFiles[l].EndTime.EqOrdering(f.EndTime) {
+		c.pendingFiles[l].Span.EndKey = span.EndKey
+		c.pendingFiles[l].EntryCounts.Add(f.EntryCounts)
+	} else {
+		c.pendingFiles = append(c.pendingFiles, f)
+	}
+	if c.outSize > targetFileSize.Get(&c.execCfg.Settings.SV) {
+		return c.flush()
+	}
+	return nil
+}
+
+// open creates a new data file in the compacted layer, unless one is already
+// being written.
+func (c *backupCompactor) open(ctx context.Context) error {
+	if c.out != nil {
+		return nil
+	}
+	c.outName = generateUniqueSSTName(c.execCfg.NodeInfo.NodeID.SQLInstanceID())
+	w, err := c.dest.Writer(ctx, c.outName)
+	if err != nil {
+		return err
+	}
+	c.out = w
+	if c.encryption != nil {
+		e, err := storageccl.EncryptingWriter(w, c.encryption.Key)
+		if err != nil {
+			return err
+		}
+		c.out = e
+	}
+	c.sst = storage.MakeBackupSSTWriter(ctx, c.dest.Settings(), c.out)
+	return nil
+}
+
+// flush finishes the data file which is being written.
+func (c *backupCompactor) flush() error {
+	if c.out == nil {
+		return nil
+	}
+	if err := c.sst.Finish(); err != nil {
+		return err
+	}
+	if err := c.out.Close(); err != nil {
+		return errors.Wrap(err, "writing SST")
+	}
+	for i := range c.pendingFiles {
+		c.pendingFiles[i].BackingFileSize = c.sst.Meta.Size
+	}
+	c.files = append(c.files, c.pendingFiles...)
+	c.prog.Files++
+	c.pendingFiles = nil
+	c.out = nil
+	c.outName = ""
+	c.outSize = 0
+	return nil
+}
+
+// writeManifest writes the manifest of the compacted layer, which is that of
+// the last compacted layer, extended to the start time of the first one, with
+// the data files of the compacted layer.
+func (c *backupCompactor) writeManifest(
+	ctx context.Context, iterFactories map[int]*backupinfo.IterFactory, descs []descpb.Descriptor,
+) error {
+	first, last := c.manifests[0], c.manifests[len(c.manifests)-1]
+
+	m := last
+	m.ID = uuid.MakeV4()
+	m.StartTime = first.StartTime
+	m.Dir = c.dest.Conf()
+	m.Files = c.files
+	m.Descriptors = descs
+	m.IntroducedSpans = c.introduced.Slice()
+	m.HasExternalManifestSSTs = false
+	m.PartitionDescriptorFilenames = nil
+	m.LocalityKVs = nil
+	m.Compacted = true
+	m.EntryCounts = roachpb.RowCount{}
+	for _, f := range c.files {
+		m.EntryCounts.Add(f.EntryCounts)
+	}
+	m.DescriptorChanges = nil
+	if c.details.RevisionHistory {
+		m.MVCCFilter = backuppb.MVCCFilter_All
+		m.RevisionStartTime = first.RevisionStartTime
+		for i := range c.manifests {
+			it := iterFactories[i].NewDescriptorChangesIter(ctx)
+			for ; ; it.Next() {
+				if ok, err := it.Valid(); err != nil {
+					it.Close()
+					return err
+				} else if !ok {
+					break
+				}
+				rev := *it.Value()
+				if rev.Desc != nil {
+					rev.Desc = protoutil.Clone(rev.Desc).(*descpb.Descriptor)
+				}
+				m.DescriptorChanges = append(m.DescriptorChanges, rev)
+			}
+			it.Close()
+		}
+	} else {
+		m.MVCCFilter = backuppb.MVCCFilter_Latest
+		m.RevisionStartTime = hlc.Timestamp{}
+	}
+
+	if err := c.copyStatistics(ctx, last); err != nil {
+		return err
+	}
+
+	if err := backupinfo.WriteBackupManifest(ctx, c.dest, backupbase.BackupManifestName,
+		c.details.Encryption, c.kmsEnv, &m); err != nil {
+		return err
+	}
+	if backupinfo.WriteMetadataWithExternalSSTsEnabled.Get(&c.execCfg.Settings.SV) {
+		if err := backupinfo.WriteMetadataWithExternalSSTs(ctx, c.dest, c.details.Encryption,
+			c.kmsEnv, &m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyStatistics copies the table statistics of the last compacted layer to
+// the compacted layer.
+func (c *backupCompactor) copyStatistics(ctx context.Context, last backuppb.BackupManifest) error {
+	names := map[string]struct{}{backupinfo.BackupStatisticsFileName: {}}
+	for _, name := range last.StatisticsFilenames {
+		names[name] = struct{}{}
+	}
+	src := c.stores[len(c.stores)-1]
+	for name := range names {
+		r, _, err := src.ReadFile(ctx, name, cloud.ReadOptions{NoFileSize: true})
+		if err != nil {
+			if errors.Is(err, cloud.ErrFileDoesNotExist) {
+				continue
+			}
+			return err
+		}
+		buf, err := ioctx.ReadAll(ctx, r)
+		r.Close(ctx)
+		if err != nil {
+			return err
+		}
+		if err := cloud.WriteFile(ctx, c.dest, name, bytes.NewReader(buf)); err != nil {
+			return errors.Wrapf(err, "copying %s", name)
+		}
+	}
+	return nil
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeBackupCompaction,
+		func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+			return &backupCompactionResumer{job: job}
+		},
+		jobs.UsesTenantCostControl,
+	)
+}
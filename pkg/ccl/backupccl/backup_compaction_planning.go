@@ -0,0 +1,560 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudprivilege"
+	"github.com/cockroachdb/cockroach/pkg/featureflag"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/asof"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/syntheticprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+// backupCompactionLockFilePrefix is the prefix of the lock file that a
+// compaction writes to the directory containing the incremental layers of a
+// backup, which prevents two compactions of the same backup from running at
+// the same time.
+const backupCompactionLockFilePrefix = "COMPACTION-LOCK-"
+
+var backupCompactionOptionExpectValues = map[string]exprutil.KVStringOptValidate{
+	tree.AlterBackupCompactOptionDetached:              exprutil.KVStringOptRequireNoValue,
+	tree.AlterBackupCompactOptionRevisionHistory:       exprutil.KVStringOptRequireNoValue,
+	tree.AlterBackupCompactOptionStartTime:             exprutil.KVStringOptRequireValue,
+	tree.AlterBackupCompactOptionEndTime:               exprutil.KVStringOptRequireValue,
+	tree.AlterBackupCompactOptionEncryptionPassphrase:  exprutil.KVStringOptRequireValue,
+	tree.AlterBackupCompactOptionKMS:                   exprutil.KVStringOptRequireValue,
+	tree.AlterBackupCompactOptionIncrementalLocation:   exprutil.KVStringOptRequireValue,
+	tree.AlterBackupCompactOptionDeleteCompactedLayers: exprutil.KVStringOptRequireNoValue,
+}
+
+// backupCompactionHeader is the header of the results of an ALTER BACKUP ...
+// COMPACT which is not detached.
+var backupCompactionHeader = colinfo.ResultColumns{
+	{Name: "job_id", Typ: types.Int},
+	{Name: "status", Typ: types.String},
+	{Name: "files", Typ: types.Int},
+	{Name: "keys", Typ: types.Int},
+	{Name: "bytes", Typ: types.Int},
+}
+
+// getAlterBackupCompact returns the COMPACT command of an ALTER BACKUP
+// statement, or nil if it has none.
+func getAlterBackupCompact(stmt *tree.AlterBackup) *tree.AlterBackupCompact {
+	for _, cmd := range stmt.Cmds {
+		if compact, ok := cmd.(*tree.AlterBackupCompact); ok {
+			return compact
+		}
+	}
+	return nil
+}
+
+func backupCompactionTypeCheck(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (matched bool, header colinfo.ResultColumns, _ error) {
+	alterStmt, ok := stmt.(*tree.AlterBackup)
+	if !ok {
+		return false, nil, nil
+	}
+	compact := getAlterBackupCompact(alterStmt)
+	if compact == nil {
+		return false, nil, nil
+	}
+	if len(alterStmt.Cmds) > 1 {
+		return false, nil, errors.New("COMPACT cannot be combined with other ALTER BACKUP commands")
+	}
+	if err := exprutil.TypeCheck(
+		ctx, "ALTER BACKUP", p.SemaCtx(),
+		exprutil.Strings{alterStmt.Backup, alterStmt.Subdir},
+		exprutil.KVOptions{
+			KVOptions: compact.Options, Validation: backupCompactionOptionExpectValues,
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	if compact.Options.HasKey(tree.AlterBackupCompactOptionDetached) {
+		return true, jobs.DetachedJobExecutionResultHeader, nil
+	}
+	return true, backupCompactionHeader, nil
+}
+
+// backupCompactionPlanHook implements sql.PlanHookFn for ALTER BACKUP ...
+// COMPACT, which starts a job that merges a range of the incremental layers of
+// a backup into a single layer.
+func backupCompactionPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	alterStmt, ok := stmt.(*tree.AlterBackup)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+	compact := getAlterBackupCompact(alterStmt)
+	if compact == nil {
+		return nil, nil, nil, false, nil
+	}
+	if len(alterStmt.Cmds) > 1 {
+		return nil, nil, nil, false, errors.New(
+			"COMPACT cannot be combined with other ALTER BACKUP commands")
+	}
+
+	if err := featureflag.CheckEnabled(
+		ctx,
+		p.ExecCfg(),
+		featureBackupEnabled,
+		"ALTER BACKUP",
+	); err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn, header, err := planBackupCompaction(ctx, p, alterStmt, compact, 0 /* threshold */)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	return fn, header, nil, false, nil
+}
+
+// planBackupCompaction plans the compaction of the incremental layers of the
+// backup that the statement refers to.
+//
+// If threshold is positive, the compaction was requested by a backup schedule,
+// and the returned function only starts a job if there are at least threshold
+// layers to compact and no other compaction of the backup is running.
+func planBackupCompaction(
+	ctx context.Context,
+	p sql.PlanHookState,
+	alterStmt *tree.AlterBackup,
+	compact *tree.AlterBackupCompact,
+	threshold int64,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, error) {
+	exprEval := p.ExprEvaluator("ALTER BACKUP")
+	backup, err := exprEval.String(ctx, alterStmt.Backup)
+	if err != nil {
+		return nil, nil, err
+	}
+	var subdir string
+	if alterStmt.Subdir != nil {
+		subdir, err = exprEval.String(ctx, alterStmt.Subdir)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	opts, err := exprEval.KVOptions(ctx, compact.Options, backupCompactionOptionExpectValues)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, isDetached := opts[tree.AlterBackupCompactOptionDetached]
+	_, revisionHistory := opts[tree.AlterBackupCompactOptionRevisionHistory]
+	_, deleteCompactedLayers := opts[tree.AlterBackupCompactOptionDeleteCompactedLayers]
+	if _, ok := opts[tree.AlterBackupCompactOptionEncryptionPassphrase]; ok {
+		if _, ok := opts[tree.AlterBackupCompactOptionKMS]; ok {
+			return nil, nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot specify both %s and %s",
+				tree.AlterBackupCompactOptionEncryptionPassphrase, tree.AlterBackupCompactOptionKMS)
+		}
+	}
+	evalCtx := &p.ExtendedEvalContext().Context
+	parseTime := func(opt string) (hlc.Timestamp, error) {
+		v, ok := opts[opt]
+		if !ok {
+			return hlc.Timestamp{}, nil
+		}
+		ts, err := asof.DatumToHLC(evalCtx, evalCtx.GetStmtTimestamp(), tree.NewDString(v), asof.AsOf)
+		if err != nil {
+			return hlc.Timestamp{}, errors.Wrapf(err, "invalid %s", opt)
+		}
+		return ts, nil
+	}
+	startTime, err := parseTime(tree.AlterBackupCompactOptionStartTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	endTime, err := parseTime(tree.AlterBackupCompactOptionEndTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !endTime.IsEmpty() && endTime.LessEq(startTime) {
+		return nil, nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"%s must be greater than %s",
+			tree.AlterBackupCompactOptionEndTime, tree.AlterBackupCompactOptionStartTime)
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, alterStmt.StatementTag())
+		defer span.Finish()
+
+		if !(p.ExtendedEvalContext().TxnIsSingleStmt || isDetached) {
+			return errors.Errorf("ALTER BACKUP ... COMPACT cannot be used inside a multi-statement transaction without DETACHED option")
+		}
+
+		dest := []string{backup}
+		if err := checkPrivilegesForBackupCompaction(ctx, p, dest); err != nil {
+			return err
+		}
+
+		mkStore := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
+		subdir := subdir
+		if strings.EqualFold(subdir, backupbase.LatestFileName) {
+			var err error
+			subdir, err = backupdest.ReadLatestFile(ctx, dest[0], mkStore, p.User())
+			if err != nil {
+				return errors.Wrap(err, "read LATEST path")
+			}
+		}
+		fullyResolvedDest, err := backuputils.AppendPaths(dest, subdir)
+		if err != nil {
+			return err
+		}
+		baseStore, err := mkStore(ctx, fullyResolvedDest[0], p.User())
+		if err != nil {
+			return errors.Wrapf(err, "make storage")
+		}
+		defer baseStore.Close()
+
+		kmsEnv := backupencryption.MakeBackupKMSEnv(
+			p.ExecCfg().Settings,
+			&p.ExecCfg().ExternalIODirConfig,
+			p.ExecCfg().InternalDB,
+			p.User(),
+		)
+		encryption, err := resolveBackupEncryptionForRead(ctx, baseStore,
+			opts[tree.AlterBackupCompactOptionEncryptionPassphrase],
+			opts[tree.AlterBackupCompactOptionKMS], &kmsEnv)
+		if err != nil {
+			return err
+		}
+
+		var explicitIncPaths []string
+		if incLoc, ok := opts[tree.AlterBackupCompactOptionIncrementalLocation]; ok {
+			explicitIncPaths = []string{incLoc}
+		}
+		collections, computedSubdir, err := backupdest.CollectionsAndSubdir(dest, subdir)
+		if err != nil {
+			return err
+		}
+		fullyResolvedIncrementalsDirectory, err := backupdest.ResolveIncrementalsBackupLocation(
+			ctx,
+			p.User(),
+			p.ExecCfg(),
+			explicitIncPaths,
+			collections,
+			computedSubdir,
+		)
+		if err != nil {
+			return err
+		}
+		incStores, cleanupFn, err := backupdest.MakeBackupDestinationStores(ctx, p.User(), mkStore,
+			fullyResolvedIncrementalsDirectory)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := cleanupFn(); err != nil {
+				log.Warningf(ctx, "failed to close incremental store: %+v", err)
+			}
+		}()
+
+		mem := p.ExecCfg().RootMemoryMonitor.MakeBoundAccount()
+		defer mem.Close(ctx)
+		defaultURIs, manifests, localityInfo, memReserved, err := backupdest.ResolveBackupManifests(
+			ctx, &mem, []cloud.ExternalStorage{baseStore}, incStores, mkStore, fullyResolvedDest,
+			fullyResolvedIncrementalsDirectory, hlc.Timestamp{}, encryption, &kmsEnv, p.User())
+		if err != nil {
+			return err
+		}
+		defer mem.Shrink(ctx, memReserved)
+
+		// Pick the incremental layers which lie between the start and end time.
+		// The layers of the chain are ordered by time, so they are contiguous.
+		var layers []int
+		for i := 1; i < len(manifests); i++ {
+			if manifests[i].StartTime.Less(startTime) {
+				continue
+			}
+			if !endTime.IsEmpty() && endTime.Less(manifests[i].EndTime) {
+				break
+			}
+			layers = append(layers, i)
+		}
+		if threshold > 0 {
+			if int64(len(layers)) < threshold {
+				return nil
+			}
+		} else if len(layers) < 2 {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"found %d incremental layers of the backup between the requested times; at least 2"+
+					" are required to compact", len(layers))
+		}
+		for _, i := range layers {
+			if len(localityInfo[i].URIsByOriginalLocalityKV) > 0 {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"cannot compact locality-aware backup layer %s", defaultURIs[i])
+			}
+			if revisionHistory && manifests[i].MVCCFilter != backuppb.MVCCFilter_All {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					"cannot compact with %s: backup layer %s was not taken with revision_history",
+					tree.AlterBackupCompactOptionRevisionHistory, defaultURIs[i])
+			}
+		}
+		first, last := layers[0], layers[len(layers)-1]
+
+		compactedURI, err := chooseCompactedLayerURI(ctx, p, defaultURIs[last])
+		if err != nil {
+			return err
+		}
+
+		jobID := p.ExecCfg().JobRegistry.MakeJobID()
+		if locked, err := lockBackupForCompaction(ctx, p, incStores[0], jobID); err != nil {
+			return err
+		} else if !locked {
+			if threshold > 0 {
+				log.Infof(ctx, "skipping compaction of backup %s which is already being compacted", subdir)
+				return nil
+			}
+			return pgerror.New(pgcode.ObjectInUse, "the backup is already being compacted")
+		}
+
+		jobDesc, err := backupCompactionJobDescription(p, alterStmt, subdir, backup, opts)
+		if err != nil {
+			return err
+		}
+		jr := jobs.Record{
+			Description: jobDesc,
+			Username:    p.User(),
+			Details: jobspb.BackupCompactionDetails{
+				URIs:                  defaultURIs[first : last+1],
+				Encryption:            encryption,
+				Dest:                  fullyResolvedIncrementalsDirectory[0],
+				CompactedURI:          compactedURI,
+				RevisionHistory:       revisionHistory,
+				DeleteCompactedLayers: deleteCompactedLayers,
+			},
+			Progress: jobspb.BackupCompactionProgress{},
+		}
+
+		if isDetached {
+			if _, err := p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(
+				ctx, jr, jobID, p.InternalSQLTxn(),
+			); err != nil {
+				return err
+			}
+			resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(jobID))}
+			return nil
+		}
+
+		plannerTxn := p.InternalSQLTxn()
+		var sj *jobs.StartableJob
+		if err := func() (err error) {
+			defer func() {
+				if err == nil || sj == nil {
+					return
+				}
+				if cleanupErr := sj.CleanupOnRollback(ctx); cleanupErr != nil {
+					log.Errorf(ctx, "failed to cleanup job: %v", cleanupErr)
+				}
+			}()
+			if err := p.ExecCfg().JobRegistry.CreateStartableJobWithTxn(ctx, &sj, jobID, plannerTxn, jr); err != nil {
+				return err
+			}
+			// We commit the transaction here so that the job can be started. This
+			// is safe because we're in an implicit transaction.
+			return plannerTxn.KV().Commit(ctx)
+		}(); err != nil {
+			return err
+		}
+		p.InternalSQLTxn().Descriptors().ReleaseAll(ctx)
+		if err := sj.Start(ctx); err != nil {
+			return err
+		}
+		if err := sj.AwaitCompletion(ctx); err != nil {
+			return err
+		}
+		return sj.ReportExecutionResults(ctx, resultsCh)
+	}
+
+	if isDetached {
+		return fn, jobs.DetachedJobExecutionResultHeader, nil
+	}
+	return fn, backupCompactionHeader, nil
+}
+
+// checkPrivilegesForBackupCompaction checks that the user may rewrite the
+// backup. Compacting a backup reads all of its incremental layers and writes a
+// new one, which requires the same privileges as backing up the cluster.
+func checkPrivilegesForBackupCompaction(
+	ctx context.Context, p sql.PlanHookState, dest []string,
+) error {
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	if !hasAdmin {
+		if err := p.CheckPrivilegeForUser(
+			ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.BACKUP, p.User(),
+		); err != nil {
+			return pgerror.Wrapf(
+				err,
+				pgcode.InsufficientPrivilege,
+				"only users with the admin role or the BACKUP system privilege are allowed to"+
+					" compact a backup")
+		}
+	}
+	return cloudprivilege.CheckDestinationPrivileges(ctx, p, dest)
+}
+
+// chooseCompactedLayerURI returns the URI of the directory to which the layer
+// compacted from the layers up to the one at lastURI is written. The directory
+// is named after that of the last layer, so that the compacted layer sorts
+// directly after it when the layers of the backup are listed.
+func chooseCompactedLayerURI(
+	ctx context.Context, p sql.PlanHookState, lastURI string,
+) (string, error) {
+	parsed, err := url.Parse(lastURI)
+	if err != nil {
+		return "", err
+	}
+	dir := strings.TrimSuffix(parsed.Path, "/")
+	for n := 1; n < 100; n++ {
+		u := *parsed
+		u.Path = fmt.Sprintf("%s.%02d", dir, n)
+		uri := u.String()
+		exists, err := func() (bool, error) {
+			store, err := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, uri, p.User())
+			if err != nil {
+				return false, err
+			}
+			defer store.Close()
+			r, _, err := store.ReadFile(ctx, backupbase.BackupManifestName, cloud.ReadOptions{NoFileSize: true})
+			if err != nil {
+				if errors.Is(err, cloud.ErrFileDoesNotExist) {
+					return false, nil
+				}
+				return false, err
+			}
+			return true, r.Close(ctx)
+		}()
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return uri, nil
+		}
+	}
+	return "", errors.Newf("too many compacted layers of backup layer %s", path.Base(dir))
+}
+
+// lockBackupForCompaction writes the lock file of the compaction job to the
+// directory of the incremental layers of a backup. It returns false if another
+// compaction of the backup holds the lock. The lock of a job which is no longer
+// running is removed.
+func lockBackupForCompaction(
+	ctx context.Context, p sql.PlanHookState, store cloud.ExternalStorage, jobID jobspb.JobID,
+) (bool, error) {
+	var lockedBy []string
+	if err := store.List(ctx, backupCompactionLockFilePrefix, "/", func(f string) error {
+		lockedBy = append(lockedBy, strings.TrimPrefix(f, "/"))
+		return nil
+	}); err != nil {
+		return false, errors.Wrap(err, "listing compaction lock files")
+	}
+	for _, suffix := range lockedBy {
+		lock := backupCompactionLockFilePrefix + suffix
+		id, err := strconv.ParseInt(suffix, 10, 64)
+		if err != nil {
+			return false, errors.Wrapf(err, "parsing compaction lock file %s", lock)
+		}
+		j, err := p.ExecCfg().JobRegistry.LoadJob(ctx, jobspb.JobID(id))
+		if err != nil && !jobs.HasJobNotFoundError(err) {
+			return false, err
+		}
+		if err == nil && !j.Status().Terminal() {
+			return false, nil
+		}
+		if err := store.Delete(ctx, lock); err != nil {
+			return false, errors.Wrapf(err, "removing stale compaction lock file %s", lock)
+		}
+	}
+	lockFileName := fmt.Sprintf("%s%d", backupCompactionLockFilePrefix, jobID)
+	return true, cloud.WriteFile(ctx, store, lockFileName, bytes.NewReader([]byte("lock")))
+}
+
+func backupCompactionJobDescription(
+	p sql.PlanHookState,
+	alterStmt *tree.AlterBackup,
+	subdir, backup string,
+	opts map[string]string,
+) (string, error) {
+	sanitizedBackup, err := cloud.SanitizeExternalStorageURI(backup, nil /* extraParams */)
+	if err != nil {
+		return "", err
+	}
+	compact := &tree.AlterBackupCompact{}
+	for k, v := range opts {
+		opt := tree.KVOption{Key: tree.Name(k)}
+		switch k {
+		case tree.AlterBackupCompactOptionEncryptionPassphrase:
+			opt.Value = tree.NewDString("redacted")
+		case tree.AlterBackupCompactOptionKMS:
+			redacted, err := cloud.RedactKMSURI(v)
+			if err != nil {
+				return "", err
+			}
+			opt.Value = tree.NewDString(redacted)
+		case tree.AlterBackupCompactOptionIncrementalLocation:
+			sanitized, err := cloud.SanitizeExternalStorageURI(v, nil /* extraParams */)
+			if err != nil {
+				return "", err
+			}
+			opt.Value = tree.NewDString(sanitized)
+		case tree.AlterBackupCompactOptionStartTime, tree.AlterBackupCompactOptionEndTime:
+			opt.Value = tree.NewDString(v)
+		}
+		compact.Options = append(compact.Options, opt)
+	}
+	sort.Slice(compact.Options, func(i, j int) bool { return compact.Options[i].Key < compact.Options[j].Key })
+	stmt := &tree.AlterBackup{
+		Backup: tree.NewDString(sanitizedBackup),
+		Cmds:   tree.AlterBackupCmds{compact},
+	}
+	if alterStmt.Subdir != nil {
+		stmt.Subdir = tree.NewDString(subdir)
+	}
+	ann := p.ExtendedEvalContext().Annotations
+	return tree.AsStringWithFQNames(stmt, ann), nil
+}
+
+func init() {
+	sql.AddPlanHook("alter backup compact", backupCompactionPlanHook, backupCompactionTypeCheck)
+}
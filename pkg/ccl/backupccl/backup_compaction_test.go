@@ -0,0 +1,178 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/jobutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupCompaction(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 100
+	_, sqlDB, dir, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	numLayers := func(t *testing.T, dest string, opts string) int {
+		var n int
+		sqlDB.QueryRow(t,
+			`SELECT count(DISTINCT end_time) FROM [SHOW BACKUP LATEST IN $1`+opts+`]`, dest).Scan(&n)
+		return n
+	}
+	// numManifests returns the number of layers, including those which were
+	// compacted, whose manifest is stored in dest.
+	numManifests := func(t *testing.T, dest string) int {
+		var n int
+		require.NoError(t, filepath.Walk(
+			strings.Replace(dest, localFoo, filepath.Join(dir, "foo"), 1),
+			func(path string, info os.FileInfo, err error) error {
+				if err == nil && info.Name() == backupbase.BackupManifestName {
+					n++
+				}
+				return err
+			}))
+		return n
+	}
+	takeBackups := func(t *testing.T, dest string, opts string) [][]string {
+		sqlDB.Exec(t, `BACKUP TABLE data.bank INTO $1`+opts, dest)
+		for i := 0; i < 3; i++ {
+			sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1 WHERE id < 10`)
+			sqlDB.Exec(t, `DELETE FROM data.bank WHERE id = $1`, numAccounts-i-1)
+			sqlDB.Exec(t, `BACKUP TABLE data.bank INTO LATEST IN $1`+opts, dest)
+		}
+		return sqlDB.QueryStr(t, `SELECT * FROM data.bank ORDER BY id`)
+	}
+	checkRestore := func(t *testing.T, dest string, opts string, expected [][]string) {
+		sqlDB.Exec(t, `DROP DATABASE IF EXISTS restored CASCADE`)
+		sqlDB.Exec(t, `CREATE DATABASE restored`)
+		sqlDB.Exec(t, `RESTORE TABLE data.bank FROM LATEST IN $1 WITH into_db = 'restored'`+
+			opts, dest)
+		require.Equal(t, expected, sqlDB.QueryStr(t, `SELECT * FROM restored.bank ORDER BY id`))
+	}
+
+	t.Run("compact", func(t *testing.T) {
+		dest := localFoo + "/compact"
+		expected := takeBackups(t, dest, "")
+		require.Equal(t, 4, numLayers(t, dest, ""))
+
+		var (
+			jobID              int64
+			status             string
+			files, keys, bytes int
+		)
+		sqlDB.QueryRow(t, `ALTER BACKUP LATEST IN $1 COMPACT`, dest).Scan(
+			&jobID, &status, &files, &keys, &bytes)
+		require.Equal(t, string(jobs.StatusSucceeded), status)
+		require.Greater(t, files, 0)
+		require.Greater(t, keys, 0)
+		require.Equal(t, 2, numLayers(t, dest, ""))
+		// The compacted layers are kept, but ignored by readers of the backup.
+		require.Equal(t, 5, numManifests(t, dest))
+		checkRestore(t, dest, "", expected)
+
+		// Incremental backups can be taken on top of the compacted layer.
+		sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1`)
+		sqlDB.Exec(t, `BACKUP TABLE data.bank INTO LATEST IN $1`, dest)
+		checkRestore(t, dest, "", sqlDB.QueryStr(t, `SELECT * FROM data.bank ORDER BY id`))
+
+		var now string
+		sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()::STRING`).Scan(&now)
+		sqlDB.ExpectErr(t, "at least 2 are required",
+			`ALTER BACKUP LATEST IN $1 COMPACT WITH start_time = $2`, dest, now)
+	})
+
+	t.Run("revision-history", func(t *testing.T) {
+		dest := localFoo + "/revisions"
+		expected := takeBackups(t, dest, " WITH revision_history")
+		var jobID jobspb.JobID
+		sqlDB.QueryRow(t, `ALTER BACKUP LATEST IN $1 COMPACT WITH revision_history, detached`,
+			dest).Scan(&jobID)
+		jobutils.WaitForJobToSucceed(t, sqlDB, jobID)
+		require.Equal(t, 2, numLayers(t, dest, ""))
+		checkRestore(t, dest, "", expected)
+	})
+
+	t.Run("delete-compacted-layers", func(t *testing.T) {
+		dest := localFoo + "/delete"
+		expected := takeBackups(t, dest, "")
+		sqlDB.Exec(t, `SET CLUSTER SETTING jobs.registry.retry.initial_delay = '10ms'`)
+		sqlDB.Exec(t, `SET CLUSTER SETTING jobs.registry.retry.max_delay = '100ms'`)
+		defer sqlDB.Exec(t, `RESET CLUSTER SETTING jobs.registry.retry.initial_delay`)
+		defer sqlDB.Exec(t, `RESET CLUSTER SETTING jobs.registry.retry.max_delay`)
+
+		// A restore which was planned before the compaction may still read the
+		// compacted layers, so they are not deleted until it is no longer running.
+		sqlDB.Exec(t, `SET CLUSTER SETTING jobs.debug.pausepoints = 'restore.before_flow'`)
+		var restoreID jobspb.JobID
+		sqlDB.Exec(t, `CREATE DATABASE paused`)
+		sqlDB.QueryRow(t, `RESTORE TABLE data.bank FROM LATEST IN $1 WITH into_db = 'paused', detached`,
+			dest).Scan(&restoreID)
+		jobutils.WaitForJobToPause(t, sqlDB, restoreID)
+		sqlDB.Exec(t, `SET CLUSTER SETTING jobs.debug.pausepoints = ''`)
+
+		var jobID jobspb.JobID
+		sqlDB.QueryRow(t, `ALTER BACKUP LATEST IN $1 COMPACT WITH delete_compacted_layers, detached`,
+			dest).Scan(&jobID)
+		testutils.SucceedsSoon(t, func() error {
+			var numRuns int
+			sqlDB.QueryRow(t, `SELECT num_runs FROM system.jobs WHERE id = $1`, jobID).Scan(&numRuns)
+			if numRuns < 2 {
+				return errors.Newf("compaction job has run %d times", numRuns)
+			}
+			return nil
+		})
+		require.Equal(t, 5, numManifests(t, dest))
+		require.Equal(t, 2, numLayers(t, dest, ""))
+
+		sqlDB.Exec(t, `CANCEL JOB $1`, restoreID)
+		jobutils.WaitForJobToCancel(t, sqlDB, restoreID)
+		jobutils.WaitForJobToSucceed(t, sqlDB, jobID)
+		require.Equal(t, 2, numManifests(t, dest))
+		require.Equal(t, 2, numLayers(t, dest, ""))
+		checkRestore(t, dest, "", expected)
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		dest := localFoo + "/enc"
+		opts := " WITH encryption_passphrase = 'abc'"
+		expected := takeBackups(t, dest, opts)
+		sqlDB.ExpectErr(t, "failed to decrypt",
+			`ALTER BACKUP LATEST IN $1 COMPACT WITH encryption_passphrase = 'wrong'`, dest)
+		sqlDB.Exec(t, `ALTER BACKUP LATEST IN $1 COMPACT`+opts, dest)
+		require.Equal(t, 2, numLayers(t, dest, opts))
+		checkRestore(t, dest, ", encryption_passphrase = 'abc'", expected)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		dest := localFoo + "/errors"
+		sqlDB.Exec(t, `BACKUP TABLE data.bank INTO $1`, dest)
+		sqlDB.Exec(t, `BACKUP TABLE data.bank INTO LATEST IN $1`, dest)
+		sqlDB.ExpectErr(t, "found 1 incremental layers",
+			`ALTER BACKUP LATEST IN $1 COMPACT`, dest)
+		sqlDB.Exec(t, `BACKUP TABLE data.bank INTO LATEST IN $1`, dest)
+		sqlDB.ExpectErr(t, "was not taken with revision_history",
+			`ALTER BACKUP LATEST IN $1 COMPACT WITH revision_history`, dest)
+		sqlDB.ExpectErr(t, "COMPACT cannot be combined",
+			`ALTER BACKUP LATEST IN $1 COMPACT ADD NEW_KMS = 'a' WITH OLD_KMS = 'b'`, dest)
+	})
+}
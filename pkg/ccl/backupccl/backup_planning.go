@@ -235,6 +235,7 @@ func GetRedactedBackupNode(
 	b := &tree.Backup{
 		AsOf:           backup.AsOf,
 		Targets:        backup.Targets,
+		SystemTarget:   backup.SystemTarget,
 		Nested:         backup.Nested,
 		AppendToLatest: backup.AppendToLatest,
 	}
@@ -389,6 +390,7 @@ func checkPrivilegesForBackup(
 	{
 		// Cluster and tenant backups require the `BACKUP` system privilege.
 		requiresBackupSystemPrivilege := backupStmt.Coverage() == tree.AllDescriptors ||
+			backupStmt.Coverage().IsSystemTarget() ||
 			(backupStmt.Targets != nil && backupStmt.Targets.TenantID.IsSet())
 
 		if requiresBackupSystemPrivilege {
@@ -745,6 +747,12 @@ func backupPlanHook(
 			if err != nil {
 				return err
 			}
+		case tree.SystemUsers, tree.SystemSettings, tree.SystemZoneConfigs:
+			var err error
+			targetDescs, err = systemTargetsBackup(ctx, p.ExecCfg(), endTime, backupStmt.Coverage())
+			if err != nil {
+				return err
+			}
 		default:
 			return errors.AssertionFailedf("unexpected descriptor coverage %v", backupStmt.Coverage())
 		}
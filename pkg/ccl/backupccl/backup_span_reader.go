@@ -0,0 +1,288 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// BackupSpanReader reads the contents of a backup chain in a collection
+// directly from the backup's SSTs, as of the end time of one of the chain's
+// layers. It allows callers which need a consistent snapshot of some spans,
+// such as the initial scan of a changefeed, to read that snapshot from a
+// backup instead of from KV.
+//
+// Encrypted and locality-aware backups are not supported.
+type BackupSpanReader struct {
+	subdir             string
+	manifests          []backuppb.BackupManifest
+	layerToIterFactory backupinfo.LayerToBackupManifestFileIterFactory
+	backupLocalityMap  map[int]storeByLocalityKV
+	filter             spanCoveringFilter
+	mkStore            cloud.ExternalStorageFactory
+	mem                mon.BoundAccount
+}
+
+// NewBackupSpanReader resolves the backup chain in the collection at
+// collectionURI whose full backup is in subdir, or the latest chain if subdir
+// is empty. The chain is read as of endTime, which must be the end time of one
+// of its layers, or as of the end time of its last layer if endTime is empty.
+// The caller is responsible for calling Close on the returned reader.
+func NewBackupSpanReader(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	collectionURI string,
+	subdir string,
+	endTime hlc.Timestamp,
+) (_ *BackupSpanReader, retErr error) {
+	mkStore := execCfg.DistSQLSrv.ExternalStorageFromURI
+	if subdir == "" {
+		var err error
+		subdir, err = backupdest.ReadLatestFile(ctx, collectionURI, mkStore, user)
+		if err != nil {
+			return nil, err
+		}
+	}
+	collections := []string{collectionURI}
+	fullyResolvedBaseDirectory, err := backuputils.AppendPaths(collections, subdir)
+	if err != nil {
+		return nil, err
+	}
+	fullyResolvedIncrementalsDirectory, err := backupdest.ResolveIncrementalsBackupLocation(
+		ctx, user, execCfg, nil /* explicitIncrementalCollections */, collections, subdir,
+	)
+	if err != nil {
+		if !errors.Is(err, cloud.ErrListingUnsupported) {
+			return nil, err
+		}
+		log.Warningf(ctx, "storage sink %s does not support listing, only resolving the base backup",
+			backuputils.RedactURIForErrorMessage(collectionURI))
+	}
+
+	baseStores, cleanupFn, err := backupdest.MakeBackupDestinationStores(ctx, user, mkStore,
+		fullyResolvedBaseDirectory)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cleanupFn(); err != nil {
+			log.Warningf(ctx, "failed to close base store: %+v", err)
+		}
+	}()
+	incStores, cleanupIncFn, err := backupdest.MakeBackupDestinationStores(ctx, user, mkStore,
+		fullyResolvedIncrementalsDirectory)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cleanupIncFn(); err != nil {
+			log.Warningf(ctx, "failed to close incremental store: %+v", err)
+		}
+	}()
+
+	ioConf := baseStores[0].ExternalIOConf()
+	kmsEnv := backupencryption.MakeBackupKMSEnv(
+		execCfg.Settings, &ioConf, execCfg.InternalDB, user,
+	)
+
+	r := &BackupSpanReader{
+		subdir:  subdir,
+		mkStore: execCfg.DistSQLSrv.ExternalStorage,
+		mem:     execCfg.RootMemoryMonitor.MakeBoundAccount(),
+	}
+	defer func() {
+		if retErr != nil {
+			r.Close(ctx)
+		}
+	}()
+
+	// The memory reserved for the manifests is released when the reader's
+	// bound account is closed. Layers added to the chain after endTime are
+	// ignored.
+	var localityInfo []jobspb.RestoreDetails_BackupLocalityInfo
+	_, r.manifests, localityInfo, _, err = backupdest.ResolveBackupManifests(
+		ctx, &r.mem, baseStores, incStores, mkStore, fullyResolvedBaseDirectory,
+		fullyResolvedIncrementalsDirectory, endTime, nil /* encryption */, &kmsEnv, user,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !endTime.IsEmpty() && !endTime.Equal(r.EndTime()) {
+		return nil, errors.Newf("backup has no layer ending at %s", endTime)
+	}
+	for i := range r.manifests {
+		if len(r.manifests[i].LocalityKVs) > 0 {
+			return nil, errors.Newf("reading from locality-aware backups is not supported")
+		}
+	}
+
+	r.layerToIterFactory, err = backupinfo.GetBackupManifestIterFactories(
+		ctx, r.mkStore, r.manifests, nil /* encryption */, &kmsEnv,
+	)
+	if err != nil {
+		return nil, err
+	}
+	r.backupLocalityMap, err = makeBackupLocalityMap(localityInfo, user)
+	if err != nil {
+		return nil, err
+	}
+	introducedSpanFrontier, err := createIntroducedSpanFrontier(r.manifests, hlc.Timestamp{})
+	if err != nil {
+		return nil, err
+	}
+	r.filter, err = makeSpanCoveringFilter(
+		nil, /* checkpointFrontier */
+		nil, /* highWater */
+		introducedSpanFrontier,
+		targetRestoreSpanSize.Get(&execCfg.Settings.SV),
+		false, /* useFrontierCheckpointing */
+	)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Subdir returns the subdirectory of the full backup of the chain within the
+// collection.
+func (r *BackupSpanReader) Subdir() string {
+	return r.subdir
+}
+
+// EndTime returns the timestamp as of which the backup is read.
+func (r *BackupSpanReader) EndTime() hlc.Timestamp {
+	return r.manifests[len(r.manifests)-1].EndTime
+}
+
+// CheckCovers returns an error if the backup was taken of a different tenant
+// than the one whose keys are encoded by codec, or if it does not contain all
+// of the given spans.
+func (r *BackupSpanReader) CheckCovers(codec keys.SQLCodec, spans roachpb.Spans) error {
+	backupCodec, err := backupinfo.MakeBackupCodec(r.manifests[0])
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(backupCodec.TenantPrefix(), codec.TenantPrefix()) {
+		return errors.Newf("backup was not taken of this tenant")
+	}
+	var covered roachpb.SpanGroup
+	covered.Add(r.manifests[len(r.manifests)-1].Spans...)
+	for _, sp := range spans {
+		if !covered.Encloses(sp) {
+			return errors.Newf("backup does not contain span %s", sp)
+		}
+	}
+	return nil
+}
+
+// CoverSpan partitions sp into the chunks of the restore span covering of the
+// backup, each of which only overlaps a subset of the backup's files, and calls
+// fn with each chunk and an iterator over the latest live revision of each key
+// in the chunk as of EndTime. Chunks in which the backup has no data are
+// skipped. The iterator is closed once fn returns.
+func (r *BackupSpanReader) CoverSpan(
+	ctx context.Context,
+	sp roachpb.Span,
+	fn func(chunk roachpb.Span, iter storage.SimpleMVCCIterator) error,
+) error {
+	spanCh := make(chan execinfrapb.RestoreSpanEntry, 16)
+	g := ctxgroup.WithContext(ctx)
+	g.GoCtx(func(ctx context.Context) error {
+		defer close(spanCh)
+		return generateAndSendImportSpans(
+			ctx,
+			roachpb.Spans{sp},
+			r.manifests,
+			r.layerToIterFactory,
+			r.backupLocalityMap,
+			r.filter,
+			false, /* useSimpleImportSpans */
+			spanCh,
+		)
+	})
+	g.GoCtx(func(ctx context.Context) error {
+		for entry := range spanCh {
+			if err := r.readEntry(ctx, entry, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return g.Wait()
+}
+
+// readEntry calls fn with an iterator over the files of an entry of the span
+// covering.
+func (r *BackupSpanReader) readEntry(
+	ctx context.Context,
+	entry execinfrapb.RestoreSpanEntry,
+	fn func(chunk roachpb.Span, iter storage.SimpleMVCCIterator) error,
+) error {
+	if len(entry.Files) == 0 {
+		return nil
+	}
+	var dirs []cloud.ExternalStorage
+	defer func() {
+		for _, dir := range dirs {
+			if err := dir.Close(); err != nil {
+				log.Warningf(ctx, "close export storage failed %v", err)
+			}
+		}
+	}()
+	storeFiles := make([]storageccl.StoreFile, 0, len(entry.Files))
+	for _, f := range entry.Files {
+		dir, err := r.mkStore(ctx, f.Dir)
+		if err != nil {
+			return err
+		}
+		dirs = append(dirs, dir)
+		storeFiles = append(storeFiles, storageccl.StoreFile{Store: dir, FilePath: f.Path})
+	}
+
+	endTime := r.EndTime()
+	iter, err := storageccl.ExternalSSTReader(ctx, storeFiles, nil /* encryption */, storage.IterOptions{
+		RangeKeyMaskingBelow: endTime,
+		KeyTypes:             storage.IterKeyTypePointsAndRanges,
+		LowerBound:           entry.Span.Key,
+		UpperBound:           entry.Span.EndKey,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "opening backup files for %s", entry.Span)
+	}
+	readAsOfIter := storage.NewReadAsOfIterator(iter, endTime)
+	defer readAsOfIter.Close()
+	return fn(entry.Span, readAsOfIter)
+}
+
+// Close releases the resources held by the reader.
+func (r *BackupSpanReader) Close(ctx context.Context) {
+	r.mem.Close(ctx)
+}
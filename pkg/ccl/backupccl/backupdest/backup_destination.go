@@ -632,6 +632,8 @@ func ResolveBackupManifests(
 	totalMemSize := ownedMemSize
 	ownedMemSize = 0
 
+	defaultURIs, mainBackupManifests, localityInfo = backupinfo.ElideCompactedLayers(
+		defaultURIs, mainBackupManifests, localityInfo)
 	validatedDefaultURIs, validatedMainBackupManifests, validatedLocalityInfo, err := backupinfo.ValidateEndTimeAndTruncate(
 		defaultURIs, mainBackupManifests, localityInfo, endTime)
 
@@ -711,6 +713,8 @@ func DeprecatedResolveBackupManifestsExplicitIncrementals(
 	totalMemSize := ownedMemSize
 	ownedMemSize = 0
 
+	defaultURIs, mainBackupManifests, localityInfo = backupinfo.ElideCompactedLayers(
+		defaultURIs, mainBackupManifests, localityInfo)
 	validatedDefaultURIs, validatedMainBackupManifests, validatedLocalityInfo, err :=
 		backupinfo.ValidateEndTimeAndTruncate(defaultURIs, mainBackupManifests, localityInfo, endTime)
 
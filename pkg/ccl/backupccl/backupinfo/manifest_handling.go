@@ -952,6 +952,46 @@ func ValidateEndTimeAndTruncate(
 	)
 }
 
+// ElideCompactedLayers removes the layers of a backup chain whose data is also
+// contained in a compacted layer, i.e. a layer written by ALTER BACKUP ...
+// COMPACT which covers their time interval. The layers that were compacted are
+// only deleted if that was requested, and may still be present even then if
+// the compaction has not deleted them yet.
+func ElideCompactedLayers(
+	defaultURIs []string,
+	mainBackupManifests []backuppb.BackupManifest,
+	localityInfo []jobspb.RestoreDetails_BackupLocalityInfo,
+) ([]string, []backuppb.BackupManifest, []jobspb.RestoreDetails_BackupLocalityInfo) {
+	elided := func(i int) bool {
+		b := mainBackupManifests[i]
+		for j, c := range mainBackupManifests {
+			if i == j || !c.Compacted {
+				continue
+			}
+			if c.StartTime.LessEq(b.StartTime) && b.EndTime.LessEq(c.EndTime) {
+				// Of two compacted layers of the same interval, keep the later one.
+				if !b.Compacted || !c.StartTime.Equal(b.StartTime) ||
+					!c.EndTime.Equal(b.EndTime) || j > i {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	var uris []string
+	var manifests []backuppb.BackupManifest
+	var info []jobspb.RestoreDetails_BackupLocalityInfo
+	for i := range mainBackupManifests {
+		if elided(i) {
+			continue
+		}
+		uris = append(uris, defaultURIs[i])
+		manifests = append(manifests, mainBackupManifests[i])
+		info = append(info, localityInfo[i])
+	}
+	return uris, manifests, info
+}
+
 // GetBackupIndexAtTime returns the index of the latest backup in
 // `backupManifests` with a StartTime >= asOf.
 func GetBackupIndexAtTime(
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupinfo"
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -137,6 +138,75 @@ func TestManifestHandlingEmptyIterators(t *testing.T) {
 	})
 }
 
+// TestElideCompactedLayers tests that the layers of a backup chain which are
+// covered by a compacted layer are removed from it.
+func TestElideCompactedLayers(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	layer := func(start, end int64, compacted bool) backuppb.BackupManifest {
+		return backuppb.BackupManifest{
+			StartTime: hlc.Timestamp{WallTime: start},
+			EndTime:   hlc.Timestamp{WallTime: end},
+			Compacted: compacted,
+		}
+	}
+	for _, tc := range []struct {
+		name     string
+		layers   []backuppb.BackupManifest
+		expected []int
+	}{
+		{
+			name:     "no compacted layers",
+			layers:   []backuppb.BackupManifest{layer(0, 1, false), layer(1, 2, false), layer(2, 3, false)},
+			expected: []int{0, 1, 2},
+		},
+		{
+			name: "compacted layers deleted",
+			layers: []backuppb.BackupManifest{
+				layer(0, 1, false), layer(1, 3, true), layer(3, 4, false),
+			},
+			expected: []int{0, 1, 2},
+		},
+		{
+			name: "compacted layers present",
+			layers: []backuppb.BackupManifest{
+				layer(0, 1, false), layer(1, 2, false), layer(2, 3, false), layer(1, 3, true),
+				layer(3, 4, false),
+			},
+			expected: []int{0, 3, 4},
+		},
+		{
+			name: "compaction of a compacted layer",
+			layers: []backuppb.BackupManifest{
+				layer(0, 1, false), layer(1, 3, true), layer(1, 4, true), layer(3, 4, false),
+			},
+			expected: []int{0, 2},
+		},
+		{
+			name: "repeated compaction",
+			layers: []backuppb.BackupManifest{
+				layer(0, 1, false), layer(1, 2, false), layer(1, 2, true), layer(1, 2, true),
+			},
+			expected: []int{0, 3},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			uris := make([]string, len(tc.layers))
+			info := make([]jobspb.RestoreDetails_BackupLocalityInfo, len(tc.layers))
+			for i := range tc.layers {
+				uris[i] = fmt.Sprintf("nodelocal://1/%d", i)
+			}
+			gotURIs, gotLayers, gotInfo := backupinfo.ElideCompactedLayers(uris, tc.layers, info)
+			require.Len(t, gotLayers, len(tc.expected))
+			require.Len(t, gotInfo, len(tc.expected))
+			for i, idx := range tc.expected {
+				require.Equal(t, uris[idx], gotURIs[i])
+				require.Equal(t, tc.layers[idx], gotLayers[i])
+			}
+		})
+	}
+}
+
 func makeMockManifest(
 	numFiles int, numDescriptors int, changesPerDescriptor int,
 ) backuppb.BackupManifest {
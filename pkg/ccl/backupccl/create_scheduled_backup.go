@@ -11,6 +11,7 @@ package backupccl
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
@@ -45,6 +46,7 @@ const (
 	optOnPreviousRunning       = "on_previous_running"
 	optIgnoreExistingBackups   = "ignore_existing_backups"
 	optUpdatesLastBackupMetric = "updates_cluster_last_backup_time_metric"
+	optCompactionThreshold     = "compaction_threshold"
 )
 
 var scheduledBackupOptionExpectValues = map[string]exprutil.KVStringOptValidate{
@@ -53,6 +55,7 @@ var scheduledBackupOptionExpectValues = map[string]exprutil.KVStringOptValidate{
 	optOnPreviousRunning:       exprutil.KVStringOptRequireValue,
 	optIgnoreExistingBackups:   exprutil.KVStringOptRequireNoValue,
 	optUpdatesLastBackupMetric: exprutil.KVStringOptRequireNoValue,
+	optCompactionThreshold:     exprutil.KVStringOptRequireValue,
 }
 
 // scheduledBackupGCProtectionEnabled is used to enable and disable the chaining
@@ -107,6 +110,20 @@ func makeScheduleDetails(opts map[string]string) (jobspb.ScheduleDetails, error)
 	return details, nil
 }
 
+// parseCompactionThreshold returns the number of incremental layers at which
+// an incremental schedule compacts them, or 0 if the option is not set.
+func parseCompactionThreshold(opts map[string]string) (int64, error) {
+	v, ok := opts[optCompactionThreshold]
+	if !ok {
+		return 0, nil
+	}
+	threshold, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || threshold < 2 {
+		return 0, errors.Newf("%s must be an integer greater than 1, got %q", optCompactionThreshold, v)
+	}
+	return threshold, nil
+}
+
 func scheduleFirstRun(evalCtx *eval.Context, opts map[string]string) (*time.Time, error) {
 	if v, ok := opts[optFirstRun]; ok {
 		firstRun, _, err := tree.ParseDTimestampTZ(evalCtx, v, time.Microsecond)
@@ -297,6 +314,14 @@ func doCreateBackupSchedules(
 		}
 	}
 
+	compactionThreshold, err := parseCompactionThreshold(scheduleOptions)
+	if err != nil {
+		return err
+	}
+	if compactionThreshold > 0 && incRecurrence == nil {
+		return errors.Newf("%s requires an incremental backup schedule", optCompactionThreshold)
+	}
+
 	evalCtx := &p.ExtendedEvalContext().Context
 	firstRun, err := scheduleFirstRun(evalCtx, scheduleOptions)
 	if err != nil {
@@ -344,7 +369,7 @@ func doCreateBackupSchedules(
 		}
 		inc, incScheduledBackupArgs, err = makeBackupSchedule(
 			env, p.User(), scheduleLabel, incRecurrence, incrementalScheduleDetails, unpauseOnSuccessID,
-			updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords)
+			updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords, compactionThreshold)
 		if err != nil {
 			return err
 		}
@@ -368,7 +393,7 @@ func doCreateBackupSchedules(
 	var fullScheduledBackupArgs *backuppb.ScheduledBackupExecutionArgs
 	full, fullScheduledBackupArgs, err := makeBackupSchedule(
 		env, p.User(), scheduleLabel, fullRecurrence, details, unpauseOnSuccessID,
-		updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords)
+		updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords, 0 /* compactionThreshold */)
 	if err != nil {
 		return err
 	}
@@ -475,6 +500,7 @@ func makeBackupSchedule(
 	updateLastMetricOnSuccess bool,
 	backupNode *tree.Backup,
 	chainProtectedTimestampRecords bool,
+	compactionThreshold int64,
 ) (*jobs.ScheduledJob, *backuppb.ScheduledBackupExecutionArgs, error) {
 	sj := jobs.NewScheduledJob(env)
 	sj.SetScheduleLabel(label)
@@ -485,6 +511,7 @@ func makeBackupSchedule(
 		UnpauseOnSuccess:               unpauseOnSuccess,
 		UpdatesLastBackupMetric:        updateLastMetricOnSuccess,
 		ChainProtectedTimestampRecords: chainProtectedTimestampRecords,
+		CompactionThreshold:            compactionThreshold,
 	}
 	if backupNode.AppendToLatest {
 		args.BackupType = backuppb.ScheduledBackupExecutionArgs_INCREMENTAL
@@ -0,0 +1,288 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/keyside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// applyRestoreTableOverrides modifies a table descriptor read from the backup
+// according to the into_table restore option. It is applied both during
+// planning and in the job, since the job re-reads the descriptors from the
+// backup.
+func applyRestoreTableOverrides(table *tabledesc.Mutable, intoTable string) {
+	if intoTable != "" {
+		table.SetName(intoTable)
+	}
+}
+
+// restoreFilterSpans returns the spans of the primary index of the table, in
+// the keyspace of the backup, which contain the rows that satisfy the filter.
+//
+// The filter must be a predicate over the leading column of the primary key
+// of the table made up of comparisons with constants (=, <, <=, >, >=, IN and
+// BETWEEN) combined with AND and OR.
+//
+// Only the primary index is restricted, so tables with secondary indexes are
+// rejected: the entries of their secondary indexes in the restored spans would
+// not match the restored rows, and nothing would rebuild them.
+func restoreFilterSpans(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *eval.Context,
+	codec keys.SQLCodec,
+	table catalog.TableDescriptor,
+	filter string,
+) (roachpb.Spans, error) {
+	if !table.IsPhysicalTable() {
+		return nil, errors.Errorf("cannot use %q option with non-physical table %q",
+			restoreOptFilter, table.GetName())
+	}
+	if len(table.AllMutations()) > 0 {
+		return nil, errors.Errorf("cannot use %q option with table %q which has in-progress schema changes",
+			restoreOptFilter, table.GetName())
+	}
+	if idxs := table.PublicNonPrimaryIndexes(); len(idxs) > 0 {
+		return nil, errors.WithHintf(
+			pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot use %q option with table %q which has secondary index %q",
+				restoreOptFilter, table.GetName(), idxs[0].GetName()),
+			"restore the whole table with into_table, or drop the secondary indexes before taking the backup")
+	}
+	expr, err := parser.ParseExpr(filter)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %q option", restoreOptFilter)
+	}
+	idx := table.GetPrimaryIndex()
+	dir, err := catalogkeys.IndexColumnEncodingDirection(idx.GetKeyColumnDirection(0))
+	if err != nil {
+		return nil, err
+	}
+	col, err := catalog.MustFindColumnByID(table, idx.GetKeyColumnID(0))
+	if err != nil {
+		return nil, err
+	}
+	f := restoreFilter{
+		semaCtx:   semaCtx,
+		evalCtx:   evalCtx,
+		col:       col,
+		dir:       dir,
+		indexSpan: table.IndexSpan(codec, idx.GetID()),
+	}
+	if err := f.checkColumnRefs(expr); err != nil {
+		return nil, err
+	}
+	spans, err := f.spansForExpr(ctx, expr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%q option", restoreOptFilter)
+	}
+	return spans, nil
+}
+
+// restoreFilter converts a filter over the leading primary key column of a
+// table into spans of its primary index.
+type restoreFilter struct {
+	semaCtx   *tree.SemaContext
+	evalCtx   *eval.Context
+	col       catalog.Column
+	dir       encoding.Direction
+	indexSpan roachpb.Span
+}
+
+func (f *restoreFilter) spansForExpr(ctx context.Context, expr tree.Expr) (roachpb.Spans, error) {
+	switch e := expr.(type) {
+	case *tree.ParenExpr:
+		return f.spansForExpr(ctx, e.Expr)
+	case *tree.AndExpr:
+		left, err := f.spansForExpr(ctx, e.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := f.spansForExpr(ctx, e.Right)
+		if err != nil {
+			return nil, err
+		}
+		return intersectSpans(left, right), nil
+	case *tree.OrExpr:
+		left, err := f.spansForExpr(ctx, e.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := f.spansForExpr(ctx, e.Right)
+		if err != nil {
+			return nil, err
+		}
+		merged := append(append([]roachpb.Span(nil), left...), right...)
+		merged, _ = roachpb.MergeSpans(&merged)
+		return merged, nil
+	case *tree.RangeCond:
+		if e.Not || e.Symmetric || !f.isColumn(e.Left) {
+			break
+		}
+		from, err := f.spanForComparison(ctx, treecmp.GE, e.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := f.spanForComparison(ctx, treecmp.LE, e.To)
+		if err != nil {
+			return nil, err
+		}
+		return intersectSpans(from, to), nil
+	case *tree.ComparisonExpr:
+		op, val := e.Operator.Symbol, e.Right
+		if !f.isColumn(e.Left) {
+			if !f.isColumn(e.Right) {
+				break
+			}
+			// Flip the comparison so that the column is on the left.
+			val = e.Left
+			switch op {
+			case treecmp.LT:
+				op = treecmp.GT
+			case treecmp.LE:
+				op = treecmp.GE
+			case treecmp.GT:
+				op = treecmp.LT
+			case treecmp.GE:
+				op = treecmp.LE
+			case treecmp.In:
+				return nil, errors.Errorf("unsupported filter expression: %s", e)
+			}
+		}
+		if op == treecmp.In {
+			tuple, ok := val.(*tree.Tuple)
+			if !ok {
+				break
+			}
+			var spans []roachpb.Span
+			for _, v := range tuple.Exprs {
+				sp, err := f.spanForComparison(ctx, treecmp.EQ, v)
+				if err != nil {
+					return nil, err
+				}
+				spans = append(spans, sp...)
+			}
+			spans, _ = roachpb.MergeSpans(&spans)
+			return spans, nil
+		}
+		return f.spanForComparison(ctx, op, val)
+	}
+	return nil, errors.Errorf("unsupported filter expression: %s; only comparisons of "+
+		"column %q with constants are supported", expr, f.col.GetName())
+}
+
+// checkColumnRefs returns an error if the expression references any column
+// other than the leading primary key column, since the filter can only be
+// applied to the keys of the primary index.
+func (f *restoreFilter) checkColumnRefs(expr tree.Expr) error {
+	_, err := tree.SimpleVisit(expr, func(e tree.Expr) (bool, tree.Expr, error) {
+		name, ok := e.(*tree.UnresolvedName)
+		if !ok || f.isColumn(name) {
+			return true, e, nil
+		}
+		return false, nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"%q option may only reference the leading primary key column %q, found %s",
+			restoreOptFilter, f.col.GetName(), name)
+	})
+	return err
+}
+
+// isColumn returns whether the expression is a reference to the leading
+// primary key column.
+func (f *restoreFilter) isColumn(expr tree.Expr) bool {
+	name, ok := expr.(*tree.UnresolvedName)
+	return ok && name.NumParts == 1 && name.Parts[0] == f.col.GetName()
+}
+
+// spanForComparison returns the span of the primary index containing the rows
+// for which "col op val" holds.
+func (f *restoreFilter) spanForComparison(
+	ctx context.Context, op treecmp.ComparisonOperatorSymbol, val tree.Expr,
+) (roachpb.Spans, error) {
+	typed, err := tree.TypeCheckAndRequire(ctx, val, f.semaCtx, f.col.GetType(), restoreOptFilter)
+	if err != nil {
+		return nil, err
+	}
+	d, err := eval.Expr(ctx, f.evalCtx, typed)
+	if err != nil {
+		return nil, err
+	}
+	if d == tree.DNull {
+		// Comparisons with NULL never hold, and primary key columns can't be NULL.
+		return nil, nil
+	}
+	key, err := keyside.Encode(f.indexSpan.Key.Clone(), d, f.dir)
+	if err != nil {
+		return nil, err
+	}
+	keyEnd := key.PrefixEnd()
+
+	// For descending columns, larger values sort first in the index.
+	if f.dir == encoding.Descending {
+		switch op {
+		case treecmp.LT:
+			op = treecmp.GT
+		case treecmp.LE:
+			op = treecmp.GE
+		case treecmp.GT:
+			op = treecmp.LT
+		case treecmp.GE:
+			op = treecmp.LE
+		}
+	}
+
+	var sp roachpb.Span
+	switch op {
+	case treecmp.EQ:
+		sp = roachpb.Span{Key: key, EndKey: keyEnd}
+	case treecmp.LT:
+		sp = roachpb.Span{Key: f.indexSpan.Key, EndKey: key}
+	case treecmp.LE:
+		sp = roachpb.Span{Key: f.indexSpan.Key, EndKey: keyEnd}
+	case treecmp.GT:
+		sp = roachpb.Span{Key: keyEnd, EndKey: f.indexSpan.EndKey}
+	case treecmp.GE:
+		sp = roachpb.Span{Key: key, EndKey: f.indexSpan.EndKey}
+	default:
+		return nil, errors.Errorf("unsupported comparison operator %s", op)
+	}
+	if !sp.Valid() {
+		return nil, nil
+	}
+	return roachpb.Spans{sp}, nil
+}
+
+// intersectSpans returns the merged set of spans covered by both a and b.
+func intersectSpans(a, b []roachpb.Span) []roachpb.Span {
+	var res []roachpb.Span
+	for _, x := range a {
+		for _, y := range b {
+			if sp := x.Intersect(y); sp.Valid() {
+				res = append(res, sp)
+			}
+		}
+	}
+	res, _ = roachpb.MergeSpans(&res)
+	return res
+}
@@ -0,0 +1,166 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreFilterSpans(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	semaCtx := tree.MakeSemaContext()
+	evalCtx := eval.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	codec := keys.SystemSQLCodec
+
+	makeTable := func(dir catenumpb.IndexColumn_Direction) *tabledesc.Mutable {
+		return tabledesc.NewBuilder(&descpb.TableDescriptor{
+			ID:       100,
+			ParentID: 50,
+			Name:     "t",
+			Columns: []descpb.ColumnDescriptor{
+				{ID: 1, Name: "id", Type: types.Int},
+				{ID: 2, Name: "v", Type: types.String, Nullable: true},
+			},
+			NextColumnID: 3,
+			Families: []descpb.ColumnFamilyDescriptor{
+				{ID: 0, Name: "primary", ColumnIDs: []descpb.ColumnID{1, 2}, ColumnNames: []string{"id", "v"}},
+			},
+			PrimaryIndex: descpb.IndexDescriptor{
+				ID:                  1,
+				Name:                "t_pkey",
+				Unique:              true,
+				KeyColumnIDs:        []descpb.ColumnID{1},
+				KeyColumnNames:      []string{"id"},
+				KeyColumnDirections: []catenumpb.IndexColumn_Direction{dir},
+			},
+			NextIndexID: 2,
+		}).BuildCreatedMutableTable()
+	}
+
+	prefix := codec.IndexPrefix(100, 1)
+	asc := func(i int64) roachpb.Key {
+		return encoding.EncodeVarintAscending(prefix.Clone(), i)
+	}
+	desc := func(i int64) roachpb.Key {
+		return encoding.EncodeVarintDescending(prefix.Clone(), i)
+	}
+	indexEnd := prefix.PrefixEnd()
+
+	for _, tc := range []struct {
+		filter   string
+		dir      catenumpb.IndexColumn_Direction
+		expected roachpb.Spans
+		err      string
+	}{
+		{
+			filter:   "id = 5",
+			expected: roachpb.Spans{{Key: asc(5), EndKey: asc(5).PrefixEnd()}},
+		},
+		{
+			filter:   "id BETWEEN 10 AND 20",
+			expected: roachpb.Spans{{Key: asc(10), EndKey: asc(20).PrefixEnd()}},
+		},
+		{
+			filter:   "5 > id",
+			expected: roachpb.Spans{{Key: prefix, EndKey: asc(5)}},
+		},
+		{
+			filter: "id < 3 OR (id > 7 AND id <= 9)",
+			expected: roachpb.Spans{
+				{Key: prefix, EndKey: asc(3)},
+				{Key: asc(7).PrefixEnd(), EndKey: asc(9).PrefixEnd()},
+			},
+		},
+		{
+			filter:   "id IN (1, 2)",
+			expected: roachpb.Spans{{Key: asc(1), EndKey: asc(2).PrefixEnd()}},
+		},
+		{
+			filter:   "id > 5 AND id < 3",
+			expected: nil,
+		},
+		{
+			filter:   "id >= 10",
+			dir:      catenumpb.IndexColumn_DESC,
+			expected: roachpb.Spans{{Key: prefix, EndKey: desc(10).PrefixEnd()}},
+		},
+		{
+			filter:   "id < 10",
+			dir:      catenumpb.IndexColumn_DESC,
+			expected: roachpb.Spans{{Key: desc(10).PrefixEnd(), EndKey: indexEnd}},
+		},
+		{
+			filter: "v = 'a'",
+			err:    `"filter" option may only reference the leading primary key column "id", found v`,
+		},
+		{
+			filter: "id = 1 OR length(v) > 2",
+			err:    `"filter" option may only reference the leading primary key column "id", found v`,
+		},
+		{
+			filter: "id + 1 = 2",
+			err:    "unsupported filter expression",
+		},
+		{
+			filter: "id = 'a'",
+			err:    "could not parse",
+		},
+	} {
+		t.Run(tc.filter, func(t *testing.T) {
+			spans, err := restoreFilterSpans(ctx, &semaCtx, &evalCtx, codec, makeTable(tc.dir), tc.filter)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, spans)
+		})
+	}
+
+	t.Run("secondary index", func(t *testing.T) {
+		// The secondary index would not match the restored rows.
+		table := makeTable(catenumpb.IndexColumn_ASC)
+		table.Indexes = []descpb.IndexDescriptor{{
+			ID:                  2,
+			Name:                "t_v_idx",
+			KeyColumnIDs:        []descpb.ColumnID{2},
+			KeyColumnNames:      []string{"v"},
+			KeyColumnDirections: []catenumpb.IndexColumn_Direction{catenumpb.IndexColumn_ASC},
+		}}
+		table.NextIndexID = 3
+		imm := table.ImmutableCopy().(catalog.TableDescriptor)
+		_, err := restoreFilterSpans(ctx, &semaCtx, &evalCtx, codec, imm, "id = 5")
+		require.ErrorContains(t, err, `cannot use "filter" option with table "t" which has secondary index "t_v_idx"`)
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		table := makeTable(catenumpb.IndexColumn_ASC)
+		applyRestoreTableOverrides(table, "t_recovered")
+		require.Equal(t, "t_recovered", table.GetName())
+	})
+}
@@ -55,6 +55,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scbackup"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
@@ -65,7 +67,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
 	bulkutil "github.com/cockroachdb/cockroach/pkg/util/bulk"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
-	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/interval"
@@ -869,6 +870,7 @@ func createImportingDescriptors(
 		switch desc := desc.(type) {
 		case catalog.TableDescriptor:
 			mut := tabledesc.NewBuilder(desc.TableDesc()).BuildCreatedMutableTable()
+			applyRestoreTableOverrides(mut, details.IntoTable)
 			if shouldPreRestore(mut) {
 				preRestoreTables = append(preRestoreTables, mut)
 			} else {
@@ -911,6 +913,11 @@ func createImportingDescriptors(
 	// that is, in the 'old' keyspace, before we reassign the table IDs.
 	preRestoreSpans := spansForAllRestoreTableIndexes(backupCodec, preRestoreTables, nil, details.SchemaOnly)
 	postRestoreSpans := spansForAllRestoreTableIndexes(backupCodec, postRestoreTables, nil, details.SchemaOnly)
+	if len(details.RestrictSpans) > 0 {
+		// Only restore the rows of the primary index selected by the filter
+		// option.
+		postRestoreSpans = intersectSpans(postRestoreSpans, details.RestrictSpans)
+	}
 	var verifySpans []roachpb.Span
 	if details.VerifyData {
 		// verifySpans contains the spans that should be read and checksum'd during a
@@ -1877,8 +1884,17 @@ func (r *restoreResumer) doResume(ctx context.Context, execCtx interface{}) erro
 		if err := r.cleanupTempSystemTables(ctx); err != nil {
 			return err
 		}
-	} else if isSystemUserRestore(details) {
-		if err := r.restoreSystemUsers(ctx, p.ExecCfg().InternalDB, mainData.systemTables); err != nil {
+	} else if isSystemTargetRestore(details) {
+		var err error
+		switch {
+		case isSystemUserRestore(details):
+			err = r.restoreSystemUsers(ctx, p.ExecCfg().InternalDB, mainData.systemTables)
+		case details.DescriptorCoverage == tree.SystemSettings:
+			err = r.restoreSystemSettings(ctx, p.ExecCfg().InternalDB)
+		case details.DescriptorCoverage == tree.SystemZoneConfigs:
+			err = r.restoreSystemZoneConfigs(ctx, p.ExecCfg().InternalDB)
+		}
+		if err != nil {
 			return err
 		}
 		details = r.job.Details().(jobspb.RestoreDetails)
@@ -1971,6 +1987,13 @@ func isSystemUserRestore(details jobspb.RestoreDetails) bool {
 	return details.DescriptorCoverage == tree.SystemUsers || details.RestoreSystemUsers
 }
 
+// isSystemTargetRestore checks if the user called RESTORE SYSTEM USERS,
+// SETTINGS or ZONE CONFIGURATIONS, all of which restore system tables into a
+// temporary system database and then copy rows from it.
+func isSystemTargetRestore(details jobspb.RestoreDetails) bool {
+	return details.DescriptorCoverage.IsSystemTarget() || isSystemUserRestore(details)
+}
+
 func revalidateIndexes(
 	ctx context.Context,
 	execCfg *sql.ExecutorConfig,
@@ -2093,7 +2116,7 @@ func (r *restoreResumer) notifyStatsRefresherOfNewTables() {
 func tempSystemDatabaseID(
 	details jobspb.RestoreDetails, tables []catalog.TableDescriptor,
 ) descpb.ID {
-	if details.DescriptorCoverage != tree.AllDescriptors && !isSystemUserRestore(details) {
+	if details.DescriptorCoverage != tree.AllDescriptors && !isSystemTargetRestore(details) {
 		return descpb.InvalidID
 	}
 
@@ -3000,6 +3023,11 @@ type systemTableNameWithConfig struct {
 // Restore system.users from the backup into the restoring cluster. Only recreate users
 // which are in a backup of system.users but do not currently exist (ignoring those who do)
 // and re-grant roles for users if the backup has system.role_members.
+//
+// The role memberships, role options, system privileges and default session
+// settings in the backup are only restored for the users which are recreated.
+// Users which already exist in the cluster are left unchanged, even if their
+// grants or settings differ from those in the backup.
 func (r *restoreResumer) restoreSystemUsers(
 	ctx context.Context, db isql.DB, systemTables []catalog.TableDescriptor,
 ) error {
@@ -3084,10 +3112,103 @@ VALUES ($1, $2, $3, (SELECT user_id FROM system.users WHERE username = $1), (SEL
 			}
 		}
 
+		if hasSystemTableByName(systemschema.SystemPrivilegeTable.GetName(), systemTables) {
+			selectNonExistentPrivileges := "SELECT username, path, privileges, grant_options FROM crdb_temp_system.privileges temp_p " +
+				"WHERE NOT EXISTS (SELECT * FROM system.privileges p WHERE temp_p.username = p.username AND temp_p.path = p.path)"
+			privileges, err := txn.QueryBuffered(ctx, "get-privileges", txn.KV(), selectNonExistentPrivileges)
+			if err != nil {
+				return err
+			}
+
+			privilegesHasUserIDColumn := r.execCfg.Settings.Version.IsActive(ctx, clusterversion.V23_1SystemPrivilegesTableHasUserIDColumn)
+			insertPrivilege := `INSERT INTO system.privileges (username, path, privileges, grant_options, user_id) VALUES ($1, $2, $3, $4, $5)`
+			if !privilegesHasUserIDColumn {
+				insertPrivilege = `INSERT INTO system.privileges (username, path, privileges, grant_options) VALUES ($1, $2, $3, $4)`
+			}
+			for _, privilege := range privileges {
+				// Only grant system privileges to the new users we just added.
+				if roleID, ok := newUsernames[privilege[0].String()]; ok {
+					args := []interface{}{privilege[0], privilege[1], privilege[2], privilege[3]}
+					if privilegesHasUserIDColumn {
+						args = append(args, roleID)
+					}
+					if _, err = txn.Exec(ctx, "insert-non-existent-privileges", txn.KV(),
+						insertPrivilege, args...); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		if hasSystemTableByName(systemschema.DatabaseRoleSettingsTable.GetName(), systemTables) {
+			// Only the defaults which apply to all databases are restored, as the
+			// IDs of the databases in the backup have no meaning in this cluster.
+			selectRoleSettings := "SELECT role_name, settings FROM crdb_temp_system.database_role_settings " +
+				"WHERE database_id = 0"
+			roleSettings, err := txn.QueryBuffered(ctx, "get-database-role-settings", txn.KV(), selectRoleSettings)
+			if err != nil {
+				return err
+			}
+
+			roleSettingsHasRoleIDColumn := r.execCfg.Settings.Version.IsActive(ctx, clusterversion.V23_1DatabaseRoleSettingsHasRoleIDColumn)
+			insertRoleSettings := `INSERT INTO system.database_role_settings (database_id, role_name, settings, role_id) VALUES (0, $1, $2, $3)`
+			if !roleSettingsHasRoleIDColumn {
+				insertRoleSettings = `INSERT INTO system.database_role_settings (database_id, role_name, settings) VALUES (0, $1, $2)`
+			}
+			for _, roleSetting := range roleSettings {
+				if roleID, ok := newUsernames[roleSetting[0].String()]; ok {
+					args := []interface{}{roleSetting[0], roleSetting[1]}
+					if roleSettingsHasRoleIDColumn {
+						args = append(args, roleID)
+					}
+					if _, err = txn.Exec(ctx, "insert-database-role-settings", txn.KV(),
+						insertRoleSettings, args...); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
 		return nil
 	})
 }
 
+// systemSettingsExcludedFromRestore are the cluster settings which RESTORE
+// SYSTEM SETTINGS never restores: the cluster version and the settings which
+// control upgrades, and those which identify the cluster or its license.
+var systemSettingsExcludedFromRestore = []string{
+	"version",
+	"cluster.preserve_downgrade_option",
+	"cluster.organization",
+	"cluster.secret",
+	"enterprise.license",
+}
+
+// restoreSystemSettings restores the cluster settings in a backup of
+// system.settings, overwriting the current value of any setting which is also
+// set in the backup, except for systemSettingsExcludedFromRestore.
+func (r *restoreResumer) restoreSystemSettings(ctx context.Context, db isql.DB) error {
+	return db.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		_, err := txn.Exec(ctx, "restore-system-settings", txn.KV(),
+			`UPSERT INTO system.settings SELECT * FROM crdb_temp_system.settings WHERE name <> ALL ($1)`,
+			systemSettingsExcludedFromRestore)
+		return err
+	})
+}
+
+// restoreSystemZoneConfigs restores the zone configurations in a backup of
+// system.zones. Only the zone configurations of the cluster's ranges and
+// system tables, whose IDs are the same in every cluster, are restored;
+// zone configurations of user databases and tables are restored with them.
+func (r *restoreResumer) restoreSystemZoneConfigs(ctx context.Context, db isql.DB) error {
+	return db.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		_, err := txn.Exec(ctx, "restore-system-zone-configs", txn.KV(),
+			`UPSERT INTO system.zones SELECT * FROM crdb_temp_system.zones WHERE id <= $1`,
+			keys.MaxReservedDescID)
+		return err
+	})
+}
+
 func hasSystemRoleMembersTable(systemTables []catalog.TableDescriptor) bool {
 	return hasSystemTableByName(systemschema.RoleMembersTable.GetName(), systemTables)
 }
@@ -3221,11 +3342,66 @@ func (r *restoreResumer) cleanupTempSystemTables(ctx context.Context) error {
 	return nil
 }
 
-var onlineRestoreGate = envutil.EnvOrDefaultBool("COCKROACH_UNSAFE_RESTORE", false)
+var onlineRestoreEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"bulkio.restore.online.experimental.enabled",
+	"experimental: if set to true, RESTORE ... WITH EXPERIMENTAL DEFERRED COPY links the backup's "+
+		"files into the restored ranges and brings the restored tables online before their data "+
+		"is downloaded",
+	false,
+)
+
+var onlineRestoreEagerDownload = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"bulkio.restore.online.eager_download.enabled",
+	"if set to true, the data of an online restore is downloaded in the background after the "+
+		"restored tables come online; otherwise it is only downloaded as it is compacted",
+	true,
+)
 
-// sendAddRemoteSSTs is a stubbed out, very simplisitic version of restore used
-// to test out ingesting "remote" SSTs. It will be replaced with a real distsql
-// plan and processors in the future.
+// checkOnlineRestoreSupported returns an error if the restore cannot be run
+// as an online restore, which links the files of the backup into the restored
+// ranges rather than ingesting their contents.
+func checkOnlineRestoreSupported(
+	p sql.PlanHookState,
+	restoreStmt *tree.Restore,
+	mainBackupManifests []backuppb.BackupManifest,
+	encryption *jobspb.BackupEncryptionOptions,
+	endTime hlc.Timestamp,
+) error {
+	if !onlineRestoreEnabled.Get(&p.ExecCfg().Settings.SV) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"EXPERIMENTAL DEFERRED COPY requires the %s cluster setting", onlineRestoreEnabled.Name())
+	}
+	if !p.ExecCfg().Codec.ForSystemTenant() {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"EXPERIMENTAL DEFERRED COPY is not supported in virtual clusters")
+	}
+	if restoreStmt.DescriptorCoverage != tree.RequestedDescriptors {
+		return errors.New("EXPERIMENTAL DEFERRED COPY cannot be used for cluster or system restores")
+	}
+	if encryption != nil {
+		return errors.New("EXPERIMENTAL DEFERRED COPY cannot be used with encrypted backups")
+	}
+	if len(mainBackupManifests) > 1 {
+		return errors.New("EXPERIMENTAL DEFERRED COPY can only restore data from a full backup")
+	}
+	if !endTime.IsEmpty() && !endTime.Equal(mainBackupManifests[0].EndTime) {
+		return errors.New("EXPERIMENTAL DEFERRED COPY cannot restore to a time before the end of the backup")
+	}
+	if useSimpleImportSpans.Get(&p.ExecCfg().Settings.SV) {
+		return errors.Newf("EXPERIMENTAL DEFERRED COPY cannot be used with %s", useSimpleImportSpans.Name())
+	}
+	return nil
+}
+
+// sendAddRemoteSSTs runs the link phase of an online restore from the
+// coordinator: rather than ingesting the contents of the backup's files, it
+// links each file into the restored ranges with an AddSSTable request that
+// references the file in external storage, splitting and scattering ranges as
+// they fill up. It then creates the job which downloads the linked data in the
+// background. The restore was validated by checkOnlineRestoreSupported during
+// planning, so the remaining checks here are assertions.
 func sendAddRemoteSSTs(
 	ctx context.Context,
 	execCtx sql.JobExecContext,
@@ -3244,10 +3420,6 @@ func sendAddRemoteSSTs(
 ) error {
 	defer close(progCh)
 
-	if !onlineRestoreGate {
-		return errors.AssertionFailedf("experimental restore mode not supported")
-	}
-
 	if encryption != nil {
 		return errors.AssertionFailedf("encryption not supported with online restore")
 	}
@@ -3453,6 +3625,14 @@ func (r *restoreResumer) doDownloadFiles(ctx context.Context, execCtx sql.JobExe
 		}
 	}
 
+	// Ask the stores holding the restored spans to rewrite the linked files
+	// into local ones, rather than waiting for them to be compacted away.
+	if onlineRestoreEagerDownload.Get(&execCtx.ExecCfg().Settings.SV) {
+		if err := requestDownloads(ctx, execCtx.ExecCfg(), details.DownloadSpans); err != nil {
+			return err
+		}
+	}
+
 	var lastProgressUpdate time.Time
 	for rt := retry.StartWithCtx(
 		ctx, retry.Options{InitialBackoff: time.Second * 10, Multiplier: 1.2, MaxBackoff: time.Minute * 5},
@@ -3491,6 +3671,54 @@ func (r *restoreResumer) doDownloadFiles(ctx context.Context, execCtx sql.JobExe
 	}
 }
 
+// requestDownloads starts, in the background, a compaction of the given spans
+// on every store with a replica of a range which overlaps them. Compacting a
+// span rewrites the external files linked into it as local files, downloading
+// their data. Failures are logged rather than returned since the linked data
+// remains readable and will be downloaded by later compactions regardless.
+func requestDownloads(ctx context.Context, execCfg *sql.ExecutorConfig, spans []roachpb.Span) error {
+	type storeSpans struct {
+		nodeID roachpb.NodeID
+		spans  []roachpb.Span
+	}
+	byStore := make(map[roachpb.StoreID]*storeSpans)
+	for _, span := range spans {
+		it, err := execCfg.RangeDescIteratorFactory.NewIterator(ctx, span)
+		if err != nil {
+			return err
+		}
+		for ; it.Valid(); it.Next() {
+			desc := it.CurRangeDescriptor()
+			sp := desc.RSpan().AsRawSpanWithNoLocals().Intersect(span)
+			for _, replica := range desc.Replicas().Descriptors() {
+				s, ok := byStore[replica.StoreID]
+				if !ok {
+					s = &storeSpans{nodeID: replica.NodeID}
+					byStore[replica.StoreID] = s
+				}
+				s.spans = append(s.spans, sp)
+			}
+		}
+	}
+
+	log.Infof(ctx, "requesting download of %d spans on %d stores", len(spans), len(byStore))
+	for storeID, s := range byStore {
+		storeID, s := storeID, s
+		if err := execCfg.DistSQLSrv.Stopper.RunAsyncTask(ctx, "restore-download", func(ctx context.Context) {
+			for _, sp := range s.spans {
+				if err := execCfg.CompactEngineSpanFunc(
+					ctx, int32(s.nodeID), int32(storeID), sp.Key, sp.EndKey,
+				); err != nil {
+					log.Warningf(ctx, "failed to download span %s on n%d,s%d: %v", sp, s.nodeID, storeID, err)
+				}
+			}
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type sz int64
 
 func (b sz) String() string { return string(humanizeutil.IBytes(int64(b))) }
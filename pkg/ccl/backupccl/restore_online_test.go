@@ -0,0 +1,70 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/testutils/jobutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnlineRestoreBasic(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 1000
+	params := base.TestClusterArgs{
+		ServerArgs: base.TestServerArgs{
+			DefaultTestTenant: base.TODOTestTenantDisabled,
+		},
+	}
+	_, sqlDB, dir, cleanupFn := backupRestoreTestSetupWithParams(t, singleNode, numAccounts, InitManualReplication, params)
+	defer cleanupFn()
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, localFoo)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1 WITH encryption_passphrase = 'abc'`, localFoo+"/enc")
+
+	_, rSQLDB, cleanupFnRestored := backupRestoreTestSetupEmpty(t, singleNode, dir, InitManualReplication, params)
+	defer cleanupFnRestored()
+
+	rSQLDB.ExpectErr(t, "requires the bulkio.restore.online.experimental.enabled cluster setting",
+		`RESTORE DATABASE data FROM LATEST IN $1 WITH EXPERIMENTAL DEFERRED COPY`, localFoo)
+
+	rSQLDB.Exec(t, `SET CLUSTER SETTING bulkio.restore.online.experimental.enabled = true`)
+	rSQLDB.ExpectErr(t, "cannot be used with encrypted backups",
+		`RESTORE DATABASE data FROM LATEST IN $1 WITH EXPERIMENTAL DEFERRED COPY, encryption_passphrase = 'abc'`,
+		localFoo+"/enc")
+	rSQLDB.ExpectErr(t, "cannot be used for cluster or system restores",
+		`RESTORE FROM LATEST IN $1 WITH EXPERIMENTAL DEFERRED COPY`, localFoo)
+
+	rSQLDB.Exec(t, `RESTORE DATABASE data FROM LATEST IN $1 WITH EXPERIMENTAL DEFERRED COPY`, localFoo)
+
+	// The restored table is readable as soon as the restore job completes,
+	// before its data has been downloaded.
+	require.Equal(t,
+		sqlDB.QueryStr(t, `SHOW EXPERIMENTAL_FINGERPRINTS FROM TABLE data.bank`),
+		rSQLDB.QueryStr(t, `SHOW EXPERIMENTAL_FINGERPRINTS FROM TABLE data.bank`))
+
+	var downloadJobID jobspb.JobID
+	rSQLDB.QueryRow(t,
+		`SELECT job_id FROM [SHOW JOBS] WHERE description LIKE 'Background Data Download%'`,
+	).Scan(&downloadJobID)
+	jobutils.WaitForJobToSucceed(t, rSQLDB, downloadJobID)
+
+	// The downloaded table serves the same reads and writes as the original.
+	rSQLDB.Exec(t, `UPDATE data.bank SET balance = balance + 1 WHERE id < 10`)
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1 WHERE id < 10`)
+	require.Equal(t,
+		sqlDB.QueryStr(t, `SELECT * FROM data.bank ORDER BY id`),
+		rSQLDB.QueryStr(t, `SELECT * FROM data.bank ORDER BY id`))
+}
@@ -72,6 +72,8 @@ import (
 
 const (
 	restoreOptIntoDB                    = "into_db"
+	restoreOptIntoTable                 = "into_table"
+	restoreOptFilter                    = "filter"
 	restoreOptSkipMissingFKs            = "skip_missing_foreign_keys"
 	restoreOptSkipMissingSequences      = "skip_missing_sequences"
 	restoreOptSkipMissingUDFs           = "skip_missing_udfs"
@@ -820,7 +822,7 @@ func allocateDescriptorRewrites(
 		}
 	}
 
-	if descriptorCoverage == tree.AllDescriptors || descriptorCoverage == tree.SystemUsers {
+	if descriptorCoverage == tree.AllDescriptors || descriptorCoverage.IsSystemTarget() {
 		// Increment the DescIDSequenceKey so that it is higher than both the max desc ID
 		// in the backup and current max desc ID in the restoring cluster. This generator
 		// keeps produced the next descriptor ID.
@@ -1063,6 +1065,8 @@ func resolveOptionsForRestoreJobDescription(
 	opts tree.RestoreOptions,
 	intoDB string,
 	newDBName string,
+	intoTable string,
+	filter string,
 	kmsURIs []string,
 	incFrom []string,
 ) (tree.RestoreOptions, error) {
@@ -1096,6 +1100,14 @@ func resolveOptionsForRestoreJobDescription(
 		newOpts.NewDBName = tree.NewDString(newDBName)
 	}
 
+	if opts.IntoTable != nil {
+		newOpts.IntoTable = tree.NewDString(intoTable)
+	}
+
+	if opts.Filter != nil {
+		newOpts.Filter = tree.NewDString(filter)
+	}
+
 	for _, uri := range kmsURIs {
 		redactedURI, err := cloud.RedactKMSURI(uri)
 		if err != nil {
@@ -1128,6 +1140,8 @@ func restoreJobDescription(
 	opts tree.RestoreOptions,
 	intoDB string,
 	newDBName string,
+	intoTable string,
+	filter string,
 	kmsURIs []string,
 ) (string, error) {
 	r := &tree.Restore{
@@ -1140,7 +1154,7 @@ func restoreJobDescription(
 	var options tree.RestoreOptions
 	var err error
 	if options, err = resolveOptionsForRestoreJobDescription(ctx, opts, intoDB, newDBName,
-		kmsURIs, incFrom); err != nil {
+		intoTable, filter, kmsURIs, incFrom); err != nil {
 		return "", err
 	}
 	r.Options = options
@@ -1182,6 +1196,8 @@ func restoreTypeCheck(
 			restoreStmt.Options.EncryptionPassphrase,
 			restoreStmt.Options.IntoDB,
 			restoreStmt.Options.NewDBName,
+			restoreStmt.Options.IntoTable,
+			restoreStmt.Options.Filter,
 			restoreStmt.Options.ForceTenantID,
 			restoreStmt.Options.AsTenant,
 			restoreStmt.Options.DebugPauseOn,
@@ -1265,8 +1281,9 @@ func restorePlanHook(
 
 	var intoDB string
 	if restoreStmt.Options.IntoDB != nil {
-		if restoreStmt.DescriptorCoverage == tree.SystemUsers {
-			return nil, nil, nil, false, errors.New("cannot set into_db option when only restoring system users")
+		if restoreStmt.DescriptorCoverage.IsSystemTarget() {
+			return nil, nil, nil, false, errors.Newf("cannot set into_db option when only restoring system %s",
+				strings.ToLower(restoreStmt.DescriptorCoverage.SystemTargetName()))
 		}
 		var err error
 		intoDB, err = exprEval.String(ctx, restoreStmt.Options.IntoDB)
@@ -1328,8 +1345,9 @@ func restorePlanHook(
 				" database")
 			return nil, nil, nil, false, err
 		}
-		if restoreStmt.DescriptorCoverage == tree.SystemUsers {
-			return nil, nil, nil, false, errors.New("cannot set new_db_name option when only restoring system users")
+		if restoreStmt.DescriptorCoverage.IsSystemTarget() {
+			return nil, nil, nil, false, errors.Newf("cannot set new_db_name option when only restoring system %s",
+				strings.ToLower(restoreStmt.DescriptorCoverage.SystemTargetName()))
 		}
 		var err error
 		newDBName, err = exprEval.String(ctx, restoreStmt.Options.NewDBName)
@@ -1338,6 +1356,40 @@ func restorePlanHook(
 		}
 	}
 
+	var intoTable, filter string
+	if restoreStmt.Options.IntoTable != nil || restoreStmt.Options.Filter != nil {
+		if restoreStmt.DescriptorCoverage != tree.RequestedDescriptors ||
+			restoreStmt.Targets.Databases != nil || restoreStmt.Targets.Schemas != nil ||
+			restoreStmt.Targets.TenantID.IsSet() || len(restoreStmt.Targets.Tables.TablePatterns) != 1 {
+			err := errors.Errorf("options %q/%q can only be used for RESTORE TABLE with a single target table",
+				restoreOptIntoTable, restoreOptFilter)
+			return nil, nil, nil, false, err
+		}
+		if restoreStmt.Options.SchemaOnly && restoreStmt.Options.Filter != nil {
+			err := errors.Errorf("cannot use %q option with schema_only", restoreOptFilter)
+			return nil, nil, nil, false, err
+		}
+		var err error
+		if restoreStmt.Options.IntoTable != nil {
+			intoTable, err = exprEval.String(ctx, restoreStmt.Options.IntoTable)
+			if err != nil {
+				return nil, nil, nil, false, err
+			}
+			if intoTable == "" {
+				return nil, nil, nil, false, errors.Errorf("%q option cannot be empty", restoreOptIntoTable)
+			}
+		}
+		if restoreStmt.Options.Filter != nil {
+			filter, err = exprEval.String(ctx, restoreStmt.Options.Filter)
+			if err != nil {
+				return nil, nil, nil, false, err
+			}
+			if filter == "" {
+				return nil, nil, nil, false, errors.Errorf("%q option cannot be empty", restoreOptFilter)
+			}
+		}
+	}
+
 	var restoreAllTenants bool
 	if restoreStmt.Options.IncludeAllSecondaryTenants != nil {
 		if restoreStmt.DescriptorCoverage != tree.AllDescriptors {
@@ -1437,7 +1489,8 @@ func restorePlanHook(
 
 		return doRestorePlan(
 			ctx, restoreStmt, &exprEval, p, from, incStorage, pw, kms, restoreAllTenants, intoDB,
-			newDBName, newTenantID, newTenantName, endTime, resultsCh, subdir, execLocality,
+			newDBName, intoTable, filter, newTenantID, newTenantName, endTime, resultsCh, subdir,
+			execLocality,
 		)
 	}
 
@@ -1505,9 +1558,10 @@ func checkPrivilegesForRestore(
 	}
 
 	{
-		// Cluster and tenant restores require the `RESTORE` system privilege for
-		// non-admin users.
+		// Cluster, tenant and system configuration restores require the
+		// `RESTORE` system privilege for non-admin users.
 		requiresRestoreSystemPrivilege := restoreStmt.DescriptorCoverage == tree.AllDescriptors ||
+			restoreStmt.DescriptorCoverage.IsSystemTarget() ||
 			restoreStmt.Targets.TenantID.IsSet()
 
 		if requiresRestoreSystemPrivilege {
@@ -1679,6 +1733,8 @@ func doRestorePlan(
 	restoreAllTenants bool,
 	intoDB string,
 	newDBName string,
+	intoTable string,
+	filter string,
 	newTenantID *roachpb.TenantID,
 	newTenantName *roachpb.TenantName,
 	endTime hlc.Timestamp,
@@ -1849,6 +1905,12 @@ func doRestorePlan(
 		return err
 	}
 
+	if restoreStmt.Options.ExperimentalOnline {
+		if err := checkOnlineRestoreSupported(p, restoreStmt, mainBackupManifests, encryption, endTime); err != nil {
+			return err
+		}
+	}
+
 	if restoreStmt.DescriptorCoverage == tree.AllDescriptors {
 		// Validate that the backup is a full cluster backup if a full cluster restore was requested.
 		if mainBackupManifests[0].DescriptorCoverage == tree.RequestedDescriptors {
@@ -2072,6 +2134,27 @@ func doRestorePlan(
 		}
 	}
 
+	// Rename and restrict the target table before we allocate descriptor
+	// rewrites, so that name collisions are checked against the new name.
+	var restrictSpans roachpb.Spans
+	if intoTable != "" || filter != "" {
+		if len(filteredTablesByID) != 1 {
+			return errors.Errorf("options %q/%q can only be used when restoring a single table",
+				restoreOptIntoTable, restoreOptFilter)
+		}
+		for _, t := range filteredTablesByID {
+			if filter != "" {
+				restrictSpans, err = restoreFilterSpans(
+					ctx, p.SemaCtx(), &p.ExtendedEvalContext().Context, backupCodec, t, filter,
+				)
+				if err != nil {
+					return err
+				}
+			}
+			applyRestoreTableOverrides(t, intoTable)
+		}
+	}
+
 	// If we are stripping localities, wipe tables of their LocalityConfig before we allocate
 	// descriptor rewrites - as validation in remapTables compares these tables with the non-mr
 	// database and fails otherwise
@@ -2112,6 +2195,8 @@ func doRestorePlan(
 		restoreStmt.Options,
 		intoDB,
 		newDBName,
+		intoTable,
+		filter,
 		kms)
 	if err != nil {
 		return err
@@ -2198,6 +2283,8 @@ func doRestorePlan(
 		ExecutionLocality:   execLocality,
 		ExperimentalOnline:  restoreStmt.Options.ExperimentalOnline,
 		RemoveRegions:       restoreStmt.Options.RemoveRegions,
+		IntoTable:           intoTable,
+		RestrictSpans:       restrictSpans,
 	}
 
 	jr := jobs.Record{
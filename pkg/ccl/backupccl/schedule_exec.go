@@ -10,8 +10,10 @@ package backupccl
 
 import (
 	"context"
+	"strconv"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -115,8 +117,72 @@ func (e *scheduledBackupExecutor) executeBackup(
 	if err != nil {
 		return err
 	}
-	_, err = invokeBackup(ctx, backupFn, nil, nil)
-	return err
+	if _, err := invokeBackup(ctx, backupFn, nil, nil); err != nil {
+		return err
+	}
+
+	if backupStmt.AppendToLatest {
+		args := &backuppb.ScheduledBackupExecutionArgs{}
+		if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+			return errors.Wrap(err, "un-marshaling args")
+		}
+		if args.CompactionThreshold > 0 {
+			// A failure to compact the backup should not fail the scheduled backup,
+			// as the backup remains valid without compaction.
+			if err := compactScheduledBackup(
+				ctx, hook.(sql.PlanHookState), backupStmt.Backup, args.CompactionThreshold,
+			); err != nil {
+				log.Warningf(ctx, "failed to compact backup of schedule %d: %v", sj.ScheduleID(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// compactScheduledBackup starts a job to compact the incremental layers of the
+// latest backup in the collection of an incremental backup schedule, if it
+// has at least threshold incremental layers.
+//
+// Since incremental schedules wait for their previous run to complete, the
+// layers written by previous runs are all complete; the layer written by the
+// backup which was just started is not compacted as it is not yet listed.
+func compactScheduledBackup(
+	ctx context.Context, p sql.PlanHookState, backupStmt *tree.Backup, threshold int64,
+) error {
+	opts := tree.KVOptions{{Key: tree.AlterBackupCompactOptionDetached}}
+	if backupStmt.Options.CaptureRevisionHistory == tree.DBoolTrue {
+		opts = append(opts, tree.KVOption{Key: tree.AlterBackupCompactOptionRevisionHistory})
+	}
+	if backupStmt.Options.EncryptionPassphrase != nil {
+		opts = append(opts, tree.KVOption{
+			Key:   tree.AlterBackupCompactOptionEncryptionPassphrase,
+			Value: backupStmt.Options.EncryptionPassphrase,
+		})
+	}
+	if len(backupStmt.Options.EncryptionKMSURI) > 0 {
+		opts = append(opts, tree.KVOption{
+			Key:   tree.AlterBackupCompactOptionKMS,
+			Value: backupStmt.Options.EncryptionKMSURI[0],
+		})
+	}
+	if len(backupStmt.Options.IncrementalStorage) > 0 {
+		opts = append(opts, tree.KVOption{
+			Key:   tree.AlterBackupCompactOptionIncrementalLocation,
+			Value: backupStmt.Options.IncrementalStorage[0],
+		})
+	}
+	compact := &tree.AlterBackupCompact{Options: opts}
+	alterStmt := &tree.AlterBackup{
+		Subdir: tree.NewStrVal(backupbase.LatestFileName),
+		Backup: backupStmt.To[0],
+		Cmds:   tree.AlterBackupCmds{compact},
+	}
+	fn, _, err := planBackupCompaction(ctx, p, alterStmt, compact, threshold)
+	if err != nil {
+		return err
+	}
+	resultsCh := make(chan tree.Datums, 1)
+	return fn(ctx, nil, resultsCh)
 }
 
 func invokeBackup(
@@ -285,6 +351,23 @@ func (e *scheduledBackupExecutor) GetCreateScheduleStatement(
 		},
 	}
 
+	// The compaction threshold is stored in the arguments of the incremental
+	// schedule.
+	compactionThreshold := args.CompactionThreshold
+	if !backupNode.AppendToLatest && dependentSchedule != nil {
+		incArgs := &backuppb.ScheduledBackupExecutionArgs{}
+		if err := pbtypes.UnmarshalAny(dependentSchedule.ExecutionArgs().Args, incArgs); err != nil {
+			return "", errors.Wrap(err, "un-marshaling args")
+		}
+		compactionThreshold = incArgs.CompactionThreshold
+	}
+	if compactionThreshold > 0 {
+		scheduleOptions = append(scheduleOptions, tree.KVOption{
+			Key:   optCompactionThreshold,
+			Value: tree.NewDString(strconv.FormatInt(compactionThreshold, 10)),
+		})
+	}
+
 	var destinations []string
 	for i := range backupNode.To {
 		dest, ok := backupNode.To[i].(*tree.StrVal)
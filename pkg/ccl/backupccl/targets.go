@@ -361,6 +361,75 @@ func fullClusterTargetsBackup(
 	return fullClusterDescs, fullClusterDBIDs, nil
 }
 
+// systemTargetTables maps each part of the system configuration of the
+// cluster which can be backed up and restored on its own, with e.g. BACKUP
+// SYSTEM USERS and RESTORE SYSTEM USERS, to the system tables which hold it.
+// The first table of each part must be in a backup to restore the part from it.
+var systemTargetTables = map[tree.DescriptorCoverage][]string{
+	tree.SystemUsers: {
+		systemschema.UsersTable.GetName(),
+		systemschema.RoleMembersTable.GetName(),
+		systemschema.RoleOptionsTable.GetName(),
+		systemschema.DatabaseRoleSettingsTable.GetName(),
+		systemschema.SystemPrivilegeTable.GetName(),
+	},
+	tree.SystemSettings: {
+		systemschema.SettingsTable.GetName(),
+	},
+	tree.SystemZoneConfigs: {
+		systemschema.ZonesTable.GetName(),
+	},
+}
+
+// systemTargetsBackup returns the descriptors of the system tables to include
+// in a backup of a part of the system configuration of the cluster.
+func systemTargetsBackup(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	endTime hlc.Timestamp,
+	coverage tree.DescriptorCoverage,
+) ([]catalog.Descriptor, error) {
+	allDescs, err := backupresolver.LoadAllDescs(ctx, execCfg, endTime)
+	if err != nil {
+		return nil, err
+	}
+	var systemDB catalog.Descriptor
+	for _, desc := range allDescs {
+		if desc.GetID() == keys.SystemDatabaseID {
+			systemDB = desc
+			break
+		}
+	}
+	if systemDB == nil {
+		return nil, errors.AssertionFailedf("system database descriptor not found")
+	}
+	return append([]catalog.Descriptor{systemDB}, systemTargetDescs(allDescs, coverage)...), nil
+}
+
+// systemTargetDescs returns the descriptors of the system tables which hold
+// the given part of the system configuration.
+func systemTargetDescs(
+	allDescs []catalog.Descriptor, coverage tree.DescriptorCoverage,
+) []catalog.Descriptor {
+	names := make(map[string]struct{})
+	for _, name := range systemTargetTables[coverage] {
+		names[name] = struct{}{}
+	}
+	var descs []catalog.Descriptor
+	for _, desc := range allDescs {
+		if desc.GetParentID() != keys.SystemDatabaseID || desc.Dropped() {
+			continue
+		}
+		if _, ok := desc.(catalog.TableDescriptor); !ok {
+			continue
+		}
+		if _, ok := names[desc.GetName()]; ok {
+			descs = append(descs, desc)
+		}
+	}
+	return descs
+}
+
 // checkMissingIntroducedSpans asserts that each backup's IntroducedSpans
 // contain all tables that require an introduction (i.e. a backup from ts=0), if
 // they are also restore targets. This specifically entails checking the
@@ -563,24 +632,12 @@ func selectTargets(
 		return fullClusterTargetsRestore(ctx, allDescs, lastBackupManifest, restoreAllTenants)
 	}
 
-	if descriptorCoverage == tree.SystemUsers {
-		systemTables := make([]catalog.Descriptor, 0)
-		var users catalog.Descriptor
-		for _, desc := range allDescs {
-			if desc.GetParentID() == systemschema.SystemDB.GetID() {
-				switch desc.GetName() {
-				case systemschema.UsersTable.GetName():
-					users = desc
-					systemTables = append(systemTables, desc)
-				case systemschema.RoleMembersTable.GetName():
-					systemTables = append(systemTables, desc)
-				case systemschema.RoleOptionsTable.GetName():
-					systemTables = append(systemTables, desc)
-				}
-			}
-		}
-		if users == nil {
-			return nil, nil, nil, nil, errors.Errorf("cannot restore system users as no system.users table in the backup")
+	if descriptorCoverage.IsSystemTarget() {
+		systemTables := systemTargetDescs(allDescs, descriptorCoverage)
+		required := systemTargetTables[descriptorCoverage][0]
+		if !hasSystemTableDescByName(required, systemTables) {
+			return nil, nil, nil, nil, errors.Errorf("cannot restore system %s as no system.%s table in the backup",
+				strings.ToLower(descriptorCoverage.SystemTargetName()), required)
 		}
 		return systemTables, nil, nil, nil, nil
 	}
@@ -615,6 +672,15 @@ func selectTargets(
 	return matched.Descs, matched.RequestedDBs, matched.DescsByTablePattern, nil, nil
 }
 
+func hasSystemTableDescByName(name string, descs []catalog.Descriptor) bool {
+	for _, desc := range descs {
+		if desc.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
 // EntryFiles is a group of sst files of a backup table range
 type EntryFiles []execinfrapb.RestoreFileSpec
 
@@ -0,0 +1,411 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// verifyBackupCheckpointInterval is how often a VERIFY BACKUP job records the
+// spans that it has verified.
+const verifyBackupCheckpointInterval = 10 * time.Second
+
+type verifyBackupResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &verifyBackupResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+//
+// The job generates the same span covering that a restore of the backup as of
+// its end time would, and reads, on the coordinator, the part of every file in
+// each entry of the covering that the entry restores. The keys of each file
+// must be ordered and their values' checksums must match; pebble verifies the
+// checksums of the blocks of the files as it reads them. If requested, the
+// fingerprint of the data that the entry restores is then compared with the
+// fingerprint of the entry's span of the cluster as of the backup's end time.
+func (r *verifyBackupResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.VerifyBackupDetails)
+
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+
+	kmsEnv := backupencryption.MakeBackupKMSEnv(
+		execCfg.Settings,
+		&execCfg.ExternalIODirConfig,
+		execCfg.InternalDB,
+		p.User(),
+	)
+	backupManifests, memSize, err := backupinfo.LoadBackupManifestsAtTime(ctx, &mem, details.URIs,
+		p.User(), execCfg.DistSQLSrv.ExternalStorageFromURI, details.Encryption, &kmsEnv, details.EndTime)
+	if err != nil {
+		return err
+	}
+	defer mem.Shrink(ctx, memSize)
+
+	layerToIterFactory, err := backupinfo.GetBackupManifestIterFactories(ctx,
+		execCfg.DistSQLSrv.ExternalStorage, backupManifests, details.Encryption, &kmsEnv)
+	if err != nil {
+		return err
+	}
+	backupLocalityMap, err := makeBackupLocalityMap(details.BackupLocalityInfo, p.User())
+	if err != nil {
+		return errors.Wrap(err, "resolving locality locations")
+	}
+	introducedSpanFrontier, err := createIntroducedSpanFrontier(backupManifests, details.EndTime)
+	if err != nil {
+		return err
+	}
+	if err := checkCoverage(ctx, details.Spans, backupManifests); err != nil {
+		return err
+	}
+	filter, err := makeSpanCoveringFilter(
+		nil, /* checkpointFrontier */
+		nil, /* highWater */
+		introducedSpanFrontier,
+		targetRestoreSpanSize.Get(&execCfg.Settings.SV),
+		false, /* useFrontierCheckpointing */
+	)
+	if err != nil {
+		return err
+	}
+
+	var fileEncryption *kvpb.FileEncryptionOptions
+	if details.Encryption != nil {
+		key := details.Encryption.Key
+		if details.Encryption.Mode == jobspb.EncryptionMode_KMS {
+			key, err = backupencryption.GetEncryptionKey(ctx, details.Encryption, &kmsEnv)
+			if err != nil {
+				return errors.Wrap(err, "failed to decrypt data key")
+			}
+		}
+		fileEncryption = &kvpb.FileEncryptionOptions{Key: key}
+	}
+
+	v := &backupVerifier{
+		execCfg:    execCfg,
+		job:        r.job,
+		details:    details,
+		encryption: fileEncryption,
+		checkpoint: util.Every(verifyBackupCheckpointInterval),
+	}
+	if prog := r.job.Progress().GetVerifyBackup(); prog != nil {
+		v.prog = *prog
+	}
+	v.completed.Add(v.prog.CompletedSpans...)
+
+	spanCh := make(chan execinfrapb.RestoreSpanEntry, 1000)
+	g := ctxgroup.WithContext(ctx)
+	g.GoCtx(func(ctx context.Context) error {
+		defer close(spanCh)
+		return generateAndSendImportSpans(
+			ctx,
+			details.Spans,
+			backupManifests,
+			layerToIterFactory,
+			backupLocalityMap,
+			filter,
+			false, /* useSimpleImportSpans */
+			spanCh,
+		)
+	})
+	g.GoCtx(func(ctx context.Context) error {
+		for entry := range spanCh {
+			if err := v.verifyEntry(ctx, entry); err != nil {
+				return err
+			}
+		}
+		return v.saveProgress(ctx)
+	})
+	return g.Wait()
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. The job only reads the
+// backup, so there is nothing to clean up.
+func (r *verifyBackupResumer) OnFailOrCancel(
+	ctx context.Context, execCtx interface{}, jobErr error,
+) error {
+	return nil
+}
+
+// CollectProfile is a part of the Resumer interface.
+func (r *verifyBackupResumer) CollectProfile(_ context.Context, _ interface{}) error {
+	return nil
+}
+
+// ReportResults implements JobResultsReporter interface.
+func (r *verifyBackupResumer) ReportResults(
+	ctx context.Context, resultsCh chan<- tree.Datums,
+) error {
+	prog := r.job.Progress().GetVerifyBackup()
+	select {
+	case resultsCh <- tree.Datums{
+		tree.NewDInt(tree.DInt(r.job.ID())),
+		tree.NewDString(string(jobs.StatusSucceeded)),
+		tree.NewDInt(tree.DInt(prog.Files)),
+		tree.NewDInt(tree.DInt(prog.Keys)),
+		tree.NewDInt(tree.DInt(prog.Bytes)),
+		tree.NewDInt(tree.DInt(prog.FingerprintedSpans)),
+	}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// backupVerifier verifies the entries of the span covering of a backup.
+type backupVerifier struct {
+	execCfg    *sql.ExecutorConfig
+	job        *jobs.Job
+	details    jobspb.VerifyBackupDetails
+	encryption *kvpb.FileEncryptionOptions
+
+	prog       jobspb.VerifyBackupProgress
+	completed  roachpb.SpanGroup
+	checkpoint util.EveryN
+}
+
+// verifyEntry verifies the files of an entry of the span covering, and, if
+// requested, compares the fingerprint of the data that they contain with that
+// of the cluster.
+func (v *backupVerifier) verifyEntry(
+	ctx context.Context, entry execinfrapb.RestoreSpanEntry,
+) error {
+	if v.completed.Encloses(entry.Span) {
+		return nil
+	}
+	for _, file := range entry.Files {
+		if err := v.verifyFile(ctx, entry.Span, file); err != nil {
+			return err
+		}
+	}
+	if v.details.Fingerprint {
+		if err := v.fingerprintEntry(ctx, entry); err != nil {
+			return err
+		}
+	}
+	v.completed.Add(entry.Span)
+	if v.checkpoint.ShouldProcess(timeutil.Now()) {
+		return v.saveProgress(ctx)
+	}
+	return nil
+}
+
+// verifyFile reads the keys of the file which lie in the given span of the
+// span covering, and checks that they are ordered and that the checksums of
+// their values match.
+func (v *backupVerifier) verifyFile(
+	ctx context.Context, span roachpb.Span, file execinfrapb.RestoreFileSpec,
+) error {
+	// The end key of the span of a file entry is inclusive.
+	fileSpan := roachpb.Span{
+		Key:    file.BackupFileEntrySpan.Key,
+		EndKey: file.BackupFileEntrySpan.EndKey.Next(),
+	}
+	span = span.Intersect(fileSpan)
+	if !span.Valid() {
+		return nil
+	}
+
+	dir, err := v.execCfg.DistSQLSrv.ExternalStorage(ctx, file.Dir)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := dir.Close(); err != nil {
+			log.Warningf(ctx, "close export storage failed %v", err)
+		}
+	}()
+	iter, err := storageccl.ExternalSSTReader(ctx,
+		[]storageccl.StoreFile{{Store: dir, FilePath: file.Path}}, v.encryption,
+		storage.IterOptions{
+			KeyTypes:   storage.IterKeyTypePointsAndRanges,
+			LowerBound: span.Key,
+			UpperBound: span.EndKey,
+		})
+	if err != nil {
+		return errors.Wrapf(err, "opening backup file %s", file.Path)
+	}
+	defer iter.Close()
+
+	var prev storage.MVCCKey
+	for iter.SeekGE(storage.MVCCKey{Key: span.Key}); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return errors.Wrapf(err, "reading backup file %s", file.Path)
+		} else if !ok {
+			break
+		}
+		if hasPoint, _ := iter.HasPointAndRange(); !hasPoint {
+			continue
+		}
+		key := iter.UnsafeKey()
+		if len(prev.Key) > 0 && !prev.Less(key) {
+			return pgerror.Newf(pgcode.DataCorrupted,
+				"backup file %s is corrupt: key %s follows key %s", file.Path, key, prev)
+		}
+		value, err := iter.UnsafeValue()
+		if err != nil {
+			return errors.Wrapf(err, "reading backup file %s", file.Path)
+		}
+		if err := (roachpb.Value{RawBytes: value}).Verify(key.Key); err != nil {
+			return pgerror.Wrapf(err, pgcode.DataCorrupted, "backup file %s is corrupt", file.Path)
+		}
+		v.prog.Keys++
+		v.prog.Bytes += int64(len(key.Key) + len(value))
+		prev = key.Clone()
+	}
+	v.prog.Files++
+	return nil
+}
+
+// fingerprintEntry compares the fingerprint of the data that the entry of the
+// span covering restores with the fingerprint of its span of the cluster as of
+// the end time of the backup.
+func (v *backupVerifier) fingerprintEntry(
+	ctx context.Context, entry execinfrapb.RestoreSpanEntry,
+) error {
+	backupFingerprint, err := v.backupFingerprint(ctx, entry)
+	if err != nil {
+		return err
+	}
+	clusterFingerprint, err := v.clusterFingerprint(ctx, entry.Span)
+	if err != nil {
+		return errors.Wrapf(err, "fingerprinting %s as of %s", entry.Span, v.details.EndTime)
+	}
+	if backupFingerprint != clusterFingerprint {
+		return jobs.MarkAsPermanentJobError(pgerror.Newf(pgcode.DataCorrupted,
+			"fingerprint %d of the data in the backup in %s does not match fingerprint %d of the"+
+				" cluster's data as of %s", backupFingerprint, entry.Span, clusterFingerprint,
+			v.details.EndTime))
+	}
+	v.prog.FingerprintedSpans++
+	return nil
+}
+
+// backupFingerprint returns the fingerprint of the latest values, as of the end
+// time of the backup, of the keys in the span of the entry, computed in the same
+// way as crdb_internal.fingerprint computes them for the cluster.
+func (v *backupVerifier) backupFingerprint(
+	ctx context.Context, entry execinfrapb.RestoreSpanEntry,
+) (uint64, error) {
+	if len(entry.Files) == 0 {
+		return 0, nil
+	}
+	storeFiles := make([]storageccl.StoreFile, 0, len(entry.Files))
+	defer func() {
+		for _, f := range storeFiles {
+			if err := f.Store.Close(); err != nil {
+				log.Warningf(ctx, "close export storage failed %v", err)
+			}
+		}
+	}()
+	for _, file := range entry.Files {
+		dir, err := v.execCfg.DistSQLSrv.ExternalStorage(ctx, file.Dir)
+		if err != nil {
+			return 0, err
+		}
+		storeFiles = append(storeFiles, storageccl.StoreFile{Store: dir, FilePath: file.Path})
+	}
+	iter, err := storageccl.ExternalSSTReader(ctx, storeFiles, v.encryption, storage.IterOptions{
+		RangeKeyMaskingBelow: v.details.EndTime,
+		KeyTypes:             storage.IterKeyTypePointsAndRanges,
+		LowerBound:           keys.LocalMax,
+		UpperBound:           keys.MaxKey,
+	})
+	if err != nil {
+		return 0, err
+	}
+	readAsOfIter := storage.NewReadAsOfIterator(iter, v.details.EndTime)
+	defer readAsOfIter.Close()
+	return storage.FingerprintPointKeys(readAsOfIter, entry.Span, storage.MVCCExportFingerprintOptions{
+		StripTenantPrefix:  true,
+		StripValueChecksum: true,
+	})
+}
+
+// clusterFingerprint returns the fingerprint of the span of the cluster as of
+// the end time of the backup.
+func (v *backupVerifier) clusterFingerprint(
+	ctx context.Context, span roachpb.Span,
+) (uint64, error) {
+	row, err := v.execCfg.InternalDB.Executor().QueryRowEx(
+		ctx, "verify-backup-fingerprint", nil, /* txn */
+		sessiondata.RootUserSessionDataOverride,
+		fmt.Sprintf(
+			`SELECT crdb_internal.fingerprint(ARRAY[$1::BYTES, $2::BYTES], false) AS OF SYSTEM TIME %s`,
+			v.details.EndTime.AsOfSystemTime(),
+		),
+		[]byte(span.Key), []byte(span.EndKey),
+	)
+	if err != nil {
+		return 0, err
+	}
+	if row == nil {
+		return 0, errors.AssertionFailedf("no fingerprint returned for %s", span)
+	}
+	return uint64(tree.MustBeDInt(row[0])), nil
+}
+
+// saveProgress records the spans of the span covering that have been
+// verified, so that a resumed job does not verify them again.
+func (v *backupVerifier) saveProgress(ctx context.Context) error {
+	v.prog.CompletedSpans = v.completed.Slice()
+	var done int
+	for _, sp := range v.details.Spans {
+		if v.completed.Encloses(sp) {
+			done++
+		}
+	}
+	return v.job.NoTxn().FractionProgressed(ctx, func(
+		ctx context.Context, details jobspb.ProgressDetails,
+	) float32 {
+		*details.(*jobspb.Progress_VerifyBackup).VerifyBackup = v.prog
+		if len(v.details.Spans) == 0 {
+			return 1
+		}
+		return float32(done) / float32(len(v.details.Spans))
+	})
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeVerifyBackup,
+		func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+			return &verifyBackupResumer{job: job}
+		},
+		jobs.UsesTenantCostControl,
+	)
+}
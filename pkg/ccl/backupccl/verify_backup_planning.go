@@ -0,0 +1,395 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudprivilege"
+	"github.com/cockroachdb/cockroach/pkg/featureflag"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/syntheticprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+var verifyBackupOptionExpectValues = map[string]exprutil.KVStringOptValidate{
+	tree.VerifyBackupOptionDetached:             exprutil.KVStringOptRequireNoValue,
+	tree.VerifyBackupOptionFingerprint:          exprutil.KVStringOptRequireNoValue,
+	tree.VerifyBackupOptionEncryptionPassphrase: exprutil.KVStringOptRequireValue,
+	tree.VerifyBackupOptionKMS:                  exprutil.KVStringOptRequireValue,
+	tree.VerifyBackupOptionIncrementalLocation:  exprutil.KVStringOptRequireValue,
+}
+
+// verifyBackupHeader is the header of the results of a VERIFY BACKUP which is
+// not detached.
+var verifyBackupHeader = colinfo.ResultColumns{
+	{Name: "job_id", Typ: types.Int},
+	{Name: "status", Typ: types.String},
+	{Name: "files", Typ: types.Int},
+	{Name: "keys", Typ: types.Int},
+	{Name: "bytes", Typ: types.Int},
+	{Name: "fingerprinted_spans", Typ: types.Int},
+}
+
+func verifyBackupTypeCheck(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (matched bool, header colinfo.ResultColumns, _ error) {
+	verifyStmt, ok := stmt.(*tree.VerifyBackup)
+	if !ok {
+		return false, nil, nil
+	}
+	if err := exprutil.TypeCheck(
+		ctx, "VERIFY BACKUP", p.SemaCtx(),
+		exprutil.Strings{verifyStmt.Subdir},
+		exprutil.StringArrays{tree.Exprs(verifyStmt.To)},
+		exprutil.KVOptions{
+			KVOptions: verifyStmt.Options, Validation: verifyBackupOptionExpectValues,
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	if verifyStmt.Options.HasKey(tree.VerifyBackupOptionDetached) {
+		return true, jobs.DetachedJobExecutionResultHeader, nil
+	}
+	return true, verifyBackupHeader, nil
+}
+
+// checkPrivilegesForVerifyBackup checks that the user may read the backup.
+// Reading every file of a backup requires the same privileges as restoring all
+// of it, and comparing it with the cluster requires reading all of the spans
+// that it contains, which only admins may do.
+func checkPrivilegesForVerifyBackup(
+	ctx context.Context, p sql.PlanHookState, dest []string, fingerprint bool,
+) error {
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	if !hasAdmin {
+		if fingerprint {
+			return pgerror.Newf(pgcode.InsufficientPrivilege,
+				"only users with the admin role may run VERIFY BACKUP with the %s option",
+				tree.VerifyBackupOptionFingerprint)
+		}
+		if err := p.CheckPrivilegeForUser(
+			ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.RESTORE, p.User(),
+		); err != nil {
+			return pgerror.Wrapf(
+				err,
+				pgcode.InsufficientPrivilege,
+				"only users with the admin role or the RESTORE system privilege are allowed to verify"+
+					" a backup")
+		}
+	}
+	return cloudprivilege.CheckDestinationPrivileges(ctx, p, dest)
+}
+
+// resolveBackupEncryptionForRead returns the options with which the files of
+// the backup in the given store are encrypted, given the passphrase or the KMS
+// URI that the user provided, or nil if neither was provided.
+func resolveBackupEncryptionForRead(
+	ctx context.Context, store cloud.ExternalStorage, passphrase, kms string, kmsEnv cloud.KMSEnv,
+) (*jobspb.BackupEncryptionOptions, error) {
+	if passphrase != "" {
+		encOpts, err := backupencryption.ReadEncryptionOptions(ctx, store)
+		if err != nil {
+			return nil, err
+		}
+		return &jobspb.BackupEncryptionOptions{
+			Mode: jobspb.EncryptionMode_Passphrase,
+			Key:  storageccl.GenerateKey([]byte(passphrase), encOpts[0].Salt),
+		}, nil
+	}
+	if kms == "" {
+		return nil, nil
+	}
+	encOpts, err := backupencryption.ReadEncryptionOptions(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+	var defaultKMSInfo *jobspb.BackupEncryptionOptions_KMSInfo
+	for _, encFile := range encOpts {
+		defaultKMSInfo, err = backupencryption.ValidateKMSURIsAgainstFullBackup(
+			ctx,
+			[]string{kms},
+			backupencryption.NewEncryptedDataKeyMapFromProtoMap(encFile.EncryptedDataKeyByKMSMasterKeyID),
+			kmsEnv,
+		)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &jobspb.BackupEncryptionOptions{
+		Mode:    jobspb.EncryptionMode_KMS,
+		KMSInfo: defaultKMSInfo,
+	}, nil
+}
+
+func verifyBackupJobDescription(
+	p sql.PlanHookState, subdir string, dest []string, opts map[string]string,
+) (string, error) {
+	to, err := sanitizeURIList(dest)
+	if err != nil {
+		return "", err
+	}
+	stmt := &tree.VerifyBackup{
+		Subdir: tree.NewDString(subdir),
+		To:     to,
+	}
+	for k, v := range opts {
+		opt := tree.KVOption{Key: tree.Name(k)}
+		switch k {
+		case tree.VerifyBackupOptionEncryptionPassphrase:
+			opt.Value = tree.NewDString("redacted")
+		case tree.VerifyBackupOptionKMS:
+			redacted, err := cloud.RedactKMSURI(v)
+			if err != nil {
+				return "", err
+			}
+			opt.Value = tree.NewDString(redacted)
+		case tree.VerifyBackupOptionIncrementalLocation:
+			sanitized, err := cloud.SanitizeExternalStorageURI(v, nil /* extraParams */)
+			if err != nil {
+				return "", err
+			}
+			opt.Value = tree.NewDString(sanitized)
+		}
+		stmt.Options = append(stmt.Options, opt)
+	}
+	sort.Slice(stmt.Options, func(i, j int) bool { return stmt.Options[i].Key < stmt.Options[j].Key })
+	ann := p.ExtendedEvalContext().Annotations
+	return tree.AsStringWithFQNames(stmt, ann), nil
+}
+
+// verifyBackupPlanHook implements sql.PlanHookFn for VERIFY BACKUP, which
+// starts a job that reads all of the files that a restore of the backup as of
+// its end time would read.
+func verifyBackupPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	verifyStmt, ok := stmt.(*tree.VerifyBackup)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+
+	if err := featureflag.CheckEnabled(
+		ctx,
+		p.ExecCfg(),
+		featureRestoreEnabled,
+		"VERIFY BACKUP",
+	); err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	exprEval := p.ExprEvaluator("VERIFY BACKUP")
+	subdir, err := exprEval.String(ctx, verifyStmt.Subdir)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	dest, err := exprEval.StringArray(ctx, tree.Exprs(verifyStmt.To))
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	opts, err := exprEval.KVOptions(ctx, verifyStmt.Options, verifyBackupOptionExpectValues)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	_, isDetached := opts[tree.VerifyBackupOptionDetached]
+	_, fingerprint := opts[tree.VerifyBackupOptionFingerprint]
+	if _, ok := opts[tree.VerifyBackupOptionEncryptionPassphrase]; ok {
+		if _, ok := opts[tree.VerifyBackupOptionKMS]; ok {
+			return nil, nil, nil, false, pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot specify both %s and %s",
+				tree.VerifyBackupOptionEncryptionPassphrase, tree.VerifyBackupOptionKMS)
+		}
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, verifyStmt.StatementTag())
+		defer span.Finish()
+
+		if !(p.ExtendedEvalContext().TxnIsSingleStmt || isDetached) {
+			return errors.Errorf("VERIFY BACKUP cannot be used inside a multi-statement transaction without DETACHED option")
+		}
+
+		if err := checkPrivilegesForVerifyBackup(ctx, p, dest, fingerprint); err != nil {
+			return err
+		}
+
+		mkStore := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
+		subdir := subdir
+		if strings.EqualFold(subdir, backupbase.LatestFileName) {
+			var err error
+			subdir, err = backupdest.ReadLatestFile(ctx, dest[0], mkStore, p.User())
+			if err != nil {
+				return errors.Wrap(err, "read LATEST path")
+			}
+		}
+		fullyResolvedDest, err := backuputils.AppendPaths(dest, subdir)
+		if err != nil {
+			return err
+		}
+		baseStores := make([]cloud.ExternalStorage, len(fullyResolvedDest))
+		for j := range fullyResolvedDest {
+			baseStores[j], err = mkStore(ctx, fullyResolvedDest[j], p.User())
+			if err != nil {
+				return errors.Wrapf(err, "make storage")
+			}
+			defer baseStores[j].Close()
+		}
+
+		kmsEnv := backupencryption.MakeBackupKMSEnv(
+			p.ExecCfg().Settings,
+			&p.ExecCfg().ExternalIODirConfig,
+			p.ExecCfg().InternalDB,
+			p.User(),
+		)
+		encryption, err := resolveBackupEncryptionForRead(ctx, baseStores[0],
+			opts[tree.VerifyBackupOptionEncryptionPassphrase], opts[tree.VerifyBackupOptionKMS], &kmsEnv)
+		if err != nil {
+			return err
+		}
+
+		var explicitIncPaths []string
+		if incLoc, ok := opts[tree.VerifyBackupOptionIncrementalLocation]; ok {
+			explicitIncPaths = []string{incLoc}
+		}
+		collections, computedSubdir, err := backupdest.CollectionsAndSubdir(dest, subdir)
+		if err != nil {
+			return err
+		}
+		fullyResolvedIncrementalsDirectory, err := backupdest.ResolveIncrementalsBackupLocation(
+			ctx,
+			p.User(),
+			p.ExecCfg(),
+			explicitIncPaths,
+			collections,
+			computedSubdir,
+		)
+		if err != nil {
+			if !errors.Is(err, cloud.ErrListingUnsupported) {
+				return err
+			}
+			log.Warningf(
+				ctx, "storage sink %v does not support listing, only verifying the base backup", explicitIncPaths)
+		}
+		incStores, cleanupFn, err := backupdest.MakeBackupDestinationStores(ctx, p.User(), mkStore,
+			fullyResolvedIncrementalsDirectory)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := cleanupFn(); err != nil {
+				log.Warningf(ctx, "failed to close incremental store: %+v", err)
+			}
+		}()
+
+		mem := p.ExecCfg().RootMemoryMonitor.MakeBoundAccount()
+		defer mem.Close(ctx)
+		defaultURIs, manifests, localityInfo, memReserved, err := backupdest.ResolveBackupManifests(
+			ctx, &mem, baseStores, incStores, mkStore, fullyResolvedDest,
+			fullyResolvedIncrementalsDirectory, hlc.Timestamp{}, encryption, &kmsEnv, p.User())
+		if err != nil {
+			return err
+		}
+		defer mem.Shrink(ctx, memReserved)
+		lastManifest := manifests[len(manifests)-1]
+
+		jobDesc, err := verifyBackupJobDescription(p, subdir, dest, opts)
+		if err != nil {
+			return err
+		}
+		jr := jobs.Record{
+			Description: jobDesc,
+			Username:    p.User(),
+			Details: jobspb.VerifyBackupDetails{
+				URIs:               defaultURIs,
+				BackupLocalityInfo: localityInfo,
+				Encryption:         encryption,
+				EndTime:            lastManifest.EndTime,
+				Spans:              lastManifest.Spans,
+				Fingerprint:        fingerprint,
+			},
+			Progress: jobspb.VerifyBackupProgress{},
+		}
+
+		if isDetached {
+			jobID := p.ExecCfg().JobRegistry.MakeJobID()
+			if _, err := p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(
+				ctx, jr, jobID, p.InternalSQLTxn(),
+			); err != nil {
+				return err
+			}
+			resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(jobID))}
+			return nil
+		}
+
+		plannerTxn := p.InternalSQLTxn()
+		var sj *jobs.StartableJob
+		if err := func() (err error) {
+			defer func() {
+				if err == nil || sj == nil {
+					return
+				}
+				if cleanupErr := sj.CleanupOnRollback(ctx); cleanupErr != nil {
+					log.Errorf(ctx, "failed to cleanup job: %v", cleanupErr)
+				}
+			}()
+			jobID := p.ExecCfg().JobRegistry.MakeJobID()
+			if err := p.ExecCfg().JobRegistry.CreateStartableJobWithTxn(ctx, &sj, jobID, plannerTxn, jr); err != nil {
+				return err
+			}
+			// We commit the transaction here so that the job can be started. This
+			// is safe because we're in an implicit transaction.
+			return plannerTxn.KV().Commit(ctx)
+		}(); err != nil {
+			return err
+		}
+		p.InternalSQLTxn().Descriptors().ReleaseAll(ctx)
+		if err := sj.Start(ctx); err != nil {
+			return err
+		}
+		if err := sj.AwaitCompletion(ctx); err != nil {
+			return err
+		}
+		return sj.ReportExecutionResults(ctx, resultsCh)
+	}
+
+	if isDetached {
+		return fn, jobs.DetachedJobExecutionResultHeader, nil, false, nil
+	}
+	return fn, verifyBackupHeader, nil, false, nil
+}
+
+func init() {
+	sql.AddPlanHook("verify backup", verifyBackupPlanHook, verifyBackupTypeCheck)
+}
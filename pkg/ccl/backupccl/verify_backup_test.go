@@ -0,0 +1,104 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/testutils/jobutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyBackup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 100
+	_, sqlDB, tempDir, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `BACKUP TABLE data.bank INTO $1`, localFoo)
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1 WHERE id < 10`)
+	sqlDB.Exec(t, `DELETE FROM data.bank WHERE id >= 90`)
+	sqlDB.Exec(t, `BACKUP TABLE data.bank INTO LATEST IN $1`, localFoo)
+	sqlDB.Exec(t, `BACKUP TABLE data.bank INTO $1 WITH encryption_passphrase = 'abc'`, localFoo+"/enc")
+
+	// Changes made after the backups must not affect their fingerprints.
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1`)
+
+	verify := func(t *testing.T, query string, args ...interface{}) (keys, fingerprinted int) {
+		var (
+			jobID             int64
+			status            string
+			files, bytes, fps int
+		)
+		sqlDB.QueryRow(t, query, args...).Scan(&jobID, &status, &files, &keys, &bytes, &fps)
+		require.Equal(t, string(jobs.StatusSucceeded), status)
+		require.Greater(t, files, 0)
+		require.Greater(t, bytes, 0)
+		return keys, fps
+	}
+
+	t.Run("incremental", func(t *testing.T) {
+		keys, fps := verify(t, `VERIFY BACKUP LATEST IN $1`, localFoo)
+		require.GreaterOrEqual(t, keys, numAccounts)
+		require.Equal(t, 0, fps)
+
+		_, fps = verify(t, `VERIFY BACKUP LATEST IN $1 WITH fingerprint`, localFoo)
+		require.Greater(t, fps, 0)
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		_, fps := verify(t,
+			`VERIFY BACKUP LATEST IN $1 WITH fingerprint, encryption_passphrase = 'abc'`, localFoo+"/enc")
+		require.Greater(t, fps, 0)
+
+		sqlDB.ExpectErr(t, "failed to decrypt",
+			`VERIFY BACKUP LATEST IN $1 WITH encryption_passphrase = 'wrong'`, localFoo+"/enc")
+	})
+
+	t.Run("detached", func(t *testing.T) {
+		var jobID jobspb.JobID
+		sqlDB.QueryRow(t, `VERIFY BACKUP LATEST IN $1 WITH detached`, localFoo).Scan(&jobID)
+		jobutils.WaitForJobToSucceed(t, sqlDB, jobID)
+	})
+
+	t.Run("corrupt", func(t *testing.T) {
+		var ssts []string
+		require.NoError(t, filepath.Walk(filepath.Join(tempDir, "foo"), func(
+			path string, info os.FileInfo, err error,
+		) error {
+			if err != nil {
+				return err
+			}
+			if strings.HasSuffix(path, ".sst") && strings.Contains(path, "data") &&
+				!strings.Contains(path, "enc") {
+				ssts = append(ssts, path)
+			}
+			return nil
+		}))
+		require.NotEmpty(t, ssts)
+		for _, path := range ssts {
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			for i := range data[:len(data)/2] {
+				data[i] ^= 0xff
+			}
+			require.NoError(t, os.WriteFile(path, data, 0644))
+		}
+		sqlDB.ExpectErr(t, "checksum|corrupt|invalid", `VERIFY BACKUP LATEST IN $1`, localFoo)
+	})
+}
@@ -154,6 +154,23 @@ func alterChangefeedPlanHook(
 		}
 		newChangefeedStmt.Targets = newTargets
 
+		if cursorSet := alterChangefeedSetsOption(alterChangefeedStmt.Cmds, changefeedbase.OptCursor); cursorSet {
+			if alterChangefeedChangesTargets(alterChangefeedStmt.Cmds) {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					`cannot set %q while adding or dropping targets`, changefeedbase.OptCursor)
+			}
+			var cursor hlc.Timestamp
+			newProgress, cursor, err = rewindChangefeedProgress(
+				ctx, p, newOptions.GetCursor(), prevDetails, job.Progress(),
+			)
+			if err != nil {
+				return err
+			}
+			// Persist the resolved cursor, rather than, for instance, an interval
+			// relative to the time of this statement.
+			newOptions.SetCursor(eval.TimestampToDecimalDatum(cursor).Decimal.String())
+		}
+
 		for key, value := range newOptions.AsMap() {
 			opt := tree.KVOption{Key: tree.Name(key)}
 			if len(value) > 0 {
@@ -201,6 +218,11 @@ func alterChangefeedPlanHook(
 		// newStatementTime will either be the StatementTime of the job prior to the
 		// alteration, or it will be the high watermark of the job.
 		newDetails.StatementTime = newStatementTime
+		// The backup from which the initial scan is read can only be used as long
+		// as the scan is still done as of its end time.
+		if b := prevDetails.InitialScanBackup; b != nil && b.EndTime.Equal(newStatementTime) {
+			newDetails.InitialScanBackup = b
+		}
 
 		newPayload := job.Payload()
 		newPayload.Details = jobspb.WrapPayloadDetails(newDetails)
@@ -244,6 +266,130 @@ func alterChangefeedPlanHook(
 	return fn, alterChangefeedHeader, nil, false, nil
 }
 
+// alterChangefeedSetsOption returns true if any of the commands sets the
+// option.
+func alterChangefeedSetsOption(alterCmds tree.AlterChangefeedCmds, key string) bool {
+	for _, cmd := range alterCmds {
+		if v, ok := cmd.(*tree.AlterChangefeedSetOptions); ok {
+			for _, opt := range v.Options {
+				if string(opt.Key) == key {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// alterChangefeedChangesTargets returns true if any of the commands adds or
+// drops targets.
+func alterChangefeedChangesTargets(alterCmds tree.AlterChangefeedCmds) bool {
+	for _, cmd := range alterCmds {
+		switch cmd.(type) {
+		case *tree.AlterChangefeedAddTarget, *tree.AlterChangefeedDropTarget:
+			return true
+		}
+	}
+	return false
+}
+
+// rewindChangefeedProgress returns the resolved cursor along with the progress
+// of a changefeed whose high watermark is moved back to the cursor, so that the
+// changefeed re-emits all changes after the cursor when it is resumed. The
+// cursor must be between the start time of the changefeed and its current high
+// watermark, and must not be older than the data which is retained for the
+// targets of the changefeed. The protected timestamp record of the changefeed,
+// if any, is moved back to the cursor so that the data is not garbage
+// collected before the changefeed resumes.
+func rewindChangefeedProgress(
+	ctx context.Context,
+	p sql.PlanHookState,
+	cursor string,
+	details jobspb.ChangefeedDetails,
+	prevProgress jobspb.Progress,
+) (*jobspb.Progress, hlc.Timestamp, error) {
+	asOf, err := p.EvalAsOfTimestamp(ctx, tree.AsOfClause{Expr: tree.NewStrVal(cursor)})
+	if err != nil {
+		return nil, hlc.Timestamp{}, err
+	}
+	cursorTS := asOf.Timestamp
+
+	highWater := prevProgress.GetHighWater()
+	if highWater == nil || highWater.IsEmpty() {
+		return nil, hlc.Timestamp{}, pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			`cannot set %q before the changefeed has completed its initial scan`, changefeedbase.OptCursor)
+	}
+	if highWater.Less(cursorTS) {
+		return nil, hlc.Timestamp{}, pgerror.Newf(pgcode.InvalidParameterValue,
+			`cannot set %q to %s, which is after the high watermark %s of the changefeed`,
+			changefeedbase.OptCursor, cursorTS.AsOfSystemTime(), highWater.AsOfSystemTime())
+	}
+	if cursorTS.Less(details.StatementTime) {
+		return nil, hlc.Timestamp{}, errors.WithHint(pgerror.Newf(pgcode.InvalidParameterValue,
+			`cannot set %q to %s, which is before the start time %s of the changefeed`,
+			changefeedbase.OptCursor, cursorTS.AsOfSystemTime(), details.StatementTime.AsOfSystemTime()),
+			`consider creating a new changefeed with the cursor instead`)
+	}
+
+	var ptsRecord uuid.UUID
+	if changefeedProgress := prevProgress.GetChangefeed(); changefeedProgress != nil {
+		ptsRecord = changefeedProgress.ProtectedTimestampRecord
+	}
+	pts := p.ExecCfg().ProtectedTimestampProvider.WithTxn(p.InternalSQLTxn())
+	protected := false
+	if ptsRecord != uuid.Nil {
+		rec, err := pts.GetRecord(ctx, ptsRecord)
+		if err != nil {
+			return nil, hlc.Timestamp{}, err
+		}
+		protected = rec.Timestamp.LessEq(cursorTS)
+	}
+	// The changes after the cursor must not have been garbage collected. This
+	// is checked against the GC threshold of the targets' ranges, which only
+	// advances past data that isn't protected.
+	if err := checkCursorNotGarbageCollected(ctx, p, cursorTS, details); err != nil {
+		return nil, hlc.Timestamp{}, err
+	}
+	if ptsRecord != uuid.Nil && !protected {
+		if err := pts.UpdateTimestamp(ctx, ptsRecord, cursorTS); err != nil {
+			return nil, hlc.Timestamp{}, err
+		}
+	}
+
+	telemetry.Count(telemetryPath + `.cursor`)
+	return &jobspb.Progress{
+		Progress: &jobspb.Progress_HighWater{HighWater: &cursorTS},
+		Details: &jobspb.Progress_Changefeed{
+			Changefeed: &jobspb.ChangefeedProgress{
+				ProtectedTimestampRecord: ptsRecord,
+			},
+		},
+	}, cursorTS, nil
+}
+
+// checkCursorNotGarbageCollected returns an error if the history of any of the
+// tables targeted by the changefeed after the cursor may have been garbage
+// collected.
+func checkCursorNotGarbageCollected(
+	ctx context.Context, p sql.PlanHookState, cursor hlc.Timestamp, details jobspb.ChangefeedDetails,
+) error {
+	var spans roachpb.Spans
+	seen := make(map[descpb.ID]struct{}, len(details.TargetSpecifications))
+	for _, spec := range details.TargetSpecifications {
+		if _, ok := seen[spec.TableID]; ok {
+			continue
+		}
+		seen[spec.TableID] = struct{}{}
+		spans = append(spans, p.ExecCfg().Codec.TableSpan(uint32(spec.TableID)))
+	}
+	if err := checkNotGarbageCollected(ctx, p.ExecCfg(), spans, cursor); err != nil {
+		return pgerror.Wrapf(err, pgcode.InvalidParameterValue,
+			`cannot set %q to %s: changes since then may have been garbage collected`,
+			changefeedbase.OptCursor, cursor.AsOfSystemTime())
+	}
+	return nil
+}
+
 func getTargetDesc(
 	ctx context.Context,
 	p sql.PlanHookState,
@@ -329,7 +475,7 @@ func generateNewOpts(
 		case *tree.AlterChangefeedUnsetOptions:
 			optKeys := v.Options.ToStrings()
 			for _, key := range optKeys {
-				if key == changefeedbase.OptSink {
+				if key == changefeedbase.OptSink || key == changefeedbase.OptCursor {
 					return null, ``, pgerror.Newf(pgcode.InvalidParameterValue, `cannot unset option %q`, key)
 				}
 				if _, ok := changefeedbase.ChangefeedOptionExpectValues[key]; !ok {
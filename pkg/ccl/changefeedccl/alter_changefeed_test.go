@@ -1521,6 +1521,84 @@ func TestAlterChangefeedWithOldCursorFromCreateChangefeed(t *testing.T) {
 	cdcTest(t, testFn, feedTestEnterpriseSinks, feedTestNoExternalConnection)
 }
 
+func TestAlterChangefeedSetCursor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		registry := s.Server.JobRegistry().(*jobs.Registry)
+
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'before')`)
+
+		testFeed := feed(t, f, `CREATE CHANGEFEED FOR foo`)
+		defer closeFeed(t, testFeed)
+		assertPayloads(t, testFeed, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "before"}}`,
+		})
+
+		var tsLogical string
+		sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&tsLogical)
+		cursor := parseTimeToHLC(t, tsLogical)
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'after')`)
+		assertPayloads(t, testFeed, []string{
+			`foo: [2]->{"after": {"a": 2, "b": "after"}}`,
+		})
+
+		castedFeed, ok := testFeed.(cdctest.EnterpriseTestFeed)
+		require.True(t, ok)
+
+		var highWater hlc.Timestamp
+		testutils.SucceedsSoon(t, func() error {
+			progress := loadProgress(t, castedFeed, registry)
+			if hw := progress.GetHighWater(); hw != nil && cursor.Less(*hw) {
+				highWater = *hw
+				return nil
+			}
+			return errors.New("waiting for checkpoint advance")
+		})
+
+		sqlDB.Exec(t, `PAUSE JOB $1`, castedFeed.JobID())
+		waitForJobStatus(sqlDB, t, castedFeed.JobID(), `paused`)
+
+		sqlDB.ExpectErr(t,
+			`cannot set "cursor" to .*, which is after the high watermark`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d SET cursor = '%s'`,
+				castedFeed.JobID(), highWater.Next().AsOfSystemTime()),
+		)
+		sqlDB.ExpectErr(t,
+			`cannot unset option "cursor"`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d UNSET cursor`, castedFeed.JobID()),
+		)
+
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d SET cursor = '%s'`, castedFeed.JobID(), tsLogical))
+		progress := loadProgress(t, castedFeed, registry)
+		require.Equal(t, cursor, *progress.GetHighWater())
+
+		// Changes after the cursor are emitted again once the changefeed is
+		// resumed, and the job is not replaced.
+		sqlDB.Exec(t, fmt.Sprintf(`RESUME JOB %d`, castedFeed.JobID()))
+		waitForJobStatus(sqlDB, t, castedFeed.JobID(), `running`)
+		assertPayloads(t, testFeed, []string{
+			`foo: [2]->{"after": {"a": 2, "b": "after"}}`,
+		})
+
+		// Once the changes after the cursor have been garbage collected, the
+		// changefeed can no longer be rewound to it.
+		sqlDB.Exec(t, `PAUSE JOB $1`, castedFeed.JobID())
+		waitForJobStatus(sqlDB, t, castedFeed.JobID(), `paused`)
+		require.NoError(t, s.Server.ForceTableGC(context.Background(), "d", "foo", s.Server.Clock().Now()))
+		sqlDB.ExpectErr(t,
+			`cannot set "cursor" to .*: changes since then may have been garbage collected`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d SET cursor = '%s'`, castedFeed.JobID(), tsLogical),
+		)
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks, feedTestNoExternalConnection, feedTestNoTenants)
+}
+
 // TestChangefeedJobControl tests if a user can modify and existing changefeed
 // based on their privileges.
 func TestAlterChangefeedAccessControl(t *testing.T) {
@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
 	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
@@ -71,6 +72,10 @@ type batchingSink struct {
 	metrics metricsRecorder
 	knobs   batchingSinkKnobs
 
+	// dlq, if set, receives the messages of batches which the client
+	// permanently failed to flush.
+	dlq *deadLetterQueue
+
 	// eventCh is the channel used to send requests from the Sink caller routines
 	// to the batching routine.  Messages can either be a flushReq or a rowEvent.
 	eventCh chan interface{}
@@ -133,6 +138,12 @@ func (s *batchingSink) Flush(ctx context.Context) error {
 }
 
 var _ Sink = (*batchingSink)(nil)
+var _ deadLetterQueueSetter = (*batchingSink)(nil)
+
+// setDeadLetterQueue implements the deadLetterQueueSetter interface.
+func (s *batchingSink) setDeadLetterQueue(q *deadLetterQueue) {
+	s.dlq = q
+}
 
 // Event structs and batch structs which are transferred across routines (and
 // therefore escape to the heap) can both be incredibly frequent (every event
@@ -241,6 +252,11 @@ type sinkBatch struct {
 
 	alloc  kvevent.Alloc
 	hasher hash.Hash32
+
+	// messages retains the contents of the batch so that they can be sent to a
+	// dead letter queue should the batch fail to flush. It is only populated if
+	// the sink has a dead letter queue.
+	messages []deadLetter
 }
 
 // FinalizePayload closes the writer to produce a payload that is ready to be
@@ -270,7 +286,8 @@ func hashToInt(h hash.Hash32, buf []byte) int {
 }
 
 // Append adds the contents of a kvEvent to the batch, merging its alloc pool.
-func (sb *sinkBatch) Append(e *rowEvent) {
+// If retain is true, the event is also retained in the batch's messages.
+func (sb *sinkBatch) Append(e *rowEvent, retain bool) {
 	if sb.isEmpty() {
 		sb.bufferTime = timeutil.Now()
 	}
@@ -286,6 +303,15 @@ func (sb *sinkBatch) Append(e *rowEvent) {
 	}
 
 	sb.alloc.Merge(&e.alloc)
+
+	if retain {
+		sb.messages = append(sb.messages, deadLetter{
+			topic: e.topicDescriptor,
+			key:   e.key,
+			value: e.val,
+			mvcc:  e.mvcc,
+		})
+	}
 }
 
 func (s *batchingSink) handleError(err error) {
@@ -294,6 +320,20 @@ func (s *batchingSink) handleError(err error) {
 	}
 }
 
+// sendToDeadLetterQueue sends the messages of a batch which failed to flush
+// with the given error to the dead letter queue.
+func (s *batchingSink) sendToDeadLetterQueue(
+	ctx context.Context, batch *sinkBatch, flushErr error,
+) error {
+	for _, dl := range batch.messages {
+		dl.err = flushErr
+		if err := s.dlq.send(ctx, dl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *batchingSink) newBatchBuffer(topic string) *sinkBatch {
 	batch := newSinkBatch()
 	batch.buffer = s.client.MakeBatchBuffer(topic)
@@ -330,17 +370,23 @@ func (s *batchingSink) runBatchingWorker(ctx context.Context) {
 	handleResult := func(result *ioResult) {
 		batch, _ := result.request.(*sinkBatch)
 
-		if result.err != nil {
-			s.handleError(result.err)
-		} else {
+		err := result.err
+		if err != nil && s.dlq != nil && changefeedbase.IsPoisonMessageError(err) {
+			// The batch will never be accepted by the sink, so rather than failing
+			// the changefeed, its messages are sent to the dead letter queue.
+			err = s.sendToDeadLetterQueue(ctx, batch, err)
+		} else if err == nil {
 			s.metrics.recordEmittedBatch(
 				batch.bufferTime, batch.numMessages, batch.mvcc, batch.numKVBytes, sinkDoesNotCompress,
 			)
 		}
+		if err != nil {
+			s.handleError(err)
+		}
 
 		inflight -= batch.numMessages
 
-		if (result.err != nil || inflight == 0) && sinkFlushWaiter != nil {
+		if (err != nil || inflight == 0) && sinkFlushWaiter != nil {
 			close(sinkFlushWaiter)
 			sinkFlushWaiter = nil
 		}
@@ -438,7 +484,7 @@ func (s *batchingSink) runBatchingWorker(ctx context.Context) {
 					topicBatches[topic] = batchBuffer
 				}
 
-				batchBuffer.Append(r)
+				batchBuffer.Append(r, s.dlq != nil)
 				if s.knobs.OnAppend != nil {
 					s.knobs.OnAppend(r)
 				}
@@ -0,0 +1,187 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package cdceval
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/valueside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/lib/pq/oid"
+)
+
+// The limits below match those enforced by the rangefeed server on the
+// filters it accepts; predicates beyond them are not pushed down.
+const (
+	maxColumnFilterPredicates   = 64
+	maxColumnFilterOperandBytes = 4 << 10
+)
+
+// ColumnFilterForExpression returns a filter which rangefeed servers can apply
+// to the rows of the target table before sending them to the changefeed, or
+// nil if no part of the WHERE clause of the normalized select clause can be
+// evaluated by the servers.
+//
+// The filter contains the top level conjuncts of the WHERE clause which
+// compare a stored, non primary key column of the target family with a
+// constant, or test it for NULL. Rows it filters out would therefore also be
+// filtered out by the changefeed expression; the changefeed still evaluates
+// the whole expression on the rows it receives, since servers may not apply
+// the filter.
+func ColumnFilterForExpression(
+	ctx context.Context,
+	descr catalog.TableDescriptor,
+	target jobspb.ChangefeedTargetSpecification,
+	sc *tree.SelectClause,
+) *kvpb.RangeFeedColumnFilter {
+	if sc.Where == nil {
+		return nil
+	}
+	family, err := getTargetFamilyDescriptor(descr, target)
+	if err != nil {
+		return nil
+	}
+	famCols := catalog.MakeTableColSet(family.ColumnIDs...)
+	keyCols := descr.GetPrimaryIndex().CollectKeyColumnIDs()
+
+	// column returns the column referenced by expr, if it is one whose value
+	// the rangefeed server can find in the row's value and compare exactly
+	// like SQL does.
+	column := func(expr tree.Expr) catalog.Column {
+		name, ok := expr.(*tree.UnresolvedName)
+		if !ok || name.Star || name.NumParts > 2 {
+			return nil
+		}
+		col := catalog.FindColumnByName(descr, name.Parts[0])
+		if col == nil || !col.Public() || col.IsVirtual() ||
+			!famCols.Contains(col.GetID()) || keyCols.Contains(col.GetID()) {
+			return nil
+		}
+		return col
+	}
+
+	f := &kvpb.RangeFeedColumnFilter{FamilyID: uint32(family.ID)}
+	for _, expr := range splitConjuncts(sc.Where.Expr, nil) {
+		if len(f.Predicates) == maxColumnFilterPredicates {
+			break
+		}
+		switch e := expr.(type) {
+		case *tree.IsNullExpr:
+			if col := column(e.Expr); col != nil {
+				f.Predicates = append(f.Predicates, kvpb.RangeFeedColumnPredicate{
+					ColumnID: uint32(col.GetID()), Op: kvpb.RangeFeedColumnPredicate_IS_NULL,
+				})
+			}
+		case *tree.IsNotNullExpr:
+			if col := column(e.Expr); col != nil {
+				f.Predicates = append(f.Predicates, kvpb.RangeFeedColumnPredicate{
+					ColumnID: uint32(col.GetID()), Op: kvpb.RangeFeedColumnPredicate_IS_NOT_NULL,
+				})
+			}
+		case *tree.ComparisonExpr:
+			op, ok := comparisonOps[e.Operator.Symbol]
+			if !ok {
+				continue
+			}
+			col, val := column(e.Left), e.Right
+			if col == nil {
+				// Flip the comparison so that the column is on the left.
+				col, val = column(e.Right), e.Left
+				op = flippedComparisonOps[op]
+			}
+			if col == nil || !comparableType(col.GetType()) {
+				continue
+			}
+			operand, ok := encodeConstant(ctx, val, col.GetType())
+			if !ok {
+				continue
+			}
+			f.Predicates = append(f.Predicates, kvpb.RangeFeedColumnPredicate{
+				ColumnID: uint32(col.GetID()), Op: op, Operand: operand,
+			})
+		}
+	}
+	if len(f.Predicates) == 0 {
+		return nil
+	}
+	return f
+}
+
+var comparisonOps = map[treecmp.ComparisonOperatorSymbol]kvpb.RangeFeedColumnPredicate_Op{
+	treecmp.EQ: kvpb.RangeFeedColumnPredicate_EQ,
+	treecmp.NE: kvpb.RangeFeedColumnPredicate_NE,
+	treecmp.LT: kvpb.RangeFeedColumnPredicate_LT,
+	treecmp.LE: kvpb.RangeFeedColumnPredicate_LE,
+	treecmp.GT: kvpb.RangeFeedColumnPredicate_GT,
+	treecmp.GE: kvpb.RangeFeedColumnPredicate_GE,
+}
+
+// flippedComparisonOps maps each operator to the one which holds when its
+// operands are swapped.
+var flippedComparisonOps = map[kvpb.RangeFeedColumnPredicate_Op]kvpb.RangeFeedColumnPredicate_Op{
+	kvpb.RangeFeedColumnPredicate_EQ: kvpb.RangeFeedColumnPredicate_EQ,
+	kvpb.RangeFeedColumnPredicate_NE: kvpb.RangeFeedColumnPredicate_NE,
+	kvpb.RangeFeedColumnPredicate_LT: kvpb.RangeFeedColumnPredicate_GT,
+	kvpb.RangeFeedColumnPredicate_LE: kvpb.RangeFeedColumnPredicate_GE,
+	kvpb.RangeFeedColumnPredicate_GT: kvpb.RangeFeedColumnPredicate_LT,
+	kvpb.RangeFeedColumnPredicate_GE: kvpb.RangeFeedColumnPredicate_LE,
+}
+
+// splitConjuncts appends the top level conjuncts of expr to exprs.
+func splitConjuncts(expr tree.Expr, exprs []tree.Expr) []tree.Expr {
+	switch e := expr.(type) {
+	case *tree.AndExpr:
+		return splitConjuncts(e.Right, splitConjuncts(e.Left, exprs))
+	case *tree.ParenExpr:
+		return splitConjuncts(e.Expr, exprs)
+	}
+	return append(exprs, expr)
+}
+
+// comparableType returns whether the rangefeed server compares the value
+// encoding of columns of type typ the same way SQL compares their datums.
+// Notably, collated strings, CHAR(n) and enums are all value encoded as bytes,
+// but don't compare bytewise.
+func comparableType(typ *types.T) bool {
+	switch typ.Family() {
+	case types.IntFamily, types.FloatFamily, types.BoolFamily, types.BytesFamily:
+		return true
+	case types.StringFamily:
+		return typ.Oid() == oid.T_text || typ.Oid() == oid.T_varchar
+	}
+	return false
+}
+
+// encodeConstant returns the value encoding of expr, without a column ID, if
+// it is a constant of type typ.
+func encodeConstant(ctx context.Context, expr tree.Expr, typ *types.T) ([]byte, bool) {
+	switch expr.(type) {
+	case tree.Constant, tree.Datum:
+	default:
+		return nil, false
+	}
+	typed, err := tree.TypeCheckAndRequire(ctx, expr, nil /* semaCtx */, typ, "cdc filter")
+	if err != nil {
+		return nil, false
+	}
+	d, ok := typed.(tree.Datum)
+	if !ok || d == tree.DNull {
+		return nil, false
+	}
+	operand, err := valueside.Encode(nil, valueside.NoColumnID, d, nil /* scratch */)
+	if err != nil || len(operand) > maxColumnFilterOperandBytes {
+		return nil, false
+	}
+	return operand, true
+}
@@ -70,6 +70,19 @@ consumed, we can determine if the row ought to be filtered.  This step is done t
 ensure that we correctly release resources for each event -- even the ones that
 are filtered out.
 
+Sub-queries are not supported.  Instead, events can be enriched with rows from
+other tables via the cdc_lookup function:
+  SELECT *, cdc_lookup('customers', 'id', customer_id)->>'region' AS region FROM orders
+cdc_lookup reads the matching row, as a JSONB object, as of the MVCC timestamp
+of the event, using the privileges of the user who created the changefeed.
+Rows are cached by each aggregator (see lookupTable), bounded by the
+changefeed.lookup_cache_size setting, so that lookups of the same key by
+nearby events do not each issue a read.  The table and column must be constant
+strings, and the column must have a unique index, so that the looked up tables
+can be resolved when the changefeed is created: they are protected from GC
+along with the changefeed's targets, and are read by the descriptor ID they
+were resolved to, regardless of later renames.
+
 Virtual computed columns can be easily supported but currently are not.
 To support virtual computed columns we must ensure that the expression in that
 column references only the target changefeed column family.
@@ -43,6 +43,10 @@ type Evaluator struct {
 	sessionData *sessiondata.SessionData
 	withDiff    bool
 	familyEval  map[descpb.FamilyID]*familyEvaluator
+
+	// lookups is shared by the evaluators of each family so that rows read by
+	// cdc_lookup are cached once per changefeed aggregator.
+	lookups *lookupTable
 }
 
 // familyEvaluator is a responsible for evaluating expressions in CDC
@@ -92,6 +96,7 @@ func NewEvaluator(
 		statementTS: statementTS,
 		withDiff:    withDiff,
 		familyEval:  make(map[descpb.FamilyID]*familyEvaluator, 1), // usually, just 1 family.
+		lookups:     newLookupTable(execCfg, user, sd),
 	}
 }
 
@@ -104,6 +109,7 @@ func newFamilyEvaluator(
 	sd *sessiondata.SessionData,
 	statementTS hlc.Timestamp,
 	withDiff bool,
+	lookups *lookupTable,
 ) *familyEvaluator {
 	e := familyEvaluator{
 		targetFamilyID: targetFamilyID,
@@ -117,6 +123,7 @@ func newFamilyEvaluator(
 	}
 	e.rowEvalCtx.startTime = statementTS
 	e.rowEvalCtx.withDiff = withDiff
+	e.rowEvalCtx.lookups = lookups
 
 	// Arrange to be notified when event does not match predicate.
 	predicateAsProjection(e.norm)
@@ -150,6 +157,7 @@ func (e *Evaluator) Eval(
 	if !ok {
 		fe = newFamilyEvaluator(
 			e.sc, updatedRow.FamilyID, e.execCfg, e.user, e.sessionData, e.statementTS, e.withDiff,
+			e.lookups,
 		)
 		e.familyEval[updatedRow.FamilyID] = fe
 	}
@@ -511,6 +519,7 @@ type rowEvalContext struct {
 	withDiff   bool
 	updatedRow cdcevent.Row
 	op         tree.Datum
+	lookups    *lookupTable
 }
 
 // cdcAnnotationAddr is the address used to store relevant information
@@ -125,6 +125,29 @@ var cdcFunctions = map[string]*tree.ResolvedFunctionDefinition{
 			return rowEvalCtx.startTime
 		},
 	),
+	"cdc_lookup": makeCDCBuiltIn(
+		"cdc_lookup",
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "table", Typ: types.String},
+				{Name: "column", Typ: types.String},
+				{Name: "key", Typ: types.Any},
+			},
+			ReturnType: tree.FixedReturnType(types.Jsonb),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				if args[0] == tree.DNull || args[1] == tree.DNull {
+					return tree.DNull, nil
+				}
+				rowEvalCtx := rowEvalContextFromEvalContext(evalCtx)
+				return rowEvalCtx.lookups.lookup(
+					ctx, string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1])),
+					args[2], rowEvalCtx.updatedRow.MvccTimestamp,
+				)
+			},
+			Info: "Returns the row of table whose column is equal to key as of the MVCC " +
+				"timestamp of the event, as a JSONB object, or NULL if there is no such row.",
+			Volatility: volatility.Volatile,
+		}),
 }
 
 var (
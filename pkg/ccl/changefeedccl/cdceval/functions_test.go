@@ -246,6 +246,48 @@ func TestEvaluatesCDCFunctionOverloads(t *testing.T) {
 		}
 	})
 
+	t.Run("cdc_lookup", func(t *testing.T) {
+		sqlDB.Exec(t, "CREATE TABLE customers (id INT PRIMARY KEY, region STRING)")
+		schemaTS := s.Clock().Now()
+		row := makeEventRow(t, desc, schemaTS, false, s.Clock().Now(), false)
+		id := tree.AsStringWithFlags(row.EncDatums()[0].Datum, tree.FmtParsable)
+
+		sqlDB.Exec(t, fmt.Sprintf("INSERT INTO customers VALUES (%s, 'us-east1')", id))
+		before := cdcevent.TestingMakeEventRow(desc, 0, row.EncDatums(), false)
+		before.SchemaTS, before.MvccTimestamp = schemaTS, s.Clock().Now()
+		sqlDB.Exec(t, fmt.Sprintf("UPDATE customers SET region = 'eu-west1' WHERE id = %s", id))
+		after := cdcevent.TestingMakeEventRow(desc, 0, row.EncDatums(), false)
+		after.SchemaTS, after.MvccTimestamp = schemaTS, s.Clock().Now()
+
+		// Lookups read the table by the ID it was resolved to, so renaming it
+		// and creating another table with its name does not affect them.
+		customersID := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "customers").GetID()
+		sqlDB.Exec(t, "ALTER TABLE customers RENAME TO old_customers")
+		sqlDB.Exec(t, "CREATE TABLE customers (id INT PRIMARY KEY, region STRING)")
+		sqlDB.Exec(t, fmt.Sprintf("INSERT INTO customers VALUES (%s, 'ap-south1')", id))
+
+		e, err := newEvaluator(&execCfg, &semaCtx, row.EventDescriptor, false, fmt.Sprintf(
+			"SELECT cdc_lookup('%s', 'id', a)->>'region' AS region FROM foo", lookupTableRef(customersID)))
+		require.NoError(t, err)
+		defer e.Close()
+
+		// The lookup is evaluated as of the MVCC timestamp of each event,
+		// whether or not the row was read from the cache.
+		for _, tc := range []struct {
+			row    cdcevent.Row
+			expect string
+		}{
+			{row: row, expect: "NULL"},
+			{row: before, expect: "us-east1"},
+			{row: after, expect: "eu-west1"},
+			{row: before, expect: "us-east1"},
+		} {
+			p, err := e.Eval(ctx, tc.row, cdcevent.Row{})
+			require.NoError(t, err)
+			require.Equal(t, map[string]string{"region": tc.expect}, slurpValues(t, p))
+		}
+	})
+
 	mustParseJSON := func(d tree.Datum) jsonb.JSON {
 		t.Helper()
 		j, err := tree.AsJSON(d, sessiondatapb.DataConversionConfig{}, time.UTC)
@@ -372,8 +414,9 @@ func TestEvaluatesCDCFunctionOverloads(t *testing.T) {
 	// error is returned when cdc function called with wrong arguments.
 	t.Run("cdc function errors", func(t *testing.T) {
 		testRow := makeEventRow(t, desc, s.Clock().Now(), false, s.Clock().Now(), false)
-		// currently, all cdc functions take no args, so call these functions with
-		// some arguments.
+		// currently, all cdc functions other than cdc_lookup take no args, so call
+		// these functions with some arguments. cdc_lookup takes a string as its
+		// first argument, which none of these arguments are.
 		rng, _ := randutil.NewTestRand()
 		fnArgs := func() string {
 			switch rng.Int31n(3) {
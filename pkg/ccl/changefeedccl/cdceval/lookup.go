@@ -0,0 +1,298 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package cdceval
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// lookupTable reads rows from tables other than the changefeed's target on
+// behalf of the cdc_lookup function.
+//
+// Each lookup reads the row whose column matches the key as of the MVCC
+// timestamp of the event being evaluated, as the user who created the
+// changefeed. Tables are read by the descriptor ID they were resolved to when
+// the changefeed was created, so that renaming a table, or replacing it with
+// another of the same name, does not change which table is read. Since events for the same key are often evaluated close in time
+// to each other, rows are cached along with the interval of time during which
+// they are known to be unchanged: a row read at timestamp T which was last
+// modified at timestamp M may be served from the cache to any lookup at a
+// timestamp in [M, T].
+//
+// lookupTable is safe for concurrent use.
+type lookupTable struct {
+	execCfg     *sql.ExecutorConfig
+	user        username.SQLUsername
+	database    string
+	searchPath  *sessiondata.SearchPath
+	maxCacheLen int
+
+	mu struct {
+		syncutil.Mutex
+		cache *cache.UnorderedCache
+	}
+}
+
+// lookupKey identifies the row read by a lookup.
+type lookupKey struct {
+	table       descpb.ID
+	column, key string
+}
+
+// lookupEntry is a row read by a lookup, which is known not to have changed
+// between validFrom and readAt.
+type lookupEntry struct {
+	row       tree.Datum
+	validFrom hlc.Timestamp
+	readAt    hlc.Timestamp
+}
+
+func newLookupTable(
+	execCfg *sql.ExecutorConfig, user username.SQLUsername, sd *sessiondata.SessionData,
+) *lookupTable {
+	l := &lookupTable{
+		execCfg:    execCfg,
+		user:       user,
+		database:   sd.Database,
+		searchPath: &sd.SearchPath,
+	}
+	l.maxCacheLen = int(changefeedbase.LookupCacheSize.Get(&execCfg.Settings.SV))
+	l.mu.cache = cache.NewUnorderedCache(cache.Config{
+		Policy: cache.CacheLRU,
+		ShouldEvict: func(size int, _, _ interface{}) bool {
+			return size > l.maxCacheLen
+		},
+	})
+	return l
+}
+
+// lookup returns the row of table, as a JSONB object, whose column is equal
+// to key as of ts. Returns DNull if there is no such row.
+func (l *lookupTable) lookup(
+	ctx context.Context, table, column string, key tree.Datum, ts hlc.Timestamp,
+) (tree.Datum, error) {
+	if key == tree.DNull {
+		return tree.DNull, nil
+	}
+	id, ok := parseLookupTableRef(table)
+	if !ok {
+		return nil, errors.AssertionFailedf("cdc_lookup table %q was not resolved to an ID", table)
+	}
+	k := lookupKey{table: id, column: column, key: tree.AsStringWithFlags(key, tree.FmtParsable)}
+	if row, ok := l.getCached(k, ts); ok {
+		return row, nil
+	}
+
+	stmt := fmt.Sprintf(
+		`SELECT row_to_json(t.*), t.crdb_internal_mvcc_timestamp FROM [%d AS t] AS OF SYSTEM TIME %s WHERE t.%s = $1`,
+		id, ts.AsOfSystemTime(), tree.NameString(column),
+	)
+	datums, err := l.execCfg.InternalDB.Executor().QueryRowEx(
+		ctx, "cdc-lookup", nil, /* txn */
+		sessiondata.InternalExecutorOverride{
+			User:       l.user,
+			Database:   l.database,
+			SearchPath: l.searchPath,
+		},
+		stmt, key,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "looking up %s in %s", column, table)
+	}
+
+	entry := lookupEntry{row: tree.DNull, validFrom: ts, readAt: ts}
+	if datums != nil {
+		entry.row = datums[0]
+		if d, ok := datums[1].(*tree.DDecimal); ok {
+			if entry.validFrom, err = hlc.DecimalToHLC(&d.Decimal); err != nil {
+				return nil, err
+			}
+		}
+	}
+	l.putCached(k, entry)
+	return entry.row, nil
+}
+
+// resolveLookupTables returns the IDs of the tables read by cdc_lookup calls
+// in the select clause. The table and column of each call must be constant
+// strings, so that the tables can be protected from GC along with the
+// changefeed's targets, and the column must be the sole key column of a unique
+// index, so that a lookup reads at most one row.
+//
+// The table of each call is rewritten in place to a numeric table reference,
+// such as '[104]', so that the changefeed keeps reading the table which was
+// resolved here. Tables which are already numeric references, as when an
+// altered changefeed's expression is normalized again, are looked up by ID.
+func resolveLookupTables(
+	ctx context.Context, execCtx sql.JobExecContext, sc *tree.SelectClause,
+) (descpb.IDs, error) {
+	var calls []*tree.FuncExpr
+	if _, err := tree.SimpleStmtVisit(
+		sc,
+		func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+			if f, ok := expr.(*tree.FuncExpr); ok && f.Func.String() == "cdc_lookup" {
+				calls = append(calls, f)
+			}
+			return true, expr, nil
+		},
+	); err != nil {
+		return nil, err
+	}
+	if len(calls) == 0 {
+		return nil, nil
+	}
+
+	var ids descpb.IDs
+	seen := make(map[descpb.ID]struct{})
+	for _, f := range calls {
+		if len(f.Exprs) != 3 {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"cdc_lookup expects 3 arguments, found %d", len(f.Exprs))
+		}
+		table, ok := constantString(f.Exprs[0])
+		if !ok {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"cdc_lookup table must be a constant string, found %s", tree.AsString(f.Exprs[0]))
+		}
+		column, ok := constantString(f.Exprs[1])
+		if !ok {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"cdc_lookup column must be a constant string, found %s", tree.AsString(f.Exprs[1]))
+		}
+
+		desc, err := resolveLookupTable(ctx, execCtx, table)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkUniqueLookupColumn(desc, column); err != nil {
+			return nil, err
+		}
+		f.Exprs[0] = tree.NewDString(lookupTableRef(desc.GetID()))
+		if _, ok := seen[desc.GetID()]; !ok {
+			seen[desc.GetID()] = struct{}{}
+			ids = append(ids, desc.GetID())
+		}
+	}
+	return ids, nil
+}
+
+// resolveLookupTable returns the descriptor of the table named by the table
+// argument of a cdc_lookup call, which is either a table name or a numeric
+// table reference.
+func resolveLookupTable(
+	ctx context.Context, execCtx sql.JobExecContext, table string,
+) (catalog.TableDescriptor, error) {
+	if id, ok := parseLookupTableRef(table); ok {
+		txn := execCtx.(sql.PlanHookState).InternalSQLTxn()
+		return txn.Descriptors().ByID(txn.KV()).WithoutNonPublic().Get().Table(ctx, id)
+	}
+	tn, err := parser.ParseQualifiedTableName(table)
+	if err != nil {
+		return nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid table name %q", table)
+	}
+	_, desc, err := resolver.ResolveExistingTableObject(
+		ctx, execCtx.(resolver.SchemaResolver), tn, tree.ObjectLookupFlags{
+			Required:          true,
+			DesiredObjectKind: tree.TableObject,
+		})
+	return desc, err
+}
+
+// lookupTableRef returns the numeric table reference to which the table
+// argument of cdc_lookup calls is resolved.
+func lookupTableRef(id descpb.ID) string {
+	return fmt.Sprintf("[%d]", id)
+}
+
+// parseLookupTableRef returns the ID of a numeric table reference returned by
+// lookupTableRef.
+func parseLookupTableRef(table string) (descpb.ID, bool) {
+	if !strings.HasPrefix(table, "[") || !strings.HasSuffix(table, "]") {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(table[1:len(table)-1], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return descpb.ID(id), true
+}
+
+// checkUniqueLookupColumn returns an error unless column is the sole key
+// column of a unique, non-partial index of desc.
+func checkUniqueLookupColumn(desc catalog.TableDescriptor, column string) error {
+	col, err := catalog.MustFindColumnByName(desc, column)
+	if err != nil {
+		return err
+	}
+	for _, idx := range desc.ActiveIndexes() {
+		if !idx.IsUnique() || idx.IsPartial() {
+			continue
+		}
+		start := idx.ExplicitColumnStartIdx()
+		if idx.NumKeyColumns()-start == 1 && idx.GetKeyColumnID(start) == col.GetID() {
+			return nil
+		}
+	}
+	return pgerror.Newf(pgcode.InvalidParameterValue,
+		"cdc_lookup column %s of table %s must have a unique index", column, desc.GetName())
+}
+
+// constantString returns the value of expr if it is a string constant.
+func constantString(expr tree.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *tree.StrVal:
+		return e.RawString(), true
+	case *tree.DString:
+		return string(*e), true
+	default:
+		return "", false
+	}
+}
+
+func (l *lookupTable) getCached(k lookupKey, ts hlc.Timestamp) (tree.Datum, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	v, ok := l.mu.cache.Get(k)
+	if !ok {
+		return nil, false
+	}
+	e := v.(lookupEntry)
+	if ts.Less(e.validFrom) || e.readAt.Less(ts) {
+		return nil, false
+	}
+	return e.row, true
+}
+
+func (l *lookupTable) putCached(k lookupKey, e lookupEntry) {
+	if l.maxCacheLen == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.mu.cache.Add(k, e)
+}
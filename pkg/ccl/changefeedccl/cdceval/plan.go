@@ -72,6 +72,9 @@ func normalizeExpression(
 	if err != nil {
 		return nil, false, changefeedbase.WithTerminalError(err)
 	}
+	if norm.lookupTables, err = resolveLookupTables(ctx, execCtx, norm.SelectClause); err != nil {
+		return nil, false, changefeedbase.WithTerminalError(err)
+	}
 
 	defer configSemaForCDC(execCtx.SemaCtx())()
 
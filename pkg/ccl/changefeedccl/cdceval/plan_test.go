@@ -10,6 +10,7 @@ package cdceval
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
@@ -289,6 +290,115 @@ FAMILY extra (extra)
 	}
 }
 
+func TestNormalizeResolvesLookupTables(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(context.Background())
+	s := srv.ApplicationLayer()
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.ExecMultiple(t,
+		`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`,
+		`CREATE TABLE customers (
+id INT PRIMARY KEY,
+email STRING UNIQUE,
+region STRING,
+INDEX (region),
+UNIQUE INDEX (region, email),
+UNIQUE INDEX (email) WHERE region IS NOT NULL
+)`,
+		`CREATE TABLE regions (name STRING PRIMARY KEY)`,
+	)
+
+	execCfg := s.ExecutorConfig().(sql.ExecutorConfig)
+	fooDesc := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "foo")
+	customersID := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "customers").GetID()
+	regionsID := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "regions").GetID()
+	target := jobspb.ChangefeedTargetSpecification{
+		TableID:           fooDesc.GetID(),
+		StatementTimeName: fooDesc.GetName(),
+	}
+	ctx := context.Background()
+	schemaTS := s.Clock().Now()
+
+	for _, tc := range []struct {
+		name      string
+		stmt      string
+		expectIDs descpb.IDs
+		expectErr string
+	}{
+		{
+			name: "no lookups",
+			stmt: "SELECT * FROM foo",
+		},
+		{
+			name:      "primary key",
+			stmt:      "SELECT cdc_lookup('customers', 'id', a) AS c FROM foo",
+			expectIDs: descpb.IDs{customersID},
+		},
+		{
+			name:      "unique column",
+			stmt:      "SELECT cdc_lookup('customers', 'email', b) AS c FROM foo",
+			expectIDs: descpb.IDs{customersID},
+		},
+		{
+			name: "multiple tables",
+			stmt: "SELECT cdc_lookup('customers', 'id', a) AS c, " +
+				"cdc_lookup('public.customers', 'email', b) AS e, " +
+				"cdc_lookup('regions', 'name', b) AS r FROM foo",
+			expectIDs: descpb.IDs{customersID, regionsID},
+		},
+		{
+			name:      "table ID",
+			stmt:      fmt.Sprintf("SELECT cdc_lookup('[%d]', 'id', a) AS c FROM foo", customersID),
+			expectIDs: descpb.IDs{customersID},
+		},
+		{
+			name:      "non-unique column",
+			stmt:      "SELECT cdc_lookup('customers', 'region', b) AS c FROM foo",
+			expectErr: "cdc_lookup column region of table customers must have a unique index",
+		},
+		{
+			name:      "unknown column",
+			stmt:      "SELECT cdc_lookup('customers', 'nope', b) AS c FROM foo",
+			expectErr: `column "nope" does not exist`,
+		},
+		{
+			name:      "unknown table",
+			stmt:      "SELECT cdc_lookup('nope', 'id', a) AS c FROM foo",
+			expectErr: `relation "nope" does not exist`,
+		},
+		{
+			name:      "non-constant table",
+			stmt:      "SELECT cdc_lookup(b, 'id', a) AS c FROM foo",
+			expectErr: "cdc_lookup table must be a constant string",
+		},
+		{
+			name:      "non-constant column",
+			stmt:      "SELECT cdc_lookup('customers', b, a) AS c FROM foo",
+			expectErr: "cdc_lookup column must be a constant string",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sc, err := ParseChangefeedExpression(tc.stmt)
+			require.NoError(t, err)
+
+			norm, _, _, err := normalizeAndPlan(ctx, &execCfg, username.RootUserName(),
+				defaultDBSessionData, fooDesc, schemaTS, target, sc, false /* splitFams */)
+			if tc.expectErr != "" {
+				require.Regexp(t, tc.expectErr, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectIDs, norm.LookupTableIDs())
+			// Every lookup is rewritten to read its table by ID.
+			require.NotRegexp(t, `cdc_lookup\('[^\[]`, AsStringUnredacted(norm))
+		})
+	}
+}
+
 func mkPkKey(t *testing.T, codec keys.SQLCodec, tableID descpb.ID, vals ...int) roachpb.Key {
 	t.Helper()
 
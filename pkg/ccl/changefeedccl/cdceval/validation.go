@@ -31,7 +31,14 @@ import (
 // normalized input aren't called out of order.
 type NormalizedSelectClause struct {
 	*tree.SelectClause
-	desc *cdcevent.EventDescriptor
+	desc         *cdcevent.EventDescriptor
+	lookupTables descpb.IDs
+}
+
+// LookupTableIDs returns the IDs of the tables read by cdc_lookup calls in
+// this expression.
+func (n *NormalizedSelectClause) LookupTableIDs() descpb.IDs {
+	return n.lookupTables
 }
 
 // SelectStatementForFamily returns tree.Select representing this object.
@@ -296,8 +303,9 @@ func normalizeSelectClause(
 				}
 				return true, expr, nil
 			case *tree.Subquery:
-				return false, e, pgerror.New(
-					pgcode.FeatureNotSupported, "sub-query expressions not supported by CDC")
+				return false, e, errors.WithHint(pgerror.New(
+					pgcode.FeatureNotSupported, "sub-query expressions not supported by CDC"),
+					"use cdc_lookup() to read rows from other tables")
 			default:
 				return true, expr, nil
 			}
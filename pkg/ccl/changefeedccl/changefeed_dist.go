@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobsprofiler"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql"
@@ -215,6 +216,39 @@ func fetchSpansForTables(
 		sd, tableDescs[0], initialHighwater, target, sc)
 }
 
+// columnFilterForTables returns the filter which the rangefeed servers apply
+// to the rows of the changefeed's table on its behalf, if details.Select has
+// a predicate they can evaluate.
+func columnFilterForTables(
+	ctx context.Context,
+	execCtx sql.JobExecContext,
+	tableDescs []catalog.TableDescriptor,
+	details jobspb.ChangefeedDetails,
+) (*kvpb.RangeFeedColumnFilter, error) {
+	if details.Select == "" || len(tableDescs) != 1 ||
+		!changefeedbase.PushDownFilters.Get(&execCtx.ExecCfg().Settings.SV) {
+		return nil, nil
+	}
+	// The filter refers to columns by ID. If the schema changes, the changefeed
+	// keeps evaluating the expression by column name, so don't push the
+	// filter down if schema changes aren't observed (see kvfeed).
+	opts := changefeedbase.MakeStatementOptions(details.Opts)
+	schemaChange, err := opts.GetSchemaChangeHandlingOptions()
+	if err != nil {
+		return nil, err
+	}
+	if schemaChange.Policy == changefeedbase.OptSchemaChangePolicyIgnore {
+		return nil, nil
+	}
+	sc, err := cdceval.ParseChangefeedExpression(details.Select)
+	if err != nil {
+		return nil, pgerror.Wrap(err, pgcode.InvalidParameterValue,
+			"could not parse changefeed expression")
+	}
+	return cdceval.ColumnFilterForExpression(
+		ctx, tableDescs[0], details.TargetSpecifications[0], sc), nil
+}
+
 // startDistChangefeed starts distributed changefeed execution.
 func startDistChangefeed(
 	ctx context.Context,
@@ -240,6 +274,10 @@ func startDistChangefeed(
 		return err
 	}
 	localState.trackedSpans = trackedSpans
+	columnFilter, err := columnFilterForTables(ctx, execCtx, tableDescs, details)
+	if err != nil {
+		return err
+	}
 
 	// Changefeed flows handle transactional consistency themselves.
 	var noTxn *kv.Txn
@@ -252,7 +290,7 @@ func startDistChangefeed(
 		checkpoint = progress.Checkpoint
 	}
 	p, planCtx, err := makePlan(execCtx, jobID, details, initialHighWater,
-		trackedSpans, checkpoint, localState.drainingNodes)(ctx, dsp)
+		trackedSpans, columnFilter, checkpoint, localState.drainingNodes)(ctx, dsp)
 	if err != nil {
 		return err
 	}
@@ -330,6 +368,7 @@ func makePlan(
 	details jobspb.ChangefeedDetails,
 	initialHighWater hlc.Timestamp,
 	trackedSpans []roachpb.Span,
+	columnFilter *kvpb.RangeFeedColumnFilter,
 	checkpoint *jobspb.ChangefeedProgress_Checkpoint,
 	drainingNodes []roachpb.NodeID,
 ) func(context.Context, *sql.DistSQLPlanner) (*sql.PhysicalPlan, *sql.PlanningCtx, error) {
@@ -413,12 +452,13 @@ func makePlan(
 			}
 
 			aggregatorSpecs[i] = &execinfrapb.ChangeAggregatorSpec{
-				Watches:    watches,
-				Checkpoint: aggregatorCheckpoint,
-				Feed:       details,
-				UserProto:  execCtx.User().EncodeProto(),
-				JobID:      jobID,
-				Select:     execinfrapb.Expression{Expr: details.Select},
+				Watches:      watches,
+				Checkpoint:   aggregatorCheckpoint,
+				Feed:         details,
+				UserProto:    execCtx.User().EncodeProto(),
+				JobID:        jobID,
+				Select:       execinfrapb.Expression{Expr: details.Select},
+				ColumnFilter: columnFilter,
 			}
 		}
 
@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcutils"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
@@ -24,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
@@ -292,6 +294,21 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 		ca.changedRowBuf = &b.buf
 	}
 
+	var dlq *deadLetterQueue
+	if opts.HasDeadLetterQueue() {
+		dlq, err = ca.makeDeadLetterQueue(ctx, opts.GetDeadLetterQueue(), timestampOracle, recorder)
+		if err != nil {
+			err = changefeedbase.MarkRetryableError(err)
+			ca.MoveToDraining(err)
+			ca.cancel()
+			return
+		}
+		if s, ok := ca.sink.(deadLetterQueueSetter); ok {
+			s.setDeadLetterQueue(dlq)
+		}
+		ca.sink = &deadLetterSink{EventSink: ca.sink, dlq: dlq}
+	}
+
 	// If the initial scan was disabled the highwater would've already been forwarded
 	needsInitialScan := ca.frontier.Frontier().IsEmpty()
 
@@ -319,7 +336,7 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 	ca.sink = &errorWrapperSink{wrapped: ca.sink}
 	ca.eventConsumer, ca.sink, err = newEventConsumer(
 		ctx, ca.flowCtx.Cfg, ca.spec, feed, ca.frontier.SpanFrontier(), kvFeedHighWater,
-		ca.sink, ca.metrics, ca.sliMetrics, ca.knobs)
+		ca.sink, dlq, ca.metrics, ca.sliMetrics, ca.knobs)
 	if err != nil {
 		ca.MoveToDraining(err)
 		ca.cancel()
@@ -339,6 +356,22 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 	}
 }
 
+// makeDeadLetterQueue makes the dead letter queue of the changefeed, which
+// emits to the sink at sinkURI.
+func (ca *changeAggregator) makeDeadLetterQueue(
+	ctx context.Context,
+	sinkURI string,
+	timestampOracle timestampLowerBoundOracle,
+	recorder metricsRecorder,
+) (*deadLetterQueue, error) {
+	sink, err := getEventSink(ctx, ca.flowCtx.Cfg, makeDeadLetterQueueDetails(ca.spec.Feed, sinkURI),
+		timestampOracle, ca.spec.User(), ca.spec.JobID, recorder)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating dead letter queue")
+	}
+	return newDeadLetterQueue(sink, ca.sliMetrics), nil
+}
+
 // checkForNodeDrain returns an error if the node is draining.
 func (ca *changeAggregator) checkForNodeDrain() error {
 	if ca.drainWatchCh == nil {
@@ -386,6 +419,9 @@ func (ca *changeAggregator) startKVFeed(
 		defer kvFeedMemMon.Stop(ctx)
 		errCh <- kvfeed.Run(ctx, kvfeedCfg)
 	}); err != nil {
+		if kvfeedCfg.InitialScanBackup != nil {
+			kvfeedCfg.InitialScanBackup.Close(ctx)
+		}
 		return nil, nil, nil, err
 	}
 
@@ -439,6 +475,21 @@ func (ca *changeAggregator) makeKVFeedCfg(
 		return kvfeed.Config{}, err
 	}
 
+	// The backup from which the initial scan is read was resolved when the
+	// changefeed was created, so that every aggregator reads the same backup
+	// regardless of the backups taken since.
+	var initialScanBackup kvfeed.BackupReader
+	if b := ca.spec.Feed.InitialScanBackup; needsInitialScan && b != nil {
+		execCfg := cfg.ExecutorConfig.(*sql.ExecutorConfig)
+		r, err := backupccl.NewBackupSpanReader(ctx, execCfg, ca.spec.User(),
+			opts.GetInitialScanFromBackup(), b.Subdir, b.EndTime)
+		if err != nil {
+			return kvfeed.Config{}, errors.Wrapf(err, "opening backup for %s",
+				changefeedbase.OptInitialScanFromBackup)
+		}
+		initialScanBackup = r
+	}
+
 	return kvfeed.Config{
 		Writer:              buf,
 		Settings:            cfg.Settings,
@@ -455,9 +506,11 @@ func (ca *changeAggregator) makeKVFeedCfg(
 		EndTime:             config.EndTime,
 		WithDiff:            filters.WithDiff,
 		NeedsInitialScan:    needsInitialScan,
+		InitialScanBackup:   initialScanBackup,
 		SchemaChangeEvents:  schemaChange.EventClass,
 		SchemaChangePolicy:  schemaChange.Policy,
 		SchemaFeed:          sf,
+		ColumnFilter:        ca.spec.ColumnFilter,
 		Knobs:               ca.knobs.FeedKnobs,
 		UseMux:              changefeedbase.UseMuxRangeFeed.Get(&cfg.Settings.SV),
 		MonitoringCfg:       monitoringCfg,
@@ -1589,7 +1642,8 @@ func (cf *changeFrontier) manageProtectedTimestamps(
 	recordID := progress.ProtectedTimestampRecord
 	if recordID == uuid.Nil {
 		ptr := createProtectedTimestampRecord(
-			ctx, cf.flowCtx.Codec(), cf.spec.JobID, AllTargets(cf.spec.Feed), highWater,
+			ctx, cf.flowCtx.Codec(), cf.spec.JobID, AllTargets(cf.spec.Feed),
+			cf.spec.Feed.LookupTableIDs, highWater,
 		)
 		progress.ProtectedTimestampRecord = ptr.ID.GetUUID()
 		if err := pts.Protect(ctx, ptr); err != nil {
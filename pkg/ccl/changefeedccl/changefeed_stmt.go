@@ -15,18 +15,22 @@ import (
 	"sort"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupresolver"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdceval"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedvalidators"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudprivilege"
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/docs"
 	"github.com/cockroachdb/cockroach/pkg/featureflag"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptpb"
@@ -252,6 +256,7 @@ func changefeedPlanHook(
 				codec,
 				jobID,
 				AllTargets(details),
+				details.LookupTableIDs,
 				details.StatementTime,
 			)
 			progress.GetChangefeed().ProtectedTimestampRecord = ptr.ID.GetUUID()
@@ -410,6 +415,26 @@ func createChangefeedJobRecord(
 		statementTime = initialHighWater
 	}
 
+	// When the initial scan is read from a backup, the changefeed starts at the
+	// end time of the backup so that rangefeeds take over exactly where the
+	// backup's data ends. The backup is resolved once here and recorded in the
+	// job's details, so that backups taken later don't change the data which
+	// is scanned. Altered changefeeds keep their original start time.
+	var initialScanBackup *backupccl.BackupSpanReader
+	if opts.HasInitialScanFromBackup() && changefeedStmt.alterChangefeedAsOf.IsEmpty() {
+		uri := opts.GetInitialScanFromBackup()
+		if err := cloudprivilege.CheckDestinationPrivileges(ctx, p, []string{uri}); err != nil {
+			return nil, err
+		}
+		initialScanBackup, err = backupccl.NewBackupSpanReader(ctx, p.ExecCfg(), p.User(), uri,
+			"" /* subdir */, hlc.Timestamp{} /* endTime */)
+		if err != nil {
+			return nil, errors.Wrapf(err, "resolving %s", changefeedbase.OptInitialScanFromBackup)
+		}
+		defer initialScanBackup.Close(ctx)
+		statementTime = initialScanBackup.EndTime()
+	}
+
 	checkPrivs := true
 	if !changefeedStmt.alterChangefeedAsOf.IsEmpty() {
 		statementTime = changefeedStmt.alterChangefeedAsOf
@@ -464,6 +489,26 @@ func createChangefeedJobRecord(
 	if err != nil {
 		return nil, err
 	}
+
+	if initialScanBackup != nil {
+		var spans roachpb.Spans
+		for _, desc := range targetDescs {
+			if table, isTable := desc.(catalog.TableDescriptor); isTable {
+				spans = append(spans, table.PrimaryIndexSpan(p.ExecCfg().Codec))
+			}
+		}
+		if err := initialScanBackup.CheckCovers(p.ExecCfg().Codec, spans); err != nil {
+			return nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue,
+				"cannot use backup for %s", changefeedbase.OptInitialScanFromBackup)
+		}
+		// The changes made since the backup are read with rangefeeds starting
+		// at its end time, so they must not have been garbage collected.
+		if err := checkNotGarbageCollected(ctx, p.ExecCfg(), spans, statementTime); err != nil {
+			return nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue,
+				"cannot use backup for %s: changes since the backup's end time %s may have been garbage collected",
+				changefeedbase.OptInitialScanFromBackup, statementTime)
+		}
+	}
 	tolerances := opts.GetCanHandle()
 	sd := p.SessionData().Clone()
 	// Add non-local session data state (localization, etc).
@@ -476,6 +521,12 @@ func createChangefeedJobRecord(
 		TargetSpecifications: targets,
 		SessionData:          &sd.SessionData,
 	}
+	if initialScanBackup != nil {
+		details.InitialScanBackup = &jobspb.ChangefeedInitialScanBackup{
+			Subdir:  initialScanBackup.Subdir(),
+			EndTime: initialScanBackup.EndTime(),
+		}
+	}
 
 	specs := AllTargets(details)
 	hasSelectPrivOnAllTables := true
@@ -498,7 +549,7 @@ func createChangefeedJobRecord(
 		}
 	}
 	if checkPrivs {
-		if err := authorizeUserToCreateChangefeed(ctx, p, sinkURI, hasSelectPrivOnAllTables, hasChangefeedPrivOnAllTables, opts.GetConfluentSchemaRegistry()); err != nil {
+		if err := authorizeUserToCreateChangefeed(ctx, p, sinkURI, hasSelectPrivOnAllTables, hasChangefeedPrivOnAllTables, opts.GetConfluentSchemaRegistry(), opts.GetDeadLetterQueue()); err != nil {
 			return nil, err
 		}
 	}
@@ -542,6 +593,7 @@ func createChangefeedJobRecord(
 		// that support it.
 		opts.SetDefaultEnvelope(changefeedbase.OptEnvelopeBare)
 		details.Select = cdceval.AsStringUnredacted(normalized)
+		details.LookupTableIDs = normalized.LookupTableIDs()
 	}
 
 	// TODO(dan): In an attempt to present the most helpful error message to the
@@ -775,6 +827,46 @@ func validateSettings(ctx context.Context, needsRangeFeed bool, execCfg *sql.Exe
 	return nil
 }
 
+// checkNotGarbageCollected returns an error if ts is at or below the GC
+// threshold of any of the ranges of the given spans. It reads the first key of
+// each range as of ts, which fails if the range's history as of ts may have
+// been garbage collected.
+func checkNotGarbageCollected(
+	ctx context.Context, execCfg *sql.ExecutorConfig, spans roachpb.Spans, ts hlc.Timestamp,
+) error {
+	var rangeStartKeys []roachpb.Key
+	ri := kvcoord.MakeRangeIterator(execCfg.DistSender)
+	for _, sp := range spans {
+		rs, err := keys.SpanAddr(sp)
+		if err != nil {
+			return err
+		}
+		for ri.Seek(ctx, rs.Key, kvcoord.Ascending); ; ri.Next(ctx) {
+			if !ri.Valid() {
+				return ri.Error()
+			}
+			key := rs.Key
+			if start := ri.Desc().StartKey; key.Less(start) {
+				key = start
+			}
+			rangeStartKeys = append(rangeStartKeys, key.AsRawKey())
+			if !ri.NeedAnother(rs) {
+				break
+			}
+		}
+	}
+	return execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		if err := txn.SetFixedTimestamp(ctx, ts); err != nil {
+			return err
+		}
+		b := txn.NewBatch()
+		for _, key := range rangeStartKeys {
+			b.Get(key)
+		}
+		return txn.Run(ctx, b)
+	})
+}
+
 func getTableDescriptors(
 	ctx context.Context,
 	p sql.PlanHookState,
@@ -932,6 +1024,16 @@ func validateSink(
 	if err := canarySink.Close(); err != nil {
 		return err
 	}
+	if opts.HasDeadLetterQueue() {
+		dlqSink, err := getAndDialSink(ctx, &p.ExecCfg().DistSQLSrv.ServerConfig,
+			makeDeadLetterQueueDetails(details, opts.GetDeadLetterQueue()), nilOracle, p.User(), jobID, sli)
+		if err != nil {
+			return errors.Wrap(err, "creating dead letter queue")
+		}
+		if err := dlqSink.Close(); err != nil {
+			return err
+		}
+	}
 	// If there's no projection we may need to force some options to ensure messages
 	// have enough information.
 	if details.Select == `` {
@@ -946,9 +1048,30 @@ func validateSink(
 			}
 		}
 	}
+	// When all targets share a topic, consumers need to know which table each
+	// message belongs to, and when it was updated, to apply changes across
+	// tables in order. This applies to changefeeds with a projection too, since
+	// several of them may share a topic. JSON messages are tagged with the name
+	// the table's topic would have had without topic_name, while Avro messages
+	// are already tagged with the table by their schema.
+	singleTopic := hasSingleTopic(u)
+	if singleTopic {
+		if err = opts.ForceUpdatedTimestamps(); err != nil {
+			return err
+		}
+		if encodingOpts, err := opts.GetEncodingOptions(); err != nil {
+			return err
+		} else if encodingOpts.Format == changefeedbase.OptFormatJSON {
+			if err = opts.ForceTopicInValue(); err != nil {
+				return err
+			}
+		}
+	}
 	if sink, ok := canarySink.(SinkWithTopics); ok {
+		// With a single topic, the set of topics resolved timestamps are fanned
+		// out to cannot change.
 		if opts.IsSet(changefeedbase.OptResolvedTimestamps) &&
-			opts.IsSet(changefeedbase.OptSplitColumnFamilies) {
+			opts.IsSet(changefeedbase.OptSplitColumnFamilies) && !singleTopic {
 			return errors.Newf("Resolved timestamps are not currently supported with %s for this sink"+
 				" as the set of topics to fan them out to may change. Instead, use TABLE tablename FAMILY familyname"+
 				" to specify individual families to watch.", changefeedbase.OptSplitColumnFamilies)
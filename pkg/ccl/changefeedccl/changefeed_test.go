@@ -78,6 +78,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -187,6 +188,60 @@ func TestChangefeedBasicQuery(t *testing.T) {
 	cdcTest(t, testFn)
 }
 
+// TestChangefeedQueryFilterPushdown tests that the rangefeed servers filter out
+// the rows which don't satisfy simple predicates of the WHERE clause of a
+// changefeed expression, rather than sending them to the changefeed.
+func TestChangefeedQueryFilterPushdown(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b INT, c STRING)`)
+		fooDesc := desctestutils.TestingGetPublicTableDescriptor(s.Server.DB(), s.Codec, "d", "foo")
+		fooSpan := fooDesc.PrimaryIndexSpan(s.Codec)
+
+		// Record the keys of the rows of foo which the rangefeed servers send.
+		var mu syncutil.Mutex
+		var received []roachpb.Key
+		knobs := s.TestingKnobs.DistSQL.(*execinfra.TestingKnobs).Changefeed.(*TestingKnobs)
+		knobs.FeedKnobs.RangefeedOptions = append(knobs.FeedKnobs.RangefeedOptions, kvcoord.TestingWithOnRangefeedEvent(
+			func(ctx context.Context, _ roachpb.Span, _ int64, event *kvpb.RangeFeedEvent) (skip bool, _ error) {
+				if event.Val != nil && fooSpan.ContainsKey(event.Val.Key) {
+					mu.Lock()
+					defer mu.Unlock()
+					received = append(received, event.Val.Key)
+				}
+				return false, nil
+			}),
+		)
+
+		foo := feed(t, f, `CREATE CHANGEFEED WITH initial_scan='no' `+
+			`AS SELECT a, b FROM foo WHERE b > 10 AND c IS NOT NULL AND a < 100`)
+		defer closeFeed(t, foo)
+
+		// The rows which don't match are written first, so that the servers have
+		// sent everything they are going to send once the matching rows arrive.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 5, 'x'), (3, 20, NULL)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 15, 'x'), (4, 25, 'y')`)
+		// Predicates on the primary key aren't pushed down, since primary key
+		// columns aren't part of the row's value.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (100, 30, 'z')`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (5, 35, 'z')`)
+		assertPayloads(t, foo, []string{
+			`foo: [2]->{"a": 2, "b": 15}`,
+			`foo: [4]->{"a": 4, "b": 25}`,
+			`foo: [5]->{"a": 5, "b": 35}`,
+		})
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Len(t, received, 4, "expected only rows 2, 4, 100 and 5 to be sent; got %s", received)
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks)
+}
+
 // Same test as TestChangefeedBasicQuery, but using wrapped envelope with CDC query.
 func TestChangefeedBasicQueryWrapped(t *testing.T) {
 	defer leaktest.AfterTest(t)()
@@ -822,6 +877,63 @@ func TestChangefeedMultiTable(t *testing.T) {
 	cdcTest(t, testFn)
 }
 
+// TestChangefeedSingleTopic verifies that a changefeed whose sink has the
+// topic_name parameter emits the changes of all of its targets, tagged with
+// their table, to a single topic, along with a single resolved timestamp
+// stream which orders the changes across tables.
+func TestChangefeedSingleTopic(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (2, 'b')`)
+
+		fooAndBar := feed(t, f, `CREATE CHANGEFEED FOR foo, bar INTO 'kafka://does.not.matter/?topic_name=all_tables' WITH resolved='10ms'`)
+		defer closeFeed(t, fooAndBar)
+
+		assertPayloadsStripTs(t, fooAndBar, []string{
+			`all_tables: [1]->{"after": {"a": 1, "b": "a"}, "topic": "foo"}`,
+			`all_tables: [2]->{"after": {"a": 2, "b": "b"}, "topic": "bar"}`,
+		})
+
+		// Changes made to the two tables in turn are emitted with increasing
+		// updated timestamps, and each resolved timestamp follows every change
+		// at or below it.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'c')`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (4, 'd')`)
+		var lastResolved hlc.Timestamp
+		updated := make(map[string]hlc.Timestamp)
+		for len(updated) < 2 || lastResolved.Less(updated["bar"]) {
+			m, err := fooAndBar.Next()
+			require.NoError(t, err)
+			require.Equal(t, `all_tables`, m.Topic)
+			if m.Resolved != nil {
+				// Resolved timestamps are emitted once per partition of the
+				// topic rather than once per table.
+				ts := extractResolvedTimestamp(t, m)
+				require.True(t, lastResolved.Less(ts), "%s after %s", ts, lastResolved)
+				lastResolved = ts
+				continue
+			}
+			var value struct {
+				Topic   string `json:"topic"`
+				Updated string `json:"updated"`
+			}
+			require.NoError(t, json.Unmarshal(m.Value, &value))
+			ts := parseTimeToHLC(t, value.Updated)
+			require.True(t, lastResolved.Less(ts), "change at %s after resolved %s", ts, lastResolved)
+			updated[value.Topic] = ts
+		}
+		require.True(t, updated["foo"].Less(updated["bar"]))
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
 func TestChangefeedCursor(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -7266,6 +7378,65 @@ func TestChangefeedOnlyInitialScan(t *testing.T) {
 	cdcTest(t, testFn, feedTestEnterpriseSinks, feedTestOmitSinks("enterprise", "webhook"))
 }
 
+// TestChangefeedInitialScanFromBackup checks that an initial scan read from a
+// backup emits the same rows as an initial scan read from KV at the backup's
+// end time, including for rows deleted with point and range tombstones.
+func TestChangefeedInitialScanFromBackup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		ctx := context.Background()
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo SELECT i, 'initial' FROM generate_series(1, 10) AS g(i)`)
+		sqlDB.Exec(t, `UPDATE foo SET b = 'updated' WHERE a <= 3`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a IN (4, 5)`)
+		sqlDB.Exec(t, `ALTER TABLE foo SPLIT AT VALUES (5)`)
+
+		// Delete the rows with 7 <= a < 9 with an MVCC range tombstone.
+		fooDesc := desctestutils.TestingGetPublicTableDescriptor(
+			s.Server.DB(), s.Codec, "d", "foo")
+		prefix := s.Codec.IndexPrefix(uint32(fooDesc.GetID()), uint32(fooDesc.GetPrimaryIndexID()))
+		require.NoError(t, s.Server.DB().DelRangeUsingTombstone(ctx,
+			encoding.EncodeVarintAscending(prefix.Clone(), 7),
+			encoding.EncodeVarintAscending(prefix.Clone(), 9),
+		))
+
+		var backupTS string
+		sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&backupTS)
+		sqlDB.Exec(t, fmt.Sprintf(
+			`BACKUP TABLE foo INTO 'nodelocal://1/backup' AS OF SYSTEM TIME %s`, backupTS))
+
+		// Changes made after the backup's end time are not part of the scan.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (11, 'after')`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+
+		expected := []string{
+			`foo: [1]->{"after": {"a": 1, "b": "updated"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "updated"}}`,
+			`foo: [3]->{"after": {"a": 3, "b": "updated"}}`,
+			`foo: [6]->{"after": {"a": 6, "b": "initial"}}`,
+			`foo: [9]->{"after": {"a": 9, "b": "initial"}}`,
+			`foo: [10]->{"after": {"a": 10, "b": "initial"}}`,
+		}
+		for _, stmt := range []string{
+			`CREATE CHANGEFEED FOR foo WITH initial_scan = 'only', initial_scan_from_backup = 'nodelocal://1/backup'`,
+			fmt.Sprintf(`CREATE CHANGEFEED FOR foo WITH initial_scan = 'only', cursor = '%s'`, backupTS),
+		} {
+			feed := feed(t, f, stmt)
+			assertPayloads(t, feed, expected)
+			jobFeed := feed.(cdctest.EnterpriseTestFeed)
+			require.NoError(t, jobFeed.WaitForStatus(func(s jobs.Status) bool {
+				return s == jobs.StatusSucceeded
+			}))
+			closeFeed(t, feed)
+		}
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks, feedTestNoTenants, feedTestUseRootUserConnection)
+}
+
 func TestChangefeedOnlyInitialScanCSV(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
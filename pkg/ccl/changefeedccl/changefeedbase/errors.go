@@ -101,6 +101,29 @@ func MarkRetryableError(cause error) error {
 	return errors.Mark(cause, &retryableError{})
 }
 
+type poisonMessageError struct{}
+
+func (e *poisonMessageError) Error() string {
+	return "poison changefeed message"
+}
+
+// WithPoisonMessageError decorates underlying error to indicate that the
+// message being encoded or emitted was permanently rejected, and that
+// retrying it will not succeed. Such messages may be routed to a dead letter
+// queue instead of failing the changefeed.
+func WithPoisonMessageError(cause error) error {
+	if cause == nil {
+		return nil
+	}
+	return errors.Mark(cause, &poisonMessageError{})
+}
+
+// IsPoisonMessageError returns true if the error was marked with
+// WithPoisonMessageError.
+func IsPoisonMessageError(err error) bool {
+	return errors.Is(err, &poisonMessageError{})
+}
+
 type drainHelper interface {
 	IsDraining() bool
 }
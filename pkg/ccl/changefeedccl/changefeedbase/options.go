@@ -15,6 +15,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -145,6 +146,16 @@ const (
 
 	OptInitialScanOnly = `initial_scan_only`
 
+	// OptInitialScanFromBackup sources the initial scan from the latest backup
+	// in the given backup collection, rather than from a scan of the table in
+	// KV. The changefeed starts at the end time of that backup.
+	OptInitialScanFromBackup = `initial_scan_from_backup`
+
+	// OptDeadLetterQueue is the URI of a sink to which messages that cannot
+	// be encoded, or that are permanently rejected by the sink, are emitted
+	// instead of failing the changefeed.
+	OptDeadLetterQueue = `dead_letter_queue`
+
 	OptEnvelopeKeyOnly       EnvelopeType = `key_only`
 	OptEnvelopeRow           EnvelopeType = `row`
 	OptEnvelopeDeprecatedRow EnvelopeType = `deprecated_row`
@@ -196,10 +207,12 @@ const (
 	SinkParamTopicName              = `topic_name`
 	SinkSchemeCloudStorageAzure     = `azure`
 	SinkSchemeCloudStorageGCS       = `gs`
+	SinkSchemeCloudStorageHDFS      = `hdfs`
 	SinkSchemeCloudStorageHTTP      = `file-http`
 	SinkSchemeCloudStorageHTTPS     = `file-https`
 	SinkSchemeCloudStorageNodelocal = `nodelocal`
 	SinkSchemeCloudStorageS3        = `s3`
+	SinkSchemeCloudStorageSFTP      = `sftp`
 	SinkSchemeExperimentalSQL       = `experimental-sql`
 	SinkSchemeKafka                 = `kafka`
 	SinkSchemeNull                  = `null`
@@ -345,6 +358,8 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptInitialScan:                        enum("yes", "no", "only").orEmptyMeans("yes"),
 	OptNoInitialScan:                      flagOption,
 	OptInitialScanOnly:                    flagOption,
+	OptInitialScanFromBackup:              stringOption,
+	OptDeadLetterQueue:                    stringOption,
 	DeprecatedOptProtectDataFromGCOnPause: flagOption,
 	OptExpirePTSAfter:                     durationOption.thatCanBeZero(),
 	OptKafkaSinkConfig:                    jsonOption,
@@ -372,6 +387,7 @@ var CommonOptions = makeStringSet(OptCursor, OptEndTime, OptEnvelope,
 	OptInitialScan, OptNoInitialScan, OptInitialScanOnly, OptUnordered, OptCustomKeyColumn,
 	OptMinCheckpointFrequency, OptMetricsScope, OptVirtualColumns, Topics, OptExpirePTSAfter,
 	OptExecutionLocality, OptLaggingRangesThreshold, OptLaggingRangesPollingInterval,
+	OptInitialScanFromBackup, OptDeadLetterQueue,
 )
 
 // SQLValidOptions is options exclusive to SQL sink
@@ -424,11 +440,33 @@ func RedactUserFromURI(uri string) (string, error) {
 	return u.String(), nil
 }
 
+// redactExternalStorageURI removes secrets, such as access keys passed as query
+// parameters, from an external storage URI.
+func redactExternalStorageURI(uri string) (string, error) {
+	return cloud.SanitizeExternalStorageURI(uri, nil /* extraParams */)
+}
+
+// redactSinkURI removes secrets, such as passwords and certificates passed as
+// query parameters, as well as the user, from a sink URI.
+func redactSinkURI(uri string) (string, error) {
+	cleaned, err := cloud.SanitizeExternalStorageURI(uri, []string{
+		SinkParamSASLPassword,
+		SinkParamCACert,
+		SinkParamClientCert,
+	})
+	if err != nil {
+		return "", err
+	}
+	return RedactUserFromURI(cleaned)
+}
+
 // RedactedOptions are options whose values should be replaced with "redacted" in job descriptions and errors.
 var RedactedOptions = map[string]redactionFunc{
 	OptWebhookAuthHeader:       redactSimple,
 	SinkParamClientKey:         redactSimple,
 	OptConfluentSchemaRegistry: RedactUserFromURI,
+	OptInitialScanFromBackup:   redactExternalStorageURI,
+	OptDeadLetterQueue:         redactSinkURI,
 }
 
 // NoLongerExperimental aliases options prefixed with experimental that no longer need to be
@@ -452,7 +490,7 @@ var InitialScanOnlyUnsupportedOptions OptionsSet = makeStringSet(OptEndTime, Opt
 
 // ParquetFormatUnsupportedOptions is options that are not supported with the
 // parquet format.
-var ParquetFormatUnsupportedOptions OptionsSet = makeStringSet(OptTopicInValue)
+var ParquetFormatUnsupportedOptions OptionsSet = makeStringSet(OptTopicInValue, OptDeadLetterQueue)
 
 // AlterChangefeedUnsupportedOptions are changefeed options that we do not allow
 // users to alter.
@@ -460,8 +498,8 @@ var ParquetFormatUnsupportedOptions OptionsSet = makeStringSet(OptTopicInValue)
 // and the end_time option. However, there are instances in which it should be
 // allowed to alter either of these options. We need to support the alteration
 // of these fields.
-var AlterChangefeedUnsupportedOptions OptionsSet = makeStringSet(OptCursor, OptInitialScan,
-	OptNoInitialScan, OptInitialScanOnly, OptEndTime)
+var AlterChangefeedUnsupportedOptions OptionsSet = makeStringSet(OptInitialScan,
+	OptNoInitialScan, OptInitialScanOnly, OptEndTime, OptInitialScanFromBackup)
 
 // AlterChangefeedOptionExpectValues is used to parse alter changefeed options
 // using PlanHookState.TypeAsStringOpts().
@@ -512,6 +550,7 @@ func makeDirectedInvertedIndex(pairs []dependentOption) map[string][]dependentOp
 
 var incompatibleOptionsMap = makeInvertedIndex([]incompatibleOptions{
 	{opt1: OptUnordered, opt2: OptResolvedTimestamps, reason: `resolved timestamps cannot be guaranteed to be correct in unordered mode`},
+	{opt1: OptInitialScanFromBackup, opt2: OptCursor, reason: `the changefeed starts at the end time of the backup`},
 })
 
 var dependentOptionsMap = makeDirectedInvertedIndex([]dependentOption{
@@ -599,6 +638,31 @@ func (s StatementOptions) GetCursor() string {
 	return s.m[OptCursor]
 }
 
+// HasInitialScanFromBackup returns true if the initial scan should be read
+// from a backup.
+func (s StatementOptions) HasInitialScanFromBackup() bool {
+	_, ok := s.m[OptInitialScanFromBackup]
+	return ok
+}
+
+// GetInitialScanFromBackup returns the URI of the backup collection the
+// initial scan should be read from.
+func (s StatementOptions) GetInitialScanFromBackup() string {
+	return s.m[OptInitialScanFromBackup]
+}
+
+// HasDeadLetterQueue returns true if messages which cannot be emitted should
+// be routed to a dead letter queue.
+func (s StatementOptions) HasDeadLetterQueue() bool {
+	_, ok := s.m[OptDeadLetterQueue]
+	return ok
+}
+
+// GetDeadLetterQueue returns the URI of the dead letter queue sink.
+func (s StatementOptions) GetDeadLetterQueue() string {
+	return s.m[OptDeadLetterQueue]
+}
+
 // HasEndTime returns true if an end time was provided.
 func (s StatementOptions) HasEndTime() bool {
 	_, ok := s.m[OptEndTime]
@@ -1037,6 +1101,15 @@ func (s StatementOptions) ForceTopicInValue() error {
 	return err
 }
 
+// ForceUpdatedTimestamps sets the encoding option UpdatedTimestamps to true and
+// then validates the resulting encoding options.
+func (s StatementOptions) ForceUpdatedTimestamps() error {
+	s.m[OptUpdatedTimestamps] = ``
+	s.cache.EncodingOptions = EncodingOptions{}
+	_, err := s.GetEncodingOptions()
+	return err
+}
+
 // ForceDiff sets diff to true regardess of its previous value.
 func (s StatementOptions) ForceDiff() {
 	s.m[OptDiff] = ``
@@ -1051,6 +1124,11 @@ func (s StatementOptions) SetTopics(topics []string) {
 	s.m[Topics] = strings.Join(topics, ",")
 }
 
+// SetCursor sets the cursor regardless of its previous value.
+func (s StatementOptions) SetCursor(cursor string) {
+	s.m[OptCursor] = cursor
+}
+
 // ClearDiff clears diff option.
 func (s StatementOptions) ClearDiff() {
 	delete(s.m, OptDiff)
@@ -1132,6 +1210,15 @@ func (s StatementOptions) ValidateForCreateChangefeed(isPredicateChangefeed bool
 			return errors.Newf(`%s=%s is only usable with %s`, OptFormat, OptFormatCSV, OptInitialScanOnly)
 		}
 	}
+	if scanType == NoInitialScan && s.HasInitialScanFromBackup() {
+		return errors.Newf(`%s is not usable without an initial scan`, OptInitialScanFromBackup)
+	}
+	if s.HasInitialScanFromBackup() && s.GetInitialScanFromBackup() == `` {
+		return errors.Newf(`%s requires a backup collection URI`, OptInitialScanFromBackup)
+	}
+	if s.HasDeadLetterQueue() && s.GetDeadLetterQueue() == `` {
+		return errors.Newf(`%s requires a sink URI`, OptDeadLetterQueue)
+	}
 	// Right now parquet does not support any of these options
 	if s.m[OptFormat] == string(OptFormatParquet) {
 		if err := validateUnsupportedOptions(ParquetFormatUnsupportedOptions, fmt.Sprintf("format=%s", OptFormatParquet)); err != nil {
@@ -44,6 +44,13 @@ func TestOptionsValidations(t *testing.T) {
 		{map[string]string{"initial_scan_only": "", "resolved": ""}, true, "cannot specify both initial_scan='only'"},
 		{map[string]string{"initial_scan_only": "", "resolved": ""}, true, "cannot specify both initial_scan='only'"},
 		{map[string]string{"key_column": "b"}, false, "requires the unordered option"},
+		{map[string]string{"initial_scan_from_backup": "nodelocal://1/foo"}, false, ""},
+		{map[string]string{"initial_scan_from_backup": ""}, false, "requires a backup collection URI"},
+		{map[string]string{"initial_scan_from_backup": "nodelocal://1/foo", "initial_scan": "no"}, false, "not usable without an initial scan"},
+		{map[string]string{"initial_scan_from_backup": "nodelocal://1/foo", "initial_scan": "yes", "cursor": "1"}, false, "not usable with cursor"},
+		{map[string]string{"dead_letter_queue": "kafka://localhost:9092"}, false, ""},
+		{map[string]string{"dead_letter_queue": ""}, false, "requires a sink URI"},
+		{map[string]string{"format": "parquet", "dead_letter_queue": "kafka://localhost:9092"}, false, "cannot specify both"},
 	}
 
 	for _, test := range tests {
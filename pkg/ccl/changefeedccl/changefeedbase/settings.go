@@ -229,6 +229,16 @@ var UseMuxRangeFeed = settings.RegisterBoolSetting(
 	util.ConstantWithMetamorphicTestBool("changefeed.mux_rangefeed.enabled", false),
 )
 
+// PushDownFilters enables evaluating parts of the WHERE clause of changefeed
+// expressions on the rangefeed servers.
+var PushDownFilters = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"changefeed.push_down_filters.enabled",
+	"if true, rangefeed servers filter out rows which don't satisfy simple predicates "+
+		"of the changefeed's WHERE clause before sending them",
+	true,
+)
+
 // EventConsumerWorkers specifies the maximum number of workers to use when
 // processing  events.
 var EventConsumerWorkers = settings.RegisterIntSetting(
@@ -309,6 +319,17 @@ var SinkPacerRequestSize = settings.RegisterDurationSetting(
 	settings.PositiveDuration,
 )
 
+// LookupCacheSize is the maximum number of rows read by cdc_lookup which are
+// cached by each changefeed aggregator.
+var LookupCacheSize = settings.RegisterIntSetting(
+	settings.ApplicationLevel,
+	"changefeed.lookup_cache_size",
+	"the maximum number of rows read by cdc_lookup in changefeed expressions "+
+		"which are cached by each changefeed aggregator; 0 disables caching",
+	1024,
+	settings.NonNegativeInt,
+)
+
 // DefaultLaggingRangesThreshold is the default duration by which a range must be
 // lagging behind the present to be considered as 'lagging' behind in metrics.
 var DefaultLaggingRangesThreshold = 3 * time.Minute
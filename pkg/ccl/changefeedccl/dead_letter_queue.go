@@ -0,0 +1,170 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	gojson "encoding/json"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// deadLetterLogEvery is used to rate limit logging of messages routed to a
+// dead letter queue, since a single bad row may be followed by many more.
+var deadLetterLogEvery log.EveryN = log.Every(10 * time.Second)
+
+// deadLetterQueue emits messages which could not be encoded, or which were
+// permanently rejected by the sink of a changefeed, to a secondary sink so
+// that the changefeed can continue to make progress.
+//
+// Each message emitted to the queue is a JSON object describing the error
+// along with the original key and value of the message, or, if the row could
+// not be encoded, a description of the row. Messages are emitted to the same
+// topic they were destined for on the changefeed's sink.
+//
+// A deadLetterQueue is safe for concurrent use.
+type deadLetterQueue struct {
+	metrics *sliMetrics
+	mu      struct {
+		syncutil.Mutex
+		sink EventSink
+	}
+}
+
+// deadLetter is a message which could not be emitted to a changefeed's sink.
+type deadLetter struct {
+	topic      TopicDescriptor
+	key, value []byte
+	// row describes the row if it could not be encoded, in which case value is
+	// not set.
+	row           string
+	updated, mvcc hlc.Timestamp
+	err           error
+}
+
+// deadLetterMessage is the value emitted to the dead letter queue for a
+// deadLetter. Keys and values are base64 encoded since they are in the
+// format of the changefeed's sink, which may not be JSON.
+type deadLetterMessage struct {
+	Error         string `json:"error"`
+	Key           []byte `json:"key,omitempty"`
+	Value         []byte `json:"value,omitempty"`
+	Row           string `json:"row,omitempty"`
+	Updated       string `json:"updated,omitempty"`
+	MVCCTimestamp string `json:"mvcc_timestamp,omitempty"`
+}
+
+// makeDeadLetterQueueDetails returns the details of a changefeed with which
+// the sink of its dead letter queue, at sinkURI, is made. Messages are emitted
+// to the queue as JSON regardless of the format of the changefeed, so none of
+// the changefeed's options apply to the queue.
+func makeDeadLetterQueueDetails(
+	details jobspb.ChangefeedDetails, sinkURI string,
+) jobspb.ChangefeedDetails {
+	details.SinkURI = sinkURI
+	details.Opts = map[string]string{
+		changefeedbase.OptFormat:   string(changefeedbase.OptFormatJSON),
+		changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeWrapped),
+	}
+	return details
+}
+
+func newDeadLetterQueue(sink EventSink, metrics *sliMetrics) *deadLetterQueue {
+	q := &deadLetterQueue{metrics: metrics}
+	q.mu.sink = sink
+	return q
+}
+
+// send emits a dead letter to the queue.
+func (q *deadLetterQueue) send(ctx context.Context, dl deadLetter) error {
+	msg := deadLetterMessage{
+		Error: dl.err.Error(),
+		Key:   dl.key,
+		Value: dl.value,
+		Row:   dl.row,
+	}
+	if !dl.updated.IsEmpty() {
+		msg.Updated = dl.updated.AsOfSystemTime()
+	}
+	if !dl.mvcc.IsEmpty() {
+		msg.MVCCTimestamp = dl.mvcc.AsOfSystemTime()
+	}
+	value, err := gojson.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if deadLetterLogEvery.ShouldLog() {
+		log.Warningf(ctx, "emitting message to dead letter queue: %v", dl.err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.mu.sink.EmitRow(
+		ctx, dl.topic, dl.key, value, dl.updated, dl.mvcc, kvevent.Alloc{},
+	); err != nil {
+		return errors.Wrap(err, "emitting to dead letter queue")
+	}
+	q.metrics.DeadLetterMessages.Inc(1)
+	return nil
+}
+
+// Flush blocks until every message sent to the queue has been acknowledged
+// by its sink.
+func (q *deadLetterQueue) Flush(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.mu.sink.Flush(ctx); err != nil {
+		return errors.Wrap(err, "flushing dead letter queue")
+	}
+	return nil
+}
+
+// Close closes the queue's sink.
+func (q *deadLetterQueue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.mu.sink.Close()
+}
+
+// deadLetterQueueSetter is implemented by sinks which can route messages they
+// permanently fail to emit to a dead letter queue, rather than returning an
+// error. It must be called before any rows are emitted to the sink.
+type deadLetterQueueSetter interface {
+	setDeadLetterQueue(q *deadLetterQueue)
+}
+
+// deadLetterSink wraps the sink of a changefeed which has a dead letter queue,
+// flushing the queue along with the sink so that messages routed to the queue
+// are durable before the changefeed's frontier advances past them.
+type deadLetterSink struct {
+	EventSink
+	dlq *deadLetterQueue
+}
+
+var _ EventSink = (*deadLetterSink)(nil)
+
+// Flush implements the EventSink interface.
+func (s *deadLetterSink) Flush(ctx context.Context) error {
+	if err := s.EventSink.Flush(ctx); err != nil {
+		return err
+	}
+	return s.dlq.Flush(ctx)
+}
+
+// Close implements the EventSink interface.
+func (s *deadLetterSink) Close() error {
+	return errors.CombineErrors(s.EventSink.Close(), s.dlq.Close())
+}
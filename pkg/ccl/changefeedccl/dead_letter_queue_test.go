@@ -0,0 +1,214 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	gojson "encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingDeadLetterSink struct {
+	testSink
+	values    [][]byte
+	numFlush  int
+	flushErr  error
+	numClosed int
+	// If set, EmitRow signals emitting and then blocks until unblock is
+	// closed.
+	emitting, unblock chan struct{}
+}
+
+var _ EventSink = (*recordingDeadLetterSink)(nil)
+
+func (s *recordingDeadLetterSink) Dial() error {
+	return nil
+}
+
+func (s *recordingDeadLetterSink) EmitRow(
+	ctx context.Context,
+	topic TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	if s.unblock != nil {
+		s.emitting <- struct{}{}
+		<-s.unblock
+	}
+	s.values = append(s.values, value)
+	return nil
+}
+
+func (s *recordingDeadLetterSink) Flush(ctx context.Context) error {
+	s.numFlush++
+	return s.flushErr
+}
+
+func (s *recordingDeadLetterSink) Close() error {
+	s.numClosed++
+	return nil
+}
+
+func TestDeadLetterQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	metrics := MakeMetrics(time.Minute).(*Metrics)
+	sli, err := metrics.getSLIMetrics(defaultSLIScope)
+	require.NoError(t, err)
+
+	dlqSink := &recordingDeadLetterSink{}
+	dlq := newDeadLetterQueue(dlqSink, sli)
+
+	ts := hlc.Timestamp{WallTime: 1, Logical: 2}
+	cause := changefeedbase.WithPoisonMessageError(errors.New("message too large"))
+	require.NoError(t, dlq.send(ctx, deadLetter{
+		topic: noTopic{},
+		key:   []byte(`[1]`),
+		value: []byte(`{"a": 1}`),
+		mvcc:  ts,
+		err:   cause,
+	}))
+	require.NoError(t, dlq.send(ctx, deadLetter{
+		topic:   noTopic{},
+		row:     "a=1",
+		updated: ts,
+		err:     cause,
+	}))
+	require.Equal(t, int64(2), sli.DeadLetterMessages.Value())
+
+	require.Len(t, dlqSink.values, 2)
+	var msg deadLetterMessage
+	require.NoError(t, gojson.Unmarshal(dlqSink.values[0], &msg))
+	require.Equal(t, deadLetterMessage{
+		Error:         "message too large",
+		Key:           []byte(`[1]`),
+		Value:         []byte(`{"a": 1}`),
+		MVCCTimestamp: ts.AsOfSystemTime(),
+	}, msg)
+	msg = deadLetterMessage{}
+	require.NoError(t, gojson.Unmarshal(dlqSink.values[1], &msg))
+	require.Equal(t, deadLetterMessage{
+		Error:   "message too large",
+		Row:     "a=1",
+		Updated: ts.AsOfSystemTime(),
+	}, msg)
+
+	// Flushing the changefeed's sink also flushes the queue, unless the sink
+	// itself fails to flush.
+	mainSink := &recordingDeadLetterSink{}
+	s := &deadLetterSink{EventSink: mainSink, dlq: dlq}
+	require.NoError(t, s.Flush(ctx))
+	require.Equal(t, 1, mainSink.numFlush)
+	require.Equal(t, 1, dlqSink.numFlush)
+
+	mainSink.flushErr = errors.New("boom")
+	require.Error(t, s.Flush(ctx))
+	require.Equal(t, 1, dlqSink.numFlush)
+
+	require.NoError(t, s.Close())
+	require.Equal(t, 1, mainSink.numClosed)
+	require.Equal(t, 1, dlqSink.numClosed)
+}
+
+// TestWebhookPoisonMessageStatus verifies that only responses which reject the
+// contents of a batch mark it as a poison message, and that authentication and
+// configuration errors of the endpoint are retried instead.
+func TestWebhookPoisonMessageStatus(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		status int
+		poison bool
+	}{
+		{http.StatusBadRequest, true},
+		{http.StatusRequestEntityTooLarge, true},
+		{http.StatusUnprocessableEntity, true},
+		{http.StatusUnauthorized, false},
+		{http.StatusForbidden, false},
+		{http.StatusNotFound, false},
+		{http.StatusRequestTimeout, false},
+		{http.StatusTooManyRequests, false},
+		{http.StatusInternalServerError, false},
+	} {
+		t.Run(http.StatusText(tc.status), func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			sc := &webhookSinkClient{client: &httputil.Client{Client: srv.Client()}}
+			req, err := http.NewRequest(http.MethodPost, srv.URL, nil)
+			require.NoError(t, err)
+			err = sc.Flush(context.Background(), req)
+			require.Error(t, err)
+			require.Equal(t, tc.poison, changefeedbase.IsPoisonMessageError(err))
+		})
+	}
+}
+
+// TestKafkaSinkDeadLetterQueue verifies that messages which the brokers reject
+// are sent to the dead letter queue without holding the Kafka sink's lock, and
+// that flushes of the sink wait for them.
+func TestKafkaSinkDeadLetterQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	metrics := MakeMetrics(time.Minute).(*Metrics)
+	sli, err := metrics.getSLIMetrics(defaultSLIScope)
+	require.NoError(t, err)
+
+	dlqSink := &recordingDeadLetterSink{
+		emitting: make(chan struct{}),
+		unblock:  make(chan struct{}),
+	}
+	p := newAsyncProducerMock(1)
+	sink, cleanup := makeTestKafkaSink(t, noTopicPrefix, defaultTopicName, p, "t")
+	defer cleanup()
+	sink.disableInternalRetry = true
+	sink.setDeadLetterQueue(newDeadLetterQueue(dlqSink, sli))
+
+	var pool testAllocPool
+	require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`1`), nil, zeroTS, zeroTS, pool.alloc()))
+	m1 := <-p.inputCh
+	p.errorsCh <- &sarama.ProducerError{Msg: m1, Err: sarama.ErrMessageSizeTooLarge}
+
+	// While the dead letter queue blocks, the sink still accepts messages, and
+	// flushes wait for the rejected message to reach the queue.
+	<-dlqSink.emitting
+	require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`2`), nil, zeroTS, zeroTS, pool.alloc()))
+	m2 := <-p.inputCh
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	require.True(t, errors.Is(sink.Flush(timeoutCtx), context.DeadlineExceeded))
+
+	close(dlqSink.unblock)
+	p.successesCh <- m2
+	require.NoError(t, sink.Flush(ctx))
+	require.Len(t, dlqSink.values, 1)
+	require.Equal(t, int64(1), sli.DeadLetterMessages.Value())
+	require.Equal(t, int64(0), pool.used())
+}
@@ -193,9 +193,9 @@ func (e *confluentAvroEncoder) EncodeKey(ctx context.Context, row cdcevent.Row)
 		if err != nil {
 			return nil, err
 		}
-		return registered.schema.BinaryFromRow(header, it)
+		return markUnencodable(registered.schema.BinaryFromRow(header, it))
 	}
-	return registered.schema.BinaryFromRow(header, row.ForEachKeyColumn())
+	return markUnencodable(registered.schema.BinaryFromRow(header, row.ForEachKeyColumn()))
 }
 
 // EncodeValue implements the Encoder interface.
@@ -290,7 +290,17 @@ func (e *confluentAvroEncoder) EncodeValue(
 		0, 0, 0, 0, // Placeholder for the ID.
 	}
 	binary.BigEndian.PutUint32(header[1:5], uint32(registered.registryID))
-	return registered.schema.BinaryFromRow(header, meta, prevRow, updatedRow, updatedRow)
+	return markUnencodable(registered.schema.BinaryFromRow(header, meta, prevRow, updatedRow, updatedRow))
+}
+
+// markUnencodable marks a failure to encode the datums of a row, such as a
+// decimal which does not fit the precision of the column's schema, as a poison
+// message error, since encoding the same row will always fail.
+func markUnencodable(buf []byte, err error) ([]byte, error) {
+	if err != nil {
+		return nil, changefeedbase.WithPoisonMessageError(err)
+	}
+	return buf, nil
 }
 
 // EncodeResolvedTimestamp implements the Encoder interface.
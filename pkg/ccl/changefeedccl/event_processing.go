@@ -73,6 +73,9 @@ type kvEventToRowConsumer struct {
 
 	metrics *sliMetrics
 
+	// dlq, if set, receives rows which cannot be encoded.
+	dlq *deadLetterQueue
+
 	// This pacer is used to incorporate event consumption to elastic CPU
 	// control. This helps ensure that event encoding/decoding does not throttle
 	// foreground SQL traffic.
@@ -93,6 +96,7 @@ func newEventConsumer(
 	spanFrontier *span.Frontier,
 	cursor hlc.Timestamp,
 	sink EventSink,
+	dlq *deadLetterQueue,
 	metrics *Metrics,
 	sliMetrics *sliMetrics,
 	knobs TestingKnobs,
@@ -142,7 +146,7 @@ func newEventConsumer(
 		}
 
 		execCfg := cfg.ExecutorConfig.(*sql.ExecutorConfig)
-		return newKVEventToRowConsumer(ctx, execCfg, frontier, cursor, s,
+		return newKVEventToRowConsumer(ctx, execCfg, frontier, cursor, s, dlq,
 			encoder, feed, spec, knobs, topicNamer, sliMetrics, pacer)
 	}
 
@@ -217,6 +221,7 @@ func newKVEventToRowConsumer(
 	frontier frontier,
 	cursor hlc.Timestamp,
 	sink EventSink,
+	dlq *deadLetterQueue,
 	encoder Encoder,
 	details ChangefeedConfig,
 	spec execinfrapb.ChangeAggregatorSpec,
@@ -250,6 +255,7 @@ func newKVEventToRowConsumer(
 		encoder:              encoder,
 		decoder:              decoder,
 		sink:                 sink,
+		dlq:                  dlq,
 		cursor:               cursor,
 		details:              details,
 		knobs:                knobs,
@@ -438,14 +444,14 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 	var keyCopy, valueCopy []byte
 	encodedKey, err := c.encoder.EncodeKey(ctx, updatedRow)
 	if err != nil {
-		return err
+		return c.handleEncodeError(ctx, err, topic, updatedRow, schemaTS, alloc)
 	}
 	c.scratch, keyCopy = c.scratch.Copy(encodedKey, 0 /* extraCap */)
 	// TODO(yevgeniy): Some refactoring is needed in the encoder: namely, prevRow
 	// might not be available at all when working with changefeed expressions.
 	encodedValue, err := c.encoder.EncodeValue(ctx, evCtx, updatedRow, prevRow)
 	if err != nil {
-		return err
+		return c.handleEncodeError(ctx, err, topic, updatedRow, schemaTS, alloc)
 	}
 	c.scratch, valueCopy = c.scratch.Copy(encodedValue, 0 /* extraCap */)
 
@@ -464,6 +470,30 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 	return nil
 }
 
+// handleEncodeError sends a row which can never be encoded to the dead letter
+// queue, if the changefeed has one, so that the changefeed can make progress
+// past it. Otherwise, the error is returned.
+func (c *kvEventToRowConsumer) handleEncodeError(
+	ctx context.Context,
+	err error,
+	topic TopicDescriptor,
+	row cdcevent.Row,
+	schemaTS hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	if c.dlq == nil || !changefeedbase.IsPoisonMessageError(err) {
+		return err
+	}
+	defer alloc.Release(ctx)
+	return c.dlq.send(ctx, deadLetter{
+		topic:   topic,
+		row:     row.DebugString(),
+		updated: schemaTS,
+		mvcc:    row.MvccTimestamp,
+		err:     err,
+	})
+}
+
 // Close closes this consumer.
 func (c *kvEventToRowConsumer) Close() error {
 	c.pacer.Close()
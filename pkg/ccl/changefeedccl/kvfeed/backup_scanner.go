@@ -0,0 +1,111 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package kvfeed
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// BackupReader provides access to the contents of a backup as of its end
+// time.
+type BackupReader interface {
+	// EndTime returns the timestamp as of which the backup is read.
+	EndTime() hlc.Timestamp
+	// CoverSpan partitions sp into chunks, each of which only overlaps a subset
+	// of the backup's files, and calls fn with each chunk and an iterator over
+	// the latest live revision of each key in the chunk as of EndTime. Chunks
+	// in which the backup has no data may be skipped.
+	CoverSpan(
+		ctx context.Context,
+		sp roachpb.Span,
+		fn func(chunk roachpb.Span, iter storage.SimpleMVCCIterator) error,
+	) error
+	// Close releases the resources held by the reader.
+	Close(ctx context.Context)
+}
+
+// backupScanner is a kvScanner which reads the initial scan of a changefeed
+// out of a backup instead of issuing ScanRequests, keeping the cost of the
+// scan off of the cluster's foreground traffic.
+type backupScanner struct {
+	reader BackupReader
+}
+
+var _ kvScanner = (*backupScanner)(nil)
+
+func (s *backupScanner) Scan(ctx context.Context, sink kvevent.Writer, cfg scanConfig) error {
+	if !cfg.Timestamp.Equal(s.reader.EndTime()) {
+		return errors.AssertionFailedf(
+			"cannot scan backup with end time %s at %s", s.reader.EndTime(), cfg.Timestamp)
+	}
+	for _, sp := range cfg.Spans {
+		if err := s.scanSpan(ctx, sink, sp, cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *backupScanner) scanSpan(
+	ctx context.Context, sink kvevent.Writer, sp roachpb.Span, cfg scanConfig,
+) error {
+	if log.V(2) {
+		log.Infof(ctx, "reading %s from backup at %s", sp, cfg.Timestamp)
+	}
+	if err := s.reader.CoverSpan(ctx, sp, func(chunk roachpb.Span, iter storage.SimpleMVCCIterator) error {
+		return s.scanChunk(ctx, sink, chunk, iter, cfg)
+	}); err != nil {
+		return errors.Wrapf(err, `reading backup for %s`, sp)
+	}
+	return sink.Add(ctx, kvevent.NewBackfillResolvedEvent(sp, cfg.Timestamp, cfg.Boundary))
+}
+
+func (s *backupScanner) scanChunk(
+	ctx context.Context,
+	sink kvevent.Writer,
+	chunk roachpb.Span,
+	iter storage.SimpleMVCCIterator,
+	cfg scanConfig,
+) error {
+	for iter.SeekGE(storage.MVCCKey{Key: chunk.Key}); ; iter.NextKey() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		key := iter.UnsafeKey()
+		if key.Key.Compare(chunk.EndKey) >= 0 {
+			break
+		}
+		if hasPoint, _ := iter.HasPointAndRange(); !hasPoint {
+			continue
+		}
+		v, err := storage.DecodeMVCCValueAndErr(iter.UnsafeValue())
+		if err != nil {
+			return errors.Wrapf(err, `decoding backup value for %s`, key.Key)
+		}
+		// The iterator's memory is only valid until the next call to NextKey,
+		// so copy the key and value before buffering them.
+		keyBytes := append(roachpb.Key(nil), key.Key...)
+		valBytes := append([]byte(nil), v.Value.RawBytes...)
+		if err := sink.Add(ctx, kvevent.NewBackfillKVEvent(
+			keyBytes, key.Timestamp, valBytes, cfg.WithDiff, cfg.Timestamp,
+		)); err != nil {
+			return errors.Wrapf(err, `buffering changes for %s`, chunk)
+		}
+	}
+	return nil
+}
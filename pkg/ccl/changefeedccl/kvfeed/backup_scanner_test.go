@@ -0,0 +1,154 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package kvfeed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/testutils/storageutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+type memBackupReader struct {
+	sst     []byte
+	endTime hlc.Timestamp
+	// splits are the keys at which spans are split into chunks.
+	splits []roachpb.Key
+	// chunks records the chunks which were read.
+	chunks []roachpb.Span
+}
+
+func (r *memBackupReader) EndTime() hlc.Timestamp {
+	return r.endTime
+}
+
+// CoverSpan splits sp at the reader's split keys, and reads each chunk from
+// the same SST.
+func (r *memBackupReader) CoverSpan(
+	ctx context.Context,
+	sp roachpb.Span,
+	fn func(chunk roachpb.Span, iter storage.SimpleMVCCIterator) error,
+) error {
+	chunks := []roachpb.Span{sp}
+	for _, split := range r.splits {
+		last := chunks[len(chunks)-1]
+		if last.ContainsKey(split) && !last.Key.Equal(split) {
+			chunks[len(chunks)-1].EndKey = split
+			chunks = append(chunks, roachpb.Span{Key: split, EndKey: last.EndKey})
+		}
+	}
+	for _, chunk := range chunks {
+		r.chunks = append(r.chunks, chunk)
+		if err := func() error {
+			iter, err := storage.NewMemSSTIterator(r.sst, false /* verify */, storage.IterOptions{
+				KeyTypes:   storage.IterKeyTypePointsAndRanges,
+				LowerBound: chunk.Key,
+				UpperBound: chunk.EndKey,
+			})
+			if err != nil {
+				return err
+			}
+			readAsOfIter := storage.NewReadAsOfIterator(iter, r.endTime)
+			defer readAsOfIter.Close()
+			return fn(chunk, readAsOfIter)
+		}(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memBackupReader) Close(context.Context) {}
+
+type recordingWriter struct {
+	kvs      []roachpb.KeyValue
+	resolved []jobspb.ResolvedSpan
+}
+
+func (r *recordingWriter) Add(ctx context.Context, e kvevent.Event) error {
+	switch e.Type() {
+	case kvevent.TypeKV:
+		r.kvs = append(r.kvs, e.KV())
+	case kvevent.TypeResolved:
+		r.resolved = append(r.resolved, e.Resolved())
+	}
+	return nil
+}
+
+func (r *recordingWriter) Drain(ctx context.Context) error {
+	return nil
+}
+
+func (r *recordingWriter) CloseWithReason(ctx context.Context, reason error) error {
+	return nil
+}
+
+func TestBackupScanner(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	sst, _, _ := storageutils.MakeSST(t, st, []interface{}{
+		storageutils.PointKV("a", 1, "a1"),
+		storageutils.PointKV("a", 3, "a3"),
+		storageutils.PointKV("b", 2, "b2"),
+		storageutils.PointKV("b", 4, ""),
+		storageutils.PointKV("c", 6, "c6"),
+		storageutils.PointKV("d", 2, "d2"),
+		storageutils.PointKV("z", 2, "z2"),
+	})
+	endTime := storageutils.WallTS(5)
+	r := &memBackupReader{sst: sst, endTime: endTime, splits: []roachpb.Key{roachpb.Key("b"), roachpb.Key("d")}}
+	s := &backupScanner{reader: r}
+
+	sp := roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("e")}
+	w := &recordingWriter{}
+	require.NoError(t, s.Scan(ctx, w, scanConfig{
+		Spans:     []roachpb.Span{sp},
+		Timestamp: endTime,
+	}))
+
+	// Only the latest live revision of each key at or below the end time of the
+	// backup is emitted.
+	require.Len(t, w.kvs, 2)
+	require.Equal(t, roachpb.Key("a"), w.kvs[0].Key)
+	require.Equal(t, storageutils.WallTS(3), w.kvs[0].Value.Timestamp)
+	require.Equal(t, roachpb.Key("d"), w.kvs[1].Key)
+	require.Equal(t, storageutils.WallTS(2), w.kvs[1].Value.Timestamp)
+	val, err := w.kvs[1].Value.GetBytes()
+	require.NoError(t, err)
+	require.Equal(t, "d2", string(val))
+
+	// Each chunk is read separately.
+	require.Equal(t, []roachpb.Span{
+		{Key: roachpb.Key("a"), EndKey: roachpb.Key("b")},
+		{Key: roachpb.Key("b"), EndKey: roachpb.Key("d")},
+		{Key: roachpb.Key("d"), EndKey: roachpb.Key("e")},
+	}, r.chunks)
+
+	require.Len(t, w.resolved, 1)
+	require.Equal(t, sp, w.resolved[0].Span)
+	require.Equal(t, endTime, w.resolved[0].Timestamp)
+
+	// Scanning at a time other than the end time of the backup is an error.
+	require.Error(t, s.Scan(ctx, w, scanConfig{
+		Spans:     []roachpb.Span{sp},
+		Timestamp: endTime.Next(),
+	}))
+}
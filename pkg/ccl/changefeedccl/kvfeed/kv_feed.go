@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
@@ -77,6 +78,11 @@ type Config struct {
 	// been seen.
 	NeedsInitialScan bool
 
+	// InitialScanBackup, if set, is the backup from which the initial scan is
+	// read instead of KV. Its end time must equal InitialHighWater. The kvfeed
+	// takes ownership of the reader and closes it when it returns.
+	InitialScanBackup BackupReader
+
 	// InitialHighWater is the timestamp after which new events are guaranteed to
 	// be produced.
 	InitialHighWater hlc.Timestamp
@@ -86,6 +92,11 @@ type Config struct {
 	// time, the changefeed job will end with a successful status.
 	EndTime hlc.Timestamp
 
+	// ColumnFilter, if set, is sent to the rangefeed servers to filter out rows
+	// before they are sent. It is only applied until the first schema change,
+	// since it refers to the columns of the table by ID.
+	ColumnFilter *kvpb.RangeFeedColumnFilter
+
 	// Knobs are kvfeed testing knobs.
 	Knobs TestingKnobs
 
@@ -105,6 +116,11 @@ func Run(ctx context.Context, cfg Config) error {
 			onBackfillRangeCallback: cfg.MonitoringCfg.OnBackfillRangeCallback,
 		}
 	}
+	var initialScanner kvScanner
+	if cfg.InitialScanBackup != nil {
+		defer cfg.InitialScanBackup.Close(ctx)
+		initialScanner = &backupScanner{reader: cfg.InitialScanBackup}
+	}
 	var pff physicalFeedFactory
 	{
 		sender := cfg.DB.NonTransactionalSender()
@@ -126,6 +142,8 @@ func Run(ctx context.Context, cfg Config) error {
 		cfg.SchemaFeed,
 		sc, pff, bf, cfg.UseMux, cfg.Targets, cfg.Knobs)
 	f.onBackfillCallback = cfg.MonitoringCfg.OnBackfillCallback
+	f.initialScanner = initialScanner
+	f.columnFilter = cfg.ColumnFilter
 	f.rangeObserver = startLaggingRangesObserver(g, cfg.MonitoringCfg.LaggingRangesCallback,
 		cfg.MonitoringCfg.LaggingRangesPollingInterval, cfg.MonitoringCfg.LaggingRangesThreshold)
 
@@ -261,7 +279,11 @@ type kvFeed struct {
 	tableFeed     schemafeed.SchemaFeed
 	scanner       kvScanner
 	physicalFeed  physicalFeedFactory
-	knobs         TestingKnobs
+	// initialScanner, if set, is used instead of scanner for the initial scan.
+	initialScanner kvScanner
+	// columnFilter, if set, is passed to the rangefeed servers.
+	columnFilter *kvpb.RangeFeedColumnFilter
+	knobs        TestingKnobs
 }
 
 // TODO(yevgeniy): This method is a kitchen sink. Refactor.
@@ -365,6 +387,10 @@ func (f *kvFeed) run(ctx context.Context) (err error) {
 			f.checkpointTimestamp = hlc.Timestamp{}
 		}
 
+		// The column filter refers to columns by ID, which the schema change may
+		// have replaced; stop pushing it down.
+		f.columnFilter = nil
+
 		highWater := rangeFeedResumeFrontier.Frontier()
 		boundaryType := jobspb.ResolvedSpan_BACKFILL
 		events, err := f.tableFeed.Peek(ctx, highWater.Next())
@@ -511,7 +537,11 @@ func (f *kvFeed) scanIfShould(
 	if initialScanOnly {
 		boundaryType = jobspb.ResolvedSpan_EXIT
 	}
-	if err := f.scanner.Scan(ctx, f.writer, scanConfig{
+	scanner := f.scanner
+	if isInitialScan && f.initialScanner != nil {
+		scanner = f.initialScanner
+	}
+	if err := scanner.Scan(ctx, f.writer, scanConfig{
 		Spans:     spansToBackfill,
 		Timestamp: scanTime,
 		WithDiff:  !isInitialScan && f.withDiff,
@@ -565,6 +595,7 @@ func (f *kvFeed) runUntilTableEvent(
 		Knobs:         f.knobs,
 		UseMux:        f.useMux,
 		RangeObserver: f.rangeObserver,
+		ColumnFilter:  f.columnFilter,
 	}
 
 	// The following two synchronous calls works as follows:
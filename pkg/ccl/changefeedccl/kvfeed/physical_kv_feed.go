@@ -33,6 +33,7 @@ type rangeFeedConfig struct {
 	RangeObserver func(fn kvcoord.ForEachRangeFn)
 	Knobs         TestingKnobs
 	UseMux        bool
+	ColumnFilter  *kvpb.RangeFeedColumnFilter
 }
 
 type rangefeedFactory func(
@@ -85,6 +86,9 @@ func (p rangefeedFactory) Run(ctx context.Context, sink kvevent.Writer, cfg rang
 	if cfg.RangeObserver != nil {
 		rfOpts = append(rfOpts, kvcoord.WithRangeObserver(cfg.RangeObserver))
 	}
+	if cfg.ColumnFilter != nil {
+		rfOpts = append(rfOpts, kvcoord.WithColumnFilter(cfg.ColumnFilter))
+	}
 	if len(cfg.Knobs.RangefeedOptions) != 0 {
 		rfOpts = append(rfOpts, cfg.Knobs.RangefeedOptions...)
 	}
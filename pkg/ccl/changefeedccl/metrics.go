@@ -49,6 +49,7 @@ const defaultSLIScope = "default"
 type AggMetrics struct {
 	EmittedMessages           *aggmetric.AggCounter
 	FilteredMessages          *aggmetric.AggCounter
+	DeadLetterMessages        *aggmetric.AggCounter
 	MessageSize               *aggmetric.AggHistogram
 	EmittedBytes              *aggmetric.AggCounter
 	FlushedBytes              *aggmetric.AggCounter
@@ -114,6 +115,7 @@ func (a *AggMetrics) MetricStruct() {}
 type sliMetrics struct {
 	EmittedMessages           *aggmetric.Counter
 	FilteredMessages          *aggmetric.Counter
+	DeadLetterMessages        *aggmetric.Counter
 	MessageSize               *aggmetric.Histogram
 	EmittedBytes              *aggmetric.Counter
 	FlushedBytes              *aggmetric.Counter
@@ -497,6 +499,13 @@ func newAggregateMetrics(histogramWindow time.Duration) *AggMetrics {
 		Measurement: "Messages",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaChangefeedDeadLetterMessages := metric.Metadata{
+		Name: "changefeed.dead_letter_messages",
+		Help: "Messages which could not be encoded or were permanently rejected " +
+			"by the sink, and were emitted to a dead letter queue",
+		Measurement: "Messages",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaChangefeedEmittedBytes := metric.Metadata{
 		Name:        "changefeed.emitted_bytes",
 		Help:        "Bytes emitted by all feeds",
@@ -651,9 +660,10 @@ func newAggregateMetrics(histogramWindow time.Duration) *AggMetrics {
 	// retain significant figures of 2.
 	b := aggmetric.MakeBuilder("scope")
 	a := &AggMetrics{
-		ErrorRetries:     b.Counter(metaChangefeedErrorRetries),
-		EmittedMessages:  b.Counter(metaChangefeedEmittedMessages),
-		FilteredMessages: b.Counter(metaChangefeedFilteredMessages),
+		ErrorRetries:       b.Counter(metaChangefeedErrorRetries),
+		EmittedMessages:    b.Counter(metaChangefeedEmittedMessages),
+		FilteredMessages:   b.Counter(metaChangefeedFilteredMessages),
+		DeadLetterMessages: b.Counter(metaChangefeedDeadLetterMessages),
 		MessageSize: b.Histogram(metric.HistogramOptions{
 			Metadata:     metaMessageSize,
 			Duration:     histogramWindow,
@@ -753,6 +763,7 @@ func (a *AggMetrics) getOrCreateScope(scope string) (*sliMetrics, error) {
 	sm := &sliMetrics{
 		EmittedMessages:           a.EmittedMessages.AddChild(scope),
 		FilteredMessages:          a.FilteredMessages.AddChild(scope),
+		DeadLetterMessages:        a.DeadLetterMessages.AddChild(scope),
 		MessageSize:               a.MessageSize.AddChild(scope),
 		EmittedBytes:              a.EmittedBytes.AddChild(scope),
 		FlushedBytes:              a.FlushedBytes.AddChild(scope),
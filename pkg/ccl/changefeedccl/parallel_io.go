@@ -137,13 +137,25 @@ func (p *parallelIO) Close() {
 func (p *parallelIO) processIO(ctx context.Context, numEmitWorkers int) error {
 	emitWithRetries := func(ctx context.Context, payload IORequest) error {
 		initialSend := true
-		return retry.WithMaxAttempts(ctx, p.retryOpts, p.retryOpts.MaxRetries+1, func() error {
+		// Requests which were permanently rejected are not retried, as doing so
+		// would only delay their handling.
+		var poisonErr error
+		err := retry.WithMaxAttempts(ctx, p.retryOpts, p.retryOpts.MaxRetries+1, func() error {
 			if !initialSend {
 				p.metrics.recordInternalRetry(int64(payload.Keys().Len()), false)
 			}
 			initialSend = false
-			return p.ioHandler(ctx, payload)
+			err := p.ioHandler(ctx, payload)
+			if changefeedbase.IsPoisonMessageError(err) {
+				poisonErr = err
+				return nil
+			}
+			return err
 		})
+		if poisonErr != nil {
+			return poisonErr
+		}
+		return err
 	}
 
 	// Multiple worker routines handle the IO operations, retrying when necessary.
@@ -24,17 +24,19 @@ import (
 )
 
 // createProtectedTimestampRecord will create a record to protect the spans for
-// this changefeed at the resolved timestamp.
+// this changefeed at the resolved timestamp. The tables read by cdc_lookup,
+// lookupTables, are protected along with the targets.
 func createProtectedTimestampRecord(
 	ctx context.Context,
 	codec keys.SQLCodec,
 	jobID jobspb.JobID,
 	targets changefeedbase.Targets,
+	lookupTables descpb.IDs,
 	resolved hlc.Timestamp,
 ) *ptpb.Record {
 	ptsID := uuid.MakeV4()
-	deprecatedSpansToProtect := makeSpansToProtect(codec, targets)
-	targetToProtect := makeTargetToProtect(targets)
+	deprecatedSpansToProtect := makeSpansToProtect(codec, targets, lookupTables)
+	targetToProtect := makeTargetToProtect(targets, lookupTables)
 
 	log.VEventf(ctx, 2, "creating protected timestamp %v at %v", ptsID, resolved)
 	return jobsprotectedts.MakeRecord(
@@ -42,24 +44,27 @@ func createProtectedTimestampRecord(
 		jobsprotectedts.Jobs, targetToProtect)
 }
 
-func makeTargetToProtect(targets changefeedbase.Targets) *ptpb.Target {
+func makeTargetToProtect(targets changefeedbase.Targets, lookupTables descpb.IDs) *ptpb.Target {
 	// NB: We add 1 because we're also going to protect system.descriptors.
 	// We protect system.descriptors because a changefeed needs all of the history
 	// of table descriptors to version data.
-	tablesToProtect := make(descpb.IDs, 0, targets.NumUniqueTables()+1)
+	tablesToProtect := make(descpb.IDs, 0, targets.NumUniqueTables()+len(lookupTables)+1)
 	_ = targets.EachTableID(func(id descpb.ID) error {
 		tablesToProtect = append(tablesToProtect, id)
 		return nil
 	})
+	tablesToProtect = append(tablesToProtect, lookupTables...)
 	tablesToProtect = append(tablesToProtect, keys.DescriptorTableID)
 	return ptpb.MakeSchemaObjectsTarget(tablesToProtect)
 }
 
-func makeSpansToProtect(codec keys.SQLCodec, targets changefeedbase.Targets) []roachpb.Span {
+func makeSpansToProtect(
+	codec keys.SQLCodec, targets changefeedbase.Targets, lookupTables descpb.IDs,
+) []roachpb.Span {
 	// NB: We add 1 because we're also going to protect system.descriptors.
 	// We protect system.descriptors because a changefeed needs all of the history
 	// of table descriptors to version data.
-	spansToProtect := make([]roachpb.Span, 0, targets.NumUniqueTables()+1)
+	spansToProtect := make([]roachpb.Span, 0, targets.NumUniqueTables()+len(lookupTables)+1)
 	addTablePrefix := func(id uint32) {
 		tablePrefix := codec.TablePrefix(id)
 		spansToProtect = append(spansToProtect, roachpb.Span{
@@ -71,6 +76,9 @@ func makeSpansToProtect(codec keys.SQLCodec, targets changefeedbase.Targets) []r
 		addTablePrefix(uint32(id))
 		return nil
 	})
+	for _, id := range lookupTables {
+		addTablePrefix(uint32(id))
+	}
 	addTablePrefix(keys.DescriptorTableID)
 	return spansToProtect
 }
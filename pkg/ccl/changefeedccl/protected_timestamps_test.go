@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/spanconfig/spanconfigptsreader"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/bootstrap"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/desctestutils"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsql"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
@@ -447,17 +448,20 @@ func TestPTSRecordProtectsTargetsAndDescriptorTable(t *testing.T) {
 	sqlDB := sqlutils.MakeSQLRunner(db)
 
 	sqlDB.Exec(t, "CREATE TABLE foo (a INT, b STRING)")
+	sqlDB.Exec(t, "CREATE TABLE bar (id INT PRIMARY KEY, b STRING)")
+	sqlDB.Exec(t, "INSERT INTO bar VALUES (1, 'before')")
 	ts := s.Clock().Now()
 	ctx := context.Background()
 
 	fooDescr := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "d", "foo")
+	barDescr := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "d", "bar")
 	var targets changefeedbase.Targets
 	targets.Add(changefeedbase.Target{
 		TableID: fooDescr.GetID(),
 	})
 
-	// Lay protected timestamp record.
-	ptr := createProtectedTimestampRecord(ctx, s.Codec(), 42, targets, ts)
+	// Lay protected timestamp record, which also protects bar as a lookup table.
+	ptr := createProtectedTimestampRecord(ctx, s.Codec(), 42, targets, descpb.IDs{barDescr.GetID()}, ts)
 	require.NoError(t, execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
 		return execCfg.ProtectedTimestampProvider.WithTxn(txn).Protect(ctx, ptr)
 	}))
@@ -471,4 +475,11 @@ func TestPTSRecordProtectsTargetsAndDescriptorTable(t *testing.T) {
 	asOf := ts
 	_, err := fetchTableDescriptors(ctx, &execCfg, targets, asOf)
 	require.NoError(t, err)
+
+	// Rows of the lookup table remain readable as of ts as well.
+	sqlDB.Exec(t, "UPDATE bar SET b = 'after' WHERE id = 1")
+	require.NoError(t, s.ForceTableGC(ctx, "d", "bar", ts.Add(-1, 0)))
+	sqlDB.CheckQueryResults(t,
+		fmt.Sprintf("SELECT b FROM bar AS OF SYSTEM TIME %s", ts.AsOfSystemTime()),
+		[][]string{{"before"}})
 }
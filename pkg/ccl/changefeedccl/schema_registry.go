@@ -14,6 +14,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"path"
 	"time"
@@ -249,7 +250,14 @@ func (r *confluentSchemaRegistry) RegisterSchemaForSubject(
 		defer gracefulClose(ctx, resp.Body)
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 			body, _ := io.ReadAll(resp.Body)
-			return errors.Errorf("registering schema to %s %s: %s", u, resp.Status, body)
+			err := errors.Errorf("registering schema to %s %s: %s", u, resp.Status, body)
+			// The registry rejects schemas which are incompatible with the
+			// subject's existing versions or which are invalid. Retrying the
+			// registration of such a schema will not succeed.
+			if resp.StatusCode == http.StatusConflict || resp.StatusCode == http.StatusUnprocessableEntity {
+				return changefeedbase.WithPoisonMessageError(err)
+			}
+			return err
 		}
 		var res confluentSchemaVersionResponse
 		if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
@@ -285,6 +293,9 @@ func (r *confluentSchemaRegistry) doWithRetry(ctx context.Context, fn func() err
 		if err == nil {
 			return nil
 		}
+		if changefeedbase.IsPoisonMessageError(err) {
+			return err
+		}
 		if r.sliMetrics != nil {
 			r.sliMetrics.SchemaRegistryRetries.Inc(1)
 		}
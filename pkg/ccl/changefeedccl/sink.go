@@ -313,6 +313,25 @@ func getSink(
 	return sink, nil
 }
 
+// hasSingleTopic returns true if u is the URI of a Kafka or Pub/Sub sink whose
+// topic_name parameter multiplexes all of the targets of a changefeed into a
+// single topic. Messages are then tagged with the table they belong to and
+// with their updated timestamp, and resolved timestamps are emitted once to
+// each partition of the topic, rather than once per table.
+//
+// As with any changefeed, a resolved timestamp emitted to a partition
+// guarantees that every change of any of the targets with an updated timestamp
+// at or below it has already been emitted, to whichever partition its key maps
+// to. A consumer can therefore apply changes across tables in timestamp order
+// by buffering messages until it has seen a resolved timestamp at or above
+// their updated timestamp in every partition, and then applying them sorted by
+// updated timestamp. Changes with equal updated timestamps were made by the
+// same transaction.
+func hasSingleTopic(u *url.URL) bool {
+	return (isKafkaSink(u) || isPubsubSink(u)) &&
+		u.Query().Get(changefeedbase.SinkParamTopicName) != ``
+}
+
 func validateSinkOptions(opts map[string]string, sinkSpecificOpts map[string]struct{}) error {
 	for opt := range opts {
 		if _, ok := changefeedbase.CommonOptions[opt]; ok {
@@ -143,9 +143,17 @@ type kafkaSink struct {
 		inflight int64
 		flushErr error
 		flushCh  chan struct{}
+		// deadLetters are the messages which the brokers permanently rejected,
+		// waiting to be sent to the dead letter queue by the worker once it
+		// releases mu. They are counted as inflight until they are sent, so
+		// that flushes wait for them.
+		deadLetters []pendingDeadLetter
 	}
 
 	disableInternalRetry bool
+
+	// dlq, if set, receives messages which the brokers permanently rejected.
+	dlq *deadLetterQueue
 }
 
 func (s *kafkaSink) getConcreteType() sinkType {
@@ -336,10 +344,25 @@ func (s *kafkaSink) Close() error {
 	return nil
 }
 
+// pendingDeadLetter is a message waiting to be sent to the dead letter queue,
+// along with the memory it holds until then.
+type pendingDeadLetter struct {
+	deadLetter
+	alloc kvevent.Alloc
+}
+
 type messageMetadata struct {
 	alloc         kvevent.Alloc
 	updateMetrics recordOneMessageCallback
-	mvcc          hlc.Timestamp
+	topic         TopicDescriptor
+	updated, mvcc hlc.Timestamp
+}
+
+var _ deadLetterQueueSetter = (*kafkaSink)(nil)
+
+// setDeadLetterQueue implements the deadLetterQueueSetter interface.
+func (s *kafkaSink) setDeadLetterQueue(q *deadLetterQueue) {
+	s.dlq = q
 }
 
 // EmitRow implements the Sink interface.
@@ -356,10 +379,16 @@ func (s *kafkaSink) EmitRow(
 	}
 
 	msg := &sarama.ProducerMessage{
-		Topic:    topic,
-		Key:      sarama.ByteEncoder(key),
-		Value:    sarama.ByteEncoder(value),
-		Metadata: messageMetadata{alloc: alloc, mvcc: mvcc, updateMetrics: s.metrics.recordOneMessage()},
+		Topic: topic,
+		Key:   sarama.ByteEncoder(key),
+		Value: sarama.ByteEncoder(value),
+		Metadata: messageMetadata{
+			alloc:         alloc,
+			updateMetrics: s.metrics.recordOneMessage(),
+			topic:         topicDescr,
+			updated:       updated,
+			mvcc:          mvcc,
+		},
 	}
 	s.stats.startMessage(int64(msg.Key.Length() + msg.Value.Length()))
 	return s.emitMessage(ctx, msg)
@@ -495,6 +524,13 @@ func (s *kafkaSink) isInternalRetryable(err error) bool {
 	return errors.As(err, &kError) && kError == sarama.ErrMessageSizeTooLarge
 }
 
+// isKafkaPoisonMessageError returns true if the brokers rejected a message because
+// of its contents, in which case resending it will not succeed.
+func isKafkaPoisonMessageError(err error) bool {
+	var kError sarama.KError
+	return errors.As(err, &kError) && kError == sarama.ErrMessageSizeTooLarge
+}
+
 func (s *kafkaSink) workerLoop() {
 	defer s.worker.Done()
 
@@ -596,7 +632,41 @@ func (s *kafkaSink) workerLoop() {
 		// until the retry has completed.
 		if !isRetrying() {
 			muLocker.Unlock()
+			s.sendDeadLetters()
+		}
+	}
+}
+
+// sendDeadLetters sends the messages queued by finishProducerMessage to the
+// dead letter queue. Emitting to the queue may block on its own sink, so this
+// must be called without holding mu, so that acks and flushes of other
+// messages don't wait for it.
+func (s *kafkaSink) sendDeadLetters() {
+	s.mu.Lock()
+	pending := s.mu.deadLetters
+	s.mu.deadLetters = nil
+	s.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	var sendErr error
+	for _, dl := range pending {
+		if err := s.dlq.send(s.ctx, dl.deadLetter); err != nil && sendErr == nil {
+			sendErr = err
 		}
+		dl.alloc.Release(s.ctx)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.inflight -= int64(len(pending))
+	if s.mu.flushErr == nil && sendErr != nil {
+		s.mu.flushErr = sendErr
+	}
+	if s.mu.inflight == 0 && s.mu.flushCh != nil {
+		s.mu.flushCh <- struct{}{}
+		s.mu.flushCh = nil
 	}
 }
 
@@ -607,6 +677,27 @@ func (s *kafkaSink) finishProducerMessage(ackMsg *sarama.ProducerMessage, ackErr
 			sz := ackMsg.Key.Length() + ackMsg.Value.Length()
 			s.stats.finishMessage(int64(sz))
 			m.updateMetrics(m.mvcc, sz, sinkDoesNotCompress)
+		} else if s.dlq != nil && isKafkaPoisonMessageError(ackError) {
+			// The message will never be accepted by the brokers, so rather than
+			// failing the changefeed, it is queued to be sent to the dead letter
+			// queue once mu is released. It stays inflight, and keeps its memory,
+			// until then. ByteEncoders never return an error.
+			s.stats.finishMessage(int64(ackMsg.Key.Length() + ackMsg.Value.Length()))
+			key, _ := ackMsg.Key.Encode()
+			value, _ := ackMsg.Value.Encode()
+			s.mu.deadLetters = append(s.mu.deadLetters, pendingDeadLetter{
+				deadLetter: deadLetter{
+					topic:   m.topic,
+					key:     key,
+					value:   value,
+					updated: m.updated,
+					mvcc:    m.mvcc,
+					err:     ackError,
+				},
+				alloc: m.alloc,
+			})
+			s.mu.inflight++
+			return
 		}
 		m.alloc.Release(s.ctx)
 	}
@@ -620,13 +711,32 @@ func (s *kafkaSink) handleBufferedRetries(msgs []*sarama.ProducerMessage, retryE
 	activeConfig := s.kafkaCfg
 	log.Infof(s.ctx, "kafka sink handling %d buffered messages for internal retry", len(msgs))
 
+	// If the sink has a dead letter queue, msgErrs holds the error of each
+	// message which failed to send in the last attempt, so that only those
+	// messages are routed to the queue.
+	var msgErrs map[*sarama.ProducerMessage]error
+
 	// Ensure memory for messages are always cleaned up
 	defer func() {
 		for _, msg := range msgs {
-			s.finishProducerMessage(msg, lastSendErr)
+			if msgErrs != nil {
+				s.finishProducerMessage(msg, msgErrs[msg])
+			} else {
+				s.finishProducerMessage(msg, lastSendErr)
+			}
 		}
 	}()
 
+	// abandonErr is the error returned once the messages can no longer be
+	// retried. If the error of each message is known, it is surfaced by
+	// finishProducerMessage instead.
+	abandonErr := func() error {
+		if msgErrs != nil {
+			return nil
+		}
+		return lastSendErr
+	}
+
 	for {
 		select {
 		case <-s.stopWorkerCh:
@@ -641,10 +751,10 @@ func (s *kafkaSink) handleBufferedRetries(msgs []*sarama.ProducerMessage, retryE
 		// batching config any further
 		if !s.isInternalRetryable(lastSendErr) {
 			log.Infof(s.ctx, "kafka sink abandoning internal retry due to error: %s", lastSendErr.Error())
-			return lastSendErr
+			return abandonErr()
 		} else if !wasReduced {
 			log.Infof(s.ctx, "kafka sink abandoning internal retry due to being unable to reduce batching size")
-			return lastSendErr
+			return abandonErr()
 		}
 
 		log.Infof(s.ctx, "kafka sink retrying %d messages with reduced flush config: (%+v)", len(msgs), newConfig.Producer.Flush)
@@ -664,9 +774,16 @@ func (s *kafkaSink) handleBufferedRetries(msgs []*sarama.ProducerMessage, retryE
 		// SendMessages will attempt to send all messages into an AsyncProducer with
 		// the client's config and then block until the results come in.
 		lastSendErr = newProducer.SendMessages(msgs)
+		msgErrs = nil
 		if lastSendErr != nil {
 			// nolint:errcmp
 			if sendErrs, ok := lastSendErr.(sarama.ProducerErrors); ok && len(sendErrs) > 0 {
+				if s.dlq != nil {
+					msgErrs = make(map[*sarama.ProducerMessage]error, len(sendErrs))
+					for _, sendErr := range sendErrs {
+						msgErrs[sendErr.Msg] = sendErr.Err
+					}
+				}
 				// Just check the first error since all these messages being retried
 				// were likely from a single partition and therefore would've been
 				// marked with the same error.
@@ -415,6 +415,25 @@ func TestKafkaTopicNameWithPrefix(t *testing.T) {
 	require.Equal(t, `prefix-_u2603_`, m.Topic)
 }
 
+func TestHasSingleTopic(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		uri    string
+		single bool
+	}{
+		{`kafka://localhost:9092?topic_prefix=prefix-`, false},
+		{`kafka://localhost:9092?topic_name=all_tables`, true},
+		{`gcpubsub://project?region=us-east1&topic_name=all_tables`, true},
+		{`webhook-https://localhost?topic_name=all_tables`, false},
+	} {
+		u, err := url.Parse(tc.uri)
+		require.NoError(t, err)
+		require.Equal(t, tc.single, hasSingleTopic(u), tc.uri)
+	}
+}
+
 // goos: darwin
 // goarch: amd64
 // pkg: github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl
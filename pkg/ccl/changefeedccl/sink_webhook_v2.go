@@ -223,11 +223,30 @@ func (sc *webhookSinkClient) Flush(ctx context.Context, batch SinkPayload) error
 		if err != nil {
 			return errors.Wrapf(err, "failed to read body for HTTP response with status: %d", res.StatusCode)
 		}
-		return fmt.Errorf("%s: %s", res.Status, string(resBody))
+		err = fmt.Errorf("%s: %s", res.Status, string(resBody))
+		if isWebhookPoisonMessageStatus(res.StatusCode) {
+			return changefeedbase.WithPoisonMessageError(err)
+		}
+		return err
 	}
 	return nil
 }
 
+// isWebhookPoisonMessageStatus returns true if an HTTP response with the given
+// status code indicates that the endpoint rejected the batch because of its
+// contents, so that resending it will never succeed. Other client errors, such
+// as authentication failures or a missing endpoint, are problems with the sink
+// rather than with the messages, and are retried like server errors so that
+// they don't divert every message to the dead letter queue.
+func isWebhookPoisonMessageStatus(code int) bool {
+	switch code {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		return true
+	default:
+		return false
+	}
+}
+
 // Close implements the SinkClient interface
 func (sc *webhookSinkClient) Close() error {
 	sc.client.CloseIdleConnections()
@@ -15,6 +15,7 @@ import (
 	"math/rand"
 	"net/url"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -76,6 +77,10 @@ type TenantStreamingClustersArgs struct {
 	MultiTenantSingleClusterTestRegions []string
 
 	NoMetamorphicExternalConnection bool
+
+	// EnableReaderTenant, if set, creates a reader tenant for the destination
+	// tenant that serves read-only queries over the replicated data.
+	EnableReaderTenant bool
 }
 
 var DefaultTenantStreamingClustersArgs = TenantStreamingClustersArgs{
@@ -271,8 +276,15 @@ func (c *TenantStreamingClusters) BuildCreateTenantQuery(externalConnection stri
 		c.Args.DestTenantName,
 		c.Args.SrcTenantName,
 		sourceURI)
+	var options []string
 	if c.Args.RetentionTTLSeconds > 0 {
-		streamReplStmt = fmt.Sprintf("%s WITH RETENTION = '%ds'", streamReplStmt, c.Args.RetentionTTLSeconds)
+		options = append(options, fmt.Sprintf("RETENTION = '%ds'", c.Args.RetentionTTLSeconds))
+	}
+	if c.Args.EnableReaderTenant {
+		options = append(options, "READ VIRTUAL CLUSTER")
+	}
+	if len(options) > 0 {
+		streamReplStmt = fmt.Sprintf("%s WITH %s", streamReplStmt, strings.Join(options, ", "))
 	}
 	return streamReplStmt
 }
@@ -63,3 +63,14 @@ var StreamReplicationMinCheckpointFrequency = settings.RegisterDurationSetting(
 	10*time.Second,
 	settings.NonNegativeDuration,
 )
+
+// ReplicationReaderCatalogRefreshInterval controls how often the catalog of
+// the reader tenant of a replication stream is updated to the replicated time.
+var ReplicationReaderCatalogRefreshInterval = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"stream_replication.reader_catalog_refresh_interval",
+	"controls how often the catalog of the reader virtual cluster of a replication "+
+		"stream is updated to the replicated time",
+	30*time.Second,
+	settings.PositiveDuration,
+)
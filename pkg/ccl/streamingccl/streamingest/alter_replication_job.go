@@ -42,7 +42,8 @@ var alterReplicationCutoverHeader = colinfo.ResultColumns{
 // ResolvedTenantReplicationOptions represents options from an
 // evaluated CREATE VIRTUAL CLUSTER FROM REPLICATION command.
 type resolvedTenantReplicationOptions struct {
-	retention *int32
+	retention          *int32
+	readVirtualCluster bool
 }
 
 func evalTenantReplicationOptions(
@@ -65,6 +66,7 @@ func evalTenantReplicationOptions(
 		retSeconds := int32(retSeconds64)
 		r.retention = &retSeconds
 	}
+	r.readVirtualCluster = options.ReadVirtualCluster
 	return r, nil
 }
 
@@ -75,6 +77,10 @@ func (r *resolvedTenantReplicationOptions) GetRetention() (int32, bool) {
 	return *r.retention, true
 }
 
+func (r *resolvedTenantReplicationOptions) ReadVirtualCluster() bool {
+	return r != nil && r.readVirtualCluster
+}
+
 func alterReplicationJobTypeCheck(
 	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
 ) (matched bool, header colinfo.ResultColumns, _ error) {
@@ -151,6 +157,10 @@ func alterReplicationJobHook(
 	if err != nil {
 		return nil, nil, nil, false, err
 	}
+	if options.ReadVirtualCluster() {
+		return nil, nil, nil, false, errors.New(
+			"READ VIRTUAL CLUSTER can only be specified when the replication stream is created")
+	}
 
 	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
 		if err := utilccl.CheckEnterpriseEnabled(
@@ -0,0 +1,257 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamingest
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// maintainReaderCatalog periodically copies the catalog of the destination
+// tenant, as of the replicated time, into the reader tenant of the replication
+// stream, if there is one. It returns once ingestDone is closed.
+//
+// Failing to update the reader tenant's catalog must not interrupt the
+// replication stream, so errors are logged and the update is retried on the
+// next iteration.
+func (s *streamIngestionResumer) maintainReaderCatalog(
+	ctx context.Context, execCtx sql.JobExecContext, ingestDone <-chan struct{},
+) error {
+	details := s.job.Details().(jobspb.StreamIngestionDetails)
+	if !details.ReadTenantID.IsSet() {
+		return nil
+	}
+	execCfg := execCtx.ExecCfg()
+
+	var lastRefresh hlc.Timestamp
+	timer := timeutil.NewTimer()
+	defer timer.Stop()
+	for {
+		timer.Reset(streamingccl.ReplicationReaderCatalogRefreshInterval.Get(&execCfg.Settings.SV))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ingestDone:
+			return nil
+		case <-timer.C:
+			timer.Read = true
+		}
+
+		replicatedTime := loadReplicatedTime(ctx, execCfg.InternalDB, s.job)
+		if !lastRefresh.Less(replicatedTime) {
+			continue
+		}
+		if err := setupOrAdvanceReaderCatalog(
+			ctx, execCfg.DB, details.DestinationTenantID, details.ReadTenantID, replicatedTime,
+		); err != nil {
+			log.Warningf(ctx, "failed to update the catalog of reader tenant %s: %v",
+				details.ReadTenantID, err)
+			continue
+		}
+		lastRefresh = replicatedTime
+	}
+}
+
+// setupOrAdvanceReaderCatalog replaces the non-system descriptors and
+// namespace entries of the reader tenant with those of the source tenant as of
+// the given timestamp. The tables of the reader tenant read their rows from
+// the source tenant's keyspace as of that timestamp.
+func setupOrAdvanceReaderCatalog(
+	ctx context.Context, db *kv.DB, srcTenantID, readerTenantID roachpb.TenantID, asOf hlc.Timestamp,
+) error {
+	srcCodec := keys.MakeSQLCodec(srcTenantID)
+	readerCodec := keys.MakeSQLCodec(readerTenantID)
+
+	var srcDescs, srcNames []kv.KeyValue
+	var srcDescIDSeq int64
+	if err := db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		if err := txn.SetFixedTimestamp(ctx, asOf); err != nil {
+			return err
+		}
+		var err error
+		if srcDescs, err = txn.Scan(ctx, srcCodec.DescMetadataPrefix(),
+			srcCodec.DescMetadataPrefix().PrefixEnd(), 0 /* maxRows */); err != nil {
+			return err
+		}
+		namePrefix := srcCodec.IndexPrefix(keys.NamespaceTableID, catconstants.NamespaceTablePrimaryIndexID)
+		if srcNames, err = txn.Scan(ctx, namePrefix, namePrefix.PrefixEnd(), 0 /* maxRows */); err != nil {
+			return err
+		}
+		seq, err := txn.Get(ctx, srcCodec.SequenceKey(keys.DescIDSequenceID))
+		if err != nil {
+			return err
+		}
+		srcDescIDSeq = seq.ValueInt()
+		return nil
+	}); err != nil {
+		return errors.Wrapf(err, "reading catalog of tenant %s as of %s", srcTenantID, asOf)
+	}
+
+	return db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		readerDescs, err := txn.Scan(ctx, readerCodec.DescMetadataPrefix(),
+			readerCodec.DescMetadataPrefix().PrefixEnd(), 0 /* maxRows */)
+		if err != nil {
+			return err
+		}
+		readerVersions := make(map[descpb.ID]descpb.DescriptorVersion, len(readerDescs))
+		for i := range readerDescs {
+			var desc descpb.Descriptor
+			if err := readerDescs[i].ValueProto(&desc); err != nil {
+				return err
+			}
+			id, version, _, _, err := descpb.GetDescriptorMetadata(&desc)
+			if err != nil {
+				return err
+			}
+			if !isSystemDescriptor(&desc) {
+				readerVersions[id] = version
+			}
+		}
+
+		b := txn.NewBatch()
+		copied := make(map[descpb.ID]struct{}, len(srcDescs))
+		for i := range srcDescs {
+			var desc descpb.Descriptor
+			if err := srcDescs[i].ValueProto(&desc); err != nil {
+				return err
+			}
+			if isSystemDescriptor(&desc) {
+				continue
+			}
+			id, _, _, state, err := descpb.GetDescriptorMetadata(&desc)
+			if err != nil {
+				return err
+			}
+			if state != descpb.DescriptorState_PUBLIC {
+				continue
+			}
+			prepareReaderDescriptor(&desc, srcTenantID, asOf, readerVersions[id]+1)
+			b.Put(catalogkeys.MakeDescMetadataKey(readerCodec, id), &desc)
+			copied[id] = struct{}{}
+		}
+		for id := range readerVersions {
+			if _, ok := copied[id]; !ok {
+				b.Del(catalogkeys.MakeDescMetadataKey(readerCodec, id))
+			}
+		}
+
+		// Replace the namespace entries of the copied descriptors.
+		namePrefix := readerCodec.IndexPrefix(keys.NamespaceTableID, catconstants.NamespaceTablePrimaryIndexID)
+		readerNames, err := txn.Scan(ctx, namePrefix, namePrefix.PrefixEnd(), 0 /* maxRows */)
+		if err != nil {
+			return err
+		}
+		for i := range readerNames {
+			nameInfo, err := catalogkeys.DecodeNameMetadataKey(readerCodec, readerNames[i].Key)
+			if err != nil {
+				return err
+			}
+			if !isSystemNameInfo(nameInfo) {
+				b.Del(readerNames[i].Key)
+			}
+		}
+		for i := range srcNames {
+			nameInfo, err := catalogkeys.DecodeNameMetadataKey(srcCodec, srcNames[i].Key)
+			if err != nil {
+				return err
+			}
+			if _, ok := copied[descpb.ID(srcNames[i].ValueInt())]; !ok || isSystemNameInfo(nameInfo) {
+				continue
+			}
+			b.Put(catalogkeys.EncodeNameKey(readerCodec, &nameInfo), srcNames[i].ValueInt())
+		}
+
+		// Make sure that descriptor IDs allocated by the reader tenant do not
+		// collide with those of the copied descriptors.
+		seqKey := readerCodec.SequenceKey(keys.DescIDSequenceID)
+		readerSeq, err := txn.Get(ctx, seqKey)
+		if err != nil {
+			return err
+		}
+		if readerSeq.ValueInt() < srcDescIDSeq {
+			b.Put(seqKey, srcDescIDSeq)
+		}
+		return txn.Run(ctx, b)
+	})
+}
+
+// prepareReaderDescriptor adapts a descriptor of the source tenant for use in
+// the reader tenant: its rows are read from the source tenant's keyspace, it
+// has no in-progress schema changes, and its version is set to the given
+// version so that the reader tenant's leases on the previous version expire.
+func prepareReaderDescriptor(
+	desc *descpb.Descriptor,
+	srcTenantID roachpb.TenantID,
+	asOf hlc.Timestamp,
+	version descpb.DescriptorVersion,
+) {
+	table, database, typ, schema, function := descpb.GetDescriptors(desc)
+	switch {
+	case table != nil:
+		table.Version = version
+		table.ModificationTime = hlc.Timestamp{}
+		table.Mutations = nil
+		table.MutationJobs = nil
+		table.DeclarativeSchemaChangerState = nil
+		if !table.IsView() || table.IsMaterializedView {
+			table.External = &descpb.ExternalRowData{
+				TenantID: srcTenantID,
+				TableID:  table.ID,
+				AsOf:     asOf,
+			}
+		}
+	case database != nil:
+		database.Version = version
+		database.ModificationTime = hlc.Timestamp{}
+		database.DeclarativeSchemaChangerState = nil
+	case typ != nil:
+		typ.Version = version
+		typ.ModificationTime = hlc.Timestamp{}
+		typ.DeclarativeSchemaChangerState = nil
+	case schema != nil:
+		schema.Version = version
+		schema.ModificationTime = hlc.Timestamp{}
+		schema.DeclarativeSchemaChangerState = nil
+	case function != nil:
+		function.Version = version
+		function.ModificationTime = hlc.Timestamp{}
+		function.DeclarativeSchemaChangerState = nil
+	}
+}
+
+// isSystemDescriptor returns true if the descriptor is the system database or
+// one of its tables. These descriptors are owned by each tenant and are never
+// copied into the reader tenant.
+func isSystemDescriptor(desc *descpb.Descriptor) bool {
+	table, database, _, _, _ := descpb.GetDescriptors(desc)
+	if database != nil {
+		return database.ID == keys.SystemDatabaseID
+	}
+	return table != nil && table.ParentID == keys.SystemDatabaseID
+}
+
+// isSystemNameInfo returns true if the namespace entry is that of the system
+// database or of one of its children.
+func isSystemNameInfo(nameInfo descpb.NameInfo) bool {
+	return nameInfo.ParentID == keys.SystemDatabaseID ||
+		(nameInfo.ParentID == descpb.InvalidID && nameInfo.Name == catconstants.SystemDatabaseName)
+}
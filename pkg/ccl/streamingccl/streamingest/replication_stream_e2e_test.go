@@ -195,6 +195,63 @@ func TestTenantStreamingPauseResumeIngestion(t *testing.T) {
 	c.RequireFingerprintMatchAtTimestamp(srcTime.AsOfSystemTime())
 }
 
+func TestTenantStreamingReaderTenant(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	args := replicationtestutils.DefaultTenantStreamingClustersArgs
+	args.EnableReaderTenant = true
+	c, cleanup := replicationtestutils.CreateTenantStreamingClusters(ctx, t, args)
+	defer cleanup()
+	c.DestSysSQL.Exec(t, `SET CLUSTER SETTING stream_replication.reader_catalog_refresh_interval = '100ms'`)
+
+	producerJobID, ingestionJobID := c.StartStreamReplication(ctx)
+	jobutils.WaitForJobToRun(c.T, c.SrcSysSQL, jobspb.JobID(producerJobID))
+	jobutils.WaitForJobToRun(c.T, c.DestSysSQL, jobspb.JobID(ingestionJobID))
+
+	srcTime := c.SrcCluster.Server(0).Clock().Now()
+	c.WaitUntilReplicatedTime(srcTime, jobspb.JobID(ingestionJobID))
+
+	readerTenantName := string(args.DestTenantName) + "-readonly"
+	c.DestSysSQL.Exec(t, `ALTER VIRTUAL CLUSTER $1 START SERVICE SHARED`, readerTenantName)
+	readerConn := c.DestCluster.Server(0).SystemLayer().SQLConn(t, "cluster:"+readerTenantName+"/defaultdb")
+	readerSQL := sqlutils.MakeSQLRunner(readerConn)
+
+	waitForRows := func(query string, expected [][]string) {
+		testutils.SucceedsSoon(t, func() error {
+			rows, err := readerConn.Query(query)
+			if err != nil {
+				return err
+			}
+			actual, err := sqlutils.RowsToStrMatrix(rows)
+			if err != nil {
+				return err
+			}
+			if fmt.Sprint(actual) != fmt.Sprint(expected) {
+				return errors.Newf("expected %v, got %v", expected, actual)
+			}
+			return nil
+		})
+	}
+
+	// The reader tenant serves the replicated rows as of the replicated time.
+	waitForRows(`SELECT * FROM d.t2 ORDER BY i`, [][]string{{"2"}})
+	waitForRows(`SELECT i, b FROM d.t1`, [][]string{{"42", "world"}})
+
+	// New rows and tables become visible once they have been replicated.
+	c.SrcTenantSQL.Exec(t, `INSERT INTO d.t2 VALUES (3)`)
+	c.SrcTenantSQL.Exec(t, `CREATE TABLE d.t3 AS SELECT i FROM d.t2`)
+	srcTime = c.SrcCluster.Server(0).Clock().Now()
+	c.WaitUntilReplicatedTime(srcTime, jobspb.JobID(ingestionJobID))
+	waitForRows(`SELECT * FROM d.t2 ORDER BY i`, [][]string{{"2"}, {"3"}})
+	waitForRows(`SELECT * FROM d.t3 ORDER BY i`, [][]string{{"2"}, {"3"}})
+
+	// The replicated tables cannot be written to.
+	readerSQL.ExpectErr(t, "cannot mutate read-only table", `INSERT INTO d.t2 VALUES (4)`)
+	readerSQL.ExpectErr(t, "cannot mutate read-only table", `DELETE FROM d.t1 WHERE true`)
+}
+
 func TestTenantStreamingPauseOnPermanentJobError(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
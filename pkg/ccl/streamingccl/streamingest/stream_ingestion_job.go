@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
 	bulkutil "github.com/cockroachdb/cockroach/pkg/util/bulk"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
@@ -271,9 +272,18 @@ func (s *streamIngestionResumer) Resume(ctx context.Context, execCtx interface{}
 		return s.handleResumeError(ctx, jobExecCtx, err)
 	}
 
-	// Start ingesting KVs from the replication stream.
-	err = ingestWithRetries(ctx, jobExecCtx, s)
-	if err != nil {
+	// Start ingesting KVs from the replication stream, while keeping the
+	// catalog of the reader tenant, if any, up to date with the replicated data.
+	ingestDone := make(chan struct{})
+	g := ctxgroup.WithContext(ctx)
+	g.GoCtx(func(ctx context.Context) error {
+		defer close(ingestDone)
+		return ingestWithRetries(ctx, jobExecCtx, s)
+	})
+	g.GoCtx(func(ctx context.Context) error {
+		return s.maintainReaderCatalog(ctx, jobExecCtx, ingestDone)
+	})
+	if err := g.Wait(); err != nil {
 		return s.handleResumeError(ctx, jobExecCtx, err)
 	}
 	return nil
@@ -10,6 +10,7 @@ package streamingest
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl/streamclient"
@@ -226,6 +227,14 @@ func ingestionPlanHook(
 			return err
 		}
 
+		var readerTenantID roachpb.TenantID
+		if options.ReadVirtualCluster() {
+			readerTenantID, err = createReaderTenant(ctx, p, roachpb.TenantName(dstTenantName), destinationTenantID)
+			if err != nil {
+				return err
+			}
+		}
+
 		streamIngestionDetails := jobspb.StreamIngestionDetails{
 			StreamAddress:         string(streamAddress),
 			StreamID:              uint64(replicationProducerSpec.StreamID),
@@ -235,6 +244,7 @@ func ingestionPlanHook(
 			DestinationTenantName: roachpb.TenantName(dstTenantName),
 			ReplicationTTLSeconds: retentionTTLSeconds,
 			ReplicationStartTime:  replicationProducerSpec.ReplicationStartTime,
+			ReadTenantID:          readerTenantID,
 		}
 
 		jobDescription, err := streamIngestionJobDescription(p, from, ingestionStmt)
@@ -258,6 +268,40 @@ func ingestionPlanHook(
 	return fn, nil, nil, false, nil
 }
 
+// createReaderTenant creates the reader tenant of the replication stream into
+// the given destination tenant. The reader tenant serves read-only queries
+// over the data replicated into the destination tenant's keyspace, and is
+// granted read access to that keyspace.
+func createReaderTenant(
+	ctx context.Context,
+	p sql.PlanHookState,
+	dstTenantName roachpb.TenantName,
+	dstTenantID roachpb.TenantID,
+) (roachpb.TenantID, error) {
+	readerTenantName := roachpb.TenantName(string(dstTenantName) + "-readonly")
+	if err := readerTenantName.IsValid(); err != nil {
+		return roachpb.TenantID{}, err
+	}
+	params, err := json.Marshal(map[string]string{"name": string(readerTenantName)})
+	if err != nil {
+		return roachpb.TenantID{}, err
+	}
+	readerTenantID, err := p.ExtendedEvalContext().Tenant.CreateTenant(ctx, string(params))
+	if err != nil {
+		return roachpb.TenantID{}, errors.Wrapf(err, "creating reader virtual cluster %q", readerTenantName)
+	}
+
+	info, err := sql.GetTenantRecordByID(ctx, p.InternalSQLTxn(), readerTenantID, p.ExecCfg().Settings)
+	if err != nil {
+		return roachpb.TenantID{}, err
+	}
+	info.Capabilities.ReadFromTenant = dstTenantID
+	if err := sql.UpdateTenantRecord(ctx, p.ExecCfg().Settings, p.InternalSQLTxn(), info); err != nil {
+		return roachpb.TenantID{}, err
+	}
+	return readerTenantID, nil
+}
+
 func init() {
 	sql.AddPlanHook("ingestion", ingestionPlanHook, ingestionTypeCheck)
 }
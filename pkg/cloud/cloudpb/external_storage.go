@@ -52,6 +52,10 @@ func (m *ExternalStorage) AccessIsWithExplicitAuth() bool {
 		// Arbitrary network endpoints may be accessible only via the node and thus
 		// make use of its implicit access to them.
 		return false
+	case ExternalStorageProvider_sftp, ExternalStorageProvider_hdfs:
+		// Like HTTP, these are network endpoints which may only be reachable from
+		// the node's network.
+		return false
 	case ExternalStorageProvider_nodelocal:
 		// The node's local filesystem is obviously accessed implicitly as the node.
 		return false
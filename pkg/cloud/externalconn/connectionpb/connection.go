@@ -16,9 +16,11 @@ import "github.com/cockroachdb/errors"
 func (d *ConnectionDetails) Type() ConnectionType {
 	switch d.Provider {
 	case ConnectionProvider_nodelocal, ConnectionProvider_s3, ConnectionProvider_userfile,
-		ConnectionProvider_gs, ConnectionProvider_azure_storage:
+		ConnectionProvider_gs, ConnectionProvider_azure_storage, ConnectionProvider_sftp,
+		ConnectionProvider_hdfs:
 		return TypeStorage
-	case ConnectionProvider_gcp_kms, ConnectionProvider_aws_kms, ConnectionProvider_azure_kms:
+	case ConnectionProvider_gcp_kms, ConnectionProvider_aws_kms, ConnectionProvider_azure_kms,
+		ConnectionProvider_vault_kms, ConnectionProvider_kmip_kms:
 		return TypeKMS
 	case ConnectionProvider_kafka, ConnectionProvider_http, ConnectionProvider_https,
 		ConnectionProvider_webhookhttp, ConnectionProvider_webhookhttps, ConnectionProvider_gcpubsub:
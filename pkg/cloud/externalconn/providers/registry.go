@@ -20,6 +20,10 @@ import (
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/amazon"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/azure"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/gcp"
+	_ "github.com/cockroachdb/cockroach/pkg/cloud/hdfs"
+	_ "github.com/cockroachdb/cockroach/pkg/cloud/kmip"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/nodelocal"
+	_ "github.com/cockroachdb/cockroach/pkg/cloud/sftp"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/userfile"
+	_ "github.com/cockroachdb/cockroach/pkg/cloud/vault"
 )
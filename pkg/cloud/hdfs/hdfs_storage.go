@@ -0,0 +1,416 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package hdfs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	scheme = "hdfs"
+
+	// DelegationTokenParam is the query parameter for an HDFS delegation token
+	// used to authenticate requests.
+	DelegationTokenParam = "HDFS_DELEGATION_TOKEN"
+	// UseTLSParam is the query parameter which, when set to true, makes
+	// requests to the NameNode use HTTPS.
+	UseTLSParam = "HDFS_USE_TLS"
+
+	webHDFSPrefix = "/webhdfs/v1"
+)
+
+func parseHDFSURL(_ cloud.ExternalStorageURIContext, uri *url.URL) (cloudpb.ExternalStorage, error) {
+	hdfsURL := cloud.ConsumeURL{URL: uri}
+	conf := cloudpb.ExternalStorage{}
+	conf.Provider = cloudpb.ExternalStorageProvider_hdfs
+	if uri.Host == "" {
+		return conf, errors.Errorf(
+			"host component of hdfs URI must be the address of the NameNode's WebHDFS endpoint: %s",
+			uri.Redacted())
+	}
+	cfg := &cloudpb.ExternalStorage_HDFS{
+		NameNode:        uri.Host,
+		User:            uri.User.Username(),
+		DelegationToken: hdfsURL.ConsumeParam(DelegationTokenParam),
+		Path:            uri.Path,
+	}
+	if useTLS := hdfsURL.ConsumeParam(UseTLSParam); useTLS != "" {
+		var err error
+		if cfg.UseTLS, err = strconv.ParseBool(useTLS); err != nil {
+			return conf, errors.Wrapf(err, "parsing value of %s", UseTLSParam)
+		}
+	}
+
+	// Validate that all the passed in parameters are supported.
+	if unknownParams := hdfsURL.RemainingQueryParams(); len(unknownParams) > 0 {
+		return cloudpb.ExternalStorage{}, errors.Errorf(
+			`unknown HDFS query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+	conf.HDFSConfig = cfg
+	return conf, nil
+}
+
+// hdfsStorage is an ExternalStorage which stores files in HDFS. It accesses
+// HDFS through the WebHDFS REST API, which the NameNode serves over HTTP, so
+// no Hadoop client libraries or configuration are needed. Requests are
+// authenticated either as a user, using Hadoop's simple authentication, or
+// with a delegation token.
+type hdfsStorage struct {
+	conf     *cloudpb.ExternalStorage_HDFS
+	ioConf   base.ExternalIODirConfig
+	settings *cluster.Settings
+	prefix   string
+	client   *http.Client
+}
+
+var _ cloud.ExternalStorage = &hdfsStorage{}
+
+func makeHDFSStorage(
+	ctx context.Context, args cloud.ExternalStorageContext, dest cloudpb.ExternalStorage,
+) (cloud.ExternalStorage, error) {
+	telemetry.Count("external-io.hdfs")
+	conf := dest.HDFSConfig
+	if conf == nil {
+		return nil, errors.Errorf("hdfs upload requested but info missing")
+	}
+	if conf.NameNode == "" {
+		return nil, errors.Errorf("hdfs storage requested but NameNode not provided")
+	}
+	client, err := cloud.MakeHTTPClient(args.Settings)
+	if err != nil {
+		return nil, err
+	}
+	// The NameNode redirects requests to read or write the contents of files to
+	// a DataNode. Writes must be redirected by hand, since the contents of the
+	// file should only be sent to the DataNode, so redirects are never followed
+	// automatically.
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &hdfsStorage{
+		conf:     conf,
+		ioConf:   args.IOConf,
+		settings: args.Settings,
+		prefix:   path.Join("/", conf.Path),
+		client:   client,
+	}, nil
+}
+
+func (h *hdfsStorage) Conf() cloudpb.ExternalStorage {
+	return cloudpb.ExternalStorage{
+		Provider:   cloudpb.ExternalStorageProvider_hdfs,
+		HDFSConfig: h.conf,
+	}
+}
+
+func (h *hdfsStorage) ExternalIOConf() base.ExternalIODirConfig {
+	return h.ioConf
+}
+
+func (h *hdfsStorage) RequiresExternalIOAccounting() bool { return true }
+
+func (h *hdfsStorage) Settings() *cluster.Settings {
+	return h.settings
+}
+
+// opURL returns the WebHDFS URL for the operation on the file at name.
+func (h *hdfsStorage) opURL(name, op string, params url.Values) string {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("op", op)
+	if h.conf.DelegationToken != "" {
+		params.Set("delegation", h.conf.DelegationToken)
+	} else if h.conf.User != "" {
+		params.Set("user.name", h.conf.User)
+	}
+	u := url.URL{
+		Scheme:   "http",
+		Host:     h.conf.NameNode,
+		Path:     webHDFSPrefix + name,
+		RawQuery: params.Encode(),
+	}
+	if h.conf.UseTLS {
+		u.Scheme = "https"
+	}
+	return u.String()
+}
+
+// remoteException is the body of WebHDFS error responses.
+type remoteException struct {
+	RemoteException struct {
+		Exception string `json:"exception"`
+		Message   string `json:"message"`
+	} `json:"RemoteException"`
+}
+
+// req issues a request and returns the response if it was successful or a
+// redirect. Otherwise the response body is closed, and its error returned.
+func (h *hdfsStorage) req(
+	ctx context.Context, method, url string, body io.Reader,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error constructing request %s %q", method, url)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %s", method, redactURL(url))
+	}
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusTemporaryRedirect:
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	var exc remoteException
+	if json.Unmarshal(raw, &exc) != nil || exc.RemoteException.Exception == "" {
+		err = errors.Errorf("error response from WebHDFS: %s %q", resp.Status, raw)
+	} else {
+		err = errors.Errorf("error response from WebHDFS: %s: %s: %s",
+			resp.Status, exc.RemoteException.Exception, exc.RemoteException.Message)
+	}
+	if resp.StatusCode == http.StatusNotFound ||
+		exc.RemoteException.Exception == "FileNotFoundException" {
+		// nolint:errwrap
+		err = errors.Wrapf(
+			errors.Wrap(cloud.ErrFileDoesNotExist, "hdfs storage file does not exist"),
+			"%v",
+			err.Error(),
+		)
+	}
+	return nil, err
+}
+
+// redactURL removes the query parameters, which may contain a delegation
+// token, from a WebHDFS URL.
+func redactURL(u string) string {
+	if i := strings.IndexByte(u, '?'); i >= 0 {
+		return u[:i]
+	}
+	return u
+}
+
+// follow follows the redirect in resp to a DataNode, if there is one.
+func (h *hdfsStorage) follow(
+	ctx context.Context, resp *http.Response, method string, body io.Reader,
+) (*http.Response, error) {
+	if resp.StatusCode != http.StatusTemporaryRedirect {
+		return resp, nil
+	}
+	resp.Body.Close()
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil, errors.Errorf("WebHDFS redirect is missing a location")
+	}
+	return h.req(ctx, method, location, body)
+}
+
+func (h *hdfsStorage) open(ctx context.Context, name string, pos int64) (io.ReadCloser, error) {
+	params := url.Values{}
+	if pos > 0 {
+		params.Set("offset", strconv.FormatInt(pos, 10))
+	}
+	resp, err := h.req(ctx, http.MethodGet, h.opURL(name, "OPEN", params), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp, err = h.follow(ctx, resp, http.MethodGet, nil); err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (h *hdfsStorage) ReadFile(
+	ctx context.Context, basename string, opts cloud.ReadOptions,
+) (_ ioctx.ReadCloserCtx, fileSize int64, _ error) {
+	ctx, sp := tracing.ChildSpan(ctx, "hdfs.ReadFile")
+	defer sp.Finish()
+
+	name := path.Join(h.prefix, basename)
+	if !opts.NoFileSize {
+		var err error
+		if fileSize, err = h.Size(ctx, basename); err != nil {
+			return nil, 0, err
+		}
+	}
+	body, err := h.open(ctx, name, opts.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	opener := func(ctx context.Context, pos int64) (io.ReadCloser, int64, error) {
+		r, err := h.open(ctx, name, pos)
+		return r, fileSize, err
+	}
+	return cloud.NewResumingReader(ctx, opener, body, opts.Offset, fileSize, name,
+		cloud.ResumingReaderRetryOnErrFnForSettings(ctx, h.settings), nil), fileSize, nil
+}
+
+func (h *hdfsStorage) Writer(ctx context.Context, basename string) (io.WriteCloser, error) {
+	name := path.Join(h.prefix, basename)
+	return cloud.BackgroundPipe(ctx, func(ctx context.Context, r io.Reader) error {
+		// Creating a file also creates any missing parent directories.
+		resp, err := h.req(ctx, http.MethodPut,
+			h.opURL(name, "CREATE", url.Values{"overwrite": {"true"}}), nil)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusTemporaryRedirect {
+			resp.Body.Close()
+			return errors.Errorf("expected WebHDFS to redirect the creation of %s, got %s", name, resp.Status)
+		}
+		if resp, err = h.follow(ctx, resp, http.MethodPut, r); err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}), nil
+}
+
+type fileStatus struct {
+	PathSuffix string `json:"pathSuffix"`
+	Type       string `json:"type"`
+	Length     int64  `json:"length"`
+}
+
+// getJSON issues a request to the NameNode and decodes its JSON response.
+func (h *hdfsStorage) getJSON(
+	ctx context.Context, method, name, op string, params url.Values, out interface{},
+) error {
+	return timeutil.RunWithTimeout(ctx, fmt.Sprintf("%s %s", op, name),
+		cloud.Timeout.Get(&h.settings.SV), func(ctx context.Context) error {
+			resp, err := h.req(ctx, method, h.opURL(name, op, params), nil)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return errors.Errorf("unexpected response from WebHDFS to %s: %s", op, resp.Status)
+			}
+			return errors.Wrapf(json.NewDecoder(resp.Body).Decode(out), "decoding response to %s", op)
+		})
+}
+
+func (h *hdfsStorage) List(ctx context.Context, prefix, delim string, fn cloud.ListingFn) error {
+	ctx, sp := tracing.ChildSpan(ctx, "hdfs.List")
+	defer sp.Finish()
+
+	dest := cloud.JoinPathPreservingTrailingSlash(h.prefix, prefix)
+	// Every file whose name begins with dest is under the directory containing
+	// dest, if dest does not name a directory itself.
+	dir := dest
+	if !strings.HasSuffix(dir, "/") {
+		dir = path.Dir(dir)
+	}
+
+	var files []string
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		var res struct {
+			FileStatuses struct {
+				FileStatus []fileStatus `json:"FileStatus"`
+			} `json:"FileStatuses"`
+		}
+		if err := h.getJSON(ctx, http.MethodGet, dir, "LISTSTATUS", nil, &res); err != nil {
+			if errors.Is(err, cloud.ErrFileDoesNotExist) {
+				return nil
+			}
+			return errors.Wrapf(err, "listing %s", dir)
+		}
+		for _, st := range res.FileStatuses.FileStatus {
+			name := path.Join(dir, st.PathSuffix)
+			if st.Type == "DIRECTORY" {
+				// Only descend into directories that may contain matching files.
+				if strings.HasPrefix(name+"/", dest) || strings.HasPrefix(dest, name+"/") {
+					if err := walk(name); err != nil {
+						return err
+					}
+				}
+			} else if strings.HasPrefix(name, dest) {
+				files = append(files, name)
+			}
+		}
+		return nil
+	}
+	if err := walk(dir); err != nil {
+		return err
+	}
+
+	// Sort results so that we can group as we go.
+	sort.Strings(files)
+	var prevPrefix string
+	for _, f := range files {
+		f = strings.TrimPrefix(f, dest)
+		if delim != "" {
+			if i := strings.Index(f, delim); i >= 0 {
+				f = f[:i+len(delim)]
+			}
+			if f == prevPrefix {
+				continue
+			}
+			prevPrefix = f
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *hdfsStorage) Delete(ctx context.Context, basename string) error {
+	var res struct {
+		Boolean bool `json:"boolean"`
+	}
+	// Deleting a file which does not exist returns false rather than an error.
+	return h.getJSON(ctx, http.MethodDelete, path.Join(h.prefix, basename), "DELETE",
+		url.Values{"recursive": {"false"}}, &res)
+}
+
+func (h *hdfsStorage) Size(ctx context.Context, basename string) (int64, error) {
+	var res struct {
+		FileStatus fileStatus `json:"FileStatus"`
+	}
+	if err := h.getJSON(ctx, http.MethodGet, path.Join(h.prefix, basename), "GETFILESTATUS",
+		nil, &res); err != nil {
+		return 0, err
+	}
+	return res.FileStatus.Length, nil
+}
+
+func (h *hdfsStorage) Close() error {
+	return nil
+}
+
+func init() {
+	cloud.RegisterExternalStorageProvider(cloudpb.ExternalStorageProvider_hdfs,
+		parseHDFSURL, makeHDFSStorage, cloud.RedactedParams(DelegationTokenParam), scheme)
+}
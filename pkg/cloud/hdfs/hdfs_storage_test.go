@@ -0,0 +1,226 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package hdfs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudtestutils"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testUser  = "backup"
+	testToken = "token"
+)
+
+// fakeWebHDFS serves the subset of the WebHDFS API used by hdfsStorage from
+// memory. Like a real NameNode, it redirects reads and writes of the contents
+// of files to a "DataNode", which here is another path on the same server.
+type fakeWebHDFS struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func writeRemoteException(w http.ResponseWriter, code int, exception, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"RemoteException": map[string]string{"exception": exception, "message": msg},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (f *fakeWebHDFS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if strings.HasPrefix(r.URL.Path, "/datanode/") {
+		name := strings.TrimPrefix(r.URL.Path, "/datanode")
+		switch r.Method {
+		case http.MethodGet:
+			data := f.files[name]
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			if offset > len(data) {
+				offset = len(data)
+			}
+			_, _ = w.Write(data[offset:])
+		case http.MethodPut:
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			f.files[name] = data
+			w.WriteHeader(http.StatusCreated)
+		}
+		return
+	}
+
+	if !strings.HasPrefix(r.URL.Path, webHDFSPrefix+"/") {
+		http.NotFound(w, r)
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, webHDFSPrefix)
+	q := r.URL.Query()
+	if q.Get("user.name") != testUser && q.Get("delegation") != testToken {
+		writeRemoteException(w, http.StatusForbidden, "SecurityException", "access denied")
+		return
+	}
+	redirect := func() {
+		loc := url.URL{Scheme: "http", Host: r.Host, Path: "/datanode" + name}
+		if offset := q.Get("offset"); offset != "" {
+			loc.RawQuery = url.Values{"offset": {offset}}.Encode()
+		}
+		http.Redirect(w, r, loc.String(), http.StatusTemporaryRedirect)
+	}
+	notFound := func() {
+		writeRemoteException(w, http.StatusNotFound, "FileNotFoundException",
+			fmt.Sprintf("File does not exist: %s", name))
+	}
+
+	switch op := q.Get("op"); op {
+	case "OPEN":
+		if _, ok := f.files[name]; !ok {
+			notFound()
+			return
+		}
+		redirect()
+	case "CREATE":
+		redirect()
+	case "GETFILESTATUS":
+		data, ok := f.files[name]
+		if !ok {
+			notFound()
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"FileStatus": fileStatus{Type: "FILE", Length: int64(len(data))},
+		})
+	case "LISTSTATUS":
+		children := map[string]fileStatus{}
+		dir := strings.TrimSuffix(name, "/") + "/"
+		for p, data := range f.files {
+			if !strings.HasPrefix(p, dir) {
+				continue
+			}
+			rest := strings.TrimPrefix(p, dir)
+			if i := strings.IndexByte(rest, '/'); i >= 0 {
+				children[rest[:i]] = fileStatus{PathSuffix: rest[:i], Type: "DIRECTORY"}
+			} else {
+				children[rest] = fileStatus{PathSuffix: rest, Type: "FILE", Length: int64(len(data))}
+			}
+		}
+		if len(children) == 0 {
+			notFound()
+			return
+		}
+		statuses := make([]fileStatus, 0, len(children))
+		for _, st := range children {
+			statuses = append(statuses, st)
+		}
+		sort.Slice(statuses, func(i, j int) bool { return statuses[i].PathSuffix < statuses[j].PathSuffix })
+		writeJSON(w, map[string]interface{}{
+			"FileStatuses": map[string]interface{}{"FileStatus": statuses},
+		})
+	case "DELETE":
+		_, ok := f.files[name]
+		delete(f.files, name)
+		writeJSON(w, map[string]bool{"boolean": ok})
+	default:
+		writeRemoteException(w, http.StatusBadRequest, "IllegalArgumentException",
+			fmt.Sprintf("unsupported operation %s", op))
+	}
+}
+
+func TestHDFSStorage(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	srv := httptest.NewServer(&fakeWebHDFS{files: map[string][]byte{}})
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	user := username.RootUserName()
+	testSettings := cluster.MakeTestingClusterSettings()
+
+	t.Run("user", func(t *testing.T) {
+		uri := fmt.Sprintf("hdfs://%s@%s/backups", testUser, host)
+		cloudtestutils.CheckExportStore(t, uri, false, user, nil /* db */, testSettings)
+		cloudtestutils.CheckListFiles(t, uri, user, nil /* db */, testSettings)
+	})
+
+	t.Run("delegation-token", func(t *testing.T) {
+		uri := fmt.Sprintf("hdfs://%s/tokens?%s=%s", host, DelegationTokenParam, testToken)
+		cloudtestutils.CheckExportStore(t, uri, true, user, nil /* db */, testSettings)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		ctx := context.Background()
+		uri := fmt.Sprintf("hdfs://other@%s/denied", host)
+		s, err := cloud.ExternalStorageFromURI(ctx, uri, base.ExternalIODirConfig{}, testSettings,
+			nil /* blobClientFactory */, user, nil /* db */, nil /* limiters */, cloud.NilMetrics)
+		require.NoError(t, err)
+		defer s.Close()
+		_, err = s.Size(ctx, "file")
+		require.ErrorContains(t, err, "SecurityException")
+	})
+}
+
+func TestParseHDFSURL(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		uri string
+		err string
+	}{
+		{uri: "hdfs://u@namenode:9870/dir"},
+		{uri: "hdfs://namenode:9871/dir?HDFS_DELEGATION_TOKEN=t&HDFS_USE_TLS=true"},
+		{uri: "hdfs:///dir", err: "host component"},
+		{uri: "hdfs://namenode/dir?HDFS_USE_TLS=maybe", err: "parsing value of HDFS_USE_TLS"},
+		{uri: "hdfs://namenode/dir?FOO=bar", err: "unknown HDFS query parameters: FOO"},
+	} {
+		t.Run(tc.uri, func(t *testing.T) {
+			_, err := cloud.ExternalStorageConfFromURI(tc.uri, username.RootUserName())
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+
+	t.Run("redacted", func(t *testing.T) {
+		sanitized, err := cloud.SanitizeExternalStorageURI(
+			"hdfs://namenode/dir?HDFS_DELEGATION_TOKEN=secret", nil)
+		require.NoError(t, err)
+		require.Equal(t, "hdfs://namenode/dir?HDFS_DELEGATION_TOKEN=redacted", sanitized)
+	})
+}
@@ -126,6 +126,16 @@ func RegisterExternalStorageProvider(
 	}
 }
 
+// RegisterRedactedParams registers query parameters that should be redacted
+// from URIs whenever they are displayed to a user. It is used by providers of
+// URIs that do not name external storage, such as KMS implementations, whose
+// parameters are not registered by RegisterExternalStorageProvider.
+func RegisterRedactedParams(redactedParams map[string]struct{}) {
+	for param := range redactedParams {
+		redactedQueryParams[param] = struct{}{}
+	}
+}
+
 // ExternalStorageConfFromURI generates an ExternalStorage config from a URI string.
 func ExternalStorageConfFromURI(
 	path string, user username.SQLUsername,
@@ -0,0 +1,323 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kmip
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/errors"
+	"github.com/gemalto/kmip-go"
+	"github.com/gemalto/kmip-go/kmip14"
+	"github.com/gemalto/kmip-go/ttlv"
+)
+
+const (
+	kmsScheme = "kmip"
+
+	// ClientCertParam is the query parameter for the base64 encoded PEM client
+	// certificate used to authenticate to the KMIP server.
+	ClientCertParam = "KMIP_CLIENT_CERT"
+	// ClientKeyParam is the query parameter for the base64 encoded PEM private
+	// key of the client certificate.
+	ClientKeyParam = "KMIP_CLIENT_KEY"
+	// CACertParam is the query parameter for the base64 encoded PEM certificate
+	// of the CA that signed the KMIP server's certificate. If it is not set, the
+	// system's root CAs are used.
+	CACertParam = "KMIP_CA_CERT"
+
+	defaultPort = "5696"
+)
+
+// Cryptographic parameters of the requests. Data is encrypted with AES in GCM
+// mode, so that a ciphertext which was tampered with, or which was encrypted
+// with a different key, fails to decrypt rather than yielding garbage. The IV is
+// generated by the client and stored alongside the ciphertext, as is the tag.
+const (
+	ivLen  = 12
+	tagLen = 16
+)
+
+// protocolVersion is the version of KMIP spoken to the server. Version 1.4
+// added the authenticated encryption fields used by GCM.
+var protocolVersion = kmip.ProtocolVersion{ProtocolVersionMajor: 1, ProtocolVersionMinor: 4}
+
+var cryptographicParameters = &kmip.CryptographicParameters{
+	BlockCipherMode:        kmip14.BlockCipherModeGCM,
+	CryptographicAlgorithm: kmip14.CryptographicAlgorithmAES,
+	TagLength:              tagLen,
+}
+
+// encryptRequestPayload is the payload of an Encrypt request.
+type encryptRequestPayload struct {
+	UniqueIdentifier        string
+	CryptographicParameters *kmip.CryptographicParameters
+	Data                    []byte
+	IVCounterNonce          []byte
+}
+
+// encryptResponsePayload is the payload of an Encrypt response.
+type encryptResponsePayload struct {
+	UniqueIdentifier           string
+	Data                       []byte
+	IVCounterNonce             []byte `ttlv:",omitempty"`
+	AuthenticatedEncryptionTag []byte
+}
+
+// decryptRequestPayload is the payload of a Decrypt request.
+type decryptRequestPayload struct {
+	UniqueIdentifier           string
+	CryptographicParameters    *kmip.CryptographicParameters
+	Data                       []byte
+	IVCounterNonce             []byte
+	AuthenticatedEncryptionTag []byte
+}
+
+// decryptResponsePayload is the payload of a Decrypt response.
+type decryptResponsePayload struct {
+	UniqueIdentifier string
+	Data             []byte
+}
+
+// kmipKMS is a KMS which uses a symmetric AES key held by a server, such as a
+// hardware appliance, that speaks the OASIS Key Management Interoperability
+// Protocol. The key never leaves the server; data is sent to the server to be
+// encrypted or decrypted using the Encrypt and Decrypt operations of KMIP 1.4,
+// with AES-GCM.
+//
+// The server is identified by the host of the URI and the key by its path,
+// which is the key's unique identifier. Rotating a key in KMIP creates a new
+// key with a new identifier, so data keys stored in a backup are re-wrapped
+// with the new key by running ALTER BACKUP ... ADD NEW_KMS with the new URI
+// and OLD_KMS with the old one.
+type kmipKMS struct {
+	addr    string
+	keyID   string
+	tlsConf *tls.Config
+}
+
+var _ cloud.KMS = &kmipKMS{}
+
+func init() {
+	cloud.RegisterKMSFromURIFactory(MakeKMIPKMS, kmsScheme)
+	cloud.RegisterRedactedParams(cloud.RedactedParams(ClientKeyParam))
+}
+
+type kmsURIParams struct {
+	clientCert []byte
+	clientKey  []byte
+	caCert     []byte
+}
+
+func resolveKMSURIParams(kmsURI cloud.ConsumeURL) (kmsURIParams, error) {
+	var params kmsURIParams
+	for _, p := range []struct {
+		name string
+		dest *[]byte
+	}{
+		{ClientCertParam, &params.clientCert},
+		{ClientKeyParam, &params.clientKey},
+		{CACertParam, &params.caCert},
+	} {
+		if v := kmsURI.ConsumeParam(p.name); v != "" {
+			var err error
+			if *p.dest, err = base64.StdEncoding.DecodeString(v); err != nil {
+				return kmsURIParams{}, errors.Wrapf(err, "decoding value of %s", p.name)
+			}
+		}
+	}
+
+	// Validate that all the passed in parameters are supported.
+	if unknownParams := kmsURI.RemainingQueryParams(); len(unknownParams) > 0 {
+		return kmsURIParams{}, errors.Errorf(
+			`unknown KMS query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+	if params.clientCert == nil || params.clientKey == nil {
+		return kmsURIParams{}, errors.Errorf(
+			"%s and %s must be set to authenticate to the KMIP server", ClientCertParam, ClientKeyParam)
+	}
+	return params, nil
+}
+
+// MakeKMIPKMS is the factory method which returns a configured, ready-to-use
+// KMIP KMS object. The URI has the form
+// kmip://<host>[:<port>]/<key-unique-identifier>?KMIP_CLIENT_CERT=...&KMIP_CLIENT_KEY=...
+func MakeKMIPKMS(ctx context.Context, uri string, env cloud.KMSEnv) (cloud.KMS, error) {
+	if env.KMSConfig().DisableOutbound {
+		return nil, errors.New("external IO must be enabled to use KMIP KMS")
+	}
+	kmsURI, err := url.ParseRequestURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if kmsURI.Hostname() == "" {
+		return nil, errors.New("host component of the KMIP KMS URI must be the address of the KMIP server")
+	}
+	keyID := strings.TrimPrefix(kmsURI.Path, "/")
+	if keyID == "" {
+		return nil, errors.New("path component of the KMIP KMS URI must contain the unique identifier of the key")
+	}
+
+	kmsURIParams, err := resolveKMSURIParams(cloud.ConsumeURL{URL: kmsURI})
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.X509KeyPair(kmsURIParams.clientCert, kmsURIParams.clientKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading KMIP client certificate")
+	}
+	tlsConf := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ServerName:   kmsURI.Hostname(),
+		MinVersion:   tls.VersionTLS12,
+	}
+	if kmsURIParams.caCert != nil {
+		tlsConf.RootCAs = x509.NewCertPool()
+		if !tlsConf.RootCAs.AppendCertsFromPEM(kmsURIParams.caCert) {
+			return nil, errors.Errorf("failed to parse CA certificate from %s", CACertParam)
+		}
+	}
+
+	addr := kmsURI.Host
+	if kmsURI.Port() == "" {
+		addr = net.JoinHostPort(kmsURI.Hostname(), defaultPort)
+	}
+	return &kmipKMS{addr: addr, keyID: keyID, tlsConf: tlsConf}, nil
+}
+
+// MasterKeyID implements the KMS interface.
+func (k *kmipKMS) MasterKeyID() string {
+	return k.keyID
+}
+
+// roundTrip sends a request containing a single batch item for the operation
+// with the given payload, and decodes the payload of the response into resp.
+// Every request uses a new connection, since the KMS is used rarely.
+func (k *kmipKMS) roundTrip(
+	ctx context.Context, operation kmip14.Operation, req interface{}, resp interface{},
+) error {
+	msg, err := ttlv.Marshal(kmip.RequestMessage{
+		RequestHeader: kmip.RequestHeader{
+			ProtocolVersion: protocolVersion,
+			BatchCount:      1,
+		},
+		BatchItem: []kmip.RequestBatchItem{{
+			Operation:      operation,
+			RequestPayload: req,
+		}},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "encoding KMIP %s request", operation)
+	}
+
+	dialer := tls.Dialer{Config: k.tlsConf}
+	conn, err := dialer.DialContext(ctx, "tcp", k.addr)
+	if err != nil {
+		return cloud.KMSInaccessible(errors.Wrapf(err, "connecting to KMIP server %s", k.addr))
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	if _, err := conn.Write(msg); err != nil {
+		return cloud.KMSInaccessible(errors.Wrapf(err, "KMIP %s", operation))
+	}
+	raw, err := ttlv.NewDecoder(conn).NextTTLV()
+	if err != nil {
+		return cloud.KMSInaccessible(errors.Wrapf(err, "KMIP %s", operation))
+	}
+
+	var respMsg kmip.ResponseMessage
+	if err := ttlv.Unmarshal(raw, &respMsg); err != nil {
+		return errors.Wrapf(err, "decoding KMIP %s response", operation)
+	}
+	if len(respMsg.BatchItem) != 1 {
+		return errors.Errorf("KMIP %s response has %d batch items", operation, len(respMsg.BatchItem))
+	}
+	item := respMsg.BatchItem[0]
+	if item.ResultStatus != kmip14.ResultStatusSuccess {
+		msg := item.ResultReason.String()
+		if item.ResultMessage != "" {
+			msg += ": " + item.ResultMessage
+		}
+		return cloud.KMSInaccessible(errors.Newf("KMIP %s failed: %s", operation, msg))
+	}
+	payload, ok := item.ResponsePayload.(ttlv.TTLV)
+	if !ok {
+		return errors.Errorf("KMIP %s response is missing a payload", operation)
+	}
+	return errors.Wrapf(ttlv.Unmarshal(payload, resp), "decoding KMIP %s response payload", operation)
+}
+
+// Encrypt implements the KMS interface. The returned ciphertext is the IV,
+// followed by the authentication tag and the data encrypted by the server.
+func (k *kmipKMS) Encrypt(ctx context.Context, data []byte) ([]byte, error) {
+	iv := make([]byte, ivLen)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, errors.Wrap(err, "generating IV")
+	}
+	if data == nil {
+		// A nil slice would be omitted from the request entirely.
+		data = []byte{}
+	}
+	var resp encryptResponsePayload
+	if err := k.roundTrip(ctx, kmip14.OperationEncrypt, encryptRequestPayload{
+		UniqueIdentifier:        k.keyID,
+		CryptographicParameters: cryptographicParameters,
+		Data:                    data,
+		IVCounterNonce:          iv,
+	}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.AuthenticatedEncryptionTag) != tagLen {
+		return nil, errors.Errorf("KMIP Encrypt response has a tag of length %d, expected %d",
+			len(resp.AuthenticatedEncryptionTag), tagLen)
+	}
+	res := make([]byte, 0, ivLen+tagLen+len(resp.Data))
+	res = append(res, iv...)
+	res = append(res, resp.AuthenticatedEncryptionTag...)
+	return append(res, resp.Data...), nil
+}
+
+// Decrypt implements the KMS interface.
+func (k *kmipKMS) Decrypt(ctx context.Context, data []byte) ([]byte, error) {
+	if len(data) < ivLen+tagLen {
+		return nil, errors.New("KMIP ciphertext is too short")
+	}
+	var resp decryptResponsePayload
+	if err := k.roundTrip(ctx, kmip14.OperationDecrypt, decryptRequestPayload{
+		UniqueIdentifier:           k.keyID,
+		CryptographicParameters:    cryptographicParameters,
+		Data:                       data[ivLen+tagLen:],
+		IVCounterNonce:             data[:ivLen],
+		AuthenticatedEncryptionTag: data[ivLen : ivLen+tagLen],
+	}, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data == nil {
+		resp.Data = []byte{}
+	}
+	return resp.Data, nil
+}
+
+// Close implements the KMS interface.
+func (k *kmipKMS) Close() error {
+	return nil
+}
@@ -0,0 +1,349 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kmip
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudtestutils"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/errors"
+	"github.com/gemalto/kmip-go"
+	"github.com/gemalto/kmip-go/kmip14"
+	"github.com/stretchr/testify/require"
+)
+
+// testCert is a certificate and its private key, PEM encoded.
+type testCert struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certPEM  []byte
+	keyPEM   []byte
+	template *x509.Certificate
+}
+
+// makeCert makes a certificate signed by parent, or a self-signed CA
+// certificate if parent is nil.
+func makeCert(t *testing.T, name string, parent *testCert, server bool) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if server {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		template.IPAddresses = []net.IP{net.IPv4(127, 0, 0, 1)}
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.ExtKeyUsage = nil
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.template, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return &testCert{
+		cert:     cert,
+		key:      key,
+		certPEM:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:   pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		template: template,
+	}
+}
+
+// testServer is a KMIP server which implements the Encrypt and Decrypt
+// operations with AES-GCM, using keys held in memory.
+type testServer struct {
+	listener net.Listener
+	srv      *kmip.Server
+	keys     map[string]cipher.AEAD
+	done     chan struct{}
+}
+
+func startTestServer(t *testing.T, ca, serverCert *testCert, keys map[string]cipher.AEAD) *testServer {
+	pair, err := tls.X509KeyPair(serverCert.certPEM, serverCert.keyPEM)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+	l, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{pair},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})
+	require.NoError(t, err)
+
+	s := &testServer{listener: l, keys: keys, done: make(chan struct{})}
+	var mux kmip.OperationMux
+	mux.Handle(kmip14.OperationEncrypt, kmip.ItemHandlerFunc(s.encrypt))
+	mux.Handle(kmip14.OperationDecrypt, kmip.ItemHandlerFunc(s.decrypt))
+	s.srv = &kmip.Server{Handler: &kmip.StandardProtocolHandler{
+		ProtocolVersion: protocolVersion,
+		MessageHandler:  &mux,
+	}}
+	go func() {
+		defer close(s.done)
+		_ = s.srv.Serve(l)
+	}()
+	return s
+}
+
+func (s *testServer) stop() {
+	_ = s.srv.Close()
+	<-s.done
+}
+
+func (s *testServer) key(id string) (cipher.AEAD, error) {
+	aead, ok := s.keys[id]
+	if !ok {
+		return nil, kmip.WithResultReason(
+			errors.Newf("no object with identifier %s", id), kmip14.ResultReasonItemNotFound)
+	}
+	return aead, nil
+}
+
+func checkParams(p *kmip.CryptographicParameters, iv []byte) error {
+	if p == nil || p.BlockCipherMode != kmip14.BlockCipherModeGCM ||
+		p.CryptographicAlgorithm != kmip14.CryptographicAlgorithmAES || p.TagLength != tagLen {
+		return kmip.WithResultReason(
+			errors.New("unsupported cryptographic parameters"), kmip14.ResultReasonFeatureNotSupported)
+	}
+	if len(iv) != ivLen {
+		return kmip.WithResultReason(errors.New("invalid IV"), kmip14.ResultReasonInvalidField)
+	}
+	return nil
+}
+
+func (s *testServer) encrypt(_ context.Context, req *kmip.Request) (*kmip.ResponseBatchItem, error) {
+	var payload encryptRequestPayload
+	if err := req.DecodePayload(&payload); err != nil {
+		return nil, err
+	}
+	aead, err := s.key(payload.UniqueIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkParams(payload.CryptographicParameters, payload.IVCounterNonce); err != nil {
+		return nil, err
+	}
+	sealed := aead.Seal(nil, payload.IVCounterNonce, payload.Data, nil)
+	n := len(sealed) - tagLen
+	return &kmip.ResponseBatchItem{ResponsePayload: encryptResponsePayload{
+		UniqueIdentifier:           payload.UniqueIdentifier,
+		Data:                       sealed[:n],
+		AuthenticatedEncryptionTag: sealed[n:],
+	}}, nil
+}
+
+func (s *testServer) decrypt(_ context.Context, req *kmip.Request) (*kmip.ResponseBatchItem, error) {
+	var payload decryptRequestPayload
+	if err := req.DecodePayload(&payload); err != nil {
+		return nil, err
+	}
+	aead, err := s.key(payload.UniqueIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkParams(payload.CryptographicParameters, payload.IVCounterNonce); err != nil {
+		return nil, err
+	}
+	sealed := append(append([]byte(nil), payload.Data...), payload.AuthenticatedEncryptionTag...)
+	data, err := aead.Open(nil, payload.IVCounterNonce, sealed, nil)
+	if err != nil {
+		return nil, kmip.WithResultReason(
+			errors.New("authentication failed"), kmip14.ResultReasonCryptographicFailure)
+	}
+	return &kmip.ResponseBatchItem{ResponsePayload: decryptResponsePayload{
+		UniqueIdentifier: payload.UniqueIdentifier,
+		Data:             data,
+	}}, nil
+}
+
+func makeKey(t *testing.T) cipher.AEAD {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+	aead, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	return aead
+}
+
+func TestKMIPKMS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	ca := makeCert(t, "ca", nil, false)
+	serverCert := makeCert(t, "server", ca, true)
+	clientCert := makeCert(t, "client", ca, false)
+
+	srv := startTestServer(t, ca, serverCert, map[string]cipher.AEAD{
+		"key-1": makeKey(t),
+		"key-2": makeKey(t),
+	})
+	defer srv.stop()
+
+	env := &cloud.TestKMSEnv{
+		Settings:         cluster.MakeTestingClusterSettings(),
+		ExternalIOConfig: &base.ExternalIODirConfig{},
+	}
+	makeURI := func(keyID string, client *testCert) string {
+		q := url.Values{}
+		q.Set(ClientCertParam, base64.StdEncoding.EncodeToString(client.certPEM))
+		q.Set(ClientKeyParam, base64.StdEncoding.EncodeToString(client.keyPEM))
+		q.Set(CACertParam, base64.StdEncoding.EncodeToString(ca.certPEM))
+		return fmt.Sprintf("kmip://%s/%s?%s", srv.listener.Addr(), keyID, q.Encode())
+	}
+
+	t.Run("encrypt-decrypt", func(t *testing.T) {
+		cloud.KMSEncryptDecrypt(t, makeURI("key-1", clientCert), env)
+
+		k, err := cloud.KMSFromURI(ctx, makeURI("key-1", clientCert), env)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, k.Close()) }()
+		require.Equal(t, "key-1", k.MasterKeyID())
+		for _, n := range []int{0, 1, 16, 32, 100} {
+			data := bytes.Repeat([]byte{'x'}, n)
+			encrypted, err := k.Encrypt(ctx, data)
+			require.NoError(t, err)
+			decrypted, err := k.Decrypt(ctx, encrypted)
+			require.NoError(t, err)
+			require.Equal(t, data, decrypted)
+		}
+	})
+
+	t.Run("unknown-key", func(t *testing.T) {
+		cloudtestutils.RequireKMSInaccessibleErrorContaining(ctx, t,
+			makeURI("key-3", clientCert), "ItemNotFound: no object with identifier key-3")
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		k, err := cloud.KMSFromURI(ctx, makeURI("key-1", clientCert), env)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, k.Close()) }()
+		encrypted, err := k.Encrypt(ctx, []byte("data key"))
+		require.NoError(t, err)
+		// Flipping a bit of the IV, the tag or the data fails authentication.
+		for _, i := range []int{0, ivLen, len(encrypted) - 1} {
+			tampered := append([]byte(nil), encrypted...)
+			tampered[i] ^= 1
+			_, err := k.Decrypt(ctx, tampered)
+			require.ErrorContains(t, err, "CryptographicFailure", "byte %d", i)
+		}
+		_, err = k.Decrypt(ctx, encrypted[:ivLen+tagLen-1])
+		require.ErrorContains(t, err, "too short")
+	})
+
+	// Rotating the key creates a new key with a new identifier. Data keys
+	// encrypted with the old key are re-wrapped by decrypting them with the old
+	// key and encrypting them with the new one, as ALTER BACKUP ... ADD NEW_KMS
+	// does.
+	t.Run("rotate", func(t *testing.T) {
+		oldKMS, err := cloud.KMSFromURI(ctx, makeURI("key-1", clientCert), env)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, oldKMS.Close()) }()
+		newKMS, err := cloud.KMSFromURI(ctx, makeURI("key-2", clientCert), env)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, newKMS.Close()) }()
+
+		dataKey := []byte("data key")
+		wrapped, err := oldKMS.Encrypt(ctx, dataKey)
+		require.NoError(t, err)
+
+		unwrapped, err := oldKMS.Decrypt(ctx, wrapped)
+		require.NoError(t, err)
+		rewrapped, err := newKMS.Encrypt(ctx, unwrapped)
+		require.NoError(t, err)
+		require.NotEqual(t, wrapped, rewrapped)
+
+		decrypted, err := newKMS.Decrypt(ctx, rewrapped)
+		require.NoError(t, err)
+		require.Equal(t, dataKey, decrypted)
+
+		// Each key only decrypts the data keys it wrapped.
+		_, err = oldKMS.Decrypt(ctx, rewrapped)
+		require.ErrorContains(t, err, "CryptographicFailure")
+		_, err = newKMS.Decrypt(ctx, wrapped)
+		require.ErrorContains(t, err, "CryptographicFailure")
+
+		// The old key still decrypts what it wrapped until it is destroyed.
+		decrypted, err = oldKMS.Decrypt(ctx, wrapped)
+		require.NoError(t, err)
+		require.Equal(t, dataKey, decrypted)
+	})
+
+	t.Run("untrusted-client", func(t *testing.T) {
+		untrusted := makeCert(t, "untrusted", nil, false)
+		k, err := cloud.KMSFromURI(ctx, makeURI("key-1", untrusted), env)
+		require.NoError(t, err)
+		_, err = k.Encrypt(ctx, []byte("data"))
+		require.True(t, cloud.IsKMSInaccessible(err), "%v", err)
+		require.NoError(t, k.Close())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		cert := base64.StdEncoding.EncodeToString(clientCert.certPEM)
+		for _, tc := range []struct {
+			uri string
+			err string
+		}{
+			{uri: "kmip:///key-1", err: "host component"},
+			{uri: "kmip://kms/", err: "unique identifier of the key"},
+			{uri: "kmip://kms/key-1?KMIP_CLIENT_CERT=" + url.QueryEscape(cert), err: "KMIP_CLIENT_CERT and KMIP_CLIENT_KEY must be set"},
+			{uri: "kmip://kms/key-1?KMIP_CLIENT_CERT=%21", err: "decoding value of KMIP_CLIENT_CERT"},
+			{uri: "kmip://kms/key-1?FOO=bar", err: "unknown KMS query parameters: FOO"},
+			{uri: "kmip://kms/key-1?KMIP_CLIENT_CERT=Zm9v&KMIP_CLIENT_KEY=Zm9v", err: "loading KMIP client certificate"},
+		} {
+			_, err := cloud.KMSFromURI(ctx, tc.uri, env)
+			require.ErrorContains(t, err, tc.err, tc.uri)
+		}
+	})
+
+	t.Run("redacted", func(t *testing.T) {
+		redacted, err := cloud.RedactKMSURI("kmip://kms:5696/key-1?KMIP_CLIENT_CERT=cert&KMIP_CLIENT_KEY=secret")
+		require.NoError(t, err)
+		require.Equal(t, "kmip://kms:5696/redacted?KMIP_CLIENT_CERT=cert&KMIP_CLIENT_KEY=redacted", redacted)
+	})
+}
@@ -0,0 +1,496 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sftp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/oserror"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	scheme = "sftp"
+
+	// PrivateKeyParam is the query parameter for the base64-encoded PEM
+	// private key used to authenticate to the SSH server.
+	PrivateKeyParam = "SSH_PRIVATE_KEY"
+	// HostKeyParam is the query parameter for the public key of the SSH server,
+	// in authorized_keys format, which the server is required to present.
+	HostKeyParam = "SSH_HOST_KEY"
+	// InsecureSkipHostKeyCheckParam is the query parameter which, when set to
+	// true, allows connecting to an SSH server whose host key is not known.
+	InsecureSkipHostKeyCheckParam = "SSH_INSECURE_SKIP_HOST_KEY_CHECK"
+
+	defaultPort = "22"
+)
+
+func parseSFTPURL(_ cloud.ExternalStorageURIContext, uri *url.URL) (cloudpb.ExternalStorage, error) {
+	sftpURL := cloud.ConsumeURL{URL: uri}
+	conf := cloudpb.ExternalStorage{}
+	conf.Provider = cloudpb.ExternalStorageProvider_sftp
+	if uri.Host == "" {
+		return conf, errors.Errorf("host component of sftp URI must be set: %s", uri.Redacted())
+	}
+	password, _ := uri.User.Password()
+	cfg := &cloudpb.ExternalStorage_SFTP{
+		Host:     uri.Host,
+		User:     uri.User.Username(),
+		Password: password,
+		HostKey:  sftpURL.ConsumeParam(HostKeyParam),
+		Path:     uri.Path,
+	}
+	if encoded := sftpURL.ConsumeParam(PrivateKeyParam); encoded != "" {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return conf, errors.Wrapf(err, "decoding value of %s", PrivateKeyParam)
+		}
+		cfg.PrivateKey = string(key)
+	}
+	if skip := sftpURL.ConsumeParam(InsecureSkipHostKeyCheckParam); skip != "" {
+		if skip != "true" && skip != "false" {
+			return conf, errors.Errorf("%s must be true or false, got %q", InsecureSkipHostKeyCheckParam, skip)
+		}
+		cfg.InsecureSkipHostKeyCheck = skip == "true"
+	}
+	if cfg.User == "" {
+		return conf, errors.Errorf("user component of sftp URI must be set: %s", uri.Redacted())
+	}
+	if cfg.Password == "" && cfg.PrivateKey == "" {
+		return conf, errors.Errorf("sftp URI must specify a password or %s", PrivateKeyParam)
+	}
+	if cfg.HostKey == "" && !cfg.InsecureSkipHostKeyCheck {
+		return conf, errors.Errorf("sftp URI must specify %s, or set %s to skip verifying the server's host key",
+			HostKeyParam, InsecureSkipHostKeyCheckParam)
+	}
+
+	// Validate that all the passed in parameters are supported.
+	if unknownParams := sftpURL.RemainingQueryParams(); len(unknownParams) > 0 {
+		return cloudpb.ExternalStorage{}, errors.Errorf(
+			`unknown SFTP query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+	conf.SFTPConfig = cfg
+	return conf, nil
+}
+
+// connectTimeout bounds the time spent establishing the SSH connection and
+// starting the sftp subsystem, in addition to the deadline of the context of
+// the request which opens the connection.
+const connectTimeout = 30 * time.Second
+
+// sftpStorage is an ExternalStorage which stores files in a directory on an
+// SFTP server. A single SSH connection is opened on first use, and is shared
+// by all requests to the server until it is closed, after which the next
+// request opens a new connection.
+type sftpStorage struct {
+	conf     *cloudpb.ExternalStorage_SFTP
+	ioConf   base.ExternalIODirConfig
+	settings *cluster.Settings
+	prefix   string
+
+	mu struct {
+		syncutil.Mutex
+		conn   *ssh.Client
+		client *sftp.Client
+	}
+}
+
+var _ cloud.ExternalStorage = &sftpStorage{}
+
+func makeSFTPStorage(
+	ctx context.Context, args cloud.ExternalStorageContext, dest cloudpb.ExternalStorage,
+) (cloud.ExternalStorage, error) {
+	telemetry.Count("external-io.sftp")
+	conf := dest.SFTPConfig
+	if conf == nil {
+		return nil, errors.Errorf("sftp upload requested but info missing")
+	}
+	if conf.Host == "" {
+		return nil, errors.Errorf("sftp storage requested but host not provided")
+	}
+	return &sftpStorage{
+		conf:     conf,
+		ioConf:   args.IOConf,
+		settings: args.Settings,
+		prefix:   conf.Path,
+	}, nil
+}
+
+func (s *sftpStorage) Conf() cloudpb.ExternalStorage {
+	return cloudpb.ExternalStorage{
+		Provider:   cloudpb.ExternalStorageProvider_sftp,
+		SFTPConfig: s.conf,
+	}
+}
+
+func (s *sftpStorage) ExternalIOConf() base.ExternalIODirConfig {
+	return s.ioConf
+}
+
+func (s *sftpStorage) RequiresExternalIOAccounting() bool { return true }
+
+func (s *sftpStorage) Settings() *cluster.Settings {
+	return s.settings
+}
+
+func (s *sftpStorage) getClient(ctx context.Context) (*sftp.Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.client != nil {
+		return s.mu.client, nil
+	}
+
+	cfg := &ssh.ClientConfig{User: s.conf.User}
+	if s.conf.PrivateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(s.conf.PrivateKey))
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing ssh private key")
+		}
+		cfg.Auth = append(cfg.Auth, ssh.PublicKeys(signer))
+	}
+	if s.conf.Password != "" {
+		cfg.Auth = append(cfg.Auth, ssh.Password(s.conf.Password))
+	}
+	cfg.HostKeyCallback = ssh.InsecureIgnoreHostKey()
+	if s.conf.HostKey != "" {
+		hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(s.conf.HostKey))
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing ssh host key")
+		}
+		cfg.HostKeyCallback = ssh.FixedHostKey(hostKey)
+	}
+
+	addr := s.conf.Host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, defaultPort)
+	}
+	ctx, cancel := context.WithTimeout(ctx, connectTimeout)
+	defer cancel()
+	var dialer net.Dialer
+	netConn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "connecting to sftp server %s", addr)
+	}
+	conn, c, err := handshake(ctx, netConn, addr, cfg)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.conn, s.mu.client = conn, c
+	go s.forgetOnDisconnect(conn, c)
+	return c, nil
+}
+
+// forgetOnDisconnect waits for the SSH connection to be closed, by either end
+// or by a network error, and then clears it from the storage if it is still
+// the cached connection, so that the next request dials the server again
+// rather than failing on the dead connection forever.
+func (s *sftpStorage) forgetOnDisconnect(conn *ssh.Client, c *sftp.Client) {
+	_ = conn.Wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.conn != conn {
+		// Close already released the connection.
+		return
+	}
+	_ = c.Close()
+	s.mu.conn, s.mu.client = nil, nil
+}
+
+// handshake establishes the SSH connection over netConn and starts the sftp
+// subsystem. Neither the SSH library nor the sftp client observe a context, so
+// the handshake is bounded by setting the deadline of ctx on netConn, and by
+// closing netConn if ctx is canceled before the handshake completes. netConn
+// is closed if an error is returned.
+func handshake(
+	ctx context.Context, netConn net.Conn, addr string, cfg *ssh.ClientConfig,
+) (*ssh.Client, *sftp.Client, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := netConn.SetDeadline(deadline); err != nil {
+			_ = netConn.Close()
+			return nil, nil, err
+		}
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			_ = netConn.Close()
+		case <-done:
+		}
+	}()
+
+	conn, c, err := func() (*ssh.Client, *sftp.Client, error) {
+		sshConn, chans, reqs, err := ssh.NewClientConn(netConn, addr, cfg)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "establishing ssh connection to %s", addr)
+		}
+		conn := ssh.NewClient(sshConn, chans, reqs)
+		c, err := sftp.NewClient(conn)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "starting sftp subsystem")
+		}
+		return conn, c, nil
+	}()
+	close(done)
+	<-stopped
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		// Closing the connection when the context was canceled failed the
+		// handshake at an arbitrary step, or may have raced with its success.
+		err = errors.Wrapf(ctxErr, "connecting to sftp server %s", addr)
+	}
+	if err == nil {
+		err = netConn.SetDeadline(time.Time{})
+	}
+	if err != nil {
+		if c != nil {
+			_ = c.Close()
+		}
+		_ = netConn.Close()
+		return nil, nil, err
+	}
+	return conn, c, nil
+}
+
+func (s *sftpStorage) ReadFile(
+	ctx context.Context, basename string, opts cloud.ReadOptions,
+) (_ ioctx.ReadCloserCtx, fileSize int64, _ error) {
+	ctx, sp := tracing.ChildSpan(ctx, "sftp.ReadFile")
+	defer sp.Finish()
+
+	c, err := s.getClient(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	name := path.Join(s.prefix, basename)
+	f, err := c.Open(name)
+	if err != nil {
+		return nil, 0, notExistErr(err, name)
+	}
+	if !opts.NoFileSize {
+		info, err := f.Stat()
+		if err != nil {
+			_ = f.Close()
+			return nil, 0, errors.Wrapf(err, "stat %s", name)
+		}
+		fileSize = info.Size()
+	}
+	if opts.Offset != 0 {
+		if _, err := f.Seek(opts.Offset, io.SeekStart); err != nil {
+			_ = f.Close()
+			return nil, 0, errors.Wrapf(err, "seeking %s", name)
+		}
+	}
+	return ioctx.ReadCloserAdapter(f), fileSize, nil
+}
+
+// notExistErr marks the error as cloud.ErrFileDoesNotExist if it is the
+// server reporting that the file does not exist.
+func notExistErr(err error, name string) error {
+	if oserror.IsNotExist(err) {
+		// nolint:errwrap
+		return errors.WithMessagef(
+			errors.Wrap(cloud.ErrFileDoesNotExist, "sftp storage file does not exist"),
+			"%s: %s", name, err.Error(),
+		)
+	}
+	return errors.Wrapf(err, "opening %s", name)
+}
+
+// Writer returns a writer which writes to a temporary file next to the
+// destination, which is renamed to the destination when the writer is closed,
+// so that readers never observe a partially written file. If ctx is canceled
+// before the writer is closed, the temporary file is removed instead.
+func (s *sftpStorage) Writer(ctx context.Context, basename string) (io.WriteCloser, error) {
+	c, err := s.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name := path.Join(s.prefix, basename)
+	if err := c.MkdirAll(path.Dir(name)); err != nil {
+		return nil, errors.Wrapf(err, "creating directory %s", path.Dir(name))
+	}
+	tmp := fmt.Sprintf("%s.%s.tmp", name, uuid.MakeV4().Short())
+	f, err := c.Create(tmp)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating %s", tmp)
+	}
+	return &sftpWriter{ctx: ctx, client: c, f: f, tmp: tmp, dest: name}, nil
+}
+
+type sftpWriter struct {
+	ctx       context.Context
+	client    *sftp.Client
+	f         *sftp.File
+	tmp, dest string
+}
+
+func (w *sftpWriter) Write(p []byte) (int, error) {
+	return w.f.Write(p)
+}
+
+func (w *sftpWriter) Close() error {
+	if err := w.ctx.Err(); err != nil {
+		closeErr := w.f.Close()
+		rmErr := w.client.Remove(w.tmp)
+		return errors.CombineErrors(err, errors.Wrap(errors.CombineErrors(rmErr, closeErr), "cleaning up"))
+	}
+	if err := w.f.Close(); err != nil {
+		return errors.CombineErrors(err, w.client.Remove(w.tmp))
+	}
+	return errors.Wrapf(w.rename(), "moving temporary file to final location %q", w.dest)
+}
+
+// rename replaces the destination with the temporary file. Servers which don't
+// support the posix-rename extension refuse to rename over an existing file,
+// in which case the destination is removed first.
+func (w *sftpWriter) rename() error {
+	if _, ok := w.client.HasExtension("posix-rename@openssh.com"); ok {
+		return w.client.PosixRename(w.tmp, w.dest)
+	}
+	if err := w.client.Remove(w.dest); err != nil && !oserror.IsNotExist(err) {
+		return err
+	}
+	return w.client.Rename(w.tmp, w.dest)
+}
+
+func (s *sftpStorage) List(ctx context.Context, prefix, delim string, fn cloud.ListingFn) error {
+	ctx, sp := tracing.ChildSpan(ctx, "sftp.List")
+	defer sp.Finish()
+
+	c, err := s.getClient(ctx)
+	if err != nil {
+		return err
+	}
+	dest := cloud.JoinPathPreservingTrailingSlash(s.prefix, prefix)
+	// Every file whose name begins with dest is under the directory containing
+	// dest, if dest does not name a directory itself.
+	dir := dest
+	if !strings.HasSuffix(dir, "/") {
+		dir = path.Dir(dir)
+	}
+
+	var files []string
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		entries, err := c.ReadDir(dir)
+		if err != nil {
+			if oserror.IsNotExist(err) {
+				return nil
+			}
+			return errors.Wrapf(err, "listing %s", dir)
+		}
+		for _, e := range entries {
+			name := path.Join(dir, e.Name())
+			if e.IsDir() {
+				// Only descend into directories that may contain matching files.
+				if strings.HasPrefix(name+"/", dest) || strings.HasPrefix(dest, name+"/") {
+					if err := walk(name); err != nil {
+						return err
+					}
+				}
+			} else if strings.HasPrefix(name, dest) {
+				files = append(files, name)
+			}
+		}
+		return nil
+	}
+	if err := walk(dir); err != nil {
+		return err
+	}
+
+	// Sort results so that we can group as we go.
+	sort.Strings(files)
+	var prevPrefix string
+	for _, f := range files {
+		f = strings.TrimPrefix(f, dest)
+		if delim != "" {
+			if i := strings.Index(f, delim); i >= 0 {
+				f = f[:i+len(delim)]
+			}
+			if f == prevPrefix {
+				continue
+			}
+			prevPrefix = f
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sftpStorage) Delete(ctx context.Context, basename string) error {
+	c, err := s.getClient(ctx)
+	if err != nil {
+		return err
+	}
+	name := path.Join(s.prefix, basename)
+	if err := c.Remove(name); err != nil && !oserror.IsNotExist(err) {
+		return errors.Wrapf(err, "deleting %s", name)
+	}
+	return nil
+}
+
+func (s *sftpStorage) Size(ctx context.Context, basename string) (int64, error) {
+	c, err := s.getClient(ctx)
+	if err != nil {
+		return 0, err
+	}
+	name := path.Join(s.prefix, basename)
+	info, err := c.Stat(name)
+	if err != nil {
+		return 0, notExistErr(err, name)
+	}
+	return info.Size(), nil
+}
+
+func (s *sftpStorage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.conn == nil {
+		return nil
+	}
+	err := errors.CombineErrors(s.mu.client.Close(), s.mu.conn.Close())
+	// Closing a connection which the server has already closed returns io.EOF.
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+	s.mu.conn, s.mu.client = nil, nil
+	return err
+}
+
+func init() {
+	cloud.RegisterExternalStorageProvider(cloudpb.ExternalStorageProvider_sftp,
+		parseSFTPURL, makeSFTPStorage, cloud.RedactedParams(PrivateKeyParam), scheme)
+}
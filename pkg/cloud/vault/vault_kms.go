@@ -0,0 +1,241 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	kmsScheme = "vault"
+
+	// TokenParam is the query parameter for the Vault token used to
+	// authenticate requests to the Transit secrets engine.
+	TokenParam = "VAULT_TOKEN"
+	// NamespaceParam is the query parameter for the Vault Enterprise namespace
+	// in which the Transit secrets engine is mounted.
+	NamespaceParam = "VAULT_NAMESPACE"
+	// InsecureHTTPParam is the query parameter which, when set to true, makes
+	// requests to Vault use plain HTTP rather than HTTPS. It is intended for
+	// development servers only.
+	InsecureHTTPParam = "VAULT_INSECURE_HTTP"
+
+	defaultMount = "transit"
+)
+
+// vaultKMS is a KMS which uses a named encryption key of HashiCorp Vault's
+// Transit secrets engine. The key never leaves Vault; data is sent to Vault to
+// be encrypted or decrypted.
+//
+// Vault always encrypts using the latest version of a key, while ciphertexts
+// record the version that produced them, so that they can still be decrypted
+// after the key is rotated. Data keys stored in a backup can be re-wrapped with
+// the latest version of a rotated key by running ALTER BACKUP ... ADD NEW_KMS
+// with the same URI as OLD_KMS.
+type vaultKMS struct {
+	client    *http.Client
+	baseURL   url.URL
+	mount     string
+	keyName   string
+	token     string
+	namespace string
+}
+
+var _ cloud.KMS = &vaultKMS{}
+
+func init() {
+	cloud.RegisterKMSFromURIFactory(MakeVaultKMS, kmsScheme)
+	cloud.RegisterRedactedParams(cloud.RedactedParams(TokenParam))
+}
+
+type kmsURIParams struct {
+	token        string
+	namespace    string
+	insecureHTTP bool
+}
+
+func resolveKMSURIParams(kmsURI cloud.ConsumeURL) (kmsURIParams, error) {
+	params := kmsURIParams{
+		token:     kmsURI.ConsumeParam(TokenParam),
+		namespace: kmsURI.ConsumeParam(NamespaceParam),
+	}
+	if insecure := kmsURI.ConsumeParam(InsecureHTTPParam); insecure != "" {
+		var err error
+		if params.insecureHTTP, err = strconv.ParseBool(insecure); err != nil {
+			return kmsURIParams{}, errors.Wrapf(err, "parsing value of %s", InsecureHTTPParam)
+		}
+	}
+
+	// Validate that all the passed in parameters are supported.
+	if unknownParams := kmsURI.RemainingQueryParams(); len(unknownParams) > 0 {
+		return kmsURIParams{}, errors.Errorf(
+			`unknown KMS query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+	if params.token == "" {
+		return kmsURIParams{}, errors.Errorf("%s must be set to use Vault KMS", TokenParam)
+	}
+	return params, nil
+}
+
+// MakeVaultKMS is the factory method which returns a configured, ready-to-use
+// Vault KMS object. The URI has the form
+// vault://<host>:<port>/[<mount>/]<key-name>?VAULT_TOKEN=<token>, where mount
+// is the path at which the Transit secrets engine is mounted, and defaults to
+// "transit".
+func MakeVaultKMS(ctx context.Context, uri string, env cloud.KMSEnv) (cloud.KMS, error) {
+	if env.KMSConfig().DisableOutbound {
+		return nil, errors.New("external IO must be enabled to use Vault KMS")
+	}
+	kmsURI, err := url.ParseRequestURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if kmsURI.Host == "" {
+		return nil, errors.New("host component of the Vault KMS URI must be the address of the Vault server")
+	}
+	keyPath := strings.Trim(kmsURI.Path, "/")
+	if keyPath == "" {
+		return nil, errors.New("path component of the Vault KMS URI must contain the name of the Transit key")
+	}
+	mount, keyName := defaultMount, keyPath
+	if i := strings.LastIndexByte(keyPath, '/'); i >= 0 {
+		mount, keyName = keyPath[:i], keyPath[i+1:]
+	}
+
+	kmsURIParams, err := resolveKMSURIParams(cloud.ConsumeURL{URL: kmsURI})
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := cloud.MakeHTTPClient(env.ClusterSettings())
+	if err != nil {
+		return nil, err
+	}
+	baseURL := url.URL{Scheme: "https", Host: kmsURI.Host}
+	if kmsURIParams.insecureHTTP {
+		baseURL.Scheme = "http"
+	}
+	return &vaultKMS{
+		client:    client,
+		baseURL:   baseURL,
+		mount:     mount,
+		keyName:   keyName,
+		token:     kmsURIParams.token,
+		namespace: kmsURIParams.namespace,
+	}, nil
+}
+
+// MasterKeyID implements the KMS interface.
+func (k *vaultKMS) MasterKeyID() string {
+	return path.Join(k.baseURL.Host, k.namespace, k.mount, k.keyName)
+}
+
+// transitRequest is the body of requests to the Transit secrets engine.
+type transitRequest struct {
+	Plaintext  string `json:"plaintext,omitempty"`
+	Ciphertext string `json:"ciphertext,omitempty"`
+}
+
+// transitResponse is the body of responses from the Transit secrets engine.
+type transitResponse struct {
+	Data struct {
+		Plaintext  string `json:"plaintext"`
+		Ciphertext string `json:"ciphertext"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+// do issues the Transit request for op, which is either "encrypt" or
+// "decrypt".
+func (k *vaultKMS) do(ctx context.Context, op string, body transitRequest) (transitResponse, error) {
+	var res transitResponse
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return res, err
+	}
+	u := k.baseURL
+	u.Path = path.Join("/v1", k.mount, op, k.keyName)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(reqBody))
+	if err != nil {
+		return res, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", k.token)
+	if k.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", k.namespace)
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return res, cloud.KMSInaccessible(errors.Wrapf(err, "Vault %s", op))
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return res, cloud.KMSInaccessible(errors.Wrapf(err, "reading Vault %s response", op))
+	}
+	if err := json.Unmarshal(raw, &res); err != nil && resp.StatusCode == http.StatusOK {
+		return res, errors.Wrapf(err, "decoding Vault %s response", op)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if len(res.Errors) > 0 {
+			return res, cloud.KMSInaccessible(errors.Newf("Vault %s failed: %s: %s",
+				op, resp.Status, strings.Join(res.Errors, "; ")))
+		}
+		return res, cloud.KMSInaccessible(errors.Newf("Vault %s failed: %s", op, resp.Status))
+	}
+	return res, nil
+}
+
+// Encrypt implements the KMS interface.
+func (k *vaultKMS) Encrypt(ctx context.Context, data []byte) ([]byte, error) {
+	res, err := k.do(ctx, "encrypt", transitRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(data),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.Data.Ciphertext == "" {
+		return nil, errors.New("Vault encrypt response is missing the ciphertext")
+	}
+	return []byte(res.Data.Ciphertext), nil
+}
+
+// Decrypt implements the KMS interface.
+func (k *vaultKMS) Decrypt(ctx context.Context, data []byte) ([]byte, error) {
+	res, err := k.do(ctx, "decrypt", transitRequest{Ciphertext: string(data)})
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := base64.StdEncoding.DecodeString(res.Data.Plaintext)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding Vault decrypt response")
+	}
+	return plaintext, nil
+}
+
+// Close implements the KMS interface.
+func (k *vaultKMS) Close() error {
+	k.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,226 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package vault
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudtestutils"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "s.token"
+
+// fakeTransit serves the encrypt and decrypt endpoints of Vault's Transit
+// secrets engine, mounted at any path, with AES-GCM keys held in memory.
+type fakeTransit struct {
+	mu sync.Mutex
+	// keys maps the path of a key, including its mount, to its versions.
+	keys map[string][]cipher.AEAD
+}
+
+func (f *fakeTransit) rotate(t *testing.T, key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	raw := make([]byte, 32)
+	_, err := rand.Read(raw)
+	require.NoError(t, err)
+	block, err := aes.NewCipher(raw)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	f.keys[key] = append(f.keys[key], gcm)
+}
+
+func (f *fakeTransit) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fail := func(code int, msg string) {
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(map[string][]string{"errors": {msg}})
+	}
+	if r.Header.Get("X-Vault-Token") != testToken {
+		fail(http.StatusForbidden, "permission denied")
+		return
+	}
+	var req transitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		fail(http.StatusBadRequest, err.Error())
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/"), "/")
+	if len(parts) < 3 {
+		fail(http.StatusNotFound, "no handler for route")
+		return
+	}
+	op := parts[len(parts)-2]
+	key := strings.Join(parts[:len(parts)-2], "/") + "/" + parts[len(parts)-1]
+	versions, ok := f.keys[key]
+	if !ok {
+		fail(http.StatusBadRequest, "encryption key not found")
+		return
+	}
+
+	var res transitResponse
+	switch op {
+	case "encrypt":
+		plaintext, err := base64.StdEncoding.DecodeString(req.Plaintext)
+		if err != nil {
+			fail(http.StatusBadRequest, err.Error())
+			return
+		}
+		gcm := versions[len(versions)-1]
+		nonce := make([]byte, gcm.NonceSize())
+		_, _ = rand.Read(nonce)
+		sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+		res.Data.Ciphertext = fmt.Sprintf("vault:v%d:%s",
+			len(versions), base64.StdEncoding.EncodeToString(sealed))
+	case "decrypt":
+		fields := strings.SplitN(req.Ciphertext, ":", 3)
+		if len(fields) != 3 || fields[0] != "vault" {
+			fail(http.StatusBadRequest, "invalid ciphertext: no prefix")
+			return
+		}
+		version, err := strconv.Atoi(strings.TrimPrefix(fields[1], "v"))
+		if err != nil || version < 1 || version > len(versions) {
+			fail(http.StatusBadRequest, "invalid ciphertext: bad key version")
+			return
+		}
+		sealed, err := base64.StdEncoding.DecodeString(fields[2])
+		if err != nil {
+			fail(http.StatusBadRequest, err.Error())
+			return
+		}
+		gcm := versions[version-1]
+		if len(sealed) < gcm.NonceSize() {
+			fail(http.StatusBadRequest, "invalid ciphertext: too short")
+			return
+		}
+		plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+		if err != nil {
+			fail(http.StatusBadRequest, "cipher: message authentication failed")
+			return
+		}
+		res.Data.Plaintext = base64.StdEncoding.EncodeToString(plaintext)
+	default:
+		fail(http.StatusNotFound, "no handler for route")
+		return
+	}
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func TestVaultKMS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	transit := &fakeTransit{keys: map[string][]cipher.AEAD{}}
+	transit.rotate(t, "transit/backup")
+	transit.rotate(t, "secret/transit/other")
+	srv := httptest.NewServer(transit)
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	env := &cloud.TestKMSEnv{
+		Settings:         cluster.MakeTestingClusterSettings(),
+		ExternalIOConfig: &base.ExternalIODirConfig{},
+	}
+	uri := fmt.Sprintf("vault://%s/backup?%s=%s&%s=true", host, TokenParam, testToken, InsecureHTTPParam)
+
+	t.Run("encrypt-decrypt", func(t *testing.T) {
+		cloud.KMSEncryptDecrypt(t, uri, env)
+		cloud.KMSEncryptDecrypt(t, fmt.Sprintf("vault://%s/secret/transit/other?%s=%s&%s=true",
+			host, TokenParam, testToken, InsecureHTTPParam), env)
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		k, err := cloud.KMSFromURI(ctx, uri, env)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, k.Close()) }()
+
+		dataKey := []byte("data key")
+		before, err := k.Encrypt(ctx, dataKey)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(string(before), "vault:v1:"), "%s", before)
+
+		transit.rotate(t, "transit/backup")
+
+		// Data keys wrapped before the rotation can still be unwrapped, and
+		// wrapping them again uses the new version of the key.
+		unwrapped, err := k.Decrypt(ctx, before)
+		require.NoError(t, err)
+		require.Equal(t, dataKey, unwrapped)
+		after, err := k.Encrypt(ctx, unwrapped)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(string(after), "vault:v2:"), "%s", after)
+		unwrapped, err = k.Decrypt(ctx, after)
+		require.NoError(t, err)
+		require.Equal(t, dataKey, unwrapped)
+	})
+
+	t.Run("wrong-token", func(t *testing.T) {
+		cloudtestutils.RequireKMSInaccessibleErrorContaining(ctx, t,
+			fmt.Sprintf("vault://%s/backup?%s=wrong&%s=true", host, TokenParam, InsecureHTTPParam),
+			"permission denied")
+	})
+
+	t.Run("master-key-id", func(t *testing.T) {
+		k, err := cloud.KMSFromURI(ctx, fmt.Sprintf("vault://vault:8200/backup?%s=t&%s=ns",
+			TokenParam, NamespaceParam), env)
+		require.NoError(t, err)
+		require.Equal(t, "vault:8200/ns/transit/backup", k.MasterKeyID())
+		require.NoError(t, k.Close())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, tc := range []struct {
+			uri string
+			err string
+		}{
+			{uri: "vault:///backup?VAULT_TOKEN=t", err: "host component"},
+			{uri: "vault://vault:8200/?VAULT_TOKEN=t", err: "name of the Transit key"},
+			{uri: "vault://vault:8200/backup", err: "VAULT_TOKEN must be set"},
+			{uri: "vault://vault:8200/backup?VAULT_TOKEN=t&FOO=bar", err: "unknown KMS query parameters: FOO"},
+			{uri: "vault://vault:8200/backup?VAULT_TOKEN=t&VAULT_INSECURE_HTTP=maybe", err: "parsing value"},
+		} {
+			_, err := cloud.KMSFromURI(ctx, tc.uri, env)
+			require.ErrorContains(t, err, tc.err, tc.uri)
+		}
+
+		_, err := cloud.KMSFromURI(ctx, uri, &cloud.TestKMSEnv{
+			Settings:         env.Settings,
+			ExternalIOConfig: &base.ExternalIODirConfig{DisableOutbound: true},
+		})
+		require.ErrorContains(t, err, "external IO must be enabled")
+	})
+
+	t.Run("redacted", func(t *testing.T) {
+		redacted, err := cloud.RedactKMSURI("vault://vault:8200/transit/backup?VAULT_TOKEN=secret")
+		require.NoError(t, err)
+		require.Equal(t, "vault://vault:8200/redacted?VAULT_TOKEN=redacted", redacted)
+	})
+}
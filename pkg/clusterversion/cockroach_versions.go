@@ -499,6 +499,18 @@ const (
 	// role for all existing functions.
 	V23_2_GrantExecuteToPublic
 
+	// V23_2_PreparedTransactionsTable creates the system.prepared_transactions
+	// table, which is required by PREPARE TRANSACTION.
+	V23_2_PreparedTransactionsTable
+
+	// V23_2_ResourceGroupsTable creates the system.resource_groups table, which
+	// stores the definitions of the resource groups used by admission control.
+	V23_2_ResourceGroupsTable
+
+	// V23_2_TTLStorageExpiry enables the ttl_storage_expiry table storage
+	// parameter, with which KV enforces the expiry of a table's rows.
+	V23_2_TTLStorageExpiry
+
 	// *************************************************
 	// Step (1) Add new versions here.
 	// Do not add new versions to a patch release.
@@ -832,6 +844,18 @@ var rawVersionsSingleton = keyedVersions{
 		Key:     V23_2_GrantExecuteToPublic,
 		Version: roachpb.Version{Major: 23, Minor: 1, Internal: 26},
 	},
+	{
+		Key:     V23_2_PreparedTransactionsTable,
+		Version: roachpb.Version{Major: 23, Minor: 1, Internal: 28},
+	},
+	{
+		Key:     V23_2_ResourceGroupsTable,
+		Version: roachpb.Version{Major: 23, Minor: 1, Internal: 30},
+	},
+	{
+		Key:     V23_2_TTLStorageExpiry,
+		Version: roachpb.Version{Major: 23, Minor: 1, Internal: 32},
+	},
 
 	// *************************************************
 	// Step (2): Add new versions here.
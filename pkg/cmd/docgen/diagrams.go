@@ -1624,6 +1624,7 @@ var specs = []stmtSpec{
 		unlink:  []string{"select_stmt"},
 		nosplit: true,
 	},
+	{name: "verify_backup_stmt"},
 	{
 		name:    "validate_constraint",
 		stmt:    "alter_onetable_stmt",
@@ -35,6 +35,8 @@ const (
 	Constraints            // constraints
 	VoterConstraints       // voter_constraints
 	LeasePreferences       // lease_preferences
+	NumWitnesses           // num_witnesses
+	StorageTier            // storage_tier
 
 	// NumFields is the number of fields in the config.
 	NumFields int = iota - 1
@@ -393,6 +393,25 @@ func (z *ZoneConfig) Validate() error {
 		}
 	}
 
+	if z.NumWitnesses != nil {
+		if *z.NumWitnesses < 0 {
+			return fmt.Errorf("num_witnesses cannot be negative")
+		}
+		numVoters := z.NumReplicas
+		if numVotersExplicit {
+			numVoters = z.NumVoters
+		}
+		if numVoters != nil && *z.NumWitnesses > 0 && *z.NumWitnesses >= *numVoters {
+			return fmt.Errorf("num_witnesses must be less than the number of voting replicas")
+		}
+	}
+
+	if z.StorageTier != nil {
+		if _, err := roachpb.ParseStorageTier(*z.StorageTier); err != nil {
+			return err
+		}
+	}
+
 	if z.RangeMaxBytes != nil && *z.RangeMaxBytes < minRangeMaxBytes {
 		return fmt.Errorf("RangeMaxBytes %d less than minimum allowed %d",
 			*z.RangeMaxBytes, minRangeMaxBytes)
@@ -559,11 +578,21 @@ func (z *ZoneConfig) InheritFromParent(parent *ZoneConfig) {
 			z.NumVoters = proto.Int32(*parent.NumVoters)
 		}
 	}
+	if z.NumWitnesses == nil {
+		if parent.NumWitnesses != nil {
+			z.NumWitnesses = proto.Int32(*parent.NumWitnesses)
+		}
+	}
 	if z.GlobalReads == nil {
 		if parent.GlobalReads != nil {
 			z.GlobalReads = proto.Bool(*parent.GlobalReads)
 		}
 	}
+	if z.StorageTier == nil {
+		if parent.StorageTier != nil {
+			z.StorageTier = proto.String(*parent.StorageTier)
+		}
+	}
 	if z.RangeMinBytes == nil {
 		if parent.RangeMinBytes != nil {
 			z.RangeMinBytes = proto.Int64(*parent.RangeMinBytes)
@@ -608,6 +637,11 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 			if other.NumVoters != nil {
 				z.NumVoters = proto.Int32(*other.NumVoters)
 			}
+		case "num_witnesses":
+			z.NumWitnesses = nil
+			if other.NumWitnesses != nil {
+				z.NumWitnesses = proto.Int32(*other.NumWitnesses)
+			}
 		case "range_min_bytes":
 			z.RangeMinBytes = nil
 			if other.RangeMinBytes != nil {
@@ -623,6 +657,11 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 			if other.GlobalReads != nil {
 				z.GlobalReads = proto.Bool(*other.GlobalReads)
 			}
+		case "storage_tier":
+			z.StorageTier = nil
+			if other.StorageTier != nil {
+				z.StorageTier = proto.String(*other.StorageTier)
+			}
 		case "gc.ttlseconds":
 			z.GC = nil
 			if other.GC != nil {
@@ -697,6 +736,16 @@ func (z *ZoneConfig) DiffWithZone(
 					Field: "num_voters",
 				}, nil
 			}
+		case "num_witnesses":
+			if other.NumWitnesses == nil && z.NumWitnesses == nil {
+				continue
+			}
+			if z.NumWitnesses == nil || other.NumWitnesses == nil ||
+				*z.NumWitnesses != *other.NumWitnesses {
+				return false, DiffWithZoneMismatch{
+					Field: "num_witnesses",
+				}, nil
+			}
 		case "range_min_bytes":
 			if other.RangeMinBytes == nil && z.RangeMinBytes == nil {
 				continue
@@ -727,6 +776,16 @@ func (z *ZoneConfig) DiffWithZone(
 					Field: "global_reads",
 				}, nil
 			}
+		case "storage_tier":
+			if other.StorageTier == nil && z.StorageTier == nil {
+				continue
+			}
+			if z.StorageTier == nil || other.StorageTier == nil ||
+				*z.StorageTier != *other.StorageTier {
+				return false, DiffWithZoneMismatch{
+					Field: "storage_tier",
+				}, nil
+			}
 		case "gc.ttlseconds":
 			if other.GC == nil && z.GC == nil {
 				continue
@@ -1138,6 +1197,15 @@ func (z *ZoneConfig) toSpanConfig() (roachpb.SpanConfig, error) {
 	if z.NumVoters != nil {
 		sc.NumVoters = *z.NumVoters
 	}
+	if z.NumWitnesses != nil {
+		sc.NumWitnesses = *z.NumWitnesses
+	}
+	// The storage tier is unspecified by default.
+	if z.StorageTier != nil {
+		if sc.StorageTier, err = roachpb.ParseStorageTier(*z.StorageTier); err != nil {
+			return sc, err
+		}
+	}
 
 	toSpanConfigConstraints := func(src []Constraint) ([]roachpb.Constraint, error) {
 		spanConfigConstraints := make([]roachpb.Constraint, len(src))
@@ -55,6 +55,13 @@ func TestZoneConfigValidate(t *testing.T) {
 			},
 			"at least 3 replicas are required for multi-replica configurations",
 		},
+		{
+			ZoneConfig{
+				NumReplicas: proto.Int32(1),
+				StorageTier: proto.String("lukewarm"),
+			},
+			`unknown storage tier "lukewarm"`,
+		},
 		{
 			ZoneConfig{
 				NumReplicas:   proto.Int32(1),
@@ -205,6 +205,8 @@ type marshalableZoneConfig struct {
 	GlobalReads                  *bool             `json:"global_reads" yaml:"global_reads"`
 	NumReplicas                  *int32            `json:"num_replicas" yaml:"num_replicas"`
 	NumVoters                    *int32            `json:"num_voters" yaml:"num_voters"`
+	NumWitnesses                 *int32            `json:"num_witnesses,omitempty" yaml:"num_witnesses,omitempty"`
+	StorageTier                  *string           `json:"storage_tier,omitempty" yaml:"storage_tier,omitempty"`
 	Constraints                  ConstraintsList   `json:"constraints" yaml:"constraints,flow"`
 	VoterConstraints             ConstraintsList   `json:"voter_constraints" yaml:"voter_constraints,flow"`
 	LeasePreferences             []LeasePreference `json:"lease_preferences" yaml:"lease_preferences,flow"`
@@ -235,6 +237,12 @@ func zoneConfigToMarshalable(c ZoneConfig) marshalableZoneConfig {
 	if c.NumVoters != nil && *c.NumVoters != 0 {
 		m.NumVoters = proto.Int32(*c.NumVoters)
 	}
+	if c.NumWitnesses != nil && *c.NumWitnesses != 0 {
+		m.NumWitnesses = proto.Int32(*c.NumWitnesses)
+	}
+	if c.StorageTier != nil {
+		m.StorageTier = proto.String(*c.StorageTier)
+	}
 	// NB: In order to preserve round-trippability, we're directly using
 	// `NullVoterConstraintsIsEmpty` as opposed to calling
 	// `c.InheritedVoterConstraints()`. This is copacetic as long as the value is
@@ -275,6 +283,12 @@ func zoneConfigFromMarshalable(m marshalableZoneConfig, c ZoneConfig) ZoneConfig
 	if m.NumVoters != nil {
 		c.NumVoters = proto.Int32(*m.NumVoters)
 	}
+	if m.NumWitnesses != nil {
+		c.NumWitnesses = proto.Int32(*m.NumWitnesses)
+	}
+	if m.StorageTier != nil {
+		c.StorageTier = proto.String(*m.StorageTier)
+	}
 	c.VoterConstraints = m.VoterConstraints.Constraints
 	c.NullVoterConstraintsIsEmpty = !m.VoterConstraints.Inherited
 	if m.LeasePreferences != nil {
@@ -276,6 +276,7 @@ BNF_SRCS = [
     "//docs/generated/sql/bnf:use_stmt.bnf",
     "//docs/generated/sql/bnf:validate_constraint.bnf",
     "//docs/generated/sql/bnf:values_clause.bnf",
+    "//docs/generated/sql/bnf:verify_backup_stmt.bnf",
     "//docs/generated/sql/bnf:window_definition.bnf",
     "//docs/generated/sql/bnf:with_clause.bnf",
 ]
@@ -52,6 +52,9 @@ var (
 	_ Details = AutoConfigEnvRunnerDetails{}
 	_ Details = AutoConfigTaskDetails{}
 	_ Details = AutoUpdateSQLActivityDetails{}
+	_ Details = ExportDetails{}
+	_ Details = VerifyBackupDetails{}
+	_ Details = BackupCompactionDetails{}
 )
 
 // ProgressDetails is a marker interface for job progress details proto structs.
@@ -76,6 +79,9 @@ var (
 	_ ProgressDetails = AutoConfigEnvRunnerProgress{}
 	_ ProgressDetails = AutoConfigTaskProgress{}
 	_ ProgressDetails = AutoUpdateSQLActivityProgress{}
+	_ ProgressDetails = ExportProgress{}
+	_ ProgressDetails = VerifyBackupProgress{}
+	_ ProgressDetails = BackupCompactionProgress{}
 )
 
 // Type returns the payload's job type and panics if the type is invalid.
@@ -212,6 +218,12 @@ func DetailsType(d isPayload_Details) (Type, error) {
 		return TypeAutoConfigTask, nil
 	case *Payload_AutoUpdateSqlActivities:
 		return TypeAutoUpdateSQLActivity, nil
+	case *Payload_Export:
+		return TypeExport, nil
+	case *Payload_VerifyBackup:
+		return TypeVerifyBackup, nil
+	case *Payload_BackupCompaction:
+		return TypeBackupCompaction, nil
 	default:
 		return TypeUnspecified, errors.Newf("Payload.Type called on a payload with an unknown details type: %T", d)
 	}
@@ -256,6 +268,9 @@ var JobDetailsForEveryJobType = map[Type]Details{
 	TypeAutoConfigEnvRunner:          AutoConfigEnvRunnerDetails{},
 	TypeAutoConfigTask:               AutoConfigTaskDetails{},
 	TypeAutoUpdateSQLActivity:        AutoUpdateSQLActivityDetails{},
+	TypeExport:                       ExportDetails{},
+	TypeVerifyBackup:                 VerifyBackupDetails{},
+	TypeBackupCompaction:             BackupCompactionDetails{},
 }
 
 // WrapProgressDetails wraps a ProgressDetails object in the protobuf wrapper
@@ -311,6 +326,12 @@ func WrapProgressDetails(details ProgressDetails) interface {
 		return &Progress_AutoConfigTask{AutoConfigTask: &d}
 	case AutoUpdateSQLActivityProgress:
 		return &Progress_UpdateSqlActivity{UpdateSqlActivity: &d}
+	case ExportProgress:
+		return &Progress_Export{Export: &d}
+	case VerifyBackupProgress:
+		return &Progress_VerifyBackup{VerifyBackup: &d}
+	case BackupCompactionProgress:
+		return &Progress_BackupCompaction{BackupCompaction: &d}
 	default:
 		panic(errors.AssertionFailedf("WrapProgressDetails: unknown progress type %T", d))
 	}
@@ -364,6 +385,12 @@ func (p *Payload) UnwrapDetails() Details {
 		return *d.AutoConfigTask
 	case *Payload_AutoUpdateSqlActivities:
 		return *d.AutoUpdateSqlActivities
+	case *Payload_Export:
+		return *d.Export
+	case *Payload_VerifyBackup:
+		return *d.VerifyBackup
+	case *Payload_BackupCompaction:
+		return *d.BackupCompaction
 	default:
 		return nil
 	}
@@ -417,6 +444,12 @@ func (p *Progress) UnwrapDetails() ProgressDetails {
 		return *d.AutoConfigTask
 	case *Progress_UpdateSqlActivity:
 		return *d.UpdateSqlActivity
+	case *Progress_Export:
+		return *d.Export
+	case *Progress_VerifyBackup:
+		return *d.VerifyBackup
+	case *Progress_BackupCompaction:
+		return *d.BackupCompaction
 	default:
 		return nil
 	}
@@ -494,6 +527,12 @@ func WrapPayloadDetails(details Details) interface {
 		return &Payload_AutoConfigTask{AutoConfigTask: &d}
 	case AutoUpdateSQLActivityDetails:
 		return &Payload_AutoUpdateSqlActivities{AutoUpdateSqlActivities: &d}
+	case ExportDetails:
+		return &Payload_Export{Export: &d}
+	case VerifyBackupDetails:
+		return &Payload_VerifyBackup{VerifyBackup: &d}
+	case BackupCompactionDetails:
+		return &Payload_BackupCompaction{BackupCompaction: &d}
 	default:
 		panic(errors.AssertionFailedf("jobs.WrapPayloadDetails: unknown details type %T", d))
 	}
@@ -529,7 +568,7 @@ const (
 func (Type) SafeValue() {}
 
 // NumJobTypes is the number of jobs types.
-const NumJobTypes = 24
+const NumJobTypes = 27
 
 // ChangefeedDetailsMarshaler allows for dependency injection of
 // cloud.SanitizeExternalStorageURI to avoid the dependency from this
@@ -906,6 +906,108 @@ func (db *DB) Barrier(ctx context.Context, begin, end interface{}) (hlc.Timestam
 	return resp.Timestamp, nil
 }
 
+// CommitPrepared commits the prepared transaction identified by the given
+// TxnMeta, which must carry the transaction's ID and key. See Txn.Prepare.
+func (db *DB) CommitPrepared(ctx context.Context, txn enginepb.TxnMeta) error {
+	return db.endPreparedTxn(ctx, txn, true /* commit */)
+}
+
+// RollbackPrepared rolls back the prepared transaction identified by the given
+// TxnMeta, which must carry the transaction's ID and key. See Txn.Prepare.
+//
+// A transaction that has not been prepared yet, either because its preparation
+// is still in flight or because its coordinator failed before preparing it, is
+// aborted instead, so that an orphaned transaction can be cleaned up and can
+// no longer be prepared. Rolling back a transaction that was already aborted
+// is a no-op.
+func (db *DB) RollbackPrepared(ctx context.Context, txn enginepb.TxnMeta) error {
+	return db.endPreparedTxn(ctx, txn, false /* commit */)
+}
+
+// endPreparedTxn loads the record of a prepared transaction and then commits
+// or aborts it. The EndTxn request is sent directly to the wrapped sender, as
+// the transaction's coordinator has relinquished control of the transaction
+// when it prepared it. The transaction record holds the lock spans which need
+// to be resolved.
+func (db *DB) endPreparedTxn(ctx context.Context, meta enginepb.TxnMeta, commit bool) error {
+	b := &Batch{}
+	b.AddRawRequest(&kvpb.QueryTxnRequest{
+		RequestHeader: kvpb.RequestHeader{Key: meta.Key},
+		Txn:           meta,
+	})
+	if err := getOneErr(db.Run(ctx, b), b); err != nil {
+		return err
+	}
+	resp := b.RawResponse().Responses[0].GetQueryTxn()
+	if !resp.TxnRecordExists {
+		if !commit {
+			// The transaction may not have written its record yet. The push
+			// prevents it from ever creating one.
+			return db.abortUnpreparedTxn(ctx, meta)
+		}
+		return errors.Errorf("prepared transaction %s not found", meta.ID.Short())
+	}
+
+	txn := resp.QueriedTxn
+	switch txn.Status {
+	case roachpb.PREPARED:
+	case roachpb.PENDING:
+		if !commit {
+			return db.abortUnpreparedTxn(ctx, txn.TxnMeta)
+		}
+		return errors.Errorf("transaction %s is not prepared: %s", meta.ID.Short(), txn.Status)
+	case roachpb.COMMITTED:
+		if commit {
+			return nil
+		}
+		return kvpb.NewTransactionStatusError(
+			kvpb.TransactionStatusError_REASON_TXN_COMMITTED, "already committed")
+	case roachpb.ABORTED:
+		if !commit {
+			return nil
+		}
+		return kvpb.NewTransactionAbortedError(kvpb.ABORT_REASON_ABORTED_RECORD_FOUND)
+	default:
+		return errors.Errorf("transaction %s is not prepared: %s", meta.ID.Short(), txn.Status)
+	}
+
+	// A prepared transaction commits at the timestamp at which it was prepared.
+	txn.ReadTimestamp = txn.WriteTimestamp
+	ba := &kvpb.BatchRequest{}
+	ba.Txn = &txn
+	ba.Add(&kvpb.EndTxnRequest{
+		RequestHeader: kvpb.RequestHeader{Key: txn.Key},
+		Commit:        commit,
+		LockSpans:     txn.LockSpans,
+	})
+	_, pErr := db.sendUsingSender(ctx, ba, db.crs.wrapped)
+	return pErr.GoError()
+}
+
+// abortUnpreparedTxn aborts a transaction that has not been prepared with a
+// forced push, which does not wait for the transaction's coordinator to
+// expire. The push fails if the transaction is prepared concurrently, in which
+// case the caller may retry.
+func (db *DB) abortUnpreparedTxn(ctx context.Context, meta enginepb.TxnMeta) error {
+	b := &Batch{}
+	b.Header.Timestamp = db.clock.Now()
+	b.AddRawRequest(&kvpb.PushTxnRequest{
+		RequestHeader: kvpb.RequestHeader{Key: meta.Key},
+		PusheeTxn:     meta,
+		PushType:      kvpb.PUSH_ABORT,
+		Force:         true,
+	})
+	if err := getOneErr(db.Run(ctx, b), b); err != nil {
+		return err
+	}
+	pushee := b.RawResponse().Responses[0].GetPushTxn().PusheeTxn
+	if pushee.Status == roachpb.COMMITTED {
+		return kvpb.NewTransactionStatusError(
+			kvpb.TransactionStatusError_REASON_TXN_COMMITTED, "already committed")
+	}
+	return nil
+}
+
 // sendAndFill is a helper which sends the given batch and fills its results,
 // returning the appropriate error which is either from the first failing call,
 // or an "internal" error.
@@ -87,6 +87,7 @@ type rangeFeedConfig struct {
 	useMuxRangeFeed bool
 	overSystemTable bool
 	withDiff        bool
+	columnFilter    *kvpb.RangeFeedColumnFilter
 	rangeObserver   func(ForEachRangeFn)
 
 	knobs struct {
@@ -136,6 +137,15 @@ func WithDiff() RangeFeedOption {
 	})
 }
 
+// WithColumnFilter asks the servers to filter and project the values of the
+// rangefeed's events using the provided filter before sending them. The
+// filter is advisory; see kvpb.RangeFeedColumnFilter.
+func WithColumnFilter(f *kvpb.RangeFeedColumnFilter) RangeFeedOption {
+	return optionFunc(func(c *rangeFeedConfig) {
+		c.columnFilter = f
+	})
+}
+
 // WithRangeObserver is called when the rangefeed starts with a function that
 // can be used to iterate over all the ranges.
 func WithRangeObserver(observer func(ForEachRangeFn)) RangeFeedOption {
@@ -806,6 +816,7 @@ func (ds *DistSender) singleRangeFeed(
 	}()
 
 	args := makeRangeFeedRequest(span, desc.RangeID, cfg.overSystemTable, startAfter, cfg.withDiff)
+	args.ColumnFilter = cfg.columnFilter
 	transport, err := newTransportForRange(ctx, desc, ds)
 	if err != nil {
 		return args.Timestamp, err
@@ -455,8 +455,13 @@ func (tc *TxnCoordSender) finalizeNonLockingTxnLocked(
 		}
 		// Mark the transaction as committed so that, in case this commit is done by
 		// the closure passed to db.Txn()), db.Txn() doesn't attempt to commit again.
-		// Also so that the correct metric gets incremented.
+		// Also so that the correct metric gets incremented. A transaction that
+		// acquired no locks has nothing to prepare, so it is marked as prepared
+		// without writing a transaction record.
 		tc.mu.txn.Status = roachpb.COMMITTED
+		if et.Prepare {
+			tc.mu.txn.Status = roachpb.PREPARED
+		}
 	} else {
 		tc.mu.txn.Status = roachpb.ABORTED
 	}
@@ -534,13 +539,14 @@ func (tc *TxnCoordSender) Send(
 
 	pErr = tc.updateStateLocked(ctx, ba, br, pErr)
 
-	// If we succeeded to commit, or we attempted to rollback, we move to
-	// txnFinalized.
+	// If we succeeded to commit or prepare, or we attempted to rollback, we move
+	// to txnFinalized. A prepared transaction is no longer heartbeated by this
+	// coordinator; it will be committed or aborted by a later request.
 	if req, ok := ba.GetArg(kvpb.EndTxn); ok {
 		et := req.(*kvpb.EndTxnRequest)
 		if (et.Commit && pErr == nil) || !et.Commit {
 			tc.finalizeAndCleanupTxnLocked(ctx)
-			if et.Commit {
+			if et.Commit && !et.Prepare {
 				if err := tc.maybeCommitWait(ctx, false /* deferred */); err != nil {
 					return nil, kvpb.NewError(err)
 				}
@@ -705,7 +711,7 @@ func (tc *TxnCoordSender) maybeRejectClientLocked(
 	ctx context.Context, ba *kvpb.BatchRequest,
 ) *kvpb.Error {
 	rollback := ba != nil && ba.IsSingleAbortTxnRequest()
-	if rollback && tc.mu.txn.Status != roachpb.COMMITTED {
+	if rollback && tc.mu.txn.Status != roachpb.COMMITTED && tc.mu.txn.Status != roachpb.PREPARED {
 		// As a special case, we allow rollbacks to be sent at any time. Any
 		// rollback attempt moves the TxnCoordSender state to txnFinalized, but higher
 		// layers are free to retry rollbacks if they want (and they do, for
@@ -715,6 +721,10 @@ func (tc *TxnCoordSender) maybeRejectClientLocked(
 		// committed, to avoid sending the rollback concurrently with the
 		// txnCommitter asynchronously making the commit explicit. See:
 		// https://github.com/cockroachdb/cockroach/issues/68643
+		//
+		// We also reject this if the transaction has been prepared. A prepared
+		// transaction outlives its coordinator and must only be rolled back
+		// explicitly, through DB.RollbackPrepared.
 		return nil
 	}
 
@@ -1276,6 +1286,13 @@ func (tc *TxnCoordSender) Epoch() enginepb.TxnEpoch {
 	return tc.mu.txn.Epoch
 }
 
+// TxnMeta is part of the kv.TxnSender interface.
+func (tc *TxnCoordSender) TxnMeta() enginepb.TxnMeta {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.mu.txn.TxnMeta
+}
+
 // IsLocking is part of the kv.TxnSender interface.
 func (tc *TxnCoordSender) IsLocking() bool {
 	tc.mu.Lock()
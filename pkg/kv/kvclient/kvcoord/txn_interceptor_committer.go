@@ -218,6 +218,10 @@ func (tc *txnCommitter) SendLocked(
 		// or because there were no unproven in-flight writes (see txnPipeliner)
 		// and there were no writes in the batch request.
 		return br, nil
+	case roachpb.PREPARED:
+		// The transaction was prepared. It was not committed in parallel, so
+		// there's nothing left to do.
+		return br, nil
 	default:
 		return nil, kvpb.NewErrorf("unexpected response status without error: %v", br.Txn)
 	}
@@ -321,7 +325,9 @@ func (tc *txnCommitter) sendLockedWithElidedEndTxn(
 	// request was optimized away. The caller may still inspect the transaction
 	// struct, so we manually update it here to emulate a true transaction.
 	status := roachpb.ABORTED
-	if et.Commit {
+	if et.Prepare {
+		status = roachpb.PREPARED
+	} else if et.Commit {
 		status = roachpb.COMMITTED
 	}
 	br.Txn = cloneWithStatus(br.Txn, status)
@@ -352,6 +358,12 @@ func (tc *txnCommitter) canCommitInParallel(ba *kvpb.BatchRequest, et *kvpb.EndT
 		return false
 	}
 
+	// A prepared transaction is not committed, so it can't be committed in
+	// parallel. Its writes must all have succeeded by the time it is prepared.
+	if et.Prepare {
+		return false
+	}
+
 	// Check whether every request in the batch is compatable with a parallel
 	// commit. If any are incompatible then we cannot perform a parallel commit.
 	// We ignore the last request in the slice because we know it is the EndTxn.
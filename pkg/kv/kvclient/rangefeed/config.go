@@ -39,6 +39,7 @@ type config struct {
 	useRowTimestampInInitialScan bool
 
 	withDiff             bool
+	columnFilter         *kvpb.RangeFeedColumnFilter
 	onUnrecoverableError OnUnrecoverableError
 	onCheckpoint         OnCheckpoint
 	onFrontierAdvance    OnFrontierAdvance
@@ -144,6 +145,16 @@ func WithDiff(withDiff bool) Option {
 	})
 }
 
+// WithColumnFilter makes an option to have the servers filter and project the
+// values of the rangefeed's events with the provided filter. The filter is
+// advisory and is not applied to the initial scan, so callers must still
+// apply their own filter to the events they receive.
+func WithColumnFilter(f *kvpb.RangeFeedColumnFilter) Option {
+	return optionFunc(func(c *config) {
+		c.columnFilter = f
+	})
+}
+
 // WithRetry configures the retry options for the rangefeed.
 func WithRetry(options retry.Options) Option {
 	return optionFunc(func(c *config) {
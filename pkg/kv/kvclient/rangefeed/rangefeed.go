@@ -297,6 +297,9 @@ func (f *RangeFeed) run(ctx context.Context, frontier *span.Frontier) {
 	if f.withDiff {
 		rangefeedOpts = append(rangefeedOpts, kvcoord.WithDiff())
 	}
+	if f.columnFilter != nil {
+		rangefeedOpts = append(rangefeedOpts, kvcoord.WithColumnFilter(f.columnFilter))
+	}
 
 	for i := 0; r.Next(); i++ {
 		ts := frontier.Frontier()
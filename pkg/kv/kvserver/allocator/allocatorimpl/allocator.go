@@ -140,11 +140,17 @@ const (
 	AllocatorConsiderRebalance
 	AllocatorRangeUnavailable
 	AllocatorFinalizeAtomicReplicationChange
+	AllocatorAddWitness
+	AllocatorReplaceDeadWitness
+	AllocatorReplaceDecommissioningWitness
+	AllocatorRemoveDeadWitness
+	AllocatorRemoveDecommissioningWitness
+	AllocatorRemoveWitness
 )
 
 // Add indicates an action adding a replica.
 func (a AllocatorAction) Add() bool {
-	return a == AllocatorAddVoter || a == AllocatorAddNonVoter
+	return a == AllocatorAddVoter || a == AllocatorAddNonVoter || a == AllocatorAddWitness
 }
 
 // Replace indicates an action replacing a dead or decommissioning replica.
@@ -152,7 +158,9 @@ func (a AllocatorAction) Replace() bool {
 	return a == AllocatorReplaceDeadVoter ||
 		a == AllocatorReplaceDeadNonVoter ||
 		a == AllocatorReplaceDecommissioningVoter ||
-		a == AllocatorReplaceDecommissioningNonVoter
+		a == AllocatorReplaceDecommissioningNonVoter ||
+		a == AllocatorReplaceDeadWitness ||
+		a == AllocatorReplaceDecommissioningWitness
 }
 
 // Remove indicates an action removing a replica, i.e. in overreplication cases.
@@ -162,10 +170,14 @@ func (a AllocatorAction) Remove() bool {
 		a == AllocatorRemoveDeadVoter ||
 		a == AllocatorRemoveDeadNonVoter ||
 		a == AllocatorRemoveDecommissioningVoter ||
-		a == AllocatorRemoveDecommissioningNonVoter
+		a == AllocatorRemoveDecommissioningNonVoter ||
+		a == AllocatorRemoveWitness ||
+		a == AllocatorRemoveDeadWitness ||
+		a == AllocatorRemoveDecommissioningWitness
 }
 
-// TargetReplicaType returns that the action is for a voter or non-voter replica.
+// TargetReplicaType returns that the action is for a voter, non-voter or
+// witness replica.
 func (a AllocatorAction) TargetReplicaType() TargetReplicaType {
 	var t TargetReplicaType
 	if a == AllocatorRemoveVoter ||
@@ -182,6 +194,13 @@ func (a AllocatorAction) TargetReplicaType() TargetReplicaType {
 		a == AllocatorReplaceDecommissioningNonVoter ||
 		a == AllocatorRemoveDecommissioningNonVoter {
 		t = NonVoterTarget
+	} else if a == AllocatorRemoveWitness ||
+		a == AllocatorAddWitness ||
+		a == AllocatorReplaceDeadWitness ||
+		a == AllocatorRemoveDeadWitness ||
+		a == AllocatorReplaceDecommissioningWitness ||
+		a == AllocatorRemoveDecommissioningWitness {
+		t = WitnessTarget
 	}
 	return t
 }
@@ -193,17 +212,23 @@ func (a AllocatorAction) ReplicaStatus() ReplicaStatus {
 	if a == AllocatorRemoveVoter ||
 		a == AllocatorRemoveNonVoter ||
 		a == AllocatorAddVoter ||
-		a == AllocatorAddNonVoter {
+		a == AllocatorAddNonVoter ||
+		a == AllocatorRemoveWitness ||
+		a == AllocatorAddWitness {
 		s = Alive
 	} else if a == AllocatorReplaceDeadVoter ||
 		a == AllocatorReplaceDeadNonVoter ||
 		a == AllocatorRemoveDeadVoter ||
-		a == AllocatorRemoveDeadNonVoter {
+		a == AllocatorRemoveDeadNonVoter ||
+		a == AllocatorReplaceDeadWitness ||
+		a == AllocatorRemoveDeadWitness {
 		s = Dead
 	} else if a == AllocatorReplaceDecommissioningVoter ||
 		a == AllocatorReplaceDecommissioningNonVoter ||
 		a == AllocatorRemoveDecommissioningVoter ||
-		a == AllocatorRemoveDecommissioningNonVoter {
+		a == AllocatorRemoveDecommissioningNonVoter ||
+		a == AllocatorReplaceDecommissioningWitness ||
+		a == AllocatorRemoveDecommissioningWitness {
 		s = Decommissioning
 	}
 	return s
@@ -227,6 +252,12 @@ var allocatorActionNames = map[AllocatorAction]string{
 	AllocatorConsiderRebalance:               "consider rebalance",
 	AllocatorRangeUnavailable:                "range unavailable",
 	AllocatorFinalizeAtomicReplicationChange: "finalize conf change",
+	AllocatorAddWitness:                      "add witness",
+	AllocatorReplaceDeadWitness:              "replace dead witness",
+	AllocatorReplaceDecommissioningWitness:   "replace decommissioning witness",
+	AllocatorRemoveDeadWitness:               "remove dead witness",
+	AllocatorRemoveDecommissioningWitness:    "remove decommissioning witness",
+	AllocatorRemoveWitness:                   "remove witness",
 }
 
 func (a AllocatorAction) String() string {
@@ -259,6 +290,18 @@ func (a AllocatorAction) Priority() float64 {
 		return 900
 	case AllocatorRemoveVoter:
 		return 800
+	case AllocatorReplaceDeadWitness:
+		return 760
+	case AllocatorAddWitness:
+		return 740
+	case AllocatorReplaceDecommissioningWitness:
+		return 720
+	case AllocatorRemoveDeadWitness:
+		return 715
+	case AllocatorRemoveDecommissioningWitness:
+		return 710
+	case AllocatorRemoveWitness:
+		return 705
 	case AllocatorReplaceDeadNonVoter:
 		return 700
 	case AllocatorAddNonVoter:
@@ -278,8 +321,8 @@ func (a AllocatorAction) Priority() float64 {
 	}
 }
 
-// TargetReplicaType indicates whether the target replica is a voter or
-// non-voter.
+// TargetReplicaType indicates whether the target replica is a voter,
+// non-voter or witness.
 type TargetReplicaType int
 
 const (
@@ -288,6 +331,8 @@ const (
 	VoterTarget
 	// NonVoterTarget represents a non-voting target replica.
 	NonVoterTarget
+	// WitnessTarget represents a witness target replica.
+	WitnessTarget
 )
 
 // ReplicaStatus represents whether a replica is currently alive,
@@ -315,6 +360,8 @@ func (t TargetReplicaType) AddChangeType() roachpb.ReplicaChangeType {
 		return roachpb.ADD_VOTER
 	case NonVoterTarget:
 		return roachpb.ADD_NON_VOTER
+	case WitnessTarget:
+		return roachpb.ADD_WITNESS
 	default:
 		panic(fmt.Sprintf("unknown targetReplicaType %d", t))
 	}
@@ -328,6 +375,8 @@ func (t TargetReplicaType) RemoveChangeType() roachpb.ReplicaChangeType {
 		return roachpb.REMOVE_VOTER
 	case NonVoterTarget:
 		return roachpb.REMOVE_NON_VOTER
+	case WitnessTarget:
+		return roachpb.REMOVE_WITNESS
 	default:
 		panic(fmt.Sprintf("unknown targetReplicaType %d", t))
 	}
@@ -339,6 +388,8 @@ func (t TargetReplicaType) String() string {
 		return "voter"
 	case NonVoterTarget:
 		return "non-voter"
+	case WitnessTarget:
+		return "witness"
 	default:
 		panic(fmt.Sprintf("unknown targetReplicaType %d", t))
 	}
@@ -685,6 +736,21 @@ func GetNeededNonVoters(numVoters, zoneConfigNonVoterCount, clusterNodes int) in
 	return need
 }
 
+// GetNeededWitnesses calculates how many of the given number of needed voters
+// should be witnesses, given the witness count configured in the zone config.
+// The number of witnesses is capped so that a quorum of the voters are always
+// full voters, since witnesses cannot serve reads or hold the lease.
+func GetNeededWitnesses(zoneConfigWitnessCount, neededVoters int) int {
+	need := zoneConfigWitnessCount
+	if max := neededVoters - computeQuorum(neededVoters); need > max {
+		need = max
+	}
+	if need < 0 {
+		need = 0 // Must be non-negative.
+	}
+	return need
+}
+
 // WillHaveFragileQuorum determines, based on the number of existing voters,
 // incoming voters, and needed voters, if we will be upreplicating to a state
 // in which we don't have enough needed voters and yet will have a fragile quorum
@@ -925,7 +991,7 @@ func (a *Allocator) ComputeAction(
 	}
 
 	return a.computeAction(ctx, storePool, conf, desc.Replicas().VoterDescriptors(),
-		desc.Replicas().NonVoterDescriptors())
+		desc.Replicas().NonVoterDescriptors(), desc.Replicas().WitnessDescriptors())
 }
 
 func (a *Allocator) computeAction(
@@ -934,6 +1000,7 @@ func (a *Allocator) computeAction(
 	conf *roachpb.SpanConfig,
 	voterReplicas []roachpb.ReplicaDescriptor,
 	nonVoterReplicas []roachpb.ReplicaDescriptor,
+	witnessReplicas []roachpb.ReplicaDescriptor,
 ) (action AllocatorAction, adjustedPriority float64) {
 	// NB: The ordering of the checks in this method is intentional. The order in
 	// which these actions are returned by this method determines the relative
@@ -942,20 +1009,28 @@ func (a *Allocator) computeAction(
 	// (which influence the replicateQueue's decision of which range it'll pick to
 	// repair/rebalance before the others).
 	//
-	// In broad strokes, we first handle all voting replica-based actions and then
-	// the actions pertaining to non-voting replicas. Within each replica set, we
+	// In broad strokes, we first handle all voting replica-based actions, then
+	// the actions pertaining to witnesses and finally those pertaining to
+	// non-voting replicas. Within each replica set, we
 	// first handle operations that correspond to repairing/recovering the range.
 	// After that we handle rebalancing related actions, followed by removal
 	// actions.
+	//
+	// Witnesses are voters as far as raft is concerned, so they count towards
+	// the range's quorum, but the voter-based actions below only consider the
+	// full voters. The zone config's voter count includes its witnesses.
 	haveVoters := len(voterReplicas)
+	haveWitnesses := len(witnessReplicas)
 	decommissioningVoters := storePool.DecommissioningReplicas(voterReplicas)
 	postDecommissionVoters := haveVoters - len(decommissioningVoters)
 	// Node count including dead nodes but excluding
 	// decommissioning/decommissioned nodes.
 	clusterNodes := storePool.ClusterNodeCount()
-	neededVoters := GetNeededVoters(conf.GetNumVoters(), clusterNodes)
-	desiredQuorum := computeQuorum(neededVoters)
-	quorum := computeQuorum(haveVoters)
+	neededRaftVoters := GetNeededVoters(conf.GetNumVoters(), clusterNodes)
+	neededWitnesses := GetNeededWitnesses(int(conf.NumWitnesses), neededRaftVoters)
+	neededVoters := neededRaftVoters - neededWitnesses
+	desiredQuorum := computeQuorum(neededRaftVoters)
+	quorum := computeQuorum(haveVoters + haveWitnesses)
 
 	// TODO(aayush): When haveVoters < neededVoters but we don't have quorum to
 	// actually execute the addition of a new replica, we should be returning a
@@ -965,7 +1040,7 @@ func (a *Allocator) computeAction(
 		// Priority is adjusted by the difference between the current voter
 		// count and the quorum of the desired voter count.
 		action = AllocatorAddVoter
-		adjustedPriority = action.Priority() + float64(desiredQuorum-haveVoters)
+		adjustedPriority = action.Priority() + float64(desiredQuorum-haveVoters-haveWitnesses)
 		log.KvDistribution.VEventf(ctx, 3, "%s - missing voter need=%d, have=%d, priority=%.2f",
 			action, neededVoters, haveVoters, adjustedPriority)
 		return action, adjustedPriority
@@ -979,15 +1054,16 @@ func (a *Allocator) computeAction(
 	// elsewhere (for a regular rebalance or for decommissioning).
 	const includeSuspectAndDrainingStores = true
 	liveVoters, deadVoters := storePool.LiveAndDeadReplicas(voterReplicas, includeSuspectAndDrainingStores)
+	liveWitnesses, deadWitnesses := storePool.LiveAndDeadReplicas(witnessReplicas, includeSuspectAndDrainingStores)
 
-	if len(liveVoters) < quorum {
+	if len(liveVoters)+len(liveWitnesses) < quorum {
 		// Do not take any replacement/removal action if we do not have a quorum of
 		// live voters. If we're correctly assessing the unavailable state of the
 		// range, we also won't be able to add replicas as we try above, but hope
 		// springs eternal.
 		action = AllocatorRangeUnavailable
-		log.KvDistribution.VEventf(ctx, 1, "unable to take action - live voters %v don't meet quorum of %d",
-			liveVoters, quorum)
+		log.KvDistribution.VEventf(ctx, 1, "unable to take action - live voters %v and witnesses %v don't meet quorum of %d",
+			liveVoters, liveWitnesses, quorum)
 		return action, action.Priority()
 	}
 
@@ -1018,7 +1094,7 @@ func (a *Allocator) computeAction(
 	if len(deadVoters) > 0 {
 		// The range has dead replicas, which should be removed immediately.
 		action = AllocatorRemoveDeadVoter
-		adjustedPriority = action.Priority() + float64(quorum-len(liveVoters))
+		adjustedPriority = action.Priority() + float64(quorum-len(liveVoters)-len(liveWitnesses))
 		log.KvDistribution.VEventf(ctx, 3, "%s - dead=%d, live=%d, quorum=%d, priority=%.2f",
 			action, len(deadVoters), len(liveVoters), quorum, adjustedPriority)
 		return action, adjustedPriority
@@ -1034,6 +1110,16 @@ func (a *Allocator) computeAction(
 		return action, action.Priority()
 	}
 
+	if haveVoters > neededVoters && haveWitnesses < neededWitnesses {
+		// The range is configured to replace some of its full voters with
+		// witnesses. Add the witness before removing the voter so that the number
+		// of raft voters doesn't drop below what is needed in between.
+		action = AllocatorAddWitness
+		log.KvDistribution.VEventf(ctx, 3, "%s - missing witness need=%d, have=%d, priority=%.2f",
+			action, neededWitnesses, haveWitnesses, action.Priority())
+		return action, action.Priority()
+	}
+
 	if haveVoters > neededVoters {
 		// Range is over-replicated, and should remove a voter.
 		// Ranges with an even number of voters get extra priority because
@@ -1045,11 +1131,58 @@ func (a *Allocator) computeAction(
 		return action, adjustedPriority
 	}
 
+	// Witness actions follow.
+	if haveWitnesses < neededWitnesses {
+		action = AllocatorAddWitness
+		log.KvDistribution.VEventf(ctx, 3, "%s - missing witness need=%d, have=%d, priority=%.2f",
+			action, neededWitnesses, haveWitnesses, action.Priority())
+		return action, action.Priority()
+	}
+
+	decommissioningWitnesses := storePool.DecommissioningReplicas(witnessReplicas)
+	postDecommissionWitnesses := haveWitnesses - len(decommissioningWitnesses)
+
+	if postDecommissionWitnesses <= neededWitnesses && len(deadWitnesses) > 0 {
+		action = AllocatorReplaceDeadWitness
+		log.KvDistribution.VEventf(ctx, 3, "%s - replacement for %d dead witnesses priority=%.2f",
+			action, len(deadWitnesses), action.Priority())
+		return action, action.Priority()
+	}
+
+	if postDecommissionWitnesses < neededWitnesses {
+		action = AllocatorReplaceDecommissioningWitness
+		log.KvDistribution.VEventf(ctx, 3, "%s - replacement for %d decommissioning witnesses priority=%.2f",
+			action, len(decommissioningWitnesses), action.Priority())
+		return action, action.Priority()
+	}
+
+	if len(deadWitnesses) > 0 {
+		action = AllocatorRemoveDeadWitness
+		log.KvDistribution.VEventf(ctx, 3, "%s - dead=%d, live=%d, priority=%.2f",
+			action, len(deadWitnesses), len(liveWitnesses), action.Priority())
+		return action, action.Priority()
+	}
+
+	if len(decommissioningWitnesses) > 0 {
+		action = AllocatorRemoveDecommissioningWitness
+		log.KvDistribution.VEventf(ctx, 3,
+			"%s - need=%d, have=%d, num_decommissioning=%d, priority=%.2f",
+			action, neededWitnesses, haveWitnesses, len(decommissioningWitnesses), action.Priority())
+		return action, action.Priority()
+	}
+
+	if haveWitnesses > neededWitnesses {
+		action = AllocatorRemoveWitness
+		log.KvDistribution.VEventf(ctx, 3, "%s - need=%d, have=%d, priority=%.2f",
+			action, neededWitnesses, haveWitnesses, action.Priority())
+		return action, action.Priority()
+	}
+
 	// Non-voting replica actions follow.
 	//
 	// Non-voting replica addition / replacement.
 	haveNonVoters := len(nonVoterReplicas)
-	neededNonVoters := GetNeededNonVoters(haveVoters, int(conf.GetNumNonVoters()), clusterNodes)
+	neededNonVoters := GetNeededNonVoters(haveVoters+haveWitnesses, int(conf.GetNumNonVoters()), clusterNodes)
 	if haveNonVoters < neededNonVoters {
 		action = AllocatorAddNonVoter
 		log.KvDistribution.VEventf(ctx, 3, "%s - missing non-voter need=%d, have=%d, priority=%.2f",
@@ -1339,6 +1472,24 @@ func (a *Allocator) AllocateNonVoter(
 	return a.AllocateTarget(ctx, storePool, conf, existingVoters, existingNonVoters, replacing, replicaStatus, NonVoterTarget)
 }
 
+// AllocateWitness returns a suitable store for a new allocation of a witness
+// replica. Witnesses don't hold the lease and don't serve reads, so they're
+// placed like non-voting replicas: they need to satisfy the range's overall
+// constraints but not its voter constraints. Nodes already accommodating _any_
+// existing replicas, including witnesses, are ruled out as targets.
+func (a *Allocator) AllocateWitness(
+	ctx context.Context,
+	storePool storepool.AllocatorStorePool,
+	conf *roachpb.SpanConfig,
+	existingVoters, existingNonVoters, existingWitnesses []roachpb.ReplicaDescriptor,
+	replacing *roachpb.ReplicaDescriptor,
+	replicaStatus ReplicaStatus,
+) (roachpb.ReplicationTarget, string, error) {
+	existingNonVoters = append(append([]roachpb.ReplicaDescriptor(nil), existingNonVoters...),
+		existingWitnesses...)
+	return a.AllocateTarget(ctx, storePool, conf, existingVoters, existingNonVoters, replacing, replicaStatus, NonVoterTarget)
+}
+
 // AllocateTargetFromList returns a suitable store for a new allocation of a
 // replica of the given type from the set of candidate stores, with the given
 // existing set of voters and non-voters..
@@ -1423,6 +1574,7 @@ func (a *Allocator) allocateTargetFromList(
 	default:
 		log.KvDistribution.Fatalf(ctx, "unsupported targetReplicaType: %v", t)
 	}
+	constraintsChecker = storageTierChecker(constraintsChecker, conf.StorageTier)
 
 	// We'll consider the targets that have a non-voter as feasible
 	// relocation/up-replication targets for existing/new voting replicas, since
@@ -1564,6 +1716,8 @@ func (a Allocator) RemoveTarget(
 	default:
 		log.KvDistribution.Fatalf(ctx, "unsupported targetReplicaType: %v", t)
 	}
+	// Replicas on stores of the wrong storage tier are preferred for removal.
+	constraintsChecker = storageTierChecker(constraintsChecker, conf.StorageTier)
 
 	replicaSetForDiversityCalc := getReplicasForDiversityCalc(targetType, existingVoters, existingReplicas)
 	rankedCandidates := candidateListForRemoval(
@@ -1659,6 +1813,25 @@ func (a Allocator) RemoveNonVoter(
 	)
 }
 
+// RemoveWitness returns a suitable witness replica to remove from the provided
+// set. See AllocateWitness for how witnesses are treated by the allocator.
+func (a Allocator) RemoveWitness(
+	ctx context.Context,
+	storePool storepool.AllocatorStorePool,
+	conf *roachpb.SpanConfig,
+	witnessCandidates []roachpb.ReplicaDescriptor,
+	existingVoters []roachpb.ReplicaDescriptor,
+	existingNonVoters []roachpb.ReplicaDescriptor,
+	existingWitnesses []roachpb.ReplicaDescriptor,
+	options ScorerOptions,
+) (roachpb.ReplicationTarget, string, error) {
+	existingNonVoters = append(append([]roachpb.ReplicaDescriptor(nil), existingNonVoters...),
+		existingWitnesses...)
+	return a.RemoveNonVoter(
+		ctx, storePool, conf, witnessCandidates, existingVoters, existingNonVoters, options,
+	)
+}
+
 // RebalanceTarget returns a suitable store for a rebalance target (of the given
 // type) with required attributes.
 func (a Allocator) RebalanceTarget(
@@ -1725,6 +1898,11 @@ func (a Allocator) RebalanceTarget(
 	default:
 		log.KvDistribution.Fatalf(ctx, "unsupported targetReplicaType: %v", t)
 	}
+	// Replicas on stores of the wrong storage tier are rebalanced onto stores of
+	// the span's storage tier. This is how data migrates between tiers when the
+	// span's configured tier changes.
+	removalConstraintsChecker = storageTierChecker(removalConstraintsChecker, conf.StorageTier)
+	rebalanceConstraintsChecker = storageTierRebalanceChecker(rebalanceConstraintsChecker, conf.StorageTier)
 
 	replicaSetForDiversityCalc := getReplicasForDiversityCalc(targetType, existingVoters, existingReplicas)
 	results := rankedCandidateListForRebalancing(
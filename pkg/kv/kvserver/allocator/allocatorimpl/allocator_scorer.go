@@ -1964,6 +1964,32 @@ func nonVoterConstraintsCheckerForReplace(
 	}
 }
 
+// storageTierChecker returns a constraintsCheckFn that additionally marks
+// stores that don't belong to the span's storage tier as invalid.
+func storageTierChecker(check constraintsCheckFn, tier roachpb.StorageTier) constraintsCheckFn {
+	if tier == roachpb.StorageTier_ANY {
+		return check
+	}
+	return func(s roachpb.StoreDescriptor) (valid, necessary bool) {
+		valid, necessary = check(s)
+		return valid && tier.Admits(s.Properties.StorageTier), necessary
+	}
+}
+
+// storageTierRebalanceChecker is like storageTierChecker, for a
+// rebalanceConstraintsCheckFn.
+func storageTierRebalanceChecker(
+	check rebalanceConstraintsCheckFn, tier roachpb.StorageTier,
+) rebalanceConstraintsCheckFn {
+	if tier == roachpb.StorageTier_ANY {
+		return check
+	}
+	return func(toStore, fromStore roachpb.StoreDescriptor) (valid, necessary bool) {
+		valid, necessary = check(toStore, fromStore)
+		return valid && tier.Admits(toStore.Properties.StorageTier), necessary
+	}
+}
+
 // allocateConstraintsCheck checks the potential allocation target store
 // against all the constraints. If it matches a constraint at all, it's valid.
 // If it matches a constraint that is not already fully satisfied by existing
@@ -7820,6 +7820,93 @@ func TestAllocatorGetNeededReplicas(t *testing.T) {
 	}
 }
 
+func TestAllocatorGetNeededWitnesses(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testCases := []struct {
+		numWitnesses int
+		neededVoters int
+		expected     int
+	}{
+		{0, 3, 0},
+		{1, 1, 0},
+		{1, 3, 1},
+		{2, 3, 1},
+		{1, 5, 1},
+		{2, 5, 2},
+		{3, 5, 2},
+		{-1, 3, 0},
+	}
+
+	for _, tc := range testCases {
+		if e, a := tc.expected, GetNeededWitnesses(tc.numWitnesses, tc.neededVoters); e != a {
+			t.Errorf(
+				"GetNeededWitnesses(numWitnesses=%d, neededVoters=%d) got %d; want %d",
+				tc.numWitnesses, tc.neededVoters, a, e)
+		}
+	}
+}
+
+func TestAllocatorStorageTier(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// Stores 4 and 5 keep their data in remote storage.
+	stores := make([]*roachpb.StoreDescriptor, len(sameDCStores))
+	for i, s := range sameDCStores {
+		store := *s
+		if store.StoreID >= 4 {
+			store.Properties.StorageTier = roachpb.StorageTier_COLD
+		}
+		stores[i] = &store
+	}
+
+	ctx := context.Background()
+	stopper, g, sp, a, _ := CreateTestAllocator(ctx, 10, false /* deterministic */)
+	defer stopper.Stop(ctx)
+	gossiputil.NewStoreGossiper(g).GossipStores(stores, t)
+
+	testCases := []struct {
+		tier     roachpb.StorageTier
+		existing []roachpb.ReplicaDescriptor
+		expected []roachpb.StoreID
+	}{
+		{roachpb.StorageTier_ANY, replicas(1, 2, 3, 4), []roachpb.StoreID{5}},
+		{roachpb.StorageTier_HOT, replicas(1, 2), []roachpb.StoreID{3}},
+		{roachpb.StorageTier_COLD, nil, []roachpb.StoreID{4, 5}},
+		{roachpb.StorageTier_COLD, replicas(4), []roachpb.StoreID{5}},
+	}
+	for _, tc := range testCases {
+		conf := &roachpb.SpanConfig{NumReplicas: 3, StorageTier: tc.tier}
+		result, _, err := a.AllocateVoter(
+			ctx, sp, conf, tc.existing, nil /* existingNonVoters */, nil, /* replacing */
+			Dead,
+		)
+		require.NoError(t, err)
+		require.Truef(t, checkReplExists(result, tc.expected),
+			"tier %s: expected one of %v, got %v", tc.tier, tc.expected, result)
+	}
+
+	// No store remains in the HOT tier.
+	conf := &roachpb.SpanConfig{NumReplicas: 3, StorageTier: roachpb.StorageTier_HOT}
+	_, _, err := a.AllocateVoter(
+		ctx, sp, conf, replicas(1, 2, 3), nil /* existingNonVoters */, nil, /* replacing */
+		Dead,
+	)
+	require.Error(t, err)
+
+	// Replicas on stores of the wrong tier are removed first.
+	conf = &roachpb.SpanConfig{NumReplicas: 3, StorageTier: roachpb.StorageTier_COLD}
+	existing := replicas(1, 4, 5)
+	remove, _, err := a.RemoveVoter(
+		ctx, sp, conf, existing /* voterCandidates */, existing, nil, /* existingNonVoters */
+		a.ScorerOptions(ctx),
+	)
+	require.NoError(t, err)
+	require.Equal(t, roachpb.StoreID(1), remove.StoreID)
+}
+
 func makeDescriptor(storeList []roachpb.StoreID) roachpb.RangeDescriptor {
 	desc := roachpb.RangeDescriptor{
 		EndKey: roachpb.RKey(keys.SystemPrefix),
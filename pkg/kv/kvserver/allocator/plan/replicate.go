@@ -259,6 +259,10 @@ func (rp ReplicaPlanner) PlanOneChange(
 	voterReplicas, nonVoterReplicas,
 		liveVoterReplicas, deadVoterReplicas,
 		liveNonVoterReplicas, deadNonVoterReplicas := allocatorimpl.LiveAndDeadVoterAndNonVoterReplicas(rp.storePool, desc)
+	witnessReplicas := desc.Replicas().WitnessDescriptors()
+	liveWitnessReplicas, deadWitnessReplicas := rp.storePool.LiveAndDeadReplicas(
+		witnessReplicas, true, /* includeSuspectAndDrainingStores */
+	)
 
 	// NB: the replication layer ensures that the below operations don't cause
 	// unavailability; see kvserver.execChangeReplicasTxn.
@@ -295,6 +299,10 @@ func (rp ReplicaPlanner) PlanOneChange(
 			break
 		}
 
+		// Witnesses are passed along with the non-voters so that their stores
+		// aren't picked for a new replica, other than for replacing a witness
+		// with a voter.
+		remainingLiveNonVoters = append(remainingLiveNonVoters, liveWitnessReplicas...)
 		switch action.TargetReplicaType() {
 		case allocatorimpl.VoterTarget:
 			op, stats, err = rp.addOrReplaceVoters(
@@ -310,6 +318,16 @@ func (rp ReplicaPlanner) PlanOneChange(
 			panic(fmt.Sprintf("unsupported targetReplicaType: %v", action.TargetReplicaType()))
 		}
 
+	// Add witnesses, replace dead witnesses, or replace decommissioning
+	// witnesses.
+	case allocatorimpl.AllocatorAddWitness, allocatorimpl.AllocatorReplaceDeadWitness,
+		allocatorimpl.AllocatorReplaceDecommissioningWitness:
+		op, stats, err = rp.addOrReplaceWitnesses(
+			ctx, repl, conf, voterReplicas, witnessReplicas,
+			liveVoterReplicas, liveNonVoterReplicas, liveWitnessReplicas, deadWitnessReplicas,
+			action.ReplicaStatus(), allocatorPrio,
+		)
+
 	// Remove replicas.
 	case allocatorimpl.AllocatorRemoveVoter:
 		op, stats, err = rp.removeVoter(ctx, repl, desc, conf, voterReplicas, nonVoterReplicas)
@@ -325,6 +343,8 @@ func (rp ReplicaPlanner) PlanOneChange(
 		op, stats, err = rp.removeDecommissioning(ctx, repl, desc, conf, allocatorimpl.VoterTarget)
 	case allocatorimpl.AllocatorRemoveDecommissioningNonVoter:
 		op, stats, err = rp.removeDecommissioning(ctx, repl, desc, conf, allocatorimpl.NonVoterTarget)
+	case allocatorimpl.AllocatorRemoveDecommissioningWitness:
+		op, stats, err = rp.removeDecommissioning(ctx, repl, desc, conf, allocatorimpl.WitnessTarget)
 
 	// Remove dead replicas.
 	//
@@ -335,6 +355,10 @@ func (rp ReplicaPlanner) PlanOneChange(
 		op, stats, err = rp.removeDead(ctx, repl, deadVoterReplicas, allocatorimpl.VoterTarget)
 	case allocatorimpl.AllocatorRemoveDeadNonVoter:
 		op, stats, err = rp.removeDead(ctx, repl, deadNonVoterReplicas, allocatorimpl.NonVoterTarget)
+	case allocatorimpl.AllocatorRemoveDeadWitness:
+		op, stats, err = rp.removeDead(ctx, repl, deadWitnessReplicas, allocatorimpl.WitnessTarget)
+	case allocatorimpl.AllocatorRemoveWitness:
+		op, stats, err = rp.removeWitness(ctx, repl, conf, voterReplicas, nonVoterReplicas, witnessReplicas)
 	// Rebalance replicas.
 	//
 	// NB: Rebalacing attempts to balance replica counts among stores of
@@ -432,7 +456,13 @@ func (rp ReplicaPlanner) addOrReplaceVoters(
 	// a voting replica or if we ought to be adding a voter afresh.
 	var ops []kvpb.ReplicationChange
 	replDesc, found := desc.GetReplicaDescriptor(newVoter.StoreID)
-	if found {
+	if found && replDesc.Type == roachpb.WITNESS {
+		// The allocation target is a witness, which has no user data. Replace it
+		// with a full voter on the same store, which receives a regular snapshot;
+		// a replacement witness is added in a later pass if one is still needed.
+		ops = kvpb.MakeReplicationChanges(roachpb.ADD_VOTER, newVoter)
+		ops = append(ops, kvpb.MakeReplicationChanges(roachpb.REMOVE_WITNESS, newVoter)...)
+	} else if found {
 		if replDesc.Type != roachpb.NON_VOTER {
 			return nil, stats, errors.AssertionFailedf("allocation target %s for a voter"+
 				" already has an unexpected replica: %s", newVoter, replDesc)
@@ -530,6 +560,74 @@ func (rp ReplicaPlanner) addOrReplaceNonVoters(
 	return op, stats, nil
 }
 
+// addOrReplaceWitnesses adds a witness replica to `repl`s range, replacing a
+// dead or decommissioning witness if replicaStatus says so.
+func (rp ReplicaPlanner) addOrReplaceWitnesses(
+	ctx context.Context,
+	repl AllocatorReplica,
+	conf *roachpb.SpanConfig,
+	existingVoters, existingWitnesses []roachpb.ReplicaDescriptor,
+	liveVoterReplicas, liveNonVoterReplicas []roachpb.ReplicaDescriptor,
+	liveWitnessReplicas, deadWitnessReplicas []roachpb.ReplicaDescriptor,
+	replicaStatus allocatorimpl.ReplicaStatus,
+	allocatorPrio float64,
+) (op AllocationOp, stats ReplicateStats, _ error) {
+	var replacing *roachpb.ReplicaDescriptor
+	remainingLiveWitnesses := liveWitnessReplicas
+	switch replicaStatus {
+	case allocatorimpl.Dead:
+		if len(deadWitnessReplicas) == 0 {
+			return nil, stats, nil
+		}
+		replacing = &deadWitnessReplicas[0]
+	case allocatorimpl.Decommissioning:
+		decommissioning := rp.storePool.DecommissioningReplicas(existingWitnesses)
+		if len(decommissioning) == 0 {
+			return nil, stats, nil
+		}
+		replacing = &decommissioning[0]
+		remainingLiveWitnesses = nil
+		for _, w := range liveWitnessReplicas {
+			if w.StoreID != replacing.StoreID {
+				remainingLiveWitnesses = append(remainingLiveWitnesses, w)
+			}
+		}
+	}
+
+	newWitness, details, err := rp.allocator.AllocateWitness(ctx, rp.storePool, conf,
+		liveVoterReplicas, liveNonVoterReplicas, remainingLiveWitnesses, replacing, replicaStatus)
+	if err != nil {
+		return nil, stats, err
+	}
+
+	stats = stats.trackAddReplicaCount(allocatorimpl.WitnessTarget)
+	ops := kvpb.MakeReplicationChanges(roachpb.ADD_WITNESS, newWitness)
+	if replacing == nil {
+		log.KvDistribution.Infof(ctx, "adding witness %+v: %s",
+			newWitness, rangeRaftProgress(repl.RaftStatus(), existingVoters))
+	} else {
+		stats = stats.trackRemoveMetric(allocatorimpl.WitnessTarget, replicaStatus)
+		log.KvDistribution.Infof(ctx, "replacing witness %s with %+v: %s",
+			replacing, newWitness, rangeRaftProgress(repl.RaftStatus(), existingVoters))
+		ops = append(ops,
+			kvpb.MakeReplicationChanges(roachpb.REMOVE_WITNESS, roachpb.ReplicationTarget{
+				StoreID: replacing.StoreID,
+				NodeID:  replacing.NodeID,
+			})...)
+	}
+
+	op = AllocationChangeReplicasOp{
+		lhStore:           repl.StoreID(),
+		Usage:             repl.RangeUsageInfo(),
+		Chgs:              ops,
+		Priority:          kvserverpb.SnapshotRequest_RECOVERY,
+		AllocatorPriority: allocatorPrio,
+		Reason:            kvserverpb.ReasonRangeUnderReplicated,
+		Details:           details,
+	}
+	return op, stats, nil
+}
+
 // findRemoveVoter takes a list of voting replicas and picks one to remove,
 // making sure to not remove a newly added voter or to violate the zone configs
 // in the process.
@@ -705,6 +803,42 @@ func (rp ReplicaPlanner) removeNonVoter(
 	return op, stats, nil
 }
 
+func (rp ReplicaPlanner) removeWitness(
+	ctx context.Context,
+	repl AllocatorReplica,
+	conf *roachpb.SpanConfig,
+	existingVoters, existingNonVoters, existingWitnesses []roachpb.ReplicaDescriptor,
+) (op AllocationOp, stats ReplicateStats, _ error) {
+	removeWitness, details, err := rp.allocator.RemoveWitness(
+		ctx,
+		rp.storePool,
+		conf,
+		existingWitnesses,
+		existingVoters,
+		existingNonVoters,
+		existingWitnesses,
+		rp.allocator.ScorerOptions(ctx),
+	)
+	if err != nil {
+		return nil, stats, err
+	}
+	stats = stats.trackRemoveMetric(allocatorimpl.WitnessTarget, allocatorimpl.Alive)
+
+	log.KvDistribution.Infof(ctx, "removing witness %+v due to over-replication: %s",
+		removeWitness, rangeRaftProgress(repl.RaftStatus(), existingVoters))
+
+	op = AllocationChangeReplicasOp{
+		lhStore:           repl.StoreID(),
+		Usage:             repl.RangeUsageInfo(),
+		Chgs:              kvpb.MakeReplicationChanges(roachpb.REMOVE_WITNESS, removeWitness),
+		Priority:          kvserverpb.SnapshotRequest_UNKNOWN, // unused
+		AllocatorPriority: 0.0,                                // unused
+		Reason:            kvserverpb.ReasonRangeOverReplicated,
+		Details:           details,
+	}
+	return op, stats, nil
+}
+
 func (rp ReplicaPlanner) removeDecommissioning(
 	ctx context.Context,
 	repl AllocatorReplica,
@@ -722,6 +856,10 @@ func (rp ReplicaPlanner) removeDecommissioning(
 		decommissioningReplicas = rp.storePool.DecommissioningReplicas(
 			desc.Replicas().NonVoterDescriptors(),
 		)
+	case allocatorimpl.WitnessTarget:
+		decommissioningReplicas = rp.storePool.DecommissioningReplicas(
+			desc.Replicas().WitnessDescriptors(),
+		)
 	default:
 		panic(fmt.Sprintf("unknown targetReplicaType: %s", targetType))
 	}
@@ -75,6 +75,8 @@ func (rs ReplicateStats) trackAddReplicaCount(
 		rs.AddVoterReplicaCount++
 	case allocatorimpl.NonVoterTarget:
 		rs.AddNonVoterReplicaCount++
+	case allocatorimpl.WitnessTarget:
+		// Witnesses are only tracked in the aggregate counts.
 	default:
 		panic(fmt.Sprintf("unsupported targetReplicaType: %v", targetType))
 	}
@@ -111,6 +113,8 @@ func (rs ReplicateStats) trackRemoveReplicaCount(
 		rs.RemoveVoterReplicaCount++
 	case allocatorimpl.NonVoterTarget:
 		rs.RemoveNonVoterReplicaCount++
+	case allocatorimpl.WitnessTarget:
+		// Witnesses are only tracked in the aggregate counts.
 	default:
 		panic(fmt.Sprintf("unsupported targetReplicaType: %v", targetType))
 	}
@@ -128,6 +132,8 @@ func (rs ReplicateStats) trackRemoveDeadReplicaCount(
 		rs.RemoveDeadVoterReplicaCount++
 	case allocatorimpl.NonVoterTarget:
 		rs.RemoveDeadNonVoterReplicaCount++
+	case allocatorimpl.WitnessTarget:
+		// Witnesses are only tracked in the aggregate counts.
 	default:
 		panic(fmt.Sprintf("unsupported targetReplicaType: %v", targetType))
 	}
@@ -146,6 +152,8 @@ func (rs ReplicateStats) trackRemoveDecommissioningReplicaCount(
 		rs.RemoveDecommissioningVoterReplicaCount++
 	case allocatorimpl.NonVoterTarget:
 		rs.RemoveDecommissioningNonVoterReplicaCount++
+	case allocatorimpl.WitnessTarget:
+		// Witnesses are only tracked in the aggregate counts.
 	default:
 		panic(fmt.Sprintf("unsupported targetReplicaType: %v", targetType))
 	}
@@ -163,6 +171,8 @@ func (rs ReplicateStats) trackRebalanceReplicaCount(
 		rs.RebalanceVoterReplicaCount++
 	case allocatorimpl.NonVoterTarget:
 		rs.RebalanceNonVoterReplicaCount++
+	case allocatorimpl.WitnessTarget:
+		// Witnesses are only tracked in the aggregate counts.
 	default:
 		panic(fmt.Sprintf("unsupported targetReplicaType: %v", targetType))
 	}
@@ -154,6 +154,9 @@ type postAddEnv struct {
 	eng         storage.Engine
 	sideloaded  logstore.SideloadStorage
 	bulkLimiter *rate.Limiter
+	// witness is set if the replica is a WITNESS, which doesn't hold user keys
+	// and thus skips AddSSTable ingestions.
+	witness bool
 }
 
 func (b *appBatch) runPostAddTriggers(
@@ -174,7 +177,7 @@ func (b *appBatch) runPostAddTriggers(
 	// NB: any command which has an AddSSTable is non-trivial and will be
 	// applied in its own batch so it's not possible that any other commands
 	// which precede this command can shadow writes from this SSTable.
-	if res.AddSSTable != nil {
+	if res.AddSSTable != nil && !env.witness {
 		copied := addSSTablePreApply(
 			ctx,
 			env,
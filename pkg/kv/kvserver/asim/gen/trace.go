@@ -0,0 +1,305 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/config"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/state"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/workload"
+	"github.com/cockroachdb/errors"
+)
+
+// HotRange is a range in a hot ranges report, as returned by the
+// _status/v2/hotranges endpoint (serverpb.HotRangesResponseV2).
+type HotRange struct {
+	RangeID             int64   `json:"range_id"`
+	NodeID              int32   `json:"node_id"`
+	QPS                 float64 `json:"qps"`
+	ReplicaNodeIDs      []int32 `json:"replica_node_ids"`
+	LeaseholderNodeID   int32   `json:"leaseholder_node_id"`
+	WritesPerSecond     float64 `json:"writes_per_second"`
+	ReadsPerSecond      float64 `json:"reads_per_second"`
+	WriteBytesPerSecond float64 `json:"write_bytes_per_second"`
+	ReadBytesPerSecond  float64 `json:"read_bytes_per_second"`
+	CPUTimePerSecond    float64 `json:"cpu_time_per_second"`
+}
+
+// leaseholder returns the node holding the lease for the hot range. Older
+// reports don't populate the leaseholder, in which case the reporting node is
+// assumed to hold the lease.
+func (hr HotRange) leaseholder() int32 {
+	if hr.LeaseholderNodeID != 0 {
+		return hr.LeaseholderNodeID
+	}
+	return hr.NodeID
+}
+
+type hotRangesReport struct {
+	Ranges []HotRange `json:"ranges"`
+}
+
+// defaultTraceKeysPerRange is the number of simulated keys assigned to each
+// range of a trace when not specified.
+const defaultTraceKeysPerRange = 1000
+
+// Trace is the load history of a cluster, reconstructed from a series of hot
+// ranges reports taken at a fixed interval. A trace is used to generate the
+// cluster, ranges and load of a simulation, via TraceCluster, TraceRanges and
+// TraceLoad, which replay the recorded load against the recorded replica and
+// lease placement.
+//
+// Hot ranges reports don't contain range boundaries, localities or span
+// configurations. The ranges of a trace are assigned consecutive, equally
+// sized simulated key spans in range ID order. The nodes are placed into a
+// single zone and the span config of each range only carries its replication
+// factor. Constraints may be set over the trace's key spans separately.
+type Trace struct {
+	// Interval is the duration between consecutive reports.
+	Interval time.Duration
+	// KeysPerRange is the size of the simulated key span of each range.
+	KeysPerRange int64
+	// nodes are the IDs of the nodes seen in the trace, in ascending order.
+	// The simulated node ID of a node is its index + 1.
+	nodes []int32
+	// ranges are the ranges seen in the trace in range ID order, with the
+	// replica and lease placement from the first report they appear in.
+	ranges []HotRange
+	// reports are the hot ranges reports of the trace, keyed by range ID.
+	reports []map[int64]HotRange
+}
+
+// ReadHotRangesTrace returns the trace of the hot ranges reports read from r.
+// The reader is expected to contain one or more JSON encoded hot ranges
+// responses, in the order they were taken, each interval apart.
+func ReadHotRangesTrace(r io.Reader, interval time.Duration) (*Trace, error) {
+	if interval <= 0 {
+		return nil, errors.Newf("trace interval must be positive, found %s", interval)
+	}
+	t := &Trace{Interval: interval, KeysPerRange: defaultTraceKeysPerRange}
+	nodes := make(map[int32]struct{})
+	ranges := make(map[int64]HotRange)
+
+	dec := json.NewDecoder(r)
+	for {
+		var report hotRangesReport
+		if err := dec.Decode(&report); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "decoding hot ranges report %d", len(t.reports)+1)
+		}
+
+		byRangeID := make(map[int64]HotRange, len(report.Ranges))
+		for _, hr := range report.Ranges {
+			// A range may be reported by more than one node, prefer the
+			// report from the leaseholder.
+			if existing, ok := byRangeID[hr.RangeID]; ok && existing.NodeID == existing.leaseholder() {
+				continue
+			}
+			byRangeID[hr.RangeID] = hr
+		}
+		for rangeID, hr := range byRangeID {
+			if _, ok := ranges[rangeID]; !ok {
+				ranges[rangeID] = hr
+			}
+			if hr.NodeID != 0 {
+				nodes[hr.NodeID] = struct{}{}
+			}
+			if lh := hr.leaseholder(); lh != 0 {
+				nodes[lh] = struct{}{}
+			}
+			for _, nodeID := range hr.ReplicaNodeIDs {
+				nodes[nodeID] = struct{}{}
+			}
+		}
+		t.reports = append(t.reports, byRangeID)
+	}
+	if len(ranges) == 0 {
+		return nil, errors.New("trace contains no ranges")
+	}
+
+	for nodeID := range nodes {
+		t.nodes = append(t.nodes, nodeID)
+	}
+	sort.Slice(t.nodes, func(i, j int) bool { return t.nodes[i] < t.nodes[j] })
+	for _, hr := range ranges {
+		t.ranges = append(t.ranges, hr)
+	}
+	sort.Slice(t.ranges, func(i, j int) bool { return t.ranges[i].RangeID < t.ranges[j].RangeID })
+	return t, nil
+}
+
+// storeID returns the simulated store of the node with the given ID. Each
+// node in a trace has a single store, which shares the simulated node ID.
+func (t *Trace) storeID(nodeID int32) state.StoreID {
+	idx := sort.Search(len(t.nodes), func(i int) bool { return t.nodes[i] >= nodeID })
+	return state.StoreID(idx + 1)
+}
+
+// keySpan returns the simulated key span [start, end) of the i-th range.
+func (t *Trace) keySpan(i int) (start, end int64) {
+	start = int64(state.MinKey) + int64(i)*t.KeysPerRange
+	return start, start + t.KeysPerRange
+}
+
+// clusterInfo returns the cluster info of the trace, where every node is in
+// the same zone and has a single store.
+func (t *Trace) clusterInfo() state.ClusterInfo {
+	return state.ClusterInfo{
+		DiskCapacityGB: 1024,
+		Regions: []state.Region{{
+			Name:  "trace",
+			Zones: []state.Zone{state.NewZoneWithSingleStore("trace_1", len(t.nodes))},
+		}},
+	}
+}
+
+// placement returns the simulated stores holding replicas of the hot range
+// and the store holding its lease. The leaseholder is added as a replica if
+// the report doesn't include it.
+func (t *Trace) placement(hr HotRange) (voters []state.StoreID, leaseholder state.StoreID) {
+	leaseholder = t.storeID(hr.leaseholder())
+	found := false
+	for _, nodeID := range hr.ReplicaNodeIDs {
+		storeID := t.storeID(nodeID)
+		found = found || storeID == leaseholder
+		voters = append(voters, storeID)
+	}
+	if !found {
+		voters = append(voters, leaseholder)
+	}
+	return voters, leaseholder
+}
+
+// rangesInfo returns the ranges of the trace, with their replicas and lease
+// placed as in the first report each range appears in.
+func (t *Trace) rangesInfo() state.RangesInfo {
+	ret := make(state.RangesInfo, len(t.ranges))
+	for i, hr := range t.ranges {
+		voters, leaseholder := t.placement(hr)
+		spanConfig := state.SpanConfigWithReplicationFactor(len(voters))
+		startKey, _ := t.keySpan(i)
+		ret[i] = state.RangeInfoWithReplicas(
+			state.Key(startKey), voters, nil /* nonVoters */, leaseholder, &spanConfig)
+	}
+	return ret
+}
+
+// samples returns the load recorded in each report of the trace, as rates
+// over the simulated key span of each range. Reports which only populate QPS
+// have it replayed as reads.
+func (t *Trace) samples() []workload.ReplaySample {
+	rangeIdx := make(map[int64]int, len(t.ranges))
+	for i, hr := range t.ranges {
+		rangeIdx[hr.RangeID] = i
+	}
+	ret := make([]workload.ReplaySample, len(t.reports))
+	for i, report := range t.reports {
+		ret[i].Offset = time.Duration(i) * t.Interval
+		for rangeID, hr := range report {
+			minKey, maxKey := t.keySpan(rangeIdx[rangeID])
+			rate := workload.ReplayRate{
+				MinKey:              minKey,
+				MaxKey:              maxKey,
+				ReadsPerSecond:      hr.ReadsPerSecond,
+				WritesPerSecond:     hr.WritesPerSecond,
+				ReadBytesPerSecond:  hr.ReadBytesPerSecond,
+				WriteBytesPerSecond: hr.WriteBytesPerSecond,
+				CPUNanosPerSecond:   hr.CPUTimePerSecond,
+			}
+			if rate.ReadsPerSecond == 0 && rate.WritesPerSecond == 0 {
+				rate.ReadsPerSecond = hr.QPS
+			}
+			ret[i].Rates = append(ret[i].Rates, rate)
+		}
+		sort.Slice(ret[i].Rates, func(a, b int) bool {
+			return ret[i].Rates[a].MinKey < ret[i].Rates[b].MinKey
+		})
+	}
+	return ret
+}
+
+func (t *Trace) String() string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "trace with nodes=%d, ranges=%d, reports=%d, interval=%s, keys_per_range=%d",
+		len(t.nodes), len(t.ranges), len(t.reports), t.Interval, t.KeysPerRange)
+	for i, hr := range t.ranges {
+		startKey, endKey := t.keySpan(i)
+		voters, leaseholder := t.placement(hr)
+		fmt.Fprintf(&buf, "\n\tr%d: [%d,%d) replicas=%v leaseholder=%d",
+			hr.RangeID, startKey, endKey, voters, leaseholder)
+	}
+	return buf.String()
+}
+
+// TraceCluster implements the ClusterGen interface.
+type TraceCluster struct {
+	Trace *Trace
+}
+
+// Generate returns a new simulator state, where the cluster contains a node
+// with a single store for each node seen in the trace. There is no randomness
+// in this cluster generation.
+func (tc TraceCluster) Generate(seed int64, settings *config.SimulationSettings) state.State {
+	return state.LoadClusterInfo(tc.Trace.clusterInfo(), settings)
+}
+
+func (tc TraceCluster) String() string {
+	return fmt.Sprintf("trace cluster with\n %v", tc.Trace.clusterInfo())
+}
+
+func (tc TraceCluster) Regions() []state.Region {
+	return tc.Trace.clusterInfo().Regions
+}
+
+// TraceRanges implements the RangeGen interface.
+type TraceRanges struct {
+	Trace *Trace
+}
+
+func (tr TraceRanges) String() string {
+	return fmt.Sprintf("trace ranges with ranges=%d", len(tr.Trace.ranges))
+}
+
+// Generate returns an updated simulator state, where the ranges of the trace
+// are loaded with their recorded replica and lease placement. There is no
+// randomness in this range generation.
+func (tr TraceRanges) Generate(
+	seed int64, settings *config.SimulationSettings, s state.State,
+) state.State {
+	state.LoadRangeInfo(s, tr.Trace.rangesInfo()...)
+	return s
+}
+
+// TraceLoad implements the LoadGen interface.
+type TraceLoad struct {
+	Trace *Trace
+}
+
+func (tl TraceLoad) String() string {
+	return fmt.Sprintf("trace load with reports=%d, interval=%s", len(tl.Trace.reports), tl.Trace.Interval)
+}
+
+// Generate returns a workload generator which replays the load recorded in
+// each report of the trace, from the start of the simulation. The returned
+// workload generator is seeded with the provided seed, which determines the
+// keys accessed within each range.
+func (tl TraceLoad) Generate(seed int64, settings *config.SimulationSettings) []workload.Generator {
+	return []workload.Generator{
+		workload.NewReplayGenerator(settings.StartTime, seed, tl.Trace.samples()),
+	}
+}
@@ -309,6 +309,15 @@ func RangesInfoWithReplicaCounts(
 		int64(MinKey), int64(keyspace), rangeSize)
 }
 
+// SpanConfigWithReplicationFactor returns the default span config, with the
+// number of replicas and voters set to the replication factor given.
+func SpanConfigWithReplicationFactor(replicationFactor int) roachpb.SpanConfig {
+	spanConfig := defaultSpanConfig
+	spanConfig.NumReplicas = int32(replicationFactor)
+	spanConfig.NumVoters = int32(replicationFactor)
+	return spanConfig
+}
+
 func RangesInfoEvenDistribution(
 	stores int, ranges int, keyspace int, replicationFactor int, rangeSize int64,
 ) RangesInfo {
@@ -61,6 +61,16 @@ import (
 //     regions having 3 zones. complex: 28 nodes, 3 regions with a skewed
 //     number of nodes per region.
 //
+//   - "load_trace" [interval=<duration>] [keys_per_range=<int>]
+//     Load the cluster, ranges and load to simulate from a trace of hot ranges
+//     reports, provided as input. Each report is a JSON encoded response of
+//     the _status/v2/hotranges endpoint, taken interval apart. The cluster
+//     has a node for each node in the trace and the ranges have their replicas
+//     and lease placed as first reported. During eval, the load of each report
+//     is replayed in turn over the ranges' simulated key span, sized
+//     keys_per_range keys. The mapping of ranges to key spans is printed. The
+//     default values are: interval=1m keys_per_range=1000.
+//
 //   - "gen_ranges" [ranges=<int>] [placement_skew=<bool>] [repl_factor=<int>]
 //     [keyspace=<int>] [range_bytes=<int>]
 //     Initialize the range generator parameters. On the next call to eval, the
@@ -158,7 +168,7 @@ func TestDataDriven(t *testing.T) {
 	dir := datapathutils.TestDataPath(t, "non_rand")
 	datadriven.Walk(t, dir, func(t *testing.T, path string) {
 		const defaultKeyspace = 10000
-		loadGen := gen.BasicLoad{}
+		var loadGen gen.LoadGen = gen.BasicLoad{}
 		var clusterGen gen.ClusterGen
 		var rangeGen gen.RangeGen = gen.BasicRanges{
 			BaseRanges: gen.BaseRanges{
@@ -187,13 +197,15 @@ func TestDataDriven(t *testing.T) {
 				scanIfExists(t, d, "min_key", &minKey)
 				scanIfExists(t, d, "max_key", &maxKey)
 
-				loadGen.SkewedAccess = accessSkew
-				loadGen.MinKey = minKey
-				loadGen.MaxKey = maxKey
-				loadGen.RWRatio = rwRatio
-				loadGen.Rate = rate
-				loadGen.MaxBlockSize = maxBlock
-				loadGen.MinBlockSize = minBlock
+				loadGen = gen.BasicLoad{
+					SkewedAccess: accessSkew,
+					MinKey:       minKey,
+					MaxKey:       maxKey,
+					RWRatio:      rwRatio,
+					Rate:         rate,
+					MaxBlockSize: maxBlock,
+					MinBlockSize: minBlock,
+				}
 				return ""
 			case "gen_ranges":
 				var ranges, replFactor, keyspace = 1, 3, defaultKeyspace
@@ -242,6 +254,20 @@ func TestDataDriven(t *testing.T) {
 				scanArg(t, d, "config", &config)
 				clusterGen = loadClusterInfo(config)
 				return ""
+			case "load_trace":
+				var interval = time.Minute
+				var keysPerRange int64
+				scanIfExists(t, d, "interval", &interval)
+				scanIfExists(t, d, "keys_per_range", &keysPerRange)
+				trace, err := gen.ReadHotRangesTrace(strings.NewReader(d.Input), interval)
+				require.NoError(t, err)
+				if keysPerRange > 0 {
+					trace.KeysPerRange = keysPerRange
+				}
+				clusterGen = gen.TraceCluster{Trace: trace}
+				rangeGen = gen.TraceRanges{Trace: trace}
+				loadGen = gen.TraceLoad{Trace: trace}
+				return trace.String()
 			case "add_node":
 				var delay time.Duration
 				var numStores = 1
@@ -0,0 +1,154 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package workload
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// ReplayRate is the rate of load recorded against the keys [MinKey, MaxKey),
+// e.g. against a single range of a real cluster.
+type ReplayRate struct {
+	MinKey, MaxKey      int64
+	ReadsPerSecond      float64
+	WritesPerSecond     float64
+	ReadBytesPerSecond  float64
+	WriteBytesPerSecond float64
+	CPUNanosPerSecond   float64
+}
+
+// ReplaySample is the load recorded in a trace, starting at Offset from the
+// beginning of the trace and lasting until the Offset of the next sample.
+type ReplaySample struct {
+	Offset time.Duration
+	Rates  []ReplayRate
+}
+
+// replayCarry is the fractional number of reads and writes owed to a replay
+// rate, which were not large enough to be generated on a previous tick.
+type replayCarry struct {
+	reads, writes float64
+}
+
+// ReplayGenerator generates operations which replay recorded load rates. Each
+// sample in the trace is replayed in turn, with the load of each rate spread
+// uniformly over its keys. The last sample is replayed indefinitely once the
+// trace is exhausted.
+type ReplayGenerator struct {
+	start     time.Time
+	lastRun   time.Time
+	rand      *rand.Rand
+	samples   []ReplaySample
+	sampleIdx int
+	carry     []replayCarry
+}
+
+// NewReplayGenerator returns a generator that replays the given samples of
+// recorded load, beginning at start.
+func NewReplayGenerator(start time.Time, seed int64, samples []ReplaySample) Generator {
+	return newReplayGenerator(start, seed, samples)
+}
+
+func newReplayGenerator(start time.Time, seed int64, samples []ReplaySample) *ReplayGenerator {
+	sorted := make([]ReplaySample, len(samples))
+	copy(sorted, samples)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Offset < sorted[j].Offset
+	})
+	return &ReplayGenerator{
+		start:     start,
+		lastRun:   start,
+		rand:      rand.New(rand.NewSource(seed)),
+		samples:   sorted,
+		sampleIdx: -1,
+	}
+}
+
+// sampleAt returns the index of the sample recorded at time t, or -1 if t is
+// before the first sample.
+func (rg *ReplayGenerator) sampleAt(t time.Time) int {
+	return sort.Search(len(rg.samples), func(i int) bool {
+		return rg.start.Add(rg.samples[i].Offset).After(t)
+	}) - 1
+}
+
+// Tick returns the load events up till time tick, from the last time the
+// workload generator was called. The load of the sample recorded at the last
+// call is used for the whole duration.
+func (rg *ReplayGenerator) Tick(maxTime time.Time) LoadBatch {
+	elapsed := maxTime.Sub(rg.lastRun).Seconds()
+	if elapsed <= 0 {
+		return LoadBatch{}
+	}
+	idx := rg.sampleAt(rg.lastRun)
+	rg.lastRun = maxTime
+	if idx < 0 {
+		return LoadBatch{}
+	}
+	if idx != rg.sampleIdx {
+		rg.sampleIdx = idx
+		rg.carry = make([]replayCarry, len(rg.samples[idx].Rates))
+	}
+
+	next := make(map[int64]LoadEvent)
+	for i, rate := range rg.samples[idx].Rates {
+		if rate.MaxKey <= rate.MinKey {
+			continue
+		}
+		// Accumulate the operations owed to the rate, generating only whole
+		// operations and carrying over the remainder. Otherwise, rates lower
+		// than the tick frequency would never generate any load.
+		carry := &rg.carry[i]
+		carry.reads += rate.ReadsPerSecond * elapsed
+		carry.writes += rate.WritesPerSecond * elapsed
+		reads, writes := int64(carry.reads), int64(carry.writes)
+		carry.reads -= float64(reads)
+		carry.writes -= float64(writes)
+
+		var readSize, writeSize, cpu int64
+		if rate.ReadsPerSecond > 0 {
+			readSize = int64(rate.ReadBytesPerSecond / rate.ReadsPerSecond)
+		}
+		if rate.WritesPerSecond > 0 {
+			writeSize = int64(rate.WriteBytesPerSecond / rate.WritesPerSecond)
+		}
+		if ops := rate.ReadsPerSecond + rate.WritesPerSecond; ops > 0 {
+			cpu = int64(rate.CPUNanosPerSecond / ops)
+		}
+
+		for read := int64(0); read < reads; read++ {
+			key := rg.rand.Int63n(rate.MaxKey-rate.MinKey) + rate.MinKey
+			event := next[key]
+			event.Reads++
+			event.ReadSize += readSize
+			event.RequestCPU += cpu
+			next[key] = event
+		}
+		for write := int64(0); write < writes; write++ {
+			key := rg.rand.Int63n(rate.MaxKey-rate.MinKey) + rate.MinKey
+			event := next[key]
+			event.Writes++
+			event.WriteSize += writeSize
+			event.RequestCPU += cpu
+			next[key] = event
+		}
+	}
+
+	ret := make(LoadBatch, 0, len(next))
+	for k, v := range next {
+		v.Key = k
+		ret = append(ret, v)
+	}
+	sort.Sort(ret)
+	return ret
+}
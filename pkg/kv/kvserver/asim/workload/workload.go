@@ -26,6 +26,10 @@ type LoadEvent struct {
 	WriteSize int64
 	Reads     int64
 	ReadSize  int64
+	// RequestCPU is the CPU time in nanoseconds spent serving the event. It is
+	// only populated by generators replaying recorded load, synthetic
+	// workloads leave it zero.
+	RequestCPU int64
 }
 
 // LoadBatch is a sorted list of load events.
@@ -162,3 +162,63 @@ func TestRandWorkloadGenerator(t *testing.T) {
 		require.Equal(t, math.Round(tc.readRatio*100), math.Round((float64(stats.reads)/float64(stats.reads+stats.writes))*100))
 	}
 }
+
+// TestReplayGenerator asserts that the replay generator generates the load
+// recorded in each sample, over the keys of each rate.
+func TestReplayGenerator(t *testing.T) {
+	start := time.Date(2022, 03, 21, 11, 0, 0, 0, time.UTC)
+	samples := []ReplaySample{
+		{
+			Offset: 10 * time.Second,
+			Rates:  []ReplayRate{{MinKey: 10, MaxKey: 20, ReadsPerSecond: 1}},
+		},
+		{
+			Offset: 0,
+			Rates: []ReplayRate{{
+				MinKey:              0,
+				MaxKey:              10,
+				ReadsPerSecond:      10,
+				WritesPerSecond:     0.5,
+				ReadBytesPerSecond:  100,
+				WriteBytesPerSecond: 50,
+				CPUNanosPerSecond:   21000,
+			}},
+		},
+	}
+	rg := newReplayGenerator(start, testingSeed, samples)
+	require.Empty(t, rg.Tick(start))
+
+	var first, second []LoadEvent
+	for i := 1; i <= 20; i++ {
+		ops := rg.Tick(start.Add(time.Duration(i) * time.Second))
+		require.True(t, sort.IsSorted(ops))
+		if i <= 10 {
+			first = append(first, ops...)
+		} else {
+			second = append(second, ops...)
+		}
+	}
+
+	var reads, writes, readSize, writeSize, cpu int64
+	for _, op := range first {
+		require.True(t, op.Key >= 0 && op.Key < 10)
+		reads += op.Reads
+		writes += op.Writes
+		readSize += op.ReadSize
+		writeSize += op.WriteSize
+		cpu += op.RequestCPU
+	}
+	require.Equal(t, int64(100), reads)
+	require.Equal(t, int64(5), writes)
+	require.Equal(t, int64(1000), readSize)
+	require.Equal(t, int64(500), writeSize)
+	require.Equal(t, int64(210000), cpu)
+
+	reads = 0
+	for _, op := range second {
+		require.True(t, op.Key >= 10 && op.Key < 20)
+		require.Zero(t, op.Writes)
+		reads += op.Reads
+	}
+	require.Equal(t, int64(10), reads)
+}
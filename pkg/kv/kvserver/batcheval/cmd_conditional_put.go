@@ -64,6 +64,7 @@ func ConditionalPut(
 		Stats:                          cArgs.Stats,
 		ReplayWriteTimestampProtection: h.AmbiguousReplayProtection,
 		MaxLockConflicts:               storage.MaxConflictsPerLockConflictError.Get(&cArgs.EvalCtx.ClusterSettings().SV),
+		RowExpiry:                      storage.NewRowExpiry(cArgs.EvalCtx.RowExpiry()),
 	}
 
 	var err error
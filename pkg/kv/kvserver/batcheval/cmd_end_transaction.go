@@ -216,6 +216,8 @@ func declareKeysEndTxn(
 
 // EndTxn either commits or aborts (rolls back) an extant transaction according
 // to the args.Commit parameter. Rolling back an already rolled-back txn is ok.
+// If args.Prepare is set, the transaction is instead moved to the PREPARED
+// state, from which a later EndTxn request can commit or abort it.
 // TODO(nvanbenschoten): rename this file to cmd_end_txn.go once some of andrei's
 // recent PRs have landed.
 func EndTxn(
@@ -226,7 +228,9 @@ func EndTxn(
 	ms := cArgs.Stats
 	reply := resp.(*kvpb.EndTxnResponse)
 
-	if err := VerifyTransaction(h, args, roachpb.PENDING, roachpb.STAGING, roachpb.ABORTED); err != nil {
+	if err := VerifyTransaction(
+		h, args, roachpb.PENDING, roachpb.STAGING, roachpb.PREPARED, roachpb.ABORTED,
+	); err != nil {
 		return result.Result{}, err
 	}
 	if args.Require1PC {
@@ -238,6 +242,9 @@ func EndTxn(
 	if args.Commit && args.Poison {
 		return result.Result{}, errors.AssertionFailedf("cannot poison during a committing EndTxn request")
 	}
+	if args.Prepare && (!args.Commit || args.IsParallelCommit()) {
+		return result.Result{}, errors.AssertionFailedf("cannot prepare during a rollback or a parallel commit")
+	}
 
 	key := keys.TransactionKey(h.Txn.Key, h.Txn.ID)
 
@@ -248,7 +255,17 @@ func EndTxn(
 	)
 	if err != nil {
 		return result.Result{}, err
-	} else if !recordAlreadyExisted {
+	}
+	// A request that finalizes a prepared transaction on behalf of its
+	// coordinator must find the transaction record in the PREPARED state (or
+	// already finalized). Otherwise, the transaction was never prepared and it
+	// is not up to this request to finalize it.
+	if h.Txn.Status == roachpb.PREPARED && (!recordAlreadyExisted ||
+		existingTxn.Status == roachpb.PENDING || existingTxn.Status == roachpb.STAGING) {
+		return result.Result{}, kvpb.NewTransactionStatusError(
+			kvpb.TransactionStatusError_REASON_UNKNOWN, "transaction is not prepared")
+	}
+	if !recordAlreadyExisted {
 		// No existing transaction record was found - create one by writing it
 		// below in updateFinalizedTxn.
 		reply.Txn = h.Txn.Clone()
@@ -335,6 +352,16 @@ func EndTxn(
 				reply.Txn.Status = roachpb.PENDING
 			}
 
+		case roachpb.PREPARED:
+			if h.Txn.Epoch != reply.Txn.Epoch {
+				return result.Result{}, errors.AssertionFailedf(
+					"programming error: epoch change of prepared txn: %d", h.Txn.Epoch)
+			}
+			if args.Prepare {
+				return result.Result{}, kvpb.NewTransactionStatusError(
+					kvpb.TransactionStatusError_REASON_UNKNOWN, "already prepared")
+			}
+
 		default:
 			return result.Result{}, errors.AssertionFailedf("bad txn status: %s", reply.Txn)
 		}
@@ -357,6 +384,9 @@ func EndTxn(
 			// Furthermore, checking the timestamp cache and increasing the commit
 			// timestamp at this point would be incorrect, because the transaction may
 			// have entered the implicit commit state.
+		case existingTxn.Status == roachpb.PREPARED:
+			// Don't check timestamp cache. A prepared transaction can not be pushed,
+			// and it must commit at the timestamp at which it was prepared.
 		default:
 			panic("unreachable")
 		}
@@ -391,6 +421,23 @@ func EndTxn(
 			return result.Result{}, nil
 		}
 
+		// If the transaction is being prepared, write the prepared transaction
+		// record and return without running commit triggers or resolving local
+		// locks. The locks are resolved once the transaction is committed or
+		// aborted by a later EndTxn request.
+		if args.Prepare {
+			if ct := args.InternalCommitTrigger; ct != nil {
+				err := errors.Errorf("cannot prepare transaction with a commit trigger: %+v", ct)
+				return result.Result{}, err
+			}
+
+			reply.Txn.Status = roachpb.PREPARED
+			if err := updatePreparedTxn(ctx, readWriter, ms, key, args, reply.Txn); err != nil {
+				return result.Result{}, err
+			}
+			return result.Result{}, nil
+		}
+
 		// Else, the transaction can be explicitly committed.
 		reply.Txn.Status = roachpb.COMMITTED
 	} else {
@@ -692,6 +739,23 @@ func updateStagingTxn(
 	return storage.MVCCPutProto(ctx, readWriter, key, hlc.Timestamp{}, &txnRecord, storage.MVCCWriteOptions{Stats: ms})
 }
 
+// updatePreparedTxn persists the PREPARED transaction record with updated
+// status (and possibly timestamp). The record retains the transaction's lock
+// spans, so that whoever finalizes the transaction later can resolve them.
+func updatePreparedTxn(
+	ctx context.Context,
+	readWriter storage.ReadWriter,
+	ms *enginepb.MVCCStats,
+	key []byte,
+	args *kvpb.EndTxnRequest,
+	txn *roachpb.Transaction,
+) error {
+	txn.LockSpans = args.LockSpans
+	txn.InFlightWrites = nil
+	txnRecord := txn.AsRecord()
+	return storage.MVCCPutProto(ctx, readWriter, key, hlc.Timestamp{}, &txnRecord, storage.MVCCWriteOptions{Stats: ms})
+}
+
 // updateFinalizedTxn persists the COMMITTED or ABORTED transaction record with
 // updated status (and possibly timestamp). If we've already resolved all locks
 // locally, we actually delete the record right away - no use in keeping it
@@ -82,6 +82,18 @@ func declareKeysGC(
 				hlc.MaxTimestamp)
 		}
 	}
+	// Removing the live version of an expired row is not covered by the
+	// correctness argument below: a concurrent writer could otherwise see the
+	// expired version and write on top of it while we remove it, or write a new
+	// version between our evaluation and its application, which would both
+	// corrupt the key's history and the live stats. We obtain write latches on
+	// the keys at the highest timestamp to serialize with writers, but not with
+	// readers, which already ignore expired versions.
+	if gcr.ExpiredLiveKeys {
+		for _, k := range gcr.Keys {
+			latchSpans.AddMVCC(spanset.SpanReadWrite, roachpb.Span{Key: k.Key}, hlc.MaxTimestamp)
+		}
+	}
 	// The RangeGCThresholdKey is only written to if the
 	// req.(*GCRequest).Threshold is set. However, we always declare an exclusive
 	// access over this key in order to serialize with other GC requests.
@@ -199,10 +211,17 @@ func GC(
 		}
 	}
 
-	// Garbage collect the specified keys by expiration timestamps.
+	// Garbage collect the specified keys by expiration timestamps. Rows which
+	// expired as of the GC threshold are collected even if they are live, but
+	// only if the request latched the keys for it (see declareKeysGC).
+	var rowExpiry *storage.RowExpiry
+	if args.ExpiredLiveKeys {
+		rowExpiry = storage.NewRowExpiry(cArgs.EvalCtx.RowExpiry())
+	}
 	for _, gcKeys := range [][]kvpb.GCRequest_GCKey{localKeys, globalKeys} {
-		if err := storage.MVCCGarbageCollect(
+		if err := storage.MVCCGarbageCollectWithRowExpiry(
 			ctx, readWriter, cArgs.Stats, gcKeys, h.Timestamp,
+			rowExpiry, cArgs.EvalCtx.GetGCThreshold(),
 		); err != nil {
 			return result.Result{}, err
 		}
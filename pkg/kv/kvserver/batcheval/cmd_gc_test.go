@@ -0,0 +1,64 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package batcheval
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/lockspanset"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDeclareKeysGCExpiredLiveKeys verifies that GC requests which may remove
+// the live versions of expired rows latch the keys they GC against writers.
+func TestDeclareKeysGCExpiredLiveKeys(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	desc := roachpb.RangeDescriptor{
+		RangeID:  99,
+		StartKey: roachpb.RKey("a"),
+		EndKey:   roachpb.RKey("z"),
+	}
+	gcKeys := []kvpb.GCRequest_GCKey{
+		{Key: roachpb.Key("b"), Timestamp: hlc.Timestamp{WallTime: 10}},
+		{Key: roachpb.Key("d"), Timestamp: hlc.Timestamp{WallTime: 10}},
+	}
+
+	declare := func(expiredLiveKeys bool) []spanset.Span {
+		req := &kvpb.GCRequest{
+			RequestHeader:   kvpb.RequestHeader{Key: desc.StartKey.AsRawKey(), EndKey: desc.EndKey.AsRawKey()},
+			Keys:            gcKeys,
+			ExpiredLiveKeys: expiredLiveKeys,
+		}
+		var latchSpans spanset.SpanSet
+		var lockSpans lockspanset.LockSpanSet
+		require.NoError(t, declareKeysGC(&desc, &kvpb.Header{}, req, &latchSpans, &lockSpans, 0))
+		return latchSpans.GetSpans(spanset.SpanReadWrite, spanset.SpanGlobal)
+	}
+
+	t.Run("without expired live keys", func(t *testing.T) {
+		require.Empty(t, declare(false))
+	})
+
+	t.Run("with expired live keys", func(t *testing.T) {
+		require.Equal(t, []spanset.Span{
+			{Span: roachpb.Span{Key: roachpb.Key("b")}, Timestamp: hlc.MaxTimestamp},
+			{Span: roachpb.Span{Key: roachpb.Key("d")}, Timestamp: hlc.MaxTimestamp},
+		}, declare(true))
+	})
+}
@@ -42,6 +42,7 @@ func InitPut(
 		Stats:                          cArgs.Stats,
 		ReplayWriteTimestampProtection: h.AmbiguousReplayProtection,
 		MaxLockConflicts:               storage.MaxConflictsPerLockConflictError.Get(&cArgs.EvalCtx.ClusterSettings().SV),
+		RowExpiry:                      storage.NewRowExpiry(cArgs.EvalCtx.RowExpiry()),
 	}
 
 	var err error
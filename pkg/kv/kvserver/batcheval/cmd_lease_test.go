@@ -213,6 +213,10 @@ func TestCheckCanReceiveLease(t *testing.T) {
 
 		{leaseholderType: roachpb.LEARNER, anotherReplicaType: none, expIfWasLastLeaseholderTrue: false, expIfWasLastLeaseholderFalse: false},
 		{leaseholderType: roachpb.NON_VOTER, anotherReplicaType: none, expIfWasLastLeaseholderTrue: false, expIfWasLastLeaseholderFalse: false},
+
+		// A WITNESS never gets the lease, since it doesn't hold the range's data.
+		{leaseholderType: roachpb.WITNESS, anotherReplicaType: none, expIfWasLastLeaseholderTrue: false, expIfWasLastLeaseholderFalse: false},
+		{leaseholderType: roachpb.WITNESS, anotherReplicaType: roachpb.VOTER_INCOMING, expIfWasLastLeaseholderTrue: false, expIfWasLastLeaseholderFalse: false},
 	} {
 		t.Run(tc.leaseholderType.String(), func(t *testing.T) {
 			repDesc := roachpb.ReplicaDescriptor{
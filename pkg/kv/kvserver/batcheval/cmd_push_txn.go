@@ -97,6 +97,10 @@ func declareKeysPushTransaction(
 //
 // Push cannot proceed: a TransactionPushError is returned.
 //
+// Push cannot proceed: a PREPARED pushee can not be pushed, regardless of the
+// pusher's priority or the pushee's heartbeat. A TransactionPushError is
+// returned.
+//
 // Push can proceed but txn record staging: if the transaction record
 // is STAGING then it can't be changed by a pusher without going through
 // the transaction recovery process. An IndeterminateCommitError is returned
@@ -221,6 +225,12 @@ func PushTxn(
 	var pusherWins bool
 	var reason string
 	switch {
+	case pusheeStatus == roachpb.PREPARED:
+		// If the pushee is prepared, it can only be committed or aborted by an
+		// explicit EndTxn request. The pusher always fails, no matter its
+		// priority, and must wait for the prepared transaction to be finalized.
+		reason = "pushee is prepared"
+		pusherWins = false
 	case txnwait.IsExpired(cArgs.EvalCtx.Clock().Now(), &reply.PusheeTxn):
 		reason = "pushee is expired"
 		// When cleaning up, actually clean up (as opposed to simply pushing
@@ -70,6 +70,14 @@ func Refresh(
 			return result.Result{},
 				kvpb.NewRefreshFailedError(ctx, kvpb.RefreshFailedError_REASON_COMMITTED_VALUE, args.Key, ts)
 		}
+		// A row which expired in the refresh interval was deleted as of its
+		// expiration time, even though no new version was written.
+		rowExpiry := storage.NewRowExpiry(cArgs.EvalCtx.RowExpiry())
+		if exp, ok := rowExpiry.Expiration(args.Key, res.Value.RawBytes); ok &&
+			refreshFrom.Less(exp) && exp.LessEq(refreshTo) {
+			return result.Result{},
+				kvpb.NewRefreshFailedError(ctx, kvpb.RefreshFailedError_REASON_COMMITTED_VALUE, args.Key, exp)
+		}
 	}
 
 	// Check if an intent which is not owned by this transaction was written
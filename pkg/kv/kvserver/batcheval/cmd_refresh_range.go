@@ -59,7 +59,8 @@ func RefreshRange(
 	}
 
 	log.VEventf(ctx, 2, "refresh %s @[%s-%s]", args.Span(), refreshFrom, refreshTo)
-	return result.Result{}, refreshRange(ctx, reader, args.Span(), refreshFrom, refreshTo, h.Txn.ID, h.WaitPolicy)
+	rowExpiry := storage.NewRowExpiry(cArgs.EvalCtx.RowExpiry())
+	return result.Result{}, refreshRange(ctx, reader, args.Span(), refreshFrom, refreshTo, h.Txn.ID, h.WaitPolicy, rowExpiry)
 }
 
 // refreshRange iterates over the specified key span until it discovers a value
@@ -68,6 +69,10 @@ func RefreshRange(
 // as conflicts during a refresh. The iteration also observes intents, and any
 // intent that is not owned by the specified txn ID is considered a conflict.
 //
+// If rowExpiry is set, a row which expired after the refreshFrom timestamp but
+// before or at the refreshTo timestamp is considered a conflict as well, since
+// it was deleted by its expiry.
+//
 // If such a conflict is found, the function returns an error. Otherwise, no
 // error is returned.
 func refreshRange(
@@ -77,6 +82,7 @@ func refreshRange(
 	refreshFrom, refreshTo hlc.Timestamp,
 	txnID uuid.UUID,
 	wp lock.WaitPolicy,
+	rowExpiry *storage.RowExpiry,
 ) error {
 	// Construct an incremental iterator with the desired time bounds. Incremental
 	// iterators will emit MVCC tombstones by default and will emit intents when
@@ -152,5 +158,21 @@ func refreshRange(
 		// If a committed value is found, return an error.
 		return kvpb.NewRefreshFailedError(ctx, kvpb.RefreshFailedError_REASON_COMMITTED_VALUE, key.Key, key.Timestamp)
 	}
-	return nil
+	if rowExpiry == nil {
+		return nil
+	}
+
+	// No values were written in the refresh interval, so the values visible at
+	// refreshFrom are still the latest ones. Check whether any of them expired
+	// in the interval.
+	_, err = storage.MVCCIterate(ctx, reader, span.Key, span.EndKey, refreshFrom,
+		storage.MVCCScanOptions{Inconsistent: true},
+		func(kv roachpb.KeyValue) error {
+			if exp, ok := rowExpiry.Expiration(kv.Key, kv.Value.RawBytes); ok &&
+				refreshFrom.Less(exp) && exp.LessEq(refreshTo) {
+				return kvpb.NewRefreshFailedError(ctx, kvpb.RefreshFailedError_REASON_COMMITTED_VALUE, kv.Key, exp)
+			}
+			return nil
+		})
+	return err
 }
@@ -14,15 +14,19 @@ import (
 	"context"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/stretchr/testify/require"
 )
@@ -95,6 +99,7 @@ func TestRefreshError(t *testing.T) {
 			// time 2, therefore the refresh should fail.
 			var resp kvpb.RefreshResponse
 			_, err := Refresh(ctx, db, CommandArgs{
+				EvalCtx: (&MockEvalCtx{}).EvalContext(),
 				Args: &kvpb.RefreshRequest{
 					RequestHeader: kvpb.RequestHeader{
 						Key: k,
@@ -152,6 +157,7 @@ func TestRefreshTimestampBounds(t *testing.T) {
 	} {
 		var resp kvpb.RefreshResponse
 		_, err := Refresh(ctx, db, CommandArgs{
+			EvalCtx: (&MockEvalCtx{}).EvalContext(),
 			Args: &kvpb.RefreshRequest{
 				RequestHeader: kvpb.RequestHeader{
 					Key: k,
@@ -177,3 +183,79 @@ func TestRefreshTimestampBounds(t *testing.T) {
 		}
 	}
 }
+
+// TestRefreshRowExpiry verifies that Refresh and RefreshRange requests fail if
+// a row expired in the refresh interval, since the row was deleted by its
+// expiry.
+func TestRefreshRowExpiry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	db := storage.NewDefaultInMemForTesting()
+	defer db.Close()
+
+	ts1 := hlc.Timestamp{WallTime: 1}
+	ts2 := hlc.Timestamp{WallTime: 2}
+	ts3 := hlc.Timestamp{WallTime: 3}
+	ts4 := hlc.Timestamp{WallTime: 4}
+
+	// Write a row at ts1 which expires at ts3. The row's only column family
+	// consists of its expiration column.
+	k := encoding.EncodeVarintAscending(keys.SystemSQLCodec.IndexPrefix(100, 1), 1)
+	k = keys.MakeFamilyKey(k, 0)
+	var v roachpb.Value
+	v.SetTime(timeutil.Unix(0, ts3.WallTime))
+	require.NoError(t, storage.MVCCPut(ctx, db, k, ts1, v, storage.MVCCWriteOptions{}))
+
+	evalCtx := (&MockEvalCtx{
+		ClusterSettings: cluster.MakeTestingClusterSettings(),
+		RowExpiry:       &roachpb.RowExpiry{TableID: 100, IndexID: 1, FamilyID: 0, ColumnID: 1},
+	}).EvalContext()
+	for _, tc := range []struct {
+		from, to hlc.Timestamp
+		expErr   bool
+	}{
+		// The row expires in the refresh interval.
+		{ts1, ts3, true},
+		{ts2, ts4, true},
+		// The row expires after the refresh interval.
+		{ts1, ts2, false},
+		// The row had already expired as of RefreshFrom.
+		{ts3, ts4, false},
+	} {
+		header := kvpb.Header{
+			Txn: &roachpb.Transaction{
+				TxnMeta: enginepb.TxnMeta{
+					WriteTimestamp: tc.to,
+				},
+				ReadTimestamp: tc.to,
+			},
+			Timestamp: tc.to,
+		}
+		_, err := Refresh(ctx, db, CommandArgs{
+			EvalCtx: evalCtx,
+			Args: &kvpb.RefreshRequest{
+				RequestHeader: kvpb.RequestHeader{Key: k},
+				RefreshFrom:   tc.from,
+			},
+			Header: header,
+		}, &kvpb.RefreshResponse{})
+		_, rangeErr := RefreshRange(ctx, db, CommandArgs{
+			EvalCtx: evalCtx,
+			Args: &kvpb.RefreshRangeRequest{
+				RequestHeader: kvpb.RequestHeader{Key: k, EndKey: k.PrefixEnd()},
+				RefreshFrom:   tc.from,
+			},
+			Header: header,
+		}, &kvpb.RefreshRangeResponse{})
+
+		if tc.expErr {
+			require.Regexp(t, "encountered recently written committed value", err)
+			require.Regexp(t, "encountered recently written committed value", rangeErr)
+		} else {
+			require.NoError(t, err)
+			require.NoError(t, rangeErr)
+		}
+	}
+}
@@ -55,6 +55,7 @@ func Scan(
 		MemoryAccount:         cArgs.EvalCtx.GetResponseMemoryAccount(),
 		LockTable:             cArgs.Concurrency,
 		DontInterleaveIntents: cArgs.DontInterleaveIntents,
+		RowExpiry:             storage.NewRowExpiry(cArgs.EvalCtx.RowExpiry()),
 	}
 
 	switch args.ScanFormat {
@@ -104,6 +104,9 @@ type EvalContext interface {
 
 	GetGCThreshold() hlc.Timestamp
 	ExcludeDataFromBackup() bool
+	// RowExpiry returns the storage level row expiry of the range's span
+	// config, or nil if the range's rows don't expire at the storage level.
+	RowExpiry() *roachpb.RowExpiry
 	GetLastReplicaGCTimestamp(context.Context) (hlc.Timestamp, error)
 	GetLease() (roachpb.Lease, roachpb.Lease)
 	GetRangeInfo(context.Context) roachpb.RangeInfo
@@ -182,6 +185,7 @@ type MockEvalCtx struct {
 	RevokedLeaseSeq      roachpb.LeaseSequence
 	MaxBytes             int64
 	ApproxDiskBytes      uint64
+	RowExpiry            *roachpb.RowExpiry
 	EvalKnobs            kvserverbase.BatchEvalTestingKnobs
 }
 
@@ -276,6 +280,9 @@ func (m *mockEvalCtxImpl) GetGCThreshold() hlc.Timestamp {
 func (m *mockEvalCtxImpl) ExcludeDataFromBackup() bool {
 	return false
 }
+func (m *mockEvalCtxImpl) RowExpiry() *roachpb.RowExpiry {
+	return m.MockEvalCtx.RowExpiry
+}
 func (m *mockEvalCtxImpl) GetLastReplicaGCTimestamp(context.Context) (hlc.Timestamp, error) {
 	panic("unimplemented")
 }
@@ -32,6 +32,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/txnwait"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/keyside"
@@ -488,6 +489,106 @@ func TestClosedTimestampCantServeBasedOnUncertaintyLimit(t *testing.T) {
 	verifyNotLeaseHolderErrors(t, baRead, repls, 2)
 }
 
+// TestClosedTimestampFollowerReadsMaxWait verifies that a follower waits for up
+// to kv.closed_timestamp.follower_reads.max_wait for its closed timestamp to
+// reach the timestamp of a read, and redirects the read to the leaseholder if
+// it doesn't get there in time. Reads that the closed timestamp can't catch up
+// with in time are redirected without waiting.
+func TestClosedTimestampFollowerReadsMaxWait(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	// Limiting how long transactions can run does not work
+	// well with race unless we're extremely lenient, which
+	// drives up the test duration.
+	skip.UnderRace(t)
+
+	ctx := context.Background()
+	cArgs := aggressiveResolvedTimestampManuallyReplicatedClusterArgs
+	tc, db0, desc := setupClusterForClosedTSTesting(ctx, t, testingTargetDuration, 0, cArgs, "cttest", "kv")
+	defer tc.Stopper().Stop(ctx)
+	sqlRunner := sqlutils.MakeSQLRunner(db0)
+	repls := replsForRange(ctx, t, tc, desc)
+
+	if _, err := db0.Exec(`INSERT INTO cttest.kv VALUES(1, $1)`, "foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	lh := getCurrentLeaseholder(t, tc, desc)
+	var follower *kvserver.Replica
+	for _, repl := range repls {
+		if repl.StoreID() != lh.StoreID {
+			follower = repl
+			break
+		}
+	}
+	require.NotNil(t, follower)
+
+	setDuration := func(t *testing.T, setting *settings.DurationSetting, d time.Duration) {
+		sqlRunner.Exec(t, fmt.Sprintf(`SET CLUSTER SETTING %s = '%s'`, setting.Name(), d))
+		testutils.SucceedsSoon(t, func() error {
+			for i := 0; i < tc.NumServers(); i++ {
+				if cur := setting.Get(&tc.Server(i).ClusterSettings().SV); cur != d {
+					return errors.Errorf("n%d: %s is %s, not %s", i+1, setting.Name(), cur, d)
+				}
+			}
+			return nil
+		})
+	}
+
+	t.Run("closed timestamp catches up", func(t *testing.T) {
+		setDuration(t, kvserver.FollowerReadsMaxWait, testutils.DefaultSucceedsSoonDuration)
+
+		// The closed timestamp lags the present time by the target duration, so
+		// the follower has to wait for it before serving the read.
+		ts := tc.Server(0).Clock().Now()
+		require.True(t, follower.GetCurrentClosedTimestamp(ctx).Less(ts))
+		resp, pErr := follower.Send(ctx, makeTxnReadBatchForDesc(desc, ts))
+		require.NoError(t, pErr.GoError())
+		require.Len(t, resp.Responses[0].GetInner().(*kvpb.ScanResponse).Rows, 1)
+		require.True(t, ts.LessEq(follower.GetCurrentClosedTimestamp(ctx)))
+	})
+
+	// With a very long target duration, the closed timestamp doesn't advance
+	// anymore.
+	setDuration(t, closedts.TargetDuration, time.Hour)
+	const maxWait = time.Second
+	setDuration(t, kvserver.FollowerReadsMaxWait, maxWait)
+	closed := follower.GetCurrentClosedTimestamp(ctx)
+	// waitForClock waits for the clock to pass the given timestamp, so that
+	// reads at it are not in the future.
+	waitForClock := func(t *testing.T, ts hlc.Timestamp) {
+		testutils.SucceedsSoon(t, func() error {
+			if now := tc.Server(0).Clock().Now(); now.LessEq(ts) {
+				return errors.Errorf("clock at %s, waiting for %s", now, ts)
+			}
+			return nil
+		})
+	}
+
+	t.Run("wait times out", func(t *testing.T) {
+		// The closed timestamp trails the read by less than max_wait, so the
+		// follower waits for it, and gives up after max_wait.
+		ts := closed.Add((maxWait / 2).Nanoseconds(), 0)
+		waitForClock(t, ts)
+		start := timeutil.Now()
+		_, pErr := follower.Send(ctx, makeTxnReadBatchForDesc(desc, ts))
+		require.IsType(t, &kvpb.NotLeaseHolderError{}, pErr.GetDetail())
+		require.GreaterOrEqual(t, timeutil.Since(start), maxWait)
+		require.True(t, follower.GetCurrentClosedTimestamp(ctx).Less(ts))
+	})
+
+	t.Run("closed timestamp too far behind", func(t *testing.T) {
+		// The closed timestamp can't catch up with the read within max_wait, so
+		// the follower redirects it right away.
+		waitForClock(t, closed.Add((2*maxWait).Nanoseconds(), 0))
+		ts := tc.Server(0).Clock().Now()
+		start := timeutil.Now()
+		_, pErr := follower.Send(ctx, makeTxnReadBatchForDesc(desc, ts))
+		require.IsType(t, &kvpb.NotLeaseHolderError{}, pErr.GetDetail())
+		require.Less(t, timeutil.Since(start), maxWait)
+	})
+}
+
 func TestClosedTimestampCanServeForWritingTransaction(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
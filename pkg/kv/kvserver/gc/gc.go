@@ -305,6 +305,11 @@ type RunOptions struct {
 	// to issuing point delete requests for the oldest batch to free up memory
 	// before resuming further iteration.
 	MaxPendingKeysSize int64
+	// RowExpiry, if set, determines which rows of the range have expired. The
+	// latest version of an expired row is treated as a deletion tombstone, so it
+	// is removed along with its history once it has expired as of the new GC
+	// threshold.
+	RowExpiry *storage.RowExpiry
 }
 
 // CleanupIntentsFunc synchronously resolves the supplied intents
@@ -366,7 +371,7 @@ func Run(
 		return Info{}, err
 	}
 	fastPath, err := processReplicatedKeyRange(ctx, desc, snap, newThreshold,
-		populateBatcherOptions(options), gcer, &info)
+		options.RowExpiry, populateBatcherOptions(options), gcer, &info)
 	if err != nil {
 		if errors.Is(err, pebble.ErrSnapshotExcised) {
 			err = benignerror.NewStoreBenign(err)
@@ -435,6 +440,7 @@ func processReplicatedKeyRange(
 	desc *roachpb.RangeDescriptor,
 	snap storage.Reader,
 	threshold hlc.Timestamp,
+	rowExpiry *storage.RowExpiry,
 	batcherThresholds gcKeyBatcherThresholds,
 	gcer PureGCer,
 	info *Info,
@@ -476,7 +482,7 @@ func processReplicatedKeyRange(
 		// It could also request the main loop to rewind to a previous point to
 		// retry (this is needed when attempt to collect a clear range batch fails
 		// in the middle of key versions).
-		it := makeGCIterator(iterator, threshold)
+		it := makeGCIterator(iterator, threshold, rowExpiry)
 
 		b := gcKeyBatcher{
 			gcKeyBatcherThresholds: batcherThresholds,
@@ -1233,6 +1239,10 @@ func processLocalKeyRange(
 			info.TransactionSpanGCPending++
 		case roachpb.STAGING:
 			info.TransactionSpanGCStaging++
+		case roachpb.PREPARED:
+			// A prepared transaction can only be finalized by an explicit
+			// request, so its record must be retained regardless of its age.
+			return nil
 		case roachpb.ABORTED:
 			info.TransactionSpanGCAborted++
 		case roachpb.COMMITTED:
@@ -22,6 +22,7 @@ import (
 type gcIterator struct {
 	it        storage.MVCCIterator
 	threshold hlc.Timestamp
+	rowExpiry *storage.RowExpiry
 	err       error
 	buf       gcIteratorRingBuf
 
@@ -34,10 +35,13 @@ type gcIterator struct {
 // TODO(sumeer): change gcIterator to use MVCCValueLenAndIsTombstone(). It
 // needs to get the value only for intents.
 
-func makeGCIterator(iter storage.MVCCIterator, threshold hlc.Timestamp) gcIterator {
+func makeGCIterator(
+	iter storage.MVCCIterator, threshold hlc.Timestamp, rowExpiry *storage.RowExpiry,
+) gcIterator {
 	return gcIterator{
 		it:        iter,
 		threshold: threshold,
+		rowExpiry: rowExpiry,
 	}
 }
 
@@ -152,6 +156,16 @@ func (it *gcIterator) fillTo(targetLen int) (ok bool) {
 					it.err = err
 					return false
 				}
+				if it.rowExpiry != nil && !mvccValueIsTombstone {
+					// Values which expired as of the threshold are collected as if
+					// they were deletion tombstones.
+					v, err := storage.DecodeMVCCValueAndErr(it.it.UnsafeValue())
+					if err != nil {
+						it.err = err
+						return false
+					}
+					mvccValueIsTombstone = it.rowExpiry.Expired(key.Key, v.Value.RawBytes, it.threshold)
+				}
 			} else {
 				var err error
 				metaValue, err = it.it.UnsafeValue()
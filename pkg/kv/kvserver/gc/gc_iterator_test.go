@@ -173,7 +173,7 @@ func TestGCIterator(t *testing.T) {
 			}
 			mvccIt.SeekLT(storage.MVCCKey{Key: desc.EndKey.AsRawKey()})
 			defer mvccIt.Close()
-			it := makeGCIterator(mvccIt, tc.gcThreshold)
+			it := makeGCIterator(mvccIt, tc.gcThreshold, nil /* rowExpiry */)
 			expectations := tc.expectations
 			for i, ex := range expectations {
 				s, ok := it.state()
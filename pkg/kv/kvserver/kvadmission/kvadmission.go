@@ -229,6 +229,7 @@ var _ Controller = &controllerImpl{}
 // cooperative scheduling with elastic CPU granters).
 type Handle struct {
 	tenantID             roachpb.TenantID
+	resourceGroup        string
 	storeAdmissionQ      *admission.StoreWorkQueue
 	storeWorkHandle      admission.StoreWorkHandle
 	elasticCPUWorkHandle *admission.ElasticCPUWorkHandle
@@ -280,7 +281,7 @@ func MakeController(
 func (n *controllerImpl) AdmitKVWork(
 	ctx context.Context, tenantID roachpb.TenantID, ba *kvpb.BatchRequest,
 ) (handle Handle, retErr error) {
-	ah := Handle{tenantID: tenantID}
+	ah := Handle{tenantID: tenantID, resourceGroup: ba.AdmissionHeader.ResourceGroup.Name}
 	if n.kvAdmissionQ == nil {
 		return ah, nil
 	}
@@ -312,6 +313,7 @@ func (n *controllerImpl) AdmitKVWork(
 		Priority:        admissionpb.WorkPriority(ba.AdmissionHeader.Priority),
 		CreateTime:      createTime,
 		BypassAdmission: bypassAdmission,
+		ResourceGroup:   ba.AdmissionHeader.ResourceGroup,
 	}
 
 	admissionEnabled := true
@@ -451,7 +453,7 @@ func (n *controllerImpl) AdmittedKVWorkDone(ah Handle, writeBytes *StoreWriteByt
 			// TODO(sumeer): remove this hack when that bug is fixed.
 			cpuTime = 1
 		}
-		n.kvAdmissionQ.AdmittedWorkDone(ah.tenantID, cpuTime)
+		n.kvAdmissionQ.AdmittedWorkDone(ah.tenantID, ah.resourceGroup, cpuTime)
 	}
 	if ah.storeAdmissionQ != nil {
 		var doneInfo admission.StoreWorkDoneInfo
@@ -486,6 +488,7 @@ func (n *controllerImpl) AdmitRangefeedRequest(
 			Priority:        admissionpb.WorkPriority(request.AdmissionHeader.Priority),
 			CreateTime:      request.AdmissionHeader.CreateTime,
 			BypassAdmission: false,
+			ResourceGroup:   request.AdmissionHeader.ResourceGroup,
 		})
 }
 
@@ -312,10 +312,11 @@ func (mq *mergeQueue) process(
 	}
 	leftRepls, rightRepls := lhsDesc.Replicas().Descriptors(), rhsDesc.Replicas().Descriptors()
 
-	// Defensive sanity check that the ranges involved only have either VOTER_FULL
-	// and NON_VOTER replicas.
+	// Defensive sanity check that the ranges involved only have either VOTER_FULL,
+	// NON_VOTER and WITNESS replicas.
 	for i := range leftRepls {
-		if typ := leftRepls[i].Type; !(typ == roachpb.VOTER_FULL || typ == roachpb.NON_VOTER) {
+		if typ := leftRepls[i].Type; !(typ == roachpb.VOTER_FULL || typ == roachpb.NON_VOTER ||
+			typ == roachpb.WITNESS) {
 			return false,
 				errors.AssertionFailedf(
 					`cannot merge because lhs is either in a joint state or has learner replicas: %v`,
@@ -324,12 +325,23 @@ func (mq *mergeQueue) process(
 		}
 	}
 
+	// AdminRelocateRange doesn't place witnesses, so it can't collocate ranges
+	// whose witnesses are on different stores (see replicasCollocated). Leave
+	// those to the replicate queue rather than relocating the RHS.
+	if len(lhsDesc.Replicas().WitnessDescriptors())+len(rhsDesc.Replicas().WitnessDescriptors()) > 0 &&
+		!replicasCollocated(leftRepls, rightRepls) {
+		log.VEventf(ctx, 2, "skipping merge: witnesses of %s and %s are not collocated",
+			lhsDesc, rhsDesc)
+		return false, nil
+	}
+
 	// Range merges require that the set of stores that contain a replica for the
 	// RHS range be equal to the set of stores that contain a replica for the LHS
 	// range. The LHS and RHS ranges' leaseholders do not need to be co-located
-	// and types of the replicas (voting or non-voting) do not matter. Even if
-	// replicas are collocated, the RHS might still be in a joint config, and
-	// calling AdminRelocateRange will fix this.
+	// and types of the replicas (voting or non-voting) do not matter, except
+	// that witnesses must be placed identically. Even if replicas are
+	// collocated, the RHS might still be in a joint config, and calling
+	// AdminRelocateRange will fix this.
 	if !replicasCollocated(leftRepls, rightRepls) ||
 		rhsDesc.Replicas().InAtomicReplicationChange() {
 		// TODO(aayush): We enable merges to proceed even when LHS and/or RHS are in
@@ -376,7 +388,8 @@ func (mq *mergeQueue) process(
 		rightRepls = rhsDesc.Replicas().Descriptors()
 	}
 	for i := range rightRepls {
-		if typ := rightRepls[i].Type; !(typ == roachpb.VOTER_FULL || typ == roachpb.NON_VOTER) {
+		if typ := rightRepls[i].Type; !(typ == roachpb.VOTER_FULL || typ == roachpb.NON_VOTER ||
+			typ == roachpb.WITNESS) {
 			log.Infof(ctx, "RHS Type: %s", typ)
 			return false,
 				errors.AssertionFailedf(
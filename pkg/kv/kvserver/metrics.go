@@ -337,6 +337,13 @@ var (
 		Unit:        metric.Unit_BYTES,
 	}
 
+	metaRemote = metric.Metadata{
+		Name:        "capacity.remote",
+		Help:        "Storage used in remote object storage, not included in capacity.used",
+		Measurement: "Storage",
+		Unit:        metric.Unit_BYTES,
+	}
+
 	metaReserved = metric.Metadata{
 		Name:        "capacity.reserved",
 		Help:        "Capacity reserved for snapshots",
@@ -2316,6 +2323,7 @@ type StoreMetrics struct {
 	Capacity           *metric.Gauge
 	Available          *metric.Gauge
 	Used               *metric.Gauge
+	Remote             *metric.Gauge
 	Reserved           *metric.Gauge
 
 	// Rebalancing metrics.
@@ -2994,6 +3002,7 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		Capacity:  metric.NewGauge(metaCapacity),
 		Available: metric.NewGauge(metaAvailable),
 		Used:      metric.NewGauge(metaUsed),
+		Remote:    metric.NewGauge(metaRemote),
 		Reserved:  metric.NewGauge(metaReserved),
 
 		// Rebalancing metrics.
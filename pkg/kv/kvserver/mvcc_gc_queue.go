@@ -70,6 +70,11 @@ const (
 	// prevent continually spinning on intents that belong to active transactions,
 	// which can't be cleaned up.
 	mvccGCQueueIntentCooldownDuration = 2 * time.Hour
+	// mvccGCQueueRowExpiryCooldownDuration is the duration to wait between MVCC
+	// GC attempts of the same range when triggered solely by the range having a
+	// storage level row expiry. Expired rows are accounted for as live data, so
+	// they don't contribute to the MVCC score.
+	mvccGCQueueRowExpiryCooldownDuration = 2 * time.Hour
 	// intentAgeNormalization is the average age of outstanding intents
 	// which amount to a score of "1" added to total replica priority.
 	intentAgeNormalization = 8 * time.Hour
@@ -286,6 +291,11 @@ func (mgcq *mvccGCQueue) shouldQueue(
 
 	r := makeMVCCGCQueueScore(ctx, repl, gcTimestamp, lastGC, conf.TTL(), canAdvanceGCThreshold)
 	log.VEventf(ctx, 2, "shouldQueue=%t: %s", r.ShouldQueue, r)
+	if !r.ShouldQueue && conf.RowExpiry != nil && canAdvanceGCThreshold &&
+		(r.LastGC == 0 || r.LastGC >= mvccGCQueueRowExpiryCooldownDuration) {
+		log.VEventf(ctx, 2, "shouldQueue=true: range has storage level row expiry")
+		return true, r.FinalScore
+	}
 	return r.ShouldQueue, r.FinalScore
 }
 
@@ -581,6 +591,9 @@ type replicaGCer struct {
 	count               int32 // update atomically
 	admissionController kvadmission.Controller
 	storeID             roachpb.StoreID
+	// expiredLiveKeys is set if the range has a row expiry, in which case the
+	// GC'd keys may include the live versions of expired rows.
+	expiredLiveKeys bool
 }
 
 var _ gc.GCer = &replicaGCer{}
@@ -590,6 +603,7 @@ func (r *replicaGCer) template() kvpb.GCRequest {
 	var template kvpb.GCRequest
 	template.Key = desc.StartKey.AsRawKey()
 	template.EndKey = desc.EndKey.AsRawKey()
+	template.ExpiredLiveKeys = r.expiredLiveKeys
 
 	return template
 }
@@ -742,6 +756,11 @@ func (mgcq *mvccGCQueue) process(
 	if repl.store.ClusterSettings().Version.IsActive(ctx, clusterversion.V23_1) {
 		clearRangeMinKeys = gc.ClearRangeMinKeys.Get(&repl.store.ClusterSettings().SV)
 	}
+	if conf.RowExpiry != nil {
+		// Clear range GC only removes versions shadowed by deletions, which
+		// doesn't include expired rows.
+		clearRangeMinKeys = 0
+	}
 
 	info, err := gc.Run(ctx, desc, snap, gcTimestamp, newThreshold,
 		gc.RunOptions{
@@ -752,12 +771,14 @@ func (mgcq *mvccGCQueue) process(
 			MaxTxnsPerIntentCleanupBatch:           intentresolver.MaxTxnsPerIntentCleanupBatch,
 			IntentCleanupBatchTimeout:              mvccGCQueueIntentBatchTimeout,
 			ClearRangeMinKeys:                      clearRangeMinKeys,
+			RowExpiry:                              storage.NewRowExpiry(conf.RowExpiry),
 		},
 		conf.TTL(),
 		&replicaGCer{
 			repl:                repl,
 			admissionController: mgcq.store.cfg.KVAdmissionController,
 			storeID:             mgcq.store.StoreID(),
+			expiredLiveKeys:     conf.RowExpiry != nil,
 		},
 		func(ctx context.Context, intents []roachpb.Intent) error {
 			intentCount, err := repl.store.intentResolver.CleanupIntents(
@@ -0,0 +1,340 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rangefeed
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// The limits below bound the cost of evaluating a ColumnFilter, which is
+// provided by a (possibly untrusted) tenant and evaluated on the server for
+// every event of the registration.
+const (
+	maxColumnFilterPredicates   = 64
+	maxColumnFilterProjection   = 1024
+	maxColumnFilterOperandBytes = 4 << 10
+)
+
+// ColumnFilter is the compiled form of a kvpb.RangeFeedColumnFilter. It is
+// evaluated against the values of a registration's events before they are
+// sent to the client. A nil *ColumnFilter matches every event and doesn't
+// project any columns.
+//
+// ColumnFilter is immutable and safe for concurrent use.
+type ColumnFilter struct {
+	familyID   uint32
+	predicates []columnPredicate
+	projection map[uint32]struct{}
+}
+
+type columnPredicate struct {
+	columnID uint32
+	op       kvpb.RangeFeedColumnPredicate_Op
+	operand  columnValue
+}
+
+// columnValueKind enumerates the kinds of column values that a ColumnFilter
+// is able to compare.
+type columnValueKind int
+
+const (
+	unsupportedKind columnValueKind = iota
+	intKind
+	floatKind
+	decimalKind
+	bytesKind
+	boolKind
+)
+
+// columnValue is a decoded column value.
+type columnValue struct {
+	kind columnValueKind
+	i    int64
+	f    float64
+	d    apd.Decimal
+	b    []byte
+}
+
+// NewColumnFilter compiles the provided filter. It returns nil if the filter
+// is nil.
+func NewColumnFilter(f *kvpb.RangeFeedColumnFilter) (*ColumnFilter, error) {
+	if f == nil {
+		return nil, nil
+	}
+	if len(f.Predicates) > maxColumnFilterPredicates {
+		return nil, errors.Newf("rangefeed column filter has %d predicates, more than the maximum of %d",
+			len(f.Predicates), maxColumnFilterPredicates)
+	}
+	if len(f.Projection) > maxColumnFilterProjection {
+		return nil, errors.Newf("rangefeed column filter projects %d columns, more than the maximum of %d",
+			len(f.Projection), maxColumnFilterProjection)
+	}
+	cf := &ColumnFilter{
+		familyID:   f.FamilyID,
+		predicates: make([]columnPredicate, 0, len(f.Predicates)),
+	}
+	for _, p := range f.Predicates {
+		if p.ColumnID == 0 {
+			return nil, errors.New("rangefeed column filter predicate is missing a column ID")
+		}
+		cp := columnPredicate{columnID: p.ColumnID, op: p.Op}
+		switch p.Op {
+		case kvpb.RangeFeedColumnPredicate_IS_NULL, kvpb.RangeFeedColumnPredicate_IS_NOT_NULL:
+		case kvpb.RangeFeedColumnPredicate_EQ, kvpb.RangeFeedColumnPredicate_NE,
+			kvpb.RangeFeedColumnPredicate_LT, kvpb.RangeFeedColumnPredicate_LE,
+			kvpb.RangeFeedColumnPredicate_GT, kvpb.RangeFeedColumnPredicate_GE:
+			if len(p.Operand) > maxColumnFilterOperandBytes {
+				return nil, errors.Newf("rangefeed column filter operand for column %d is too large (%d bytes)",
+					p.ColumnID, len(p.Operand))
+			}
+			v, err := decodeColumnValue(p.Operand)
+			if err != nil {
+				return nil, errors.Wrapf(err, "decoding rangefeed column filter operand for column %d", p.ColumnID)
+			}
+			if v.kind == unsupportedKind {
+				return nil, errors.Newf("unsupported rangefeed column filter operand type for column %d", p.ColumnID)
+			}
+			cp.operand = v
+		default:
+			return nil, errors.Newf("unknown rangefeed column filter operator %d", p.Op)
+		}
+		cf.predicates = append(cf.predicates, cp)
+	}
+	if len(f.Projection) > 0 {
+		cf.projection = make(map[uint32]struct{}, len(f.Projection))
+		for _, colID := range f.Projection {
+			cf.projection[colID] = struct{}{}
+		}
+	}
+	return cf, nil
+}
+
+// appliesTo returns whether the filter applies to the given key and value,
+// i.e. whether the value is a tuple encoded row of the filter's column family.
+func (f *ColumnFilter) appliesTo(key roachpb.Key, value roachpb.Value) bool {
+	if f == nil || !value.IsPresent() || value.GetTag() != roachpb.ValueType_TUPLE {
+		return false
+	}
+	familyID, err := keys.DecodeFamilyKey(key)
+	return err == nil && familyID == f.familyID
+}
+
+// Matches returns whether the given key and value satisfy the filter's
+// predicates. Values that the filter doesn't apply to always match, as do
+// values that cannot be decoded.
+func (f *ColumnFilter) Matches(key roachpb.Key, value roachpb.Value) bool {
+	if f == nil || len(f.predicates) == 0 || !f.appliesTo(key, value) {
+		return true
+	}
+	tuple, err := value.GetTuple()
+	if err != nil {
+		return true
+	}
+	var seen [maxColumnFilterPredicates]bool
+	var colID uint32
+	for len(tuple) > 0 {
+		var col []byte
+		var typ encoding.Type
+		tuple, colID, col, typ, err = nextColumn(tuple, colID)
+		if err != nil {
+			return true
+		}
+		for i := range f.predicates {
+			p := &f.predicates[i]
+			if p.columnID != colID {
+				continue
+			}
+			seen[i] = true
+			if !p.eval(col, typ) {
+				return false
+			}
+		}
+	}
+	// NULL columns are omitted from the tuple.
+	for i := range f.predicates {
+		if !seen[i] && !f.predicates[i].eval(nil, encoding.Null) {
+			return false
+		}
+	}
+	return true
+}
+
+// Project returns the given value with all columns that aren't part of the
+// filter's projection removed. The returned boolean is false if the value was
+// left unchanged.
+func (f *ColumnFilter) Project(key roachpb.Key, value roachpb.Value) (roachpb.Value, bool) {
+	if f == nil || len(f.projection) == 0 || !f.appliesTo(key, value) {
+		return value, false
+	}
+	tuple, err := value.GetTuple()
+	if err != nil {
+		return value, false
+	}
+	var out []byte
+	var colID, lastColID uint32
+	for len(tuple) > 0 {
+		var col []byte
+		var typ encoding.Type
+		tuple, colID, col, typ, err = nextColumn(tuple, colID)
+		if err != nil {
+			return value, false
+		}
+		if _, ok := f.projection[colID]; !ok {
+			continue
+		}
+		// Re-encode the tag, since the column ID delta depends on the previous
+		// column that was retained.
+		_, dataOffset, _, _, err := encoding.DecodeValueTag(col)
+		if err != nil {
+			return value, false
+		}
+		out = encoding.EncodeValueTag(out, colID-lastColID, typ)
+		out = append(out, col[dataOffset:]...)
+		lastColID = colID
+	}
+	var projected roachpb.Value
+	projected.SetTuple(out)
+	projected.Timestamp = value.Timestamp
+	return projected, true
+}
+
+// nextColumn decodes the next column of a tuple encoded value. It returns the
+// remainder of the tuple, the column's ID and its encoded value, including the
+// value tag.
+func nextColumn(
+	tuple []byte, prevColID uint32,
+) (rest []byte, colID uint32, col []byte, typ encoding.Type, err error) {
+	_, dataOffset, colIDDelta, typ, err := encoding.DecodeValueTag(tuple)
+	if err != nil {
+		return nil, 0, nil, encoding.Unknown, err
+	}
+	length, err := encoding.PeekValueLengthWithOffsetsAndType(tuple, dataOffset, typ)
+	if err != nil {
+		return nil, 0, nil, encoding.Unknown, err
+	}
+	return tuple[length:], prevColID + colIDDelta, tuple[:length], typ, nil
+}
+
+// eval evaluates the predicate against the given encoded column value, which
+// is nil if the column is NULL. Predicates that cannot be evaluated hold.
+func (p *columnPredicate) eval(col []byte, typ encoding.Type) bool {
+	isNull := typ == encoding.Null
+	switch p.op {
+	case kvpb.RangeFeedColumnPredicate_IS_NULL:
+		return isNull
+	case kvpb.RangeFeedColumnPredicate_IS_NOT_NULL:
+		return !isNull
+	}
+	if isNull {
+		// Comparisons with NULL are never true.
+		return false
+	}
+	v, err := decodeColumnValue(col)
+	if err != nil {
+		return true
+	}
+	c, ok := compareColumnValues(v, p.operand)
+	if !ok {
+		return true
+	}
+	switch p.op {
+	case kvpb.RangeFeedColumnPredicate_EQ:
+		return c == 0
+	case kvpb.RangeFeedColumnPredicate_NE:
+		return c != 0
+	case kvpb.RangeFeedColumnPredicate_LT:
+		return c < 0
+	case kvpb.RangeFeedColumnPredicate_LE:
+		return c <= 0
+	case kvpb.RangeFeedColumnPredicate_GT:
+		return c > 0
+	case kvpb.RangeFeedColumnPredicate_GE:
+		return c >= 0
+	default:
+		return true
+	}
+}
+
+// decodeColumnValue decodes a value encoded column. Columns of types that the
+// filter doesn't know how to compare are returned with an unsupportedKind.
+func decodeColumnValue(b []byte) (v columnValue, err error) {
+	_, _, _, typ, err := encoding.DecodeValueTag(b)
+	if err != nil {
+		return v, err
+	}
+	switch typ {
+	case encoding.Int:
+		v.kind = intKind
+		_, v.i, err = encoding.DecodeIntValue(b)
+	case encoding.Float:
+		v.kind = floatKind
+		_, v.f, err = encoding.DecodeFloatValue(b)
+	case encoding.Decimal:
+		v.kind = decimalKind
+		_, v.d, err = encoding.DecodeDecimalValue(b)
+	case encoding.Bytes:
+		v.kind = bytesKind
+		_, v.b, err = encoding.DecodeBytesValue(b)
+	case encoding.True, encoding.False:
+		v.kind = boolKind
+		if typ == encoding.True {
+			v.i = 1
+		}
+	}
+	return v, err
+}
+
+// compareColumnValues compares two column values. The returned boolean is
+// false if the values are of different kinds and cannot be compared.
+func compareColumnValues(a, b columnValue) (int, bool) {
+	if a.kind != b.kind || a.kind == unsupportedKind {
+		return 0, false
+	}
+	switch a.kind {
+	case intKind, boolKind:
+		switch {
+		case a.i < b.i:
+			return -1, true
+		case a.i > b.i:
+			return 1, true
+		}
+		return 0, true
+	case floatKind:
+		// NaN sorts before all other values, as it does in SQL.
+		aNaN, bNaN := math.IsNaN(a.f), math.IsNaN(b.f)
+		switch {
+		case aNaN && bNaN:
+			return 0, true
+		case aNaN:
+			return -1, true
+		case bNaN:
+			return 1, true
+		case a.f < b.f:
+			return -1, true
+		case a.f > b.f:
+			return 1, true
+		}
+		return 0, true
+	case decimalKind:
+		return a.d.Cmp(&b.d), true
+	case bytesKind:
+		return bytes.Compare(a.b, b.b), true
+	}
+	return 0, false
+}
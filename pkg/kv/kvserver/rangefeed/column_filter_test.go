@@ -0,0 +1,159 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rangefeed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+// makeRowKV returns a key of the given column family in a table and a tuple
+// encoded value of the given int columns, keyed by column ID.
+func makeRowKV(famID uint32, pk int64, cols map[uint32]int64) (roachpb.Key, roachpb.Value) {
+	key := keys.SystemSQLCodec.IndexPrefix(104, 1)
+	key = encoding.EncodeVarintAscending(key, pk)
+	key = keys.MakeFamilyKey(key, famID)
+
+	var tuple []byte
+	var lastColID uint32
+	for colID := uint32(1); colID <= 8; colID++ {
+		if v, ok := cols[colID]; ok {
+			tuple = encoding.EncodeIntValue(tuple, colID-lastColID, v)
+			lastColID = colID
+		}
+	}
+	var value roachpb.Value
+	value.SetTuple(tuple)
+	value.Timestamp = hlc.Timestamp{WallTime: 1}
+	return key, value
+}
+
+func intOperand(i int64) []byte {
+	return encoding.EncodeIntValue(nil, encoding.NoColumnID, i)
+}
+
+func TestColumnFilterMatches(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	pred := func(colID uint32, op kvpb.RangeFeedColumnPredicate_Op, operand []byte) kvpb.RangeFeedColumnPredicate {
+		return kvpb.RangeFeedColumnPredicate{ColumnID: colID, Op: op, Operand: operand}
+	}
+	row := map[uint32]int64{1: 1, 2: 20, 4: 40}
+
+	testCases := []struct {
+		name     string
+		famID    uint32
+		preds    []kvpb.RangeFeedColumnPredicate
+		expected bool
+	}{
+		{"no predicates", 0, nil, true},
+		{"eq", 0, []kvpb.RangeFeedColumnPredicate{pred(2, kvpb.RangeFeedColumnPredicate_EQ, intOperand(20))}, true},
+		{"eq mismatch", 0, []kvpb.RangeFeedColumnPredicate{pred(2, kvpb.RangeFeedColumnPredicate_EQ, intOperand(21))}, false},
+		{"lt", 0, []kvpb.RangeFeedColumnPredicate{pred(4, kvpb.RangeFeedColumnPredicate_LT, intOperand(41))}, true},
+		{"ge mismatch", 0, []kvpb.RangeFeedColumnPredicate{pred(4, kvpb.RangeFeedColumnPredicate_GE, intOperand(41))}, false},
+		{"conjunction", 0, []kvpb.RangeFeedColumnPredicate{
+			pred(1, kvpb.RangeFeedColumnPredicate_NE, intOperand(2)),
+			pred(4, kvpb.RangeFeedColumnPredicate_GT, intOperand(41)),
+		}, false},
+		{"null column is null", 0, []kvpb.RangeFeedColumnPredicate{pred(3, kvpb.RangeFeedColumnPredicate_IS_NULL, nil)}, true},
+		{"null column comparison", 0, []kvpb.RangeFeedColumnPredicate{pred(3, kvpb.RangeFeedColumnPredicate_EQ, intOperand(0))}, false},
+		{"not null", 0, []kvpb.RangeFeedColumnPredicate{pred(2, kvpb.RangeFeedColumnPredicate_IS_NOT_NULL, nil)}, true},
+		{"type mismatch holds", 0, []kvpb.RangeFeedColumnPredicate{
+			pred(2, kvpb.RangeFeedColumnPredicate_EQ, encoding.EncodeBytesValue(nil, encoding.NoColumnID, []byte("x"))),
+		}, true},
+		{"other family", 1, []kvpb.RangeFeedColumnPredicate{pred(2, kvpb.RangeFeedColumnPredicate_EQ, intOperand(21))}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := NewColumnFilter(&kvpb.RangeFeedColumnFilter{FamilyID: tc.famID, Predicates: tc.preds})
+			require.NoError(t, err)
+			key, value := makeRowKV(0, 1, row)
+			require.Equal(t, tc.expected, f.Matches(key, value))
+		})
+	}
+
+	t.Run("deletion", func(t *testing.T) {
+		f, err := NewColumnFilter(&kvpb.RangeFeedColumnFilter{Predicates: []kvpb.RangeFeedColumnPredicate{
+			pred(2, kvpb.RangeFeedColumnPredicate_EQ, intOperand(21)),
+		}})
+		require.NoError(t, err)
+		key, _ := makeRowKV(0, 1, row)
+		require.True(t, f.Matches(key, roachpb.Value{}))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewColumnFilter(&kvpb.RangeFeedColumnFilter{Predicates: []kvpb.RangeFeedColumnPredicate{
+			pred(0, kvpb.RangeFeedColumnPredicate_EQ, intOperand(1)),
+		}})
+		require.Error(t, err)
+		_, err = NewColumnFilter(&kvpb.RangeFeedColumnFilter{Predicates: []kvpb.RangeFeedColumnPredicate{
+			pred(1, kvpb.RangeFeedColumnPredicate_EQ, nil),
+		}})
+		require.Error(t, err)
+	})
+}
+
+func TestColumnFilterProject(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	f, err := NewColumnFilter(&kvpb.RangeFeedColumnFilter{Projection: []uint32{2, 4}})
+	require.NoError(t, err)
+
+	key, value := makeRowKV(0, 1, map[uint32]int64{1: 1, 2: 20, 3: 30, 4: 40})
+	projected, ok := f.Project(key, value)
+	require.True(t, ok)
+	_, expected := makeRowKV(0, 1, map[uint32]int64{2: 20, 4: 40})
+	require.Equal(t, expected, projected)
+
+	// Values of other column families are left untouched.
+	key, value = makeRowKV(1, 1, map[uint32]int64{1: 1, 2: 20})
+	_, ok = f.Project(key, value)
+	require.False(t, ok)
+}
+
+func TestRegistrationColumnFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	key1, val1 := makeRowKV(0, 1, map[uint32]int64{1: 1, 2: 10})
+	key2, val2 := makeRowKV(0, 2, map[uint32]int64{1: 2, 2: 20})
+	ev1, ev2 := new(kvpb.RangeFeedEvent), new(kvpb.RangeFeedEvent)
+	ev1.MustSetValue(&kvpb.RangeFeedValue{Key: key1, Value: val1})
+	ev2.MustSetValue(&kvpb.RangeFeedValue{Key: key2, Value: val2})
+
+	span := roachpb.Span{Key: keys.SystemSQLCodec.TablePrefix(104), EndKey: keys.SystemSQLCodec.TablePrefix(105)}
+	reg := newTestRegistration(span, hlc.Timestamp{}, nil, false)
+	reg.columnFilter, _ = NewColumnFilter(&kvpb.RangeFeedColumnFilter{
+		Predicates: []kvpb.RangeFeedColumnPredicate{{
+			ColumnID: 2, Op: kvpb.RangeFeedColumnPredicate_GT, Operand: intOperand(15),
+		}},
+		Projection: []uint32{1},
+	})
+	reg.publish(ctx, ev1, nil /* alloc */)
+	reg.publish(ctx, ev2, nil /* alloc */)
+	require.Equal(t, 1, len(reg.buf))
+	go reg.runOutputLoop(ctx, 0)
+	require.NoError(t, reg.waitForCaughtUp())
+
+	_, projected := makeRowKV(0, 2, map[uint32]int64{1: 2})
+	expected := new(kvpb.RangeFeedEvent)
+	expected.MustSetValue(&kvpb.RangeFeedValue{Key: key2, Value: projected})
+	require.Equal(t, []*kvpb.RangeFeedEvent{expected}, reg.Events())
+	reg.disconnect(nil)
+}
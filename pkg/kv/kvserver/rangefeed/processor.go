@@ -168,6 +168,9 @@ type Processor interface {
 	// subsequently close it. If method fails, iterator must be kept intact and
 	// would be closed by caller.
 	//
+	// The optionally provided column filter is applied to the value events of
+	// the registration, including those emitted by the catch-up scan.
+	//
 	// If the method returns false, the processor will have been stopped, so calling
 	// Stop is not necessary. If the method returns true, it will also return an
 	// updated operation filter that includes the operations required by the new
@@ -179,6 +182,7 @@ type Processor interface {
 		startTS hlc.Timestamp, // exclusive
 		catchUpIter *CatchUpIterator,
 		withDiff bool,
+		columnFilter *ColumnFilter,
 		stream Stream,
 		disconnectFn func(),
 		done *future.ErrorFuture,
@@ -556,6 +560,7 @@ func (p *LegacyProcessor) Register(
 	startTS hlc.Timestamp,
 	catchUpIter *CatchUpIterator,
 	withDiff bool,
+	columnFilter *ColumnFilter,
 	stream Stream,
 	disconnectFn func(),
 	done *future.ErrorFuture,
@@ -567,7 +572,7 @@ func (p *LegacyProcessor) Register(
 
 	blockWhenFull := p.Config.EventChanTimeout == 0 // for testing
 	r := newRegistration(
-		span.AsRawSpanWithNoLocals(), startTS, catchUpIter, withDiff,
+		span.AsRawSpanWithNoLocals(), startTS, catchUpIter, withDiff, columnFilter,
 		p.Config.EventChanCap, blockWhenFull, p.Metrics, stream, disconnectFn, done,
 	)
 	select {
@@ -442,6 +442,7 @@ func TestProcessorBasic(t *testing.T) {
 			hlc.Timestamp{WallTime: 1},
 			nil,   /* catchUpIter */
 			false, /* withDiff */
+			nil,   /* columnFilter */
 			r1Stream,
 			func() {},
 			&r1Done,
@@ -575,6 +576,7 @@ func TestProcessorBasic(t *testing.T) {
 			hlc.Timestamp{WallTime: 1},
 			nil,  /* catchUpIter */
 			true, /* withDiff */
+			nil,  /* columnFilter */
 			r2Stream,
 			func() {},
 			&r2Done,
@@ -671,6 +673,7 @@ func TestProcessorBasic(t *testing.T) {
 			hlc.Timestamp{WallTime: 1},
 			nil,   /* catchUpIter */
 			false, /* withDiff */
+			nil,   /* columnFilter */
 			r3Stream,
 			func() {},
 			&r3Done,
@@ -694,6 +697,7 @@ func TestProcessorSlowConsumer(t *testing.T) {
 			hlc.Timestamp{WallTime: 1},
 			nil,   /* catchUpIter */
 			false, /* withDiff */
+			nil,   /* columnFilter */
 			r1Stream,
 			func() {},
 			&r1Done,
@@ -705,6 +709,7 @@ func TestProcessorSlowConsumer(t *testing.T) {
 			hlc.Timestamp{WallTime: 1},
 			nil,   /* catchUpIter */
 			false, /* withDiff */
+			nil,   /* columnFilter */
 			r2Stream,
 			func() {},
 			&r2Done,
@@ -801,6 +806,7 @@ func TestProcessorMemoryBudgetExceeded(t *testing.T) {
 			hlc.Timestamp{WallTime: 1},
 			nil,   /* catchUpIter */
 			false, /* withDiff */
+			nil,   /* columnFilter */
 			r1Stream,
 			func() {},
 			&r1Done,
@@ -856,6 +862,7 @@ func TestProcessorMemoryBudgetReleased(t *testing.T) {
 			hlc.Timestamp{WallTime: 1},
 			nil,   /* catchUpIter */
 			false, /* withDiff */
+			nil,   /* columnFilter */
 			r1Stream,
 			func() {},
 			&r1Done,
@@ -937,6 +944,7 @@ func TestProcessorInitializeResolvedTimestamp(t *testing.T) {
 			hlc.Timestamp{WallTime: 1},
 			nil,   /* catchUpIter */
 			false, /* withDiff */
+			nil,   /* columnFilter */
 			r1Stream,
 			func() {},
 			&r1Done,
@@ -1190,7 +1198,7 @@ func TestProcessorConcurrentStop(t *testing.T) {
 				runtime.Gosched()
 				s := newTestStream()
 				var done future.ErrorFuture
-				p.Register(h.span, hlc.Timestamp{}, nil, false, s,
+				p.Register(h.span, hlc.Timestamp{}, nil, false, nil, s,
 					func() {}, &done)
 			}()
 			go func() {
@@ -1263,7 +1271,7 @@ func TestProcessorRegistrationObservesOnlyNewEvents(t *testing.T) {
 				s := newTestStream()
 				regs[s] = firstIdx
 				var done future.ErrorFuture
-				p.Register(h.span, hlc.Timestamp{}, nil, false,
+				p.Register(h.span, hlc.Timestamp{}, nil, false, nil,
 					s, func() {}, &done)
 				regDone <- struct{}{}
 			}
@@ -1325,6 +1333,7 @@ func TestBudgetReleaseOnProcessorStop(t *testing.T) {
 			hlc.Timestamp{WallTime: 1},
 			nil,   /* catchUpIter */
 			false, /* withDiff */
+			nil,   /* columnFilter */
 			rStream,
 			func() {},
 			&done,
@@ -1406,6 +1415,7 @@ func TestBudgetReleaseOnLastStreamError(t *testing.T) {
 			hlc.Timestamp{WallTime: 1},
 			nil,   /* catchUpIter */
 			false, /* withDiff */
+			nil,   /* columnFilter */
 			rStream,
 			func() {},
 			&done,
@@ -1477,6 +1487,7 @@ func TestBudgetReleaseOnOneStreamError(t *testing.T) {
 			hlc.Timestamp{WallTime: 1},
 			nil,   /* catchUpIter */
 			false, /* withDiff */
+			nil,   /* columnFilter */
 			r1Stream,
 			func() {},
 			&r1Done,
@@ -1491,6 +1502,7 @@ func TestBudgetReleaseOnOneStreamError(t *testing.T) {
 			hlc.Timestamp{WallTime: 1},
 			nil,   /* catchUpIter */
 			false, /* withDiff */
+			nil,   /* columnFilter */
 			r2Stream,
 			func() {},
 			&r2Done,
@@ -1641,7 +1653,7 @@ func TestProcessorBackpressure(t *testing.T) {
 	// Add a registration.
 	stream := newTestStream()
 	done := &future.ErrorFuture{}
-	ok, _ := p.Register(span, hlc.MinTimestamp, nil, false, stream, nil, done)
+	ok, _ := p.Register(span, hlc.MinTimestamp, nil, false, nil, stream, nil, done)
 	require.True(t, ok)
 
 	// Wait for the initial checkpoint.
@@ -79,6 +79,7 @@ type registration struct {
 	span             roachpb.Span
 	catchUpTimestamp hlc.Timestamp // exclusive
 	withDiff         bool
+	columnFilter     *ColumnFilter
 	metrics          *Metrics
 
 	// Output.
@@ -117,6 +118,7 @@ func newRegistration(
 	startTS hlc.Timestamp,
 	catchUpIter *CatchUpIterator,
 	withDiff bool,
+	columnFilter *ColumnFilter,
 	bufferSz int,
 	blockWhenFull bool,
 	metrics *Metrics,
@@ -128,6 +130,7 @@ func newRegistration(
 		span:             span,
 		catchUpTimestamp: startTS,
 		withDiff:         withDiff,
+		columnFilter:     columnFilter,
 		metrics:          metrics,
 		stream:           stream,
 		done:             done,
@@ -145,11 +148,14 @@ func newRegistration(
 // registration. If the output buffer is full, the overflowed flag is set,
 // indicating that live events were lost and a catch-up scan should be initiated.
 // If overflowed is already set, events are ignored and not written to the
-// buffer.
+// buffer. Events rejected by the registration's column filter are dropped.
 func (r *registration) publish(
 	ctx context.Context, event *kvpb.RangeFeedEvent, alloc *SharedBudgetAllocation,
 ) {
 	r.validateEvent(event)
+	if !r.matchesColumnFilter(event) {
+		return
+	}
 	e := getPooledSharedEvent(sharedEvent{event: r.maybeStripEvent(event), alloc: alloc})
 
 	r.mu.Lock()
@@ -225,10 +231,22 @@ func (r *registration) validateEvent(event *kvpb.RangeFeedEvent) {
 	}
 }
 
+// matchesColumnFilter returns whether the event passes the registration's
+// column filter. Only value events are filtered.
+func (r *registration) matchesColumnFilter(event *kvpb.RangeFeedEvent) bool {
+	if r.columnFilter == nil {
+		return true
+	}
+	if t, ok := event.GetValue().(*kvpb.RangeFeedValue); ok {
+		return r.columnFilter.Matches(t.Key, t.Value)
+	}
+	return true
+}
+
 // maybeStripEvent determines whether the event contains excess information not
 // applicable to the current registration. If so, it makes a copy of the event
 // and strips the incompatible information to match only what the registration
-// requested.
+// requested, including the columns not retained by its column filter.
 func (r *registration) maybeStripEvent(event *kvpb.RangeFeedEvent) *kvpb.RangeFeedEvent {
 	ret := event
 	copyOnWrite := func() interface{} {
@@ -250,6 +268,16 @@ func (r *registration) maybeStripEvent(event *kvpb.RangeFeedEvent) *kvpb.RangeFe
 			t = copyOnWrite().(*kvpb.RangeFeedValue)
 			t.PrevValue = roachpb.Value{}
 		}
+		if r.columnFilter != nil {
+			if v, ok := r.columnFilter.Project(t.Key, t.Value); ok {
+				t = copyOnWrite().(*kvpb.RangeFeedValue)
+				t.Value = v
+			}
+			if v, ok := r.columnFilter.Project(t.Key, t.PrevValue); ok {
+				t = copyOnWrite().(*kvpb.RangeFeedValue)
+				t.PrevValue = v
+			}
+		}
 	case *kvpb.RangeFeedCheckpoint:
 		if !t.Span.EqualValue(r.span) {
 			// Checkpoint events are always created spanning the entire Range.
@@ -397,7 +425,18 @@ func (r *registration) maybeRunCatchUpScan(ctx context.Context) error {
 		r.metrics.RangeFeedCatchUpScanNanos.Inc(timeutil.Since(start).Nanoseconds())
 	}()
 
-	return catchUpIter.CatchUpScan(ctx, r.stream.Send, r.withDiff)
+	outputFn := r.stream.Send
+	if r.columnFilter != nil {
+		// Apply the column filter to the catch-up scan as well, so that events
+		// that would be dropped don't leave the server.
+		outputFn = func(event *kvpb.RangeFeedEvent) error {
+			if !r.matchesColumnFilter(event) {
+				return nil
+			}
+			return r.stream.Send(r.maybeStripEvent(event))
+		}
+	}
+	return catchUpIter.CatchUpScan(ctx, outputFn, r.withDiff)
 }
 
 // ID implements interval.Interface.
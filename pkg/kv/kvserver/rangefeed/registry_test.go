@@ -122,6 +122,7 @@ func newTestRegistration(
 		ts,
 		makeCatchUpIterator(catchup, span, ts),
 		withDiff,
+		nil, /* columnFilter */
 		5,
 		false, /* blockWhenFull */
 		NewMetrics(),
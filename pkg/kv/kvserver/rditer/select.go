@@ -39,6 +39,11 @@ const (
 	// ReplicatedSpansLocksOnly includes just spans for the lock table, and no
 	// other replicated spans.
 	ReplicatedSpansLocksOnly
+	// ReplicatedSpansRangeLocalOnly includes just the range-local keys (range
+	// descriptors, transaction records, etc.), and neither the lock table nor
+	// user keys. This, together with the range-ID local keys, is the replicated
+	// state held by WITNESS replicas.
+	ReplicatedSpansRangeLocalOnly
 )
 
 // SelectOpts configures which spans for a Replica to return from Select.
@@ -96,7 +101,8 @@ func Select(rangeID roachpb.RangeID, opts SelectOpts) []roachpb.Span {
 			}
 
 			// Lock table.
-			if opts.ReplicatedSpansFilter != ReplicatedSpansExcludeLocks {
+			if opts.ReplicatedSpansFilter != ReplicatedSpansExcludeLocks &&
+				opts.ReplicatedSpansFilter != ReplicatedSpansRangeLocalOnly {
 				// Handle doubly-local lock table keys since range descriptor key
 				// is a range local key that can have a replicated lock acquired on it.
 				startRangeLocal, _ := keys.LockTableSingleKey(keys.MakeRangeKeyPrefix(in.Key), nil)
@@ -115,7 +121,8 @@ func Select(rangeID roachpb.RangeID, opts SelectOpts) []roachpb.Span {
 			}
 		}
 		if opts.ReplicatedSpansFilter != ReplicatedSpansExcludeUser &&
-			opts.ReplicatedSpansFilter != ReplicatedSpansLocksOnly {
+			opts.ReplicatedSpansFilter != ReplicatedSpansLocksOnly &&
+			opts.ReplicatedSpansFilter != ReplicatedSpansRangeLocalOnly {
 			// Adjusted span because r1's "normal" keyspace starts only at LocalMax,
 			// not RKeyMin.
 			sl = append(sl, adjustedIn.AsRawSpanWithNoLocals())
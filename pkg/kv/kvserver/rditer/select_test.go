@@ -84,6 +84,14 @@ func TestSelect(t *testing.T) {
 			},
 			filter: ReplicatedSpansLocksOnly,
 		},
+		{
+			name: "r2_rangelocalonly",
+			sp: roachpb.RSpan{
+				Key:    roachpb.RKey("a"),
+				EndKey: roachpb.RKey("c"),
+			},
+			filter: ReplicatedSpansRangeLocalOnly,
+		},
 		{
 			name: "r3",
 			sp: roachpb.RSpan{
@@ -1187,6 +1187,14 @@ func (r *Replica) excludeReplicaFromBackupRLocked() bool {
 	return r.mu.conf.ExcludeDataFromBackup
 }
 
+// RowExpiry returns the storage level row expiry of the replica's span config,
+// if any.
+func (r *Replica) RowExpiry() *roachpb.RowExpiry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.mu.conf.RowExpiry
+}
+
 // Version returns the replica version.
 func (r *Replica) Version() roachpb.Version {
 	if r.mu.state.Version == nil {
@@ -1883,6 +1891,8 @@ func (r *Replica) checkExecutionCanProceedForRangeFeed(
 			r.RangeID, docs.URL(`change-data-capture.html#enable-rangefeeds-to-reduce-latency`))
 	} else if err := r.checkTSAboveGCThresholdRLocked(ts, status, false /* isAdmin */); err != nil {
 		return err
+	} else if r.isWitnessRLocked() {
+		return errors.Errorf("[r%d] rangefeeds cannot be served by %s replicas", r.RangeID, roachpb.WITNESS)
 	}
 	return nil
 }
@@ -11,9 +11,12 @@
 package kvserver
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/apply"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvadmission"
@@ -26,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble"
 	"github.com/cockroachdb/redact"
 )
 
@@ -135,6 +139,16 @@ func (b *replicaAppBatch) Stage(
 		return nil, err
 	}
 
+	// Witnesses only apply the range's metadata, see witnessWriteBatch.
+	isWitness := b.isWitness()
+	if wb := cmd.Cmd.WriteBatch; wb != nil && isWitness {
+		data, err := witnessWriteBatch(wb.Data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to filter WriteBatch for witness")
+		}
+		cmd.Cmd.WriteBatch = &kvserverpb.WriteBatch{Data: data}
+	}
+
 	// Stage the command's write batch in the application batch.
 	if err := b.ab.addWriteBatch(ctx, b.batch, cmd); err != nil {
 		return nil, err
@@ -149,6 +163,7 @@ func (b *replicaAppBatch) Stage(
 		eng:         b.r.store.TODOEngine(),
 		sideloaded:  b.r.raftMu.sideloaded,
 		bulkLimiter: b.r.store.limiters.BulkIOWriteRate,
+		witness:     isWitness,
 	}); err != nil {
 		return nil, err
 	}
@@ -186,6 +201,69 @@ func changeRemovesStore(
 	return !existsInChange
 }
 
+// isWitness returns whether the local replica is a WITNESS according to the
+// batch's view of the range descriptor.
+func (b *replicaAppBatch) isWitness() bool {
+	repDesc, ok := b.state.Desc.GetReplicaDescriptorByID(b.r.replicaID)
+	return ok && repDesc.IsWitness()
+}
+
+// witnessWriteBatch returns the subset of the given WriteBatch repr that is
+// applied by WITNESS replicas: the writes to range-ID local and range-local
+// keys, such as the applied state, the range descriptor and transaction
+// records, which a witness needs to remain a functioning member of the range.
+// Writes to the lock table and to user keys are dropped, since witnesses never
+// serve reads, hold the lease or send snapshots. Ranged writes are kept or
+// dropped based on their start key.
+//
+// Note that the replicated MVCCStats are left untouched, so a witness reports
+// the same stats as the other replicas of the range.
+func witnessWriteBatch(repr []byte) ([]byte, error) {
+	r, err := storage.NewBatchReader(repr)
+	if err != nil {
+		return nil, err
+	}
+	// The batch header is composed of an 8-byte sequence number (all zeroes) and
+	// a 4-byte count of the number of entries in the batch.
+	const headerSize, countPos = 12, 8
+	filtered := make([]byte, headerSize, len(repr))
+	var count uint32
+	var read int
+	for ; r.Next(); read++ {
+		if r.KeyKind() != pebble.InternalKeyKindLogData {
+			key, ok := storage.DecodeEngineKey(r.Key())
+			if !ok {
+				return nil, errors.Errorf("invalid encoded engine key: %x", r.Key())
+			}
+			if !bytes.HasPrefix(key.Key, keys.LocalRangeIDPrefix) &&
+				!bytes.HasPrefix(key.Key, keys.LocalRangePrefix) {
+				continue
+			}
+		}
+		filtered = append(filtered, byte(r.KeyKind()))
+		filtered = binary.AppendUvarint(filtered, uint64(len(r.Key())))
+		filtered = append(filtered, r.Key()...)
+		switch r.KeyKind() {
+		case pebble.InternalKeyKindSet, pebble.InternalKeyKindMerge, pebble.InternalKeyKindRangeDelete,
+			pebble.InternalKeyKindRangeKeySet, pebble.InternalKeyKindRangeKeyUnset,
+			pebble.InternalKeyKindRangeKeyDelete, pebble.InternalKeyKindDeleteSized:
+			// These are the kinds that carry a value, see pebble.BatchReader.Next.
+			v := r.Value()
+			filtered = binary.AppendUvarint(filtered, uint64(len(v)))
+			filtered = append(filtered, v...)
+		}
+		count++
+	}
+	if err := r.Error(); err != nil {
+		return nil, err
+	}
+	if read != r.Count() {
+		return nil, errors.Errorf("corrupt batch: read %d of %d entries", read, r.Count())
+	}
+	binary.LittleEndian.PutUint32(filtered[countPos:headerSize], count)
+	return filtered, nil
+}
+
 // runPreAddTriggersReplicaOnly is like (appBatch).runPreAddTriggers (and is
 // called right after it), except that it must only contain ephemeral side
 // effects that have no influence on durable state. It is not invoked during
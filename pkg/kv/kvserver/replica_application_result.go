@@ -526,6 +526,13 @@ func (r *Replica) handleVersionResult(ctx context.Context, version *roachpb.Vers
 }
 
 func (r *Replica) handleComputeChecksumResult(ctx context.Context, cc *kvserverpb.ComputeChecksum) {
+	r.mu.RLock()
+	isWitness := r.isWitnessRLocked()
+	r.mu.RUnlock()
+	if isWitness {
+		// Witnesses are left out of consistency checks, see runConsistencyCheck.
+		return
+	}
 	err := r.computeChecksumPostApply(ctx, *cc)
 	// Don't log errors caused by the store quiescing, they are expected.
 	if err != nil && !errors.Is(err, stop.ErrUnavailable) {
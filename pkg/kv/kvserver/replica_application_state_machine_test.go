@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble"
 	"github.com/stretchr/testify/require"
 	"go.etcd.io/raft/v3/raftpb"
 )
@@ -479,3 +480,52 @@ func TestReplicaStateMachineEphemeralAppBatchRejection(t *testing.T) {
 	b.Close()
 	require.Equal(t, []bool{false, true}, rejs)
 }
+
+// TestWitnessWriteBatch tests that witnessWriteBatch only keeps the writes to
+// range-ID local and range-local keys of a command's WriteBatch.
+func TestWitnessWriteBatch(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	eng := storage.NewDefaultInMemForTesting()
+	defer eng.Close()
+
+	rangeIDKey := keys.RangeGCThresholdKey(1)
+	descKey := keys.RangeDescriptorKey(roachpb.RKey("a"))
+	lockKey, _ := keys.LockTableSingleKey(roachpb.Key("a"), nil)
+	userKey := roachpb.Key("a")
+
+	b := eng.NewBatch()
+	defer b.Close()
+	require.NoError(t, b.PutUnversioned(rangeIDKey, []byte("gc")))
+	require.NoError(t, b.PutUnversioned(userKey, []byte("user")))
+	require.NoError(t, b.PutEngineKey(storage.EngineKey{Key: lockKey}, []byte("lock")))
+	require.NoError(t, b.PutUnversioned(descKey, []byte("desc")))
+	require.NoError(t, b.ClearUnversioned(descKey, storage.ClearOptions{}))
+	require.NoError(t, b.ClearRawRange(userKey, userKey.PrefixEnd(), true /* pointKeys */, false /* rangeKeys */))
+
+	data, err := witnessWriteBatch(b.Repr())
+	require.NoError(t, err)
+
+	r, err := storage.NewBatchReader(data)
+	require.NoError(t, err)
+	require.Equal(t, 3, r.Count())
+	var kinds []pebble.InternalKeyKind
+	var kept []roachpb.Key
+	for r.Next() {
+		key, err := r.EngineKey()
+		require.NoError(t, err)
+		kinds = append(kinds, r.KeyKind())
+		kept = append(kept, key.Key)
+	}
+	require.Equal(t, []pebble.InternalKeyKind{
+		pebble.InternalKeyKindSet, pebble.InternalKeyKindSet, pebble.InternalKeyKindDelete,
+	}, kinds)
+	require.Equal(t, []roachpb.Key{rangeIDKey, descKey, descKey}, kept)
+
+	// The filtered batch can be applied like any other.
+	wb := eng.NewBatch()
+	defer wb.Close()
+	require.NoError(t, wb.ApplyBatchRepr(data, false /* sync */))
+	require.Equal(t, uint32(3), wb.Count())
+}
@@ -666,14 +666,18 @@ func (r *Replica) AdminMerge(
 		// queues should fix things up quickly).
 		lReplicas, rReplicas := origLeftDesc.Replicas(), rightDesc.Replicas()
 
-		if len(lReplicas.VoterFullAndNonVoterDescriptors()) != len(lReplicas.Descriptors()) {
+		if len(lReplicas.VoterFullAndNonVoterDescriptors())+len(lReplicas.WitnessDescriptors()) !=
+			len(lReplicas.Descriptors()) {
 			return errors.Errorf("cannot merge ranges when lhs is in a joint state or has learners: %s",
 				lReplicas)
 		}
-		if len(rReplicas.VoterFullAndNonVoterDescriptors()) != len(rReplicas.Descriptors()) {
+		if len(rReplicas.VoterFullAndNonVoterDescriptors())+len(rReplicas.WitnessDescriptors()) !=
+			len(rReplicas.Descriptors()) {
 			return errors.Errorf("cannot merge ranges when rhs is in a joint state or has learners: %s",
 				rReplicas)
 		}
+		// NB: this also rejects ranges whose witnesses are on different stores,
+		// which AdminRelocateRange can't fix up since it doesn't place witnesses.
 		if !replicasCollocated(lReplicas.Descriptors(), rReplicas.Descriptors()) {
 			return errors.Errorf("ranges not collocated; %s != %s", lReplicas, rReplicas)
 		}
@@ -1044,6 +1048,8 @@ func (r *Replica) changeReplicasImpl(
 	// 3. Voter removals
 	// 4. Non-voter additions
 	// 5. Non-voter removals
+	// 6. Witness additions
+	// 7. Witness removals
 	//
 	// This order is meant to be symmetric with how the allocator prioritizes
 	// these actions. Broadly speaking, we first want to add a missing voter (and
@@ -1073,6 +1079,23 @@ func (r *Replica) changeReplicasImpl(
 		}
 	}
 
+	// Witnesses don't hold the range's data, so they can't be promoted into
+	// full voters in place. Instead, the witness is removed here and the target
+	// is then added as a voter below, which sends it a full snapshot.
+	for _, target := range targets.WitnessPromotions {
+		iChgs := []internalReplicationChange{{target: target, typ: internalChangeTypeRemoveWitness}}
+		desc, err = execChangeReplicasTxn(ctx, r.store.cfg.Tracer(), desc, reason, details, iChgs, changeReplicasTxnArgs{
+			db:                                   r.store.DB(),
+			liveAndDeadReplicas:                  r.store.cfg.StorePool.LiveAndDeadReplicas,
+			logChange:                            r.store.logChange,
+			testForceJointConfig:                 r.store.TestingKnobs().ReplicationAlwaysUseJointConfig,
+			testAllowDangerousReplicationChanges: r.store.TestingKnobs().AllowDangerousReplicationChanges,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if adds := targets.VoterAdditions; len(adds) > 0 {
 		// For all newly added voters, first add LEARNER replicas. They accept raft
 		// traffic (so they can catch up) but don't get to vote (so they don't
@@ -1155,6 +1178,47 @@ func (r *Replica) changeReplicasImpl(
 		}
 	}
 
+	if adds := targets.WitnessAdditions; len(adds) > 0 {
+		// Like voters, witnesses are first added as LEARNERs so that they can
+		// catch up on the raft log without affecting quorum, and are then
+		// promoted. Their initial snapshot only carries the range's metadata.
+		desc, err = r.initializeRaftLearners(
+			ctx, desc, priority, senderName, senderQueuePriority, reason, details, adds, roachpb.WITNESS,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, target := range adds {
+			iChgs := []internalReplicationChange{{target: target, typ: internalChangeTypePromoteLearnerToWitness}}
+			desc, err = execChangeReplicasTxn(ctx, r.store.cfg.Tracer(), desc, reason, details, iChgs, changeReplicasTxnArgs{
+				db:                                   r.store.DB(),
+				liveAndDeadReplicas:                  r.store.cfg.StorePool.LiveAndDeadReplicas,
+				logChange:                            r.store.logChange,
+				testForceJointConfig:                 r.store.TestingKnobs().ReplicationAlwaysUseJointConfig,
+				testAllowDangerousReplicationChanges: r.store.TestingKnobs().AllowDangerousReplicationChanges,
+			})
+			if err != nil {
+				log.Infof(ctx, "could not promote %v to witness, rolling back: %v", target, err)
+				r.tryRollbackRaftLearner(ctx, r.Desc(), target, reason, details)
+				return nil, err
+			}
+		}
+	}
+
+	for _, rem := range targets.WitnessRemovals {
+		iChgs := []internalReplicationChange{{target: rem, typ: internalChangeTypeRemoveWitness}}
+		desc, err = execChangeReplicasTxn(ctx, r.store.cfg.Tracer(), desc, reason, details, iChgs, changeReplicasTxnArgs{
+			db:                                   r.store.DB(),
+			liveAndDeadReplicas:                  r.store.cfg.StorePool.LiveAndDeadReplicas,
+			logChange:                            r.store.logChange,
+			testForceJointConfig:                 r.store.TestingKnobs().ReplicationAlwaysUseJointConfig,
+			testAllowDangerousReplicationChanges: r.store.TestingKnobs().AllowDangerousReplicationChanges,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if len(targets.VoterDemotions) > 0 {
 		// If we demoted or swapped any voters with non-voters, we likely are in a
 		// joint config or have learners on the range. Let's exit the joint config
@@ -1171,6 +1235,8 @@ type TargetsForReplicationChanges struct {
 	VoterDemotions, NonVoterPromotions  []roachpb.ReplicationTarget
 	VoterAdditions, VoterRemovals       []roachpb.ReplicationTarget
 	NonVoterAdditions, NonVoterRemovals []roachpb.ReplicationTarget
+	WitnessPromotions                   []roachpb.ReplicationTarget
+	WitnessAdditions, WitnessRemovals   []roachpb.ReplicationTarget
 }
 
 // SynthesizeTargetsByChangeType groups replication changes in the
@@ -1179,8 +1245,10 @@ type TargetsForReplicationChanges struct {
 // In particular, it coalesces ReplicationChanges of types ADD_VOTER and
 // REMOVE_NON_VOTER on a given target as promotions of non-voters into voters
 // and likewise, ADD_NON_VOTER and REMOVE_VOTER changes for a given target as
-// demotions of voters into non-voters. The rest of the changes are handled
-// distinctly and are thus segregated in the return result.
+// demotions of voters into non-voters. Similarly, ADD_VOTER and REMOVE_WITNESS
+// changes for a given target are coalesced into promotions of witnesses into
+// full voters. The rest of the changes are handled distinctly and are thus
+// segregated in the return result.
 func SynthesizeTargetsByChangeType(
 	chgs kvpb.ReplicationChanges,
 ) (result TargetsForReplicationChanges) {
@@ -1197,6 +1265,14 @@ func SynthesizeTargetsByChangeType(
 	result.NonVoterAdditions = subtractTargets(chgs.NonVoterAdditions(), chgs.VoterRemovals())
 	result.NonVoterRemovals = subtractTargets(chgs.NonVoterRemovals(), chgs.VoterAdditions())
 
+	// Witnesses are only ever promoted into full voters, which is executed as a
+	// removal of the witness followed by a regular voter addition, so the
+	// promoted targets remain in VoterAdditions. The reverse is expressed as a
+	// separate removal and addition.
+	result.WitnessPromotions = intersectTargets(chgs.WitnessRemovals(), chgs.VoterAdditions())
+	result.WitnessAdditions = chgs.WitnessAdditions()
+	result.WitnessRemovals = subtractTargets(chgs.WitnessRemovals(), chgs.VoterAdditions())
+
 	return result
 }
 
@@ -1455,6 +1531,11 @@ func validateAdditionsPerStore(
 					return errors.AssertionFailedf(
 						"trying to add a non-voter to a store that already has a %s", t)
 				}
+			case roachpb.WITNESS:
+				if chg.ChangeType != roachpb.ADD_VOTER {
+					return errors.AssertionFailedf(
+						"trying to add(%+v) to a store that already has a %s", chg, t)
+				}
 			default:
 				return errors.AssertionFailedf("store(%d) being added to already contains a"+
 					" replica of an unexpected type: %s", storeID, t)
@@ -1489,6 +1570,11 @@ func validateRemovals(desc *roachpb.RangeDescriptor, chgsByStoreID changesByStor
 					return errors.AssertionFailedf("type of replica being removed (%s) does not match"+
 						" expectation for change: %+v", t, chg)
 				}
+			case roachpb.WITNESS:
+				if chg.ChangeType != roachpb.REMOVE_WITNESS {
+					return errors.AssertionFailedf("type of replica being removed (%s) does not match"+
+						" expectation for change: %+v", t, chg)
+				}
 			default:
 				return errors.AssertionFailedf("unexpected replica type for removal %+v: %s", chg, t)
 			}
@@ -1504,6 +1590,9 @@ func validateRemovals(desc *roachpb.RangeDescriptor, chgsByStoreID changesByStor
 // 2. All additions of non-voters to stores that already have a voter are
 // accompanied by a removal of that voter (which is interpreted as a demotion of
 // a voter to a non-voter)
+// 3. All additions of voters to stores that already have a witness are
+// accompanied by a removal of that witness (which is interpreted as a promotion
+// of a witness to a full voter).
 func validatePromotionsAndDemotions(
 	desc *roachpb.RangeDescriptor, chgsByStoreID changesByStoreID,
 ) error {
@@ -1529,14 +1618,16 @@ func validatePromotionsAndDemotions(
 					" that has no replicas", chgs, storeID)
 			}
 			if c1.ChangeType.IsAddition() && c2.ChangeType.IsRemoval() {
-				// There's only two legal possibilities here:
+				// There's only three legal possibilities here:
 				// 1. Promotion: ADD_VOTER, REMOVE_NON_VOTER
 				// 2. Demotion: ADD_NON_VOTER, REMOVE_VOTER
+				// 3. Witness promotion: ADD_VOTER, REMOVE_WITNESS
 				//
 				// We reject everything else.
 				isPromotion := c1.ChangeType == roachpb.ADD_VOTER && c2.ChangeType == roachpb.REMOVE_NON_VOTER
 				isDemotion := c1.ChangeType == roachpb.ADD_NON_VOTER && c2.ChangeType == roachpb.REMOVE_VOTER
-				if !(isPromotion || isDemotion) {
+				isWitnessPromotion := c1.ChangeType == roachpb.ADD_VOTER && c2.ChangeType == roachpb.REMOVE_WITNESS
+				if !(isPromotion || isDemotion || isWitnessPromotion) {
 					return errors.AssertionFailedf("trying to add-remove the same replica(%s):"+
 						" %+v", replDesc.Type, chgs)
 				}
@@ -1686,7 +1777,9 @@ func getChangesByNodeID(chgs kvpb.ReplicationChanges) changesByNodeID {
 
 // initializeRaftLearners adds etcd LearnerNodes (LEARNERs or NON_VOTERs in
 // Cockroach-land) to the given replication targets and synchronously sends them
-// an initial snapshot to upreplicate. Once this successfully returns, the
+// an initial snapshot to upreplicate. Passing WITNESS as the replicaType adds
+// LEARNERs that are sent a log-only snapshot, to be promoted to witnesses by
+// the caller. Once this successfully returns, the
 // callers can assume that the learners were added and have been initialized via
 // that snapshot. Otherwise, if we get any errors trying to add or upreplicate
 // any of these learners, this function will clean up after itself by rolling all
@@ -1703,11 +1796,15 @@ func (r *Replica) initializeRaftLearners(
 	replicaType roachpb.ReplicaType,
 ) (afterDesc *roachpb.RangeDescriptor, err error) {
 	var iChangeType internalChangeType
+	learnerType := replicaType
 	switch replicaType {
 	case roachpb.LEARNER:
 		iChangeType = internalChangeTypeAddLearner
 	case roachpb.NON_VOTER:
 		iChangeType = internalChangeTypeAddNonVoter
+	case roachpb.WITNESS:
+		iChangeType = internalChangeTypeAddLearner
+		learnerType = roachpb.LEARNER
 	default:
 		log.Fatalf(ctx, "unexpected replicaType %s", replicaType)
 	}
@@ -1819,7 +1916,7 @@ func (r *Replica) initializeRaftLearners(
 			return nil, errors.Errorf("programming error: replica %v not found in %v", target, desc)
 		}
 
-		if rDesc.Type != replicaType {
+		if rDesc.Type != learnerType {
 			return nil, errors.Errorf("programming error: cannot promote replica of type %s", rDesc.Type)
 		}
 
@@ -1845,6 +1942,7 @@ func (r *Replica) initializeRaftLearners(
 		// these, it would be susceptible to future similar issues.
 		if err := r.sendSnapshotUsingDelegate(
 			ctx, rDesc, kvserverpb.SnapshotRequest_INITIAL, priority, senderName, senderQueuePriority,
+			replicaType == roachpb.WITNESS, /* logOnly */
 		); err != nil {
 			return nil, err
 		}
@@ -2021,6 +2119,14 @@ const (
 	// the type of replica being promoted. See `prepareChangeReplicasTrigger`.
 	internalChangeTypePromoteLearner
 	internalChangeTypePromoteNonVoter
+	// internalChangeTypePromoteLearnerToWitness turns a learner into a WITNESS,
+	// which is a voter as far as raft is concerned.
+	internalChangeTypePromoteLearnerToWitness
+	// internalChangeTypeRemoveWitness removes a WITNESS. Unlike voters, this
+	// is always a simple change: witnesses never hold the lease, and demoting
+	// one through joint consensus would turn it into a VOTER_DEMOTING_LEARNER
+	// that's no longer recognizable as log-only.
+	internalChangeTypeRemoveWitness
 	// internalChangeTypeDemoteVoterToLearner changes a voter to an ephemeral
 	// learner. This will necessarily go through joint consensus since it requires
 	// two individual changes (only one changes the quorum, so we could allow it
@@ -2111,6 +2217,21 @@ func prepareChangeReplicasTrigger(
 						chg.target)
 				}
 				added = append(added, rDesc)
+			case internalChangeTypePromoteLearnerToWitness:
+				rDesc, prevTyp, ok := updatedDesc.SetReplicaType(chg.target.NodeID, chg.target.StoreID, roachpb.WITNESS)
+				if !ok || prevTyp != roachpb.LEARNER {
+					return nil, errors.Errorf("cannot promote target %v which is missing as LEARNER",
+						chg.target)
+				}
+				added = append(added, rDesc)
+			case internalChangeTypeRemoveWitness:
+				rDesc, ok := updatedDesc.GetReplicaDescriptor(chg.target.StoreID)
+				if !ok || rDesc.Type != roachpb.WITNESS {
+					return nil, errors.Errorf("cannot remove target %v which is missing as WITNESS",
+						chg.target)
+				}
+				rDesc, _ = updatedDesc.RemoveReplica(chg.target.NodeID, chg.target.StoreID)
+				removed = append(removed, rDesc)
 			case internalChangeTypeRemoveLearner, internalChangeTypeRemoveNonVoter:
 				rDesc, ok := updatedDesc.GetReplicaDescriptor(chg.target.StoreID)
 				if !ok {
@@ -2679,6 +2800,10 @@ func (r *Replica) getSenderReplicas(
 // the voting replica directly, this avoids a period of fragility when the
 // replica would be a full member, but very far behind.
 //
+// Snapshots sent to WITNESS replicas, or to learners that are about to become
+// witnesses (as indicated by logOnly), only contain the range-ID local and
+// range-local keys, since witnesses don't hold the range's user data.
+//
 // The snapshot process itself is broken into 4 parts: delegating the request,
 // generating the snapshot, transmitting it, and applying it.
 //
@@ -2777,6 +2902,7 @@ func (r *Replica) sendSnapshotUsingDelegate(
 	priority kvserverpb.SnapshotRequest_Priority,
 	senderQueueName kvserverpb.SnapshotRequest_QueueName,
 	senderQueuePriority float64,
+	logOnly bool,
 ) (retErr error) {
 
 	defer func() {
@@ -2841,6 +2967,7 @@ func (r *Replica) sendSnapshotUsingDelegate(
 		DescriptorGeneration: r.Desc().Generation,
 		QueueOnDelegateLen:   MaxQueueOnDelegateLimit.Get(&r.ClusterSettings().SV),
 		SnapId:               snapUUID,
+		LogOnly:              logOnly || recipient.IsWitness(),
 	}
 
 	// Get the list of senders in order.
@@ -3139,7 +3266,7 @@ func (r *Replica) followerSendSnapshot(
 	// a snapshot for a non-system range. This allows us to send metadata of
 	// sstables in shared storage as opposed to streaming their contents. Keys
 	// in higher levels of the LSM are still streamed in the snapshot.
-	sharedReplicate := r.store.cfg.SharedStorageEnabled && snap.State.Desc.StartKey.AsRawKey().Compare(keys.TableDataMin) >= 0
+	sharedReplicate := r.store.cfg.SharedStorageEnabled && snap.State.Desc.StartKey.AsRawKey().Compare(keys.TableDataMin) >= 0 && !req.LogOnly
 
 	// Create new snapshot request header using the delegate snapshot request.
 	header := kvserverpb.SnapshotRequest_Header{
@@ -3164,6 +3291,7 @@ func (r *Replica) followerSendSnapshot(
 		DeprecatedStrategy:  kvserverpb.SnapshotRequest_KV_BATCH,
 		DeprecatedType:      req.DeprecatedType,
 		SharedReplicate:     sharedReplicate,
+		LogOnly:             req.LogOnly,
 	}
 	newBatchFn := func() storage.WriteBatch {
 		return r.store.TODOEngine().NewWriteBatch()
@@ -3229,19 +3357,26 @@ func (r *Replica) followerSendSnapshot(
 }
 
 // replicasCollocated is used in AdminMerge to ensure that the ranges are
-// all collocate on the same set of replicas.
+// all collocate on the same set of replicas. Witnesses don't hold the range's
+// data, so a store must hold a witness of either both ranges or neither: a
+// full replica of the merged range on a store which only held a witness of one
+// of its halves would be missing that half's data.
 func replicasCollocated(a, b []roachpb.ReplicaDescriptor) bool {
 	if len(a) != len(b) {
 		return false
 	}
 
-	set := make(map[roachpb.StoreID]int)
+	type placement struct {
+		storeID roachpb.StoreID
+		witness bool
+	}
+	set := make(map[placement]int)
 	for _, replica := range a {
-		set[replica.StoreID]++
+		set[placement{replica.StoreID, replica.IsWitness()}]++
 	}
 
 	for _, replica := range b {
-		set[replica.StoreID]--
+		set[placement{replica.StoreID, replica.IsWitness()}]--
 	}
 
 	for _, value := range set {
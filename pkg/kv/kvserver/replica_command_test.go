@@ -433,6 +433,8 @@ func TestSynthesizeTargetsByChangeType(t *testing.T) {
 		expPromotions, expDemotions               []int32
 		expVoterAdditions, expVoterRemovals       []int32
 		expNonVoterAdditions, expNonVoterRemovals []int32
+		expWitnessPromotions                      []int32
+		expWitnessAdditions, expWitnessRemovals   []int32
 	}
 
 	mkTarget := func(t int32) roachpb.ReplicationTarget {
@@ -594,6 +596,33 @@ func TestSynthesizeTargetsByChangeType(t *testing.T) {
 			expNonVoterAdditions: []int32{5},
 			expNonVoterRemovals:  []int32{6},
 		},
+		{
+			name: "simple witness addition",
+			changes: []kvpb.ReplicationChange{
+				{ChangeType: roachpb.ADD_WITNESS, Target: mkTarget(2)},
+			},
+			expWitnessAdditions: []int32{2},
+		},
+		{
+			name: "simple witness removal",
+			changes: []kvpb.ReplicationChange{
+				{ChangeType: roachpb.REMOVE_WITNESS, Target: mkTarget(2)},
+			},
+			expWitnessRemovals: []int32{2},
+		},
+		{
+			// Witnesses are promoted by removing them and adding a voter afresh, so
+			// the target also shows up as a voter addition.
+			name: "promote witness to voter",
+			changes: []kvpb.ReplicationChange{
+				{ChangeType: roachpb.ADD_VOTER, Target: mkTarget(2)},
+				{ChangeType: roachpb.REMOVE_WITNESS, Target: mkTarget(2)},
+				{ChangeType: roachpb.REMOVE_WITNESS, Target: mkTarget(3)},
+			},
+			expVoterAdditions:    []int32{2},
+			expWitnessPromotions: []int32{2},
+			expWitnessRemovals:   []int32{3},
+		},
 	}
 
 	for _, test := range tests {
@@ -605,6 +634,9 @@ func TestSynthesizeTargetsByChangeType(t *testing.T) {
 			require.Equal(t, result.VoterRemovals, mkTargetList(test.expVoterRemovals))
 			require.Equal(t, result.NonVoterAdditions, mkTargetList(test.expNonVoterAdditions))
 			require.Equal(t, result.NonVoterRemovals, mkTargetList(test.expNonVoterRemovals))
+			require.Equal(t, result.WitnessPromotions, mkTargetList(test.expWitnessPromotions))
+			require.Equal(t, result.WitnessAdditions, mkTargetList(test.expWitnessAdditions))
+			require.Equal(t, result.WitnessRemovals, mkTargetList(test.expWitnessRemovals))
 		})
 	}
 }
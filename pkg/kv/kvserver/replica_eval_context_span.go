@@ -182,6 +182,12 @@ func (rec SpanSetReplicaEvalContext) ExcludeDataFromBackup() bool {
 	return rec.i.ExcludeDataFromBackup()
 }
 
+// RowExpiry returns the storage level row expiry of the replica's span config,
+// if any.
+func (rec SpanSetReplicaEvalContext) RowExpiry() *roachpb.RowExpiry {
+	return rec.i.RowExpiry()
+}
+
 // String implements Stringer.
 func (rec SpanSetReplicaEvalContext) String() string {
 	return rec.i.String()
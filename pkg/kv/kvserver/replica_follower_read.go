@@ -12,6 +12,7 @@ package kvserver
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
@@ -19,6 +20,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/redact"
 )
 
@@ -34,6 +37,23 @@ var FollowerReadsEnabled = settings.RegisterBoolSetting(
 	settings.WithName("kv.closed_timestamp.follower_reads.enabled"),
 	settings.WithPublic)
 
+// FollowerReadsMaxWait controls how long a follower replica waits for its
+// closed timestamp to reach the timestamp of a follower read before giving up
+// and redirecting the read to the leaseholder. This is useful for reads that
+// must observe a recent write, e.g. reads that carry a causality token (see
+// the read_after_timestamp session variable), for which the closed timestamp
+// often lags by a small amount only. Reads whose timestamp is further ahead
+// of the closed timestamp than the maximum wait are redirected right away.
+var FollowerReadsMaxWait = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"kv.closed_timestamp.follower_reads.max_wait",
+	"maximum duration that a follower waits for its closed timestamp to reach the "+
+		"timestamp of a follower read before redirecting the read to the leaseholder; "+
+		"reads further ahead of the closed timestamp are redirected without waiting",
+	0,
+	settings.NonNegativeDuration,
+)
+
 // BatchCanBeEvaluatedOnFollower determines if a batch consists exclusively of
 // requests that can be evaluated on a follower replica, given a sufficiently
 // advanced closed timestamp.
@@ -129,6 +149,69 @@ func (r *Replica) canServeFollowerReadRLocked(ctx context.Context, ba *kvpb.Batc
 	return true
 }
 
+// maybeWaitForFollowerRead waits, for up to FollowerReadsMaxWait, for the
+// replica's closed timestamp to reach the timestamp of a batch that can be
+// served as a follower read, if the replica doesn't hold the lease. If the
+// closed timestamp doesn't get there in time, the batch proceeds as usual and
+// is redirected to the leaseholder.
+//
+// Since the closed timestamp advances at most as fast as the clock, the
+// replica only waits if the closed timestamp trails the batch by no more than
+// FollowerReadsMaxWait. Batches it couldn't serve in time anyway, e.g. reads
+// at the present time that were routed to the replica, are redirected right
+// away.
+func (r *Replica) maybeWaitForFollowerRead(ctx context.Context, ba *kvpb.BatchRequest) error {
+	maxWait := FollowerReadsMaxWait.Get(&r.store.cfg.Settings.SV)
+	if maxWait == 0 || !BatchCanBeEvaluatedOnFollower(ba) ||
+		!FollowerReadsEnabled.Get(&r.store.cfg.Settings.SV) {
+		return nil
+	}
+	requiredFrontier := ba.RequiredFrontier()
+	// NB: leaseholders don't need to wait, they serve the batch under the lease.
+	shouldWait := func() bool {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		if r.mu.state.Lease.OwnedBy(r.store.StoreID()) {
+			return false
+		}
+		repDesc, err := r.getReplicaDescriptorRLocked()
+		if err != nil {
+			return false
+		}
+		switch repDesc.Type {
+		case roachpb.VOTER_FULL, roachpb.VOTER_INCOMING, roachpb.NON_VOTER:
+		default:
+			return false
+		}
+		maxClosed := r.getCurrentClosedTimestampLocked(ctx, requiredFrontier /* sufficient */)
+		if requiredFrontier.LessEq(maxClosed) {
+			return false
+		}
+		if lag := requiredFrontier.GoTime().Sub(maxClosed.GoTime()); lag > maxWait {
+			log.VEventf(ctx, 2, "not waiting for closed timestamp %s to reach %s; it lags by %s",
+				maxClosed, requiredFrontier, lag)
+			return false
+		}
+		return true
+	}
+	if !shouldWait() {
+		return nil
+	}
+	log.VEventf(ctx, 2, "waiting up to %s for closed timestamp to reach %s", maxWait, requiredFrontier)
+	deadline := timeutil.Now().Add(maxWait)
+	opts := retry.Options{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     25 * time.Millisecond,
+		Multiplier:     2,
+	}
+	for re := retry.StartWithCtx(ctx, opts); re.Next(); {
+		if !shouldWait() || timeutil.Now().After(deadline) {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
 // getCurrentClosedTimestampRLocked is like GetCurrentClosedTimestamp, except
 // that it requires r.mu to be RLocked. It also optionally takes a hint: if
 // sufficient is not empty, getClosedTimestampRLocked might return a timestamp
@@ -1786,6 +1786,23 @@ func (r *Replica) sendRaftMessage(ctx context.Context, msg raftpb.Message) {
 		return
 	}
 
+	// Witnesses must never become leader, see isWitnessRLocked. Raft doesn't
+	// let us stop a voter from campaigning once its election timeout elapses, so
+	// drop the (pre)vote requests it sends, which keeps it from ever winning an
+	// election. Similarly, never hand leadership over to a witness.
+	switch msg.Type {
+	case raftpb.MsgPreVote, raftpb.MsgVote:
+		if fromReplica.IsWitness() {
+			log.VEventf(ctx, 3, "dropping %s from witness %s", msg.Type, fromReplica)
+			return
+		}
+	case raftpb.MsgTimeoutNow:
+		if toReplica.IsWitness() {
+			log.VEventf(ctx, 3, "dropping %s to witness %s", msg.Type, toReplica)
+			return
+		}
+	}
+
 	// Raft-initiated snapshots are handled by the Raft snapshot queue.
 	if msg.Type == raftpb.MsgSnap {
 		r.store.raftSnapshotQueue.AddAsync(ctx, r, raftSnapshotPriority)
@@ -2295,6 +2312,10 @@ func shouldCampaignOnLeaseRequestRedirect(
 // also grant any number of pre-votes, both for themselves and anyone else
 // that's eligible.
 func (r *Replica) campaignLocked(ctx context.Context) {
+	if r.isWitnessRLocked() {
+		log.VEventf(ctx, 3, "not campaigning as a witness")
+		return
+	}
 	log.VEventf(ctx, 3, "campaigning")
 	if err := r.mu.internalRaftGroup.Campaign(); err != nil {
 		log.VEventf(ctx, 1, "failed to campaign: %s", err)
@@ -2311,6 +2332,10 @@ func (r *Replica) campaignLocked(ctx context.Context) {
 // caller is certain that the current leader is actually dead, and we're not
 // simply partitioned away from it and/or liveness.
 func (r *Replica) forceCampaignLocked(ctx context.Context) {
+	if r.isWitnessRLocked() {
+		log.VEventf(ctx, 3, "not force campaigning as a witness")
+		return
+	}
 	log.VEventf(ctx, 3, "force campaigning")
 	msg := raftpb.Message{To: uint64(r.replicaID), Type: raftpb.MsgTimeoutNow}
 	if err := r.mu.internalRaftGroup.Step(msg); err != nil {
@@ -2319,6 +2344,14 @@ func (r *Replica) forceCampaignLocked(ctx context.Context) {
 	r.store.enqueueRaftUpdateCheck(r.RangeID)
 }
 
+// isWitnessRLocked returns whether the local replica is a WITNESS. Witnesses
+// vote, but only apply the range's metadata and thus must never become the raft
+// leader, since the leader is responsible for sending snapshots to followers.
+func (r *Replica) isWitnessRLocked() bool {
+	repDesc, ok := r.mu.state.Desc.GetReplicaDescriptorByID(r.replicaID)
+	return ok && repDesc.IsWitness()
+}
+
 // forgetLeaderLocked forgets a follower's current raft leader, remaining a
 // leaderless follower in the current term. The replica will not campaign unless
 // the election timeout elapses. However, this allows it to grant (pre)votes if
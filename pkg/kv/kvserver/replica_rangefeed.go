@@ -217,6 +217,11 @@ func (r *Replica) RangeFeed(
 		return future.MakeCompletedErrorFuture(err.GoError())
 	}
 
+	columnFilter, err := rangefeed.NewColumnFilter(args.ColumnFilter)
+	if err != nil {
+		return future.MakeCompletedErrorFuture(err)
+	}
+
 	// If the RangeFeed is performing a catch-up scan then it will observe all
 	// values above args.Timestamp. If the RangeFeed is requesting previous
 	// values for every update then it will also need to look for the version
@@ -286,7 +291,7 @@ func (r *Replica) RangeFeed(
 	}
 	var done future.ErrorFuture
 	p := r.registerWithRangefeedRaftMuLocked(
-		ctx, rSpan, args.Timestamp, catchUpIter, args.WithDiff, lockedStream, &done,
+		ctx, rSpan, args.Timestamp, catchUpIter, args.WithDiff, columnFilter, lockedStream, &done,
 	)
 	r.raftMu.Unlock()
 
@@ -382,6 +387,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	startTS hlc.Timestamp, // exclusive
 	catchUpIter *rangefeed.CatchUpIterator,
 	withDiff bool,
+	columnFilter *rangefeed.ColumnFilter,
 	stream rangefeed.Stream,
 	done *future.ErrorFuture,
 ) rangefeed.Processor {
@@ -403,7 +409,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	p := r.rangefeedMu.proc
 
 	if p != nil {
-		reg, filter := p.Register(span, startTS, catchUpIter, withDiff, stream, func() { r.maybeDisconnectEmptyRangefeed(p) }, done)
+		reg, filter := p.Register(span, startTS, catchUpIter, withDiff, columnFilter, stream, func() { r.maybeDisconnectEmptyRangefeed(p) }, done)
 		if reg {
 			// Registered successfully with an existing processor.
 			// Update the rangefeed filter to avoid filtering ops
@@ -489,7 +495,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	// any other goroutines are able to stop the processor. In other words,
 	// this ensures that the only time the registration fails is during
 	// server shutdown.
-	reg, filter := p.Register(span, startTS, catchUpIter, withDiff, stream, func() { r.maybeDisconnectEmptyRangefeed(p) }, done)
+	reg, filter := p.Register(span, startTS, catchUpIter, withDiff, columnFilter, stream, func() { r.maybeDisconnectEmptyRangefeed(p) }, done)
 	if !reg {
 		select {
 		case <-r.store.Stopper().ShouldQuiesce():
@@ -162,6 +162,11 @@ func (r *Replica) SendWithWriteBytes(
 	if err := r.maybeCommitWaitBeforeCommitTrigger(ctx, ba); err != nil {
 		return nil, nil, kvpb.NewError(err)
 	}
+	if isReadOnly {
+		if err := r.maybeWaitForFollowerRead(ctx, ba); err != nil {
+			return nil, nil, kvpb.NewError(err)
+		}
+	}
 
 	// NB: must be performed before collecting request spans.
 	ba, err := maybeStripInFlightWrites(ba)
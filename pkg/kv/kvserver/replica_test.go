@@ -6614,6 +6614,15 @@ func TestConditionFailedError(t *testing.T) {
 func TestReplicaSetsEqual(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
+	// withWitness turns the replica on the given store into a witness.
+	withWitness := func(rs []roachpb.ReplicaDescriptor, storeID roachpb.StoreID) []roachpb.ReplicaDescriptor {
+		for i := range rs {
+			if rs[i].StoreID == storeID {
+				rs[i].Type = roachpb.WITNESS
+			}
+		}
+		return rs
+	}
 	testData := []struct {
 		expected bool
 		a        []roachpb.ReplicaDescriptor
@@ -6631,6 +6640,9 @@ func TestReplicaSetsEqual(t *testing.T) {
 		{true, createReplicaSets([]roachpb.StoreID{1, 1}), createReplicaSets([]roachpb.StoreID{1, 1})},
 		{false, createReplicaSets([]roachpb.StoreID{1, 1}), createReplicaSets([]roachpb.StoreID{1, 1, 1})},
 		{true, createReplicaSets([]roachpb.StoreID{1, 2, 3, 1, 2, 3}), createReplicaSets([]roachpb.StoreID{1, 1, 2, 2, 3, 3})},
+		{true, withWitness(createReplicaSets([]roachpb.StoreID{1, 2, 3}), 3), withWitness(createReplicaSets([]roachpb.StoreID{3, 2, 1}), 3)},
+		{false, withWitness(createReplicaSets([]roachpb.StoreID{1, 2, 3}), 3), createReplicaSets([]roachpb.StoreID{1, 2, 3})},
+		{false, withWitness(createReplicaSets([]roachpb.StoreID{1, 2, 3}), 3), withWitness(createReplicaSets([]roachpb.StoreID{1, 2, 3}), 2)},
 	}
 	for _, test := range testData {
 		if replicasCollocated(test.a, test.b) != test.expected {
@@ -183,7 +183,7 @@ func (r *Replica) updateTimestampCache(
 				tombstone = false
 			case roachpb.ABORTED:
 				tombstone = true
-			case roachpb.STAGING:
+			case roachpb.STAGING, roachpb.PREPARED:
 				// No need to update the timestamp cache. If a transaction
 				// is in this state then it must have a transaction record.
 				continue
@@ -965,6 +965,7 @@ func (s *Store) updateCapacityGauges(ctx context.Context) error {
 	s.metrics.Capacity.Update(desc.Capacity.Capacity)
 	s.metrics.Available.Update(desc.Capacity.Available)
 	s.metrics.Used.Update(desc.Capacity.Used)
+	s.metrics.Remote.Update(desc.Capacity.RemoteBytes)
 
 	return nil
 }
@@ -762,7 +762,12 @@ func (kvSS *kvBatchSnapshotStrategy) Send(
 	// of data we're iterating on and sending over the network.
 	sharedReplicate := header.SharedReplicate
 	replicatedFilter := rditer.ReplicatedSpansAll
-	if sharedReplicate {
+	if header.LogOnly {
+		// WITNESS replicas don't hold user keys or locks, so there's nothing to
+		// send beyond the range's metadata.
+		sharedReplicate = false
+		replicatedFilter = rditer.ReplicatedSpansRangeLocalOnly
+	} else if sharedReplicate {
 		replicatedFilter = rditer.ReplicatedSpansExcludeUser
 	}
 
@@ -677,6 +677,15 @@ func (ts *testState) HasProcessDebugCapability(ctx context.Context, tenID roachp
 	}
 }
 
+func (ts *testState) HasReadAccessToTenant(
+	ctx context.Context, tenID, otherTenID roachpb.TenantID,
+) error {
+	if tenID == otherTenID || ts.capabilities[tenID].ReadFromTenant == otherTenID {
+		return nil
+	}
+	return errors.New("unauthorized")
+}
+
 func (ts *testState) HasNodeStatusCapability(_ context.Context, tenID roachpb.TenantID) error {
 	if ts.capabilities[tenID].CanViewNodeInfo {
 		return nil
@@ -803,3 +812,9 @@ func (fakeAuthorizer) BindReader(tenantcapabilities.Reader) {}
 func (fakeAuthorizer) HasProcessDebugCapability(ctx context.Context, tenID roachpb.TenantID) error {
 	return nil
 }
+
+func (fakeAuthorizer) HasReadAccessToTenant(
+	ctx context.Context, tenID, otherTenID roachpb.TenantID,
+) error {
+	return nil
+}
@@ -105,6 +105,10 @@ func CanPushWithPriority(
 		}
 	}
 	pusherPri, pusheePri = normalize(pusherPri), normalize(pusheePri)
+	// A PREPARED pushee can not be pushed, regardless of priorities.
+	if pusheeStatus == roachpb.PREPARED && pushType != kvpb.PUSH_TOUCH {
+		return false
+	}
 	switch pushType {
 	case kvpb.PUSH_ABORT:
 		return pusherPri > pusheePri
@@ -149,8 +153,12 @@ func TxnExpiration(txn *roachpb.Transaction) hlc.Timestamp {
 	return txn.LastActive().Add(TxnLivenessThreshold.Load(), 0)
 }
 
-// IsExpired is true if the given transaction is expired.
+// IsExpired is true if the given transaction is expired. A PREPARED
+// transaction is not heartbeated by its coordinator and never expires.
 func IsExpired(now hlc.Timestamp, txn *roachpb.Transaction) bool {
+	if txn.Status == roachpb.PREPARED {
+		return false
+	}
 	return TxnExpiration(txn).Less(now)
 }
 
@@ -702,7 +710,13 @@ func (q *Queue) waitForPush(
 				log.VEventf(ctx, 1, "pushing expired txn %s", req.PusheeTxn.ID.Short())
 				return nil, nil
 			}
-			// Set the timer to check for the pushee txn's expiration.
+			// Set the timer to check for the pushee txn's expiration. A prepared
+			// pushee never expires, so we just check on it again after the
+			// liveness threshold.
+			if updatedPushee.Status == roachpb.PREPARED {
+				pusheeTxnTimer.Reset(time.Duration(TxnLivenessThreshold.Load()))
+				break
+			}
 			expiration := TxnExpiration(updatedPushee).GoTime()
 			now := q.cfg.Clock.Now().GoTime()
 			pusheeTxnTimer.Reset(expiration.Sub(now))
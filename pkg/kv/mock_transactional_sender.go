@@ -204,6 +204,9 @@ func (m *MockTransactionalSender) ReleaseSavepoint(context.Context, SavepointTok
 // Epoch is part of the TxnSender interface.
 func (m *MockTransactionalSender) Epoch() enginepb.TxnEpoch { panic("unimplemented") }
 
+// TxnMeta is part of the TxnSender interface.
+func (m *MockTransactionalSender) TxnMeta() enginepb.TxnMeta { return m.txn.TxnMeta }
+
 // IsLocking is part of the TxnSender interface.
 func (m *MockTransactionalSender) IsLocking() bool { return false }
 
@@ -285,6 +285,10 @@ type TxnSender interface {
 	// Epoch returns the txn's epoch.
 	Epoch() enginepb.TxnEpoch
 
+	// TxnMeta returns the txn's current TxnMeta. Its key is nil until the txn
+	// acquires its first lock.
+	TxnMeta() enginepb.TxnMeta
+
 	// IsLocking returns whether the transaction has begun acquiring locks.
 	IsLocking() bool
 
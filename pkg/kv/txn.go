@@ -247,6 +247,14 @@ func (txn *Txn) Epoch() enginepb.TxnEpoch {
 	return txn.mu.sender.Epoch()
 }
 
+// TxnMeta returns the txn's current TxnMeta. Its key is nil until the txn
+// acquires its first lock, and it doesn't change after that.
+func (txn *Txn) TxnMeta() enginepb.TxnMeta {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.TxnMeta()
+}
+
 // statusLocked returns the txn proto status field.
 func (txn *Txn) statusLocked() roachpb.TransactionStatus {
 	return txn.mu.sender.TxnStatus()
@@ -807,6 +815,41 @@ func (txn *Txn) CommitInBatch(ctx context.Context, b *Batch) error {
 	return txn.Run(ctx, b)
 }
 
+// Prepare sends an EndTxnRequest with Commit=true and Prepare=true. A prepared
+// transaction's writes remain locked until the transaction is committed or
+// rolled back through DB.CommitPrepared or DB.RollbackPrepared, which may be
+// invoked by any client. Once prepared, the txn is considered finalized and
+// cannot be used to send any more commands, including a Rollback.
+//
+// The returned TxnMeta identifies the prepared transaction. Its key is nil if
+// the transaction acquired no locks, in which case there is nothing left to
+// commit or roll back.
+func (txn *Txn) Prepare(ctx context.Context) (enginepb.TxnMeta, error) {
+	if txn.typ != RootTxn {
+		return enginepb.TxnMeta{}, errors.WithContextTags(
+			errors.AssertionFailedf("Prepare() called on leaf txn"), ctx)
+	}
+
+	et := endTxnReq(true, txn.deadline())
+	et.req.Prepare = true
+	ba := &kvpb.BatchRequest{Requests: et.unionArr[:]}
+	br, pErr := txn.Send(ctx, ba)
+	if pErr != nil {
+		return enginepb.TxnMeta{}, pErr.GoError()
+	}
+	if br == nil || br.Txn == nil {
+		return enginepb.TxnMeta{ID: txn.ID()}, nil
+	}
+	return br.Txn.TxnMeta, nil
+}
+
+// IsPrepared returns true iff the transaction has the prepared status.
+func (txn *Txn) IsPrepared() bool {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.statusLocked() == roachpb.PREPARED
+}
+
 // UpdateDeadline sets the transactions deadline to the passed deadline.
 // It may move the deadline to any timestamp above the current read timestamp.
 // If the deadline is below the current provisional commit timestamp (write timestamp),
@@ -1698,6 +1741,13 @@ func (txn *Txn) AdmissionHeader() kvpb.AdmissionHeader {
 	return h
 }
 
+// SetAdmissionResourceGroup sets the resource group, within the tenant, that
+// the work done in the context of this transaction is admitted under. It must
+// be called before the transaction is used.
+func (txn *Txn) SetAdmissionResourceGroup(rg admissionpb.ResourceGroup) {
+	txn.admissionHeader.ResourceGroup = rg
+}
+
 // OnePCNotAllowedError signifies that a request had the Require1PC flag set,
 // but 1PC evaluation was not possible for one reason or another.
 type OnePCNotAllowedError struct{}
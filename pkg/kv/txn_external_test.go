@@ -583,6 +583,88 @@ func TestRevScanAndGet(t *testing.T) {
 	}
 }
 
+// TestPreparedTxn verifies that a prepared transaction holds on to its locks
+// after its coordinator has given up control of it, and that it can later be
+// committed or rolled back through the DB.
+func TestPreparedTxn(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	ts, _, db := serverutils.StartServer(t, base.TestServerArgs{})
+	defer ts.Stopper().Stop(ctx)
+	k, err := ts.ScratchRange()
+	require.NoError(t, err)
+	mkKey := func(s string) roachpb.Key {
+		return encoding.EncodeStringAscending(k[:len(k):len(k)], s)
+	}
+	checkKey := func(t *testing.T, s string, exp int64) {
+		got, err := db.Get(ctx, mkKey(s))
+		require.NoError(t, err)
+		require.Equal(t, exp, got.ValueInt())
+	}
+	prepare := func(t *testing.T, val int64) enginepb.TxnMeta {
+		txn := db.NewTxn(ctx, "test")
+		require.NoError(t, txn.Put(ctx, mkKey("a"), val))
+		require.NoError(t, txn.Put(ctx, mkKey("b"), val))
+		meta, err := txn.Prepare(ctx)
+		require.NoError(t, err)
+		require.True(t, txn.IsPrepared())
+		require.NotNil(t, meta.Key)
+		// The coordinator can no longer roll back the prepared transaction.
+		require.Error(t, txn.Rollback(ctx))
+		return meta
+	}
+	// A conflicting request can't push a prepared transaction, even with a
+	// high priority.
+	checkBlocked := func(t *testing.T) {
+		timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		err := db.Txn(timeoutCtx, func(ctx context.Context, txn *kv.Txn) error {
+			if err := txn.SetUserPriority(roachpb.MaxUserPriority); err != nil {
+				return err
+			}
+			_, err := txn.Get(ctx, mkKey("a"))
+			return err
+		})
+		require.Error(t, err)
+	}
+
+	t.Run("commit", func(t *testing.T) {
+		meta := prepare(t, 1)
+		checkBlocked(t)
+		require.NoError(t, db.CommitPrepared(ctx, meta))
+		checkKey(t, "a", 1)
+		checkKey(t, "b", 1)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		meta := prepare(t, 2)
+		checkBlocked(t)
+		require.NoError(t, db.RollbackPrepared(ctx, meta))
+		checkKey(t, "a", 1)
+		checkKey(t, "b", 1)
+		require.Error(t, db.CommitPrepared(ctx, meta))
+	})
+
+	// A transaction whose coordinator recorded it but did not prepare it yet
+	// can't be committed, but can be rolled back, after which it can no longer
+	// be prepared.
+	t.Run("unprepared", func(t *testing.T) {
+		txn := db.NewTxn(ctx, "test")
+		require.NoError(t, txn.Put(ctx, mkKey("a"), 3))
+		meta := txn.TxnMeta()
+		require.NotNil(t, meta.Key)
+		require.Error(t, db.CommitPrepared(ctx, meta))
+		require.NoError(t, db.RollbackPrepared(ctx, meta))
+		_, err := txn.Prepare(ctx)
+		require.Error(t, err)
+		require.NoError(t, txn.Rollback(ctx))
+		checkKey(t, "a", 1)
+		// Rolling back an aborted transaction is a no-op.
+		require.NoError(t, db.RollbackPrepared(ctx, meta))
+	})
+}
+
 // TestGenerateForcedRetryableErrorAfterRollback poisons the txn handle and then
 // rolls it back. This should fail - a retryable error should not be returned
 // after a rollback.
@@ -75,6 +75,10 @@ type Authorizer interface {
 	// HasProcessDebugCapability returns an error if a tenant, referenced by its ID,
 	// is not allowed to debug the running process.
 	HasProcessDebugCapability(ctx context.Context, tenID roachpb.TenantID) error
+
+	// HasReadAccessToTenant returns an error if a tenant, referenced by its ID,
+	// is not allowed to read the keyspace of another tenant.
+	HasReadAccessToTenant(ctx context.Context, tenID, otherTenID roachpb.TenantID) error
 }
 
 // Entry ties together a tenantID with its capabilities.
@@ -16,6 +16,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/errors"
 )
 
 // AllowEverythingAuthorizer is a tenantcapabilities.Authorizer that
@@ -73,3 +74,15 @@ func (n *AllowEverythingAuthorizer) HasProcessDebugCapability(
 ) error {
 	return nil
 }
+
+// HasReadAccessToTenant implements the tenantcapabilities.Authorizer interface.
+//
+// Tenants are still confined to their own keyspace.
+func (n *AllowEverythingAuthorizer) HasReadAccessToTenant(
+	ctx context.Context, tenID, otherTenID roachpb.TenantID,
+) error {
+	if tenID.IsSystem() || tenID == otherTenID {
+		return nil
+	}
+	return errors.New("operation blocked")
+}
@@ -72,3 +72,10 @@ func (n *AllowNothingAuthorizer) HasProcessDebugCapability(
 ) error {
 	return errors.New("operation blocked")
 }
+
+// HasReadAccessToTenant implements the tenantcapabilities.Authorizer interface.
+func (n *AllowNothingAuthorizer) HasReadAccessToTenant(
+	ctx context.Context, tenID, otherTenID roachpb.TenantID,
+) error {
+	return errors.New("operation blocked")
+}
@@ -420,3 +420,33 @@ func (a *Authorizer) HasProcessDebugCapability(ctx context.Context, tenID roachp
 	}
 	return nil
 }
+
+// HasReadAccessToTenant implements the tenantcapabilities.Authorizer interface.
+func (a *Authorizer) HasReadAccessToTenant(
+	ctx context.Context, tenID, otherTenID roachpb.TenantID,
+) error {
+	if tenID.IsSystem() || tenID == otherTenID {
+		return nil
+	}
+	errFn := func() error {
+		return errors.Newf("client tenant does not have capability to read from tenant %s", otherTenID)
+	}
+	cp, mode := a.getMode(ctx, tenID)
+	switch mode {
+	case authorizerModeOn:
+		break // fallthrough to the next check.
+	case authorizerModeAllowAll, authorizerModeV222:
+		// Reading another tenant's keyspace crosses the isolation boundary
+		// between tenants, so it is never allowed without the capability.
+		return errFn()
+	default:
+		err := errors.AssertionFailedf("unknown authorizer mode: %d", mode)
+		logcrash.ReportOrPanic(ctx, &a.settings.SV, "%v", err)
+		return err
+	}
+
+	if cp.ReadFromTenant != otherTenID {
+		return errFn()
+	}
+	return nil
+}
@@ -1262,6 +1262,10 @@ func (t *Transaction) Update(o *Transaction) {
 			if o.Status != PENDING {
 				t.Status = o.Status
 			}
+		case PREPARED:
+			if o.Status.IsFinalized() {
+				t.Status = o.Status
+			}
 		case ABORTED:
 			if o.Status == COMMITTED {
 				log.Warningf(ctx, "updating ABORTED txn %s with COMMITTED txn %s", t.String(), o.String())
@@ -1598,7 +1602,7 @@ func confChangeImpl(
 			if err := checkNotExists(rDesc); err != nil {
 				return nil, err
 			}
-		case VOTER_FULL:
+		case VOTER_FULL, WITNESS:
 			// A voter can't be in the descriptor if it's being removed.
 			if err := checkNotExists(rDesc); err != nil {
 				return nil, err
@@ -1625,6 +1629,10 @@ func confChangeImpl(
 			// We're adding a voter, but will transition into a joint config
 			// first.
 			changeType = raftpb.ConfChangeAddNode
+		case WITNESS:
+			// We're adding a witness, which is a voter as far as raft is
+			// concerned.
+			changeType = raftpb.ConfChangeAddNode
 		case LEARNER, NON_VOTER:
 			// We're adding a learner or non-voter.
 			// Note that we're guaranteed by virtue of the upstream ChangeReplicas txn
@@ -416,6 +416,32 @@ func (r ReplicaDescriptor) Validate() error {
 // SafeValue implements the redact.SafeValue interface.
 func (r ReplicaType) SafeValue() {}
 
+// ParseStorageTier parses the (case-insensitive) name of a storage tier.
+func ParseStorageTier(s string) (StorageTier, error) {
+	t, ok := StorageTier_value[strings.ToUpper(s)]
+	if !ok {
+		return 0, errors.Errorf("unknown storage tier %q", s)
+	}
+	return StorageTier(t), nil
+}
+
+// Admits returns whether a store of the given tier is a valid placement target
+// for a span configured with the receiver tier.
+func (t StorageTier) Admits(store StorageTier) bool {
+	switch t {
+	case StorageTier_ANY:
+		return true
+	case StorageTier_HOT:
+		// Stores that don't report a tier keep their data locally.
+		return store != StorageTier_COLD
+	default:
+		return store == t
+	}
+}
+
+// SafeValue implements the redact.SafeValue interface.
+func (t StorageTier) SafeValue() {}
+
 // GetReplicaDescriptorByID returns the replica which matches the specified
 // replica ID.
 func (r ReplicaSet) GetReplicaDescriptorByID(id ReplicaID) (repDesc ReplicaDescriptor, found bool) {
@@ -465,6 +491,12 @@ func (r ReplicaDescriptor) IsAnyVoter() bool {
 	}
 }
 
+// IsWitness returns true if the replica is a witness. Can be used as a filter
+// for ReplicaDescriptors.Filter.
+func (r ReplicaDescriptor) IsWitness() bool {
+	return r.Type == WITNESS
+}
+
 // IsNonVoter returns true if the replica is a non-voter. Can be used as a
 // filter for ReplicaDescriptors.Filter.
 func (r ReplicaDescriptor) IsNonVoter() bool {
@@ -89,6 +89,10 @@ func predNonVoter(rDesc ReplicaDescriptor) bool {
 	return rDesc.Type == NON_VOTER
 }
 
+func predWitness(rDesc ReplicaDescriptor) bool {
+	return rDesc.Type == WITNESS
+}
+
 func predVoterOrNonVoter(rDesc ReplicaDescriptor) bool {
 	return predVoterFullOrIncoming(rDesc) || predNonVoter(rDesc)
 }
@@ -226,6 +230,20 @@ func (d ReplicaSet) NonVoterDescriptors() []ReplicaDescriptor {
 	return d.FilterToDescriptors(predNonVoter)
 }
 
+// Witnesses returns a ReplicaSet containing only the witnesses in `d`.
+// Witnesses vote in raft and persist the log, but they only apply range-local
+// state and don't hold a copy of the range's user data. They are therefore excluded from Voters() and can
+// neither hold the lease nor serve follower reads, but they do count towards
+// the range's quorum (see ConfState and ReplicationStatus).
+func (d ReplicaSet) Witnesses() ReplicaSet {
+	return d.Filter(predWitness)
+}
+
+// WitnessDescriptors returns the witness replica descriptors in the set.
+func (d ReplicaSet) WitnessDescriptors() []ReplicaDescriptor {
+	return d.FilterToDescriptors(predWitness)
+}
+
 // VoterFullAndNonVoterDescriptors returns the descriptors of
 // VOTER_FULL/NON_VOTER replicas in the set. This set will not contain learners
 // or, during an atomic replication change, incoming or outgoing voters.
@@ -322,7 +340,7 @@ func (d ReplicaSet) InAtomicReplicationChange() bool {
 		case VOTER_INCOMING, VOTER_OUTGOING, VOTER_DEMOTING_LEARNER,
 			VOTER_DEMOTING_NON_VOTER:
 			return true
-		case VOTER_FULL, LEARNER, NON_VOTER:
+		case VOTER_FULL, LEARNER, NON_VOTER, WITNESS:
 		default:
 			panic(fmt.Sprintf("unknown replica type %d", rDesc.Type))
 		}
@@ -340,7 +358,9 @@ func (d ReplicaSet) ConfState() raftpb.ConfState {
 	for _, rep := range d.wrapped {
 		id := uint64(rep.ReplicaID)
 		switch rep.Type {
-		case VOTER_FULL:
+		case VOTER_FULL, WITNESS:
+			// Witnesses are regular raft voters as far as quorum is concerned;
+			// they never campaign and are never eligible for the lease.
 			cs.Voters = append(cs.Voters, id)
 			if joint {
 				cs.VotersOutgoing = append(cs.VotersOutgoing, id)
@@ -389,13 +409,13 @@ type RangeStatusReport struct {
 	Available bool
 	// UnderReplicated is set if the range is considered under-replicated
 	// according to the desired replication factor and the replica liveness info
-	// passed to ReplicationStatus. Only voting replicas are counted here. Dead
-	// replicas are considered to be missing.
+	// passed to ReplicationStatus. Only voting replicas (including witnesses)
+	// are counted here. Dead replicas are considered to be missing.
 	UnderReplicated bool
 	// OverReplicated is set if the range is considered over-replicated
 	// according to the desired replication factor passed to ReplicationStatus.
-	// Only voting replicas are counted here. Replica liveness is not
-	// considered.
+	// Only voting replicas (including witnesses) are counted here. Replica
+	// liveness is not considered.
 	//
 	// Note that a range can be under-replicated and over-replicated at the same
 	// time if it has many replicas, but sufficiently many of them are on dead
@@ -432,15 +452,28 @@ func (d ReplicaSet) ReplicationStatus(
 	// outgoing group ("old") and the incoming group ("new"). In the regular case,
 	// the two groups will be identical.
 
-	votersOldGroup := d.FilterToDescriptors(ReplicaDescriptor.IsVoterOldConfig)
-	liveVotersOldGroup := d.FilterToDescriptors(isBoth(ReplicaDescriptor.IsVoterOldConfig, liveFunc))
+	// isEither takes two replica predicates and returns their disjunction.
+	isEither := func(
+		pred1 func(rDesc ReplicaDescriptor) bool,
+		pred2 func(rDesc ReplicaDescriptor) bool) func(ReplicaDescriptor) bool {
+		return func(rDesc ReplicaDescriptor) bool {
+			return pred1(rDesc) || pred2(rDesc)
+		}
+	}
+	// Witnesses are never part of a joint config transition, so they count
+	// towards the quorum of both the outgoing and the incoming group.
+	isQuorumOldConfig := isEither(ReplicaDescriptor.IsVoterOldConfig, ReplicaDescriptor.IsWitness)
+	isQuorumNewConfig := isEither(ReplicaDescriptor.IsVoterNewConfig, ReplicaDescriptor.IsWitness)
+
+	votersOldGroup := d.FilterToDescriptors(isQuorumOldConfig)
+	liveVotersOldGroup := d.FilterToDescriptors(isBoth(isQuorumOldConfig, liveFunc))
 
 	n := len(votersOldGroup)
 	// Empty groups succeed by default, to match the Raft implementation.
 	availableOutgoingGroup := (n == 0) || (len(liveVotersOldGroup) >= n/2+1)
 
-	votersNewGroup := d.FilterToDescriptors(ReplicaDescriptor.IsVoterNewConfig)
-	liveVotersNewGroup := d.FilterToDescriptors(isBoth(ReplicaDescriptor.IsVoterNewConfig, liveFunc))
+	votersNewGroup := d.FilterToDescriptors(isQuorumNewConfig)
+	liveVotersNewGroup := d.FilterToDescriptors(isBoth(isQuorumNewConfig, liveFunc))
 
 	n = len(votersNewGroup)
 	availableIncomingGroup := len(liveVotersNewGroup) >= n/2+1
@@ -506,9 +539,9 @@ func (d ReplicaSet) Subtract(o ReplicaSet) []ReplicaDescriptor {
 // IsAddition returns true if `c` refers to a replica addition operation.
 func (c ReplicaChangeType) IsAddition() bool {
 	switch c {
-	case ADD_NON_VOTER, ADD_VOTER:
+	case ADD_NON_VOTER, ADD_VOTER, ADD_WITNESS:
 		return true
-	case REMOVE_NON_VOTER, REMOVE_VOTER:
+	case REMOVE_NON_VOTER, REMOVE_VOTER, REMOVE_WITNESS:
 		return false
 	default:
 		panic(fmt.Sprintf("unexpected ReplicaChangeType %s", c))
@@ -518,9 +551,9 @@ func (c ReplicaChangeType) IsAddition() bool {
 // IsRemoval returns true if `c` refers a replica removal operation.
 func (c ReplicaChangeType) IsRemoval() bool {
 	switch c {
-	case ADD_NON_VOTER, ADD_VOTER:
+	case ADD_NON_VOTER, ADD_VOTER, ADD_WITNESS:
 		return false
-	case REMOVE_NON_VOTER, REMOVE_VOTER:
+	case REMOVE_NON_VOTER, REMOVE_VOTER, REMOVE_WITNESS:
 		return true
 	default:
 		panic(fmt.Sprintf("unexpected ReplicaChangeType %s", c))
@@ -182,6 +182,16 @@ func TestReplicaDescriptorsConfState(t *testing.T) {
 			[]ReplicaDescriptor{rd(VOTER_OUTGOING, 1), rd(VOTER_DEMOTING_LEARNER, 2), rd(VOTER_INCOMING, 3), rd(VOTER_INCOMING, 4), rd(LEARNER, 5)},
 			"Voters:[3 4] VotersOutgoing:[1 2] Learners:[5] LearnersNext:[2] AutoLeave:false",
 		},
+		// Witnesses are voters as far as raft is concerned.
+		{
+			[]ReplicaDescriptor{rd(VOTER_FULL, 1), rd(VOTER_FULL, 2), rd(WITNESS, 3)},
+			"Voters:[1 2 3] VotersOutgoing:[] Learners:[] LearnersNext:[] AutoLeave:false",
+		},
+		// Adding a voter to a range with a witness.
+		{
+			[]ReplicaDescriptor{rd(VOTER_FULL, 1), rd(WITNESS, 2), rd(VOTER_INCOMING, 3)},
+			"Voters:[1 2 3] VotersOutgoing:[1 2] Learners:[] LearnersNext:[] AutoLeave:false",
+		},
 	}
 
 	for _, test := range tests {
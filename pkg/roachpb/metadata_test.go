@@ -465,6 +465,29 @@ func TestAddTier(t *testing.T) {
 	require.Equal(t, l3, l2.AddTier(Tier{Key: "bar", Value: "foo"}))
 }
 
+func TestStorageTier(t *testing.T) {
+	for _, tc := range []struct {
+		tier                  string
+		admitsHot, admitsCold bool
+		admitsUnreportedTiers bool
+	}{
+		{"any", true, true, true},
+		{"hot", true, false, true},
+		{"COLD", false, true, false},
+	} {
+		t.Run(tc.tier, func(t *testing.T) {
+			tier, err := ParseStorageTier(tc.tier)
+			require.NoError(t, err)
+			require.Equal(t, tc.admitsHot, tier.Admits(StorageTier_HOT))
+			require.Equal(t, tc.admitsCold, tier.Admits(StorageTier_COLD))
+			require.Equal(t, tc.admitsUnreportedTiers, tier.Admits(StorageTier_ANY))
+		})
+	}
+
+	_, err := ParseStorageTier("lukewarm")
+	require.Error(t, err)
+}
+
 func TestGCHint(t *testing.T) {
 	var empty hlc.Timestamp
 	ts1, ts2, ts3 := makeTS(1234, 2), makeTS(2345, 0), makeTS(3456, 10)
@@ -88,6 +88,12 @@ func (s *SpanConfig) ValidateSystemTargetSpanConfig() error {
 	if s.NumVoters != 0 {
 		return errors.AssertionFailedf("NumVoters set on system span config")
 	}
+	if s.NumWitnesses != 0 {
+		return errors.AssertionFailedf("NumWitnesses set on system span config")
+	}
+	if s.StorageTier != StorageTier_ANY {
+		return errors.AssertionFailedf("StorageTier set on system span config")
+	}
 	if len(s.Constraints) != 0 {
 		return errors.AssertionFailedf("Constraints set on system span config")
 	}
@@ -103,6 +109,9 @@ func (s *SpanConfig) ValidateSystemTargetSpanConfig() error {
 	if s.ExcludeDataFromBackup {
 		return errors.AssertionFailedf("ExcludeDataFromBackup set on system span config")
 	}
+	if s.RowExpiry != nil {
+		return errors.AssertionFailedf("RowExpiry set on system span config")
+	}
 	return nil
 }
 
@@ -115,6 +124,12 @@ func (s *SpanConfig) GetNumVoters() int32 {
 	return s.NumReplicas
 }
 
+// GetNumFullVoters returns the number of voting replicas that are not
+// witnesses, as defined in the span config.
+func (s *SpanConfig) GetNumFullVoters() int32 {
+	return s.GetNumVoters() - s.NumWitnesses
+}
+
 // GetNumNonVoters returns the number of non-voting replicas as defined in the
 // span config.
 func (s *SpanConfig) GetNumNonVoters() int32 {
@@ -64,7 +64,7 @@ func (a tenantAuthorizer) authorize(
 		return a.authBatch(ctx, sv, tenID, req.(*kvpb.BatchRequest))
 
 	case "/cockroach.roachpb.Internal/RangeLookup":
-		return a.authRangeLookup(tenID, req.(*kvpb.RangeLookupRequest))
+		return a.authRangeLookup(ctx, tenID, req.(*kvpb.RangeLookupRequest))
 
 	case "/cockroach.roachpb.Internal/RangeFeed", "/cockroach.roachpb.Internal/MuxRangeFeed":
 		return a.authRangeFeed(tenID, req.(*kvpb.RangeFeedRequest))
@@ -144,7 +144,7 @@ func (a tenantAuthorizer) authorize(
 		return a.authUpdateSpanConfigs(tenID, req.(*roachpb.UpdateSpanConfigsRequest))
 
 	case "/cockroach.roachpb.Internal/GetRangeDescriptors":
-		return a.authGetRangeDescriptors(tenID, req.(*kvpb.GetRangeDescriptorsRequest))
+		return a.authGetRangeDescriptors(ctx, tenID, req.(*kvpb.GetRangeDescriptorsRequest))
 
 	case "/cockroach.server.serverpb.Status/HotRangesV2":
 		return a.authHotRangesV2(tenID)
@@ -189,19 +189,53 @@ func (a tenantAuthorizer) authBatch(
 		return authError(err.Error())
 	}
 
-	// All keys in the request must reside within the tenant's keyspace.
+	// All keys in the request must reside within the tenant's keyspace, unless
+	// the request is a non-locking read of the keyspace of a tenant that the
+	// tenant has been granted read access to.
 	rSpan, err := keys.Range(args.Requests)
 	if err != nil {
 		return authError(err.Error())
 	}
 	tenSpan := tenantPrefix(tenID)
-	return checkSpanBounds(rSpan, tenSpan)
+	if err := checkSpanBounds(rSpan, tenSpan); err != nil {
+		if !args.IsReadOnly() || args.IsLocking() {
+			return err
+		}
+		return a.authReadFromTenant(ctx, tenID, rSpan, err)
+	}
+	return nil
+}
+
+// authReadFromTenant authorizes the provided tenant to read the provided span
+// if it lies entirely within the keyspace of a tenant that the provided tenant
+// has been granted read access to. Otherwise, boundsErr is returned.
+func (a tenantAuthorizer) authReadFromTenant(
+	ctx context.Context, tenID roachpb.TenantID, rSpan roachpb.RSpan, boundsErr error,
+) error {
+	_, otherTenID, err := keys.DecodeTenantPrefix(rSpan.Key.AsRawKey())
+	if err != nil || otherTenID.IsSystem() || otherTenID == tenID {
+		return boundsErr
+	}
+	if checkSpanBounds(rSpan, tenantPrefix(otherTenID)) != nil {
+		return boundsErr
+	}
+	if a.capabilitiesAuthorizer.HasReadAccessToTenant(ctx, tenID, otherTenID) != nil {
+		return boundsErr
+	}
+	return nil
 }
 
 func (a tenantAuthorizer) authGetRangeDescriptors(
-	tenID roachpb.TenantID, args *kvpb.GetRangeDescriptorsRequest,
+	ctx context.Context, tenID roachpb.TenantID, args *kvpb.GetRangeDescriptorsRequest,
 ) error {
-	return validateSpan(tenID, args.Span)
+	if err := validateSpan(tenID, args.Span); err != nil {
+		rSpan, addrErr := keys.SpanAddr(args.Span)
+		if addrErr != nil {
+			return err
+		}
+		return a.authReadFromTenant(ctx, tenID, rSpan, err)
+	}
+	return nil
 }
 
 func (a tenantAuthorizer) authSpanStats(
@@ -219,11 +253,12 @@ func (a tenantAuthorizer) authSpanStats(
 // authRangeLookup authorizes the provided tenant to invoke the RangeLookup RPC
 // with the provided args.
 func (a tenantAuthorizer) authRangeLookup(
-	tenID roachpb.TenantID, args *kvpb.RangeLookupRequest,
+	ctx context.Context, tenID roachpb.TenantID, args *kvpb.RangeLookupRequest,
 ) error {
 	tenSpan := tenantPrefix(tenID)
 	if !tenSpan.ContainsKey(args.Key) {
-		return authErrorf("requested key %s not fully contained in tenant keyspace %s", args.Key, tenSpan)
+		err := authErrorf("requested key %s not fully contained in tenant keyspace %s", args.Key, tenSpan)
+		return a.authReadFromTenant(ctx, tenID, roachpb.RSpan{Key: args.Key, EndKey: args.Key.Next()}, err)
 	}
 	return nil
 }
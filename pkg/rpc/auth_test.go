@@ -946,6 +946,59 @@ func TestTenantAuthCapabilityChecks(t *testing.T) {
 				},
 				expErr: "tenant does not have capability",
 			},
+			{
+				req: &kvpb.BatchRequest{Requests: makeReqs(
+					makeReqShared(t, prefix(5, "a"), prefix(5, "b")),
+				)},
+				configureAuthorizer: func(authorizer *mockAuthorizer) {
+					authorizer.hasCapabilityForBatch = true
+					authorizer.readFromTenant = roachpb.MustMakeTenantID(5)
+				},
+				expErr: "",
+			},
+			{
+				req: &kvpb.BatchRequest{Requests: makeReqs(
+					makeReqShared(t, prefix(5, "a"), prefix(5, "b")),
+				)},
+				configureAuthorizer: func(authorizer *mockAuthorizer) {
+					authorizer.hasCapabilityForBatch = true
+				},
+				expErr: `requested key span /Tenant/5{a-b} not fully contained in tenant keyspace /Tenant/1{0-1}`,
+			},
+			{
+				req: &kvpb.BatchRequest{Requests: makeReqs(
+					&kvpb.PutRequest{RequestHeader: kvpb.RequestHeader{Key: roachpb.Key(prefix(5, "a"))}},
+				)},
+				configureAuthorizer: func(authorizer *mockAuthorizer) {
+					authorizer.hasCapabilityForBatch = true
+					authorizer.readFromTenant = roachpb.MustMakeTenantID(5)
+				},
+				expErr: `requested key span /Tenant/5a{-\\x00} not fully contained in tenant keyspace /Tenant/1{0-1}`,
+			},
+			{
+				req: &kvpb.BatchRequest{Requests: makeReqs(
+					makeReqShared(t, prefix(5, "a"), prefix(20, "b")),
+				)},
+				configureAuthorizer: func(authorizer *mockAuthorizer) {
+					authorizer.hasCapabilityForBatch = true
+					authorizer.readFromTenant = roachpb.MustMakeTenantID(5)
+				},
+				expErr: `requested key span /Tenant/{5a-20b} not fully contained in tenant keyspace /Tenant/1{0-1}`,
+			},
+		},
+		"/cockroach.roachpb.Internal/RangeLookup": {
+			{
+				req: &kvpb.RangeLookupRequest{Key: roachpb.RKey(prefix(5, "a"))},
+				configureAuthorizer: func(authorizer *mockAuthorizer) {
+					authorizer.readFromTenant = roachpb.MustMakeTenantID(5)
+				},
+				expErr: "",
+			},
+			{
+				req:                 &kvpb.RangeLookupRequest{Key: roachpb.RKey(prefix(5, "a"))},
+				configureAuthorizer: func(authorizer *mockAuthorizer) {},
+				expErr:              `requested key /Tenant/5"a" not fully contained in tenant keyspace /Tenant/1{0-1}`,
+			},
 		},
 		"/cockroach.ts.tspb.TimeSeries/Query": {
 			{
@@ -988,6 +1041,7 @@ type mockAuthorizer struct {
 	hasTSDBQueryCapability             bool
 	hasNodelocalStorageCapability      bool
 	hasExemptFromRateLimiterCapability bool
+	readFromTenant                     roachpb.TenantID
 }
 
 func (m mockAuthorizer) HasProcessDebugCapability(
@@ -996,6 +1050,15 @@ func (m mockAuthorizer) HasProcessDebugCapability(
 	return errors.New("tenant does not have capability")
 }
 
+func (m mockAuthorizer) HasReadAccessToTenant(
+	ctx context.Context, tenID, otherTenID roachpb.TenantID,
+) error {
+	if tenID == otherTenID || m.readFromTenant == otherTenID {
+		return nil
+	}
+	return errors.New("tenant does not have capability")
+}
+
 var _ tenantcapabilities.Authorizer = &mockAuthorizer{}
 
 // HasCapabilityForBatch implements the tenantcapabilities.Authorizer interface.
@@ -180,6 +180,11 @@ func registerRoutes(
 		{"nodes/{node_id}/ranges/", systemRoutes.listNodeRanges, true, authserver.AdminRole, noOption, false},
 		{"ranges/hot/", a.listHotRanges, true, authserver.AdminRole, noOption, false},
 		{"ranges/{range_id:[0-9]+}/", a.listRange, true, authserver.AdminRole, noOption, false},
+		// Export snapshots expose raw table data, and protect data from
+		// garbage collection, so they require an admin user.
+		{"export/snapshots/", a.createExportSnapshot, true, authserver.AdminRole, noOption, false},
+		{"export/snapshots/{snapshot_id}/", a.getExportSnapshot, true, authserver.AdminRole, noOption, false},
+		{"export/snapshots/{snapshot_id}/data/", a.exportSnapshotData, true, authserver.AdminRole, noOption, false},
 		{"health/", systemRoutes.health, false, authserver.RegularRole, noOption, false},
 		{"users/", a.listUsers, true, authserver.RegularRole, noOption, false},
 		{"events/", a.listEvents, true, authserver.AdminRole, noOption, false},
@@ -0,0 +1,600 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	gojson "encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptreconcile"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/apiutil"
+	"github.com/cockroachdb/cockroach/pkg/server/authserver"
+	"github.com/cockroachdb/cockroach/pkg/server/srverrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// exportSnapshotMetaType is the meta type of the protected timestamp
+	// records of export snapshots.
+	exportSnapshotMetaType = "export_snapshots"
+	// defaultExportSnapshotLease is the duration for which an export snapshot
+	// is protected from garbage collection, if not specified.
+	defaultExportSnapshotLease = time.Hour
+	// maxExportSnapshotLease is the longest duration for which an export
+	// snapshot can be protected from garbage collection.
+	maxExportSnapshotLease = 24 * time.Hour
+	// defaultExportTargetBytes is the target size of the SST returned by a
+	// single export data request, if not specified.
+	defaultExportTargetBytes = 16 << 20
+)
+
+// exportSnapshotMeta is stored as the meta of the protected timestamp record
+// of an export snapshot.
+type exportSnapshotMeta struct {
+	// Expiration is the time at which the snapshot's lease expires, expressed as
+	// nanoseconds since Unix epoch. Once it expires, the record is released by
+	// the protected timestamp reconciler.
+	Expiration int64 `json:"expiration"`
+	// Spans are the spans which can be exported from the snapshot.
+	Spans []roachpb.Span `json:"spans"`
+}
+
+// makeExportSnapshotStatusFunc returns a function which determines whether the
+// protected timestamp record of an export snapshot should be removed by the
+// reconciler, which is the case once its lease has expired.
+func makeExportSnapshotStatusFunc() ptreconcile.StatusFunc {
+	return func(ctx context.Context, _ isql.Txn, meta []byte) (shouldRemove bool, _ error) {
+		var m exportSnapshotMeta
+		if err := gojson.Unmarshal(meta, &m); err != nil {
+			return false, errors.Wrap(err, "decoding export snapshot")
+		}
+		return timeutil.Now().UnixNano() > m.Expiration, nil
+	}
+}
+
+// Request for createExportSnapshot.
+//
+// swagger:model exportSnapshotRequest
+type exportSnapshotRequest struct {
+	// Tables are the names of the tables to export. Names are resolved within
+	// Database, and may be qualified.
+	Tables []string `json:"tables,omitempty"`
+	// Database is the database used to resolve unqualified table names.
+	// Defaults to defaultdb.
+	Database string `json:"database,omitempty"`
+	// Spans are the key spans to export. Only one of Tables and Spans may be
+	// set. Exporting spans protects the whole cluster from garbage collection
+	// for the duration of the lease.
+	Spans []roachpb.Span `json:"spans,omitempty"`
+	// LeaseDuration is the duration for which the snapshot is protected from
+	// garbage collection, e.g. "1h". Defaults to 1h, and may be at most 24h.
+	LeaseDuration string `json:"lease_duration,omitempty"`
+}
+
+// exportRangeInfo describes a range of an export snapshot.
+type exportRangeInfo struct {
+	// RangeID is the ID of the range.
+	RangeID roachpb.RangeID `json:"range_id"`
+	// StartKey is the start of the range's span to export.
+	StartKey roachpb.Key `json:"start_key"`
+	// EndKey is the end of the range's span to export.
+	EndKey roachpb.Key `json:"end_key"`
+	// LeaseholderNodeID is the node believed to hold the range's lease, or 0
+	// if unknown.
+	LeaseholderNodeID roachpb.NodeID `json:"leaseholder_node_id,omitempty"`
+	// LeaseholderHTTPAddress is the HTTP address of the leaseholder node, to
+	// which the range's export data requests should be sent.
+	LeaseholderHTTPAddress string `json:"leaseholder_http_address,omitempty"`
+}
+
+// Response for createExportSnapshot and getExportSnapshot.
+//
+// swagger:model exportSnapshotResponse
+type exportSnapshotResponse struct {
+	// SnapshotID identifies the snapshot in subsequent requests.
+	SnapshotID uuid.UUID `json:"snapshot_id"`
+	// Timestamp is the timestamp at which the snapshot's data is exported.
+	Timestamp hlc.Timestamp `json:"timestamp"`
+	// Expiration is the time at which the snapshot's lease expires, after which
+	// its data may be garbage collected.
+	Expiration time.Time `json:"expiration"`
+	// Ranges are the ranges of the snapshot's spans, which can be exported in
+	// parallel.
+	Ranges []exportRangeInfo `json:"ranges"`
+}
+
+// exportFile is an SST of exported data.
+type exportFile struct {
+	// Span is the span of the data in the file.
+	Span roachpb.Span `json:"span"`
+	// SST is the SST containing the latest value of each key in the span as of
+	// the snapshot's timestamp.
+	SST []byte `json:"sst"`
+	// DataSize is the logical size of the exported data.
+	DataSize int64 `json:"data_size"`
+}
+
+// Response for exportSnapshotData.
+//
+// swagger:model exportSnapshotDataResponse
+type exportSnapshotDataResponse struct {
+	// Files are the exported SSTs.
+	Files []exportFile `json:"files"`
+	// The continuation token, for use in the next call in the `cursor`
+	// parameter. Empty once the requested span has been exported.
+	Next string `json:"next,omitempty"`
+}
+
+// swagger:operation POST /export/snapshots/ createExportSnapshot
+//
+// # Create an export snapshot
+//
+// Creates a consistent snapshot of a set of tables or key spans for export.
+// The snapshot's data is protected from garbage collection until its lease
+// expires or it is released. The response lists the ranges of the snapshot,
+// along with their leaseholders, so that the data of each range can be
+// fetched in parallel using the export data endpoint.
+//
+// Client must be logged-in as a user with admin privileges.
+//
+// ---
+// parameters:
+//   - name: request
+//     in: body
+//     required: true
+//     schema:
+//     "$ref": "#/definitions/exportSnapshotRequest"
+//
+// produces:
+// - application/json
+// security:
+// - api_session: []
+// responses:
+//
+//	"200":
+//	  description: Export snapshot response.
+//	  schema:
+//	    "$ref": "#/definitions/exportSnapshotResponse"
+func (a *apiV2Server) createExportSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST supported", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+	username := authserver.UserFromHTTPAuthInfoContext(ctx)
+	ctx = a.sqlServer.AnnotateCtx(ctx)
+
+	rc := http.MaxBytesReader(w, r.Body, 10*1024*1024 /* 10MiB */)
+	defer rc.Close()
+	input, err := io.ReadAll(rc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req exportSnapshotRequest
+	if err := gojson.Unmarshal(input, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if (len(req.Tables) == 0) == (len(req.Spans) == 0) {
+		http.Error(w, "exactly one of tables or spans must be specified", http.StatusBadRequest)
+		return
+	}
+	lease := defaultExportSnapshotLease
+	if req.LeaseDuration != "" {
+		if lease, err = time.ParseDuration(req.LeaseDuration); err != nil || lease <= 0 || lease > maxExportSnapshotLease {
+			http.Error(w, "invalid lease duration", http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Database == "" {
+		req.Database = "defaultdb"
+	}
+
+	execCfg := a.sqlServer.execCfg
+	var spans []roachpb.Span
+	var target *ptpb.Target
+	if len(req.Tables) > 0 {
+		tableIDs := make(descpb.IDs, 0, len(req.Tables))
+		for _, table := range req.Tables {
+			row, err := a.sqlServer.internalExecutor.QueryRowEx(
+				ctx, "export-snapshot-table", nil, /* txn */
+				sessiondata.InternalExecutorOverride{User: username, Database: req.Database},
+				`SELECT $1::REGCLASS::OID::INT8`, table,
+			)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			tableID := descpb.ID(tree.MustBeDInt(row[0]))
+			tableIDs = append(tableIDs, tableID)
+			spans = append(spans, execCfg.Codec.TableSpan(uint32(tableID)))
+		}
+		target = ptpb.MakeSchemaObjectsTarget(tableIDs)
+	} else {
+		tenantSpan := execCfg.Codec.TenantSpan()
+		for _, sp := range req.Spans {
+			if !sp.Valid() || keys.IsLocal(sp.Key) || !tenantSpan.Contains(sp) {
+				http.Error(w, "invalid span "+sp.String(), http.StatusBadRequest)
+				return
+			}
+		}
+		spans = req.Spans
+		// Protected timestamps can't target arbitrary spans, so the whole
+		// cluster is protected instead.
+		target = ptpb.MakeClusterTarget()
+	}
+
+	expiration := timeutil.Now().Add(lease)
+	meta, err := gojson.Marshal(exportSnapshotMeta{Expiration: expiration.UnixNano(), Spans: spans})
+	if err != nil {
+		srverrors.APIV2InternalError(ctx, err, w)
+		return
+	}
+	snapshotID := uuid.MakeV4()
+	rec := &ptpb.Record{
+		ID:        snapshotID.GetBytesMut(),
+		Timestamp: a.db.Clock().Now(),
+		Mode:      ptpb.PROTECT_AFTER,
+		MetaType:  exportSnapshotMetaType,
+		Meta:      meta,
+		Target:    target,
+	}
+	if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		return execCfg.ProtectedTimestampProvider.WithTxn(txn).Protect(ctx, rec)
+	}); err != nil {
+		srverrors.APIV2InternalError(ctx, err, w)
+		return
+	}
+
+	resp := exportSnapshotResponse{
+		SnapshotID: snapshotID,
+		Timestamp:  rec.Timestamp,
+		Expiration: expiration,
+	}
+	if resp.Ranges, err = a.exportSnapshotRanges(ctx, spans); err != nil {
+		srverrors.APIV2InternalError(ctx, err, w)
+		return
+	}
+	apiutil.WriteJSONResponse(ctx, w, http.StatusOK, resp)
+}
+
+// exportSnapshotRanges returns the ranges of the given spans, along with their
+// leaseholders as known to the range cache.
+func (a *apiV2Server) exportSnapshotRanges(
+	ctx context.Context, spans []roachpb.Span,
+) ([]exportRangeInfo, error) {
+	execCfg := a.sqlServer.execCfg
+	var ranges []exportRangeInfo
+	ri := kvcoord.MakeRangeIterator(execCfg.DistSender)
+	for _, sp := range spans {
+		rs, err := keys.SpanAddr(sp)
+		if err != nil {
+			return nil, err
+		}
+		for ri.Seek(ctx, rs.Key, kvcoord.Ascending); ; ri.Next(ctx) {
+			if !ri.Valid() {
+				return nil, ri.Error()
+			}
+			desc := ri.Desc()
+			info := exportRangeInfo{
+				RangeID:  desc.RangeID,
+				StartKey: sp.Key,
+				EndKey:   sp.EndKey,
+			}
+			if rs.Key.Less(desc.StartKey) {
+				info.StartKey = desc.StartKey.AsRawKey()
+			}
+			if desc.EndKey.Less(rs.EndKey) {
+				info.EndKey = desc.EndKey.AsRawKey()
+			}
+			if lh := ri.Leaseholder(); lh != nil {
+				info.LeaseholderNodeID = lh.NodeID
+				if nodeDesc, err := execCfg.NodeDescs.GetNodeDescriptor(lh.NodeID); err == nil {
+					info.LeaseholderHTTPAddress = nodeDesc.HTTPAddress.String()
+				}
+			}
+			ranges = append(ranges, info)
+			if !ri.NeedAnother(rs) {
+				break
+			}
+		}
+	}
+	return ranges, nil
+}
+
+// getExportSnapshotRecord returns the protected timestamp record and meta of
+// the export snapshot with the given ID. It writes an error response and
+// returns ok=false if the snapshot doesn't exist or has expired.
+func (a *apiV2Server) getExportSnapshotRecord(
+	ctx context.Context, w http.ResponseWriter, r *http.Request,
+) (_ *ptpb.Record, _ exportSnapshotMeta, ok bool) {
+	snapshotID, err := uuid.FromString(mux.Vars(r)["snapshot_id"])
+	if err != nil {
+		http.Error(w, "invalid snapshot ID", http.StatusBadRequest)
+		return nil, exportSnapshotMeta{}, false
+	}
+	execCfg := a.sqlServer.execCfg
+	var rec *ptpb.Record
+	if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) (err error) {
+		rec, err = execCfg.ProtectedTimestampProvider.WithTxn(txn).GetRecord(ctx, snapshotID)
+		return err
+	}); err != nil {
+		if errors.Is(err, protectedts.ErrNotExists) {
+			http.Error(w, "snapshot not found", http.StatusNotFound)
+		} else {
+			srverrors.APIV2InternalError(ctx, err, w)
+		}
+		return nil, exportSnapshotMeta{}, false
+	}
+	var meta exportSnapshotMeta
+	if rec.MetaType != exportSnapshotMetaType {
+		http.Error(w, "snapshot not found", http.StatusNotFound)
+		return nil, exportSnapshotMeta{}, false
+	}
+	if err := gojson.Unmarshal(rec.Meta, &meta); err != nil {
+		srverrors.APIV2InternalError(ctx, err, w)
+		return nil, exportSnapshotMeta{}, false
+	}
+	if timeutil.Now().UnixNano() > meta.Expiration {
+		http.Error(w, "snapshot lease expired", http.StatusGone)
+		return nil, exportSnapshotMeta{}, false
+	}
+	return rec, meta, true
+}
+
+// swagger:operation GET /export/snapshots/{snapshot_id}/ getExportSnapshot
+//
+// # Get or release an export snapshot
+//
+// GET returns the export snapshot, along with the current ranges of its spans
+// and their leaseholders. DELETE releases the snapshot, allowing its data to
+// be garbage collected.
+//
+// Client must be logged-in as a user with admin privileges.
+//
+// ---
+// parameters:
+//   - name: snapshot_id
+//     type: string
+//     in: path
+//     description: ID of the export snapshot.
+//     required: true
+//
+// produces:
+// - application/json
+// security:
+// - api_session: []
+// responses:
+//
+//	"200":
+//	  description: Export snapshot response.
+//	  schema:
+//	    "$ref": "#/definitions/exportSnapshotResponse"
+//	"404":
+//	  description: Snapshot not found
+//	"410":
+//	  description: Snapshot lease expired
+func (a *apiV2Server) getExportSnapshot(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx = a.sqlServer.AnnotateCtx(ctx)
+	rec, meta, ok := a.getExportSnapshotRecord(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		resp := exportSnapshotResponse{
+			SnapshotID: rec.ID.GetUUID(),
+			Timestamp:  rec.Timestamp,
+			Expiration: timeutil.Unix(0, meta.Expiration),
+		}
+		var err error
+		if resp.Ranges, err = a.exportSnapshotRanges(ctx, meta.Spans); err != nil {
+			srverrors.APIV2InternalError(ctx, err, w)
+			return
+		}
+		apiutil.WriteJSONResponse(ctx, w, http.StatusOK, resp)
+	case http.MethodDelete:
+		execCfg := a.sqlServer.execCfg
+		if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+			return execCfg.ProtectedTimestampProvider.WithTxn(txn).Release(ctx, rec.ID.GetUUID())
+		}); err != nil && !errors.Is(err, protectedts.ErrNotExists) {
+			srverrors.APIV2InternalError(ctx, err, w)
+			return
+		}
+		apiutil.WriteJSONResponse(ctx, w, http.StatusOK, struct{}{})
+	default:
+		http.Error(w, "only GET and DELETE supported", http.StatusMethodNotAllowed)
+	}
+}
+
+// swagger:operation GET /export/snapshots/{snapshot_id}/data/ exportSnapshotData
+//
+// # Export the data of an export snapshot
+//
+// Exports the latest value of each key in a span of the snapshot, as of the
+// snapshot's timestamp, as SSTs. Each call returns data up to roughly
+// `target_bytes` in size, along with a continuation token if the span has
+// not been exported in full. Calls for different spans may be issued in
+// parallel, ideally to the leaseholders of the spans' ranges.
+//
+// Client must be logged-in as a user with admin privileges.
+//
+// ---
+// parameters:
+//   - name: snapshot_id
+//     type: string
+//     in: path
+//     description: ID of the export snapshot.
+//     required: true
+//   - name: start_key
+//     type: string
+//     in: query
+//     description: Base64 encoded start of the span to export, e.g. the start
+//     key of one of the snapshot's ranges. Defaults to the start of the
+//     snapshot's first span.
+//     required: false
+//   - name: end_key
+//     type: string
+//     in: query
+//     description: Base64 encoded end of the span to export. Defaults to the
+//     end of the snapshot span containing start_key.
+//     required: false
+//   - name: cursor
+//     type: string
+//     in: query
+//     description: Continuation token from a past call for the same span.
+//     required: false
+//   - name: target_bytes
+//     type: integer
+//     in: query
+//     description: Target size of the exported data. Defaults to 16MiB.
+//     required: false
+//
+// produces:
+// - application/json
+// security:
+// - api_session: []
+// responses:
+//
+//	"200":
+//	  description: Export snapshot data response.
+//	  schema:
+//	    "$ref": "#/definitions/exportSnapshotDataResponse"
+//	"404":
+//	  description: Snapshot not found
+//	"410":
+//	  description: Snapshot lease expired
+func (a *apiV2Server) exportSnapshotData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx = a.sqlServer.AnnotateCtx(ctx)
+	rec, meta, ok := a.getExportSnapshotRecord(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	decodeKey := func(param string) (roachpb.Key, bool) {
+		s := query.Get(param)
+		if s == "" {
+			return nil, true
+		}
+		k, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			http.Error(w, "invalid "+param, http.StatusBadRequest)
+			return nil, false
+		}
+		return k, true
+	}
+	span := meta.Spans[0]
+	startKey, ok := decodeKey("start_key")
+	if !ok {
+		return
+	}
+	endKey, ok := decodeKey("end_key")
+	if !ok {
+		return
+	}
+	cursor, ok := decodeKey("cursor")
+	if !ok {
+		return
+	}
+	if startKey != nil {
+		span.Key, span.EndKey = startKey, nil
+		for _, sp := range meta.Spans {
+			if sp.ContainsKey(startKey) {
+				span.EndKey = sp.EndKey
+				break
+			}
+		}
+	}
+	if endKey != nil {
+		span.EndKey = endKey
+	}
+	// The span to export must be within one of the snapshot's spans.
+	contained := false
+	for _, sp := range meta.Spans {
+		contained = contained || sp.Contains(span)
+	}
+	if !span.Valid() || !contained {
+		http.Error(w, "span is not part of the snapshot", http.StatusBadRequest)
+		return
+	}
+	if cursor != nil {
+		if !span.ContainsKey(cursor) {
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+		span.Key = cursor
+	}
+	targetBytes := int64(defaultExportTargetBytes)
+	if s := query.Get("target_bytes"); s != "" {
+		var err error
+		if targetBytes, err = strconv.ParseInt(s, 10, 64); err != nil || targetBytes <= 0 {
+			http.Error(w, "invalid target_bytes", http.StatusBadRequest)
+			return
+		}
+	}
+
+	req := &kvpb.ExportRequest{
+		RequestHeader:  kvpb.RequestHeaderFromSpan(span),
+		MVCCFilter:     kvpb.MVCCFilter_Latest,
+		TargetFileSize: targetBytes,
+	}
+	header := kvpb.Header{
+		Timestamp: rec.Timestamp,
+		// The sentinel value of 1 forces the ExportRequest to paginate after
+		// creating a single SST.
+		TargetBytes:                 1,
+		ReturnElasticCPUResumeSpans: true,
+	}
+	rawResp, pErr := kv.SendWrappedWith(ctx, a.db.NonTransactionalSender(), header, req)
+	if pErr != nil {
+		srverrors.APIV2InternalError(ctx, pErr.GoError(), w)
+		return
+	}
+	exportResp := rawResp.(*kvpb.ExportResponse)
+
+	resp := exportSnapshotDataResponse{Files: make([]exportFile, 0, len(exportResp.Files))}
+	for _, f := range exportResp.Files {
+		resp.Files = append(resp.Files, exportFile{
+			Span:     f.Span,
+			SST:      f.SST,
+			DataSize: f.Exported.DataSize,
+		})
+	}
+	if exportResp.ResumeSpan != nil {
+		resp.Next = base64.StdEncoding.EncodeToString(exportResp.ResumeSpan.Key)
+	}
+	apiutil.WriteJSONResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/server/apiconstants"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportSnapshotV2(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestIsSpecificToStorageLayerAndNeedsASystemTenant,
+	})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY, v STRING)`)
+	sqlDB.Exec(t, `INSERT INTO t SELECT i, 'v' FROM generate_series(1, 100) AS g(i)`)
+	sqlDB.Exec(t, `ALTER TABLE t SPLIT AT VALUES (50)`)
+
+	client, err := s.GetAdminHTTPClient()
+	require.NoError(t, err)
+	snapshotsURL := s.AdminURL().WithPath(apiconstants.APIV2Path + "export/snapshots/").String()
+
+	doRequest := func(method, target string, body []byte, expStatus int, resp interface{}) {
+		req, err := http.NewRequest(method, target, bytes.NewReader(body))
+		require.NoError(t, err)
+		httpResp, err := client.Do(req)
+		require.NoError(t, err)
+		defer httpResp.Body.Close()
+		require.Equal(t, expStatus, httpResp.StatusCode)
+		if resp != nil {
+			require.NoError(t, json.NewDecoder(httpResp.Body).Decode(resp))
+		}
+	}
+
+	// Exactly one of tables and spans must be specified.
+	doRequest("POST", snapshotsURL, []byte(`{}`), http.StatusBadRequest, nil)
+
+	var snapshot exportSnapshotResponse
+	doRequest("POST", snapshotsURL, []byte(`{"tables": ["t"], "lease_duration": "10m"}`),
+		http.StatusOK, &snapshot)
+	require.NotEmpty(t, snapshot.Ranges)
+
+	// Rows written after the snapshot are not exported.
+	sqlDB.Exec(t, `INSERT INTO t VALUES (101, 'v')`)
+
+	snapshotURL := snapshotsURL + snapshot.SnapshotID.String() + "/"
+	var got exportSnapshotResponse
+	doRequest("GET", snapshotURL, nil, http.StatusOK, &got)
+	require.Equal(t, snapshot.Timestamp, got.Timestamp)
+
+	// Export each range in small batches, following the cursors.
+	var exportedKeys int
+	for _, r := range snapshot.Ranges {
+		cursor := ""
+		for {
+			params := url.Values{}
+			params.Set("start_key", base64.StdEncoding.EncodeToString(r.StartKey))
+			params.Set("end_key", base64.StdEncoding.EncodeToString(r.EndKey))
+			params.Set("target_bytes", "100")
+			if cursor != "" {
+				params.Set("cursor", cursor)
+			}
+			var data exportSnapshotDataResponse
+			doRequest("GET", snapshotURL+"data/?"+params.Encode(), nil, http.StatusOK, &data)
+			for _, f := range data.Files {
+				iter, err := storage.NewMemSSTIterator(f.SST, false /* verify */, storage.IterOptions{
+					KeyTypes:   storage.IterKeyTypePointsOnly,
+					UpperBound: keys.MaxKey,
+				})
+				require.NoError(t, err)
+				for iter.SeekGE(storage.MVCCKey{Key: keys.MinKey}); ; iter.Next() {
+					ok, err := iter.Valid()
+					require.NoError(t, err)
+					if !ok {
+						break
+					}
+					require.True(t, iter.UnsafeKey().Timestamp.LessEq(snapshot.Timestamp))
+					exportedKeys++
+				}
+				iter.Close()
+			}
+			if data.Next == "" {
+				break
+			}
+			cursor = data.Next
+		}
+	}
+	require.Equal(t, 100, exportedKeys)
+
+	// Releasing the snapshot removes it.
+	doRequest("DELETE", snapshotURL, nil, http.StatusOK, nil)
+	doRequest("GET", snapshotURL, nil, http.StatusNotFound, nil)
+}
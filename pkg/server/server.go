@@ -667,6 +667,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (serverctl.ServerStartupInterf
 				jobRegistry, jobsprotectedts.Schedules,
 			),
 			sessionprotectedts.SessionMetaType: sessionprotectedts.MakeStatusFunc(),
+			exportSnapshotMetaType:             makeExportSnapshotStatusFunc(),
 		},
 	})
 	if err != nil {
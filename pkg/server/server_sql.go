@@ -996,6 +996,9 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*SQLServer, error) {
 			serverCacheMemoryMonitor.MakeBoundAccount(),
 			virtualSchemas, cfg.internalDB,
 		),
+		ResourceGroupCache: sql.NewResourceGroupCache(
+			cfg.Settings, cfg.stopper, cfg.internalDB,
+		),
 		DistSQLPlanner: sql.NewDistSQLPlanner(
 			ctx,
 			execinfra.Version,
@@ -1707,6 +1710,7 @@ func (s *SQLServer) preStart(
 		s.execCfg.CaptureIndexUsageStatsKnobs,
 	)
 	s.execCfg.SyntheticPrivilegeCache.Start(ctx)
+	s.execCfg.ResourceGroupCache.Start(ctx)
 
 	// Report a warning if the server is being shut down via the stopper
 	// before it was gracefully drained. This warning may be innocuous
@@ -1200,6 +1200,7 @@ func makeTenantSQLServerArgs(
 				circularJobRegistry, jobsprotectedts.Schedules,
 			),
 			sessionprotectedts.SessionMetaType: sessionprotectedts.MakeStatusFunc(),
+			exportSnapshotMetaType:             makeExportSnapshotStatusFunc(),
 		},
 	})
 	if err != nil {
@@ -48,11 +48,13 @@ var fields = [config.NumFields]Field{
 	rangeMaxBytes,
 	globalReads,
 	numVoters,
+	numWitnesses,
 	numReplicas,
 	gcTTLSeconds,
 	constraints,
 	voterConstraints,
 	leasePreferences,
+	storageTier,
 }
 
 const (
@@ -61,8 +63,10 @@ const (
 	globalReads      = boolField(config.GlobalReads)
 	numReplicas      = int32Field(config.NumReplicas)
 	numVoters        = int32Field(config.NumVoters)
+	numWitnesses     = int32Field(config.NumWitnesses)
 	gcTTLSeconds     = int32Field(config.GCTTL)
 	constraints      = constraintsConjunctionField(config.Constraints)
 	voterConstraints = constraintsConjunctionField(config.VoterConstraints)
 	leasePreferences = leasePreferencesField(config.LeasePreferences)
+	storageTier      = storageTierField(config.StorageTier)
 )
@@ -0,0 +1,50 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package spanconfigbounds
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+)
+
+type storageTierField int
+
+var _ field[roachpb.StorageTier] = storageTierField(0)
+
+func (f storageTierField) SafeFormat(s redact.SafePrinter, verb rune) {
+	s.Printf("%s", config.Field(f))
+}
+
+func (f storageTierField) String() string {
+	return config.Field(f).String()
+}
+
+func (f storageTierField) FieldBound(b *Bounds) ValueBounds {
+	return unbounded{}
+}
+
+func (f storageTierField) FieldValue(c *roachpb.SpanConfig) Value {
+	return (*storageTierValue)(f.fieldValue(c))
+}
+
+func (f storageTierField) fieldValue(c *roachpb.SpanConfig) *roachpb.StorageTier {
+	switch f {
+	case storageTier:
+		return &c.StorageTier
+	default:
+		// This is safe because we test that all the fields in the proto have
+		// a corresponding field, and we call this for each of them, and the user
+		// never provides the input to this function.
+		panic(errors.AssertionFailedf("failed to look up field %s", f))
+	}
+}
@@ -69,3 +69,12 @@ func (b boolValue) String() string {
 func (b boolValue) SafeFormat(s interfaces.SafePrinter, verb rune) {
 	s.Print(bool(b))
 }
+
+type storageTierValue roachpb.StorageTier
+
+func (t storageTierValue) String() string {
+	return roachpb.StorageTier(t).String()
+}
+func (t storageTierValue) SafeFormat(s interfaces.SafePrinter, verb rune) {
+	s.Print(roachpb.StorageTier(t))
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/ttl/ttlbase"
 	"github.com/cockroachdb/errors"
 )
 
@@ -349,6 +350,9 @@ func (s *SQLTranslator) generateSpanConfigurationsForTable(
 	// backups.
 	tableSpanConfig.ExcludeDataFromBackup = table.GetExcludeDataFromBackup()
 
+	// Set the storage level expiry of the table's rows, if it has one.
+	tableSpanConfig.RowExpiry = ttlbase.GetRowExpiry(table)
+
 	records := make([]spanconfig.Record, 0)
 	if table.GetID() == keys.DescriptorTableID {
 		// We have named ranges preceding `system.descriptor`.
@@ -445,6 +449,7 @@ func (s *SQLTranslator) generateSpanConfigurationsForTable(
 		// SubzoneSpanConfig.
 		subzoneSpanConfig.GCPolicy.ProtectionPolicies = tableSpanConfig.GCPolicy.ProtectionPolicies[:]
 		subzoneSpanConfig.ExcludeDataFromBackup = tableSpanConfig.ExcludeDataFromBackup
+		subzoneSpanConfig.RowExpiry = tableSpanConfig.RowExpiry
 		if isSystemDesc { // same as above
 			subzoneSpanConfig.RangefeedEnabled = true
 			subzoneSpanConfig.GCPolicy.IgnoreStrictEnforcement = true
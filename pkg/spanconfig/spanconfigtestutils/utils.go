@@ -662,6 +662,13 @@ func PrintSpanConfigDiffedAgainstDefaults(conf roachpb.SpanConfig) string {
 	if conf.ExcludeDataFromBackup != defaultConf.ExcludeDataFromBackup {
 		diffs = append(diffs, fmt.Sprintf("exclude_data_from_backup=%v", conf.ExcludeDataFromBackup))
 	}
+	if conf.StorageTier != defaultConf.StorageTier {
+		diffs = append(diffs, fmt.Sprintf("storage_tier=%s", strings.ToLower(conf.StorageTier.String())))
+	}
+	if conf.RowExpiry != nil {
+		diffs = append(diffs, fmt.Sprintf("row_expiry=%d/%d/%d/%d", conf.RowExpiry.TableID,
+			conf.RowExpiry.IndexID, conf.RowExpiry.FamilyID, conf.RowExpiry.ColumnID))
+	}
 
 	return strings.Join(diffs, " ")
 }
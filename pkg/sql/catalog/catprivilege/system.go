@@ -75,6 +75,8 @@ var (
 		catconstants.SpanStatsSamples,
 		catconstants.SpanStatsTenantBoundaries,
 		catconstants.RegionalLiveness,
+		catconstants.PreparedTransactionsTableName,
+		catconstants.ResourceGroupsTableName,
 	}
 
 	readWriteSystemSequences = []catconstants.SystemTableName{
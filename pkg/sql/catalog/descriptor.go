@@ -759,6 +759,9 @@ type TableDescriptor interface {
 	// IsSchemaLocked returns true if we don't allow performing schema changes
 	// on this table descriptor.
 	IsSchemaLocked() bool
+	// ExternalRowData returns the location of the table's rows if they are
+	// stored in another tenant's keyspace, or nil otherwise.
+	ExternalRowData() *descpb.ExternalRowData
 }
 
 // MutableTableDescriptor is both a MutableDescriptor and a TableDescriptor.
@@ -1007,6 +1007,33 @@ CREATE TABLE system.span_stats_tenant_boundaries (
 	  FAMILY "primary" (crdb_region, unavailable_at)
 ) ;
 `
+
+	// PreparedTransactionsTableSchema stores the transactions which were
+	// prepared with PREPARE TRANSACTION and have not yet been committed or
+	// rolled back, keyed by the global identifier assigned by the client.
+	PreparedTransactionsTableSchema = `
+CREATE TABLE system.prepared_transactions (
+	global_id       STRING NOT NULL,
+	transaction_id  UUID NOT NULL,
+	transaction_key BYTES NULL,
+	prepared        TIMESTAMPTZ NOT NULL DEFAULT now():::TIMESTAMPTZ,
+	owner           STRING NOT NULL,
+	database        STRING NOT NULL,
+	CONSTRAINT "primary" PRIMARY KEY (global_id),
+	FAMILY "primary" (global_id, transaction_id, transaction_key, prepared, owner, database)
+);`
+
+	// ResourceGroupsTableSchema stores the resource groups defined with CREATE
+	// RESOURCE GROUP. A share of zero stands for the default share.
+	ResourceGroupsTableSchema = `
+CREATE TABLE system.resource_groups (
+	name            STRING NOT NULL,
+	cpu_share       INT8 NOT NULL DEFAULT 0:::INT8,
+	io_share        INT8 NOT NULL DEFAULT 0:::INT8,
+	max_concurrency INT8 NOT NULL DEFAULT 0:::INT8,
+	CONSTRAINT "primary" PRIMARY KEY (name),
+	FAMILY "primary" (name, cpu_share, io_share, max_concurrency)
+);`
 )
 
 func pk(name string) descpb.IndexDescriptor {
@@ -1237,6 +1264,8 @@ func MakeSystemTables() []SystemTable {
 		StatementActivityTable,
 		TransactionActivityTable,
 		RegionLivenessTable,
+		PreparedTransactionsTable,
+		ResourceGroupsTable,
 	}
 }
 
@@ -4103,6 +4132,66 @@ var (
 			},
 		),
 	)
+
+	PreparedTransactionsTable = makeSystemTable(
+		PreparedTransactionsTableSchema,
+		systemTable(
+			catconstants.PreparedTransactionsTableName,
+			descpb.InvalidID, // dynamically assigned
+			[]descpb.ColumnDescriptor{
+				{Name: "global_id", ID: 1, Type: types.String},
+				{Name: "transaction_id", ID: 2, Type: types.Uuid},
+				{Name: "transaction_key", ID: 3, Type: types.Bytes, Nullable: true},
+				{Name: "prepared", ID: 4, Type: types.TimestampTZ, DefaultExpr: &nowTZString},
+				{Name: "owner", ID: 5, Type: types.String},
+				{Name: "database", ID: 6, Type: types.String},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:        "primary",
+					ID:          0,
+					ColumnNames: []string{"global_id", "transaction_id", "transaction_key", "prepared", "owner", "database"},
+					ColumnIDs:   []descpb.ColumnID{1, 2, 3, 4, 5, 6},
+				},
+			},
+			descpb.IndexDescriptor{
+				Name:                "primary",
+				ID:                  1,
+				Unique:              true,
+				KeyColumnNames:      []string{"global_id"},
+				KeyColumnDirections: singleASC,
+				KeyColumnIDs:        singleID1,
+			}),
+	)
+
+	ResourceGroupsTable = makeSystemTable(
+		ResourceGroupsTableSchema,
+		systemTable(
+			catconstants.ResourceGroupsTableName,
+			descpb.InvalidID, // dynamically assigned
+			[]descpb.ColumnDescriptor{
+				{Name: "name", ID: 1, Type: types.String},
+				{Name: "cpu_share", ID: 2, Type: types.Int, DefaultExpr: &zeroIntString},
+				{Name: "io_share", ID: 3, Type: types.Int, DefaultExpr: &zeroIntString},
+				{Name: "max_concurrency", ID: 4, Type: types.Int, DefaultExpr: &zeroIntString},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:        "primary",
+					ID:          0,
+					ColumnNames: []string{"name", "cpu_share", "io_share", "max_concurrency"},
+					ColumnIDs:   []descpb.ColumnID{1, 2, 3, 4},
+				},
+			},
+			descpb.IndexDescriptor{
+				Name:                "primary",
+				ID:                  1,
+				Unique:              true,
+				KeyColumnNames:      []string{"name"},
+				KeyColumnDirections: singleASC,
+				KeyColumnIDs:        singleID1,
+			}),
+	)
 )
 
 // SpanConfigurationsTableName represents system.span_configurations.
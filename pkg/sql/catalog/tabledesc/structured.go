@@ -2401,6 +2401,9 @@ func (desc *wrapper) GetStorageParams(spaceBetweenEqual bool) []string {
 		if labelMetrics := ttl.LabelMetrics; labelMetrics {
 			appendStorageParam(`ttl_label_metrics`, fmt.Sprintf(`%t`, labelMetrics))
 		}
+		if storageExpiry := ttl.StorageExpiry; storageExpiry {
+			appendStorageParam(`ttl_storage_expiry`, fmt.Sprintf(`%t`, storageExpiry))
+		}
 	}
 	if exclude := desc.GetExcludeDataFromBackup(); exclude {
 		appendStorageParam(`exclude_data_from_backup`, `true`)
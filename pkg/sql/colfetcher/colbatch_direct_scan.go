@@ -208,8 +208,12 @@ func NewColBatchDirectScan(
 	// issued and even after it was responded to. In theory, we (the client)
 	// should be able to modify the BatchRequest, but alas.
 	fetchSpec := spec.FetchSpec
+	txn, err := row.GetKVTxn(ctx, flowCtx.Txn, &fetchSpec)
+	if err != nil {
+		return nil, nil, err
+	}
 	fetcher := row.NewDirectKVBatchFetcher(
-		flowCtx.Txn,
+		txn,
 		bsHeader,
 		&fetchSpec,
 		spec.Reverse,
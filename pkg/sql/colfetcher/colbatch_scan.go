@@ -344,8 +344,12 @@ func NewColBatchScan(
 	if err != nil {
 		return nil, nil, err
 	}
+	txn, err := row.GetKVTxn(ctx, flowCtx.Txn, &spec.FetchSpec)
+	if err != nil {
+		return nil, nil, err
+	}
 	kvFetcher := row.NewKVFetcher(
-		flowCtx.Txn,
+		txn,
 		bsHeader,
 		spec.Reverse,
 		spec.LockingStrength,
@@ -539,7 +539,7 @@ func NewColIndexJoin(
 	cFetcherMemoryLimit := totalMemoryLimit
 
 	var kvFetcher *row.KVFetcher
-	useStreamer, txn, err := flowCtx.UseStreamer()
+	useStreamer, txn, err := flowCtx.UseStreamer(&spec.FetchSpec)
 	if err != nil {
 		return nil, err
 	}
@@ -574,6 +574,10 @@ func NewColIndexJoin(
 			kvFetcherMemAcc,
 		)
 	} else {
+		txn, err = row.GetKVTxn(ctx, txn, &spec.FetchSpec)
+		if err != nil {
+			return nil, err
+		}
 		kvFetcher = row.NewKVFetcher(
 			txn,
 			nil,   /* bsHeader */
@@ -68,6 +68,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlstats/sslocal"
 	"github.com/cockroachdb/cockroach/pkg/sql/stmtdiagnostics"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
 	"github.com/cockroachdb/cockroach/pkg/util/buildutil"
 	"github.com/cockroachdb/cockroach/pkg/util/cancelchecker"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxlog"
@@ -1104,6 +1105,7 @@ func (s *Server) newConnExecutor(
 	ex.dataMutatorIterator.onTempSchemaCreation = func() {
 		ex.hasCreatedTemporarySchema = true
 	}
+	ex.transitionCtx.resourceGroup = ex.resourceGroup
 
 	ex.applicationName.Store(ex.sessionData().ApplicationName)
 	ex.applicationStats = applicationStats
@@ -3267,8 +3269,12 @@ func (ex *connExecutor) getRewindTxnCapability() (rewindCapability, bool) {
 
 // isCommit returns true if stmt is a "COMMIT" statement.
 func isCommit(stmt tree.Statement) bool {
-	_, ok := stmt.(*tree.CommitTransaction)
-	return ok
+	switch stmt.(type) {
+	case *tree.CommitTransaction, *tree.PrepareTransaction:
+		// PREPARE TRANSACTION ends the SQL transaction just like COMMIT does.
+		return true
+	}
+	return false
 }
 
 var retriableMinTimestampBoundUnsatisfiableError = errors.Newf(
@@ -3507,6 +3513,15 @@ func (ex *connExecutor) QualityOfService() sessiondatapb.QoSLevel {
 	return ex.sessionData().DefaultTxnQualityOfService
 }
 
+// resourceGroup returns the definition of the resource group that the work of
+// the session's transactions is admitted under.
+func (ex *connExecutor) resourceGroup() admissionpb.ResourceGroup {
+	if ex.sessionData() == nil {
+		return admissionpb.ResourceGroup{}
+	}
+	return ex.server.cfg.ResourceGroupCache.Get(ex.sessionData().ResourceGroup)
+}
+
 func (ex *connExecutor) readWriteModeWithSessionDefault(
 	mode tree.ReadWriteMode,
 ) tree.ReadWriteMode {
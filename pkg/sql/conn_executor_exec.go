@@ -170,7 +170,7 @@ func (ex *connExecutor) execStmt(
 	if ex.sessionData().IdleInTransactionSessionTimeout > 0 {
 		startIdleInTransactionSessionTimeout := func() {
 			switch ast.(type) {
-			case *tree.CommitTransaction, *tree.RollbackTransaction:
+			case *tree.CommitTransaction, *tree.RollbackTransaction, *tree.PrepareTransaction:
 				// Do nothing, the transaction is completed, we do not want to start
 				// an idle timer.
 			default:
@@ -842,6 +842,16 @@ func (ex *connExecutor) execStmtInOpenState(
 		ev, payload := ex.rollbackSQLTransaction(ctx, s)
 		return ev, payload, nil
 
+	case *tree.PrepareTransaction:
+		// PrepareTransaction is executed fully here; there's no plan for it.
+		if os.ImplicitTxn.Get() {
+			return makeErrEvent(errNoTransactionInProgress)
+		}
+		ev, payload := ex.commitSQLTransaction(ctx, ast, func(ctx context.Context) error {
+			return ex.prepareSQLTransactionInternal(ctx, s)
+		})
+		return ev, payload, nil
+
 	case *tree.Savepoint:
 		return ex.execSavepointInOpenState(ctx, s, res)
 
@@ -1315,6 +1325,7 @@ func (ex *connExecutor) resetTransactionOnSchemaChangeRetry(ctx context.Context)
 	userPriority := ex.state.mu.txn.UserPriority()
 	ex.state.mu.txn = kv.NewTxnWithSteppingEnabled(ctx, ex.transitionCtx.db,
 		ex.transitionCtx.nodeIDOrZero, ex.QualityOfService())
+	ex.state.mu.txn.SetAdmissionResourceGroup(ex.resourceGroup())
 	return ex.state.mu.txn.SetUserPriority(userPriority)
 }
 
@@ -2431,7 +2442,8 @@ func (ex *connExecutor) execStmtInNoTxnState(
 	case *tree.ShowCommitTimestamp:
 		return ex.execShowCommitTimestampInNoTxnState(ctx, s, res)
 	case *tree.CommitTransaction, *tree.ReleaseSavepoint,
-		*tree.RollbackTransaction, *tree.SetTransaction, *tree.Savepoint:
+		*tree.RollbackTransaction, *tree.SetTransaction, *tree.Savepoint,
+		*tree.PrepareTransaction:
 		return ex.makeErrEvent(errNoTransactionInProgress, ast)
 	default:
 		// NB: Implicit transactions are created with the session's default
@@ -1433,6 +1433,10 @@ type ExecutorConfig struct {
 	// SyntheticPrivilegeCache stores synthetic privileges in an in-memory cache.
 	SyntheticPrivilegeCache *syntheticprivilegecache.Cache
 
+	// ResourceGroupCache stores the resource groups defined in
+	// system.resource_groups in an in-memory cache.
+	ResourceGroupCache *ResourceGroupCache
+
 	// RangeStatsFetcher is used to fetch RangeStats.
 	RangeStatsFetcher eval.RangeStatsFetcher
 
@@ -2026,7 +2030,18 @@ func (p *planner) EvalAsOfTimestamp(
 		return eval.AsOfSystemTime{}, err
 	}
 	ts := asOf.Timestamp
-	if now := p.execCfg.Clock.Now(); now.Less(ts) && !ts.Synthetic {
+	now := p.execCfg.Clock.Now()
+	if now.Less(ts) && ts == p.SessionData().ReadAfterTimestamp &&
+		ts.GoTime().Sub(now.GoTime()) <= p.execCfg.Clock.MaxOffset() {
+		// The timestamp was forwarded to a causality token handed out by a
+		// gateway whose clock is ahead of ours. Wait out the clock skew instead
+		// of failing the read.
+		if err := p.execCfg.Clock.SleepUntil(ctx, ts); err != nil {
+			return eval.AsOfSystemTime{}, err
+		}
+		now = p.execCfg.Clock.Now()
+	}
+	if now.Less(ts) && !ts.Synthetic {
 		return eval.AsOfSystemTime{}, errors.Errorf(
 			"AS OF SYSTEM TIME: cannot specify timestamp in the future (%s > %s)", ts, now)
 	}
@@ -3376,6 +3391,14 @@ func (m *sessionDataMutator) SetQualityOfService(val sessiondatapb.QoSLevel) {
 	m.data.DefaultTxnQualityOfService = val.Validate()
 }
 
+func (m *sessionDataMutator) SetResourceGroup(val string) {
+	m.data.ResourceGroup = val
+}
+
+func (m *sessionDataMutator) SetReadAfterTimestamp(val hlc.Timestamp) {
+	m.data.ReadAfterTimestamp = val
+}
+
 func (m *sessionDataMutator) SetOptSplitScanLimit(val int32) {
 	m.data.OptSplitScanLimit = val
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangecache"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -209,11 +210,14 @@ func (h *LimitHintHelper) ReadSomeRows(rowsRead int64) error {
 	return nil
 }
 
-// UseStreamer returns whether the kvstreamer.Streamer API should be used as
-// well as the txn that should be used (regardless of the boolean return value).
-func (flowCtx *FlowCtx) UseStreamer() (bool, *kv.Txn, error) {
+// UseStreamer returns whether the kvstreamer.Streamer API should be used to
+// perform lookups described by the given fetch spec as well as the txn that
+// should be used (regardless of the boolean return value).
+func (flowCtx *FlowCtx) UseStreamer(spec *fetchpb.IndexFetchSpec) (bool, *kv.Txn, error) {
+	// The Streamer cannot be used when the rows are read from another tenant's
+	// keyspace since the lookups then aren't performed with the flow's txn.
 	useStreamer := flowCtx.EvalCtx.SessionData().StreamerEnabled && flowCtx.Txn != nil &&
-		flowCtx.Txn.Type() == kv.LeafTxn && flowCtx.MakeLeafTxn != nil
+		flowCtx.Txn.Type() == kv.LeafTxn && flowCtx.MakeLeafTxn != nil && spec.External == nil
 	if !useStreamer {
 		return false, flowCtx.Txn, nil
 	}
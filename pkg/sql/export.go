@@ -85,7 +85,9 @@ const (
 	parquetSuffix         = "parquet"
 )
 
-var exportOptionExpectValues = map[string]exprutil.KVStringOptValidate{
+// ExportOptionExpectValues are the options accepted by EXPORT, and whether
+// they require a value.
+var ExportOptionExpectValues = map[string]exprutil.KVStringOptValidate{
 	exportOptionChunkRows:   exprutil.KVStringOptRequireValue,
 	exportOptionDelimiter:   exprutil.KVStringOptRequireValue,
 	exportOptionFileName:    exprutil.KVStringOptRequireValue,
@@ -94,8 +96,8 @@ var exportOptionExpectValues = map[string]exprutil.KVStringOptValidate{
 	exportOptionChunkSize:   exprutil.KVStringOptRequireValue,
 }
 
-// featureExportEnabled is used to enable and disable the EXPORT feature.
-var featureExportEnabled = settings.RegisterBoolSetting(
+// FeatureExportEnabled is used to enable and disable the EXPORT feature.
+var FeatureExportEnabled = settings.RegisterBoolSetting(
 	settings.ApplicationLevel,
 	"feature.export.enabled",
 	"set to true to enable exports, false to disable; default is true",
@@ -113,7 +115,7 @@ func (ef *execFactory) ConstructExport(
 	ef.planner.BufferClientNotice(ef.ctx, pgnotice.Newf("EXPORT is not the recommended way to move data out "+
 		"of CockroachDB and may be deprecated in the future. Please consider exporting data with changefeeds instead: "+
 		"https://www.cockroachlabs.com/docs/stable/export-data-with-changefeeds"))
-	if !featureExportEnabled.Get(&ef.planner.ExecCfg().Settings.SV) {
+	if !FeatureExportEnabled.Get(&ef.planner.ExecCfg().Settings.SV) {
 		return nil, pgerror.Newf(
 			pgcode.OperatorIntervention,
 			"feature EXPORT was disabled by the database administrator",
@@ -124,7 +126,7 @@ func (ef *execFactory) ConstructExport(
 	if err := featureflag.CheckEnabled(
 		ef.ctx,
 		ef.planner.execCfg,
-		featureExportEnabled,
+		FeatureExportEnabled,
 		"EXPORT",
 	); err != nil {
 		return nil, err
@@ -177,7 +179,7 @@ func (ef *execFactory) ConstructExport(
 	for i, o := range options {
 		treeOptions[i] = tree.KVOption{Key: tree.Name(o.Key), Value: o.Value}
 	}
-	optVals, err := exprEval.KVOptions(ef.ctx, treeOptions, exportOptionExpectValues)
+	optVals, err := exprEval.KVOptions(ef.ctx, treeOptions, ExportOptionExpectValues)
 	if err != nil {
 		return nil, err
 	}
@@ -190,14 +192,40 @@ func (ef *execFactory) ConstructExport(
 		colNullability[i] = !notNullCols.Contains(i)
 	}
 
-	format := roachpb.IOFileFormat{}
+	format, chunkRows, chunkSize, err := ParseExportOptions(fileSuffix, optVals, colNullability)
+	if err != nil {
+		return nil, err
+	}
+
+	exportID := ef.planner.stmt.QueryID.String()
+	exportFilePattern := exportFilePatternPart + "." + fileSuffix
+	namePattern := fmt.Sprintf("export%s-%s", exportID, exportFilePattern)
+	return &exportNode{
+		source:          input.(planNode),
+		destination:     string(*destination),
+		fileNamePattern: namePattern,
+		format:          format,
+		chunkRows:       chunkRows,
+		chunkSize:       chunkSize,
+		colNames:        colNames,
+	}, nil
+}
+
+// ParseExportOptions returns the file format and chunking parameters for an
+// EXPORT into files of the given suffix (csv or parquet) with the given
+// options. colNullability specifies, for each exported column, whether it
+// may contain NULLs.
+func ParseExportOptions(
+	fileSuffix string, optVals map[string]string, colNullability []bool,
+) (format roachpb.IOFileFormat, chunkRows int, chunkSize int64, _ error) {
+	var err error
 	switch fileSuffix {
 	case csvSuffix:
 		csvOpts := roachpb.CSVOptions{}
 		if override, ok := optVals[exportOptionDelimiter]; ok {
 			csvOpts.Comma, err = util.GetSingleRune(override)
 			if err != nil {
-				return nil, pgerror.New(pgcode.InvalidParameterValue, "invalid delimiter")
+				return roachpb.IOFileFormat{}, 0, 0, pgerror.New(pgcode.InvalidParameterValue, "invalid delimiter")
 			}
 		}
 		if override, ok := optVals[exportOptionNullAs]; ok {
@@ -213,25 +241,25 @@ func (ef *execFactory) ConstructExport(
 		format.Parquet = parquetOpts
 	}
 
-	chunkRows := exportChunkRowsDefault
+	chunkRows = exportChunkRowsDefault
 	if override, ok := optVals[exportOptionChunkRows]; ok {
 		chunkRows, err = strconv.Atoi(override)
 		if err != nil {
-			return nil, pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
+			return roachpb.IOFileFormat{}, 0, 0, pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
 		}
 		if chunkRows < 1 {
-			return nil, pgerror.New(pgcode.InvalidParameterValue, "invalid csv chunk rows")
+			return roachpb.IOFileFormat{}, 0, 0, pgerror.New(pgcode.InvalidParameterValue, "invalid csv chunk rows")
 		}
 	}
 
-	chunkSize := exportChunkSizeDefault
+	chunkSize = exportChunkSizeDefault
 	if override, ok := optVals[exportOptionChunkSize]; ok {
 		chunkSize, err = humanizeutil.ParseBytes(override)
 		if err != nil {
-			return nil, pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
+			return roachpb.IOFileFormat{}, 0, 0, pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
 		}
 		if chunkSize < 1 {
-			return nil, pgerror.New(pgcode.InvalidParameterValue, "invalid csv chunk size")
+			return roachpb.IOFileFormat{}, 0, 0, pgerror.New(pgcode.InvalidParameterValue, "invalid csv chunk size")
 		}
 	}
 
@@ -245,22 +273,10 @@ func (ef *execFactory) ConstructExport(
 		case strings.EqualFold(name, exportSnappyCodec) && fileSuffix == parquetSuffix:
 			codec = roachpb.IOFileFormat_Snappy
 		default:
-			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			return roachpb.IOFileFormat{}, 0, 0, pgerror.Newf(pgcode.InvalidParameterValue,
 				"unsupported compression codec %s for %s file format", name, fileSuffix)
 		}
 		format.Compression = codec
 	}
-
-	exportID := ef.planner.stmt.QueryID.String()
-	exportFilePattern := exportFilePatternPart + "." + fileSuffix
-	namePattern := fmt.Sprintf("export%s-%s", exportID, exportFilePattern)
-	return &exportNode{
-		source:          input.(planNode),
-		destination:     string(*destination),
-		fileNamePattern: namePattern,
-		format:          format,
-		chunkRows:       chunkRows,
-		chunkSize:       chunkSize,
-		colNames:        colNames,
-	}, nil
+	return format, chunkRows, chunkSize, nil
 }
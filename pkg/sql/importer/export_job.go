@@ -0,0 +1,737 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	crlparquet "github.com/cockroachdb/cockroach/pkg/util/parquet"
+	"github.com/cockroachdb/errors"
+)
+
+type exportResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &exportResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+func (r *exportResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.ExportDetails)
+
+	// The table's rows are read as of the export's end time, so its descriptor
+	// is too.
+	var desc catalog.TableDescriptor
+	if err := execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		if err := txn.KV().SetFixedTimestamp(ctx, details.EndTime); err != nil {
+			return err
+		}
+		var err error
+		desc, err = txn.Descriptors().ByID(txn.KV()).WithoutNonPublic().Get().Table(ctx, details.TableID)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	es, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, details.Destination, p.User())
+	if err != nil {
+		return err
+	}
+	defer es.Close()
+
+	e, err := newTableExporter(ctx, execCfg, r.job, desc, es)
+	if err != nil {
+		return err
+	}
+	if err := e.run(ctx); err != nil {
+		return err
+	}
+	if err := writeExportManifest(ctx, es, r.job.ID(), desc, details, e.prog); err != nil {
+		return err
+	}
+
+	return execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		return r.releaseProtectedTimestamp(ctx, execCfg.ProtectedTimestampProvider.WithTxn(txn))
+	})
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. Files which were
+// already written to the destination are left in place.
+func (r *exportResumer) OnFailOrCancel(
+	ctx context.Context, execCtx interface{}, jobErr error,
+) error {
+	cfg := execCtx.(sql.JobExecContext).ExecCfg()
+	return cfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		return r.releaseProtectedTimestamp(ctx, cfg.ProtectedTimestampProvider.WithTxn(txn))
+	})
+}
+
+// CollectProfile is a part of the Resumer interface.
+func (r *exportResumer) CollectProfile(_ context.Context, _ interface{}) error {
+	return nil
+}
+
+func (r *exportResumer) releaseProtectedTimestamp(
+	ctx context.Context, pts protectedts.Storage,
+) error {
+	details := r.job.Details().(jobspb.ExportDetails)
+	ptsID := details.ProtectedTimestampRecord
+	if ptsID == nil {
+		return nil
+	}
+	err := pts.Release(ctx, *ptsID)
+	if errors.Is(err, protectedts.ErrNotExists) {
+		log.Warningf(ctx, "failed to release protected which seems not to exist: %v", err)
+		err = nil
+	}
+	return err
+}
+
+// ReportResults implements JobResultsReporter interface.
+func (r *exportResumer) ReportResults(ctx context.Context, resultsCh chan<- tree.Datums) error {
+	prog := r.job.Progress().GetExport()
+	select {
+	case resultsCh <- tree.Datums{
+		tree.NewDInt(tree.DInt(r.job.ID())),
+		tree.NewDString(string(jobs.StatusSucceeded)),
+		tree.NewDFloat(tree.DFloat(1.0)),
+		tree.NewDInt(tree.DInt(prog.Rows)),
+		tree.NewDInt(tree.DInt(0)),
+		tree.NewDInt(tree.DInt(prog.Bytes)),
+	}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// writeExportManifest writes the manifest describing a completed export to
+// its destination.
+func writeExportManifest(
+	ctx context.Context,
+	es cloud.ExternalStorage,
+	jobID jobspb.JobID,
+	desc catalog.TableDescriptor,
+	details jobspb.ExportDetails,
+	prog jobspb.ExportProgress,
+) error {
+	m := exportManifest{
+		JobID:   jobID,
+		TableID: desc.GetID(),
+		Table:   desc.GetName(),
+		Format:  details.Format.Format.String(),
+		EndTime: details.EndTime.AsOfSystemTime(),
+		Files:   prog.Files,
+		Rows:    prog.Rows,
+	}
+	if !details.StartTime.IsEmpty() {
+		m.StartTime = details.StartTime.AsOfSystemTime()
+		m.IncrementalFrom = redactURI(details.IncrementalFrom)
+	}
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return cloud.WriteFile(ctx, es, exportManifestName, bytes.NewReader(raw))
+}
+
+// exportedRow is a row of the table which was changed in the interval being
+// exported, along with its KVs as of the end of the interval. A row which was
+// deleted has no KVs.
+type exportedRow struct {
+	prefix roachpb.Key
+	kvs    []roachpb.KeyValue
+}
+
+// tableExporter exports the rows of a table by reading its primary index with
+// ExportRequests, and writes them out to files in the export's destination.
+//
+// Progress is checkpointed after each ExportRequest: the span that was read
+// is recorded as completed once the rows in it have been written out.
+type tableExporter struct {
+	execCfg *sql.ExecutorConfig
+	job     *jobs.Job
+	details jobspb.ExportDetails
+	desc    catalog.TableDescriptor
+	es      cloud.ExternalStorage
+
+	// incremental is set if only the rows changed since the details' start
+	// time are exported. Such exports have an additional column saying whether
+	// each row was upserted or deleted.
+	incremental bool
+	cols        []catalog.Column
+
+	fetcher    row.Fetcher
+	kvProvider row.KVProvider
+	alloc      tree.DatumAlloc
+
+	// keyColTypes and keyColDirs describe the primary key of the table, and
+	// keyColOrds are the ordinals of its columns in cols. They are used to
+	// decode the primary keys of deleted rows.
+	keyColTypes []*types.T
+	keyColDirs  []catenumpb.IndexColumn_Direction
+	keyColOrds  []int
+
+	out      exportFileWriter
+	outRows  int64
+	rowAlloc tree.Datums
+
+	completed roachpb.SpanGroup
+	prog      jobspb.ExportProgress
+}
+
+func newTableExporter(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	job *jobs.Job,
+	desc catalog.TableDescriptor,
+	es cloud.ExternalStorage,
+) (*tableExporter, error) {
+	details := job.Details().(jobspb.ExportDetails)
+	e := &tableExporter{
+		execCfg:     execCfg,
+		job:         job,
+		details:     details,
+		desc:        desc,
+		es:          es,
+		incremental: !details.StartTime.IsEmpty(),
+		cols:        exportColumns(desc),
+		prog:        *job.Progress().GetExport(),
+	}
+	e.completed.Add(e.prog.CompletedSpans...)
+
+	colIDs := make([]descpb.ColumnID, len(e.cols))
+	colOrds := make(map[descpb.ColumnID]int, len(e.cols))
+	colNames := make([]string, len(e.cols))
+	colTypes := make([]*types.T, len(e.cols))
+	for i, col := range e.cols {
+		colIDs[i] = col.GetID()
+		colOrds[col.GetID()] = i
+		colNames[i] = col.GetName()
+		colTypes[i] = col.GetType()
+	}
+	primaryIndex := desc.GetPrimaryIndex()
+	for i := 0; i < primaryIndex.NumKeyColumns(); i++ {
+		id := primaryIndex.GetKeyColumnID(i)
+		col := catalog.FindColumnByID(desc, id)
+		if col == nil {
+			return nil, errors.AssertionFailedf("primary key column %d not found", id)
+		}
+		e.keyColTypes = append(e.keyColTypes, col.GetType())
+		e.keyColDirs = append(e.keyColDirs, primaryIndex.GetKeyColumnDirection(i))
+		ord, ok := colOrds[id]
+		if !ok {
+			// Virtual columns cannot be exported, so their values are dropped.
+			ord = -1
+		}
+		e.keyColOrds = append(e.keyColOrds, ord)
+	}
+
+	var spec fetchpb.IndexFetchSpec
+	if err := rowenc.InitIndexFetchSpec(
+		&spec, execCfg.Codec, desc, primaryIndex, colIDs,
+	); err != nil {
+		return nil, err
+	}
+	if err := e.fetcher.Init(ctx, row.FetcherInitArgs{
+		WillUseKVProvider: true,
+		Alloc:             &e.alloc,
+		Spec:              &spec,
+	}); err != nil {
+		return nil, err
+	}
+
+	if e.incremental {
+		colNames = append(colNames, exportOpColumnName)
+		colTypes = append(colTypes, types.String)
+	}
+	e.rowAlloc = make(tree.Datums, len(colTypes))
+	exportSpec := execinfrapb.ExportSpec{
+		Format:      details.Format,
+		NamePattern: "export" + strconv.FormatInt(int64(job.ID()), 10) + "-" + exportFilePatternPart,
+	}
+	switch details.Format.Format {
+	case roachpb.IOFileFormat_CSV:
+		exportSpec.NamePattern += ".csv"
+		e.out = &csvFileWriter{spec: exportSpec, csv: newCSVExporter(exportSpec)}
+	case roachpb.IOFileFormat_Parquet:
+		exportSpec.NamePattern += ".parquet"
+		sch, err := crlparquet.NewSchema(colNames, colTypes)
+		if err != nil {
+			return nil, err
+		}
+		compression, err := parquetCompressionCodec(details.Format.Compression)
+		if err != nil {
+			return nil, err
+		}
+		e.out = &parquetFileWriter{spec: exportSpec, sch: sch, compression: compression}
+	default:
+		return nil, errors.AssertionFailedf("unsupported export format %s", details.Format.Format)
+	}
+	if err := e.out.reset(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// run exports the parts of the table's spans that have not been completed by
+// a previous run of the job.
+func (e *tableExporter) run(ctx context.Context) error {
+	remaining := roachpb.SubtractSpans(
+		append(roachpb.Spans(nil), e.details.Spans...), e.completed.Slice(),
+	)
+	for _, span := range remaining {
+		if err := e.exportSpan(ctx, span); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *tableExporter) exportSpan(ctx context.Context, span roachpb.Span) error {
+	// start is the beginning of the part of span that has not been
+	// checkpointed as completed.
+	start := span.Key
+	resumeKey := span.Key
+	// carry holds the KVs of a row that was split across two responses. The
+	// row is written out, and the span containing it checkpointed, once the
+	// remainder of its KVs are read.
+	var carry []roachpb.KeyValue
+	for resumeKey != nil {
+		resp, err := e.sendExportRequest(ctx, roachpb.Span{Key: resumeKey, EndKey: span.EndKey})
+		if err != nil {
+			return err
+		}
+		kvs := carry
+		carry = nil
+		for _, file := range resp.Files {
+			if kvs, err = appendSSTKVs(kvs, file.SST); err != nil {
+				return err
+			}
+		}
+
+		checkpoint := span.EndKey
+		resumeKey = nil
+		if resp.ResumeSpan != nil {
+			resumeKey = resp.ResumeSpan.Key
+			checkpoint = resumeKey
+			if len(kvs) > 0 {
+				lastRow, err := keys.EnsureSafeSplitKey(kvs[len(kvs)-1].Key)
+				if err != nil {
+					return err
+				}
+				if bytes.HasPrefix(resumeKey, lastRow) {
+					// The response ended in the middle of a row. Rows are not split
+					// across files, so hold its KVs back until the rest are read.
+					i := len(kvs)
+					for i > 0 && bytes.HasPrefix(kvs[i-1].Key, lastRow) {
+						i--
+					}
+					kvs, carry = kvs[:i], kvs[i:]
+					checkpoint = lastRow
+				}
+			}
+		}
+
+		if err := e.writeRows(ctx, kvs); err != nil {
+			return err
+		}
+		if bytes.Compare(start, checkpoint) < 0 {
+			if err := e.checkpoint(ctx, roachpb.Span{Key: start, EndKey: checkpoint}); err != nil {
+				return err
+			}
+			start = checkpoint
+		}
+	}
+	return nil
+}
+
+func (e *tableExporter) sendExportRequest(
+	ctx context.Context, span roachpb.Span,
+) (*kvpb.ExportResponse, error) {
+	req := &kvpb.ExportRequest{
+		RequestHeader:  kvpb.RequestHeaderFromSpan(span),
+		MVCCFilter:     kvpb.MVCCFilter_Latest,
+		StartTime:      e.details.StartTime,
+		TargetFileSize: e.details.ChunkSize,
+	}
+	header := kvpb.Header{
+		// The sentinel value of 1 makes the ExportRequest paginate after
+		// creating a single SST, so that progress can be checkpointed.
+		TargetBytes: 1,
+		Timestamp:   e.details.EndTime,
+	}
+	rawResp, pErr := kv.SendWrappedWith(ctx, e.execCfg.DB.NonTransactionalSender(), header, req)
+	if pErr != nil {
+		if _, ok := pErr.GetDetail().(*kvpb.BatchTimestampBeforeGCError); ok && e.incremental {
+			return nil, errors.WithHint(
+				errors.Wrapf(pErr.GoError(), "exporting changes since %s", e.details.StartTime),
+				"the changes since the previous export have been garbage collected; "+
+					"take a full export of the table instead")
+		}
+		return nil, errors.Wrapf(pErr.GoError(), "exporting %s", span)
+	}
+	return rawResp.(*kvpb.ExportResponse), nil
+}
+
+// appendSSTKVs appends the KVs in an SST returned by an ExportRequest to kvs.
+// Deletion tombstones are appended as KVs with empty values.
+func appendSSTKVs(kvs []roachpb.KeyValue, sst []byte) ([]roachpb.KeyValue, error) {
+	it, err := storage.NewMemSSTIterator(sst, false /* verify */, storage.IterOptions{
+		KeyTypes:   storage.IterKeyTypePointsAndRanges,
+		LowerBound: keys.MinKey,
+		UpperBound: keys.MaxKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	for it.SeekGE(storage.NilKey); ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			return nil, err
+		} else if !ok {
+			return kvs, nil
+		}
+		hasPoint, hasRange := it.HasPointAndRange()
+		if hasRange {
+			// MVCC range tombstones are only written to tables which are offline,
+			// e.g. when an IMPORT INTO is rolled back, so rows deleted by them
+			// cannot be attributed to individual keys here.
+			return nil, errors.Newf(
+				"cannot export changes to rows deleted by a range tombstone at %s",
+				it.RangeBounds())
+		}
+		if !hasPoint {
+			continue
+		}
+		k := it.UnsafeKey()
+		v, err := it.UnsafeValue()
+		if err != nil {
+			return nil, err
+		}
+		mvccValue, err := storage.DecodeMVCCValue(v)
+		if err != nil {
+			return nil, err
+		}
+		kv := roachpb.KeyValue{Key: k.Key.Clone()}
+		if !mvccValue.IsTombstone() {
+			kv.Value.RawBytes = append([]byte(nil), mvccValue.Value.RawBytes...)
+		}
+		kv.Value.Timestamp = k.Timestamp
+		kvs = append(kvs, kv)
+	}
+}
+
+// groupRows groups KVs, which are sorted by key, into the rows they belong
+// to. Deleted KVs are dropped.
+func groupRows(kvs []roachpb.KeyValue) ([]exportedRow, error) {
+	var rows []exportedRow
+	for _, kv := range kvs {
+		prefix, err := keys.EnsureSafeSplitKey(kv.Key)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 || !rows[len(rows)-1].prefix.Equal(prefix) {
+			rows = append(rows, exportedRow{prefix: prefix})
+		}
+		if kv.Value.IsPresent() {
+			r := &rows[len(rows)-1]
+			r.kvs = append(r.kvs, kv)
+		}
+	}
+	return rows, nil
+}
+
+// readRows reads the rows with the given prefixes as of the export's end
+// time. An incremental export of a table with several column families only
+// reads the families of a row which changed, so the rest are read this way.
+func (e *tableExporter) readRows(ctx context.Context, rows []exportedRow) error {
+	return e.execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		if err := txn.SetFixedTimestamp(ctx, e.details.EndTime); err != nil {
+			return err
+		}
+		b := txn.NewBatch()
+		for _, r := range rows {
+			b.Scan(r.prefix, r.prefix.PrefixEnd())
+		}
+		if err := txn.Run(ctx, b); err != nil {
+			return err
+		}
+		for i := range rows {
+			rows[i].kvs = rows[i].kvs[:0]
+			for _, res := range b.Results[i].Rows {
+				rows[i].kvs = append(rows[i].kvs, roachpb.KeyValue{Key: res.Key, Value: *res.Value})
+			}
+		}
+		return nil
+	})
+}
+
+// writeRows writes the rows which the KVs belong to out to files.
+func (e *tableExporter) writeRows(ctx context.Context, kvs []roachpb.KeyValue) error {
+	rows, err := groupRows(kvs)
+	if err != nil {
+		return err
+	}
+	if e.incremental && e.desc.NumFamilies() > 1 {
+		if err := e.readRows(ctx, rows); err != nil {
+			return err
+		}
+	}
+	for _, r := range rows {
+		deleted := len(r.kvs) == 0
+		if deleted && !e.incremental {
+			continue
+		}
+		datums, err := e.decodeRow(ctx, r)
+		if err != nil {
+			return err
+		}
+		if err := e.out.addRow(datums, deleted); err != nil {
+			return err
+		}
+		e.outRows++
+		if e.outRows >= e.details.ChunkRows || int64(e.out.len()) >= e.details.ChunkSize {
+			if err := e.flushFile(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	// Files are never left open across ExportRequests, so that all the rows
+	// read by a request have been written out when its span is checkpointed.
+	return e.flushFile(ctx)
+}
+
+// decodeRow returns the datums of the columns being exported for the row. For
+// a deleted row, only the columns in the primary key are set.
+func (e *tableExporter) decodeRow(ctx context.Context, r exportedRow) (tree.Datums, error) {
+	datums := e.rowAlloc
+	if len(r.kvs) == 0 {
+		keyDatums, err := rowenc.DecodeIndexKeyToDatums(
+			e.execCfg.Codec, e.keyColTypes, e.keyColDirs, r.prefix, &e.alloc,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for i := range e.cols {
+			datums[i] = tree.DNull
+		}
+		for i, d := range keyDatums {
+			if ord := e.keyColOrds[i]; ord >= 0 {
+				datums[ord] = d
+			}
+		}
+	} else {
+		e.kvProvider.KVs = r.kvs
+		if err := e.fetcher.ConsumeKVProvider(ctx, &e.kvProvider); err != nil {
+			return nil, err
+		}
+		decoded, err := e.fetcher.NextRowDecoded(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if decoded == nil {
+			return nil, errors.AssertionFailedf("no row decoded from %s", r.prefix)
+		}
+		copy(datums, decoded)
+	}
+	if e.incremental {
+		op := exportOpUpsert
+		if len(r.kvs) == 0 {
+			op = exportOpDelete
+		}
+		datums[len(e.cols)] = tree.NewDString(op)
+	}
+	return datums, nil
+}
+
+// flushFile writes the rows added to the current file out to the destination,
+// and starts a new file.
+func (e *tableExporter) flushFile(ctx context.Context) error {
+	if e.outRows == 0 {
+		return nil
+	}
+	data, err := e.out.finish()
+	if err != nil {
+		return err
+	}
+	name := e.out.fileName(strconv.Itoa(int(e.prog.NextFileIndex)))
+	if err := cloud.WriteFile(ctx, e.es, name, bytes.NewReader(data)); err != nil {
+		return err
+	}
+	e.prog.NextFileIndex++
+	e.prog.Files = append(e.prog.Files, name)
+	e.prog.Rows += e.outRows
+	e.prog.Bytes += int64(len(data))
+	e.outRows = 0
+	return e.out.reset()
+}
+
+// checkpoint records that the rows in the span have been written out.
+func (e *tableExporter) checkpoint(ctx context.Context, span roachpb.Span) error {
+	e.completed.Add(span)
+	e.prog.CompletedSpans = e.completed.Slice()
+	var done int
+	for _, sp := range e.details.Spans {
+		if e.completed.Encloses(sp) {
+			done++
+		}
+	}
+	return e.job.NoTxn().FractionProgressed(ctx, func(
+		ctx context.Context, details jobspb.ProgressDetails,
+	) float32 {
+		*details.(*jobspb.Progress_Export).Export = e.prog
+		return float32(done) / float32(len(e.details.Spans))
+	})
+}
+
+// exportFileWriter encodes the rows of an exported file.
+type exportFileWriter interface {
+	// addRow adds a row to the file. deleted is set for the delete markers of
+	// an incremental export, whose non-key columns are NULL.
+	addRow(row tree.Datums, deleted bool) error
+	// len returns the number of bytes of the file that are buffered.
+	len() int
+	// finish returns the contents of the file.
+	finish() ([]byte, error)
+	// reset starts a new file.
+	reset() error
+	fileName(part string) string
+}
+
+type csvFileWriter struct {
+	spec execinfrapb.ExportSpec
+	csv  *csvExporter
+	rec  []string
+}
+
+var _ exportFileWriter = &csvFileWriter{}
+
+func (w *csvFileWriter) addRow(row tree.Datums, deleted bool) error {
+	f := tree.NewFmtCtx(tree.FmtExport)
+	defer f.Close()
+	w.rec = w.rec[:0]
+	for _, d := range row {
+		if d == tree.DNull {
+			switch {
+			case w.spec.Format.Csv.NullEncoding != nil:
+				w.rec = append(w.rec, *w.spec.Format.Csv.NullEncoding)
+			case deleted:
+				w.rec = append(w.rec, "")
+			default:
+				return errors.New("NULL value encountered during EXPORT, " +
+					"use `WITH nullas` to specify the string representation of NULL")
+			}
+			continue
+		}
+		d.Format(f)
+		w.rec = append(w.rec, f.String())
+		f.Reset()
+	}
+	return w.csv.Write(w.rec)
+}
+
+func (w *csvFileWriter) len() int {
+	return w.csv.Len()
+}
+
+func (w *csvFileWriter) finish() ([]byte, error) {
+	if err := w.csv.Flush(); err != nil {
+		return nil, errors.Wrap(err, "failed to flush csv writer")
+	}
+	if err := w.csv.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to close exporting writer")
+	}
+	return w.csv.Bytes(), nil
+}
+
+func (w *csvFileWriter) reset() error {
+	w.csv.ResetBuffer()
+	return nil
+}
+
+func (w *csvFileWriter) fileName(part string) string {
+	return w.csv.FileName(w.spec, part)
+}
+
+type parquetFileWriter struct {
+	spec        execinfrapb.ExportSpec
+	sch         *crlparquet.SchemaDefinition
+	compression crlparquet.CompressionCodec
+	buf         bytes.Buffer
+	writer      *crlparquet.Writer
+}
+
+var _ exportFileWriter = &parquetFileWriter{}
+
+func (w *parquetFileWriter) addRow(row tree.Datums, _ bool) error {
+	return w.writer.AddRow(row)
+}
+
+func (w *parquetFileWriter) len() int {
+	return w.buf.Len()
+}
+
+func (w *parquetFileWriter) finish() ([]byte, error) {
+	if err := w.writer.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to close parquet writer")
+	}
+	return w.buf.Bytes(), nil
+}
+
+func (w *parquetFileWriter) reset() error {
+	w.buf.Reset()
+	var err error
+	w.writer, err = crlparquet.NewWriter(w.sch, &w.buf, crlparquet.WithCompressionCodec(w.compression))
+	return err
+}
+
+func (w *parquetFileWriter) fileName(part string) string {
+	return fileName(w.spec, part)
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeExport,
+		func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+			return &exportResumer{job: job}
+		},
+		jobs.UsesTenantCostControl,
+	)
+}
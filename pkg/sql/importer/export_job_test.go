@@ -0,0 +1,108 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package importer_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// readExportedRows returns the sorted lines of the CSV files listed in the
+// manifest of the export in dir.
+func readExportedRows(t *testing.T, dir string) []string {
+	raw, err := os.ReadFile(filepath.Join(dir, "export-manifest.json"))
+	require.NoError(t, err)
+	var manifest struct {
+		Files []string `json:"files"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &manifest))
+
+	var rows []string
+	for _, f := range manifest.Files {
+		content, err := os.ReadFile(filepath.Join(dir, f))
+		require.NoError(t, err)
+		rows = append(rows, strings.Split(strings.TrimSpace(string(content)), "\n")...)
+	}
+	sort.Strings(rows)
+	return rows
+}
+
+func TestExportJobIncremental(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	for _, tc := range []struct {
+		name   string
+		schema string
+	}{
+		{name: "single-family", schema: "CREATE TABLE %s (k INT PRIMARY KEY, v STRING, w INT)"},
+		{name: "multi-family", schema: "CREATE TABLE %s (k INT PRIMARY KEY, v STRING, w INT, FAMILY (k, v), FAMILY (w))"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			table := strings.ReplaceAll(tc.name, "-", "_")
+			sqlDB.Exec(t, strings.Replace(tc.schema, "%s", table, 1))
+			sqlDB.Exec(t, "INSERT INTO "+table+" SELECT i, 'v' || i::STRING, i FROM generate_series(1, 5) AS g(i)")
+
+			full := "nodelocal://1/" + table + "/full"
+			sqlDB.Exec(t, "EXPORT INTO CSV $1 WITH resumable FROM TABLE "+table, full)
+			require.Equal(t, []string{
+				"1,v1,1", "2,v2,2", "3,v3,3", "4,v4,4", "5,v5,5",
+			}, readExportedRows(t, filepath.Join(dir, table, "full")))
+
+			sqlDB.Exec(t, "UPDATE "+table+" SET w = 20 WHERE k = 2")
+			sqlDB.Exec(t, "DELETE FROM "+table+" WHERE k = 3")
+			sqlDB.Exec(t, "INSERT INTO "+table+" VALUES (6, 'v6', 6)")
+
+			inc := "nodelocal://1/" + table + "/inc"
+			sqlDB.Exec(t, "EXPORT INTO CSV $1 INCREMENTAL FROM $2 FROM TABLE "+table, inc, full)
+			require.Equal(t, []string{
+				"2,v2,20,upsert", "3,,,delete", "6,v6,6,upsert",
+			}, readExportedRows(t, filepath.Join(dir, table, "inc")))
+
+			// An incremental export with no changes since the previous one is
+			// empty, but can itself be used as the base of another.
+			inc2 := "nodelocal://1/" + table + "/inc2"
+			sqlDB.Exec(t, "EXPORT INTO CSV $1 INCREMENTAL FROM $2 FROM TABLE "+table, inc2, inc)
+			require.Empty(t, readExportedRows(t, filepath.Join(dir, table, "inc2")))
+		})
+	}
+
+	t.Run("mismatched table", func(t *testing.T) {
+		sqlDB.Exec(t, "CREATE TABLE other (k INT PRIMARY KEY)")
+		sqlDB.ExpectErr(t, "contains an export of table single_family, not of other",
+			"EXPORT INTO CSV 'nodelocal://1/other' INCREMENTAL FROM 'nodelocal://1/single_family/full' FROM TABLE other")
+	})
+
+	t.Run("requires table", func(t *testing.T) {
+		sqlDB.ExpectErr(t, "can only export a table using FROM TABLE",
+			"EXPORT INTO CSV 'nodelocal://1/query' WITH resumable FROM SELECT 1")
+	})
+}
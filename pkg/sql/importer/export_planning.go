@@ -0,0 +1,473 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudprivilege"
+	"github.com/cockroachdb/cockroach/pkg/featureflag"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobsprotectedts"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// exportManifestName is the name of the file, written to the destination
+	// of an export run as a job once it completes, which describes the export.
+	// A later incremental export reads it to learn where to start from.
+	exportManifestName = "export-manifest.json"
+
+	// exportOpColumnName is the name of the column, appended to the rows of an
+	// incremental export, which says whether the row was upserted or deleted.
+	exportOpColumnName = "__crdb_export_op__"
+	exportOpUpsert     = "upsert"
+	exportOpDelete     = "delete"
+)
+
+// exportJobOptionExpectValues are the options accepted by an EXPORT which is
+// run as a job.
+var exportJobOptionExpectValues = func() map[string]exprutil.KVStringOptValidate {
+	opts := map[string]exprutil.KVStringOptValidate{
+		tree.ExportOptionDetached:  exprutil.KVStringOptRequireNoValue,
+		tree.ExportOptionResumable: exprutil.KVStringOptRequireNoValue,
+	}
+	for k, v := range sql.ExportOptionExpectValues {
+		opts[k] = v
+	}
+	return opts
+}()
+
+// exportManifest describes a completed export of a table that was run as a
+// job.
+type exportManifest struct {
+	JobID   jobspb.JobID `json:"job_id"`
+	TableID descpb.ID    `json:"table_id"`
+	Table   string       `json:"table"`
+	Format  string       `json:"format"`
+	// StartTime is only set for incremental exports, which contain the changes
+	// made in (StartTime, EndTime].
+	StartTime       string   `json:"start_time,omitempty"`
+	EndTime         string   `json:"end_time"`
+	IncrementalFrom string   `json:"incremental_from,omitempty"`
+	Files           []string `json:"files"`
+	Rows            int64    `json:"rows"`
+}
+
+// readExportManifest reads the manifest of the export at the given URI.
+func readExportManifest(
+	ctx context.Context,
+	makeExternalStorageFromURI cloud.ExternalStorageFromURIFactory,
+	uri string,
+	user username.SQLUsername,
+) (exportManifest, error) {
+	var m exportManifest
+	es, err := makeExternalStorageFromURI(ctx, uri, user)
+	if err != nil {
+		return m, err
+	}
+	defer es.Close()
+	r, _, err := es.ReadFile(ctx, exportManifestName, cloud.ReadOptions{NoFileSize: true})
+	if err != nil {
+		if errors.Is(err, cloud.ErrFileDoesNotExist) {
+			return m, pgerror.Wrapf(err, pgcode.UndefinedFile,
+				"%s does not contain a completed export", redactURI(uri))
+		}
+		return m, err
+	}
+	defer r.Close(ctx)
+	raw, err := ioctx.ReadAll(ctx, r)
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return m, errors.Wrapf(err, "decoding export manifest in %s", redactURI(uri))
+	}
+	return m, nil
+}
+
+// redactURI returns the URI with any secrets removed, for use in errors.
+func redactURI(uri string) string {
+	clean, err := cloud.SanitizeExternalStorageURI(uri, nil /* extraParams */)
+	if err != nil {
+		return "<unparseable URI>"
+	}
+	return clean
+}
+
+// exportTableName returns the name of the table exported by an EXPORT ...
+// FROM TABLE statement. Exports run as a job read a table's primary index
+// directly, so they cannot export the results of arbitrary queries.
+func exportTableName(query *tree.Select) (*tree.TableName, error) {
+	errNotTable := pgerror.Newf(pgcode.FeatureNotSupported,
+		"EXPORT with INCREMENTAL FROM, %s or %s can only export a table using FROM TABLE <table>",
+		tree.ExportOptionResumable, tree.ExportOptionDetached)
+	if query.With != nil || query.OrderBy != nil || query.Limit != nil {
+		return nil, errNotTable
+	}
+	sel, ok := query.Select.(*tree.SelectClause)
+	if !ok || !sel.TableSelect || len(sel.From.Tables) != 1 {
+		return nil, errNotTable
+	}
+	aliased, ok := sel.From.Tables[0].(*tree.AliasedTableExpr)
+	if !ok || aliased.IndexFlags != nil || aliased.Ordinality {
+		return nil, errNotTable
+	}
+	tn, ok := aliased.Expr.(*tree.TableName)
+	if !ok {
+		return nil, errNotTable
+	}
+	return tn, nil
+}
+
+func exportJobDescription(
+	p sql.PlanHookState,
+	orig *tree.Export,
+	tn *tree.TableName,
+	destination, incrementalFrom string,
+	opts map[string]string,
+) (string, error) {
+	stmt := *orig
+	clean, err := cloud.SanitizeExternalStorageURI(destination, nil /* extraParams */)
+	if err != nil {
+		return "", err
+	}
+	stmt.File = tree.NewDString(clean)
+	if incrementalFrom != "" {
+		clean, err := cloud.SanitizeExternalStorageURI(incrementalFrom, nil /* extraParams */)
+		if err != nil {
+			return "", err
+		}
+		stmt.IncrementalFrom = tree.NewDString(clean)
+	}
+	stmt.Query = &tree.Select{Select: &tree.SelectClause{
+		Exprs:       tree.SelectExprs{tree.StarSelectExpr()},
+		From:        tree.From{Tables: tree.TableExprs{tn}},
+		TableSelect: true,
+	}}
+	stmt.Options = nil
+	for k, v := range opts {
+		opt := tree.KVOption{Key: tree.Name(k)}
+		if exportJobOptionExpectValues[k] == exprutil.KVStringOptRequireValue {
+			opt.Value = tree.NewDString(v)
+		}
+		stmt.Options = append(stmt.Options, opt)
+	}
+	sort.Slice(stmt.Options, func(i, j int) bool { return stmt.Options[i].Key < stmt.Options[j].Key })
+	ann := p.ExtendedEvalContext().Annotations
+	return tree.AsStringWithFQNames(&stmt, ann), nil
+}
+
+// splitSpanAtRanges splits the span at the boundaries of the ranges it
+// overlaps, so that the progress of an export can be tracked per range.
+func splitSpanAtRanges(
+	ctx context.Context, p sql.PlanHookState, span roachpb.Span,
+) ([]roachpb.Span, error) {
+	it, err := p.ExecCfg().RangeDescIteratorFactory.NewIterator(ctx, span)
+	if err != nil {
+		return nil, err
+	}
+	var spans []roachpb.Span
+	for ; it.Valid(); it.Next() {
+		desc := it.CurRangeDescriptor()
+		if sp := span.Intersect(desc.RSpan().AsRawSpanWithNoLocals()); sp.Valid() {
+			spans = append(spans, sp)
+		}
+	}
+	if len(spans) == 0 {
+		spans = append(spans, span)
+	}
+	return spans, nil
+}
+
+func exportTypeCheck(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (matched bool, header colinfo.ResultColumns, _ error) {
+	exportStmt, ok := stmt.(*tree.Export)
+	if !ok || !exportStmt.RunsAsJob() {
+		return false, nil, nil
+	}
+	if err := exprutil.TypeCheck(
+		ctx, "EXPORT", p.SemaCtx(),
+		exprutil.Strings{exportStmt.File, exportStmt.IncrementalFrom},
+		exprutil.KVOptions{
+			KVOptions: exportStmt.Options, Validation: exportJobOptionExpectValues,
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	header = jobs.BulkJobExecutionResultHeader
+	if exportStmt.Options.HasKey(tree.ExportOptionDetached) {
+		header = jobs.DetachedJobExecutionResultHeader
+	}
+	return true, header, nil
+}
+
+// exportPlanHook implements sql.PlanHookFn for EXPORTs of a table which are
+// run as a job: those which are incremental, or which were requested to be
+// resumable or detached.
+func exportPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	exportStmt, ok := stmt.(*tree.Export)
+	if !ok || !exportStmt.RunsAsJob() {
+		return nil, nil, nil, false, nil
+	}
+
+	if err := featureflag.CheckEnabled(
+		ctx,
+		p.ExecCfg(),
+		sql.FeatureExportEnabled,
+		"EXPORT",
+	); err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fileSuffix := strings.ToLower(exportStmt.FileFormat)
+	if fileSuffix != "csv" && fileSuffix != "parquet" {
+		return nil, nil, nil, false, errors.Errorf("unsupported export format: %q", fileSuffix)
+	}
+
+	tn, err := exportTableName(exportStmt.Query)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	exprEval := p.ExprEvaluator("EXPORT")
+	destination, err := exprEval.String(ctx, exportStmt.File)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	var incrementalFrom string
+	if exportStmt.IncrementalFrom != nil {
+		incrementalFrom, err = exprEval.String(ctx, exportStmt.IncrementalFrom)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+	opts, err := exprEval.KVOptions(ctx, exportStmt.Options, exportJobOptionExpectValues)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	_, isDetached := opts[tree.ExportOptionDetached]
+
+	uris := []string{destination}
+	if incrementalFrom != "" {
+		uris = append(uris, incrementalFrom)
+	}
+	if err := cloudprivilege.CheckDestinationPrivileges(ctx, p, uris); err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, exportStmt.StatementTag())
+		defer span.Finish()
+
+		if !(p.ExtendedEvalContext().TxnIsSingleStmt || isDetached) {
+			return errors.Errorf("EXPORT cannot be used inside a multi-statement transaction without DETACHED option")
+		}
+
+		_, desc, err := resolver.ResolveExistingTableObject(ctx, p, tn, tree.ObjectLookupFlags{
+			Required:          true,
+			DesiredObjectKind: tree.TableObject,
+		})
+		if err != nil {
+			return err
+		}
+		if !desc.IsPhysicalTable() || desc.IsSequence() {
+			return pgerror.Newf(pgcode.WrongObjectType, "%q is not a table", tn.ObjectName)
+		}
+		if err := p.CheckPrivilege(ctx, desc, privilege.SELECT); err != nil {
+			return err
+		}
+
+		cols := exportColumns(desc)
+		colNullability := make([]bool, len(cols))
+		for i, col := range cols {
+			// Deleted rows in an incremental export only have their primary key
+			// columns set.
+			colNullability[i] = col.IsNullable() || incrementalFrom != ""
+		}
+		format, chunkRows, chunkSize, err := sql.ParseExportOptions(fileSuffix, opts, colNullability)
+		if err != nil {
+			return err
+		}
+
+		endTime := p.Txn().ReadTimestamp()
+		var startTime hlc.Timestamp
+		if incrementalFrom != "" {
+			prev, err := readExportManifest(
+				ctx, p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, incrementalFrom, p.User(),
+			)
+			if err != nil {
+				return err
+			}
+			if prev.TableID != desc.GetID() {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					"%s contains an export of table %s, not of %s",
+					redactURI(incrementalFrom), prev.Table, tn.ObjectName)
+			}
+			startTime, err = hlc.ParseHLC(prev.EndTime)
+			if err != nil {
+				return errors.Wrapf(err, "parsing end time of export in %s", redactURI(incrementalFrom))
+			}
+			if endTime.LessEq(startTime) {
+				return errors.Errorf("previous export in %s ends at %s, which is not before %s",
+					redactURI(incrementalFrom), startTime, endTime)
+			}
+		}
+
+		spans, err := splitSpanAtRanges(ctx, p, desc.PrimaryIndexSpan(p.ExecCfg().Codec))
+		if err != nil {
+			return err
+		}
+
+		jobDesc, err := exportJobDescription(p, exportStmt, tn, destination, incrementalFrom, opts)
+		if err != nil {
+			return err
+		}
+
+		// Protect the table's history from garbage collection for as long as the
+		// job runs: an incremental export needs every revision since its start
+		// time to find the rows that were deleted.
+		ptsID := uuid.MakeV4()
+		details := jobspb.ExportDetails{
+			TableID:                  desc.GetID(),
+			Spans:                    spans,
+			Destination:              destination,
+			Format:                   format,
+			ChunkRows:                int64(chunkRows),
+			ChunkSize:                chunkSize,
+			StartTime:                startTime,
+			EndTime:                  endTime,
+			IncrementalFrom:          incrementalFrom,
+			ProtectedTimestampRecord: &ptsID,
+		}
+		jr := jobs.Record{
+			Description:   jobDesc,
+			Username:      p.User(),
+			Details:       details,
+			Progress:      jobspb.ExportProgress{},
+			DescriptorIDs: descpb.IDs{desc.GetID()},
+		}
+		tsToProtect := endTime
+		if !startTime.IsEmpty() {
+			tsToProtect = startTime
+		}
+		protect := func(jobID jobspb.JobID) error {
+			ptp := p.ExecCfg().ProtectedTimestampProvider.WithTxn(p.InternalSQLTxn())
+			return ptp.Protect(ctx, jobsprotectedts.MakeRecord(
+				ptsID, int64(jobID), tsToProtect, nil, /* deprecatedSpans */
+				jobsprotectedts.Jobs, ptpb.MakeSchemaObjectsTarget(descpb.IDs{desc.GetID()}),
+			))
+		}
+
+		if isDetached {
+			jobID := p.ExecCfg().JobRegistry.MakeJobID()
+			if err := protect(jobID); err != nil {
+				return err
+			}
+			if _, err := p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(
+				ctx, jr, jobID, p.InternalSQLTxn(),
+			); err != nil {
+				return err
+			}
+			resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(jobID))}
+			return nil
+		}
+
+		plannerTxn := p.InternalSQLTxn()
+		var sj *jobs.StartableJob
+		if err := func() (err error) {
+			defer func() {
+				if err == nil || sj == nil {
+					return
+				}
+				if cleanupErr := sj.CleanupOnRollback(ctx); cleanupErr != nil {
+					log.Errorf(ctx, "failed to cleanup job: %v", cleanupErr)
+				}
+			}()
+			jobID := p.ExecCfg().JobRegistry.MakeJobID()
+			if err := protect(jobID); err != nil {
+				return err
+			}
+			if err := p.ExecCfg().JobRegistry.CreateStartableJobWithTxn(ctx, &sj, jobID, plannerTxn, jr); err != nil {
+				return err
+			}
+			// We commit the transaction here so that the job can be started. This
+			// is safe because we're in an implicit transaction.
+			return plannerTxn.KV().Commit(ctx)
+		}(); err != nil {
+			return err
+		}
+		p.InternalSQLTxn().Descriptors().ReleaseAll(ctx)
+		if err := sj.Start(ctx); err != nil {
+			return err
+		}
+		if err := sj.AwaitCompletion(ctx); err != nil {
+			return err
+		}
+		return sj.ReportExecutionResults(ctx, resultsCh)
+	}
+
+	if isDetached {
+		return fn, jobs.DetachedJobExecutionResultHeader, nil, false, nil
+	}
+	return fn, jobs.BulkJobExecutionResultHeader, nil, false, nil
+}
+
+// exportColumns returns the columns of the table that an export run as a job
+// writes out: the columns that TABLE <table> would return, followed by any
+// hidden primary key columns, without which the rows of an incremental export
+// could not be applied. Virtual columns are not stored, so they are skipped.
+func exportColumns(desc catalog.TableDescriptor) []catalog.Column {
+	var cols []catalog.Column
+	for _, col := range desc.VisibleColumns() {
+		if !col.IsVirtual() {
+			cols = append(cols, col)
+		}
+	}
+	primaryIndex := desc.GetPrimaryIndex()
+	for i := 0; i < primaryIndex.NumKeyColumns(); i++ {
+		col := catalog.FindColumnByID(desc, primaryIndex.GetKeyColumnID(i))
+		if col != nil && col.IsHidden() {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+func init() {
+	sql.AddPlanHook("export", exportPlanHook, exportTypeCheck)
+}
@@ -64,6 +64,27 @@ func fileName(spec execinfrapb.ExportSpec, part string) string {
 	return fileName
 }
 
+// parquetCompressionCodec returns the codec with which exported parquet files
+// are compressed.
+//
+// TODO: util/parquet supports more compression formats. The exporter can be
+// updated to supported these too.
+func parquetCompressionCodec(
+	compression roachpb.IOFileFormat_Compression,
+) (crlparquet.CompressionCodec, error) {
+	switch compression {
+	case roachpb.IOFileFormat_Snappy:
+		return crlparquet.CompressionSnappy, nil
+	case roachpb.IOFileFormat_Gzip:
+		return crlparquet.CompressionGZIP, nil
+	case roachpb.IOFileFormat_Auto, roachpb.IOFileFormat_None:
+		return crlparquet.CompressionNone, nil
+	default:
+		return crlparquet.CompressionNone, pgerror.Newf(pgcode.FeatureNotSupported,
+			"parquet writer does not support compression format %s", compression)
+	}
+}
+
 // ParquetColumn contains the relevant data to map a crdb table column to a parquet table column.
 type ParquetColumn struct {
 	name     string
@@ -650,19 +671,9 @@ func (sp *parquetWriterProcessor) Run(ctx context.Context, output execinfra.RowR
 			return err
 		}
 
-		// TODO: util/parquet supports more compression formats. The
-		// exporter can be updated to supported these too.
-		var compression crlparquet.CompressionCodec
-		switch sp.spec.Format.Compression {
-		case roachpb.IOFileFormat_Snappy:
-			compression = crlparquet.CompressionSnappy
-		case roachpb.IOFileFormat_Gzip:
-			compression = crlparquet.CompressionGZIP
-		case roachpb.IOFileFormat_Auto, roachpb.IOFileFormat_None:
-			compression = crlparquet.CompressionNone
-		default:
-			return pgerror.Newf(pgcode.FeatureNotSupported,
-				"parquet writer does not support compression format %s", sp.spec.Format.Compression)
+		compression, err := parquetCompressionCodec(sp.spec.Format.Compression)
+		if err != nil {
+			return err
 		}
 
 		chunk := 0
@@ -292,6 +292,12 @@ func (r *importResumer) Resume(ctx context.Context, execCtx interface{}) error {
 
 	procsPerNode := int(processorsPerNode.Get(&p.ExecCfg().Settings.SV))
 
+	// Parquet files are read by as many processors as they have row group
+	// shards, each of which is tracked as a separate input for progress.
+	if format.Format == roachpb.IOFileFormat_Parquet {
+		files = expandParquetRowGroupShards(files, format.Parquet)
+	}
+
 	res, err := ingestWithRetry(ctx, p, r.job, tables, typeDescs, files, format, details.Walltime,
 		r.testingKnobs, procsPerNode)
 	if err != nil {
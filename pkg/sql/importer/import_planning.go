@@ -87,7 +87,7 @@ const (
 
 	optMaxRowSize = "max_row_size"
 
-	// Turn on strict validation when importing avro records.
+	// Turn on strict validation when importing avro, parquet or ndjson records.
 	avroStrict = "strict_validation"
 	// Default input format is assumed to be OCF (object container file).
 	// This default can be changed by specified either of these options.
@@ -184,6 +184,11 @@ var mysqlOutAllowedOptions = makeStringSet(
 	mysqlOutfileEscape, csvNullIf, csvSkip, csvRowLimit,
 )
 
+var (
+	parquetAllowedOptions = makeStringSet(avroStrict, csvRowLimit)
+	ndjsonAllowedOptions  = makeStringSet(avroStrict, optMaxRowSize, csvRowLimit)
+)
+
 var (
 	mysqlDumpAllowedOptions = makeStringSet(importOptionSkipFKs, csvRowLimit)
 	pgCopyAllowedOptions    = makeStringSet(pgCopyDelimiter, pgCopyNull, optMaxRowSize)
@@ -201,8 +206,20 @@ var allowedIntoFormats = map[string]struct{}{
 	"AVRO":      {},
 	"DELIMITED": {},
 	"PGCOPY":    {},
+	"PARQUET":   {},
+	"NDJSON":    {},
 }
 
+// parquetRowGroupShards is the number of ways the row groups of each parquet
+// file are split, allowing different processors to read the same file.
+var parquetRowGroupShards = settings.RegisterIntSetting(
+	settings.ApplicationLevel,
+	"bulkio.import.parquet_row_group_shards",
+	"number of ways the row groups of each parquet file are split across import processors",
+	4,
+	settings.PositiveInt,
+)
+
 // featureImportEnabled is used to enable and disable the IMPORT feature.
 var featureImportEnabled = settings.RegisterBoolSetting(
 	settings.ApplicationLevel,
@@ -751,6 +768,49 @@ func importPlanHook(
 			if err != nil {
 				return err
 			}
+		case "PARQUET":
+			if err = validateFormatOptions(importStmt.FileFormat, opts, parquetAllowedOptions); err != nil {
+				return err
+			}
+			format.Format = roachpb.IOFileFormat_Parquet
+			_, format.Parquet.StrictMode = opts[avroStrict]
+			if override, ok := opts[csvRowLimit]; ok {
+				rowLimit, err := strconv.Atoi(override)
+				if err != nil {
+					return pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
+				}
+				if rowLimit <= 0 {
+					return pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
+				}
+				format.Parquet.RowLimit = int64(rowLimit)
+			}
+			format.Parquet.RowGroupShards = int32(parquetRowGroupShards.Get(&p.ExecCfg().Settings.SV))
+		case "NDJSON":
+			if err = validateFormatOptions(importStmt.FileFormat, opts, ndjsonAllowedOptions); err != nil {
+				return err
+			}
+			format.Format = roachpb.IOFileFormat_NDJSON
+			_, format.NDJSON.StrictMode = opts[avroStrict]
+			if override, ok := opts[csvRowLimit]; ok {
+				rowLimit, err := strconv.Atoi(override)
+				if err != nil {
+					return pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
+				}
+				if rowLimit <= 0 {
+					return pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
+				}
+				format.NDJSON.RowLimit = int64(rowLimit)
+			}
+			if override, ok := opts[optMaxRowSize]; ok {
+				sz, err := humanizeutil.ParseBytes(override)
+				if err != nil {
+					return err
+				}
+				if sz < 1 || sz > math.MaxInt32 {
+					return errors.Errorf("%s out of range: %d", override, sz)
+				}
+				format.NDJSON.MaxRecordSize = int32(sz)
+			}
 		default:
 			return unimplemented.Newf("import.format", "unsupported import format: %q", importStmt.FileFormat)
 		}
@@ -0,0 +1,280 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+// defaultNDJSONMaxRecordSize is the size of the largest record we accept
+// unless the max_row_size option is specified.
+const defaultNDJSONMaxRecordSize = 4 << 20
+
+// jsonToDatum converts a JSON value to a datum of type targetT.
+//
+// Scalars are parsed from their textual representation, so that e.g. a JSON
+// string may be imported into a TIMESTAMP column. JSON arrays may be imported
+// into array columns. Any JSON value, including nested objects and arrays, may
+// be imported into a JSONB column as is, or into a string column as text.
+func jsonToDatum(
+	ctx context.Context, j json.JSON, targetT *types.T, evalCtx *eval.Context,
+) (tree.Datum, error) {
+	if targetT.Family() == types.JsonFamily {
+		return tree.NewDJSON(j), nil
+	}
+
+	switch j.Type() {
+	case json.NullJSONType:
+		return tree.DNull, nil
+	case json.StringJSONType:
+		s, err := j.AsText()
+		if err != nil {
+			return nil, err
+		}
+		return rowenc.ParseDatumStringAs(ctx, targetT, *s, evalCtx)
+	case json.NumberJSONType, json.TrueJSONType, json.FalseJSONType:
+		return rowenc.ParseDatumStringAs(ctx, targetT, j.String(), evalCtx)
+	case json.ArrayJSONType:
+		if targetT.Family() == types.ArrayFamily {
+			arr := tree.NewDArray(targetT.ArrayContents())
+			for i, n := 0, j.Len(); i < n; i++ {
+				elem, err := j.FetchValIdx(i)
+				if err != nil {
+					return nil, err
+				}
+				d, err := jsonToDatum(ctx, elem, targetT.ArrayContents(), evalCtx)
+				if err != nil {
+					return nil, err
+				}
+				if err := arr.Append(d); err != nil {
+					return nil, err
+				}
+			}
+			return arr, nil
+		}
+	}
+
+	if targetT.Family() == types.StringFamily {
+		return tree.NewDString(j.String()), nil
+	}
+	return nil, errors.Errorf("cannot convert JSON value %s to %s", j, targetT.SQLString())
+}
+
+// ndjsonConsumer implements importRowConsumer interface.
+type ndjsonConsumer struct {
+	importCtx      *parallelImportContext
+	fieldNameToIdx map[string]int
+	strict         bool
+}
+
+var _ importRowConsumer = &ndjsonConsumer{}
+
+// FillDatums implements importRowConsumer interface.
+func (n *ndjsonConsumer) FillDatums(
+	ctx context.Context, native interface{}, rowIndex int64, conv *row.DatumRowConverter,
+) error {
+	line, ok := native.([]byte)
+	if !ok {
+		return errors.AssertionFailedf("unexpected native type; expected []byte found %T instead", native)
+	}
+	record, err := json.ParseJSON(string(line))
+	if err != nil {
+		return newImportRowError(err, string(line), rowIndex)
+	}
+	iter, err := record.ObjectIter()
+	if err != nil {
+		return err
+	}
+	if iter == nil {
+		return newImportRowError(
+			errors.Errorf("expected a JSON object, found %s", record.Type()), string(line), rowIndex)
+	}
+
+	for iter.Next() {
+		field := lexbase.NormalizeName(iter.Key())
+		idx, ok := n.fieldNameToIdx[field]
+		if !ok {
+			if n.strict {
+				return fmt.Errorf("could not find column for record field %s", field)
+			}
+			continue
+		}
+		datum, err := jsonToDatum(ctx, iter.Value(), conv.VisibleColTypes[idx], conv.EvalCtx)
+		if err != nil {
+			return newImportRowError(
+				errors.Wrapf(err, "field %s", field), string(line), rowIndex)
+		}
+		conv.Datums[idx] = datum
+	}
+
+	// Set any nil datums to DNull (in case the record didn't have the value
+	// set at all).
+	for i := range conv.Datums {
+		if conv.TargetColOrds.Contains(i) && conv.Datums[i] == nil {
+			if n.strict {
+				return fmt.Errorf("field %s was not set in the ndjson import", conv.VisibleCols[i].GetName())
+			}
+			conv.Datums[i] = tree.DNull
+		}
+	}
+	return nil
+}
+
+// ndjsonStream is a scanner over a file containing one JSON object per line.
+// Blank lines are ignored.
+type ndjsonStream struct {
+	input         *fileReader
+	scanner       *bufio.Scanner
+	maxRecordSize int
+	err           error
+}
+
+var _ importRowProducer = &ndjsonStream{}
+
+// Progress implements importRowProducer interface.
+func (s *ndjsonStream) Progress() float32 {
+	return s.input.ReadFraction()
+}
+
+// Scan implements importRowProducer interface.
+func (s *ndjsonStream) Scan() bool {
+	for s.scanner.Scan() {
+		if len(bytes.TrimSpace(s.scanner.Bytes())) > 0 {
+			return true
+		}
+	}
+	if err := s.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			err = errors.Newf("record exceeds maximum size of %s; use the %s option to increase it",
+				humanizeutil.IBytes(int64(s.maxRecordSize)), optMaxRowSize)
+		}
+		s.err = err
+	}
+	return false
+}
+
+// Err implements importRowProducer interface.
+func (s *ndjsonStream) Err() error {
+	return s.err
+}
+
+// Skip implements importRowProducer interface.
+func (s *ndjsonStream) Skip() error {
+	return nil
+}
+
+// Row implements importRowProducer interface.
+func (s *ndjsonStream) Row() (interface{}, error) {
+	// The scanner reuses its buffer, so the line must be copied before it is
+	// handed off to the consumers.
+	return append([]byte(nil), s.scanner.Bytes()...), nil
+}
+
+type ndjsonInputReader struct {
+	importContext *parallelImportContext
+	opts          roachpb.NDJSONOptions
+}
+
+var _ inputConverter = &ndjsonInputReader{}
+
+func newNDJSONInputReader(
+	semaCtx *tree.SemaContext,
+	kvCh chan row.KVBatch,
+	tableDesc catalog.TableDescriptor,
+	opts roachpb.NDJSONOptions,
+	walltime int64,
+	parallelism int,
+	evalCtx *eval.Context,
+	db *kv.DB,
+) *ndjsonInputReader {
+	return &ndjsonInputReader{
+		importContext: &parallelImportContext{
+			semaCtx:    semaCtx,
+			walltime:   walltime,
+			numWorkers: parallelism,
+			evalCtx:    evalCtx,
+			tableDesc:  tableDesc,
+			kvCh:       kvCh,
+			db:         db,
+		},
+		opts: opts,
+	}
+}
+
+func (n *ndjsonInputReader) start(group ctxgroup.Group) {}
+
+func (n *ndjsonInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	user username.SQLUsername,
+) error {
+	return readInputFiles(ctx, dataFiles, resumePos, format, n.readFile, makeExternalStorage, user)
+}
+
+func (n *ndjsonInputReader) readFile(
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+) error {
+	fieldIdxByName := make(map[string]int)
+	for idx, col := range n.importContext.tableDesc.VisibleColumns() {
+		fieldIdxByName[col.GetName()] = idx
+	}
+	consumer := &ndjsonConsumer{
+		importCtx:      n.importContext,
+		fieldNameToIdx: fieldIdxByName,
+		strict:         n.opts.StrictMode,
+	}
+
+	maxRecordSize := defaultNDJSONMaxRecordSize
+	if n.opts.MaxRecordSize > 0 {
+		maxRecordSize = int(n.opts.MaxRecordSize)
+	}
+	initialBufSize := 64 << 10
+	if initialBufSize > maxRecordSize {
+		initialBufSize = maxRecordSize
+	}
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 0, initialBufSize), maxRecordSize)
+	producer := &ndjsonStream{
+		input:         input,
+		scanner:       scanner,
+		maxRecordSize: maxRecordSize,
+	}
+
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rejected: rejected,
+		rowLimit: n.opts.RowLimit,
+	}
+	return runParallelImport(ctx, n.importContext, fileCtx, producer, consumer)
+}
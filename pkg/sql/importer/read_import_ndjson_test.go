@@ -0,0 +1,81 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package importer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportNDJSON(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+
+	const data = `{"id": 1, "name": "a", "ts": "2023-01-01 12:00:00", "tags": ["x", "y"], "doc": {"k": [1, 2]}, "other": true}
+{"id": 2, "name": null, "doc": null}
+
+{"id": 3, "name": "c", "tags": [], "doc": {"k": {}}}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.ndjson"), []byte(data), 0666))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.ndjson"), []byte(`[1, 2]`), 0666))
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE TABLE t (id INT PRIMARY KEY, name STRING, ts TIMESTAMP, tags STRING[], doc JSONB)`)
+	sqlDB.Exec(t, `IMPORT INTO t NDJSON DATA ('nodelocal://1/data.ndjson')`)
+	sqlDB.CheckQueryResults(t, `SELECT id, name, ts, tags, doc FROM t ORDER BY id`, [][]string{
+		{"1", "a", "2023-01-01 12:00:00 +0000 +0000", "{x,y}", `{"k": [1, 2]}`},
+		{"2", "NULL", "NULL", "NULL", "NULL"},
+		{"3", "c", "NULL", "{}", `{"k": {}}`},
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE strict (id INT PRIMARY KEY, name STRING, ts TIMESTAMP, tags STRING[], doc JSONB)`)
+		sqlDB.ExpectErr(t, "could not find column for record field other",
+			`IMPORT INTO strict NDJSON DATA ('nodelocal://1/data.ndjson') WITH strict_validation`)
+	})
+
+	t.Run("row-limit", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE limited (id INT PRIMARY KEY, name STRING)`)
+		sqlDB.Exec(t, `IMPORT INTO limited NDJSON DATA ('nodelocal://1/data.ndjson') WITH row_limit = '2'`)
+		sqlDB.CheckQueryResults(t, `SELECT id, name FROM limited ORDER BY id`, [][]string{
+			{"1", "a"}, {"2", "NULL"},
+		})
+	})
+
+	t.Run("not-an-object", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE notobj (id INT PRIMARY KEY)`)
+		sqlDB.ExpectErr(t, "expected a JSON object",
+			`IMPORT INTO notobj NDJSON DATA ('nodelocal://1/bad.ndjson')`)
+	})
+
+	t.Run("max-row-size", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE small (id INT PRIMARY KEY, name STRING)`)
+		sqlDB.ExpectErr(t, "record exceeds maximum size",
+			`IMPORT INTO small NDJSON DATA ('nodelocal://1/data.ndjson') WITH max_row_size = '16B'`)
+	})
+}
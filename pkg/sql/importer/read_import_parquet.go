@@ -0,0 +1,1001 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package importer
+
+import (
+	"context"
+	"io"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/apache/arrow/go/v11/parquet"
+	"github.com/apache/arrow/go/v11/parquet/file"
+	"github.com/apache/arrow/go/v11/parquet/schema"
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+// parquetReadBatchSize is the number of values read from a column chunk at a
+// time.
+const parquetReadBatchSize = 1024
+
+// expandParquetRowGroupShards repeats each of the files once per row group
+// shard, so that the row groups of each file can be read by different import
+// processors. The i'th element of the result reads the row groups of its file
+// whose index is congruent to i modulo the number of shards.
+func expandParquetRowGroupShards(files []string, opts roachpb.ParquetOptions) []string {
+	shards := int(opts.RowGroupShards)
+	if shards <= 1 {
+		return files
+	}
+	res := make([]string, 0, len(files)*shards)
+	for _, f := range files {
+		for i := 0; i < shards; i++ {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// parquetFileReader implements the io.ReaderAt and io.Seeker interfaces
+// required by the parquet reader on top of ExternalStorage. Each ReadAt issues
+// a ranged read; the parquet reader reads the footer and then each column
+// chunk with a single call, so the number of requests is proportional to the
+// number of column chunks read. Note: contrary to io.ReaderAt, ReadAt does
+// *not* support parallel calls.
+type parquetFileReader struct {
+	ctx  context.Context
+	es   cloud.ExternalStorage
+	size int64
+	pos  int64
+}
+
+var _ parquet.ReaderAtSeeker = &parquetFileReader{}
+
+// ReadAt implements io.ReaderAt.
+func (r *parquetFileReader) ReadAt(p []byte, offset int64) (int, error) {
+	if offset >= r.size {
+		return 0, io.EOF
+	}
+	body, _, err := r.es.ReadFile(r.ctx, "", cloud.ReadOptions{
+		Offset:     offset,
+		LengthHint: int64(len(p)),
+		NoFileSize: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close(r.ctx)
+	n, err := io.ReadFull(ioctx.ReaderCtxAdapter(r.ctx, body), p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Seek implements io.Seeker.
+func (r *parquetFileReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, errors.Newf("invalid whence: %d", whence)
+	}
+	if offset < 0 {
+		return 0, errors.Newf("invalid offset: %d", offset)
+	}
+	r.pos = offset
+	return r.pos, nil
+}
+
+// parquetList is the value of a parquet column containing a list of
+// primitive values.
+type parquetList []tree.Datum
+
+// parquetRow is a row produced by parquetRowStream. Each value is either a
+// tree.Datum, for primitive columns, a parquetList, for lists of primitive
+// values, or a json.JSON for structs and maps.
+type parquetRow []interface{}
+
+// parquetValueToDatum converts a value produced by parquetRowStream to a datum
+// of type targetT.
+func parquetValueToDatum(
+	ctx context.Context, v interface{}, targetT *types.T, evalCtx *eval.Context,
+) (tree.Datum, error) {
+	switch v := v.(type) {
+	case tree.Datum:
+		if v == tree.DNull {
+			return v, nil
+		}
+		switch targetT.Family() {
+		case types.JsonFamily:
+			if v.ResolvedType().Family() != types.JsonFamily {
+				j, err := parquetDatumToJSON(v)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDJSON(j), nil
+			}
+		case types.StringFamily:
+			// Parquet strings are often written without the UTF8 annotation, in
+			// which case they are read as bytes.
+			if b, ok := v.(*tree.DBytes); ok {
+				return tree.NewDString(string(*b)), nil
+			}
+		}
+		return eval.PerformCast(ctx, evalCtx, v, targetT)
+	case parquetList:
+		if targetT.Family() == types.ArrayFamily {
+			arr := tree.NewDArray(targetT.ArrayContents())
+			for _, elem := range v {
+				d, err := parquetValueToDatum(ctx, elem, targetT.ArrayContents(), evalCtx)
+				if err != nil {
+					return nil, err
+				}
+				if err := arr.Append(d); err != nil {
+					return nil, err
+				}
+			}
+			return arr, nil
+		}
+		b := json.NewArrayBuilder(len(v))
+		for _, elem := range v {
+			j, err := parquetDatumToJSON(elem)
+			if err != nil {
+				return nil, err
+			}
+			b.Add(j)
+		}
+		return jsonToDatum(ctx, b.Build(), targetT, evalCtx)
+	case json.JSON:
+		return jsonToDatum(ctx, v, targetT, evalCtx)
+	default:
+		return nil, errors.AssertionFailedf("unexpected parquet value of type %T", v)
+	}
+}
+
+func parquetDatumToJSON(d tree.Datum) (json.JSON, error) {
+	if d == tree.DNull {
+		return json.NullJSONValue, nil
+	}
+	return tree.AsJSON(d, sessiondatapb.DataConversionConfig{}, time.UTC)
+}
+
+// decodeParquetInt decodes an integer stored in a parquet column of the given
+// logical type.
+func decodeParquetInt(lt schema.LogicalType, v int64) (tree.Datum, error) {
+	switch t := lt.(type) {
+	case schema.DateLogicalType:
+		d, err := pgdate.MakeDateFromUnixEpoch(v)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDDate(d), nil
+	case *schema.TimeLogicalType:
+		switch t.TimeUnit() {
+		case schema.TimeUnitMillis:
+			v *= 1000
+		case schema.TimeUnitNanos:
+			v /= 1000
+		}
+		return tree.MakeDTime(timeofday.FromInt(v)), nil
+	case *schema.TimestampLogicalType:
+		var ts time.Time
+		switch t.TimeUnit() {
+		case schema.TimeUnitMillis:
+			ts = time.UnixMilli(v)
+		case schema.TimeUnitMicros:
+			ts = time.UnixMicro(v)
+		default:
+			ts = time.Unix(0, v)
+		}
+		if t.IsAdjustedToUTC() {
+			return tree.MakeDTimestampTZ(ts.UTC(), time.Microsecond)
+		}
+		return tree.MakeDTimestamp(ts.UTC(), time.Microsecond)
+	case *schema.DecimalLogicalType:
+		return &tree.DDecimal{Decimal: *apd.New(v, -t.Scale())}, nil
+	default:
+		return tree.NewDInt(tree.DInt(v)), nil
+	}
+}
+
+// decodeParquetBytes decodes a byte array stored in a parquet column of the
+// given logical type.
+func decodeParquetBytes(lt schema.LogicalType, b []byte) (tree.Datum, error) {
+	switch t := lt.(type) {
+	case schema.StringLogicalType, schema.EnumLogicalType:
+		return tree.NewDString(string(b)), nil
+	case schema.JSONLogicalType:
+		j, err := json.ParseJSON(string(b))
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDJSON(j), nil
+	case schema.UUIDLogicalType:
+		u, err := uuid.FromBytes(b)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDUuid(tree.DUuid{UUID: u}), nil
+	case *schema.DecimalLogicalType:
+		// Decimals are stored as big-endian two's complement unscaled values.
+		unscaled := new(big.Int).SetBytes(b)
+		if len(b) > 0 && b[0]&0x80 != 0 {
+			unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(len(b))*8))
+		}
+		var coeff apd.BigInt
+		coeff.SetMathBigInt(unscaled)
+		return &tree.DDecimal{Decimal: *apd.NewWithBigInt(&coeff, -t.Scale())}, nil
+	default:
+		return tree.NewDBytes(tree.DBytes(b)), nil
+	}
+}
+
+type parquetBatchReader[T any] interface {
+	ReadBatch(batchSize int64, values []T, defLvls []int16, repLvls []int16) (total int64, valuesRead int, err error)
+}
+
+// parquetLeaf reads the values of a leaf (physical) column of a row group.
+type parquetLeaf struct {
+	desc *schema.Column
+	// readBatch reads the next batch of levels into defs and reps, and decodes
+	// the values which are defined into vals. It returns the number of levels
+	// read, which is 0 once the column chunk is exhausted.
+	readBatch  func() (int, error)
+	defs, reps []int16
+	vals       []tree.Datum
+	n, pos     int
+	valPos     int
+}
+
+// parquetEntry is a value of a leaf column along with its definition level.
+type parquetEntry struct {
+	def   int16
+	datum tree.Datum // nil unless def is the max definition level of the column.
+}
+
+func newParquetLeaf(cr file.ColumnChunkReader) (*parquetLeaf, error) {
+	l := &parquetLeaf{
+		desc: cr.Descriptor(),
+		defs: make([]int16, parquetReadBatchSize),
+		reps: make([]int16, parquetReadBatchSize),
+	}
+	lt := l.desc.LogicalType()
+	var unsigned bool
+	if it, ok := lt.(*schema.IntLogicalType); ok {
+		unsigned = !it.IsSigned()
+	}
+
+	var err error
+	switch cr.Type() {
+	case parquet.Types.Boolean:
+		l.readBatch, err = makeParquetBatchReader(l, cr, func(v bool) (tree.Datum, error) {
+			return tree.MakeDBool(tree.DBool(v)), nil
+		})
+	case parquet.Types.Int32:
+		l.readBatch, err = makeParquetBatchReader(l, cr, func(v int32) (tree.Datum, error) {
+			if unsigned {
+				return decodeParquetInt(lt, int64(uint32(v)))
+			}
+			return decodeParquetInt(lt, int64(v))
+		})
+	case parquet.Types.Int64:
+		l.readBatch, err = makeParquetBatchReader(l, cr, func(v int64) (tree.Datum, error) {
+			if unsigned && v < 0 {
+				return tree.ParseDDecimal(strconv.FormatUint(uint64(v), 10))
+			}
+			return decodeParquetInt(lt, v)
+		})
+	case parquet.Types.Int96:
+		// INT96 is the legacy encoding of timestamps.
+		l.readBatch, err = makeParquetBatchReader(l, cr, func(v parquet.Int96) (tree.Datum, error) {
+			return tree.MakeDTimestamp(v.ToTime(), time.Microsecond)
+		})
+	case parquet.Types.Float:
+		l.readBatch, err = makeParquetBatchReader(l, cr, func(v float32) (tree.Datum, error) {
+			return tree.NewDFloat(tree.DFloat(v)), nil
+		})
+	case parquet.Types.Double:
+		l.readBatch, err = makeParquetBatchReader(l, cr, func(v float64) (tree.Datum, error) {
+			return tree.NewDFloat(tree.DFloat(v)), nil
+		})
+	case parquet.Types.ByteArray:
+		l.readBatch, err = makeParquetBatchReader(l, cr, func(v parquet.ByteArray) (tree.Datum, error) {
+			return decodeParquetBytes(lt, v)
+		})
+	case parquet.Types.FixedLenByteArray:
+		l.readBatch, err = makeParquetBatchReader(l, cr, func(v parquet.FixedLenByteArray) (tree.Datum, error) {
+			return decodeParquetBytes(lt, v)
+		})
+	default:
+		return nil, errors.Errorf("unsupported parquet type %s of column %s", cr.Type(), l.desc.Path())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func makeParquetBatchReader[T any](
+	l *parquetLeaf, cr file.ColumnChunkReader, decode func(T) (tree.Datum, error),
+) (func() (int, error), error) {
+	br, ok := cr.(parquetBatchReader[T])
+	if !ok {
+		return nil, errors.AssertionFailedf("expected batch reader for type %T, found %T instead", *new(T), cr)
+	}
+	buf := make([]T, parquetReadBatchSize)
+	return func() (int, error) {
+		total, valuesRead, err := br.ReadBatch(parquetReadBatchSize, buf, l.defs, l.reps)
+		if err != nil {
+			return 0, err
+		}
+		// NB: the decoded datums must not reference buf, which is reused.
+		l.vals = l.vals[:0]
+		for _, v := range buf[:valuesRead] {
+			d, err := decode(v)
+			if err != nil {
+				return 0, errors.Wrapf(err, "column %s", l.desc.Path())
+			}
+			l.vals = append(l.vals, d)
+		}
+		return int(total), nil
+	}, nil
+}
+
+func (l *parquetLeaf) fill() error {
+	if l.pos < l.n {
+		return nil
+	}
+	n, err := l.readBatch()
+	if err != nil {
+		return err
+	}
+	l.n, l.pos, l.valPos = n, 0, 0
+	return nil
+}
+
+// next returns the next entry of the column.
+func (l *parquetLeaf) next() (parquetEntry, error) {
+	if err := l.fill(); err != nil {
+		return parquetEntry{}, err
+	}
+	if l.pos == l.n {
+		return parquetEntry{}, errors.AssertionFailedf("unexpected end of column %s", l.desc.Path())
+	}
+	var e parquetEntry
+	if l.desc.MaxDefinitionLevel() > 0 {
+		e.def = l.defs[l.pos]
+	}
+	l.pos++
+	if e.def == l.desc.MaxDefinitionLevel() {
+		e.datum = l.vals[l.valPos]
+		l.valPos++
+	}
+	return e, nil
+}
+
+// nextRow returns the entries of the column belonging to the next row, which
+// may be more than one for repeated columns.
+func (l *parquetLeaf) nextRow(buf []parquetEntry) ([]parquetEntry, error) {
+	buf = buf[:0]
+	for {
+		e, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, e)
+		if l.desc.MaxRepetitionLevel() == 0 {
+			return buf, nil
+		}
+		// A repetition level of 0 marks the start of the next row.
+		if err := l.fill(); err != nil {
+			return nil, err
+		}
+		if l.pos == l.n || l.reps[l.pos] == 0 {
+			return buf, nil
+		}
+	}
+}
+
+type parquetColumnKind int
+
+const (
+	parquetPrimitiveColumn parquetColumnKind = iota
+	parquetListColumn
+	parquetMapColumn
+	parquetStructColumn
+)
+
+// parquetLeafPath describes the nodes from the root of a parquet column down
+// to one of its leaves.
+type parquetLeafPath struct {
+	// nodes[0] is the root of the column, and the last node is the leaf.
+	nodes []schema.Node
+	// defLevels[i] is the definition level at which nodes[i] is defined.
+	defLevels []int16
+}
+
+func makeParquetLeafPath(leaf schema.Node) parquetLeafPath {
+	var p parquetLeafPath
+	// The root of the schema is the only node without a parent.
+	for n := leaf; n.Parent() != nil; n = n.Parent() {
+		p.nodes = append([]schema.Node{n}, p.nodes...)
+	}
+	var def int16
+	for _, n := range p.nodes {
+		if n.RepetitionType() != parquet.Repetitions.Required {
+			def++
+		}
+		p.defLevels = append(p.defLevels, def)
+	}
+	return p
+}
+
+// repeatedDefLevel returns the definition level at which the repeated node of
+// the path is defined, i.e. the level of entries which are list elements.
+func (p parquetLeafPath) repeatedDefLevel() int16 {
+	for i, n := range p.nodes {
+		if n.RepetitionType() == parquet.Repetitions.Repeated {
+			return p.defLevels[i]
+		}
+	}
+	return 0
+}
+
+// parquetColumn reads a top level column of a parquet file, which may be made
+// up of several leaf columns if it is nested.
+type parquetColumn struct {
+	root   schema.Node
+	kind   parquetColumnKind
+	leaves []int // Indexes of the leaf columns in the file schema.
+	paths  []parquetLeafPath
+
+	// readers and bufs are indexed like leaves, and are reset for each row
+	// group.
+	readers []*parquetLeaf
+	bufs    [][]parquetEntry
+}
+
+func newParquetColumn(sc *schema.Schema, root schema.Node, leaves []int) (*parquetColumn, error) {
+	c := &parquetColumn{
+		root:   root,
+		leaves: leaves,
+		paths:  make([]parquetLeafPath, len(leaves)),
+		bufs:   make([][]parquetEntry, len(leaves)),
+	}
+	maxRep := int16(0)
+	for i, leaf := range leaves {
+		c.paths[i] = makeParquetLeafPath(sc.Column(leaf).SchemaNode())
+		if rep := sc.Column(leaf).MaxRepetitionLevel(); rep > maxRep {
+			maxRep = rep
+		}
+	}
+
+	unsupported := func() error {
+		return errors.Newf("unsupported nested parquet column %s", root.Name())
+	}
+	switch {
+	case root.Type() == schema.Primitive:
+		c.kind = parquetPrimitiveColumn
+		if root.RepetitionType() == parquet.Repetitions.Repeated {
+			c.kind = parquetListColumn
+		}
+	case isParquetList(root):
+		if len(leaves) != 1 || maxRep != 1 {
+			return nil, unsupported()
+		}
+		c.kind = parquetListColumn
+	case isParquetMap(root):
+		if len(leaves) != 2 || maxRep != 1 {
+			return nil, unsupported()
+		}
+		c.kind = parquetMapColumn
+	default:
+		if maxRep != 0 {
+			return nil, unsupported()
+		}
+		c.kind = parquetStructColumn
+	}
+	return c, nil
+}
+
+func isParquetList(n schema.Node) bool {
+	_, ok := n.LogicalType().(schema.ListLogicalType)
+	return ok || n.ConvertedType() == schema.ConvertedTypes.List
+}
+
+func isParquetMap(n schema.Node) bool {
+	_, ok := n.LogicalType().(schema.MapLogicalType)
+	return ok || n.ConvertedType() == schema.ConvertedTypes.Map ||
+		n.ConvertedType() == schema.ConvertedTypes.MapKeyValue
+}
+
+func (c *parquetColumn) openRowGroup(rgr *file.RowGroupReader) error {
+	c.readers = c.readers[:0]
+	for _, leaf := range c.leaves {
+		cr, err := rgr.Column(leaf)
+		if err != nil {
+			return err
+		}
+		l, err := newParquetLeaf(cr)
+		if err != nil {
+			return err
+		}
+		c.readers = append(c.readers, l)
+	}
+	return nil
+}
+
+// nullOrEmpty returns whether the entries of a row of a repeated leaf denote
+// a null or an empty list.
+func (c *parquetColumn) nullOrEmpty(
+	entries []parquetEntry, path parquetLeafPath,
+) (null bool, empty bool) {
+	if len(entries) != 1 || entries[0].def >= path.repeatedDefLevel() {
+		return false, false
+	}
+	if c.root.RepetitionType() == parquet.Repetitions.Optional && entries[0].def == 0 {
+		return true, false
+	}
+	return false, true
+}
+
+// read returns the value of the column in the next row.
+func (c *parquetColumn) read() (interface{}, error) {
+	var err error
+	for i, l := range c.readers {
+		if c.bufs[i], err = l.nextRow(c.bufs[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	switch c.kind {
+	case parquetPrimitiveColumn:
+		if d := c.bufs[0][0].datum; d != nil {
+			return d, nil
+		}
+		return tree.DNull, nil
+
+	case parquetListColumn:
+		entries := c.bufs[0]
+		if null, empty := c.nullOrEmpty(entries, c.paths[0]); null {
+			return tree.DNull, nil
+		} else if empty {
+			return parquetList{}, nil
+		}
+		list := make(parquetList, len(entries))
+		for i, e := range entries {
+			list[i] = e.datum
+			if e.datum == nil {
+				list[i] = tree.DNull
+			}
+		}
+		return list, nil
+
+	case parquetMapColumn:
+		keys, vals := c.bufs[0], c.bufs[1]
+		if null, empty := c.nullOrEmpty(keys, c.paths[0]); null {
+			return tree.DNull, nil
+		} else if empty {
+			return json.NewObjectBuilder(0).Build(), nil
+		}
+		if len(keys) != len(vals) {
+			return nil, errors.AssertionFailedf(
+				"mismatched number of keys and values in parquet map column %s", c.root.Name())
+		}
+		b := json.NewObjectBuilder(len(keys))
+		for i := range keys {
+			if keys[i].datum == nil {
+				return nil, errors.Newf("null key in parquet map column %s", c.root.Name())
+			}
+			k := tree.AsStringWithFlags(keys[i].datum, tree.FmtBareStrings)
+			v, err := parquetDatumToJSON(orDNull(vals[i].datum))
+			if err != nil {
+				return nil, err
+			}
+			b.Add(k, v)
+		}
+		return b.Build(), nil
+
+	case parquetStructColumn:
+		if c.root.RepetitionType() == parquet.Repetitions.Optional {
+			allNull := true
+			for _, entries := range c.bufs {
+				allNull = allNull && entries[0].def == 0
+			}
+			if allNull {
+				return tree.DNull, nil
+			}
+		}
+		obj := make(parquetObject)
+		for i, entries := range c.bufs {
+			if err := obj.add(c.paths[i], entries[0]); err != nil {
+				return nil, err
+			}
+		}
+		return obj.build()
+
+	default:
+		return nil, errors.AssertionFailedf("unknown parquet column kind %d", c.kind)
+	}
+}
+
+func orDNull(d tree.Datum) tree.Datum {
+	if d == nil {
+		return tree.DNull
+	}
+	return d
+}
+
+// parquetObject is used to assemble a parquet struct into a JSON object. Its
+// values are either nested parquetObjects or json.JSON.
+type parquetObject map[string]interface{}
+
+// add sets the value of the leaf at the end of path, relative to the root of
+// the struct, or null if the leaf or one of its ancestors is null.
+func (o parquetObject) add(path parquetLeafPath, e parquetEntry) error {
+	obj := o
+	for i := 1; i < len(path.nodes); i++ {
+		name := path.nodes[i].Name()
+		if e.def < path.defLevels[i] {
+			if _, ok := obj[name]; !ok {
+				obj[name] = json.NullJSONValue
+			}
+			return nil
+		}
+		if i == len(path.nodes)-1 {
+			j, err := parquetDatumToJSON(e.datum)
+			if err != nil {
+				return err
+			}
+			obj[name] = j
+			return nil
+		}
+		child, ok := obj[name].(parquetObject)
+		if !ok {
+			child = make(parquetObject)
+			obj[name] = child
+		}
+		obj = child
+	}
+	return nil
+}
+
+func (o parquetObject) build() (json.JSON, error) {
+	b := json.NewObjectBuilder(len(o))
+	for k, v := range o {
+		switch v := v.(type) {
+		case parquetObject:
+			j, err := v.build()
+			if err != nil {
+				return nil, err
+			}
+			b.Add(k, j)
+		case json.JSON:
+			b.Add(k, v)
+		}
+	}
+	return b.Build(), nil
+}
+
+// parquetRowStream produces the rows of the row groups of a parquet file
+// belonging to one row group shard, projected onto the columns which are
+// imported.
+type parquetRowStream struct {
+	reader    *file.Reader
+	columns   []*parquetColumn
+	rowGroups []parquetRowGroup
+
+	totalRows   int64
+	rowsRead    int64
+	rowsInGroup int64
+	row         parquetRow
+	err         error
+}
+
+var _ importRowProducer = &parquetRowStream{}
+
+// parquetRowGroup is a row group read by parquetRowStream, of which only the
+// first numRows rows are read.
+type parquetRowGroup struct {
+	idx     int
+	numRows int64
+}
+
+// Progress implements importRowProducer interface.
+func (s *parquetRowStream) Progress() float32 {
+	if s.totalRows == 0 {
+		return 1
+	}
+	return float32(s.rowsRead) / float32(s.totalRows)
+}
+
+// Scan implements importRowProducer interface.
+func (s *parquetRowStream) Scan() bool {
+	for s.rowsInGroup == 0 {
+		if len(s.rowGroups) == 0 {
+			return false
+		}
+		rg := s.rowGroups[0]
+		s.rowGroups = s.rowGroups[1:]
+		rgr := s.reader.RowGroup(rg.idx)
+		for _, c := range s.columns {
+			if s.err = c.openRowGroup(rgr); s.err != nil {
+				return false
+			}
+		}
+		s.rowsInGroup = rg.numRows
+	}
+
+	row := make(parquetRow, len(s.columns))
+	for i, c := range s.columns {
+		if row[i], s.err = c.read(); s.err != nil {
+			return false
+		}
+	}
+	s.row = row
+	s.rowsInGroup--
+	s.rowsRead++
+	return true
+}
+
+// Err implements importRowProducer interface.
+func (s *parquetRowStream) Err() error {
+	return s.err
+}
+
+// Skip implements importRowProducer interface.
+func (s *parquetRowStream) Skip() error {
+	s.row = nil
+	return nil
+}
+
+// Row implements importRowProducer interface.
+func (s *parquetRowStream) Row() (interface{}, error) {
+	res := s.row
+	s.row = nil
+	return res, nil
+}
+
+// parquetConsumer implements importRowConsumer interface.
+type parquetConsumer struct {
+	importCtx *parallelImportContext
+	// colIdx maps the columns produced by parquetRowStream to the visible
+	// columns of the table.
+	colIdx []int
+	strict bool
+}
+
+var _ importRowConsumer = &parquetConsumer{}
+
+// FillDatums implements importRowConsumer interface.
+func (p *parquetConsumer) FillDatums(
+	ctx context.Context, native interface{}, rowIndex int64, conv *row.DatumRowConverter,
+) error {
+	r, ok := native.(parquetRow)
+	if !ok {
+		return errors.AssertionFailedf("unexpected native type; expected parquetRow found %T instead", native)
+	}
+	for i, v := range r {
+		idx := p.colIdx[i]
+		d, err := parquetValueToDatum(ctx, v, conv.VisibleColTypes[idx], conv.EvalCtx)
+		if err != nil {
+			return errors.Wrapf(err, "row %d: column %s", rowIndex, conv.VisibleCols[idx].GetName())
+		}
+		conv.Datums[idx] = d
+	}
+
+	// Set any nil datums to DNull (in case the file does not have the column).
+	for i := range conv.Datums {
+		if conv.TargetColOrds.Contains(i) && conv.Datums[i] == nil {
+			if p.strict {
+				return errors.Errorf("column %s was not found in the parquet file", conv.VisibleCols[i].GetName())
+			}
+			conv.Datums[i] = tree.DNull
+		}
+	}
+	return nil
+}
+
+// newImportParquetPipeline returns a producer of the rows in the given row
+// group shard of the parquet file, and a consumer converting them to datums.
+// Only the columns of the file which map to a column of the table are read.
+//
+// If the row_limit option is set, only the rows among the first row_limit rows
+// of the file are produced. The limit applies to the file as a whole rather
+// than to each shard: since the number of rows of every row group is known
+// from the file's metadata, each shard reads the prefix of each of its row
+// groups which falls within the limit.
+func newImportParquetPipeline(
+	p *parquetInputReader, reader *file.Reader, shard, numShards int,
+) (importRowProducer, importRowConsumer, error) {
+	fieldIdxByName := make(map[string]int)
+	for idx, col := range p.importContext.tableDesc.VisibleColumns() {
+		fieldIdxByName[col.GetName()] = idx
+	}
+
+	sc := reader.MetaData().Schema
+	leavesByRoot := make(map[schema.Node][]int)
+	for i := 0; i < sc.NumColumns(); i++ {
+		root := sc.ColumnRoot(i)
+		leavesByRoot[root] = append(leavesByRoot[root], i)
+	}
+
+	producer := &parquetRowStream{reader: reader}
+	consumer := &parquetConsumer{importCtx: p.importContext, strict: p.opts.StrictMode}
+	for i := 0; i < sc.Root().NumFields(); i++ {
+		root := sc.Root().Field(i)
+		field := lexbase.NormalizeName(root.Name())
+		idx, ok := fieldIdxByName[field]
+		if !ok {
+			if p.opts.StrictMode {
+				return nil, nil, errors.Errorf("could not find column for parquet column %s", field)
+			}
+			continue
+		}
+		c, err := newParquetColumn(sc, root, leavesByRoot[root])
+		if err != nil {
+			return nil, nil, err
+		}
+		producer.columns = append(producer.columns, c)
+		consumer.colIdx = append(consumer.colIdx, idx)
+	}
+
+	var firstRow int64
+	for rg := 0; rg < reader.NumRowGroups(); rg++ {
+		numRows := reader.MetaData().RowGroup(rg).NumRows()
+		if p.opts.RowLimit > 0 {
+			if firstRow >= p.opts.RowLimit {
+				break
+			}
+			if remaining := p.opts.RowLimit - firstRow; numRows > remaining {
+				numRows = remaining
+			}
+		}
+		if rg%numShards == shard {
+			producer.rowGroups = append(producer.rowGroups, parquetRowGroup{idx: rg, numRows: numRows})
+			producer.totalRows += numRows
+		}
+		firstRow += numRows
+	}
+	return producer, consumer, nil
+}
+
+type parquetInputReader struct {
+	importContext *parallelImportContext
+	opts          roachpb.ParquetOptions
+}
+
+var _ inputConverter = &parquetInputReader{}
+
+func newParquetInputReader(
+	semaCtx *tree.SemaContext,
+	kvCh chan row.KVBatch,
+	tableDesc catalog.TableDescriptor,
+	opts roachpb.ParquetOptions,
+	walltime int64,
+	parallelism int,
+	evalCtx *eval.Context,
+	db *kv.DB,
+) *parquetInputReader {
+	return &parquetInputReader{
+		importContext: &parallelImportContext{
+			semaCtx:    semaCtx,
+			walltime:   walltime,
+			numWorkers: parallelism,
+			evalCtx:    evalCtx,
+			tableDesc:  tableDesc,
+			kvCh:       kvCh,
+			db:         db,
+		},
+		opts: opts,
+	}
+}
+
+func (p *parquetInputReader) start(group ctxgroup.Group) {}
+
+// readFiles implements the inputConverter interface. Unlike the other formats,
+// parquet files cannot be read as a stream since their metadata is stored at
+// the end of the file, so files are read using ranged reads instead of
+// readInputFiles.
+func (p *parquetInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	user username.SQLUsername,
+) error {
+	done := ctx.Done()
+	for dataFileIndex, dataFile := range dataFiles {
+		select {
+		case <-done:
+			return ctx.Err()
+		default:
+		}
+		if err := p.readFile(
+			ctx, dataFile, dataFileIndex, resumePos[dataFileIndex], makeExternalStorage, user,
+		); err != nil {
+			return errors.Wrapf(err, "%s", dataFile)
+		}
+	}
+	return nil
+}
+
+func (p *parquetInputReader) readFile(
+	ctx context.Context,
+	dataFile string,
+	inputIdx int32,
+	resumePos int64,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	user username.SQLUsername,
+) (retErr error) {
+	conf, err := cloud.ExternalStorageConfFromURI(dataFile, user)
+	if err != nil {
+		return err
+	}
+	es, err := makeExternalStorage(ctx, conf)
+	if err != nil {
+		return err
+	}
+	defer es.Close()
+	sz, err := es.Size(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	reader, err := file.NewParquetReader(&parquetFileReader{ctx: ctx, es: es, size: sz})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		retErr = errors.CombineErrors(retErr, reader.Close())
+	}()
+
+	numShards := 1
+	if p.opts.RowGroupShards > 1 {
+		numShards = int(p.opts.RowGroupShards)
+	}
+	producer, consumer, err := newImportParquetPipeline(p, reader, int(inputIdx)%numShards, numShards)
+	if err != nil {
+		return err
+	}
+
+	// The row limit is enforced by the producer, across all of the file's row
+	// group shards.
+	fileCtx := &importFileContext{
+		source: inputIdx,
+		skip:   resumePos,
+	}
+	return runParallelImport(ctx, p.importContext, fileCtx, producer, consumer)
+}
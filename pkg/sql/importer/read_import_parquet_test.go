@@ -0,0 +1,120 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package importer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandParquetRowGroupShards(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	files := []string{"a", "b"}
+	require.Equal(t, files, expandParquetRowGroupShards(files, roachpb.ParquetOptions{}))
+	require.Equal(t, files, expandParquetRowGroupShards(files, roachpb.ParquetOptions{RowGroupShards: 1}))
+	require.Equal(t, []string{"a", "a", "a", "b", "b", "b"},
+		expandParquetRowGroupShards(files, roachpb.ParquetOptions{RowGroupShards: 3}))
+}
+
+func TestImportParquet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+
+	// Write a file with several row groups, and a column which is not part of
+	// the table the file is imported into.
+	const numRows = 10
+	infoTyp := types.MakeLabeledTuple([]*types.T{types.Int, types.String}, []string{"a", "b"})
+	sch, err := parquet.NewSchema(
+		[]string{"id", "name", "tags", "info", "extra"},
+		[]*types.T{types.Int, types.String, types.StringArray, infoTyp, types.Int},
+	)
+	require.NoError(t, err)
+	f, err := os.Create(filepath.Join(dir, "data.parquet"))
+	require.NoError(t, err)
+	w, err := parquet.NewWriter(sch, f, parquet.WithMaxRowGroupLength(3))
+	require.NoError(t, err)
+	for i := 0; i < numRows; i++ {
+		tags := tree.NewDArray(types.String)
+		require.NoError(t, tags.Append(tree.NewDString(fmt.Sprintf("t%d", i))))
+		var info tree.Datum = tree.DNull
+		if i%2 == 0 {
+			info = tree.NewDTuple(infoTyp, tree.NewDInt(tree.DInt(i)), tree.NewDString(fmt.Sprintf("b%d", i)))
+		}
+		require.NoError(t, w.AddRow([]tree.Datum{
+			tree.NewDInt(tree.DInt(i)), tree.NewDString(fmt.Sprintf("n%d", i)), tags, info, tree.NewDInt(0),
+		}))
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, f.Close())
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	for _, shards := range []int{1, 3} {
+		t.Run(fmt.Sprintf("shards=%d", shards), func(t *testing.T) {
+			sqlDB.Exec(t, fmt.Sprintf(`SET CLUSTER SETTING bulkio.import.parquet_row_group_shards = %d`, shards))
+			table := fmt.Sprintf("t%d", shards)
+			sqlDB.Exec(t, fmt.Sprintf(
+				`CREATE TABLE %s (id INT PRIMARY KEY, name STRING, tags STRING[], info JSONB, missing INT)`, table))
+			sqlDB.Exec(t, fmt.Sprintf(`IMPORT INTO %s PARQUET DATA ('nodelocal://1/data.parquet')`, table))
+			sqlDB.CheckQueryResults(t, fmt.Sprintf(`SELECT count(*), count(info), count(missing) FROM %s`, table),
+				[][]string{{fmt.Sprint(numRows), fmt.Sprint(numRows / 2), "0"}})
+			sqlDB.CheckQueryResults(t, fmt.Sprintf(`SELECT id, name, tags, info FROM %s WHERE id IN (2, 3) ORDER BY id`, table),
+				[][]string{
+					{"2", "n2", "{t2}", `{"a": 2, "b": "b2"}`},
+					{"3", "n3", "{t3}", "NULL"},
+				})
+		})
+	}
+
+	// The row limit applies to the file as a whole, even when its row groups
+	// are read by different shards: the file has row groups of rows [0, 3),
+	// [3, 6), [6, 9) and [9, 10), of which the first two and the first row of the third
+	// are read.
+	for _, shards := range []int{1, 3} {
+		t.Run(fmt.Sprintf("row_limit/shards=%d", shards), func(t *testing.T) {
+			sqlDB.Exec(t, fmt.Sprintf(`SET CLUSTER SETTING bulkio.import.parquet_row_group_shards = %d`, shards))
+			table := fmt.Sprintf("limited%d", shards)
+			sqlDB.Exec(t, fmt.Sprintf(`CREATE TABLE %s (id INT PRIMARY KEY, name STRING)`, table))
+			sqlDB.Exec(t, fmt.Sprintf(
+				`IMPORT INTO %s PARQUET DATA ('nodelocal://1/data.parquet') WITH row_limit = '7'`, table))
+			sqlDB.CheckQueryResults(t, fmt.Sprintf(`SELECT count(*), min(id), max(id) FROM %s`, table),
+				[][]string{{"7", "0", "6"}})
+		})
+	}
+
+	t.Run("strict", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE strict (id INT PRIMARY KEY, name STRING, tags STRING[], info JSONB)`)
+		sqlDB.ExpectErr(t, "could not find column for parquet column extra",
+			`IMPORT INTO strict PARQUET DATA ('nodelocal://1/data.parquet') WITH strict_validation`)
+	})
+}
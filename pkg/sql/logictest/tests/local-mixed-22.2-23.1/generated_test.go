@@ -1520,6 +1520,13 @@ func TestLogic_row_level_ttl(
 	runLogicTest(t, "row_level_ttl")
 }
 
+func TestLogic_row_level_ttl_storage_expiry_mixed(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "row_level_ttl_storage_expiry_mixed")
+}
+
 func TestLogic_rows_from(
 	t *testing.T,
 ) {
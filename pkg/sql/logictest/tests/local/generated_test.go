@@ -1681,6 +1681,13 @@ func TestLogic_reset(
 	runLogicTest(t, "reset")
 }
 
+func TestLogic_resource_groups(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "resource_groups")
+}
+
 func TestLogic_retry(
 	t *testing.T,
 ) {
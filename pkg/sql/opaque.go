@@ -139,6 +139,8 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.alterTenantService(ctx, n)
 	case *tree.AlterType:
 		return p.AlterType(ctx, n)
+	case *tree.AlterResourceGroup:
+		return p.AlterResourceGroup(ctx, n)
 	case *tree.AlterRole:
 		return p.AlterRole(ctx, n)
 	case *tree.AlterRoleSet:
@@ -159,6 +161,8 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.CommentOnIndex(ctx, n)
 	case *tree.CommentOnTable:
 		return p.CommentOnTable(ctx, n)
+	case *tree.CommitPrepared:
+		return p.CommitPrepared(ctx, n)
 	case *tree.CopyTo:
 		// COPY TO does not actually get prepared in any meaningful way. This means
 		// it can't have placeholder arguments, and the execution can use the same
@@ -180,6 +184,8 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.CreateExtension(ctx, n)
 	case *tree.CreateExternalConnection:
 		return p.CreateExternalConnection(ctx, n)
+	case *tree.CreateResourceGroup:
+		return p.CreateResourceGroup(ctx, n)
 	case *tree.CreateTenant:
 		return p.CreateTenantNode(ctx, n)
 	case *tree.DropExternalConnection:
@@ -198,6 +204,8 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.DropIndex(ctx, n)
 	case *tree.DropOwnedBy:
 		return p.DropOwnedBy(ctx)
+	case *tree.DropResourceGroup:
+		return p.DropResourceGroup(ctx, n)
 	case *tree.DropRole:
 		return p.DropRole(ctx, n)
 	case *tree.DropSchema:
@@ -238,6 +246,8 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.Revoke(ctx, n)
 	case *tree.RevokeRole:
 		return p.RevokeRole(ctx, n)
+	case *tree.RollbackPrepared:
+		return p.RollbackPrepared(ctx, n)
 	case *tree.Scatter:
 		return p.Scatter(ctx, n)
 	case *tree.Scrub:
@@ -284,6 +294,12 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.Unlisten(ctx, n)
 	case *pgrepltree.IdentifySystem:
 		return p.IdentifySystem(ctx, n)
+	case *tree.Export:
+		plan, err := p.maybePlanHook(ctx, stmt)
+		if plan == nil && err == nil {
+			return nil, errors.AssertionFailedf("no plan hook for EXPORT run as a job")
+		}
+		return plan, err
 	case tree.CCLOnlyStatement:
 		plan, err := p.maybePlanHook(ctx, stmt)
 		if plan == nil && err == nil {
@@ -330,6 +346,7 @@ func init() {
 		&tree.AlterTenantService{},
 		&tree.AlterType{},
 		&tree.AlterSequence{},
+		&tree.AlterResourceGroup{},
 		&tree.AlterRole{},
 		&tree.AlterRoleSet{},
 		&tree.CloseCursor{},
@@ -339,10 +356,12 @@ func init() {
 		&tree.CommentOnIndex{},
 		&tree.CommentOnConstraint{},
 		&tree.CommentOnTable{},
+		&tree.CommitPrepared{},
 		&tree.CopyTo{},
 		&tree.CreateDatabase{},
 		&tree.CreateExtension{},
 		&tree.CreateExternalConnection{},
+		&tree.CreateResourceGroup{},
 		&tree.CreateTenant{},
 		&tree.CreateIndex{},
 		&tree.CreateSchema{},
@@ -357,6 +376,7 @@ func init() {
 		&tree.DropFunction{},
 		&tree.DropIndex{},
 		&tree.DropOwnedBy{},
+		&tree.DropResourceGroup{},
 		&tree.DropRole{},
 		&tree.DropSchema{},
 		&tree.DropSequence{},
@@ -377,6 +397,7 @@ func init() {
 		&tree.ReparentDatabase{},
 		&tree.Revoke{},
 		&tree.RevokeRole{},
+		&tree.RollbackPrepared{},
 		&tree.Scatter{},
 		&tree.Scrub{},
 		&tree.SetClusterSetting{},
@@ -402,6 +423,10 @@ func init() {
 
 		&pgrepltree.IdentifySystem{},
 
+		// Export has an optimizer operator, but exports which are run by a job
+		// are planned by a plan hook.
+		&tree.Export{},
+
 		// CCL statements (without Export which has an optimizer operator).
 		&tree.AlterBackup{},
 		&tree.AlterBackupSchedule{},
@@ -414,6 +439,7 @@ func init() {
 		&tree.Import{},
 		&tree.ScheduledBackup{},
 		&tree.CreateTenantFromReplication{},
+		&tree.VerifyBackup{},
 	} {
 		typ := optbuilder.OpaqueReadOnly
 		if tree.CanModifySchema(stmt) {
@@ -51,6 +51,10 @@ type Table interface {
 	// that they cannot be mutated.
 	IsMaterializedView() bool
 
+	// HasExternalRowData returns true if the rows of this table are read from
+	// another tenant's keyspace. Such tables cannot be mutated.
+	HasExternalRowData() bool
+
 	// ColumnCount returns the number of columns in the table. This includes
 	// public columns, write-only columns, etc.
 	ColumnCount() int
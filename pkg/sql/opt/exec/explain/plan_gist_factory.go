@@ -506,6 +506,10 @@ func (u *unknownTable) IsMaterializedView() bool {
 	return false
 }
 
+func (u *unknownTable) HasExternalRowData() bool {
+	return false
+}
+
 func (u *unknownTable) ColumnCount() int {
 	return 0
 }
@@ -419,6 +419,13 @@ func (b *Builder) buildStmt(
 		}, inScope)
 
 	case *tree.Export:
+		// Exports which are run by a job are planned by a plan hook rather than
+		// by the optimizer.
+		if stmt.RunsAsJob() {
+			if outScope := b.tryBuildOpaque(stmt, inScope); outScope != nil {
+				return outScope
+			}
+		}
 		return b.buildExport(stmt, inScope)
 
 	default:
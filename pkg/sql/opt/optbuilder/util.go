@@ -618,6 +618,11 @@ func (b *Builder) resolveTableForMutation(
 		panic(pgerror.Newf(pgcode.WrongObjectType, "cannot mutate materialized view %q", tab.Name()))
 	}
 
+	// We can't mutate tables whose rows live in another tenant's keyspace.
+	if tab.HasExternalRowData() {
+		panic(pgerror.Newf(pgcode.ReadOnlySQLTransaction, "cannot mutate read-only table %q", tab.Name()))
+	}
+
 	return tab, depName, alias, columns
 }
 
@@ -804,6 +804,11 @@ func (tt *Table) IsMaterializedView() bool {
 	return false
 }
 
+// HasExternalRowData is part of the cat.Table interface.
+func (tt *Table) HasExternalRowData() bool {
+	return false
+}
+
 // ColumnCount is part of the cat.Table interface.
 func (tt *Table) ColumnCount() int {
 	return len(tt.Columns)
@@ -1196,6 +1196,11 @@ func (ot *optTable) IsMaterializedView() bool {
 	return ot.desc.MaterializedView()
 }
 
+// HasExternalRowData implements the cat.Table interface.
+func (ot *optTable) HasExternalRowData() bool {
+	return ot.desc.ExternalRowData() != nil
+}
+
 // ColumnCount is part of the cat.Table interface.
 func (ot *optTable) ColumnCount() int {
 	return len(ot.columns)
@@ -2229,6 +2234,11 @@ func (ot *optVirtualTable) IsMaterializedView() bool {
 	return false
 }
 
+// HasExternalRowData implements the cat.Table interface.
+func (ot *optVirtualTable) HasExternalRowData() bool {
+	return false
+}
+
 // ColumnCount is part of the cat.Table interface.
 func (ot *optVirtualTable) ColumnCount() int {
 	return len(ot.columns)
@@ -43,6 +43,9 @@ func TestContextualHelp(t *testing.T) {
 
 		{`ALTER BACKUP foo ADD NEW_KMS=bar WITH OLD_KMS=foobar ??`, `ALTER BACKUP`},
 
+		{`ALTER RESOURCE GROUP ??`, `ALTER RESOURCE GROUP`},
+		{`ALTER RESOURCE GROUP blah ??`, `ALTER RESOURCE GROUP`},
+
 		{`ALTER TABLE IF ??`, `ALTER TABLE`},
 		{`ALTER TABLE blah ??`, `ALTER TABLE`},
 		{`ALTER TABLE blah ADD ??`, `ALTER TABLE`},
@@ -177,6 +180,9 @@ func TestContextualHelp(t *testing.T) {
 
 		{`CREATE EXTERNAL CONNECTION ??`, `CREATE EXTERNAL CONNECTION`},
 
+		{`CREATE RESOURCE GROUP ??`, `CREATE RESOURCE GROUP`},
+		{`CREATE RESOURCE GROUP IF NOT ??`, `CREATE RESOURCE GROUP`},
+
 		{`CREATE VIRTUAL CLUSTER ??`, `CREATE VIRTUAL CLUSTER`},
 		{`CREATE TENANT ??`, `CREATE VIRTUAL CLUSTER`},
 
@@ -228,6 +234,8 @@ func TestContextualHelp(t *testing.T) {
 
 		{`DROP EXTERNAL CONNECTION blah ??`, `DROP EXTERNAL CONNECTION`},
 
+		{`DROP RESOURCE GROUP ??`, `DROP RESOURCE GROUP`},
+
 		{`DROP USER ??`, `DROP ROLE`},
 		{`DROP USER IF ??`, `DROP ROLE`},
 		{`DROP USER IF EXISTS bluh ??`, `DROP ROLE`},
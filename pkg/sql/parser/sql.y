@@ -534,6 +534,9 @@ func (u *sqlSymUnion) backupTargetList() tree.BackupTargetList {
 func (u *sqlSymUnion) backupTargetListPtr() *tree.BackupTargetList {
     return u.val.(*tree.BackupTargetList)
 }
+func (u *sqlSymUnion) descriptorCoverage() tree.DescriptorCoverage {
+    return u.val.(tree.DescriptorCoverage)
+}
 func (u *sqlSymUnion) grantTargetList() tree.GrantTargetList {
     return u.val.(tree.GrantTargetList)
 }
@@ -952,7 +955,7 @@ func (u *sqlSymUnion) beginTransaction() *tree.BeginTransaction {
 %token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INHERITS INJECT INITIALLY
 %token <str> INDEX_BEFORE_PAREN INDEX_BEFORE_NAME_THEN_PAREN INDEX_AFTER_ORDER_BY_BEFORE_AT
 %token <str> INNER INOUT INPUT INSENSITIVE INSERT INT INTEGER
-%token <str> INTERSECT INTERVAL INTO INTO_DB INVERTED INVOKER IS ISERROR ISNULL ISOLATION
+%token <str> INTERSECT INTERVAL INTO INTO_DB INTO_TABLE INVERTED INVOKER IS ISERROR ISNULL ISOLATION
 
 %token <str> JOB JOBS JOIN JSON JSONB JSON_SOME_EXISTS JSON_ALL_EXISTS
 
@@ -980,7 +983,7 @@ func (u *sqlSymUnion) beginTransaction() *tree.BeginTransaction {
 
 %token <str> PARALLEL PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PAUSED PHYSICAL PLACEMENT PLACING
 %token <str> PLAN PLANS POINT POINTM POINTZ POINTZM POLYGON POLYGONM POLYGONZ POLYGONZM
-%token <str> POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIOR PRIORITY PRIVILEGES
+%token <str> POSITION PRECEDING PRECISION PREPARE PREPARED PRESERVE PRIMARY PRIOR PRIORITY PRIVILEGES
 %token <str> PROCEDURAL PROCEDURE PUBLIC PUBLICATION
 
 %token <str> QUERIES QUERY QUOTE
@@ -988,7 +991,7 @@ func (u *sqlSymUnion) beginTransaction() *tree.BeginTransaction {
 %token <str> RANGE RANGES READ REAL REASON REASSIGN RECURSIVE RECURRING REDACT REF REFERENCES REFRESH
 %token <str> REGCLASS REGION REGIONAL REGIONS REGNAMESPACE REGPROC REGPROCEDURE REGROLE REGTYPE REINDEX
 %token <str> RELATIVE RELOCATE REMOVE_PATH REMOVE_REGIONS RENAME REPEATABLE REPLACE REPLICATION
-%token <str> RELEASE RESET RESTART RESTORE RESTRICT RESTRICTED RESUME RETENTION RETURNING RETURN RETURNS RETRY REVISION_HISTORY
+%token <str> RELEASE RESET RESOURCE RESTART RESTORE RESTRICT RESTRICTED RESUME RETENTION RETURNING RETURN RETURNS RETRY REVISION_HISTORY
 %token <str> REVOKE RIGHT ROLE ROLES ROLLBACK ROLLUP ROUTINES ROW ROWS RSHIFT RULE RUNNING
 
 %token <str> SAVEPOINT SCANS SCATTER SCHEDULE SCHEDULES SCROLL SCHEMA SCHEMA_ONLY SCHEMAS SCRUB
@@ -1009,7 +1012,7 @@ func (u *sqlSymUnion) beginTransaction() *tree.BeginTransaction {
 %token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLISTEN UNLOGGED UNSAFE_RESTORE_INCOMPATIBLE_VERSION UNSPLIT
 %token <str> UPDATE UPDATES_CLUSTER_MONITORING_METRICS UPSERT UNSET UNTIL USE USER USERS USING UUID
 
-%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VERIFY_BACKUP_TABLE_DATA VIEW VARYING VIEWACTIVITY VIEWACTIVITYREDACTED VIEWDEBUG
+%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VERIFY VERIFY_BACKUP_TABLE_DATA VIEW VARYING VIEWACTIVITY VIEWACTIVITYREDACTED VIEWDEBUG
 %token <str> VIEWCLUSTERMETADATA VIEWCLUSTERSETTING VIRTUAL VISIBLE INVISIBLE VISIBILITY VOLATILE VOTERS
 %token <str> VIRTUAL_CLUSTER_NAME VIRTUAL_CLUSTER
 
@@ -1088,6 +1091,7 @@ func (u *sqlSymUnion) beginTransaction() *tree.BeginTransaction {
 
 // ALTER VIRTUAL CLUSTER
 %type <tree.Statement> alter_virtual_cluster_stmt
+%type <tree.Statement> alter_resource_group_stmt
 
 // ALTER VIRTUAL CLUSTER CAPABILITY
 %type <tree.Statement> virtual_cluster_capability virtual_cluster_capability_list
@@ -1185,6 +1189,7 @@ func (u *sqlSymUnion) beginTransaction() *tree.BeginTransaction {
 %type <tree.Statement> create_database_stmt
 %type <tree.Statement> create_extension_stmt
 %type <tree.Statement> create_external_connection_stmt
+%type <tree.Statement> create_resource_group_stmt
 %type <tree.Statement> create_index_stmt
 %type <tree.Statement> create_role_stmt
 %type <tree.Statement> create_schedule_for_backup_stmt
@@ -1213,6 +1218,7 @@ func (u *sqlSymUnion) beginTransaction() *tree.BeginTransaction {
 %type <tree.Statement> drop_ddl_stmt
 %type <tree.Statement> drop_database_stmt
 %type <tree.Statement> drop_external_connection_stmt
+%type <tree.Statement> drop_resource_group_stmt
 %type <tree.Statement> drop_index_stmt
 %type <tree.Statement> drop_role_stmt
 %type <tree.Statement> drop_schema_stmt
@@ -1232,6 +1238,7 @@ func (u *sqlSymUnion) beginTransaction() *tree.BeginTransaction {
 %type <tree.Statement> row_source_extension_stmt
 %type <tree.Statement> copy_to_stmt
 %type <tree.Statement> export_stmt
+%type <tree.Statement> verify_backup_stmt
 %type <tree.Statement> execute_stmt
 %type <tree.Statement> deallocate_stmt
 %type <tree.Statement> grant_stmt
@@ -1638,6 +1645,7 @@ func (u *sqlSymUnion) beginTransaction() *tree.BeginTransaction {
 %type <tree.ChangefeedTarget> changefeed_target
 %type <tree.BackupTargetList> backup_targets
 %type <*tree.BackupTargetList> opt_backup_targets
+%type <tree.DescriptorCoverage> system_backup_target
 
 %type <tree.GrantTargetList> grant_targets targets_roles target_types
 %type <tree.TableExpr> changefeed_target_expr
@@ -1810,6 +1818,7 @@ stmt_without_legacy_transaction:
 alter_stmt:
   alter_ddl_stmt      // help texts in sub-rule
 | alter_role_stmt     // EXTEND WITH HELP: ALTER ROLE
+| alter_resource_group_stmt    // EXTEND WITH HELP: ALTER RESOURCE GROUP
 | alter_virtual_cluster_stmt   /* SKIP DOC */
 | alter_unsupported_stmt
 | ALTER error         // SHOW HELP: ALTER
@@ -3176,6 +3185,9 @@ opt_clear_data:
 //    Empty targets list: backup full cluster.
 //    TABLE <pattern> [, ...]
 //    DATABASE <databasename> [, ...]
+//    SYSTEM USERS: backup users, roles, role memberships and system privileges
+//    SYSTEM SETTINGS: backup cluster settings
+//    SYSTEM ZONE CONFIGURATIONS: backup zone configurations
 //
 // Destination:
 //    "[scheme]://[host]/[path to backup]?[parameters]"
@@ -3233,8 +3245,54 @@ backup_stmt:
       Options: *$7.backupOptions(),
     }
   }
+| BACKUP SYSTEM system_backup_target INTO sconst_or_placeholder IN string_or_placeholder_opt_list opt_as_of_clause opt_with_backup_options
+  {
+    $$.val = &tree.Backup{
+      SystemTarget: $3.descriptorCoverage(),
+      To: $7.stringOrPlaceholderOptList(),
+      Nested: true,
+      Subdir: $5.expr(),
+      AsOf: $8.asOfClause(),
+      Options: *$9.backupOptions(),
+    }
+  }
+| BACKUP SYSTEM system_backup_target INTO string_or_placeholder_opt_list opt_as_of_clause opt_with_backup_options
+  {
+    $$.val = &tree.Backup{
+      SystemTarget: $3.descriptorCoverage(),
+      To: $5.stringOrPlaceholderOptList(),
+      Nested: true,
+      AsOf: $6.asOfClause(),
+      Options: *$7.backupOptions(),
+    }
+  }
+| BACKUP SYSTEM system_backup_target INTO LATEST IN string_or_placeholder_opt_list opt_as_of_clause opt_with_backup_options
+  {
+    $$.val = &tree.Backup{
+      SystemTarget: $3.descriptorCoverage(),
+      To: $7.stringOrPlaceholderOptList(),
+      Nested: true,
+      AppendToLatest: true,
+      AsOf: $8.asOfClause(),
+      Options: *$9.backupOptions(),
+    }
+  }
 | BACKUP error // SHOW HELP: BACKUP
 
+system_backup_target:
+  USERS
+  {
+    $$.val = tree.SystemUsers
+  }
+| SETTINGS
+  {
+    $$.val = tree.SystemSettings
+  }
+| ZONE CONFIGURATIONS
+  {
+    $$.val = tree.SystemZoneConfigs
+  }
+
 opt_backup_targets:
   /* EMPTY -- full cluster */
   {
@@ -3614,6 +3672,60 @@ drop_external_connection_stmt:
 	}
 	| DROP EXTERNAL CONNECTION error // SHOW HELP: DROP EXTERNAL CONNECTION
 
+// %Help: CREATE RESOURCE GROUP - create a new resource group
+// %Category: Misc
+// %Text:
+// CREATE RESOURCE GROUP [IF NOT EXISTS] <name> [WITH <option> = <value> [, ...]]
+//
+// Options:
+//   cpu_share:       relative share of CPU of the group's work (1-100, default 10)
+//   io_share:        relative share of store write bandwidth (1-100, default 10)
+//   max_concurrency: maximum number of KV requests of the group admitted at once
+//
+// %SeeAlso: ALTER RESOURCE GROUP, DROP RESOURCE GROUP
+create_resource_group_stmt:
+  CREATE RESOURCE GROUP name opt_with_options
+  {
+    $$.val = &tree.CreateResourceGroup{Name: tree.Name($4), Options: $5.kvOptions()}
+  }
+| CREATE RESOURCE GROUP IF NOT EXISTS name opt_with_options
+  {
+    $$.val = &tree.CreateResourceGroup{Name: tree.Name($7), IfNotExists: true, Options: $8.kvOptions()}
+  }
+| CREATE RESOURCE GROUP error // SHOW HELP: CREATE RESOURCE GROUP
+
+// %Help: ALTER RESOURCE GROUP - change the options of a resource group
+// %Category: Misc
+// %Text:
+// ALTER RESOURCE GROUP [IF EXISTS] <name> WITH <option> = <value> [, ...]
+//
+// %SeeAlso: CREATE RESOURCE GROUP, DROP RESOURCE GROUP
+alter_resource_group_stmt:
+  ALTER RESOURCE GROUP name WITH kv_option_list
+  {
+    $$.val = &tree.AlterResourceGroup{Name: tree.Name($4), Options: $6.kvOptions()}
+  }
+| ALTER RESOURCE GROUP IF EXISTS name WITH kv_option_list
+  {
+    $$.val = &tree.AlterResourceGroup{Name: tree.Name($6), IfExists: true, Options: $8.kvOptions()}
+  }
+| ALTER RESOURCE GROUP error // SHOW HELP: ALTER RESOURCE GROUP
+
+// %Help: DROP RESOURCE GROUP - remove a resource group
+// %Category: Misc
+// %Text: DROP RESOURCE GROUP [IF EXISTS] <name>
+// %SeeAlso: CREATE RESOURCE GROUP, ALTER RESOURCE GROUP
+drop_resource_group_stmt:
+  DROP RESOURCE GROUP name
+  {
+    $$.val = &tree.DropResourceGroup{Name: tree.Name($4)}
+  }
+| DROP RESOURCE GROUP IF EXISTS name
+  {
+    $$.val = &tree.DropResourceGroup{Name: tree.Name($6), IfExists: true}
+  }
+| DROP RESOURCE GROUP error // SHOW HELP: DROP RESOURCE GROUP
+
 // %Help: RESTORE - restore data from external storage
 // %Category: CCL
 // %Text:
@@ -3621,7 +3733,7 @@ drop_external_connection_stmt:
 //         [ AS OF SYSTEM TIME <expr> ]
 //         [ WITH <option> [= <value>] [, ...] ]
 // or
-// RESTORE SYSTEM USERS FROM <location...>
+// RESTORE SYSTEM { USERS | SETTINGS | ZONE CONFIGURATIONS } FROM <location...>
 //         [ AS OF SYSTEM TIME <expr> ]
 //         [ WITH <option> [= <value>] [, ...] ]
 //
@@ -3629,6 +3741,13 @@ drop_external_connection_stmt:
 //    TABLE <pattern> [, ...]
 //    DATABASE <databasename> [, ...]
 //
+// System targets:
+//    USERS: restore the users and roles which do not exist in the cluster, with their
+//           grants and settings; existing users and roles are left unchanged
+//    SETTINGS: restore cluster settings, except for the cluster version, license,
+//              organization and secret, and cluster.preserve_downgrade_option
+//    ZONE CONFIGURATIONS: restore zone configurations of the cluster's ranges and system tables
+//
 // Locations:
 //    "[scheme]://[host]/[path to backup]?[parameters]"
 //
@@ -3686,19 +3805,19 @@ restore_stmt:
       Options: *($8.restoreOptions()),
     }
   }
-| RESTORE SYSTEM USERS FROM list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
+| RESTORE SYSTEM system_backup_target FROM list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
   {
     $$.val = &tree.Restore{
-      DescriptorCoverage: tree.SystemUsers,
+      DescriptorCoverage: $3.descriptorCoverage(),
       From: $5.listOfStringOrPlaceholderOptList(),
       AsOf: $6.asOfClause(),
       Options: *($7.restoreOptions()),
     }
   }
-| RESTORE SYSTEM USERS FROM string_or_placeholder IN list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
+| RESTORE SYSTEM system_backup_target FROM string_or_placeholder IN list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
   {
     $$.val = &tree.Restore{
-      DescriptorCoverage: tree.SystemUsers,
+      DescriptorCoverage: $3.descriptorCoverage(),
       Subdir: $5.expr(),
       From: $7.listOfStringOrPlaceholderOptList(),
       AsOf: $8.asOfClause(),
@@ -3769,6 +3888,14 @@ restore_options:
   {
     $$.val = &tree.RestoreOptions{IntoDB: $3.expr()}
   }
+| INTO_TABLE '=' string_or_placeholder
+  {
+    $$.val = &tree.RestoreOptions{IntoTable: $3.expr()}
+  }
+| FILTER '=' string_or_placeholder
+  {
+    $$.val = &tree.RestoreOptions{Filter: $3.expr()}
+  }
 | SKIP_MISSING_FOREIGN_KEYS
   {
     $$.val = &tree.RestoreOptions{SkipMissingFKs: true}
@@ -3932,6 +4059,8 @@ import_stmt:
 // %Category: CCL
 // %Text:
 // EXPORT INTO <format> <datafile> [WITH <option> [= value] [,...]] FROM <query>
+// EXPORT INTO <format> <datafile> [INCREMENTAL FROM <previous export>]
+//        [WITH <option> [= value] [,...]] FROM TABLE <tablename>
 //
 // Formats:
 //    CSV
@@ -3939,6 +4068,8 @@ import_stmt:
 //
 // Options:
 //    delimiter = '...'   [CSV-specific]
+//    resumable           [run the export of a table as a resumable job]
+//    detached            [run the export of a table as a job in the background]
 //
 // %SeeAlso: SELECT
 export_stmt:
@@ -3946,8 +4077,33 @@ export_stmt:
   {
     $$.val = &tree.Export{Query: $7.slct(), FileFormat: $3, File: $4.expr(), Options: $5.kvOptions()}
   }
+| EXPORT INTO import_format string_or_placeholder INCREMENTAL FROM string_or_placeholder opt_with_options FROM select_stmt
+  {
+    $$.val = &tree.Export{Query: $10.slct(), FileFormat: $3, File: $4.expr(), IncrementalFrom: $7.expr(), Options: $8.kvOptions()}
+  }
 | EXPORT error // SHOW HELP: EXPORT
 
+// %Help: VERIFY BACKUP - check that a backup can be restored
+// %Category: CCL
+// %Text:
+// VERIFY BACKUP <subdir> IN <collection...> [WITH <option> [= <value>] [, ...]]
+//
+// Options:
+//    encryption_passphrase = '...'  [passphrase the backup was encrypted with]
+//    kms = '...'                    [URI of a KMS the backup was encrypted with]
+//    incremental_location = '...'   [location of the backup's incremental layers]
+//    delete_compacted_layers        [delete the compacted layers once no job reads them]
+//    fingerprint                    [also compare the backup with this cluster's data]
+//    detached                       [run the verification as a background job]
+//
+// %SeeAlso: SHOW BACKUP, RESTORE
+verify_backup_stmt:
+  VERIFY BACKUP string_or_placeholder IN string_or_placeholder_opt_list opt_with_options
+  {
+    $$.val = &tree.VerifyBackup{Subdir: $3.expr(), To: $5.stringOrPlaceholderOptList(), Options: $6.kvOptions()}
+  }
+| VERIFY error // SHOW HELP: VERIFY BACKUP
+
 string_or_placeholder:
   non_reserved_word_or_sconst
   {
@@ -4450,6 +4606,7 @@ create_stmt:
 | create_changefeed_stmt // EXTEND WITH HELP: CREATE CHANGEFEED
 | create_extension_stmt  // EXTEND WITH HELP: CREATE EXTENSION
 | create_external_connection_stmt // EXTEND WITH HELP: CREATE EXTERNAL CONNECTION
+| create_resource_group_stmt      // EXTEND WITH HELP: CREATE RESOURCE GROUP
 | create_virtual_cluster_stmt     // EXTEND WITH HELP: CREATE VIRTUAL CLUSTER
 | create_schedule_stmt   // help texts in sub-rule
 | create_unsupported     {}
@@ -4556,6 +4713,10 @@ replication_options:
   {
     $$.val = &tree.TenantReplicationOptions{Retention: $3.expr()}
   }
+| READ VIRTUAL CLUSTER
+  {
+    $$.val = &tree.TenantReplicationOptions{ReadVirtualCluster: true}
+  }
 
 // %Help: CREATE SCHEDULE
 // %Category: Group
@@ -5464,6 +5625,7 @@ drop_stmt:
 | drop_role_stmt                // EXTEND WITH HELP: DROP ROLE
 | drop_schedule_stmt            // EXTEND WITH HELP: DROP SCHEDULES
 | drop_external_connection_stmt // EXTEND WITH HELP: DROP EXTERNAL CONNECTION
+| drop_resource_group_stmt      // EXTEND WITH HELP: DROP RESOURCE GROUP
 | drop_virtual_cluster_stmt     // EXTEND WITH HELP: DROP VIRTUAL CLUSTER
 | drop_unsupported   {}
 | DROP error                    // SHOW HELP: DROP
@@ -5846,6 +6008,7 @@ preparable_stmt:
 | truncate_stmt     // EXTEND WITH HELP: TRUNCATE
 | update_stmt       // EXTEND WITH HELP: UPDATE
 | upsert_stmt       // EXTEND WITH HELP: UPSERT
+| verify_backup_stmt // EXTEND WITH HELP: VERIFY BACKUP
 
 // These are statements that can be used as a data source using the special
 // syntax with brackets. These are a subset of preparable_stmt.
@@ -5934,17 +6097,28 @@ alter_changefeed_cmd:
     }
   }
 
-// %Help: ALTER BACKUP - alter an existing backup's encryption keys
+// %Help: ALTER BACKUP - alter an existing backup's encryption keys or compact its incremental layers
 // %Category: CCL
 // %Text:
 // ALTER BACKUP <location...>
 //        [ ADD NEW_KMS = <kms...> ]
 //        [ WITH OLD_KMS = <kms...> ]
+// ALTER BACKUP <subdir> IN <location...>
+//        COMPACT [ WITH <option> [= <value>] [, ...] ]
 // Locations:
 //    "[scheme]://[host]/[path to backup]?[parameters]"
 //
 // KMS:
 //    "[kms_provider]://[kms_host]/[master_key_identifier]?[parameters]" : add new kms keys to backup
+//
+// Compaction options:
+//    start_time = '...'             [earliest start time of the layers to compact]
+//    end_time = '...'               [latest end time of the layers to compact]
+//    revision_history               [preserve the revision history of the compacted layers]
+//    encryption_passphrase = '...'  [passphrase the backup was encrypted with]
+//    kms = '...'                    [URI of a KMS the backup was encrypted with]
+//    incremental_location = '...'   [location of the backup's incremental layers]
+//    detached                       [run the compaction as a background job]
 alter_backup_stmt:
   ALTER BACKUP string_or_placeholder alter_backup_cmds
   {
@@ -5980,6 +6154,12 @@ alter_backup_cmd:
       KMSInfo:	$2.backupKMS(),
     }
 	}
+|	COMPACT opt_with_options
+	{
+    $$.val = &tree.AlterBackupCompact{
+      Options:	$2.kvOptions(),
+    }
+	}
 
 backup_kms:
 	NEW_KMS '=' string_or_placeholder_opt_list WITH OLD_KMS '=' string_or_placeholder_opt_list
@@ -6079,8 +6259,10 @@ show_virtual_cluster_options:
 
 // %Help: PREPARE - prepare a statement for later execution
 // %Category: Misc
-// %Text: PREPARE <name> [ ( <types...> ) ] AS <query>
-// %SeeAlso: EXECUTE, DEALLOCATE, DISCARD
+// %Text:
+// PREPARE <name> [ ( <types...> ) ] AS <query>
+// PREPARE TRANSACTION <transaction id>
+// %SeeAlso: EXECUTE, DEALLOCATE, DISCARD, COMMIT, ROLLBACK
 prepare_stmt:
   PREPARE table_alias_name prep_type_clause AS preparable_stmt
   {
@@ -6099,6 +6281,10 @@ prepare_stmt:
       Statement: &tree.CannedOptPlan{Plan: $7},
     }
   }
+| PREPARE TRANSACTION SCONST
+  {
+    $$.val = &tree.PrepareTransaction{Transaction: tree.NewStrVal($3)}
+  }
 | PREPARE error // SHOW HELP: PREPARE
 
 prep_type_clause:
@@ -11727,12 +11913,17 @@ begin_stmt:
 // %Text:
 // COMMIT [TRANSACTION]
 // END [TRANSACTION]
-// %SeeAlso: BEGIN, ROLLBACK, WEBDOCS/commit-transaction.html
+// COMMIT PREPARED <transaction id>
+// %SeeAlso: BEGIN, ROLLBACK, PREPARE, WEBDOCS/commit-transaction.html
 commit_stmt:
   COMMIT opt_transaction
   {
     $$.val = &tree.CommitTransaction{}
   }
+| COMMIT PREPARED SCONST
+  {
+    $$.val = &tree.CommitPrepared{Transaction: tree.NewStrVal($3)}
+  }
 | COMMIT error // SHOW HELP: COMMIT
 
 abort_stmt:
@@ -11751,12 +11942,17 @@ opt_abort_mod:
 // %Text:
 // ROLLBACK [TRANSACTION]
 // ROLLBACK [TRANSACTION] TO [SAVEPOINT] <savepoint name>
-// %SeeAlso: BEGIN, COMMIT, SAVEPOINT, WEBDOCS/rollback-transaction.html
+// ROLLBACK PREPARED <transaction id>
+// %SeeAlso: BEGIN, COMMIT, SAVEPOINT, PREPARE, WEBDOCS/rollback-transaction.html
 rollback_stmt:
   ROLLBACK opt_transaction
   {
      $$.val = &tree.RollbackTransaction{}
   }
+| ROLLBACK PREPARED SCONST
+  {
+     $$.val = &tree.RollbackPrepared{Transaction: tree.NewStrVal($3)}
+  }
 | ROLLBACK opt_transaction TO savepoint_name
   {
      $$.val = &tree.RollbackToSavepoint{Savepoint: tree.Name($4)}
@@ -16752,6 +16948,7 @@ unreserved_keyword:
 | INPUT
 | INSERT
 | INTO_DB
+| INTO_TABLE
 | INVERTED
 | INVISIBLE
 | ISOLATION
@@ -16870,6 +17067,7 @@ unreserved_keyword:
 | POLYGONZM
 | PRECEDING
 | PREPARE
+| PREPARED
 | PRESERVE
 | PRIOR
 | PRIORITY
@@ -16903,6 +17101,7 @@ unreserved_keyword:
 | REPLACE
 | REPLICATION
 | RESET
+| RESOURCE
 | RESTART
 | RESTORE
 | RESTRICT
@@ -17026,6 +17225,7 @@ unreserved_keyword:
 | VALIDATE
 | VALUE
 | VARYING
+| VERIFY
 | VERIFY_BACKUP_TABLE_DATA
 | VIEW
 | VIEWACTIVITY
@@ -17283,6 +17483,7 @@ bare_label_keywords:
 | INTEGER
 | INTERVAL
 | INTO_DB
+| INTO_TABLE
 | INVERTED
 | INVISIBLE
 | INVOKER
@@ -17422,6 +17623,7 @@ bare_label_keywords:
 | POSITION
 | PRECEDING
 | PREPARE
+| PREPARED
 | PRESERVE
 | PRIMARY
 | PRIOR
@@ -17458,6 +17660,7 @@ bare_label_keywords:
 | REPLACE
 | REPLICATION
 | RESET
+| RESOURCE
 | RESTART
 | RESTORE
 | RESTRICT
@@ -17607,6 +17810,7 @@ bare_label_keywords:
 | VARBIT
 | VARCHAR
 | VARIADIC
+| VERIFY
 | VERIFY_BACKUP_TABLE_DATA
 | VIEW
 | VIEWACTIVITY
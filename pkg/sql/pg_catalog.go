@@ -21,6 +21,7 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
@@ -2364,14 +2365,40 @@ var (
 	_ = proArgModeTable
 )
 
+// pgCatalogPreparedXactsTable implements the pg_prepared_xacts table.
+// The transaction field is always NULL, since transaction identifiers in
+// cockroach are UUIDs rather than integers.
 var pgCatalogPreparedXactsTable = virtualSchemaTable{
-	comment: `prepared transactions (empty - feature does not exist)
+	comment: `prepared transactions
 https://www.postgresql.org/docs/9.6/view-pg-prepared-xacts.html`,
 	schema: vtable.PGCatalogPreparedXacts,
 	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V23_2_PreparedTransactionsTable) {
+			return nil
+		}
+		rows, err := p.InternalSQLTxn().QueryBufferedEx(
+			ctx,
+			"select-prepared-txns",
+			p.Txn(),
+			sessiondata.NodeUserSessionDataOverride,
+			`SELECT global_id, prepared, owner, database FROM system.prepared_transactions`,
+		)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := addRow(
+				tree.DNull, // transaction
+				row[0],     // gid
+				row[1],     // prepared
+				tree.NewDName(string(tree.MustBeDString(row[2]))), // owner
+				tree.NewDName(string(tree.MustBeDString(row[3]))), // database
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
-	unimplemented: true,
 }
 
 // pgCatalogPreparedStatementsTable implements the pg_prepared_statements table.
@@ -188,12 +188,18 @@ func (c *conn) handleAuthentication(
 		return connClose, c.sendError(ctx, err)
 	}
 
+	// Remember the resource group requested by the client, if any, before the
+	// role's defaults are added below.
+	clientResourceGroup, hasClientResourceGroup := c.sessionArgs.SessionDefaults["resource_group"]
+
 	// Add all the defaults to this session's defaults. If there is an
 	// error (e.g., a setting that no longer exists, or bad input),
 	// log a warning instead of preventing login.
 	// The defaultSettings array is ordered by precedence. This means that if
 	// SessionDefaults already has an entry for a given setting name, then
 	// it should not be replaced.
+	var roleResourceGroup string
+	var hasRoleResourceGroup bool
 	for _, settingEntry := range defaultSettings {
 		for _, setting := range settingEntry.Settings {
 			keyVal := strings.SplitN(setting, "=", 2)
@@ -205,12 +211,23 @@ func (c *conn) handleAuthentication(
 				log.Ops.Warningf(ctx, "%s has invalid default setting: %v", dbUser, err)
 				continue
 			}
+			if keyVal[0] == "resource_group" && !hasRoleResourceGroup {
+				roleResourceGroup, hasRoleResourceGroup = keyVal[1], true
+			}
 			if _, ok := c.sessionArgs.SessionDefaults[keyVal[0]]; !ok {
 				c.sessionArgs.SessionDefaults[keyVal[0]] = keyVal[1]
 			}
 		}
 	}
 
+	// Only admins can select a resource group other than the role's default
+	// one when connecting.
+	if hasClientResourceGroup &&
+		!sql.CanSelectResourceGroup(clientResourceGroup, roleResourceGroup, isSuperuser) {
+		ac.LogAuthFailed(ctx, eventpb.AuthFailReason_UNKNOWN, nil)
+		return connClose, c.sendError(ctx, sql.NewResourceGroupPrivilegeError(clientResourceGroup))
+	}
+
 	// Check replication privilege.
 	if c.sessionArgs.SessionDefaults["replication"] != "" {
 		m, err := sql.ReplicationModeFromString(c.sessionArgs.SessionDefaults["replication"])
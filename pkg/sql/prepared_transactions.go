@@ -0,0 +1,219 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+// prepareSQLTransactionInternal executes a PREPARE TRANSACTION statement. The
+// KV transaction is moved into the PREPARED state, which retains all of its
+// locks until a later COMMIT PREPARED or ROLLBACK PREPARED statement resolves
+// it, possibly from a different session. The mapping from the global
+// transaction identifier to the KV transaction is recorded in
+// system.prepared_transactions.
+func (ex *connExecutor) prepareSQLTransactionInternal(
+	ctx context.Context, s *tree.PrepareTransaction,
+) error {
+	ctx, sp := tracing.EnsureChildSpan(ctx, ex.server.cfg.AmbientCtx.Tracer, "prepare sql txn")
+	defer sp.Finish()
+
+	if !ex.server.cfg.Settings.Version.IsActive(ctx, clusterversion.V23_2_PreparedTransactionsTable) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"PREPARE TRANSACTION requires the cluster upgrade to be finalized")
+	}
+
+	if err := ex.extraTxnState.sqlCursors.closeAll(true /* errorOnWithHold */); err != nil {
+		return err
+	}
+
+	ex.extraTxnState.prepStmtsNamespace.closeAllPortals(ctx, &ex.extraTxnState.prepStmtsNamespaceMemAcc)
+
+	// Schema changes and jobs are finalized by the SQL layer as part of the
+	// commit, which a prepared transaction cannot coordinate since it may be
+	// committed from a different session.
+	if ex.extraTxnState.descCollection.HasUncommittedDescriptors() ||
+		ex.extraTxnState.jobs.hasAnyToCreate() ||
+		len(ex.extraTxnState.schemaChangerState.state.Targets) > 0 {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"cannot PREPARE a transaction that has performed schema changes")
+	}
+
+	// See commitSQLTransactionInternal for why the transaction is stepped.
+	txn := ex.state.mu.txn
+	prevSteppingMode := txn.ConfigureStepping(ctx, kv.SteppingEnabled)
+	if prevSteppingMode == kv.SteppingEnabled {
+		if err := txn.Step(ctx); err != nil {
+			return err
+		}
+	} else {
+		txn.ConfigureStepping(ctx, prevSteppingMode)
+	}
+
+	// The global transaction identifier is recorded before the transaction is
+	// prepared, so that a prepared transaction can always be found through
+	// system.prepared_transactions, even if this gateway fails right after
+	// preparing it. The insert also atomically claims the identifier. If the
+	// gateway fails before the transaction is prepared, the row refers to a
+	// transaction that is not prepared. ROLLBACK PREPARED aborts such a
+	// transaction and removes the row.
+	//
+	// A transaction that has not acquired a lock yet does not have a key and
+	// has no record to resolve through KV. Its transaction_key is left NULL,
+	// and the row is kept only so that the transaction is visible to
+	// COMMIT/ROLLBACK PREPARED and pg_prepared_xacts.
+	gid := s.Transaction.RawString()
+	meta := txn.TxnMeta()
+	ie := ex.server.cfg.InternalDB.Executor()
+	if _, err := ie.ExecEx(
+		ctx, "insert-prepared-txn", nil, /* txn */
+		sessiondata.NodeUserSessionDataOverride,
+		`INSERT INTO system.prepared_transactions
+           (global_id, transaction_id, transaction_key, owner, database)
+         VALUES ($1, $2, $3, $4, $5)`,
+		gid, tree.NewDUuid(tree.DUuid{UUID: meta.ID}), []byte(meta.Key),
+		ex.sessionData().User().Normalized(), ex.sessionData().Database,
+	); err != nil {
+		if pgerror.GetPGCode(err) == pgcode.UniqueViolation {
+			return errPreparedTxnInUse(gid)
+		}
+		return err
+	}
+
+	if _, err := txn.Prepare(ctx); err != nil {
+		// The preparation may have failed ambiguously, so the transaction is
+		// rolled back through KV before its identifier is released. If either
+		// step fails, a later ROLLBACK PREPARED completes the cleanup.
+		if cleanupErr := func() error {
+			if meta.Key != nil {
+				if err := ex.server.cfg.DB.RollbackPrepared(ctx, meta); err != nil {
+					return err
+				}
+			}
+			_, err := ie.ExecEx(
+				ctx, "delete-prepared-txn", nil, /* txn */
+				sessiondata.NodeUserSessionDataOverride,
+				`DELETE FROM system.prepared_transactions WHERE global_id = $1`, gid,
+			)
+			return err
+		}(); cleanupErr != nil {
+			log.Warningf(ctx, "failed to clean up prepared transaction %q: %v", gid, cleanupErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func errPreparedTxnInUse(gid string) error {
+	return pgerror.Newf(pgcode.DuplicateObject,
+		"transaction identifier %q is already in use", gid)
+}
+
+// endPreparedTxnNode implements COMMIT PREPARED and ROLLBACK PREPARED.
+type endPreparedTxnNode struct {
+	gid    string
+	commit bool
+}
+
+// CommitPrepared commits a transaction previously prepared with PREPARE
+// TRANSACTION.
+// Privileges: owner of the prepared transaction or admin.
+func (p *planner) CommitPrepared(ctx context.Context, n *tree.CommitPrepared) (planNode, error) {
+	return p.endPreparedTxn(n.Transaction.RawString(), true /* commit */, "COMMIT PREPARED")
+}
+
+// RollbackPrepared rolls back a transaction previously prepared with PREPARE
+// TRANSACTION. It also cleans up a transaction whose preparation did not
+// complete, for example because its gateway failed.
+// Privileges: owner of the prepared transaction or admin.
+func (p *planner) RollbackPrepared(
+	ctx context.Context, n *tree.RollbackPrepared,
+) (planNode, error) {
+	return p.endPreparedTxn(n.Transaction.RawString(), false /* commit */, "ROLLBACK PREPARED")
+}
+
+func (p *planner) endPreparedTxn(gid string, commit bool, op string) (planNode, error) {
+	if !p.extendedEvalCtx.TxnImplicit {
+		return nil, pgerror.Newf(pgcode.ActiveSQLTransaction,
+			"%s cannot run inside a transaction block", op)
+	}
+	return &endPreparedTxnNode{gid: gid, commit: commit}, nil
+}
+
+func (n *endPreparedTxnNode) startExec(params runParams) error {
+	p := params.p
+	row, err := p.InternalSQLTxn().QueryRowEx(
+		params.ctx, "get-prepared-txn", p.txn,
+		sessiondata.NodeUserSessionDataOverride,
+		`SELECT transaction_id, transaction_key, owner
+           FROM system.prepared_transactions
+          WHERE global_id = $1`,
+		n.gid,
+	)
+	if err != nil {
+		return err
+	}
+	if row == nil {
+		return pgerror.Newf(pgcode.UndefinedObject,
+			"prepared transaction with identifier %q does not exist", n.gid)
+	}
+
+	if owner := string(tree.MustBeDString(row[2])); owner != p.User().Normalized() {
+		isAdmin, err := p.HasAdminRole(params.ctx)
+		if err != nil {
+			return err
+		}
+		if !isAdmin {
+			return pgerror.Newf(pgcode.InsufficientPrivilege,
+				"permission denied to finish prepared transaction %q", n.gid)
+		}
+	}
+
+	if row[1] != tree.DNull {
+		meta := enginepb.TxnMeta{
+			ID:  tree.MustBeDUuid(row[0]).UUID,
+			Key: roachpb.Key(tree.MustBeDBytes(row[1])),
+		}
+		db := p.ExecCfg().DB
+		if n.commit {
+			err = db.CommitPrepared(params.ctx, meta)
+		} else {
+			err = db.RollbackPrepared(params.ctx, meta)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "finishing prepared transaction %q", n.gid)
+		}
+	}
+
+	_, err = p.InternalSQLTxn().ExecEx(
+		params.ctx, "delete-prepared-txn", p.txn,
+		sessiondata.NodeUserSessionDataOverride,
+		`DELETE FROM system.prepared_transactions WHERE global_id = $1`,
+		n.gid,
+	)
+	return err
+}
+
+func (n *endPreparedTxnNode) Next(_ runParams) (bool, error) { return false, nil }
+func (n *endPreparedTxnNode) Values() tree.Datums            { return tree.Datums{} }
+func (n *endPreparedTxnNode) Close(_ context.Context)        {}
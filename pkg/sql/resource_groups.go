@@ -0,0 +1,452 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// resourceGroupRefreshInterval is the interval at which a ResourceGroupCache
+// re-reads system.resource_groups, to pick up the changes made through other
+// nodes.
+const resourceGroupRefreshInterval = 10 * time.Second
+
+// defaultResourceGroupName is the name by which the default resource group can
+// be referred to. It cannot be used for a group created with CREATE RESOURCE
+// GROUP.
+const defaultResourceGroupName = "default"
+
+// Options of CREATE RESOURCE GROUP and ALTER RESOURCE GROUP.
+const (
+	resourceGroupOptionCPUShare       = "cpu_share"
+	resourceGroupOptionIOShare        = "io_share"
+	resourceGroupOptionMaxConcurrency = "max_concurrency"
+)
+
+// ResourceGroupCache is a node-local cache of the resource groups defined in
+// system.resource_groups. The definition of a session's resource group is
+// stamped onto the session's transactions, and is then carried along with the
+// transactions' requests so that the work they do is admitted under the group
+// on every node.
+type ResourceGroupCache struct {
+	settings *cluster.Settings
+	stopper  *stop.Stopper
+	db       isql.DB
+
+	mu struct {
+		syncutil.RWMutex
+		groups map[string]admissionpb.ResourceGroup
+	}
+}
+
+// NewResourceGroupCache constructs a new ResourceGroupCache.
+func NewResourceGroupCache(
+	settings *cluster.Settings, stopper *stop.Stopper, db isql.DB,
+) *ResourceGroupCache {
+	c := &ResourceGroupCache{settings: settings, stopper: stopper, db: db}
+	c.mu.groups = map[string]admissionpb.ResourceGroup{}
+	return c
+}
+
+// Start starts the periodic refresh of the cache.
+func (c *ResourceGroupCache) Start(ctx context.Context) {
+	_ = c.stopper.RunAsyncTask(ctx, "resource-group-cache", func(ctx context.Context) {
+		ctx, cancel := c.stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			if err := c.refresh(ctx); err != nil && ctx.Err() == nil {
+				log.Warningf(ctx, "failed to refresh resource groups: %v", err)
+			}
+			timer.Reset(resourceGroupRefreshInterval)
+			select {
+			case <-timer.C:
+				timer.Read = true
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+// refresh replaces the contents of the cache with the contents of
+// system.resource_groups.
+func (c *ResourceGroupCache) refresh(ctx context.Context) error {
+	if !c.settings.Version.IsActive(ctx, clusterversion.V23_2_ResourceGroupsTable) {
+		return nil
+	}
+	rows, err := c.db.Executor().QueryBufferedEx(
+		ctx, "read-resource-groups", nil, /* txn */
+		sessiondata.NodeUserSessionDataOverride,
+		`SELECT name, cpu_share, io_share, max_concurrency FROM system.resource_groups`,
+	)
+	if err != nil {
+		return err
+	}
+	groups := make(map[string]admissionpb.ResourceGroup, len(rows))
+	for _, row := range rows {
+		g := admissionpb.ResourceGroup{
+			Name:           string(tree.MustBeDString(row[0])),
+			CPUShare:       uint32(tree.MustBeDInt(row[1])),
+			IOShare:        uint32(tree.MustBeDInt(row[2])),
+			MaxConcurrency: int32(tree.MustBeDInt(row[3])),
+		}
+		groups[g.Name] = g
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.groups = groups
+	return nil
+}
+
+// Get returns the definition of the named resource group. The default resource
+// group is returned if the name is empty or if the group doesn't exist.
+func (c *ResourceGroupCache) Get(name string) admissionpb.ResourceGroup {
+	if c == nil || name == "" || name == defaultResourceGroupName {
+		return admissionpb.ResourceGroup{}
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.mu.groups[name]
+}
+
+// set adds or replaces the definition of a resource group.
+func (c *ResourceGroupCache) set(g admissionpb.ResourceGroup) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.groups[g.Name] = g
+}
+
+// remove removes the definition of a resource group.
+func (c *ResourceGroupCache) remove(name string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.mu.groups, name)
+}
+
+// CanSelectResourceGroup returns whether a session may select the named
+// resource group. Admins may select any group; other users are limited to the
+// default group and to sessionDefault, the group the session starts with,
+// which is set per role with ALTER ROLE ... SET resource_group.
+func CanSelectResourceGroup(name, sessionDefault string, isAdmin bool) bool {
+	return isAdmin || name == "" || name == defaultResourceGroupName || name == sessionDefault
+}
+
+// NewResourceGroupPrivilegeError returns the error reported when a session
+// selects a resource group that CanSelectResourceGroup doesn't allow.
+func NewResourceGroupPrivilegeError(name string) error {
+	return pgerror.Newf(pgcode.InsufficientPrivilege,
+		"only users with the admin role are allowed to select resource group %q", name)
+}
+
+// setResourceGroup implements SET resource_group.
+func (p *planner) setResourceGroup(ctx context.Context, local bool, name string) error {
+	sessionDefault := p.sessionDataMutatorIterator.defaults[`resource_group`]
+	if !CanSelectResourceGroup(name, sessionDefault, false /* isAdmin */) {
+		isAdmin, err := p.HasAdminRole(ctx)
+		if err != nil {
+			return err
+		}
+		if !isAdmin {
+			return NewResourceGroupPrivilegeError(name)
+		}
+	}
+	return p.applyOnSessionDataMutators(ctx, local, func(m sessionDataMutator) error {
+		m.SetResourceGroup(name)
+		return nil
+	})
+}
+
+type createResourceGroupNode struct {
+	n     *tree.CreateResourceGroup
+	group admissionpb.ResourceGroup
+}
+
+// CreateResourceGroup creates a resource group.
+// Privileges: MODIFYCLUSTERSETTING.
+func (p *planner) CreateResourceGroup(
+	ctx context.Context, n *tree.CreateResourceGroup,
+) (planNode, error) {
+	if err := p.checkResourceGroupDDL(ctx, "CREATE RESOURCE GROUP", n.Name); err != nil {
+		return nil, err
+	}
+	if !admissionpb.IsValidResourceGroupName(string(n.Name)) {
+		return nil, errors.WithHintf(
+			pgerror.Newf(pgcode.InvalidName, "resource group name %q is invalid", string(n.Name)),
+			"resource group names consist of at most %d lower case letters, digits and underscores",
+			admissionpb.MaxResourceGroupNameLength,
+		)
+	}
+	opts, err := p.evalResourceGroupOptions(ctx, "CREATE RESOURCE GROUP", n.Options)
+	if err != nil {
+		return nil, err
+	}
+	group := admissionpb.ResourceGroup{Name: string(n.Name)}
+	opts.apply(&group)
+	return &createResourceGroupNode{n: n, group: group}, nil
+}
+
+func (n *createResourceGroupNode) startExec(params runParams) error {
+	p := params.p
+	row, err := p.InternalSQLTxn().QueryRowEx(
+		params.ctx, "count-resource-groups", p.txn,
+		sessiondata.NodeUserSessionDataOverride,
+		`SELECT count(*) FROM system.resource_groups WHERE name != $1`, n.group.Name,
+	)
+	if err != nil {
+		return err
+	}
+	if int(tree.MustBeDInt(row[0])) >= admissionpb.MaxResourceGroups {
+		return pgerror.Newf(pgcode.ConfigurationLimitExceeded,
+			"cannot create more than %d resource groups", admissionpb.MaxResourceGroups)
+	}
+	rowsAffected, err := p.InternalSQLTxn().ExecEx(
+		params.ctx, "create-resource-group", p.txn,
+		sessiondata.NodeUserSessionDataOverride,
+		`INSERT INTO system.resource_groups (name, cpu_share, io_share, max_concurrency)
+         VALUES ($1, $2, $3, $4) ON CONFLICT (name) DO NOTHING`,
+		n.group.Name, n.group.CPUShare, n.group.IOShare, n.group.MaxConcurrency,
+	)
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		if n.n.IfNotExists {
+			return nil
+		}
+		return pgerror.Newf(pgcode.DuplicateObject,
+			"resource group %q already exists", n.group.Name)
+	}
+	p.addResourceGroupCommitTrigger(n.group, false /* remove */)
+	return nil
+}
+
+func (n *createResourceGroupNode) Next(_ runParams) (bool, error) { return false, nil }
+func (n *createResourceGroupNode) Values() tree.Datums            { return tree.Datums{} }
+func (n *createResourceGroupNode) Close(_ context.Context)        {}
+
+type alterResourceGroupNode struct {
+	n    *tree.AlterResourceGroup
+	opts resourceGroupOptions
+}
+
+// AlterResourceGroup changes the options of a resource group.
+// Privileges: MODIFYCLUSTERSETTING.
+func (p *planner) AlterResourceGroup(
+	ctx context.Context, n *tree.AlterResourceGroup,
+) (planNode, error) {
+	if err := p.checkResourceGroupDDL(ctx, "ALTER RESOURCE GROUP", n.Name); err != nil {
+		return nil, err
+	}
+	opts, err := p.evalResourceGroupOptions(ctx, "ALTER RESOURCE GROUP", n.Options)
+	if err != nil {
+		return nil, err
+	}
+	return &alterResourceGroupNode{n: n, opts: opts}, nil
+}
+
+func (n *alterResourceGroupNode) startExec(params runParams) error {
+	p := params.p
+	row, err := p.InternalSQLTxn().QueryRowEx(
+		params.ctx, "get-resource-group", p.txn,
+		sessiondata.NodeUserSessionDataOverride,
+		`SELECT cpu_share, io_share, max_concurrency FROM system.resource_groups
+          WHERE name = $1 FOR UPDATE`,
+		string(n.n.Name),
+	)
+	if err != nil {
+		return err
+	}
+	if row == nil {
+		if n.n.IfExists {
+			return nil
+		}
+		return pgerror.Newf(pgcode.UndefinedObject,
+			"resource group %q does not exist", string(n.n.Name))
+	}
+	group := admissionpb.ResourceGroup{
+		Name:           string(n.n.Name),
+		CPUShare:       uint32(tree.MustBeDInt(row[0])),
+		IOShare:        uint32(tree.MustBeDInt(row[1])),
+		MaxConcurrency: int32(tree.MustBeDInt(row[2])),
+	}
+	n.opts.apply(&group)
+	if _, err := p.InternalSQLTxn().ExecEx(
+		params.ctx, "alter-resource-group", p.txn,
+		sessiondata.NodeUserSessionDataOverride,
+		`UPDATE system.resource_groups
+            SET cpu_share = $2, io_share = $3, max_concurrency = $4
+          WHERE name = $1`,
+		group.Name, group.CPUShare, group.IOShare, group.MaxConcurrency,
+	); err != nil {
+		return err
+	}
+	p.addResourceGroupCommitTrigger(group, false /* remove */)
+	return nil
+}
+
+func (n *alterResourceGroupNode) Next(_ runParams) (bool, error) { return false, nil }
+func (n *alterResourceGroupNode) Values() tree.Datums            { return tree.Datums{} }
+func (n *alterResourceGroupNode) Close(_ context.Context)        {}
+
+type dropResourceGroupNode struct {
+	n *tree.DropResourceGroup
+}
+
+// DropResourceGroup drops a resource group. The work of sessions that still
+// refer to the group is admitted under the default resource group.
+// Privileges: MODIFYCLUSTERSETTING.
+func (p *planner) DropResourceGroup(
+	ctx context.Context, n *tree.DropResourceGroup,
+) (planNode, error) {
+	if err := p.checkResourceGroupDDL(ctx, "DROP RESOURCE GROUP", n.Name); err != nil {
+		return nil, err
+	}
+	return &dropResourceGroupNode{n: n}, nil
+}
+
+func (n *dropResourceGroupNode) startExec(params runParams) error {
+	p := params.p
+	rowsAffected, err := p.InternalSQLTxn().ExecEx(
+		params.ctx, "drop-resource-group", p.txn,
+		sessiondata.NodeUserSessionDataOverride,
+		`DELETE FROM system.resource_groups WHERE name = $1`,
+		string(n.n.Name),
+	)
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		if n.n.IfExists {
+			return nil
+		}
+		return pgerror.Newf(pgcode.UndefinedObject,
+			"resource group %q does not exist", string(n.n.Name))
+	}
+	p.addResourceGroupCommitTrigger(admissionpb.ResourceGroup{Name: string(n.n.Name)}, true /* remove */)
+	return nil
+}
+
+func (n *dropResourceGroupNode) Next(_ runParams) (bool, error) { return false, nil }
+func (n *dropResourceGroupNode) Values() tree.Datums            { return tree.Datums{} }
+func (n *dropResourceGroupNode) Close(_ context.Context)        {}
+
+// checkResourceGroupDDL performs the checks common to all resource group
+// statements.
+func (p *planner) checkResourceGroupDDL(ctx context.Context, op string, name tree.Name) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V23_2_ResourceGroupsTable) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s requires the cluster upgrade to be finalized", op)
+	}
+	if err := p.CheckGlobalPrivilegeOrRoleOption(ctx, privilege.MODIFYCLUSTERSETTING); err != nil {
+		return err
+	}
+	if name == "" || name == defaultResourceGroupName {
+		return pgerror.Newf(pgcode.ReservedName,
+			"resource group name %q is reserved", string(name))
+	}
+	return nil
+}
+
+// resourceGroupOptions holds the evaluated options of a CREATE or ALTER
+// RESOURCE GROUP statement. Options that weren't specified are nil.
+type resourceGroupOptions struct {
+	cpuShare, ioShare, maxConcurrency *int64
+}
+
+// evalResourceGroupOptions evaluates and validates the options of a CREATE or
+// ALTER RESOURCE GROUP statement.
+func (p *planner) evalResourceGroupOptions(
+	ctx context.Context, op string, opts tree.KVOptions,
+) (resourceGroupOptions, error) {
+	var res resourceGroupOptions
+	exprEval := p.ExprEvaluator(op)
+	for _, opt := range opts {
+		k := string(opt.Key)
+		var dst **int64
+		var lo, hi int64
+		switch k {
+		case resourceGroupOptionCPUShare:
+			dst, lo, hi = &res.cpuShare, 1, admissionpb.MaxResourceGroupShare
+		case resourceGroupOptionIOShare:
+			dst, lo, hi = &res.ioShare, 1, admissionpb.MaxResourceGroupShare
+		case resourceGroupOptionMaxConcurrency:
+			dst, lo, hi = &res.maxConcurrency, 0, admissionpb.MaxResourceGroupConcurrency
+		default:
+			return resourceGroupOptions{}, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid option %q", k)
+		}
+		if opt.Value == nil {
+			return resourceGroupOptions{}, pgerror.Newf(pgcode.InvalidParameterValue,
+				"option %q requires a value", k)
+		}
+		v, err := exprEval.Int(ctx, opt.Value)
+		if err != nil {
+			return resourceGroupOptions{}, err
+		}
+		if v < lo || v > hi {
+			return resourceGroupOptions{}, pgerror.Newf(pgcode.InvalidParameterValue,
+				"%s must be between %d and %d", k, lo, hi)
+		}
+		*dst = &v
+	}
+	return res, nil
+}
+
+// apply sets the specified options on the given group.
+func (o resourceGroupOptions) apply(group *admissionpb.ResourceGroup) {
+	if o.cpuShare != nil {
+		group.CPUShare = uint32(*o.cpuShare)
+	}
+	if o.ioShare != nil {
+		group.IOShare = uint32(*o.ioShare)
+	}
+	if o.maxConcurrency != nil {
+		group.MaxConcurrency = int32(*o.maxConcurrency)
+	}
+}
+
+// addResourceGroupCommitTrigger updates the node's ResourceGroupCache once the
+// current transaction commits, so that the change takes effect on this node
+// without waiting for the next refresh.
+func (p *planner) addResourceGroupCommitTrigger(group admissionpb.ResourceGroup, remove bool) {
+	cache := p.ExecCfg().ResourceGroupCache
+	p.txn.AddCommitTrigger(func(context.Context) {
+		if remove {
+			cache.remove(group.Name)
+		} else {
+			cache.set(group)
+		}
+	})
+}
@@ -447,11 +447,15 @@ func (rf *Fetcher) Init(ctx context.Context, args FetcherInitArgs) error {
 			batchRequestsIssued:        &batchRequestsIssued,
 		}
 		if args.Txn != nil {
-			fetcherArgs.sendFn = makeTxnKVFetcherDefaultSendFunc(args.Txn, &batchRequestsIssued)
-			fetcherArgs.admission.requestHeader = args.Txn.AdmissionHeader()
-			fetcherArgs.admission.responseQ = args.Txn.DB().SQLKVResponseAdmissionQ
-			fetcherArgs.admission.pacerFactory = args.Txn.DB().AdmissionPacerFactory
-			fetcherArgs.admission.settingsValues = args.Txn.DB().SettingsValues
+			txn, err := GetKVTxn(ctx, args.Txn, args.Spec)
+			if err != nil {
+				return err
+			}
+			fetcherArgs.sendFn = makeTxnKVFetcherDefaultSendFunc(txn, &batchRequestsIssued)
+			fetcherArgs.admission.requestHeader = txn.AdmissionHeader()
+			fetcherArgs.admission.responseQ = txn.DB().SQLKVResponseAdmissionQ
+			fetcherArgs.admission.pacerFactory = txn.DB().AdmissionPacerFactory
+			fetcherArgs.admission.settingsValues = txn.DB().SettingsValues
 		}
 		rf.kvFetcher = newKVFetcher(newTxnKVFetcherInternal(fetcherArgs))
 	}
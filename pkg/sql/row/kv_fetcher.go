@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/rowinfra"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
@@ -107,6 +108,26 @@ func newTxnKVFetcher(
 	return newTxnKVFetcherInternal(fetcherArgs)
 }
 
+// GetKVTxn returns the transaction that should be used to read the KVs
+// described by the given fetch spec. If the rows are stored in another
+// tenant's keyspace, a new transaction that reads at the fixed timestamp as of
+// which that data is consistent is returned; otherwise, txn is returned.
+func GetKVTxn(
+	ctx context.Context, txn *kv.Txn, spec *fetchpb.IndexFetchSpec,
+) (*kv.Txn, error) {
+	if txn == nil || spec == nil || spec.External == nil {
+		return txn, nil
+	}
+	externalTxn := kv.NewTxnWithAdmissionControl(
+		ctx, txn.DB(), 0, /* gatewayNodeID */
+		kvpb.AdmissionHeader_FROM_SQL, admissionpb.WorkPriority(txn.AdmissionHeader().Priority),
+	)
+	if err := externalTxn.SetFixedTimestamp(ctx, spec.External.AsOf); err != nil {
+		return nil, err
+	}
+	return externalTxn, nil
+}
+
 // NewDirectKVBatchFetcher creates a new KVBatchFetcher that uses the
 // COL_BATCH_RESPONSE scan format for Scans (or ReverseScans, if reverse is
 // true).
@@ -48,10 +48,23 @@ func InitIndexFetchSpec(
 		GeoConfig:           index.GetGeoConfig(),
 	}
 
+	// If the rows of the table are stored in another tenant's keyspace, the
+	// keys are encoded with that tenant's prefix and table ID.
+	keyTableID := s.TableID
+	if ext := table.ExternalRowData(); ext != nil {
+		s.External = &fetchpb.IndexFetchSpec_ExternalRowData{
+			TenantID: ext.TenantID,
+			TableID:  ext.TableID,
+			AsOf:     ext.AsOf,
+		}
+		codec = keys.MakeSQLCodec(ext.TenantID)
+		keyTableID = ext.TableID
+	}
+
 	maxKeysPerRow := table.IndexKeysPerRow(index)
 	s.MaxKeysPerRow = uint32(maxKeysPerRow)
 	s.KeyPrefixLength = uint32(len(codec.TenantPrefix()) +
-		encoding.EncodedLengthUvarintAscending(uint64(s.TableID)) +
+		encoding.EncodedLengthUvarintAscending(uint64(keyTableID)) +
 		encoding.EncodedLengthUvarintAscending(uint64(index.GetID())))
 
 	s.FamilyDefaultColumns = table.FamilyDefaultColumns()
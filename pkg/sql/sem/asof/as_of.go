@@ -146,8 +146,10 @@ func Eval(
 	// All non-function expressions must be const and must TypeCheck into a
 	// string.
 	var te tree.TypedExpr
+	fnType := funcTypeInvalid
 	if asOfFuncExpr, ok := asOf.Expr.(*tree.FuncExpr); ok {
-		switch resolveFuncType(ctx, asOf, semaCtx.SearchPath) {
+		fnType = resolveFuncType(ctx, asOf, semaCtx.SearchPath)
+		switch fnType {
 		case funcTypeFollowerRead:
 		case funcTypeBoundedStaleness:
 			if !o.allowBoundedStaleness {
@@ -207,6 +209,15 @@ func Eval(
 	if err != nil {
 		return eval.AsOfSystemTime{}, errors.Wrap(err, "AS OF SYSTEM TIME")
 	}
+	// Follower reads and bounded staleness reads pick a timestamp on behalf of
+	// the user. Make sure that such reads observe all writes that the session
+	// was told to causally depend on through read_after_timestamp. Explicit
+	// timestamps are left alone.
+	if fnType != funcTypeInvalid {
+		if sd := evalCtx.SessionData(); sd != nil {
+			ret.Timestamp.Forward(sd.ReadAfterTimestamp)
+		}
+	}
 	return ret, nil
 }
 
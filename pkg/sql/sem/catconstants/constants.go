@@ -98,6 +98,8 @@ const (
 	SpanStatsSamples                       SystemTableName = "span_stats_samples"
 	SpanStatsTenantBoundaries              SystemTableName = "span_stats_tenant_boundaries"
 	RegionalLiveness                       SystemTableName = "region_liveness"
+	PreparedTransactionsTableName          SystemTableName = "prepared_transactions"
+	ResourceGroupsTableName                SystemTableName = "resource_groups"
 )
 
 // Oid for virtual database and table.
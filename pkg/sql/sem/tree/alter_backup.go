@@ -70,6 +70,55 @@ func (node *AlterBackupKMS) Format(ctx *FmtCtx) {
 	ctx.FormatNode(&node.KMSInfo.OldKMSURI)
 }
 
+func (node *AlterBackupCompact) alterBackupCmd() {}
+
+var _ AlterBackupCmd = &AlterBackupCompact{}
+
+// AlterBackupCompact represents an alter_backup_cmd which merges incremental
+// layers of a backup into a single layer.
+type AlterBackupCompact struct {
+	Options KVOptions
+}
+
+const (
+	// AlterBackupCompactOptionDetached is the COMPACT option which requests
+	// that the ID of the job be returned immediately.
+	AlterBackupCompactOptionDetached = "detached"
+	// AlterBackupCompactOptionRevisionHistory is the COMPACT option which
+	// requests that the revision history of the compacted layers be preserved.
+	AlterBackupCompactOptionRevisionHistory = "revision_history"
+	// AlterBackupCompactOptionStartTime is the COMPACT option which sets the
+	// earliest start time of the layers to compact.
+	AlterBackupCompactOptionStartTime = "start_time"
+	// AlterBackupCompactOptionEndTime is the COMPACT option which sets the
+	// latest end time of the layers to compact.
+	AlterBackupCompactOptionEndTime = "end_time"
+	// AlterBackupCompactOptionEncryptionPassphrase is the passphrase with which
+	// the backup was encrypted.
+	AlterBackupCompactOptionEncryptionPassphrase = "encryption_passphrase"
+	// AlterBackupCompactOptionKMS is the URI of a KMS with which the backup was
+	// encrypted.
+	AlterBackupCompactOptionKMS = "kms"
+	// AlterBackupCompactOptionIncrementalLocation is the location of the
+	// incremental layers of the backup, if they are not stored in the
+	// collection.
+	AlterBackupCompactOptionIncrementalLocation = "incremental_location"
+	// AlterBackupCompactOptionDeleteCompactedLayers is the COMPACT option which
+	// requests that the compacted layers be deleted once the compacted layer
+	// has been written and no job may still be reading them.
+	AlterBackupCompactOptionDeleteCompactedLayers = "delete_compacted_layers"
+)
+
+// Format implements the NodeFormatter interface.
+func (node *AlterBackupCompact) Format(ctx *FmtCtx) {
+	ctx.WriteString(" COMPACT")
+	if node.Options != nil {
+		ctx.WriteString(" WITH OPTIONS (")
+		ctx.FormatNode(&node.Options)
+		ctx.WriteString(")")
+	}
+}
+
 // BackupKMS represents possible options used when altering a backup KMS
 type BackupKMS struct {
 	NewKMSURI StringOrPlaceholderOptList
@@ -36,8 +36,42 @@ const (
 	// SystemUsers coverage indicates that only the system.users
 	// table will be restored from the backup.
 	SystemUsers
+
+	// SystemSettings coverage indicates that only the cluster settings in
+	// system.settings are backed up or restored.
+	SystemSettings
+
+	// SystemZoneConfigs coverage indicates that only the zone configurations
+	// in system.zones are backed up or restored.
+	SystemZoneConfigs
 )
 
+// IsSystemTarget returns whether the coverage is that of a backup or restore
+// of a part of the system configuration of the cluster, such as BACKUP SYSTEM
+// USERS.
+func (c DescriptorCoverage) IsSystemTarget() bool {
+	switch c {
+	case SystemUsers, SystemSettings, SystemZoneConfigs:
+		return true
+	}
+	return false
+}
+
+// SystemTargetName returns the name of the part of the system configuration
+// that a system target coverage refers to, as it appears after SYSTEM in
+// BACKUP and RESTORE statements.
+func (c DescriptorCoverage) SystemTargetName() string {
+	switch c {
+	case SystemUsers:
+		return "USERS"
+	case SystemSettings:
+		return "SETTINGS"
+	case SystemZoneConfigs:
+		return "ZONE CONFIGURATIONS"
+	}
+	return ""
+}
+
 // BackupOptions describes options for the BACKUP execution.
 type BackupOptions struct {
 	CaptureRevisionHistory          Expr
@@ -56,6 +90,11 @@ var _ NodeFormatter = &BackupOptions{}
 type Backup struct {
 	Targets *BackupTargetList
 
+	// SystemTarget is set for backups of a part of the system configuration of
+	// the cluster, e.g. BACKUP SYSTEM USERS, to one of the system target
+	// coverages. Targets is nil in such backups.
+	SystemTarget DescriptorCoverage
+
 	// To is set to the root directory of the backup (called the <destination> in
 	// the docs).
 	To StringOrPlaceholderOptList
@@ -86,7 +125,11 @@ var _ Statement = &Backup{}
 // Format implements the NodeFormatter interface.
 func (node *Backup) Format(ctx *FmtCtx) {
 	ctx.WriteString("BACKUP ")
-	if node.Targets != nil {
+	if node.SystemTarget.IsSystemTarget() {
+		ctx.WriteString("SYSTEM ")
+		ctx.WriteString(node.SystemTarget.SystemTargetName())
+		ctx.WriteString(" ")
+	} else if node.Targets != nil {
 		ctx.FormatNode(node.Targets)
 		ctx.WriteString(" ")
 	}
@@ -120,6 +163,9 @@ func (node *Backup) Format(ctx *FmtCtx) {
 
 // Coverage return the coverage (all vs requested).
 func (node Backup) Coverage() DescriptorCoverage {
+	if node.SystemTarget.IsSystemTarget() {
+		return node.SystemTarget
+	}
 	if node.Targets == nil {
 		return AllDescriptors
 	}
@@ -150,6 +196,12 @@ type RestoreOptions struct {
 	ExecutionLocality                Expr
 	ExperimentalOnline               bool
 	RemoveRegions                    bool
+	// IntoTable, if set, is the name under which the single table being
+	// restored is created.
+	IntoTable Expr
+	// Filter, if set, is a predicate on the leading primary key column of the
+	// single table being restored, which restricts the rows that are restored.
+	Filter Expr
 }
 
 var _ NodeFormatter = &RestoreOptions{}
@@ -184,6 +236,10 @@ func (node *Restore) Format(ctx *FmtCtx) {
 	if node.DescriptorCoverage == RequestedDescriptors {
 		ctx.FormatNode(&node.Targets)
 		ctx.WriteString(" ")
+	} else if node.DescriptorCoverage.IsSystemTarget() {
+		ctx.WriteString("SYSTEM ")
+		ctx.WriteString(node.DescriptorCoverage.SystemTargetName())
+		ctx.WriteString(" ")
 	}
 	ctx.WriteString("FROM ")
 	if node.Subdir != nil {
@@ -540,6 +596,18 @@ func (o *RestoreOptions) Format(ctx *FmtCtx) {
 		maybeAddSep()
 		ctx.WriteString("remove_regions")
 	}
+
+	if o.IntoTable != nil {
+		maybeAddSep()
+		ctx.WriteString("into_table = ")
+		ctx.FormatNode(o.IntoTable)
+	}
+
+	if o.Filter != nil {
+		maybeAddSep()
+		ctx.WriteString("filter = ")
+		ctx.FormatNode(o.Filter)
+	}
 }
 
 // CombineWith merges other backup options into this backup options struct.
@@ -703,6 +771,18 @@ func (o *RestoreOptions) CombineWith(other *RestoreOptions) error {
 		o.RemoveRegions = other.RemoveRegions
 	}
 
+	if o.IntoTable == nil {
+		o.IntoTable = other.IntoTable
+	} else if other.IntoTable != nil {
+		return errors.New("into_table specified multiple times")
+	}
+
+	if o.Filter == nil {
+		o.Filter = other.Filter
+	} else if other.Filter != nil {
+		return errors.New("filter specified multiple times")
+	}
+
 	return nil
 }
 
@@ -730,7 +810,9 @@ func (o RestoreOptions) IsDefault() bool {
 		o.UnsafeRestoreIncompatibleVersion == options.UnsafeRestoreIncompatibleVersion &&
 		o.ExecutionLocality == options.ExecutionLocality &&
 		o.ExperimentalOnline == options.ExperimentalOnline &&
-		o.RemoveRegions == options.RemoveRegions
+		o.RemoveRegions == options.RemoveRegions &&
+		o.IntoTable == options.IntoTable &&
+		o.Filter == options.Filter
 }
 
 // BackupTargetList represents a list of targets.
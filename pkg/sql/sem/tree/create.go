@@ -2235,7 +2235,8 @@ type CreateTenantFromReplication struct {
 
 // TenantReplicationOptions  options for the CREATE VIRTUAL CLUSTER FROM REPLICATION command.
 type TenantReplicationOptions struct {
-	Retention Expr
+	Retention          Expr
+	ReadVirtualCluster bool
 }
 
 var _ NodeFormatter = &TenantReplicationOptions{}
@@ -2284,6 +2285,12 @@ func (o *TenantReplicationOptions) Format(ctx *FmtCtx) {
 			ctx.WriteByte(')')
 		}
 	}
+	if o.ReadVirtualCluster {
+		if o.Retention != nil {
+			ctx.WriteString(", ")
+		}
+		ctx.WriteString("READ VIRTUAL CLUSTER")
+	}
 }
 
 // CombineWith merges other TenantReplicationOptions into this struct.
@@ -2296,13 +2303,21 @@ func (o *TenantReplicationOptions) CombineWith(other *TenantReplicationOptions)
 	} else {
 		o.Retention = other.Retention
 	}
+	if o.ReadVirtualCluster {
+		if other.ReadVirtualCluster {
+			return errors.New("READ VIRTUAL CLUSTER option specified multiple times")
+		}
+	} else {
+		o.ReadVirtualCluster = other.ReadVirtualCluster
+	}
 	return nil
 }
 
 // IsDefault returns true if this backup options struct has default value.
 func (o TenantReplicationOptions) IsDefault() bool {
 	options := TenantReplicationOptions{}
-	return o.Retention == options.Retention
+	return o.Retention == options.Retention &&
+		o.ReadVirtualCluster == options.ReadVirtualCluster
 }
 
 type SuperRegion struct {
@@ -15,7 +15,27 @@ type Export struct {
 	Query      *Select
 	FileFormat string
 	File       Expr
-	Options    KVOptions
+	// IncrementalFrom, if set, is the location of a previous export of the same
+	// table; only changes made since that export are exported.
+	IncrementalFrom Expr
+	Options         KVOptions
+}
+
+const (
+	// ExportOptionDetached is the EXPORT option which requests that the export
+	// be run by a job whose ID is returned immediately.
+	ExportOptionDetached = "detached"
+	// ExportOptionResumable is the EXPORT option which requests that the export
+	// be run by a job, which checkpoints its progress and can be resumed.
+	ExportOptionResumable = "resumable"
+)
+
+// RunsAsJob returns whether the export is run by a job rather than planned
+// as a query.
+func (node *Export) RunsAsJob() bool {
+	return node.IncrementalFrom != nil ||
+		node.Options.HasKey(ExportOptionDetached) ||
+		node.Options.HasKey(ExportOptionResumable)
 }
 
 var _ Statement = &Export{}
@@ -26,6 +46,10 @@ func (node *Export) Format(ctx *FmtCtx) {
 	ctx.WriteString(node.FileFormat)
 	ctx.WriteString(" ")
 	ctx.FormatNode(node.File)
+	if node.IncrementalFrom != nil {
+		ctx.WriteString(" INCREMENTAL FROM ")
+		ctx.FormatNode(node.IncrementalFrom)
+	}
 	if node.Options != nil {
 		ctx.WriteString(" WITH OPTIONS(")
 		ctx.FormatNode(&node.Options)
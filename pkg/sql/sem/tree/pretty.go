@@ -2151,7 +2151,9 @@ func (node *Backup) doc(p *PrettyCfg) pretty.Doc {
 	items := make([]pretty.TableRow, 0, 7)
 
 	items = append(items, p.row("BACKUP", pretty.Nil))
-	if node.Targets != nil {
+	if node.SystemTarget.IsSystemTarget() {
+		items = append(items, p.row("SYSTEM", pretty.Keyword(node.SystemTarget.SystemTargetName())))
+	} else if node.Targets != nil {
 		items = append(items, node.Targets.docRow(p))
 	}
 	if node.Nested {
@@ -2185,6 +2187,8 @@ func (node *Restore) doc(p *PrettyCfg) pretty.Doc {
 	items = append(items, p.row("RESTORE", pretty.Nil))
 	if node.DescriptorCoverage == RequestedDescriptors {
 		items = append(items, node.Targets.docRow(p))
+	} else if node.DescriptorCoverage.IsSystemTarget() {
+		items = append(items, p.row("SYSTEM", pretty.Keyword(node.DescriptorCoverage.SystemTargetName())))
 	}
 	from := make([]pretty.Doc, len(node.From))
 	for i := range node.From {
@@ -2295,9 +2299,12 @@ func (node *Import) doc(p *PrettyCfg) pretty.Doc {
 }
 
 func (node *Export) doc(p *PrettyCfg) pretty.Doc {
-	items := make([]pretty.TableRow, 0, 4)
+	items := make([]pretty.TableRow, 0, 5)
 	items = append(items, p.row("EXPORT", pretty.Nil))
 	items = append(items, p.row("INTO "+node.FileFormat, p.Doc(node.File)))
+	if node.IncrementalFrom != nil {
+		items = append(items, p.row("INCREMENTAL FROM", p.Doc(node.IncrementalFrom)))
+	}
 	if node.Options != nil {
 		items = append(items, p.row("WITH", p.Doc(&node.Options)))
 	}
@@ -0,0 +1,70 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// CreateResourceGroup represents a CREATE RESOURCE GROUP statement.
+type CreateResourceGroup struct {
+	Name        Name
+	IfNotExists bool
+	Options     KVOptions
+}
+
+var _ Statement = &CreateResourceGroup{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateResourceGroup) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE RESOURCE GROUP ")
+	if node.IfNotExists {
+		ctx.WriteString("IF NOT EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	if len(node.Options) > 0 {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Options)
+	}
+}
+
+// AlterResourceGroup represents an ALTER RESOURCE GROUP statement.
+type AlterResourceGroup struct {
+	Name     Name
+	IfExists bool
+	Options  KVOptions
+}
+
+var _ Statement = &AlterResourceGroup{}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterResourceGroup) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER RESOURCE GROUP ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" WITH ")
+	ctx.FormatNode(&node.Options)
+}
+
+// DropResourceGroup represents a DROP RESOURCE GROUP statement.
+type DropResourceGroup struct {
+	Name     Name
+	IfExists bool
+}
+
+var _ Statement = &DropResourceGroup{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropResourceGroup) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP RESOURCE GROUP ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+}
@@ -323,3 +323,37 @@ func (node *RollbackToSavepoint) Format(ctx *FmtCtx) {
 	ctx.WriteString("ROLLBACK TRANSACTION TO SAVEPOINT ")
 	ctx.FormatNode(&node.Savepoint)
 }
+
+// PrepareTransaction represents a PREPARE TRANSACTION <transaction id>
+// statement, which prepares the current transaction for two-phase commit.
+type PrepareTransaction struct {
+	Transaction *StrVal
+}
+
+// Format implements the NodeFormatter interface.
+func (node *PrepareTransaction) Format(ctx *FmtCtx) {
+	ctx.WriteString("PREPARE TRANSACTION ")
+	ctx.FormatNode(node.Transaction)
+}
+
+// CommitPrepared represents a COMMIT PREPARED <transaction id> statement.
+type CommitPrepared struct {
+	Transaction *StrVal
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CommitPrepared) Format(ctx *FmtCtx) {
+	ctx.WriteString("COMMIT PREPARED ")
+	ctx.FormatNode(node.Transaction)
+}
+
+// RollbackPrepared represents a ROLLBACK PREPARED <transaction id> statement.
+type RollbackPrepared struct {
+	Transaction *StrVal
+}
+
+// Format implements the NodeFormatter interface.
+func (node *RollbackPrepared) Format(ctx *FmtCtx) {
+	ctx.WriteString("ROLLBACK PREPARED ")
+	ctx.FormatNode(node.Transaction)
+}
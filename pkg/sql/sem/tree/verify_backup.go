@@ -0,0 +1,53 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// VerifyBackup represents a VERIFY BACKUP statement.
+type VerifyBackup struct {
+	// Subdir is the subdirectory of the collection which contains the backup.
+	Subdir  Expr
+	To      StringOrPlaceholderOptList
+	Options KVOptions
+}
+
+const (
+	// VerifyBackupOptionDetached is the VERIFY BACKUP option which requests that
+	// the ID of the job be returned immediately.
+	VerifyBackupOptionDetached = "detached"
+	// VerifyBackupOptionFingerprint is the VERIFY BACKUP option which requests
+	// that the data in the backup also be compared with the data of the cluster
+	// as of the backup's end time.
+	VerifyBackupOptionFingerprint = "fingerprint"
+	// VerifyBackupOptionEncryptionPassphrase is the passphrase with which the
+	// backup was encrypted.
+	VerifyBackupOptionEncryptionPassphrase = "encryption_passphrase"
+	// VerifyBackupOptionKMS is the URI of a KMS with which the backup was
+	// encrypted.
+	VerifyBackupOptionKMS = "kms"
+	// VerifyBackupOptionIncrementalLocation is the location of the incremental
+	// layers of the backup, if they are not stored in the collection.
+	VerifyBackupOptionIncrementalLocation = "incremental_location"
+)
+
+var _ Statement = &VerifyBackup{}
+
+// Format implements the NodeFormatter interface.
+func (node *VerifyBackup) Format(ctx *FmtCtx) {
+	ctx.WriteString("VERIFY BACKUP ")
+	ctx.FormatNode(node.Subdir)
+	ctx.WriteString(" IN ")
+	ctx.FormatNode(&node.To)
+	if node.Options != nil {
+		ctx.WriteString(" WITH OPTIONS (")
+		ctx.FormatNode(&node.Options)
+		ctx.WriteString(")")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings"
@@ -116,6 +117,24 @@ func init() {
 			requiredType: types.Int,
 			setter:       func(c *zonepb.ZoneConfig, d tree.Datum) { c.NumVoters = proto.Int32(int32(tree.MustBeDInt(d))) },
 		},
+		{
+			field:        config.NumWitnesses,
+			requiredType: types.Int,
+			setter:       func(c *zonepb.ZoneConfig, d tree.Datum) { c.NumWitnesses = proto.Int32(int32(tree.MustBeDInt(d))) },
+		},
+		{
+			field:        config.StorageTier,
+			requiredType: types.String,
+			setter: func(c *zonepb.ZoneConfig, d tree.Datum) {
+				c.StorageTier = proto.String(strings.ToLower(string(tree.MustBeDString(d))))
+			},
+			checkAllowed: func(ctx context.Context, execCfg *ExecutorConfig, d tree.Datum) error {
+				if _, err := roachpb.ParseStorageTier(string(tree.MustBeDString(d))); err != nil {
+					return pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
+				}
+				return nil
+			},
+		},
 		{
 			field:        config.GCTTL,
 			requiredType: types.Int,
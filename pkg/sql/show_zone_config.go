@@ -220,6 +220,14 @@ func zoneConfigToSQL(zs *tree.ZoneSpecifier, zone *zonepb.ZoneConfig) (string, e
 		maybeWriteComma(f)
 		f.Printf("\tnum_voters = %d", *zone.NumVoters)
 	}
+	if zone.NumWitnesses != nil && *zone.NumWitnesses > 0 {
+		maybeWriteComma(f)
+		f.Printf("\tnum_witnesses = %d", *zone.NumWitnesses)
+	}
+	if zone.StorageTier != nil {
+		maybeWriteComma(f)
+		f.Printf("\tstorage_tier = %s", lexbase.EscapeSQLString(*zone.StorageTier))
+	}
 	if !zone.InheritedConstraints {
 		maybeWriteComma(f)
 		f.Printf("\tconstraints = %s", lexbase.EscapeSQLString(constraints))
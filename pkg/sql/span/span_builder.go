@@ -51,7 +51,12 @@ func (s *Builder) Init(
 	s.evalCtx = evalCtx
 	s.codec = codec
 	s.keyAndPrefixCols = table.IndexFetchSpecKeyAndSuffixColumns(index)
-	s.KeyPrefix = rowenc.MakeIndexKeyPrefix(codec, table.GetID(), index.GetID())
+	tableID := table.GetID()
+	if ext := table.ExternalRowData(); ext != nil {
+		s.codec = keys.MakeSQLCodec(ext.TenantID)
+		tableID = ext.TableID
+	}
+	s.KeyPrefix = rowenc.MakeIndexKeyPrefix(s.codec, tableID, index.GetID())
 }
 
 // InitWithFetchSpec creates a Builder using IndexFetchSpec.
@@ -61,7 +66,12 @@ func (s *Builder) InitWithFetchSpec(
 	s.evalCtx = evalCtx
 	s.codec = codec
 	s.keyAndPrefixCols = spec.KeyAndSuffixColumns
-	s.KeyPrefix = rowenc.MakeIndexKeyPrefix(codec, spec.TableID, spec.IndexID)
+	tableID := spec.TableID
+	if spec.External != nil {
+		s.codec = keys.MakeSQLCodec(spec.External.TenantID)
+		tableID = spec.External.TableID
+	}
+	s.KeyPrefix = rowenc.MakeIndexKeyPrefix(s.codec, tableID, spec.IndexID)
 }
 
 // SpanFromEncDatums encodes a span with len(values) constraint columns from the
@@ -17,6 +17,7 @@ import (
 	"math"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/paramparse"
@@ -362,6 +363,30 @@ var tableParams = map[string]tableParam{
 			return nil
 		},
 	},
+	`ttl_storage_expiry`: {
+		onSet: func(ctx context.Context, po *Setter, semaCtx *tree.SemaContext, evalCtx *eval.Context, key string, datum tree.Datum) error {
+			val, err := boolFromDatum(ctx, evalCtx, key, datum)
+			if err != nil {
+				return err
+			}
+			// Nodes which don't know about row expiry would keep returning
+			// expired rows, which the TTL job no longer deletes.
+			if val && !evalCtx.Settings.Version.IsActive(ctx, clusterversion.V23_2_TTLStorageExpiry) {
+				return pgerror.Newf(pgcode.FeatureNotSupported, "cannot set storage parameter %q "+
+					"until the cluster version is at least %s", key,
+					clusterversion.ByKey(clusterversion.V23_2_TTLStorageExpiry))
+			}
+			rowLevelTTL := po.getOrCreateRowLevelTTL()
+			rowLevelTTL.StorageExpiry = val
+			return nil
+		},
+		onReset: func(_ context.Context, po *Setter, evalCtx *eval.Context, key string) error {
+			if po.hasRowLevelTTL() {
+				po.UpdatedRowLevelTTL.StorageExpiry = false
+			}
+			return nil
+		},
+	},
 	`ttl_job_cron`: {
 		onSet: func(ctx context.Context, po *Setter, semaCtx *tree.SemaContext, evalCtx *eval.Context, key string, datum tree.Datum) error {
 			str, err := paramparse.DatumAsString(ctx, evalCtx, key, datum)
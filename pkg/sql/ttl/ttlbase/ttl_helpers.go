@@ -15,12 +15,16 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/errors"
 )
 
@@ -119,6 +123,55 @@ func CheckJobEnabled(settingsValues *settings.Values) error {
 	return nil
 }
 
+// GetRowExpiry returns the storage level row expiry of the table, or nil if
+// the table doesn't use storage level expiry or isn't eligible for it. Expired
+// rows of a table with a row expiry are hidden from reads and removed by MVCC
+// GC rather than deleted by the TTL job.
+//
+// Storage level expiry only applies to the primary index, since KV can't
+// tell which secondary index entries belong to an expired row. A table is
+// therefore only eligible if:
+//   - its TTL expression is a stored TIMESTAMPTZ column which isn't part of
+//     the primary key,
+//   - it has a single column family, and
+//   - it has no indexes other than the primary index, including ones being
+//     added by a schema change.
+//
+// Note that expired rows which haven't been garbage collected yet become
+// visible again if the table stops being eligible, until the TTL job deletes
+// them.
+func GetRowExpiry(desc catalog.TableDescriptor) *roachpb.RowExpiry {
+	if !desc.HasRowLevelTTL() {
+		return nil
+	}
+	ttl := desc.GetRowLevelTTL()
+	if !ttl.StorageExpiry {
+		return nil
+	}
+	if desc.NumFamilies() != 1 || len(desc.AllIndexes()) != 1 {
+		return nil
+	}
+	colName := string(ttl.GetTTLExpr())
+	if len(colName) > 1 && strings.HasPrefix(colName, `"`) && strings.HasSuffix(colName, `"`) {
+		colName = strings.ReplaceAll(colName[1:len(colName)-1], `""`, `"`)
+	}
+	col := catalog.FindColumnByName(desc, colName)
+	if col == nil || !col.Public() || col.IsVirtual() ||
+		col.GetType().Family() != types.TimestampTZFamily {
+		return nil
+	}
+	primaryIndex := desc.GetPrimaryIndex()
+	if primaryIndex.CollectKeyColumnIDs().Contains(col.GetID()) {
+		return nil
+	}
+	return &roachpb.RowExpiry{
+		TableID:  uint32(desc.GetID()),
+		IndexID:  uint32(primaryIndex.GetID()),
+		FamilyID: uint32(desc.GetFamilies()[0].ID),
+		ColumnID: uint32(col.GetID()),
+	}
+}
+
 // BuildScheduleLabel returns a string value intended for use as the
 // schedule_name/label column for the scheduled job created by row level TTL.
 func BuildScheduleLabel(tbl *tabledesc.Mutable) string {
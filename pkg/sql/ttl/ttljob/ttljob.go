@@ -81,6 +81,7 @@ func (t rowLevelTTLResumer) Resume(ctx context.Context, execCtx interface{}) err
 	var rowLevelTTL *catpb.RowLevelTTL
 	var relationName string
 	var entirePKSpan roachpb.Span
+	var storageExpiry bool
 	if err := db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
 		desc, err := descsCol.ByIDWithLeased(txn).WithoutNonPublic().Get().Table(ctx, details.TableID)
 		if err != nil {
@@ -114,11 +115,19 @@ func (t rowLevelTTLResumer) Resume(ctx context.Context, execCtx interface{}) err
 		relationName = tn.FQString()
 
 		entirePKSpan = desc.PrimaryIndexSpan(execCfg.Codec)
+		storageExpiry = ttlbase.GetRowExpiry(desc) != nil
 		return nil
 	}); err != nil {
 		return err
 	}
 
+	// Expired rows of tables with storage level expiry are removed by MVCC GC,
+	// so there is nothing for the job to delete.
+	if storageExpiry {
+		log.Infof(ctx, "skipping TTL deletion of %s, expired rows are removed by storage level expiry", relationName)
+		return nil
+	}
+
 	ttlExpr := rowLevelTTL.GetTTLExpr()
 
 	labelMetrics := rowLevelTTL.LabelMetrics
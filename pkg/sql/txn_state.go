@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
@@ -228,6 +229,9 @@ func (ts *txnState) resetForNewSQLTxn(
 		if txn == nil {
 			ts.mu.txn = kv.NewTxnWithSteppingEnabled(ts.Ctx, tranCtx.db, tranCtx.nodeIDOrZero, qualityOfService)
 			ts.mu.txn.SetDebugName(opName)
+			if tranCtx.resourceGroup != nil {
+				ts.mu.txn.SetAdmissionResourceGroup(tranCtx.resourceGroup())
+			}
 			if err := ts.setPriorityLocked(priority); err != nil {
 				panic(err)
 			}
@@ -505,6 +509,9 @@ type transitionCtx struct {
 	sessionTracing   *SessionTracing
 	settings         *cluster.Settings
 	execTestingKnobs ExecutorTestingKnobs
+	// resourceGroup, if set, returns the definition of the resource group that
+	// the work of new transactions is admitted under.
+	resourceGroup func() admissionpb.ResourceGroup
 }
 
 var noRewind = rewindCapability{}
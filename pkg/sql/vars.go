@@ -689,6 +689,54 @@ var varGen = map[string]sessionVar{
 		},
 	},
 
+	// CockroachDB extension.
+	`resource_group`: {
+		// Set is used for the session defaults only; SET resource_group goes
+		// through SetWithPlanner, which is defined in init() as otherwise there
+		// is a circular initialization loop with the planner.
+		Set: func(_ context.Context, m sessionDataMutator, s string) error {
+			m.SetResourceGroup(s)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext, _ *kv.Txn) (string, error) {
+			return evalCtx.SessionData().ResourceGroup, nil
+		},
+		GlobalDefault: func(_ *settings.Values) string {
+			return ""
+		},
+	},
+
+	// CockroachDB extension.
+	`read_after_timestamp`: {
+		Set: func(_ context.Context, m sessionDataMutator, s string) error {
+			var ts hlc.Timestamp
+			if s != "" {
+				d, err := tree.ParseDDecimal(s)
+				if err != nil {
+					return pgerror.Newf(pgcode.InvalidParameterValue,
+						"invalid value for read_after_timestamp: %q", s)
+				}
+				ts, err = hlc.DecimalToHLC(&d.Decimal)
+				if err != nil {
+					return pgerror.Wrapf(err, pgcode.InvalidParameterValue,
+						"invalid value for read_after_timestamp")
+				}
+			}
+			m.SetReadAfterTimestamp(ts)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext, _ *kv.Txn) (string, error) {
+			ts := evalCtx.SessionData().ReadAfterTimestamp
+			if ts.IsEmpty() {
+				return "", nil
+			}
+			return ts.AsOfSystemTime(), nil
+		},
+		GlobalDefault: func(_ *settings.Values) string {
+			return ""
+		},
+	},
+
 	// CockroachDB extension.
 	`vectorize`: {
 		Set: func(_ context.Context, m sessionDataMutator, s string) error {
@@ -2968,6 +3016,12 @@ func init() {
 				return p.setRole(ctx, local, u)
 			},
 		},
+		{
+			name: `resource_group`,
+			fn: func(ctx context.Context, p *planner, local bool, s string) error {
+				return p.setResourceGroup(ctx, local, s)
+			},
+		},
 	} {
 		v := varGen[p.name]
 		v.SetWithPlanner = p.fn
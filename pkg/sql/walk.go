@@ -366,6 +366,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&alterTenantSetClusterSettingNode{}):        "alter tenant set cluster setting",
 	reflect.TypeOf(&alterTenantServiceNode{}):                  "alter tenant service",
 	reflect.TypeOf(&alterTypeNode{}):                           "alter type",
+	reflect.TypeOf(&alterResourceGroupNode{}):                  "alter resource group",
 	reflect.TypeOf(&alterRoleNode{}):                           "alter role",
 	reflect.TypeOf(&alterRoleSetNode{}):                        "alter role set var",
 	reflect.TypeOf(&applyJoinNode{}):                           "apply join",
@@ -390,6 +391,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&createExternalConectionNode{}):             "create external connection",
 	reflect.TypeOf(&createFunctionNode{}):                      "create function",
 	reflect.TypeOf(&createIndexNode{}):                         "create index",
+	reflect.TypeOf(&createResourceGroupNode{}):                 "create resource group",
 	reflect.TypeOf(&createSequenceNode{}):                      "create sequence",
 	reflect.TypeOf(&createSchemaNode{}):                        "create schema",
 	reflect.TypeOf(&createStatsNode{}):                         "create statistics",
@@ -407,6 +409,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&dropExternalConnectionNode{}):              "drop external connection",
 	reflect.TypeOf(&dropFunctionNode{}):                        "drop function",
 	reflect.TypeOf(&dropIndexNode{}):                           "drop index",
+	reflect.TypeOf(&dropResourceGroupNode{}):                   "drop resource group",
 	reflect.TypeOf(&dropSequenceNode{}):                        "drop sequence",
 	reflect.TypeOf(&dropSchemaNode{}):                          "drop schema",
 	reflect.TypeOf(&dropTableNode{}):                           "drop table",
@@ -414,6 +417,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&dropTypeNode{}):                            "drop type",
 	reflect.TypeOf(&DropRoleNode{}):                            "drop user/role",
 	reflect.TypeOf(&dropViewNode{}):                            "drop view",
+	reflect.TypeOf(&endPreparedTxnNode{}):                      "end prepared txn",
 	reflect.TypeOf(&errorIfRowsNode{}):                         "error if rows",
 	reflect.TypeOf(&explainPlanNode{}):                         "explain plan",
 	reflect.TypeOf(&explainVecNode{}):                          "explain vectorized",
@@ -330,3 +330,48 @@ func FingerprintRangekeys(
 
 	return fw.Finish()
 }
+
+// FingerprintPointKeys returns the XOR aggregate of the fingerprints of the
+// point keys that iter surfaces within span, hashed the same way as the point
+// keys fingerprinted by MVCCExportFingerprint with the given options. It allows
+// data that is not read from an engine, such as the files of a backup, to be
+// compared with the fingerprint that an ExportRequest returns for the span.
+//
+// The values surfaced by iter must be encoded roachpb.Values, as they are in
+// the SSTs written by ExportRequest, rather than MVCCValues.
+func FingerprintPointKeys(
+	iter SimpleMVCCIterator, span roachpb.Span, opts MVCCExportFingerprintOptions,
+) (uint64, error) {
+	fw := fingerprintWriter{
+		hasher:  fnv.New64(),
+		xorAgg:  &uintXorAggregate{},
+		options: opts,
+	}
+	for iter.SeekGE(MVCCKey{Key: span.Key}); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return 0, err
+		} else if !ok {
+			break
+		}
+		key := iter.UnsafeKey()
+		if key.Key.Compare(span.EndKey) >= 0 {
+			break
+		}
+		if hasPoint, _ := iter.HasPointAndRange(); !hasPoint {
+			continue
+		}
+		value, err := iter.UnsafeValue()
+		if err != nil {
+			return 0, err
+		}
+		if key.IsValue() {
+			err = fw.PutRawMVCC(key, value)
+		} else {
+			err = fw.PutUnversioned(key.Key, value)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return fw.xorAgg.result(), nil
+}
@@ -1033,6 +1033,9 @@ type MVCCGetOptions struct {
 	// AllowEmpty will return an empty result if the request key exceeds the
 	// TargetBytes limit.
 	AllowEmpty bool
+	// RowExpiry, if set, treats row versions which have expired as of the read
+	// timestamp as deletion tombstones.
+	RowExpiry *RowExpiry
 }
 
 // MVCCGetResult bundles return values for the MVCCGet family of functions.
@@ -1265,6 +1268,7 @@ func mvccGetWithValueHeader(
 		skipLocked:       opts.SkipLocked,
 		tombstones:       opts.Tombstones,
 		failOnMoreRecent: opts.FailOnMoreRecent,
+		rowExpiry:        opts.RowExpiry,
 		keyBuf:           mvccScanner.keyBuf,
 	}
 
@@ -1783,6 +1787,7 @@ func replayTransactionalWrite(
 	txn *roachpb.Transaction,
 	valueFn func(optionalValue) (roachpb.Value, error),
 	replayWriteTimestampProtection bool,
+	rowExpiry *RowExpiry,
 ) error {
 	var writtenValue optionalValue
 	var err error
@@ -1850,6 +1855,7 @@ func replayTransactionalWrite(
 			metaTimestamp := meta.Timestamp.ToTimestamp()
 			exVal, _, err = mvccGet(ctx, iter, key, metaTimestamp.Prev(), MVCCGetOptions{
 				Tombstones: true,
+				RowExpiry:  rowExpiry,
 			})
 			if err != nil {
 				return err
@@ -2092,7 +2098,7 @@ func mvccPutInternal(
 				// The transaction has executed at this sequence before. This is merely a
 				// replay of the transactional write. Assert that all is in order and return
 				// early.
-				return false, replayTransactionalWrite(ctx, iter, meta, key, value, opts.Txn, valueFn, opts.ReplayWriteTimestampProtection)
+				return false, replayTransactionalWrite(ctx, iter, meta, key, value, opts.Txn, valueFn, opts.ReplayWriteTimestampProtection, opts.RowExpiry)
 			}
 
 			// We're overwriting the intent that was present at this key, before we do
@@ -2169,6 +2175,7 @@ func mvccPutInternal(
 				// read below our previous intents here.
 				exVal, _, err = mvccGet(ctx, iter, key, metaTimestamp.Prev(), MVCCGetOptions{
 					Tombstones: true,
+					RowExpiry:  opts.RowExpiry,
 				})
 				if err != nil {
 					return false, err
@@ -2308,6 +2315,7 @@ func mvccPutInternal(
 			if valueFn != nil {
 				exVal, _, err := mvccGet(ctx, iter, key, readTimestamp, MVCCGetOptions{
 					Tombstones: true,
+					RowExpiry:  opts.RowExpiry,
 				})
 				if err != nil {
 					return false, err
@@ -4016,6 +4024,7 @@ func mvccScanInit(
 		skipLocked:       opts.SkipLocked,
 		tombstones:       opts.Tombstones,
 		failOnMoreRecent: opts.FailOnMoreRecent,
+		rowExpiry:        opts.RowExpiry,
 		keyBuf:           mvccScanner.keyBuf,
 		// NB: If the `results` argument passed to this function is a pointer to
 		// mvccScanner.alloc.pebbleResults, we don't want to overwrite any
@@ -4169,6 +4178,9 @@ type MVCCWriteOptions struct {
 	//
 	// The zero value indicates no limit.
 	MaxLockConflicts int64
+	// RowExpiry, if set, treats existing row versions which have expired as
+	// deletion tombstones when a conditional write reads the existing value.
+	RowExpiry *RowExpiry
 }
 
 func (opts *MVCCWriteOptions) validate() error {
@@ -4236,6 +4248,9 @@ type MVCCScanOptions struct {
 	// or not. It is usually set by read-only requests that have resolved their
 	// conflicts before they begin their MVCC scan.
 	DontInterleaveIntents bool
+	// RowExpiry, if set, treats row versions which have expired as of the read
+	// timestamp as deletion tombstones.
+	RowExpiry *RowExpiry
 }
 
 func (opts *MVCCScanOptions) validate() error {
@@ -5614,6 +5629,24 @@ func MVCCGarbageCollect(
 	keys []kvpb.GCRequest_GCKey,
 	timestamp hlc.Timestamp,
 ) error {
+	return MVCCGarbageCollectWithRowExpiry(ctx, rw, ms, keys, timestamp, nil /* rowExpiry */, hlc.Timestamp{})
+}
+
+// MVCCGarbageCollectWithRowExpiry is like MVCCGarbageCollect, but also allows
+// the latest, non-deleted value of a key to be removed if it has expired as of
+// the GC threshold according to the given RowExpiry.
+//
+// REQUIRES: if rowExpiry is set, the caller must exclude concurrent writes to
+// the keys, since removing a live version races with writes on top of it.
+func MVCCGarbageCollectWithRowExpiry(
+	ctx context.Context,
+	rw ReadWriter,
+	ms *enginepb.MVCCStats,
+	keys []kvpb.GCRequest_GCKey,
+	timestamp hlc.Timestamp,
+	rowExpiry *RowExpiry,
+	gcThreshold hlc.Timestamp,
+) error {
 
 	var count int64
 	defer func(begin time.Time) {
@@ -5684,6 +5717,9 @@ func MVCCGarbageCollect(
 		// We are guaranteed now to be positioned at the meta or version key that
 		// belongs to gcKey history.
 
+		// expiredLive is set if the latest version of the key is live, but is
+		// being removed because it has expired.
+		var expiredLive bool
 		unsafeKey := iter.UnsafeKey()
 		implicitMeta := unsafeKey.IsValue()
 		// Note that we naively can't terminate GC'ing keys loop early if we
@@ -5716,7 +5752,20 @@ func MVCCGarbageCollect(
 			// they are internal and GCing them directly saves the extra
 			// deletion step.
 			if !meta.Deleted && !inlinedValue {
-				return errors.Errorf("request to GC non-deleted, latest value of %q", gcKey.Key)
+				// An expired row version is removed as if it were a deletion
+				// tombstone. The iterator is positioned on the latest version,
+				// since only intents and inline values have explicit metadata.
+				if !implicitMeta || meta.Txn != nil {
+					return errors.Errorf("request to GC non-deleted, latest value of %q", gcKey.Key)
+				}
+				v, err := DecodeMVCCValueAndErr(iter.UnsafeValue())
+				if err != nil {
+					return err
+				}
+				if !rowExpiry.Expired(gcKey.Key, v.Value.RawBytes, gcThreshold) {
+					return errors.Errorf("request to GC non-deleted, latest value of %q", gcKey.Key)
+				}
+				expiredLive = true
 			}
 			if meta.Txn != nil {
 				return errors.Errorf("request to GC intent at %q", gcKey.Key)
@@ -5726,7 +5775,15 @@ func MVCCGarbageCollect(
 					updateStatsForInline(ms, gcKey.Key, metaKeySize, metaValSize, 0, 0)
 					ms.AgeTo(timestamp.WallTime)
 				} else {
-					ms.Add(updateStatsOnGC(gcKey.Key, metaKeySize, metaValSize, true /* metaKey */, meta.Timestamp.WallTime))
+					nonLiveMS := meta.Timestamp.WallTime
+					if expiredLive {
+						// The expired key was live up until now, so it has not
+						// accumulated any GCBytesAge.
+						nonLiveMS = timestamp.WallTime
+						ms.LiveBytes -= metaKeySize + metaValSize
+						ms.LiveCount--
+					}
+					ms.Add(updateStatsOnGC(gcKey.Key, metaKeySize, metaValSize, true /* metaKey */, nonLiveMS))
 				}
 			}
 			if !implicitMeta {
@@ -5890,6 +5947,12 @@ func MVCCGarbageCollect(
 				}
 
 				ms.Add(updateStatsOnGC(gcKey.Key, keySize, valSize, false /* metaKey */, fromNS))
+				if expiredLive {
+					// The first version removed is the expired latest version, which
+					// was accounted for as live.
+					ms.LiveBytes -= keySize + valSize
+					expiredLive = false
+				}
 			}
 			count++
 			if err := rw.ClearMVCC(unsafeIterKey, clearOpts); err != nil {
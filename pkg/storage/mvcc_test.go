@@ -7003,6 +7003,38 @@ func TestMVCCExportFingerprint(t *testing.T) {
 			require.Equal(t, fingerprint3, fingerprint1^fingerprint2)
 		})
 	})
+
+	t.Run("fingerprint-point-keys", func(t *testing.T) {
+		// Reading the latest revisions out of an SST that contains all of them
+		// should produce the same fingerprint as exporting only the latest
+		// revisions.
+		opts := MVCCExportOptions{
+			StartKey:           MVCCKey{Key: key(1)},
+			EndKey:             keys.MaxKey,
+			StartTS:            hlc.Timestamp{},
+			EndTS:              hlc.Timestamp{WallTime: 9999},
+			ExportAllRevisions: true,
+		}
+		var sst bytes.Buffer
+		_, _, err := MVCCExportToSST(ctx, st, engine, opts, &sst)
+		require.NoError(t, err)
+		iter, err := NewMemSSTIterator(sst.Bytes(), false /* verify */, IterOptions{
+			KeyTypes:   IterKeyTypePointsAndRanges,
+			LowerBound: keys.LocalMax,
+			UpperBound: keys.MaxKey,
+		})
+		require.NoError(t, err)
+		readAsOfIter := NewReadAsOfIterator(iter, opts.EndTS)
+		defer readAsOfIter.Close()
+		actual, err := FingerprintPointKeys(readAsOfIter,
+			roachpb.Span{Key: key(1), EndKey: keys.MaxKey}, opts.FingerprintOptions)
+		require.NoError(t, err)
+
+		opts.ExportAllRevisions = false
+		expected, _, _, _ := fingerprint(opts, engine)
+		require.NotZero(t, expected)
+		require.Equal(t, expected, actual)
+	})
 }
 
 type fingerprintOracle struct {
@@ -1096,7 +1096,8 @@ func NewPebble(ctx context.Context, cfg PebbleConfig) (p *Pebble, err error) {
 		}
 	}
 
-	storeProps := computeStoreProperties(ctx, cfg.Dir, opts.ReadOnly, encryptionEnv != nil /* encryptionEnabled */)
+	storeProps := computeStoreProperties(ctx, cfg.Dir, opts.ReadOnly,
+		encryptionEnv != nil /* encryptionEnabled */, cfg.SharedStorage != nil /* sharedStorageEnabled */)
 
 	p = &Pebble{
 		FS:               opts.FS,
@@ -1866,6 +1867,22 @@ func (p *Pebble) Capacity() (roachpb.StoreCapacity, error) {
 	m := p.db.Metrics()
 	totalUsedBytes := int64(m.DiskSpaceUsage())
 
+	// Pebble's accounting includes sstables that live in remote storage, which
+	// don't consume any local disk space. Exclude them from the used bytes, so
+	// that stores backed by remote storage aren't considered full.
+	var remoteBytes int64
+	if p.properties.StorageTier == roachpb.StorageTier_COLD {
+		_, remoteUsed, _, err := p.ApproximateDiskBytes(roachpb.KeyMin, roachpb.KeyMax)
+		if err != nil {
+			return roachpb.StoreCapacity{}, err
+		}
+		remoteBytes = int64(remoteUsed)
+		totalUsedBytes -= remoteBytes
+		if totalUsedBytes < 0 {
+			totalUsedBytes = 0
+		}
+	}
+
 	// We don't have incremental accounting of the disk space usage of files
 	// in the auxiliary directory. Walk the auxiliary directory and all its
 	// subdirectories, adding to the total used bytes.
@@ -1903,9 +1920,10 @@ func (p *Pebble) Capacity() (roachpb.StoreCapacity, error) {
 	// totals.
 	if p.maxSize == 0 || p.maxSize >= fsuTotal || p.path == "" {
 		return roachpb.StoreCapacity{
-			Capacity:  fsuTotal,
-			Available: fsuAvail,
-			Used:      totalUsedBytes,
+			Capacity:    fsuTotal,
+			Available:   fsuAvail,
+			Used:        totalUsedBytes,
+			RemoteBytes: remoteBytes,
 		}, nil
 	}
 
@@ -1918,9 +1936,10 @@ func (p *Pebble) Capacity() (roachpb.StoreCapacity, error) {
 	}
 
 	return roachpb.StoreCapacity{
-		Capacity:  p.maxSize,
-		Available: available,
-		Used:      totalUsedBytes,
+		Capacity:    p.maxSize,
+		Available:   available,
+		Used:        totalUsedBytes,
+		RemoteBytes: remoteBytes,
 	}, nil
 }
 
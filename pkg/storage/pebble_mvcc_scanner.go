@@ -429,12 +429,13 @@ type pebbleMVCCScanner struct {
 	checkUncertainty bool
 	// Metadata object for unmarshalling intents.
 	meta enginepb.MVCCMetadata
-	// Bools copied over from MVCC{Scan,Get}Options. See the comment on the
+	// Options copied over from MVCC{Scan,Get}Options. See the comment on the
 	// package level MVCCScan for what these mean.
 	inconsistent     bool
 	skipLocked       bool
 	tombstones       bool
 	failOnMoreRecent bool
+	rowExpiry        *RowExpiry
 	keyBuf           []byte
 	savedBuf         []byte
 	lazyFetcherBuf   pebble.LazyFetcher
@@ -1227,6 +1228,13 @@ func (p *pebbleMVCCScanner) add(
 	if len(rawValue) == 0 && !p.tombstones {
 		return true /* ok */, false
 	}
+	// Treat expired row versions as deleted.
+	if p.rowExpiry != nil && p.rowExpiry.Expired(key, rawValue, p.ts) {
+		if !p.tombstones {
+			return true /* ok */, false
+		}
+		rawValue = nil
+	}
 
 	// If the scanner has been configured with the skipLocked option, don't
 	// include locked keys in the result set. Consult the in-memory lock table to
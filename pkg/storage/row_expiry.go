@@ -0,0 +1,125 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// RowExpiry determines whether row versions of a table with storage level
+// expiry have expired (see roachpb.RowExpiry). An expired row version is
+// treated as if it were a deletion tombstone: reads don't return it and MVCC
+// GC removes it once it has expired as of the GC threshold. Expired versions
+// are not dropped by Pebble compactions: compactions can't tell which version
+// of a key is the latest one without reading its newer versions, and dropping
+// them behind MVCC's back would leave the range's MVCC stats wrong. Their
+// space is reclaimed by compactions after GC has removed them.
+//
+// A nil *RowExpiry never considers anything expired. Keys outside of the
+// table's primary index and values which cannot be decoded never expire.
+type RowExpiry struct {
+	tableID, indexID   uint32
+	familyID, columnID uint32
+}
+
+// NewRowExpiry returns the RowExpiry of the given span config field, or nil
+// if it isn't set.
+func NewRowExpiry(conf *roachpb.RowExpiry) *RowExpiry {
+	if conf == nil || conf.ColumnID == 0 {
+		return nil
+	}
+	return &RowExpiry{
+		tableID:  conf.TableID,
+		indexID:  conf.IndexID,
+		familyID: conf.FamilyID,
+		columnID: conf.ColumnID,
+	}
+}
+
+// Expired returns whether the row version with the given key and encoded
+// roachpb.Value has expired as of the given timestamp, i.e. whether its
+// expiration time is at or below it.
+func (re *RowExpiry) Expired(key roachpb.Key, rawValue []byte, ts hlc.Timestamp) bool {
+	expiration, ok := re.Expiration(key, rawValue)
+	return ok && expiration.LessEq(ts)
+}
+
+// Expiration returns the expiration time of the row version with the given key
+// and encoded roachpb.Value. It returns false if the row version never expires.
+func (re *RowExpiry) Expiration(key roachpb.Key, rawValue []byte) (hlc.Timestamp, bool) {
+	if re == nil || len(rawValue) == 0 || !re.appliesTo(key) {
+		return hlc.Timestamp{}, false
+	}
+	value := roachpb.Value{RawBytes: rawValue}
+	switch value.GetTag() {
+	case roachpb.ValueType_TUPLE:
+		tuple, err := value.GetTuple()
+		if err != nil {
+			return hlc.Timestamp{}, false
+		}
+		var colID uint32
+		for len(tuple) > 0 {
+			_, dataOffset, colIDDelta, typ, err := encoding.DecodeValueTag(tuple)
+			if err != nil {
+				return hlc.Timestamp{}, false
+			}
+			colID += colIDDelta
+			if colID == re.columnID {
+				if typ != encoding.Time {
+					return hlc.Timestamp{}, false
+				}
+				_, t, err := encoding.DecodeTimeValue(tuple)
+				if err != nil {
+					return hlc.Timestamp{}, false
+				}
+				return hlc.Timestamp{WallTime: t.UnixNano()}, true
+			}
+			if colID > re.columnID {
+				// NULL columns are omitted from the tuple.
+				return hlc.Timestamp{}, false
+			}
+			length, err := encoding.PeekValueLengthWithOffsetsAndType(tuple, dataOffset, typ)
+			if err != nil {
+				return hlc.Timestamp{}, false
+			}
+			tuple = tuple[length:]
+		}
+		return hlc.Timestamp{}, false
+	case roachpb.ValueType_TIME:
+		// A column family with a single column stores the column's value
+		// directly. The expiration column is the only column in the family.
+		t, err := value.GetTime()
+		if err != nil {
+			return hlc.Timestamp{}, false
+		}
+		return hlc.Timestamp{WallTime: t.UnixNano()}, true
+	default:
+		return hlc.Timestamp{}, false
+	}
+}
+
+// appliesTo returns whether the key belongs to the expiration column's family
+// in the table's primary index.
+func (re *RowExpiry) appliesTo(key roachpb.Key) bool {
+	rest, _, err := keys.DecodeTenantPrefix(key)
+	if err != nil {
+		return false
+	}
+	_, tableID, indexID, err := keys.DecodeTableIDIndexID(rest)
+	if err != nil || tableID != re.tableID || indexID != re.indexID {
+		return false
+	}
+	familyID, err := keys.DecodeFamilyKey(key)
+	return err == nil && familyID == re.familyID
+}
@@ -0,0 +1,205 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// makeRowExpiryKey returns the key of the given column family of the row with
+// the given primary key in the table's index.
+func makeRowExpiryKey(tableID, indexID uint32, pk int64, familyID uint32) roachpb.Key {
+	key := keys.SystemSQLCodec.IndexPrefix(tableID, indexID)
+	key = encoding.EncodeVarintAscending(key, pk)
+	return keys.MakeFamilyKey(key, familyID)
+}
+
+// makeRowExpiryValue returns a tuple encoded column family value, containing
+// an INT column with ID 2 and a TIMESTAMPTZ column with ID 3 if exp is set.
+func makeRowExpiryValue(exp time.Time) roachpb.Value {
+	buf := encoding.EncodeIntValue(nil, 2, 42)
+	if !exp.IsZero() {
+		buf = encoding.EncodeTimeValue(buf, 1, exp)
+	}
+	var v roachpb.Value
+	v.SetTuple(buf)
+	return v
+}
+
+func TestRowExpiryExpired(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	re := NewRowExpiry(&roachpb.RowExpiry{TableID: 100, IndexID: 1, FamilyID: 0, ColumnID: 3})
+	exp := time.Unix(10, 0)
+	before := hlc.Timestamp{WallTime: exp.UnixNano() - 1}
+	at := hlc.Timestamp{WallTime: exp.UnixNano()}
+
+	var singleColumn roachpb.Value
+	singleColumn.SetTime(exp)
+	var wrongType roachpb.Value
+	wrongType.SetTuple(encoding.EncodeIntValue(nil, 3, exp.UnixNano()))
+
+	testCases := []struct {
+		name    string
+		re      *RowExpiry
+		key     roachpb.Key
+		value   roachpb.Value
+		ts      hlc.Timestamp
+		expired bool
+	}{
+		{"expired", re, makeRowExpiryKey(100, 1, 1, 0), makeRowExpiryValue(exp), at, true},
+		{"not expired", re, makeRowExpiryKey(100, 1, 1, 0), makeRowExpiryValue(exp), before, false},
+		{"null expiration", re, makeRowExpiryKey(100, 1, 1, 0), makeRowExpiryValue(time.Time{}), at, false},
+		{"single column family", re, makeRowExpiryKey(100, 1, 1, 0), singleColumn, at, true},
+		{"wrong column type", re, makeRowExpiryKey(100, 1, 1, 0), wrongType, at, false},
+		{"other table", re, makeRowExpiryKey(101, 1, 1, 0), makeRowExpiryValue(exp), at, false},
+		{"other index", re, makeRowExpiryKey(100, 2, 1, 0), makeRowExpiryValue(exp), at, false},
+		{"other family", re, makeRowExpiryKey(100, 1, 1, 1), makeRowExpiryValue(exp), at, false},
+		{"nil row expiry", nil, makeRowExpiryKey(100, 1, 1, 0), makeRowExpiryValue(exp), at, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expired, tc.re.Expired(tc.key, tc.value.RawBytes, tc.ts))
+		})
+	}
+	require.Nil(t, NewRowExpiry(nil))
+}
+
+// TestMVCCRowExpiry verifies that reads don't return expired rows and that
+// MVCC GC removes them, along with their history, once they have expired as
+// of the GC threshold.
+func TestMVCCRowExpiry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	engine := NewDefaultInMemForTesting()
+	defer engine.Close()
+
+	re := NewRowExpiry(&roachpb.RowExpiry{TableID: 100, IndexID: 1, FamilyID: 0, ColumnID: 3})
+	ts1 := hlc.Timestamp{WallTime: 1e9}
+	ts2 := hlc.Timestamp{WallTime: 2e9}
+	ts3 := hlc.Timestamp{WallTime: 3e9}
+	ts4 := hlc.Timestamp{WallTime: 4e9}
+
+	expiredKey := makeRowExpiryKey(100, 1, 1, 0)
+	liveKey := makeRowExpiryKey(100, 1, 2, 0)
+	ms := &enginepb.MVCCStats{}
+	for _, kv := range []struct {
+		key roachpb.Key
+		ts  hlc.Timestamp
+		exp time.Time
+	}{
+		{expiredKey, ts1, time.Unix(0, ts4.WallTime)},
+		{expiredKey, ts2, time.Unix(0, ts3.WallTime)},
+		{liveKey, ts2, time.Unix(0, ts4.WallTime)},
+	} {
+		require.NoError(t, MVCCPut(ctx, engine, kv.key, kv.ts, makeRowExpiryValue(kv.exp),
+			MVCCWriteOptions{Stats: ms}))
+	}
+
+	// Reads filter out expired rows, without affecting earlier reads.
+	res, err := MVCCGet(ctx, engine, expiredKey, ts3, MVCCGetOptions{RowExpiry: re})
+	require.NoError(t, err)
+	require.Nil(t, res.Value)
+	res, err = MVCCGet(ctx, engine, expiredKey, ts2.Prev(), MVCCGetOptions{RowExpiry: re})
+	require.NoError(t, err)
+	require.NotNil(t, res.Value)
+	scanRes, err := MVCCScan(ctx, engine, expiredKey, liveKey.PrefixEnd(), ts3, MVCCScanOptions{RowExpiry: re})
+	require.NoError(t, err)
+	require.Len(t, scanRes.KVs, 1)
+	require.Equal(t, liveKey, scanRes.KVs[0].Key)
+
+	// The latest version of a row can't be collected unless it has expired as
+	// of the GC threshold.
+	gcKeys := []kvpb.GCRequest_GCKey{{Key: expiredKey, Timestamp: ts2}}
+	require.ErrorContains(t, MVCCGarbageCollect(ctx, engine, ms, gcKeys, ts3),
+		"request to GC non-deleted, latest value")
+	require.ErrorContains(t, MVCCGarbageCollectWithRowExpiry(ctx, engine, ms, gcKeys, ts3, re, ts2),
+		"request to GC non-deleted, latest value")
+	require.ErrorContains(t, MVCCGarbageCollectWithRowExpiry(ctx, engine, ms,
+		[]kvpb.GCRequest_GCKey{{Key: liveKey, Timestamp: ts2}}, ts3, re, ts3),
+		"request to GC non-deleted, latest value")
+
+	require.NoError(t, MVCCGarbageCollectWithRowExpiry(ctx, engine, ms, gcKeys, ts3, re, ts3))
+	res, err = MVCCGet(ctx, engine, expiredKey, ts1, MVCCGetOptions{})
+	require.NoError(t, err)
+	require.Nil(t, res.Value)
+
+	for _, mvccStatsTest := range mvccStatsTests {
+		t.Run(mvccStatsTest.name, func(t *testing.T) {
+			expMS, err := mvccStatsTest.fn(engine, localMax, roachpb.KeyMax, ts3.WallTime)
+			require.NoError(t, err)
+			assertEq(t, engine, "verification", ms, &expMS)
+		})
+	}
+}
+
+// TestMVCCRowExpiryConditionalWrites verifies that conditional writes treat
+// expired rows as deleted.
+func TestMVCCRowExpiryConditionalWrites(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	engine := NewDefaultInMemForTesting()
+	defer engine.Close()
+
+	re := NewRowExpiry(&roachpb.RowExpiry{TableID: 100, IndexID: 1, FamilyID: 0, ColumnID: 3})
+	ts1 := hlc.Timestamp{WallTime: 1e9}
+	ts2 := hlc.Timestamp{WallTime: 2e9}
+	ts3 := hlc.Timestamp{WallTime: 3e9}
+
+	key := makeRowExpiryKey(100, 1, 1, 0)
+	require.NoError(t, MVCCPut(ctx, engine, key, ts1, makeRowExpiryValue(time.Unix(0, ts2.WallTime)),
+		MVCCWriteOptions{}))
+	newValue := makeRowExpiryValue(time.Unix(0, ts3.WallTime+1))
+
+	// Without the row expiry, the row still exists.
+	batch := engine.NewBatch()
+	defer batch.Close()
+	var condFailedErr *kvpb.ConditionFailedError
+	err := MVCCConditionalPut(ctx, batch, key, ts3, newValue, nil, CPutFailIfMissing, MVCCWriteOptions{})
+	require.ErrorAs(t, err, &condFailedErr)
+	err = MVCCInitPut(ctx, batch, key, ts3, newValue, false /* failOnTombstones */, MVCCWriteOptions{})
+	require.ErrorAs(t, err, &condFailedErr)
+
+	// A CPut which expects the row to be missing succeeds once it has expired,
+	// but not before.
+	err = MVCCConditionalPut(ctx, batch, key, ts2.Prev(), newValue, nil, CPutFailIfMissing,
+		MVCCWriteOptions{RowExpiry: re})
+	require.ErrorAs(t, err, &condFailedErr)
+	require.NoError(t, MVCCConditionalPut(ctx, batch, key, ts3, newValue, nil, CPutFailIfMissing,
+		MVCCWriteOptions{RowExpiry: re}))
+
+	// An InitPut of a different value succeeds once the row has expired, unless
+	// it fails on tombstones.
+	batch2 := engine.NewBatch()
+	defer batch2.Close()
+	err = MVCCInitPut(ctx, batch2, key, ts3, newValue, true, /* failOnTombstones */
+		MVCCWriteOptions{RowExpiry: re})
+	require.ErrorAs(t, err, &condFailedErr)
+	require.NoError(t, MVCCInitPut(ctx, batch2, key, ts3, newValue, false, /* failOnTombstones */
+		MVCCWriteOptions{RowExpiry: re}))
+}
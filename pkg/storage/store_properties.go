@@ -20,11 +20,17 @@ import (
 )
 
 func computeStoreProperties(
-	ctx context.Context, dir string, readonly bool, encryptionEnabled bool,
+	ctx context.Context, dir string, readonly bool, encryptionEnabled bool, sharedStorageEnabled bool,
 ) roachpb.StoreProperties {
 	props := roachpb.StoreProperties{
-		ReadOnly:  readonly,
-		Encrypted: encryptionEnabled,
+		ReadOnly:    readonly,
+		Encrypted:   encryptionEnabled,
+		StorageTier: roachpb.StorageTier_HOT,
+	}
+	// Stores that are configured with shared storage create their sstables in
+	// remote object storage, using the local disk as a cache.
+	if sharedStorageEnabled {
+		props.StorageTier = roachpb.StorageTier_COLD
 	}
 
 	// In-memory store?
@@ -322,6 +322,18 @@ var upgrades = []upgradebase.Upgrade{
 		upgrade.NoPrecondition,
 		grantExecuteToPublicOnAllFunctions,
 	),
+	upgrade.NewTenantUpgrade(
+		"create system.prepared_transactions table",
+		toCV(clusterversion.V23_2_PreparedTransactionsTable),
+		upgrade.NoPrecondition,
+		createPreparedTransactionsTable,
+	),
+	upgrade.NewTenantUpgrade(
+		"create system.resource_groups table",
+		toCV(clusterversion.V23_2_ResourceGroupsTable),
+		upgrade.NoPrecondition,
+		createResourceGroupsTable,
+	),
 }
 
 var (
@@ -0,0 +1,109 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admissionpb
+
+import "github.com/cockroachdb/redact"
+
+const (
+	// DefaultResourceGroupShare is the CPU and IO share of the default resource
+	// group, and of resource groups that don't specify a share.
+	DefaultResourceGroupShare = 10
+	// MaxResourceGroupShare is the maximum CPU or IO share of a resource group.
+	// Together with a minimum share of 1, it bounds how much more of a resource
+	// a group can get relative to another group of the same tenant.
+	MaxResourceGroupShare = 100
+	// MaxResourceGroupConcurrency is the maximum concurrency limit of a resource
+	// group.
+	MaxResourceGroupConcurrency = 1 << 20
+	// MaxResourceGroups is the maximum number of resource groups, other than
+	// the default group, of a tenant. Work queues track at most this many
+	// groups per tenant, and admit the work of any other group in the default
+	// group.
+	MaxResourceGroups = 16
+	// MaxResourceGroupNameLength is the maximum length of the name of a
+	// resource group.
+	MaxResourceGroupNameLength = 63
+)
+
+// IsValidResourceGroupName returns whether name is a valid name for a
+// (non-default) resource group, i.e., it consists of at most
+// MaxResourceGroupNameLength lower case letters, digits and underscores.
+// Resource group names are supplied by tenants and end up in metric labels
+// and logs, so work with any other name is treated as belonging to the
+// default resource group.
+func IsValidResourceGroupName(name string) bool {
+	if len(name) == 0 || len(name) > MaxResourceGroupNameLength {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsDefault returns whether this is the default resource group.
+func (rg ResourceGroup) IsDefault() bool {
+	return rg.Name == ""
+}
+
+// CPUWeight returns the weight of the resource group when sharing CPU.
+func (rg ResourceGroup) CPUWeight() uint32 {
+	return shareToWeight(rg.CPUShare)
+}
+
+// IOWeight returns the weight of the resource group when sharing store write
+// tokens.
+func (rg ResourceGroup) IOWeight() uint32 {
+	return shareToWeight(rg.IOShare)
+}
+
+// ConcurrencyLimit returns the maximum number of slots the resource group's
+// work can hold at the same time, or 0 if unbounded.
+func (rg ResourceGroup) ConcurrencyLimit() int {
+	if rg.MaxConcurrency <= 0 {
+		return 0
+	}
+	if rg.MaxConcurrency > MaxResourceGroupConcurrency {
+		return MaxResourceGroupConcurrency
+	}
+	return int(rg.MaxConcurrency)
+}
+
+func shareToWeight(share uint32) uint32 {
+	if share == 0 {
+		return DefaultResourceGroupShare
+	}
+	if share > MaxResourceGroupShare {
+		return MaxResourceGroupShare
+	}
+	return share
+}
+
+// SafeFormat implements the redact.SafeFormatter interface.
+func (rg ResourceGroup) SafeFormat(p redact.SafePrinter, _ rune) {
+	if rg.IsDefault() {
+		p.SafeString("default")
+		return
+	}
+	if !IsValidResourceGroupName(rg.Name) {
+		p.Print(rg.Name)
+		return
+	}
+	p.Printf("%s", redact.SafeString(rg.Name))
+}
+
+// String implements the fmt.Stringer interface.
+func (rg ResourceGroup) String() string {
+	return redact.StringWithoutMarkers(rg)
+}
@@ -23,6 +23,9 @@ import (
 // for cooperative scheduling with elastic CPU granters).
 type ElasticCPUWorkHandle struct {
 	tenantID roachpb.TenantID
+	// resourceGroup is the name of the resource group the work was admitted
+	// in.
+	resourceGroup string
 	// cpuStart captures the running time of the calling goroutine when this
 	// handle is constructed.
 	cpuStart time.Duration
@@ -53,7 +53,7 @@ type elasticCPUInternalWorkQueue interface {
 	requester
 	Admit(ctx context.Context, info WorkInfo) (enabled bool, err error)
 	SetTenantWeights(tenantWeights map[uint64]uint32)
-	adjustTenantUsed(tenantID roachpb.TenantID, additionalUsed int64)
+	adjustTenantUsed(tenantID roachpb.TenantID, resourceGroup string, additionalUsed int64)
 }
 
 func makeElasticCPUWorkQueue(
@@ -92,7 +92,9 @@ func (e *ElasticCPUWorkQueue) Admit(
 		return nil, nil
 	}
 	e.metrics.AcquiredNanos.Inc(duration.Nanoseconds())
-	return newElasticCPUWorkHandle(info.TenantID, duration), nil
+	h := newElasticCPUWorkHandle(info.TenantID, duration)
+	h.resourceGroup = info.ResourceGroup.Name
+	return h, nil
 }
 
 // AdmittedWorkDone indicates to the queue that the admitted work has
@@ -104,7 +106,7 @@ func (e *ElasticCPUWorkQueue) AdmittedWorkDone(h *ElasticCPUWorkHandle) {
 
 	e.metrics.PreWorkNanos.Inc(h.preWork.Nanoseconds())
 	_, difference := h.OverLimit()
-	e.workQueue.adjustTenantUsed(h.tenantID, difference.Nanoseconds())
+	e.workQueue.adjustTenantUsed(h.tenantID, h.resourceGroup, difference.Nanoseconds())
 	if difference > 0 {
 		// We've used up our allotted slice, which we've already deducted tokens
 		// for. But we've gone over by difference, which we now need to deduct
@@ -172,7 +172,7 @@ func (t *testElasticCPUInternalWorkQueue) SetTenantWeights(tenantWeights map[uin
 }
 
 func (t *testElasticCPUInternalWorkQueue) adjustTenantUsed(
-	tenantID roachpb.TenantID, additionalUsed int64,
+	tenantID roachpb.TenantID, _ string, additionalUsed int64,
 ) {
 	if !t.disabled {
 		fmt.Fprintf(&t.buf, "adjust-tenant-used: tenant=%s additional-used=%s",
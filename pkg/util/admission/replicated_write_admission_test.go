@@ -338,11 +338,12 @@ func printWorkQueue(q *WorkQueue) string {
 			admissionpb.WorkPriority(tenant.fifoPriorityThreshold),
 			printTrimmedBytes(int64(tenant.used)),
 		))
-		if len(tenant.waitingWorkHeap) > 0 {
+		// Replicated writes are always queued in the default resource group.
+		if group := tenant.groups[""]; group != nil && len(group.waitingWorkHeap) > 0 {
 			buf.WriteString("\n")
 
-			for i := range tenant.waitingWorkHeap {
-				w := tenant.waitingWorkHeap[i]
+			for i := range group.waitingWorkHeap {
+				w := group.waitingWorkHeap[i]
 				if i != 0 {
 					buf.WriteString("\n")
 				}
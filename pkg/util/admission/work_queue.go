@@ -16,6 +16,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -27,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/metric/aggmetric"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
@@ -204,6 +206,10 @@ type WorkInfo struct {
 	// ReplicatedWorkInfo groups everything needed to admit replicated writes, done
 	// so asynchronously below-raft as part of replication admission control.
 	ReplicatedWorkInfo ReplicatedWorkInfo
+	// ResourceGroup is the resource group of the work, within the tenant. The
+	// zero value represents the default resource group. Replicated writes are
+	// always admitted in the default resource group.
+	ResourceGroup admissionpb.ResourceGroup
 }
 
 // ReplicatedWorkInfo groups everything needed to admit replicated writes, done
@@ -247,15 +253,24 @@ func (r LogPosition) Less(o LogPosition) bool {
 }
 
 // WorkQueue maintains a queue of work waiting to be admitted. Ordering of
-// work is achieved via 2 heaps: a tenant heap orders the tenants with waiting
-// work in increasing order of used slots or tokens, optionally adjusted by
-// tenant weights. Within each tenant, the waiting work is ordered based on
-// priority and create time. Tenants with non-zero values of used slots or
-// tokens are tracked even if they have no more waiting work. Token usage is
-// reset to zero every second. The choice of 1 second of memory for token
-// distribution fairness is somewhat arbitrary. The same 1 second interval is
-// also used to garbage collect tenants who have no waiting requests and no
-// used slots or tokens.
+// work is achieved via 3 levels of heaps: a tenant heap orders the tenants
+// with waiting work in increasing order of used slots or tokens, optionally
+// adjusted by tenant weights. Within each tenant, a group heap orders the
+// tenant's resource groups with waiting work in the same manner, using the
+// weights (CPU or IO shares) of the resource groups. Within each resource
+// group, the waiting work is ordered based on priority and create time. Work
+// that doesn't specify a resource group is queued in the tenant's default
+// resource group. Tenants and resource groups with non-zero values of used
+// slots or tokens are tracked even if they have no more waiting work. Token
+// usage is reset to zero every second. The choice of 1 second of memory for
+// token distribution fairness is somewhat arbitrary. The same 1 second
+// interval is also used to garbage collect tenants and resource groups who
+// have no waiting requests and no used slots or tokens.
+//
+// For slot based queues, a resource group can additionally bound the number
+// of slots its work holds at the same time. A resource group that holds its
+// maximum number of slots is removed from the group heap until one of its
+// slots is returned.
 //
 // Usage example:
 //
@@ -270,7 +285,7 @@ func (r LogPosition) Less(o LogPosition) bool {
 //	}
 //	<do the work>
 //	if enabled {
-//	  kvQueue.AdmittedWorkDone(tid)
+//	  kvQueue.AdmittedWorkDone(tid, resourceGroup, cpuTime)
 //	}
 type WorkQueue struct {
 	ambientCtx     context.Context
@@ -279,7 +294,11 @@ type WorkQueue struct {
 	usesTokens     bool
 	tiedToRange    bool
 	usesAsyncAdmit bool
-	settings       *cluster.Settings
+	usesIOShares   bool
+	// maxResourceGroups is the maximum number of resource groups, other than
+	// the default group, tracked per tenant.
+	maxResourceGroups int
+	settings          *cluster.Settings
 
 	onAdmittedReplicatedWork onAdmittedReplicatedWork
 
@@ -327,6 +346,12 @@ type workQueueOptions struct {
 	usesTokens     bool
 	tiedToRange    bool
 	usesAsyncAdmit bool
+	// usesIOShares is true if resource groups are weighted by their IO share
+	// instead of their CPU share.
+	usesIOShares bool
+	// maxResourceGroups overrides admissionpb.MaxResourceGroups, if > 0. Used
+	// in tests.
+	maxResourceGroups int
 
 	// timeSource can be set to non-nil for tests. If nil,
 	// the timeutil.DefaultTimeSource will be used.
@@ -393,6 +418,11 @@ func initWorkQueue(
 	q.usesTokens = opts.usesTokens
 	q.tiedToRange = opts.tiedToRange
 	q.usesAsyncAdmit = opts.usesAsyncAdmit
+	q.usesIOShares = opts.usesIOShares
+	q.maxResourceGroups = admissionpb.MaxResourceGroups
+	if opts.maxResourceGroups > 0 {
+		q.maxResourceGroups = opts.maxResourceGroups
+	}
 	q.settings = settings
 	q.logThreshold = log.Every(5 * time.Minute)
 	q.metrics = metrics
@@ -427,8 +457,94 @@ func initWorkQueue(
 }
 
 func isInTenantHeap(tenant *tenantInfo) bool {
-	// If there is some waiting work, this tenant is in tenantHeap.
-	return len(tenant.waitingWorkHeap) > 0 || len(tenant.openEpochsHeap) > 0
+	// If there is some waiting work that can be granted, this tenant is in
+	// tenantHeap.
+	return len(tenant.groupHeap) > 0
+}
+
+// getGroupLocked returns the tenant's resource group with the given
+// definition, creating it if needed. Since work carries the latest definition
+// of its resource group, the weight and concurrency limit of an existing group
+// are updated to match the provided definition.
+//
+// Resource group definitions are supplied by tenants. Work of a group with an
+// invalid name, or of a group that would exceed the tenant's
+// maxResourceGroups tracked groups, is admitted in the default
+// group instead. Such names are mapped to the default group in
+// tenantInfo.groups, so that AdmittedWorkDone and adjustTenantUsed find the
+// group the work was admitted in.
+func (q *WorkQueue) getGroupLocked(
+	tenant *tenantInfo, rg admissionpb.ResourceGroup,
+) *resourceGroupInfo {
+	group, ok := tenant.groups[rg.Name]
+	if ok && group.name != rg.Name {
+		return group
+	}
+	weight := rg.CPUWeight()
+	if q.usesIOShares {
+		weight = rg.IOWeight()
+	}
+	var maxConcurrency int
+	if !q.usesTokens {
+		maxConcurrency = rg.ConcurrencyLimit()
+	}
+	if !ok {
+		if !rg.IsDefault() && (!admissionpb.IsValidResourceGroupName(rg.Name) ||
+			tenant.numGroups >= q.maxResourceGroups) {
+			group = q.getGroupLocked(tenant, admissionpb.ResourceGroup{})
+			tenant.groups[rg.Name] = group
+			return group
+		}
+		group = newResourceGroupInfo(rg.Name, weight, maxConcurrency)
+		if !rg.IsDefault() {
+			group.metrics = q.metrics.acquireGroupMetrics(tenant.id, rg.Name)
+			tenant.numGroups++
+		}
+		if tenant.groups == nil {
+			tenant.groups = make(map[string]*resourceGroupInfo)
+		}
+		tenant.groups[rg.Name] = group
+		return group
+	}
+	if group.weight != weight || group.maxConcurrency != maxConcurrency {
+		group.weight = weight
+		group.maxConcurrency = maxConcurrency
+		q.fixGroupLocked(tenant, group)
+	}
+	return group
+}
+
+// fixGroupLocked restores the ordering and membership of the tenant's group
+// heap, and of the tenant heap, after the state of the given resource group
+// (or of the tenant) changed.
+func (q *WorkQueue) fixGroupLocked(tenant *tenantInfo, group *resourceGroupInfo) {
+	if group.canGrant() {
+		if group.heapIndex == -1 {
+			heap.Push(&tenant.groupHeap, group)
+		} else {
+			tenant.groupHeap.fix(group)
+		}
+	} else if group.heapIndex != -1 {
+		tenant.groupHeap.remove(group)
+	}
+	if isInTenantHeap(tenant) {
+		if tenant.heapIndex == -1 {
+			heap.Push(&q.mu.tenantHeap, tenant)
+		} else {
+			q.mu.tenantHeap.fix(tenant)
+		}
+	} else if tenant.heapIndex != -1 {
+		q.mu.tenantHeap.remove(tenant)
+	}
+}
+
+// releaseGroupSlotLocked is called when a slot held by work of the given
+// resource group is returned.
+func (q *WorkQueue) releaseGroupSlotLocked(tenant *tenantInfo, group *resourceGroupInfo) {
+	if group.running > 0 {
+		group.running--
+	}
+	q.fixGroupLocked(tenant, group)
 }
 
 func (q *WorkQueue) timeNow() time.Time {
@@ -546,13 +662,15 @@ func (q *WorkQueue) tryCloseEpoch(timeNow time.Time) {
 		// makes them no longer subject to LIFO, but they will need to wait here
 		// until their epochs close. This is considered acceptable since the
 		// priority threshold should not fluctuate rapidly.
-		for len(tenant.openEpochsHeap) > 0 {
-			work := tenant.openEpochsHeap[0]
-			if work.epoch > epoch {
-				break
+		for _, group := range tenant.groups {
+			for len(group.openEpochsHeap) > 0 {
+				work := group.openEpochsHeap[0]
+				if work.epoch > epoch {
+					break
+				}
+				heap.Pop(&group.openEpochsHeap)
+				heap.Push(&group.waitingWorkHeap, work)
 			}
-			heap.Pop(&tenant.openEpochsHeap)
-			heap.Push(&tenant.waitingWorkHeap, work)
 		}
 	}
 }
@@ -563,10 +681,14 @@ func (q *WorkQueue) tryCloseEpoch(timeNow time.Time) {
 // admission control is enabled. AdmittedWorkDone must be called iff
 // enabled=true && err!=nil, and the WorkKind for this queue uses slots.
 func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err error) {
+	if info.ReplicatedWorkInfo.Enabled {
+		// The resource group of a replicated write is not known below raft.
+		info.ResourceGroup = admissionpb.ResourceGroup{}
+	}
 	if !info.ReplicatedWorkInfo.Enabled {
 		enabledSetting := admissionControlEnabledSettings[q.workKind]
 		if enabledSetting != nil && !enabledSetting.Get(&q.settings.SV) {
-			q.metrics.recordBypassedAdmission(info.Priority)
+			q.metrics.recordBypassedAdmission(info.Priority, nil /* group */)
 			return false, nil
 		}
 	}
@@ -583,7 +705,6 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 	if !q.usesTokens && info.RequestedCount != 1 {
 		panic(errors.AssertionFailedf("unexpected RequestedCount: %d", info.RequestedCount))
 	}
-	q.metrics.incRequested(info.Priority)
 	tenantID := info.TenantID.ToUint64()
 
 	// The code in this method does not use defer to unlock the mutexes because
@@ -612,16 +733,25 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 			panic("unexpected ReplicatedWrite.Enabled on slot-based queue")
 		}
 	}
+	group := q.getGroupLocked(tenant, info.ResourceGroup)
+	// The metrics of the group remain usable even if the group is GC'd while
+	// the work is admitted.
+	groupMetrics := group.metrics
+	q.metrics.incRequested(info.Priority, groupMetrics)
 	if info.BypassAdmission && roachpb.IsSystemTenantID(tenantID) && q.workKind == KVWork {
 		tenant.used += uint64(info.RequestedCount)
-		if isInTenantHeap(tenant) {
-			q.mu.tenantHeap.fix(tenant)
+		group.used += uint64(info.RequestedCount)
+		if !q.usesTokens {
+			// The slot is accounted for, even though bypassing work does not
+			// respect the group's concurrency limit.
+			group.running++
 		}
+		q.fixGroupLocked(tenant, group)
 		q.mu.Unlock()
 		q.admitMu.Unlock()
 		q.granter.tookWithoutPermission(info.RequestedCount)
-		q.metrics.incAdmitted(info.Priority)
-		q.metrics.recordBypassedAdmission(info.Priority)
+		q.metrics.incAdmitted(info.Priority, groupMetrics)
+		q.metrics.recordBypassedAdmission(info.Priority, groupMetrics)
 		return true, nil
 	}
 	// Work is subject to admission control.
@@ -631,14 +761,20 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 	// threshold for LIFO queueing based on observed admission latency.
 	tenant.priorityStates.requestAtPriority(info.Priority)
 
-	if len(q.mu.tenantHeap) == 0 && !q.knobs.DisableWorkQueueFastPath {
+	// NB: if the tenantHeap is empty, the group can only have waiting work if
+	// it is at its concurrency limit.
+	if len(q.mu.tenantHeap) == 0 && !group.atConcurrencyLimit() && !q.knobs.DisableWorkQueueFastPath {
 		// Fast-path. Try to grab token/slot.
 		// Optimistically update used to avoid locking again.
 		tenant.used += uint64(info.RequestedCount)
+		group.used += uint64(info.RequestedCount)
+		if !q.usesTokens {
+			group.running++
+		}
 		q.mu.Unlock()
 		if q.granter.tryGet(info.RequestedCount) {
 			q.admitMu.Unlock()
-			q.metrics.incAdmitted(info.Priority)
+			q.metrics.incAdmitted(info.Priority, groupMetrics)
 			if info.ReplicatedWorkInfo.Enabled {
 				// TODO(irfansharif): There's a race here, and could lead to
 				// over-admission. It's possible that there are enqueued work
@@ -667,7 +803,7 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 					false, /* coordMuLocked */
 				)
 			}
-			q.metrics.recordFastPathAdmission(info.Priority)
+			q.metrics.recordFastPathAdmission(info.Priority, groupMetrics)
 			return true, nil
 		}
 		// Did not get token/slot.
@@ -701,6 +837,16 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 		} else {
 			tenant.used = 0
 		}
+		// Same for the group, which could also have been removed.
+		group = q.getGroupLocked(tenant, info.ResourceGroup)
+		if group.used >= uint64(info.RequestedCount) {
+			group.used -= uint64(info.RequestedCount)
+		} else {
+			group.used = 0
+		}
+		if !q.usesTokens && group.running > 0 {
+			group.running--
+		}
 	}
 
 	// Check for cancellation.
@@ -713,7 +859,7 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 		// causing entering into the work queue to be delayed.
 		q.mu.Unlock()
 		q.admitMu.Unlock()
-		q.metrics.incErrored(info.Priority)
+		q.metrics.incErrored(info.Priority, groupMetrics)
 		deadline, _ := ctx.Deadline()
 		return true,
 			errors.Newf("work %s deadline already expired: deadline: %v, now: %v",
@@ -726,27 +872,27 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 	}
 	work := newWaitingWork(info.Priority, ordering, info.CreateTime, info.RequestedCount, startTime, q.mu.epochLengthNanos)
 	work.replicated = info.ReplicatedWorkInfo
+	work.group = group
 
-	inTenantHeap := isInTenantHeap(tenant)
 	if work.epoch <= q.mu.closedEpochThreshold || ordering == fifoWorkOrdering {
-		heap.Push(&tenant.waitingWorkHeap, work)
+		heap.Push(&group.waitingWorkHeap, work)
 	} else {
-		heap.Push(&tenant.openEpochsHeap, work)
-	}
-	if !inTenantHeap {
-		heap.Push(&q.mu.tenantHeap, tenant)
+		heap.Push(&group.openEpochsHeap, work)
 	}
-	// Else already in tenantHeap.
+	// Add the group and the tenant to their heaps, unless they are already
+	// there or the group is at its concurrency limit.
+	q.fixGroupLocked(tenant, group)
+	waitingWorkLen := group.waitingWorkHeap.Len()
 
 	// Release all locks.
 	q.mu.Unlock()
 	q.admitMu.Unlock()
 
-	q.metrics.recordStartWait(info.Priority)
+	q.metrics.recordStartWait(info.Priority, groupMetrics)
 	if info.ReplicatedWorkInfo.Enabled {
 		if log.V(1) {
 			log.Infof(ctx, "async-path: len(waiting-work)=%d: enqueued t%d pri=%s r%s origin=n%s log-position=%s ingested=%t",
-				tenant.waitingWorkHeap.Len(),
+				waitingWorkLen,
 				tenant.id, info.Priority,
 				info.ReplicatedWorkInfo.RangeID,
 				info.ReplicatedWorkInfo.Origin,
@@ -777,6 +923,11 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 			// goroutine that sets used=0 and could have GC'd tenant and returned it
 			// to the sync.Pool. We can fix this if needed by calling
 			// adjustTenantUsedLocked.
+			if !q.usesTokens {
+				// The group holds the slot that is returned below. The group, and
+				// thereby the tenant, can't have been GC'd while holding it.
+				q.releaseGroupSlotLocked(tenant, work.group)
+			}
 			q.mu.Unlock()
 			q.granter.returnGrant(info.RequestedCount)
 			// The channel is sent to after releasing mu, so we don't need to hold
@@ -787,17 +938,15 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 			q.granter.continueGrantChain(chainID)
 		} else {
 			if work.inWaitingWorkHeap {
-				tenant.waitingWorkHeap.remove(work)
+				work.group.waitingWorkHeap.remove(work)
 			} else {
-				tenant.openEpochsHeap.remove(work)
-			}
-			if !isInTenantHeap(tenant) {
-				q.mu.tenantHeap.remove(tenant)
+				work.group.openEpochsHeap.remove(work)
 			}
+			q.fixGroupLocked(tenant, work.group)
 			q.mu.Unlock()
 		}
-		q.metrics.incErrored(info.Priority)
-		q.metrics.recordFinishWait(info.Priority, waitDur)
+		q.metrics.incErrored(info.Priority, groupMetrics)
+		q.metrics.recordFinishWait(info.Priority, groupMetrics, waitDur)
 		deadline, _ := ctx.Deadline()
 		log.Eventf(ctx, "deadline expired, waited in %s queue for %v",
 			workKindString(q.workKind), waitDur)
@@ -808,9 +957,9 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 		if !ok {
 			panic(errors.AssertionFailedf("channel should not be closed"))
 		}
-		q.metrics.incAdmitted(info.Priority)
+		q.metrics.incAdmitted(info.Priority, groupMetrics)
 		waitDur := q.timeNow().Sub(startTime)
-		q.metrics.recordFinishWait(info.Priority, waitDur)
+		q.metrics.recordFinishWait(info.Priority, groupMetrics, waitDur)
 		if work.heapIndex != -1 {
 			panic(errors.AssertionFailedf("grantee should be removed from heap"))
 		}
@@ -824,17 +973,33 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 // finished. It must be called iff the WorkKind of this WorkQueue uses slots
 // (not tokens), i.e., KVWork, SQLStatementLeafStartWork,
 // SQLStatementRootStartWork. Note, there is no support for SQLStatementLeafStartWork,
-// SQLStatementRootStartWork in the code yet.
-func (q *WorkQueue) AdmittedWorkDone(tenantID roachpb.TenantID, cpuTime time.Duration) {
+// SQLStatementRootStartWork in the code yet. The resourceGroup is the name of
+// the resource group the work was admitted in.
+func (q *WorkQueue) AdmittedWorkDone(
+	tenantID roachpb.TenantID, resourceGroup string, cpuTime time.Duration,
+) {
 	if q.usesTokens {
 		panic(errors.AssertionFailedf("tokens should not be returned"))
 	}
 	// Single slot is allocated for the work in the granter, and tenant.used was
 	// incremented by 1.
 	additionalUsed := cpuTime - 1
-	if additionalUsed != 0 {
-		q.adjustTenantUsed(tenantID, additionalUsed.Nanoseconds())
-	}
+	func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		tenant, ok := q.mu.tenants[tenantID.ToUint64()]
+		if !ok {
+			return
+		}
+		group, ok := tenant.groups[resourceGroup]
+		if !ok {
+			return
+		}
+		if additionalUsed != 0 {
+			q.adjustTenantUsedLocked(tenant, group, additionalUsed.Nanoseconds())
+		}
+		q.releaseGroupSlotLocked(tenant, group)
+	}()
 	q.granter.returnGrant(1)
 }
 
@@ -857,24 +1022,26 @@ func (q *WorkQueue) granted(grantChainID grantChainID) int64 {
 		return 0
 	}
 	tenant := q.mu.tenantHeap[0]
+	group := tenant.groupHeap[0]
 	var item *waitingWork
-	if len(tenant.waitingWorkHeap) > 0 {
-		item = heap.Pop(&tenant.waitingWorkHeap).(*waitingWork)
+	if len(group.waitingWorkHeap) > 0 {
+		item = heap.Pop(&group.waitingWorkHeap).(*waitingWork)
 	} else {
-		item = heap.Pop(&tenant.openEpochsHeap).(*waitingWork)
+		item = heap.Pop(&group.openEpochsHeap).(*waitingWork)
 	}
 	waitDur := now.Sub(item.enqueueingTime)
 	tenant.priorityStates.updateDelayLocked(item.priority, waitDur, false /* canceled */)
 	tenant.used += uint64(item.requestedCount)
-	if isInTenantHeap(tenant) {
-		q.mu.tenantHeap.fix(tenant)
-	} else {
-		q.mu.tenantHeap.remove(tenant)
+	group.used += uint64(item.requestedCount)
+	if !q.usesTokens {
+		group.running++
 	}
+	q.fixGroupLocked(tenant, group)
 	// Get the value of requestedCount before releasing the mutex, since after
 	// releasing Admit can notice that item is no longer in the heap and call
 	// releaseWaitingWork to return item to the waitingWorkPool.
 	requestedCount := item.requestedCount
+	waitingWorkLen := group.waitingWorkHeap.Len()
 	q.mu.Unlock()
 
 	if !item.replicated.Enabled {
@@ -885,7 +1052,7 @@ func (q *WorkQueue) granted(grantChainID grantChainID) int64 {
 		// to replicated writes.
 		if log.V(1) {
 			log.Infof(q.ambientCtx, "async-path: len(waiting-work)=%d dequeued t%d pri=%s r%s origin=n%s log-position=%s ingested=%t",
-				tenant.waitingWorkHeap.Len(),
+				waitingWorkLen,
 				tenant.id, item.priority,
 				item.replicated.RangeID,
 				item.replicated.Origin,
@@ -903,9 +1070,10 @@ func (q *WorkQueue) granted(grantChainID grantChainID) int64 {
 			true, /* coordMuLocked */
 		)
 
-		q.metrics.incAdmitted(item.priority)
+		// Replicated writes are always admitted in the default resource group.
+		q.metrics.incAdmitted(item.priority, nil /* group */)
 		waitDur := q.timeNow().Sub(item.enqueueingTime)
-		q.metrics.recordFinishWait(item.priority, waitDur)
+		q.metrics.recordFinishWait(item.priority, nil /* group */, waitDur)
 		if item.heapIndex != -1 {
 			panic(errors.AssertionFailedf("grantee should be removed from heap"))
 		}
@@ -920,7 +1088,30 @@ func (q *WorkQueue) gcTenantsAndResetUsed() {
 	// longer than desired. We could break this iteration into smaller parts if
 	// needed.
 	for id, info := range q.mu.tenants {
-		if info.used == 0 && !isInTenantHeap(info) {
+		// Names mapped to the default group (see getGroupLocked) are removed
+		// together with it, before it is released below.
+		for name, group := range info.groups {
+			if group.name != name && group.used == 0 && group.running == 0 && !group.hasWaitingWork() {
+				delete(info.groups, name)
+			}
+		}
+		for name, group := range info.groups {
+			if group.name != name {
+				continue
+			}
+			if group.used == 0 && group.running == 0 && !group.hasWaitingWork() {
+				delete(info.groups, name)
+				if group.metrics != nil {
+					q.metrics.releaseGroupMetrics(group.metrics)
+					info.numGroups--
+				}
+				releaseResourceGroupInfo(group)
+			} else {
+				group.used = 0
+			}
+		}
+		// A tenant without groups has no waiting work.
+		if info.used == 0 && len(info.groups) == 0 {
 			delete(q.mu.tenants, id)
 			releaseTenantInfo(info)
 		} else {
@@ -931,11 +1122,14 @@ func (q *WorkQueue) gcTenantsAndResetUsed() {
 	}
 }
 
-// adjustTenantUsed is used internally by StoreWorkQueue, and by the KV queue
-// in AdmittedWorkDone. The additionalUsed count can be negative, in which
-// case it is returning unused resources. This is only for WorkQueue's own
-// accounting -- it should not call into granter.
-func (q *WorkQueue) adjustTenantUsed(tenantID roachpb.TenantID, additionalUsed int64) {
+// adjustTenantUsed is used internally by StoreWorkQueue, and by the elastic
+// CPU work queue. The additionalUsed count can be negative, in which case it
+// is returning unused resources. It is charged to the tenant and to the named
+// resource group of the tenant. This is only for WorkQueue's own accounting --
+// it should not call into granter.
+func (q *WorkQueue) adjustTenantUsed(
+	tenantID roachpb.TenantID, resourceGroup string, additionalUsed int64,
+) {
 	tid := tenantID.ToUint64()
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -943,19 +1137,36 @@ func (q *WorkQueue) adjustTenantUsed(tenantID roachpb.TenantID, additionalUsed i
 	if !ok {
 		return
 	}
-	if additionalUsed < 0 {
-		toReturn := uint64(-additionalUsed)
-		if tenant.used < toReturn {
-			tenant.used = 0
+	group := tenant.groups[resourceGroup]
+	q.adjustTenantUsedLocked(tenant, group, additionalUsed)
+}
+
+// adjustTenantUsedLocked is like adjustTenantUsed. The group can be nil, if
+// it was GC'd.
+func (q *WorkQueue) adjustTenantUsedLocked(
+	tenant *tenantInfo, group *resourceGroupInfo, additionalUsed int64,
+) {
+	adjust := func(used *uint64) {
+		if additionalUsed < 0 {
+			toReturn := uint64(-additionalUsed)
+			if *used < toReturn {
+				*used = 0
+			} else {
+				*used -= toReturn
+			}
 		} else {
-			tenant.used -= toReturn
+			*used += uint64(additionalUsed)
 		}
-	} else {
-		tenant.used += uint64(additionalUsed)
 	}
-	if isInTenantHeap(tenant) {
-		q.mu.tenantHeap.fix(tenant)
+	adjust(&tenant.used)
+	if group == nil {
+		if isInTenantHeap(tenant) {
+			q.mu.tenantHeap.fix(tenant)
+		}
+		return
 	}
+	adjust(&group.used)
+	q.fixGroupLocked(tenant, group)
 }
 
 func (q *WorkQueue) String() string {
@@ -980,28 +1191,48 @@ func (q *WorkQueue) SafeFormat(s redact.SafePrinter, _ rune) {
 		tenant := q.mu.tenants[id]
 		s.Printf("\n tenant-id: %d used: %d, w: %d, fifo: %d", tenant.id, tenant.used,
 			tenant.weight, tenant.fifoPriorityThreshold)
-		if len(tenant.waitingWorkHeap) > 0 {
-			s.Printf(" waiting work heap:")
-			for i := range tenant.waitingWorkHeap {
-				var workOrdering string
-				if tenant.waitingWorkHeap[i].arrivalTimeWorkOrdering == lifoWorkOrdering {
-					workOrdering = ", lifo-ordering"
+		var names []string
+		for name := range tenant.groups {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			group := tenant.groups[name]
+			if name != group.name {
+				// Mapped to the default group.
+				continue
+			}
+			// The default group is printed as part of the tenant.
+			if name != "" {
+				s.Printf("\n  group: %s used: %d, w: %d, running: %d", redact.SafeString(name),
+					group.used, group.weight, group.running)
+				if group.maxConcurrency > 0 {
+					s.Printf(", max-concurrency: %d", group.maxConcurrency)
 				}
-				s.Printf(" [%d: pri: %d, ct: %d, epoch: %d, qt: %d%s]", i,
-					tenant.waitingWorkHeap[i].priority,
-					tenant.waitingWorkHeap[i].createTime/int64(time.Millisecond),
-					tenant.waitingWorkHeap[i].epoch,
-					tenant.waitingWorkHeap[i].enqueueingTime.UnixNano()/int64(time.Millisecond), workOrdering)
 			}
-		}
-		if len(tenant.openEpochsHeap) > 0 {
-			s.Printf(" open epochs heap:")
-			for i := range tenant.openEpochsHeap {
-				s.Printf(" [%d: pri: %d, ct: %d, epoch: %d, qt: %d]", i,
-					tenant.openEpochsHeap[i].priority,
-					tenant.openEpochsHeap[i].createTime/int64(time.Millisecond),
-					tenant.openEpochsHeap[i].epoch,
-					tenant.openEpochsHeap[i].enqueueingTime.UnixNano()/int64(time.Millisecond))
+			if len(group.waitingWorkHeap) > 0 {
+				s.Printf(" waiting work heap:")
+				for i := range group.waitingWorkHeap {
+					var workOrdering string
+					if group.waitingWorkHeap[i].arrivalTimeWorkOrdering == lifoWorkOrdering {
+						workOrdering = ", lifo-ordering"
+					}
+					s.Printf(" [%d: pri: %d, ct: %d, epoch: %d, qt: %d%s]", i,
+						group.waitingWorkHeap[i].priority,
+						group.waitingWorkHeap[i].createTime/int64(time.Millisecond),
+						group.waitingWorkHeap[i].epoch,
+						group.waitingWorkHeap[i].enqueueingTime.UnixNano()/int64(time.Millisecond), workOrdering)
+				}
+			}
+			if len(group.openEpochsHeap) > 0 {
+				s.Printf(" open epochs heap:")
+				for i := range group.openEpochsHeap {
+					s.Printf(" [%d: pri: %d, ct: %d, epoch: %d, qt: %d]", i,
+						group.openEpochsHeap[i].priority,
+						group.openEpochsHeap[i].createTime/int64(time.Millisecond),
+						group.openEpochsHeap[i].epoch,
+						group.openEpochsHeap[i].enqueueingTime.UnixNano()/int64(time.Millisecond))
+				}
 			}
 		}
 	}
@@ -1277,8 +1508,7 @@ type tenantInfo struct {
 	//   that will be consumed is deducted at admission time, and a correction
 	//   is applied later.
 	//
-	// tenantInfo will not be GC'd until both used==0 and
-	// len(waitingWorkHeap)==0.
+	// tenantInfo will not be GC'd until both used==0 and len(groups)==0.
 	//
 	// The used value is reset to 0 periodically. This creates a risk since
 	// callers of Admit hold references to tenantInfo. We do not want a race
@@ -1297,9 +1527,18 @@ type tenantInfo struct {
 	// simply (a) do not do used--, if used is already zero, or (b) do not do
 	// used-- if the request was canceled. This does imply some inaccuracy in
 	// accounting -- it can be fixed if needed.
-	used            uint64
-	waitingWorkHeap waitingWorkHeap
-	openEpochsHeap  openEpochsHeap
+	used uint64
+	// groups contains the resource groups of the tenant that have waiting work,
+	// hold slots, or have a non-zero used value. The map is lazily allocated.
+	// Names of groups whose work is admitted in the default group map to the
+	// default group.
+	groups map[string]*resourceGroupInfo
+	// numGroups is the number of resource groups in groups, other than the
+	// default group.
+	numGroups int
+	// groupHeap contains the groups with waiting work that can be granted
+	// admission, i.e., that are not at their concurrency limit.
+	groupHeap resourceGroupHeap
 
 	priorityStates priorityStates
 	// priority >= fifoPriorityThreshold is FIFO. This uses a larger sized type
@@ -1329,8 +1568,8 @@ func newTenantInfo(id uint64, weight uint32) *tenantInfo {
 	*ti = tenantInfo{
 		id:                    id,
 		weight:                weight,
-		waitingWorkHeap:       ti.waitingWorkHeap,
-		openEpochsHeap:        ti.openEpochsHeap,
+		groups:                ti.groups,
+		groupHeap:             ti.groupHeap,
 		priorityStates:        makePriorityStates(ti.priorityStates.ps),
 		fifoPriorityThreshold: int(admissionpb.LowPri),
 		heapIndex:             -1,
@@ -1339,22 +1578,20 @@ func newTenantInfo(id uint64, weight uint32) *tenantInfo {
 }
 
 func releaseTenantInfo(ti *tenantInfo) {
-	if isInTenantHeap(ti) {
-		panic("tenantInfo has non-empty heap")
-	}
-	// NB: {waitingWorkHeap,openEpochsHeap}.Pop nil the slice elements when
-	// removing, so we are not inadvertently holding any references.
-	if cap(ti.waitingWorkHeap) > 100 {
-		ti.waitingWorkHeap = nil
+	if len(ti.groups) > 0 {
+		panic("tenantInfo has resource groups")
 	}
-	if cap(ti.openEpochsHeap) > 100 {
-		ti.openEpochsHeap = nil
+	// NB: the groups map is empty and resourceGroupHeap.Pop nils the slice
+	// elements when removing, so we are not inadvertently holding any
+	// references.
+	if cap(ti.groupHeap) > 100 {
+		ti.groupHeap = nil
 	}
 
 	*ti = tenantInfo{
-		waitingWorkHeap: ti.waitingWorkHeap,
-		openEpochsHeap:  ti.openEpochsHeap,
-		priorityStates:  makePriorityStates(ti.priorityStates.ps),
+		groups:         ti.groups,
+		groupHeap:      ti.groupHeap,
+		priorityStates: makePriorityStates(ti.priorityStates.ps),
 	}
 	tenantInfoPool.Put(ti)
 }
@@ -1399,6 +1636,132 @@ func (th *tenantHeap) Pop() interface{} {
 	return item
 }
 
+// resourceGroupInfo is the per resource group information of a tenant. The
+// waiting work of a tenant is queued in the heaps of its resource group.
+type resourceGroupInfo struct {
+	// name is the name of the resource group, which is empty for the default
+	// group.
+	name string
+	// The weight assigned to the group. Must be > 0.
+	weight uint32
+	// maxConcurrency, if > 0, is the maximum number of slots the group's work
+	// can hold. It is only used by slot based queues.
+	maxConcurrency int
+	// used is the equivalent of tenantInfo.used for the group. It is reset
+	// at the same time.
+	used uint64
+	// running is the number of slots held by the group's work, for slot based
+	// queues. A group is not GC'd while it holds slots.
+	running         int
+	waitingWorkHeap waitingWorkHeap
+	openEpochsHeap  openEpochsHeap
+	// metrics are the metrics of the group. Nil for the default group.
+	metrics *resourceGroupMetrics
+
+	// The heapIndex is maintained by the heap.Interface methods, and represents
+	// the heapIndex of the item in the tenant's groupHeap. -1 when not in the
+	// heap.
+	heapIndex int
+}
+
+func (g *resourceGroupInfo) hasWaitingWork() bool {
+	return len(g.waitingWorkHeap) > 0 || len(g.openEpochsHeap) > 0
+}
+
+func (g *resourceGroupInfo) atConcurrencyLimit() bool {
+	return g.maxConcurrency > 0 && g.running >= g.maxConcurrency
+}
+
+// canGrant returns whether the group belongs in the tenant's groupHeap.
+func (g *resourceGroupInfo) canGrant() bool {
+	return g.hasWaitingWork() && !g.atConcurrencyLimit()
+}
+
+var resourceGroupInfoPool = sync.Pool{
+	New: func() interface{} {
+		return &resourceGroupInfo{}
+	},
+}
+
+func newResourceGroupInfo(name string, weight uint32, maxConcurrency int) *resourceGroupInfo {
+	g := resourceGroupInfoPool.Get().(*resourceGroupInfo)
+	*g = resourceGroupInfo{
+		name:            name,
+		weight:          weight,
+		maxConcurrency:  maxConcurrency,
+		waitingWorkHeap: g.waitingWorkHeap,
+		openEpochsHeap:  g.openEpochsHeap,
+		heapIndex:       -1,
+	}
+	return g
+}
+
+func releaseResourceGroupInfo(g *resourceGroupInfo) {
+	if g.hasWaitingWork() || g.heapIndex != -1 {
+		panic("resourceGroupInfo has waiting work")
+	}
+	// NB: {waitingWorkHeap,openEpochsHeap}.Pop nil the slice elements when
+	// removing, so we are not inadvertently holding any references.
+	if cap(g.waitingWorkHeap) > 100 {
+		g.waitingWorkHeap = nil
+	}
+	if cap(g.openEpochsHeap) > 100 {
+		g.openEpochsHeap = nil
+	}
+	*g = resourceGroupInfo{
+		waitingWorkHeap: g.waitingWorkHeap,
+		openEpochsHeap:  g.openEpochsHeap,
+	}
+	resourceGroupInfoPool.Put(g)
+}
+
+// resourceGroupHeap is a heap of the resource groups of a tenant with waiting
+// work that can be granted, ordered in increasing order of
+// resourceGroupInfo.used/resourceGroupInfo.weight, like the tenantHeap.
+type resourceGroupHeap []*resourceGroupInfo
+
+var _ heap.Interface = (*resourceGroupHeap)(nil)
+
+func (gh *resourceGroupHeap) fix(item *resourceGroupInfo) {
+	heap.Fix(gh, item.heapIndex)
+}
+
+func (gh *resourceGroupHeap) remove(item *resourceGroupInfo) {
+	heap.Remove(gh, item.heapIndex)
+}
+
+func (gh *resourceGroupHeap) Len() int {
+	return len(*gh)
+}
+
+func (gh *resourceGroupHeap) Less(i, j int) bool {
+	// used_i/weight_i < used_j/weight_j
+	return (*gh)[i].used*uint64((*gh)[j].weight) < (*gh)[j].used*uint64((*gh)[i].weight)
+}
+
+func (gh *resourceGroupHeap) Swap(i, j int) {
+	(*gh)[i], (*gh)[j] = (*gh)[j], (*gh)[i]
+	(*gh)[i].heapIndex = i
+	(*gh)[j].heapIndex = j
+}
+
+func (gh *resourceGroupHeap) Push(x interface{}) {
+	n := len(*gh)
+	item := x.(*resourceGroupInfo)
+	item.heapIndex = n
+	*gh = append(*gh, item)
+}
+
+func (gh *resourceGroupHeap) Pop() interface{} {
+	old := *gh
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.heapIndex = -1
+	*gh = old[0 : n-1]
+	return item
+}
+
 // waitingWork is the per-work information in the waitingWorkHeap.
 type waitingWork struct {
 	priority admissionpb.WorkPriority
@@ -1424,6 +1787,8 @@ type waitingWork struct {
 	inWaitingWorkHeap bool
 	enqueueingTime    time.Time
 	replicated        ReplicatedWorkInfo
+	// group is the resource group in whose heaps the work is queued.
+	group *resourceGroupInfo
 }
 
 var waitingWorkPool = sync.Pool{
@@ -1528,11 +1893,11 @@ func releaseWaitingWork(ww *waitingWork) {
 	waitingWorkPool.Put(ww)
 }
 
-// waitingWorkHeap is a heap of waiting work within a tenant. It is ordered in
-// decreasing order of priority, and within the same priority in increasing
-// order of createTime (to prefer older work) for FIFO, and in decreasing
-// order of createTime for LIFO. In the LIFO case the heap only contains
-// epochs that are closed.
+// waitingWorkHeap is a heap of waiting work within a resource group of a
+// tenant. It is ordered in decreasing order of priority, and within the same
+// priority in increasing order of createTime (to prefer older work) for FIFO,
+// and in decreasing order of createTime for LIFO. In the LIFO case the heap
+// only contains epochs that are closed.
 type waitingWorkHeap []*waitingWork
 
 var _ heap.Interface = (*waitingWorkHeap)(nil)
@@ -1600,8 +1965,8 @@ func (wwh *waitingWorkHeap) Pop() interface{} {
 	return item
 }
 
-// openEpochsHeap is a heap of waiting work within a tenant that will be
-// subject to LIFO ordering (when transferred to the waitingWorkHeap) and
+// openEpochsHeap is a heap of waiting work within a resource group that will
+// be subject to LIFO ordering (when transferred to the waitingWorkHeap) and
 // whose epoch is not yet closed. See the Less method for the ordering applied
 // here.
 type openEpochsHeap []*waitingWork
@@ -1706,7 +2071,16 @@ type WorkQueueMetrics struct {
 	name       string
 	total      workQueueMetricsSingle
 	byPriority sync.Map
-	registry   *metric.Registry
+	// byResourceGroup aggregates the metrics of the resource groups, other than
+	// the default group, tracked by the WorkQueues using these metrics. Each
+	// tracked group has child metrics labeled with its tenant and name, which
+	// are removed once no WorkQueue tracks the group anymore.
+	byResourceGroup resourceGroupAggMetrics
+	groupsMu        struct {
+		syncutil.Mutex
+		groups map[resourceGroupKey]*resourceGroupMetrics
+	}
+	registry *metric.Registry
 }
 
 // getOrCreate will return the metric if it exists or create it and then return
@@ -1732,6 +2106,85 @@ func (m *WorkQueueMetrics) getOrCreate(priority admissionpb.WorkPriority) workQu
 	return val.(workQueueMetricsSingle)
 }
 
+// resourceGroupKey identifies a resource group of a tenant.
+type resourceGroupKey struct {
+	tenantID uint64
+	name     string
+}
+
+// resourceGroupAggMetrics are the aggregates of the per resource group
+// metrics. Unlike the metrics of priorities, which are registered when they
+// are first used, they are registered upfront, since resource groups are
+// created at runtime.
+type resourceGroupAggMetrics struct {
+	Requested       *aggmetric.AggCounter
+	Admitted        *aggmetric.AggCounter
+	Errored         *aggmetric.AggCounter
+	WaitDurations   *aggmetric.AggHistogram
+	WaitQueueLength *aggmetric.AggGauge
+}
+
+// resourceGroupMetrics are the metrics of a resource group of a tenant.
+type resourceGroupMetrics struct {
+	key resourceGroupKey
+	// refs is the number of WorkQueues tracking the resource group. Protected
+	// by WorkQueueMetrics.groupsMu.
+	refs int
+
+	requested       *aggmetric.Counter
+	admitted        *aggmetric.Counter
+	errored         *aggmetric.Counter
+	waitDurations   *aggmetric.Histogram
+	waitQueueLength *aggmetric.Gauge
+}
+
+// acquireGroupMetrics returns the metrics of the given resource group, adding
+// them if the group isn't tracked by any other WorkQueue sharing these
+// metrics. The group must be a valid non-default resource group, and every
+// call must be paired with a call to releaseGroupMetrics.
+func (m *WorkQueueMetrics) acquireGroupMetrics(tenantID uint64, name string) *resourceGroupMetrics {
+	m.groupsMu.Lock()
+	defer m.groupsMu.Unlock()
+	key := resourceGroupKey{tenantID: tenantID, name: name}
+	gm, ok := m.groupsMu.groups[key]
+	if !ok {
+		tid := strconv.FormatUint(tenantID, 10)
+		gm = &resourceGroupMetrics{
+			key:             key,
+			requested:       m.byResourceGroup.Requested.AddChild(tid, name),
+			admitted:        m.byResourceGroup.Admitted.AddChild(tid, name),
+			errored:         m.byResourceGroup.Errored.AddChild(tid, name),
+			waitDurations:   m.byResourceGroup.WaitDurations.AddChild(tid, name),
+			waitQueueLength: m.byResourceGroup.WaitQueueLength.AddChild(tid, name),
+		}
+		if m.groupsMu.groups == nil {
+			m.groupsMu.groups = make(map[resourceGroupKey]*resourceGroupMetrics)
+		}
+		m.groupsMu.groups[key] = gm
+	}
+	gm.refs++
+	return gm
+}
+
+// releaseGroupMetrics is called when a WorkQueue stops tracking a resource
+// group. The child metrics of the group are unlinked once no WorkQueue tracks
+// it. They remain usable by work that is still finishing up, which then only
+// affects the aggregates.
+func (m *WorkQueueMetrics) releaseGroupMetrics(gm *resourceGroupMetrics) {
+	m.groupsMu.Lock()
+	defer m.groupsMu.Unlock()
+	gm.refs--
+	if gm.refs > 0 {
+		return
+	}
+	delete(m.groupsMu.groups, gm.key)
+	gm.requested.Unlink()
+	gm.admitted.Unlink()
+	gm.errored.Unlink()
+	gm.waitDurations.Unlink()
+	gm.waitQueueLength.Unlink()
+}
+
 type workQueueMetricsSingle struct {
 	Requested       *metric.Counter
 	Admitted        *metric.Counter
@@ -1740,36 +2193,55 @@ type workQueueMetricsSingle struct {
 	WaitQueueLength *metric.Gauge
 }
 
-func (m *WorkQueueMetrics) incRequested(priority admissionpb.WorkPriority) {
+func (m *WorkQueueMetrics) incRequested(priority admissionpb.WorkPriority, group *resourceGroupMetrics) {
 	m.total.Requested.Inc(1)
 	m.getOrCreate(priority).Requested.Inc(1)
+	if group != nil {
+		group.requested.Inc(1)
+	}
 }
 
-func (m *WorkQueueMetrics) incAdmitted(priority admissionpb.WorkPriority) {
+func (m *WorkQueueMetrics) incAdmitted(priority admissionpb.WorkPriority, group *resourceGroupMetrics) {
 	m.total.Admitted.Inc(1)
 	m.getOrCreate(priority).Admitted.Inc(1)
+	if group != nil {
+		group.admitted.Inc(1)
+	}
 }
 
-func (m *WorkQueueMetrics) incErrored(priority admissionpb.WorkPriority) {
+func (m *WorkQueueMetrics) incErrored(priority admissionpb.WorkPriority, group *resourceGroupMetrics) {
 	m.total.Errored.Inc(1)
 	m.getOrCreate(priority).Errored.Inc(1)
+	if group != nil {
+		group.errored.Inc(1)
+	}
 }
 
-func (m *WorkQueueMetrics) recordStartWait(priority admissionpb.WorkPriority) {
+func (m *WorkQueueMetrics) recordStartWait(priority admissionpb.WorkPriority, group *resourceGroupMetrics) {
 	m.total.WaitQueueLength.Inc(1)
 	m.getOrCreate(priority).WaitQueueLength.Inc(1)
+	if group != nil {
+		group.waitQueueLength.Inc(1)
+	}
 }
 
-func (m *WorkQueueMetrics) recordFinishWait(priority admissionpb.WorkPriority, dur time.Duration) {
+func (m *WorkQueueMetrics) recordFinishWait(
+	priority admissionpb.WorkPriority, group *resourceGroupMetrics, dur time.Duration,
+) {
 	m.total.WaitQueueLength.Dec(1)
 	m.total.WaitDurations.RecordValue(dur.Nanoseconds())
 
 	priorityStats := m.getOrCreate(priority)
 	priorityStats.WaitQueueLength.Dec(1)
 	priorityStats.WaitDurations.RecordValue(dur.Nanoseconds())
+
+	if group != nil {
+		group.waitQueueLength.Dec(1)
+		group.waitDurations.RecordValue(dur.Nanoseconds())
+	}
 }
 
-func (m *WorkQueueMetrics) recordBypassedAdmission(priority admissionpb.WorkPriority) {
+func (m *WorkQueueMetrics) recordBypassedAdmission(priority admissionpb.WorkPriority, group *resourceGroupMetrics) {
 	// For work that either bypasses admission queues (because of the nature of
 	// the work itself or because certain queues are disabled), we'll explicit
 	// record a zero wait duration so that the histogram percentiles remain
@@ -1777,9 +2249,12 @@ func (m *WorkQueueMetrics) recordBypassedAdmission(priority admissionpb.WorkPrio
 	m.total.WaitDurations.RecordValue(0)
 	priorityStats := m.getOrCreate(priority)
 	priorityStats.WaitDurations.RecordValue(0)
+	if group != nil {
+		group.waitDurations.RecordValue(0)
+	}
 }
 
-func (m *WorkQueueMetrics) recordFastPathAdmission(priority admissionpb.WorkPriority) {
+func (m *WorkQueueMetrics) recordFastPathAdmission(priority admissionpb.WorkPriority, group *resourceGroupMetrics) {
 	// Explicitly record a zero wait queue duration when we're able to acquire
 	// tokens/slots without needing to add ourselves to tenant heaps. Explicitly
 	// recording zeros ensure that our histograms are accurate with respect to
@@ -1787,6 +2262,9 @@ func (m *WorkQueueMetrics) recordFastPathAdmission(priority admissionpb.WorkPrio
 	m.total.WaitDurations.RecordValue(0)
 	priorityStats := m.getOrCreate(priority)
 	priorityStats.WaitDurations.RecordValue(0)
+	if group != nil {
+		group.waitDurations.RecordValue(0)
+	}
 }
 
 // MetricStruct implements the metric.Struct interface.
@@ -1797,10 +2275,13 @@ func makeWorkQueueMetrics(
 ) *WorkQueueMetrics {
 	totalMetric := makeWorkQueueMetricsSingle(name)
 	registry.AddMetricStruct(totalMetric)
+	groupMetrics := makeResourceGroupAggMetrics(name + ".group")
+	registry.AddMetricStruct(groupMetrics)
 	wqm := &WorkQueueMetrics{
-		name:     name,
-		total:    totalMetric,
-		registry: registry,
+		name:            name,
+		total:           totalMetric,
+		byResourceGroup: groupMetrics,
+		registry:        registry,
 	}
 	// TODO(abaptist): This is done to pre-register stats. Need to check that we
 	// getOrCreate "enough" of the priorities to be useful. See
@@ -1827,6 +2308,31 @@ func makeWorkQueueMetricsSingle(name string) workQueueMetricsSingle {
 	}
 }
 
+// resourceGroupLabel is the label of the per resource group metrics that
+// contains the name of the group. The tenant of the group is in the
+// tenantIDLabel.
+const resourceGroupLabel = "resource_group"
+
+// tenantIDLabel is the label used with metrics associated with a tenant. It
+// matches multitenant.TenantIDLabel.
+const tenantIDLabel = "tenant_id"
+
+func makeResourceGroupAggMetrics(name string) resourceGroupAggMetrics {
+	b := aggmetric.MakeBuilder(tenantIDLabel, resourceGroupLabel)
+	return resourceGroupAggMetrics{
+		Requested: b.Counter(addName(name, requestedMeta)),
+		Admitted:  b.Counter(addName(name, admittedMeta)),
+		Errored:   b.Counter(addName(name, erroredMeta)),
+		WaitDurations: b.Histogram(metric.HistogramOptions{
+			Mode:         metric.HistogramModePreferHdrLatency,
+			Metadata:     addName(name, waitDurationsMeta),
+			Duration:     base.DefaultHistogramWindowInterval(),
+			BucketConfig: metric.IOLatencyBuckets,
+		}),
+		WaitQueueLength: b.Gauge(addName(name, waitQueueLengthMeta)),
+	}
+}
+
 // StoreWriteWorkInfo is the information that needs to be provided for work
 // seeking admission from a StoreWorkQueue.
 type StoreWriteWorkInfo struct {
@@ -1871,7 +2377,8 @@ type StoreWorkQueue struct {
 // needed by the caller (see StoreWorkHandle.UseAdmittedWorkDone) and by
 // StoreWorkQueue.AdmittedWorkDone.
 type StoreWorkHandle struct {
-	tenantID roachpb.TenantID
+	tenantID      roachpb.TenantID
+	resourceGroup string
 	// The writeTokens acquired by this request. Must be > 0.
 	writeTokens         int64
 	workClass           admissionpb.WorkClass
@@ -1924,6 +2431,7 @@ func (q *StoreWorkQueue) Admit(
 
 	h := StoreWorkHandle{
 		tenantID:            info.TenantID,
+		resourceGroup:       info.ResourceGroup.Name,
 		workClass:           wc,
 		writeTokens:         info.RequestedCount,
 		useAdmittedWorkDone: enabled,
@@ -2029,7 +2537,8 @@ func (q *StoreWorkQueue) admittedReplicatedWork(
 	if !coordMuLocked {
 		q.coordMu.Unlock()
 	}
-	q.q[wc].adjustTenantUsed(tenantID, additionalTokensNeeded)
+	// Replicated writes are always admitted in the default resource group.
+	q.q[wc].adjustTenantUsed(tenantID, "" /* resourceGroup */, additionalTokensNeeded)
 
 	// Inform callers of the entry we just admitted.
 	//
@@ -2074,7 +2583,7 @@ func (q *StoreWorkQueue) AdmittedWorkDone(h StoreWorkHandle, doneInfo StoreWorkD
 	}
 	q.updateStoreStatsAfterWorkDone(1, doneInfo, false)
 	additionalTokens := q.granters[h.workClass].storeWriteDone(h.writeTokens, doneInfo)
-	q.q[h.workClass].adjustTenantUsed(h.tenantID, additionalTokens)
+	q.q[h.workClass].adjustTenantUsed(h.tenantID, h.resourceGroup, additionalTokens)
 	return nil
 }
 
@@ -2180,6 +2689,7 @@ func makeStoreWorkQueue(
 	}
 
 	opts.usesAsyncAdmit = true
+	opts.usesIOShares = true
 	for i := range q.q {
 		initWorkQueue(&q.q[i], ambientCtx, KVWork, granters[i], settings, metrics, opts, knobs)
 		q.q[i].onAdmittedReplicatedWork = q
@@ -119,9 +119,10 @@ func (tg *testGranter) storeReplicatedWorkAdmittedLocked(
 }
 
 type testWork struct {
-	tenantID roachpb.TenantID
-	cancel   context.CancelFunc
-	admitted bool
+	tenantID      roachpb.TenantID
+	resourceGroup string
+	cancel        context.CancelFunc
+	admitted      bool
 	// For StoreWorkQueue testing.
 	handle StoreWorkHandle
 }
@@ -209,6 +210,9 @@ func TestWorkQueueBasic(t *testing.T) {
 				opts.timeSource = timeSource
 				opts.disableEpochClosingGoroutine = true
 				opts.disableGCTenantsAndResetUsed = true
+				if d.HasArg("max-groups") {
+					d.ScanArgs(t, "max-groups", &opts.maxResourceGroups)
+				}
 				st = cluster.MakeTestingClusterSettings()
 				q = makeWorkQueue(log.MakeTestingAmbientContext(tracing.NewTracer()),
 					KVWork, tg, st, metrics, opts).(*WorkQueue)
@@ -228,13 +232,15 @@ func TestWorkQueueBasic(t *testing.T) {
 				d.ScanArgs(t, "create-time-millis", &createTime)
 				var bypass bool
 				d.ScanArgs(t, "bypass", &bypass)
+				resourceGroup := scanResourceGroup(t, d)
 				ctx, cancel := context.WithCancel(context.Background())
-				wrkMap.set(id, &testWork{tenantID: tenant, cancel: cancel})
+				wrkMap.set(id, &testWork{tenantID: tenant, resourceGroup: resourceGroup.Name, cancel: cancel})
 				workInfo := WorkInfo{
 					TenantID:        tenant,
 					Priority:        admissionpb.WorkPriority(priority),
 					CreateTime:      int64(createTime) * int64(time.Millisecond),
 					BypassAdmission: bypass,
+					ResourceGroup:   resourceGroup,
 				}
 				go func(ctx context.Context, info WorkInfo, id int) {
 					enabled, err := q.Admit(ctx, info)
@@ -294,7 +300,7 @@ func TestWorkQueueBasic(t *testing.T) {
 				if d.HasArg("cpu-time") {
 					d.ScanArgs(t, "cpu-time", &cpuTime)
 				}
-				q.AdmittedWorkDone(work.tenantID, time.Duration(cpuTime))
+				q.AdmittedWorkDone(work.tenantID, work.resourceGroup, time.Duration(cpuTime))
 				wrkMap.delete(id)
 				return buf.stringAndReset()
 
@@ -345,6 +351,25 @@ func scanTenantID(t *testing.T, d *datadriven.TestData) roachpb.TenantID {
 	return roachpb.MustMakeTenantID(uint64(id))
 }
 
+// scanResourceGroup scans the optional resource group of some work.
+func scanResourceGroup(t *testing.T, d *datadriven.TestData) admissionpb.ResourceGroup {
+	var rg admissionpb.ResourceGroup
+	if d.HasArg("group") {
+		d.ScanArgs(t, "group", &rg.Name)
+	}
+	if d.HasArg("cpu-share") {
+		var share int
+		d.ScanArgs(t, "cpu-share", &share)
+		rg.CPUShare = uint32(share)
+	}
+	if d.HasArg("max-concurrency") {
+		var maxConcurrency int
+		d.ScanArgs(t, "max-concurrency", &maxConcurrency)
+		rg.MaxConcurrency = int32(maxConcurrency)
+	}
+	return rg
+}
+
 // TestWorkQueueTokenResetRace induces racing between tenantInfo.used
 // decrements and tenantInfo.used resets that used to fail until we eliminated
 // the code that decrements tenantInfo.used for tokens. It would also trigger